@@ -31,6 +31,8 @@ import (
 
 var log = logger.GetOrCreate("api")
 
+const adminGroupName = "admin"
+
 // ArgsNewWebServer holds the arguments needed to create a new instance of webServer
 type ArgsNewWebServer struct {
 	Facade                     shared.FacadeHandler
@@ -39,6 +41,7 @@ type ArgsNewWebServer struct {
 	TokenHandler               authentication.AuthTokenHandler
 	NativeAuthWhitelistHandler core.NativeAuthWhitelistHandler
 	StatusMetricsHandler       core.StatusMetricsHandler
+	AdminAPIKey                string
 }
 
 type webServer struct {
@@ -50,6 +53,7 @@ type webServer struct {
 	nativeAuthWhitelistHandler core.NativeAuthWhitelistHandler
 	httpServer                 chainShared.HttpServerCloser
 	statusMetrics              core.StatusMetricsHandler
+	adminAPIKey                string
 	groups                     map[string]shared.GroupHandler
 	cancelFunc                 func()
 }
@@ -68,6 +72,7 @@ func NewWebServerHandler(args ArgsNewWebServer) (*webServer, error) {
 		tokenHandler:               args.TokenHandler,
 		nativeAuthWhitelistHandler: args.NativeAuthWhitelistHandler,
 		statusMetrics:              args.StatusMetricsHandler,
+		adminAPIKey:                args.AdminAPIKey,
 	}
 
 	return gws, nil
@@ -151,7 +156,12 @@ func (ws *webServer) StartHttpServer() error {
 		engine.Use(proc.MiddlewareHandlerFunc())
 	}
 
-	ws.registerRoutes(engine)
+	adminAuth, err := mfaMiddleware.NewAdminAuth(ws.adminAPIKey)
+	if err != nil {
+		return err
+	}
+
+	ws.registerRoutes(engine, adminAuth)
 
 	server := &http.Server{Addr: apiInterface, Handler: engine}
 	log.Debug("creating gin web sever", "interface", apiInterface)
@@ -181,6 +191,12 @@ func (ws *webServer) createGroups() error {
 	}
 	groupsMap["status"] = statusGroup
 
+	adminGroup, err := groups.NewAdminGroup(ws.facade)
+	if err != nil {
+		return err
+	}
+	groupsMap[adminGroupName] = adminGroup
+
 	ws.groups = groupsMap
 
 	return nil
@@ -228,11 +244,15 @@ func (ws *webServer) setOptionsForClientIP(engine *gin.Engine) error {
 	return engine.SetTrustedProxies(trustedProxies)
 }
 
-func (ws *webServer) registerRoutes(ginRouter *gin.Engine) {
+func (ws *webServer) registerRoutes(ginRouter *gin.Engine, adminAuth chainShared.MiddlewareProcessor) {
 
 	for groupName, groupHandler := range ws.groups {
 		log.Debug("registering gin API group", "group name", groupName)
 		ginGroup := ginRouter.Group(fmt.Sprintf("/%s", groupName))
+		if groupName == adminGroupName {
+			// the admin routes are authorized by the admin api key, so the key is only checked on this group
+			ginGroup.Use(adminAuth.MiddlewareHandlerFunc())
+		}
 		groupHandler.RegisterRoutes(ginGroup, ws.config.ApiRoutesConfig)
 	}
 
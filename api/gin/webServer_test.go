@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	apiErrors "github.com/multiversx/mx-multi-factor-auth-go-service/api/errors"
+	mfaMiddleware "github.com/multiversx/mx-multi-factor-auth-go-service/api/middleware"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/api/shared"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
@@ -164,6 +165,16 @@ func TestWebServer_StartHttpServer(t *testing.T) {
 		err := ws.StartHttpServer()
 		assert.Equal(t, middleware.ErrInvalidMaxNumRequests, err)
 	})
+	t.Run("invalid admin api key should error", func(t *testing.T) {
+		args := createMockArgsNewWebServer()
+		args.Config.GeneralConfig.Antiflood.Enabled = false
+		args.AdminAPIKey = "short key"
+		ws, _ := NewWebServerHandler(args)
+		assert.NotNil(t, ws)
+
+		err := ws.StartHttpServer()
+		assert.True(t, errors.Is(err, mfaMiddleware.ErrInvalidAdminAPIKey))
+	})
 	t.Run("upgrade on get returns error", func(t *testing.T) {
 		args := createMockArgsNewWebServer()
 		args.NativeAuthWhitelistHandler = &middlewareMocks.NativeAuthWhitelistHandlerStub{
@@ -0,0 +1,343 @@
+package groups
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	chainApiShared "github.com/multiversx/mx-chain-go/api/shared"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-sdk-go/data"
+
+	mfaMiddleware "github.com/multiversx/mx-multi-factor-auth-go-service/api/middleware"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/api/shared"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage"
+)
+
+const (
+	userStatePath              = "/user-state"
+	forceUnsetSecurityModePath = "/unset-security-mode"
+	resetRateLimiterPath       = "/reset-rate-limiter"
+	markGuardianNotUsablePath  = "/mark-guardian-not-usable"
+	auditDataPath              = "/audit-data"
+	reEncryptPath              = "/re-encrypt"
+	reEncryptAllPath           = "/re-encrypt-all"
+	reEncryptionStatusPath     = "/re-encryption-status"
+
+	userQueryParam = "user"
+)
+
+var adminLog = logger.GetOrCreate("adminGroup")
+
+type adminGroup struct {
+	*baseGroup
+	facade    shared.FacadeHandler
+	mutFacade sync.RWMutex
+}
+
+// NewAdminGroup returns a new instance of adminGroup, holding the routes used by the operators
+func NewAdminGroup(facade shared.FacadeHandler) (*adminGroup, error) {
+	if check.IfNil(facade) {
+		return nil, fmt.Errorf("%w for admin group", core.ErrNilFacadeHandler)
+	}
+
+	ag := &adminGroup{
+		facade:    facade,
+		baseGroup: &baseGroup{},
+	}
+
+	endpoints := []*chainApiShared.EndpointHandlerData{
+		{
+			Path:    userStatePath,
+			Method:  http.MethodGet,
+			Handler: ag.userState,
+		},
+		{
+			Path:    forceUnsetSecurityModePath,
+			Method:  http.MethodPost,
+			Handler: ag.forceUnsetSecurityMode,
+		},
+		{
+			Path:    resetRateLimiterPath,
+			Method:  http.MethodPost,
+			Handler: ag.resetRateLimiter,
+		},
+		{
+			Path:    markGuardianNotUsablePath,
+			Method:  http.MethodPost,
+			Handler: ag.markGuardianNotUsable,
+		},
+		{
+			Path:    auditDataPath,
+			Method:  http.MethodGet,
+			Handler: ag.auditData,
+		},
+		{
+			Path:    reEncryptPath,
+			Method:  http.MethodPost,
+			Handler: ag.reEncrypt,
+		},
+		{
+			Path:    reEncryptAllPath,
+			Method:  http.MethodPost,
+			Handler: ag.reEncryptAll,
+		},
+		{
+			Path:    reEncryptionStatusPath,
+			Method:  http.MethodGet,
+			Handler: ag.reEncryptionStatus,
+		},
+	}
+	ag.endpoints = endpoints
+
+	return ag, nil
+}
+
+// userState returns the state of the user guardians, without any of their secrets
+func (ag *adminGroup) userState(c *gin.Context) {
+	userAddr := c.Query(userQueryParam)
+	userAddress, err := data.NewAddressFromBech32String(userAddr)
+	if err != nil {
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	retData, err := ag.facade.GetUserState(userAddress)
+	if err != nil {
+		handleAdminErrorAndReturn(c, err.Error())
+		return
+	}
+
+	returnStatus(c, retData, http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
+}
+
+// forceUnsetSecurityMode unsets the security mode of the user, without requiring any code
+func (ag *adminGroup) forceUnsetSecurityMode(c *gin.Context) {
+	var request requests.AdminUserRequest
+	var debugErr error
+	defer func() {
+		logAdminRequest(c, forceUnsetSecurityModePath, request.UserAddr, debugErr)
+		recordAuditEntry(ag.facade, c, request.UserAddr, "", debugErr, nil)
+	}()
+
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while decoding request", err)
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	userAddress, err := data.NewAddressFromBech32String(request.UserAddr)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while decoding user address", err)
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	err = ag.facade.ForceUnsetSecurityMode(userAddress)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while force unsetting security mode", err)
+		handleAdminErrorAndReturn(c, err.Error())
+		return
+	}
+
+	returnStatus(c, nil, http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
+}
+
+// resetRateLimiter resets the failed trials of the user from the provided ip
+func (ag *adminGroup) resetRateLimiter(c *gin.Context) {
+	var request requests.AdminResetRateLimiter
+	var debugErr error
+	defer func() {
+		logAdminRequest(c, resetRateLimiterPath, request.UserAddr, debugErr, "user ip", request.UserIp)
+		recordAuditEntry(ag.facade, c, request.UserAddr, "", debugErr, nil)
+	}()
+
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while decoding request", err)
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	userAddress, err := data.NewAddressFromBech32String(request.UserAddr)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while decoding user address", err)
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	err = ag.facade.ResetRateLimiter(userAddress, request.UserIp)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while resetting rate limiter", err)
+		handleAdminErrorAndReturn(c, err.Error())
+		return
+	}
+
+	returnStatus(c, nil, http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
+}
+
+// markGuardianNotUsable marks the provided guardian of the user as not usable, until its otp is verified again
+func (ag *adminGroup) markGuardianNotUsable(c *gin.Context) {
+	var request requests.AdminGuardianRequest
+	var debugErr error
+	defer func() {
+		logAdminRequest(c, markGuardianNotUsablePath, request.UserAddr, debugErr, "guardian", request.Guardian)
+		recordAuditEntry(ag.facade, c, request.UserAddr, request.Guardian, debugErr, nil)
+	}()
+
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while decoding request", err)
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	userAddress, err := data.NewAddressFromBech32String(request.UserAddr)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while decoding user address", err)
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	err = ag.facade.MarkGuardianNotUsable(userAddress, request.Guardian)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while marking guardian as not usable", err)
+		handleAdminErrorAndReturn(c, err.Error())
+		return
+	}
+
+	returnStatus(c, nil, http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
+}
+
+// auditData exports all the data held for the user which can be inspected by an operator
+func (ag *adminGroup) auditData(c *gin.Context) {
+	userAddr := c.Query(userQueryParam)
+	var debugErr error
+	defer func() {
+		logAdminRequest(c, auditDataPath, userAddr, debugErr)
+	}()
+
+	userAddress, err := data.NewAddressFromBech32String(userAddr)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while decoding user address", err)
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	retData, err := ag.facade.ExportUserData(userAddress)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while exporting user data", err)
+		handleAdminErrorAndReturn(c, err.Error())
+		return
+	}
+
+	returnStatus(c, retData, http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
+}
+
+// reEncrypt re-encrypts the secrets of the user with the current encryption key
+func (ag *adminGroup) reEncrypt(c *gin.Context) {
+	var request requests.AdminUserRequest
+	var debugErr error
+	defer func() {
+		logAdminRequest(c, reEncryptPath, request.UserAddr, debugErr)
+		recordAuditEntry(ag.facade, c, request.UserAddr, "", debugErr, nil)
+	}()
+
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while decoding request", err)
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	userAddress, err := data.NewAddressFromBech32String(request.UserAddr)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while decoding user address", err)
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	err = ag.facade.ReEncryptUser(userAddress)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while re-encrypting user", err)
+		handleAdminErrorAndReturn(c, err.Error())
+		return
+	}
+
+	returnStatus(c, nil, http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
+}
+
+// reEncryptAll starts re-encrypting in background all the users which are not encrypted with the current key
+func (ag *adminGroup) reEncryptAll(c *gin.Context) {
+	var debugErr error
+	defer func() {
+		logAdminRequest(c, reEncryptAllPath, "", debugErr)
+	}()
+
+	err := ag.facade.StartReEncryption()
+	if err != nil {
+		debugErr = fmt.Errorf("%w while starting re-encryption", err)
+		handleAdminErrorAndReturn(c, err.Error())
+		return
+	}
+
+	returnStatus(c, nil, http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
+}
+
+// reEncryptionStatus returns the progress of the last re-encryption of all the users
+func (ag *adminGroup) reEncryptionStatus(c *gin.Context) {
+	returnStatus(c, ag.facade.GetReEncryptionStatus(), http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
+}
+
+// logAdminRequest logs all the actions of the operators, as they are not verified by the user
+func logAdminRequest(c *gin.Context, route string, userAddr string, debugErr error, extraArgs ...interface{}) {
+	logArgs := []interface{}{
+		"route", route,
+		"ip", c.GetString(mfaMiddleware.UserIpKey),
+		"user agent", c.GetString(mfaMiddleware.UserAgentKey),
+		"user address", userAddr,
+	}
+	logArgs = append(logArgs, extraArgs...)
+
+	if debugErr == nil {
+		logArgs = append(logArgs, "result", "success")
+	} else {
+		logArgs = append(logArgs, "error", debugErr.Error())
+	}
+
+	adminLog.Info("Admin request info", logArgs...)
+}
+
+func handleAdminErrorAndReturn(c *gin.Context, err string) {
+	if strings.Contains(err, storage.ErrKeyNotFound.Error()) {
+		returnStatus(c, nil, http.StatusNotFound, err, chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	handleErrorAndReturn(c, nil, err)
+}
+
+// UpdateFacade will update the facade
+func (ag *adminGroup) UpdateFacade(newFacade shared.FacadeHandler) error {
+	if check.IfNil(newFacade) {
+		return core.ErrNilFacadeHandler
+	}
+
+	ag.mutFacade.Lock()
+	ag.facade = newFacade
+	ag.mutFacade.Unlock()
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ag *adminGroup) IsInterfaceNil() bool {
+	return ag == nil
+}
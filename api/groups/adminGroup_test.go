@@ -0,0 +1,432 @@
+package groups_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sdkCore "github.com/multiversx/mx-sdk-go/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/api/groups"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/resolver"
+	mockFacade "github.com/multiversx/mx-multi-factor-auth-go-service/testscommon/facade"
+)
+
+func getAdminRoutesConfig() config.ApiRoutesConfig {
+	return config.ApiRoutesConfig{
+		APIPackages: map[string]config.APIPackageConfig{
+			"admin": {
+				Routes: []config.RouteConfig{
+					{Name: "/user-state", Open: true},
+					{Name: "/unset-security-mode", Open: true},
+					{Name: "/reset-rate-limiter", Open: true},
+					{Name: "/mark-guardian-not-usable", Open: true},
+					{Name: "/audit-data", Open: true},
+					{Name: "/re-encrypt", Open: true},
+					{Name: "/re-encrypt-all", Open: true},
+					{Name: "/re-encryption-status", Open: true},
+				},
+			},
+		},
+	}
+}
+
+func sendAdminRequest(facade *mockFacade.GuardianFacadeStub, method string, path string, request interface{}) (*httptest.ResponseRecorder, generalResponse) {
+	ag, _ := groups.NewAdminGroup(facade)
+	ws := startWebServer(ag, "admin", getAdminRoutesConfig(), "")
+
+	var body io.Reader = &bytes.Buffer{}
+	if request != nil {
+		body = requestToReader(request)
+	}
+	req, _ := http.NewRequest(method, path, body)
+	resp := httptest.NewRecorder()
+	ws.ServeHTTP(resp, req)
+
+	statusRsp := generalResponse{}
+	loadResponse(resp.Body, &statusRsp)
+
+	return resp, statusRsp
+}
+
+func TestNewAdminGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil facade should error", func(t *testing.T) {
+		ag, err := groups.NewAdminGroup(nil)
+
+		assert.Nil(t, ag)
+		assert.True(t, errors.Is(err, core.ErrNilFacadeHandler))
+	})
+	t.Run("should work", func(t *testing.T) {
+		ag, err := groups.NewAdminGroup(&mockFacade.GuardianFacadeStub{})
+
+		assert.NotNil(t, ag)
+		assert.Nil(t, err)
+	})
+}
+
+func TestAdminGroup_userState(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid address", func(t *testing.T) {
+		t.Parallel()
+
+		resp, statusRsp := sendAdminRequest(&mockFacade.GuardianFacadeStub{}, http.MethodGet, "/admin/user-state?user=invalid", nil)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, "bech32"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("unknown user", func(t *testing.T) {
+		t.Parallel()
+
+		facade := &mockFacade.GuardianFacadeStub{
+			GetUserStateCalled: func(userAddress sdkCore.AddressHandler) (*requests.UserStateResponse, error) {
+				return nil, storage.ErrKeyNotFound
+			},
+		}
+		resp, statusRsp := sendAdminRequest(facade, http.MethodGet, "/admin/user-state?user="+providedAddr, nil)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.Equal(t, storage.ErrKeyNotFound.Error(), statusRsp.Error)
+		require.Equal(t, http.StatusNotFound, resp.Code)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		expectedData := &requests.UserStateResponse{
+			Address: providedAddr,
+			Index:   7,
+			Guardians: []requests.GuardianStateResponse{
+				{
+					Address:            "guardian",
+					State:              core.Usable.String(),
+					OTPType:            core.TOTPType,
+					WebAuthnRegistered: true,
+				},
+			},
+			RecoveryCodesRemaining: 3,
+		}
+		facade := &mockFacade.GuardianFacadeStub{
+			GetUserStateCalled: func(userAddress sdkCore.AddressHandler) (*requests.UserStateResponse, error) {
+				bech32Addr, _ := userAddress.AddressAsBech32String()
+				assert.Equal(t, providedAddr, bech32Addr)
+				return expectedData, nil
+			},
+		}
+		resp, statusRsp := sendAdminRequest(facade, http.MethodGet, "/admin/user-state?user="+providedAddr, nil)
+
+		expectedGenResponse := createExpectedGeneralResponse(expectedData, "")
+		assert.Equal(t, expectedGenResponse.Data, statusRsp.Data)
+		assert.Empty(t, statusRsp.Error)
+		require.Equal(t, http.StatusOK, resp.Code)
+	})
+}
+
+func TestAdminGroup_forceUnsetSecurityMode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty body", func(t *testing.T) {
+		t.Parallel()
+
+		resp, statusRsp := sendAdminRequest(&mockFacade.GuardianFacadeStub{}, http.MethodPost, "/admin/unset-security-mode", nil)
+
+		assert.True(t, strings.Contains(statusRsp.Error, "EOF"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("invalid address", func(t *testing.T) {
+		t.Parallel()
+
+		request := requests.AdminUserRequest{UserAddr: "invalid"}
+		resp, statusRsp := sendAdminRequest(&mockFacade.GuardianFacadeStub{}, http.MethodPost, "/admin/unset-security-mode", request)
+
+		assert.True(t, strings.Contains(statusRsp.Error, "bech32"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("facade returns error", func(t *testing.T) {
+		t.Parallel()
+
+		facade := &mockFacade.GuardianFacadeStub{
+			ForceUnsetSecurityModeCalled: func(userAddress sdkCore.AddressHandler) error {
+				return expectedError
+			},
+		}
+		request := requests.AdminUserRequest{UserAddr: providedAddr}
+		resp, statusRsp := sendAdminRequest(facade, http.MethodPost, "/admin/unset-security-mode", request)
+
+		assert.Equal(t, expectedError.Error(), statusRsp.Error)
+		require.Equal(t, http.StatusInternalServerError, resp.Code)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		wasCalled := false
+		facade := &mockFacade.GuardianFacadeStub{
+			ForceUnsetSecurityModeCalled: func(userAddress sdkCore.AddressHandler) error {
+				bech32Addr, _ := userAddress.AddressAsBech32String()
+				assert.Equal(t, providedAddr, bech32Addr)
+				wasCalled = true
+				return nil
+			},
+		}
+		request := requests.AdminUserRequest{UserAddr: providedAddr}
+		resp, statusRsp := sendAdminRequest(facade, http.MethodPost, "/admin/unset-security-mode", request)
+
+		assert.Empty(t, statusRsp.Error)
+		assert.True(t, wasCalled)
+		require.Equal(t, http.StatusOK, resp.Code)
+	})
+}
+
+func TestAdminGroup_resetRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty ip", func(t *testing.T) {
+		t.Parallel()
+
+		facade := &mockFacade.GuardianFacadeStub{
+			ResetRateLimiterCalled: func(userAddress sdkCore.AddressHandler, userIp string) error {
+				return resolver.ErrEmptyUserIp
+			},
+		}
+		request := requests.AdminResetRateLimiter{UserAddr: providedAddr}
+		resp, statusRsp := sendAdminRequest(facade, http.MethodPost, "/admin/reset-rate-limiter", request)
+
+		assert.Equal(t, resolver.ErrEmptyUserIp.Error(), statusRsp.Error)
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		wasCalled := false
+		facade := &mockFacade.GuardianFacadeStub{
+			ResetRateLimiterCalled: func(userAddress sdkCore.AddressHandler, userIp string) error {
+				assert.Equal(t, "127.0.0.1", userIp)
+				wasCalled = true
+				return nil
+			},
+		}
+		request := requests.AdminResetRateLimiter{
+			UserAddr: providedAddr,
+			UserIp:   "127.0.0.1",
+		}
+		resp, statusRsp := sendAdminRequest(facade, http.MethodPost, "/admin/reset-rate-limiter", request)
+
+		assert.Empty(t, statusRsp.Error)
+		assert.True(t, wasCalled)
+		require.Equal(t, http.StatusOK, resp.Code)
+	})
+}
+
+func TestAdminGroup_markGuardianNotUsable(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown guardian", func(t *testing.T) {
+		t.Parallel()
+
+		facade := &mockFacade.GuardianFacadeStub{
+			MarkGuardianNotUsableCalled: func(userAddress sdkCore.AddressHandler, guardian string) error {
+				return fmt.Errorf("%w, guardian %s", resolver.ErrInvalidGuardian, guardian)
+			},
+		}
+		request := requests.AdminGuardianRequest{
+			UserAddr: providedAddr,
+			Guardian: "guardian",
+		}
+		resp, statusRsp := sendAdminRequest(facade, http.MethodPost, "/admin/mark-guardian-not-usable", request)
+
+		assert.True(t, strings.Contains(statusRsp.Error, resolver.ErrInvalidGuardian.Error()))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		wasCalled := false
+		facade := &mockFacade.GuardianFacadeStub{
+			MarkGuardianNotUsableCalled: func(userAddress sdkCore.AddressHandler, guardian string) error {
+				assert.Equal(t, "guardian", guardian)
+				wasCalled = true
+				return nil
+			},
+		}
+		request := requests.AdminGuardianRequest{
+			UserAddr: providedAddr,
+			Guardian: "guardian",
+		}
+		resp, statusRsp := sendAdminRequest(facade, http.MethodPost, "/admin/mark-guardian-not-usable", request)
+
+		assert.Empty(t, statusRsp.Error)
+		assert.True(t, wasCalled)
+		require.Equal(t, http.StatusOK, resp.Code)
+	})
+}
+
+func TestAdminGroup_reEncrypt(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid address", func(t *testing.T) {
+		t.Parallel()
+
+		request := requests.AdminUserRequest{UserAddr: "invalid"}
+		resp, statusRsp := sendAdminRequest(&mockFacade.GuardianFacadeStub{}, http.MethodPost, "/admin/re-encrypt", request)
+
+		assert.True(t, strings.Contains(statusRsp.Error, "bech32"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("unregistered user", func(t *testing.T) {
+		t.Parallel()
+
+		facade := &mockFacade.GuardianFacadeStub{
+			ReEncryptUserCalled: func(userAddress sdkCore.AddressHandler) error {
+				return storage.ErrKeyNotFound
+			},
+		}
+		request := requests.AdminUserRequest{UserAddr: providedAddr}
+		resp, _ := sendAdminRequest(facade, http.MethodPost, "/admin/re-encrypt", request)
+
+		require.Equal(t, http.StatusNotFound, resp.Code)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		wasCalled := false
+		facade := &mockFacade.GuardianFacadeStub{
+			ReEncryptUserCalled: func(userAddress sdkCore.AddressHandler) error {
+				bech32Addr, _ := userAddress.AddressAsBech32String()
+				assert.Equal(t, providedAddr, bech32Addr)
+				wasCalled = true
+				return nil
+			},
+		}
+		request := requests.AdminUserRequest{UserAddr: providedAddr}
+		resp, statusRsp := sendAdminRequest(facade, http.MethodPost, "/admin/re-encrypt", request)
+
+		assert.Empty(t, statusRsp.Error)
+		assert.True(t, wasCalled)
+		require.Equal(t, http.StatusOK, resp.Code)
+	})
+}
+
+func TestAdminGroup_reEncryptAll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("facade returns error", func(t *testing.T) {
+		t.Parallel()
+
+		facade := &mockFacade.GuardianFacadeStub{
+			StartReEncryptionCalled: func() error {
+				return expectedError
+			},
+		}
+		resp, statusRsp := sendAdminRequest(facade, http.MethodPost, "/admin/re-encrypt-all", nil)
+
+		assert.True(t, strings.Contains(statusRsp.Error, expectedError.Error()))
+		require.Equal(t, http.StatusInternalServerError, resp.Code)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		wasCalled := false
+		facade := &mockFacade.GuardianFacadeStub{
+			StartReEncryptionCalled: func() error {
+				wasCalled = true
+				return nil
+			},
+		}
+		resp, statusRsp := sendAdminRequest(facade, http.MethodPost, "/admin/re-encrypt-all", nil)
+
+		assert.Empty(t, statusRsp.Error)
+		assert.True(t, wasCalled)
+		require.Equal(t, http.StatusOK, resp.Code)
+	})
+}
+
+func TestAdminGroup_reEncryptionStatus(t *testing.T) {
+	t.Parallel()
+
+	expectedStatus := &requests.ReEncryptionStatusResponse{
+		Running:            true,
+		NumUsers:           10,
+		NumOnOldKeys:       3,
+		NumReEncrypted:     1,
+		RemainingOnOldKeys: map[uint32]int{0: 2},
+	}
+	facade := &mockFacade.GuardianFacadeStub{
+		GetReEncryptionStatusCalled: func() *requests.ReEncryptionStatusResponse {
+			return expectedStatus
+		},
+	}
+	resp, statusRsp := sendAdminRequest(facade, http.MethodGet, "/admin/re-encryption-status", nil)
+
+	expectedGenResponse := createExpectedGeneralResponse(expectedStatus, "")
+	assert.Equal(t, expectedGenResponse.Data, statusRsp.Data)
+	assert.Empty(t, statusRsp.Error)
+	require.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestAdminGroup_auditData(t *testing.T) {
+	t.Parallel()
+
+	t.Run("facade returns error", func(t *testing.T) {
+		t.Parallel()
+
+		facade := &mockFacade.GuardianFacadeStub{
+			ExportUserDataCalled: func(userAddress sdkCore.AddressHandler) (*requests.UserAuditDataResponse, error) {
+				return nil, expectedError
+			},
+		}
+		resp, statusRsp := sendAdminRequest(facade, http.MethodGet, "/admin/audit-data?user="+providedAddr, nil)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.Equal(t, expectedError.Error(), statusRsp.Error)
+		require.Equal(t, http.StatusInternalServerError, resp.Code)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		expectedData := &requests.UserAuditDataResponse{
+			State: requests.UserStateResponse{
+				Address: providedAddr,
+			},
+			SpendingPolicy: requests.SpendingPolicyResponse{
+				Spent: []requests.SpentAmount{{Token: "EGLD", DailySpent: "10"}},
+			},
+			ExportTimestamp: 1000,
+		}
+		facade := &mockFacade.GuardianFacadeStub{
+			ExportUserDataCalled: func(userAddress sdkCore.AddressHandler) (*requests.UserAuditDataResponse, error) {
+				return expectedData, nil
+			},
+		}
+		resp, statusRsp := sendAdminRequest(facade, http.MethodGet, "/admin/audit-data?user="+providedAddr, nil)
+
+		expectedGenResponse := createExpectedGeneralResponse(expectedData, "")
+		assert.Equal(t, expectedGenResponse.Data, statusRsp.Data)
+		require.Equal(t, http.StatusOK, resp.Code)
+	})
+}
+
+func TestAdminGroup_UpdateFacade(t *testing.T) {
+	t.Parallel()
+
+	ag, _ := groups.NewAdminGroup(&mockFacade.GuardianFacadeStub{})
+
+	err := ag.UpdateFacade(nil)
+	assert.Equal(t, core.ErrNilFacadeHandler, err)
+
+	err = ag.UpdateFacade(&mockFacade.GuardianFacadeStub{})
+	assert.Nil(t, err)
+	assert.False(t, ag.IsInterfaceNil())
+}
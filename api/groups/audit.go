@@ -0,0 +1,83 @@
+package groups
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	sdkCore "github.com/multiversx/mx-sdk-go/core"
+
+	mfaMiddleware "github.com/multiversx/mx-multi-factor-auth-go-service/api/middleware"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/api/shared"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+)
+
+const (
+	auditErrorClassInvalidRequest = "invalid request"
+	auditErrorClassWrongCode      = "wrong code"
+	auditErrorClassPolicyRejected = "policy rejected"
+	auditErrorClassRateLimited    = "rate limited"
+	auditErrorClassNotFound       = "not found"
+	auditErrorClassInternal       = "internal error"
+)
+
+// recordAuditEntry appends the decision taken on the request to the audit log. It must be called
+// after the response was written, as the error class is derived from the returned status
+func recordAuditEntry(
+	facade shared.FacadeHandler,
+	c *gin.Context,
+	userAddr string,
+	guardian string,
+	debugErr error,
+	txs []transaction.FrontendTransaction,
+) {
+	entry := core.AuditEntry{
+		Route:       c.FullPath(),
+		UserAddress: userAddr,
+		Guardian:    guardian,
+		UserIp:      c.GetString(mfaMiddleware.UserIpKey),
+		UserAgent:   c.GetString(mfaMiddleware.UserAgentKey),
+		Outcome:     core.AuditOutcomeSuccess,
+	}
+	if debugErr != nil {
+		entry.Outcome = core.AuditOutcomeFailure
+		entry.ErrorClass = getAuditErrorClass(c.Writer.Status(), debugErr)
+	}
+
+	facade.RecordAuditEntry(entry, txs)
+}
+
+// getAuditErrorClass returns a coarse class of the error, so that the audit log does not hold codes or other secrets
+func getAuditErrorClass(httpStatus int, debugErr error) string {
+	if strings.Contains(debugErr.Error(), wrongCodeError) {
+		return auditErrorClassWrongCode
+	}
+
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return auditErrorClassInvalidRequest
+	case http.StatusForbidden, http.StatusPreconditionRequired:
+		return auditErrorClassPolicyRejected
+	case http.StatusTooManyRequests:
+		return auditErrorClassRateLimited
+	case http.StatusNotFound:
+		return auditErrorClassNotFound
+	default:
+		return auditErrorClassInternal
+	}
+}
+
+func getBech32Address(userAddress sdkCore.AddressHandler) string {
+	if check.IfNil(userAddress) {
+		return ""
+	}
+
+	bech32Addr, err := userAddress.AddressAsBech32String()
+	if err != nil {
+		return ""
+	}
+
+	return bech32Addr
+}
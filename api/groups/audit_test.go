@@ -0,0 +1,193 @@
+package groups_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	sdkCore "github.com/multiversx/mx-sdk-go/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/api/groups"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+	mockFacade "github.com/multiversx/mx-multi-factor-auth-go-service/testscommon/facade"
+)
+
+func TestGuardianGroup_auditEntries(t *testing.T) {
+	t.Parallel()
+
+	providedTx := transaction.FrontendTransaction{
+		Nonce:        7,
+		Sender:       providedAddr,
+		GuardianAddr: "guardian",
+		Signature:    "user signature",
+	}
+
+	t.Run("signed transaction should be recorded", func(t *testing.T) {
+		t.Parallel()
+
+		signedTx := providedTx
+		signedTx.GuardianSignature = "guardian signature"
+		var recordedEntry core.AuditEntry
+		var recordedTxs []transaction.FrontendTransaction
+		facade := &mockFacade.GuardianFacadeStub{
+			SignTransactionCalled: func(userIp string, request requests.SignTransaction) ([]byte, *requests.OTPCodeVerifyData, error) {
+				marshalledTx, _ := json.Marshal(signedTx)
+				return marshalledTx, nil, nil
+			},
+			RecordAuditEntryCalled: func(entry core.AuditEntry, txs []transaction.FrontendTransaction) {
+				recordedEntry = entry
+				recordedTxs = txs
+			},
+		}
+		gg, _ := groups.NewGuardianGroup(facade)
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		request := requests.SignTransaction{
+			Code: "123456",
+			Tx:   providedTx,
+		}
+		req, _ := http.NewRequest("POST", "/guardian/sign-transaction", requestToReader(request))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		expectedEntry := core.AuditEntry{
+			Route:       "/guardian/sign-transaction",
+			UserAddress: providedAddr,
+			Guardian:    "guardian",
+			Outcome:     core.AuditOutcomeSuccess,
+		}
+		assert.Equal(t, expectedEntry, recordedEntry)
+		assert.Equal(t, []transaction.FrontendTransaction{signedTx}, recordedTxs)
+	})
+	t.Run("rejected transactions should be recorded with the error class", func(t *testing.T) {
+		t.Parallel()
+
+		var recordedEntry core.AuditEntry
+		var recordedTxs []transaction.FrontendTransaction
+		facade := &mockFacade.GuardianFacadeStub{
+			SignMultipleTransactionsCalled: func(userIp string, request requests.SignMultipleTransactions) ([][]byte, *requests.OTPCodeVerifyData, error) {
+				return nil, nil, handlers.ErrTxPolicyRejected
+			},
+			RecordAuditEntryCalled: func(entry core.AuditEntry, txs []transaction.FrontendTransaction) {
+				recordedEntry = entry
+				recordedTxs = txs
+			},
+		}
+		gg, _ := groups.NewGuardianGroup(facade)
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		request := requests.SignMultipleTransactions{
+			Code: "123456",
+			Txs:  []transaction.FrontendTransaction{providedTx, providedTx},
+		}
+		req, _ := http.NewRequest("POST", "/guardian/sign-multiple-transactions", requestToReader(request))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusForbidden, resp.Code)
+
+		expectedEntry := core.AuditEntry{
+			Route:       "/guardian/sign-multiple-transactions",
+			UserAddress: providedAddr,
+			Guardian:    "guardian",
+			Outcome:     core.AuditOutcomeFailure,
+			ErrorClass:  "policy rejected",
+		}
+		assert.Equal(t, expectedEntry, recordedEntry)
+		assert.Equal(t, request.Txs, recordedTxs)
+	})
+	t.Run("wrong code should be recorded without the code", func(t *testing.T) {
+		t.Parallel()
+
+		var recordedEntry core.AuditEntry
+		facade := &mockFacade.GuardianFacadeStub{
+			VerifyCodeCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error) {
+				return nil, nil, wrongCodeError
+			},
+			RecordAuditEntryCalled: func(entry core.AuditEntry, txs []transaction.FrontendTransaction) {
+				recordedEntry = entry
+				assert.Nil(t, txs)
+			},
+		}
+		gg, _ := groups.NewGuardianGroup(facade)
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		request := requests.VerificationPayload{
+			Code:     "123456",
+			Guardian: "guardian",
+		}
+		req, _ := http.NewRequest("POST", "/guardian/verify-code", requestToReader(request))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+
+		expectedEntry := core.AuditEntry{
+			Route:       "/guardian/verify-code",
+			UserAddress: providedAddr,
+			Guardian:    "guardian",
+			Outcome:     core.AuditOutcomeFailure,
+			ErrorClass:  "wrong code",
+		}
+		assert.Equal(t, expectedEntry, recordedEntry)
+	})
+	t.Run("invalid request should be recorded", func(t *testing.T) {
+		t.Parallel()
+
+		var recordedEntry core.AuditEntry
+		facade := &mockFacade.GuardianFacadeStub{
+			RecordAuditEntryCalled: func(entry core.AuditEntry, txs []transaction.FrontendTransaction) {
+				recordedEntry = entry
+			},
+		}
+		gg, _ := groups.NewGuardianGroup(facade)
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/register", strings.NewReader(""))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+
+		expectedEntry := core.AuditEntry{
+			Route:       "/guardian/register",
+			UserAddress: providedAddr,
+			Outcome:     core.AuditOutcomeFailure,
+			ErrorClass:  "invalid request",
+		}
+		assert.Equal(t, expectedEntry, recordedEntry)
+	})
+}
+
+func TestAdminGroup_auditEntries(t *testing.T) {
+	t.Parallel()
+
+	var recordedEntry core.AuditEntry
+	facade := &mockFacade.GuardianFacadeStub{
+		MarkGuardianNotUsableCalled: func(userAddress sdkCore.AddressHandler, guardian string) error {
+			return nil
+		},
+		RecordAuditEntryCalled: func(entry core.AuditEntry, txs []transaction.FrontendTransaction) {
+			recordedEntry = entry
+		},
+	}
+	request := requests.AdminGuardianRequest{
+		UserAddr: providedAddr,
+		Guardian: "guardian",
+	}
+	resp, _ := sendAdminRequest(facade, http.MethodPost, "/admin/mark-guardian-not-usable", request)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	expectedEntry := core.AuditEntry{
+		Route:       "/admin/mark-guardian-not-usable",
+		UserAddress: providedAddr,
+		Guardian:    "guardian",
+		Outcome:     core.AuditOutcomeSuccess,
+	}
+	assert.Equal(t, expectedEntry, recordedEntry)
+}
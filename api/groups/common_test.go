@@ -48,10 +48,19 @@ func getServiceRoutesConfig() config.ApiRoutesConfig {
 					{Name: "/sign-multiple-transactions", Open: true},
 					{Name: "/set-security-mode", Open: true},
 					{Name: "/unset-security-mode", Open: true},
+					{Name: "/set-spending-policy", Open: true},
+					{Name: "/spending-policy", Open: true},
+					{Name: "/webauthn-challenge", Open: true},
+					{Name: "/register-webauthn", Open: true},
 					{Name: "/debug", Open: true},
 					{Name: "/verify-code", Open: true},
+					{Name: "/recovery-codes/regenerate", Open: true},
+					{Name: "/deregister", Open: true},
 					{Name: "/registered-users", Open: true},
 					{Name: "/config", Open: true},
+					{Name: "/history", Open: true},
+					{Name: "/status", Open: true},
+					{Name: "/preview-transactions", Open: true},
 				},
 			},
 		},
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -30,10 +31,22 @@ const (
 	signMultipleTransactionsPath  = "/sign-multiple-transactions"
 	setSecurityModeNoExpirePath   = "/set-security-mode"
 	unsetSecurityModeNoExpirePath = "/unset-security-mode"
+	setSpendingPolicyPath         = "/set-spending-policy"
+	spendingPolicyPath            = "/spending-policy"
+	webAuthnChallengePath         = "/webauthn-challenge"
+	registerWebAuthnPath          = "/register-webauthn"
 	registerPath                  = "/register"
 	verifyCodePath                = "/verify-code"
+	regenerateRecoveryCodesPath   = "/recovery-codes/regenerate"
+	deregisterPath                = "/deregister"
 	registeredUsersPath           = "/registered-users"
 	tcsConfig                     = "/config"
+	historyPath                   = "/history"
+	statusPath                    = "/status"
+	previewTransactionsPath       = "/preview-transactions"
+
+	offsetQueryParam = "offset"
+	limitQueryParam  = "limit"
 
 	wrongCodeError = "wrong code"
 )
@@ -83,6 +96,26 @@ func NewGuardianGroup(facade shared.FacadeHandler) (*guardianGroup, error) {
 			Method:  http.MethodPost,
 			Handler: gg.unsetSecurityModeNoExpire,
 		},
+		{
+			Path:    setSpendingPolicyPath,
+			Method:  http.MethodPost,
+			Handler: gg.setSpendingPolicy,
+		},
+		{
+			Path:    spendingPolicyPath,
+			Method:  http.MethodGet,
+			Handler: gg.spendingPolicy,
+		},
+		{
+			Path:    webAuthnChallengePath,
+			Method:  http.MethodPost,
+			Handler: gg.webAuthnChallenge,
+		},
+		{
+			Path:    registerWebAuthnPath,
+			Method:  http.MethodPost,
+			Handler: gg.registerWebAuthn,
+		},
 		{
 			Path:    registerPath,
 			Method:  http.MethodPost,
@@ -93,6 +126,16 @@ func NewGuardianGroup(facade shared.FacadeHandler) (*guardianGroup, error) {
 			Method:  http.MethodPost,
 			Handler: gg.verifyCode,
 		},
+		{
+			Path:    regenerateRecoveryCodesPath,
+			Method:  http.MethodPost,
+			Handler: gg.regenerateRecoveryCodes,
+		},
+		{
+			Path:    deregisterPath,
+			Method:  http.MethodPost,
+			Handler: gg.deregister,
+		},
 		{
 			Path:    registeredUsersPath,
 			Method:  http.MethodGet,
@@ -103,6 +146,21 @@ func NewGuardianGroup(facade shared.FacadeHandler) (*guardianGroup, error) {
 			Method:  http.MethodGet,
 			Handler: gg.config,
 		},
+		{
+			Path:    historyPath,
+			Method:  http.MethodGet,
+			Handler: gg.history,
+		},
+		{
+			Path:    statusPath,
+			Method:  http.MethodGet,
+			Handler: gg.status,
+		},
+		{
+			Path:    previewTransactionsPath,
+			Method:  http.MethodPost,
+			Handler: gg.previewTransactions,
+		},
 	}
 	gg.endpoints = endpoints
 
@@ -118,6 +176,7 @@ func (gg *guardianGroup) signMessage(c *gin.Context) {
 	userAgent := c.GetString(mfaMiddleware.UserAgentKey)
 	defer func() {
 		logSignMessage(userIp, userAgent, &request, debugErr)
+		recordAuditEntry(gg.facade, c, request.UserAddr, request.GuardianAddr, debugErr, nil)
 	}()
 
 	err := json.NewDecoder(c.Request.Body).Decode(&request)
@@ -168,6 +227,7 @@ func (gg *guardianGroup) setSecurityModeNoExpire(c *gin.Context) {
 	userAgent := c.GetString(mfaMiddleware.UserAgentKey)
 	defer func() {
 		logSecurityModeNoExpire(userIp, userAgent, setSecurityModeNoExpirePath, &request, debugErr)
+		recordAuditEntry(gg.facade, c, request.UserAddr, "", debugErr, nil)
 	}()
 
 	err := json.NewDecoder(c.Request.Body).Decode(&request)
@@ -219,6 +279,7 @@ func (gg *guardianGroup) unsetSecurityModeNoExpire(c *gin.Context) {
 	userAgent := c.GetString(mfaMiddleware.UserAgentKey)
 	defer func() {
 		logSecurityModeNoExpire(userIp, userAgent, unsetSecurityModeNoExpirePath, &request, debugErr)
+		recordAuditEntry(gg.facade, c, request.UserAddr, "", debugErr, nil)
 	}()
 
 	err := json.NewDecoder(c.Request.Body).Decode(&request)
@@ -237,15 +298,282 @@ func (gg *guardianGroup) unsetSecurityModeNoExpire(c *gin.Context) {
 	returnStatus(c, nil, http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
 }
 
+// setSpendingPolicy sets the spending limits and the trusted receivers of the user if the verification passed
+func (gg *guardianGroup) setSpendingPolicy(c *gin.Context) {
+	var request requests.SetSpendingPolicy
+	var userAddress sdkCore.AddressHandler
+	var debugErr error
+
+	userIp := c.GetString(mfaMiddleware.UserIpKey)
+	userAgent := c.GetString(mfaMiddleware.UserAgentKey)
+	defer func() {
+		logSetSpendingPolicy(userIp, userAgent, userAddress, &request, debugErr)
+		recordAuditEntry(gg.facade, c, getBech32Address(userAddress), request.Guardian, debugErr, nil)
+	}()
+
+	userAddress, err := gg.extractAddressContext(c)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while extracting user address", err)
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	err = json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while decoding request", err)
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	otpCodeVerifyData, err := gg.facade.SetSpendingPolicy(userAddress, userIp, request)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while setting spending policy", err)
+		handleErrorAndReturn(c, getVerifyCodeResponse(otpCodeVerifyData), err.Error())
+		return
+	}
+
+	returnStatus(c, nil, http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
+}
+
+func logSetSpendingPolicy(userIp string, userAgent string, userAddress sdkCore.AddressHandler, request *requests.SetSpendingPolicy, debugErr error) {
+	logArgs := []interface{}{
+		"route", setSpendingPolicyPath,
+		"ip", userIp,
+		"user agent", userAgent,
+		"guardian", request.Guardian,
+		"limits", getPrintableData(request.Limits),
+		"trusted receivers", getPrintableData(request.TrustedReceivers),
+	}
+	defer func() {
+		guardianLog.Info("Request info", logArgs...)
+	}()
+
+	if !check.IfNil(userAddress) {
+		bech32Addr, err := userAddress.AddressAsBech32String()
+		if err == nil {
+			logArgs = append(logArgs, "address", bech32Addr)
+		}
+	}
+
+	if debugErr == nil {
+		logArgs = append(logArgs, "result", "success")
+		return
+	}
+
+	if strings.Contains(debugErr.Error(), wrongCodeError) {
+		logArgs = append(logArgs, "code", request.Code)
+	}
+	logArgs = append(logArgs, "error", debugErr.Error())
+}
+
+// spendingPolicy returns the spending policies of the user along with the amounts spent in the current day and week
+func (gg *guardianGroup) spendingPolicy(c *gin.Context) {
+	userAddress, err := gg.extractAddressContext(c)
+	if err != nil {
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	retData, err := gg.facade.GetSpendingPolicy(userAddress)
+	if err != nil {
+		handleErrorAndReturn(c, nil, err.Error())
+		return
+	}
+
+	returnStatus(c, retData, http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
+}
+
+// history returns the co-signed transactions and messages of the user, along with the failed attempts, newest first
+func (gg *guardianGroup) history(c *gin.Context) {
+	userAddress, err := gg.extractAddressContext(c)
+	if err != nil {
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	offset, err := getUint32QueryParam(c, offsetQueryParam)
+	if err != nil {
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	limit, err := getUint32QueryParam(c, limitQueryParam)
+	if err != nil {
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	retData, err := gg.facade.GetHistory(userAddress, offset, limit)
+	if err != nil {
+		handleErrorAndReturn(c, nil, err.Error())
+		return
+	}
+
+	returnStatus(c, retData, http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
+}
+
+// status returns the guardians of the user, whether they can be used for signing and their on chain activation state,
+// along with the remaining verification trials and the security mode of the user. It does not consume a trial
+func (gg *guardianGroup) status(c *gin.Context) {
+	userAddress, err := gg.extractAddressContext(c)
+	if err != nil {
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	userIp := c.GetString(mfaMiddleware.UserIpKey)
+	retData, err := gg.facade.GetGuardianStatus(userAddress, userIp)
+	if err != nil {
+		handleErrorAndReturn(c, nil, err.Error())
+		return
+	}
+
+	returnStatus(c, retData, http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
+}
+
+// webAuthnChallenge returns a new challenge to be signed by the WebAuthn credential of the guardian
+func (gg *guardianGroup) webAuthnChallenge(c *gin.Context) {
+	var request requests.WebAuthnChallenge
+	var userAddress sdkCore.AddressHandler
+	var debugErr error
+
+	userIp := c.GetString(mfaMiddleware.UserIpKey)
+	userAgent := c.GetString(mfaMiddleware.UserAgentKey)
+	defer func() {
+		logWebAuthnChallenge(userIp, userAgent, userAddress, &request, debugErr)
+	}()
+
+	userAddress, err := gg.extractAddressContext(c)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while extracting user address", err)
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	err = json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while decoding request", err)
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	retData, otpCodeVerifyData, err := gg.facade.WebAuthnChallenge(userAddress, userIp, request)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while creating webauthn challenge", err)
+		handleErrorAndReturn(c, getVerifyCodeResponse(otpCodeVerifyData), err.Error())
+		return
+	}
+
+	returnStatus(c, retData, http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
+}
+
+func logWebAuthnChallenge(userIp string, userAgent string, userAddress sdkCore.AddressHandler, request *requests.WebAuthnChallenge, debugErr error) {
+	logArgs := []interface{}{
+		"route", webAuthnChallengePath,
+		"ip", userIp,
+		"user agent", userAgent,
+		"guardian", request.Guardian,
+	}
+	defer func() {
+		guardianLog.Info("Request info", logArgs...)
+	}()
+
+	if !check.IfNil(userAddress) {
+		bech32Addr, err := userAddress.AddressAsBech32String()
+		if err == nil {
+			logArgs = append(logArgs, "address", bech32Addr)
+		}
+	}
+
+	if debugErr == nil {
+		logArgs = append(logArgs, "result", "success")
+		return
+	}
+
+	logArgs = append(logArgs, "error", debugErr.Error())
+}
+
+// registerWebAuthn registers the WebAuthn credential of the user for the guardian if the verification passed
+func (gg *guardianGroup) registerWebAuthn(c *gin.Context) {
+	var request requests.RegisterWebAuthn
+	var userAddress sdkCore.AddressHandler
+	var debugErr error
+
+	userIp := c.GetString(mfaMiddleware.UserIpKey)
+	userAgent := c.GetString(mfaMiddleware.UserAgentKey)
+	defer func() {
+		logRegisterWebAuthn(userIp, userAgent, userAddress, &request, debugErr)
+		recordAuditEntry(gg.facade, c, getBech32Address(userAddress), request.Guardian, debugErr, nil)
+	}()
+
+	userAddress, err := gg.extractAddressContext(c)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while extracting user address", err)
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	err = json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while decoding request", err)
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	otpCodeVerifyData, err := gg.facade.RegisterWebAuthn(userAddress, userIp, request)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while registering webauthn credential", err)
+		handleErrorAndReturn(c, getVerifyCodeResponse(otpCodeVerifyData), err.Error())
+		return
+	}
+
+	returnStatus(c, nil, http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
+}
+
+func logRegisterWebAuthn(userIp string, userAgent string, userAddress sdkCore.AddressHandler, request *requests.RegisterWebAuthn, debugErr error) {
+	logArgs := []interface{}{
+		"route", registerWebAuthnPath,
+		"ip", userIp,
+		"user agent", userAgent,
+		"guardian", request.Guardian,
+	}
+	defer func() {
+		guardianLog.Info("Request info", logArgs...)
+	}()
+
+	if !check.IfNil(userAddress) {
+		bech32Addr, err := userAddress.AddressAsBech32String()
+		if err == nil {
+			logArgs = append(logArgs, "address", bech32Addr)
+		}
+	}
+
+	if debugErr == nil {
+		logArgs = append(logArgs, "result", "success")
+		return
+	}
+
+	if strings.Contains(debugErr.Error(), wrongCodeError) {
+		logArgs = append(logArgs, "code", request.Code)
+	}
+	logArgs = append(logArgs, "error", debugErr.Error())
+}
+
 // signTransaction returns the transaction signed by the guardian if the verification passed
 func (gg *guardianGroup) signTransaction(c *gin.Context) {
 	var request requests.SignTransaction
+	var signTransactionResponse *requests.SignTransactionResponse
 	var debugErr error
 
 	userIp := c.GetString(mfaMiddleware.UserIpKey)
 	userAgent := c.GetString(mfaMiddleware.UserAgentKey)
 	defer func() {
 		logSignTransaction(userIp, userAgent, &request, debugErr)
+		auditedTx := request.Tx
+		if signTransactionResponse != nil {
+			auditedTx = signTransactionResponse.Tx
+		}
+		recordAuditEntry(gg.facade, c, request.Tx.Sender, request.Tx.GuardianAddr, debugErr, []transaction.FrontendTransaction{auditedTx})
 	}()
 
 	err := json.NewDecoder(c.Request.Body).Decode(&request)
@@ -255,7 +583,6 @@ func (gg *guardianGroup) signTransaction(c *gin.Context) {
 		return
 	}
 
-	var signTransactionResponse *requests.SignTransactionResponse
 	marshalledTx, otpCodeVerifyData, err := gg.facade.SignTransaction(userIp, request)
 	if err != nil {
 		debugErr = fmt.Errorf("%w while signing transaction", err)
@@ -298,12 +625,15 @@ func logSignTransaction(userIp string, userAgent string, request *requests.SignT
 // signMultipleTransactions returns the transactions signed by the guardian if the verification passed
 func (gg *guardianGroup) signMultipleTransactions(c *gin.Context) {
 	var request requests.SignMultipleTransactions
+	var signMultipleTransactionsResponse *requests.SignMultipleTransactionsResponse
 	var debugErr error
 
 	userIp := c.GetString(mfaMiddleware.UserIpKey)
 	userAgent := c.GetString(mfaMiddleware.UserAgentKey)
 	defer func() {
 		logSignMultipleTransactions(userIp, userAgent, &request, debugErr)
+		userAddr, guardian, auditedTxs := getAuditDataForMultipleTransactions(request.Txs, signMultipleTransactionsResponse)
+		recordAuditEntry(gg.facade, c, userAddr, guardian, debugErr, auditedTxs)
 	}()
 
 	err := json.NewDecoder(c.Request.Body).Decode(&request)
@@ -320,7 +650,6 @@ func (gg *guardianGroup) signMultipleTransactions(c *gin.Context) {
 		return
 	}
 
-	var signMultipleTransactionsResponse *requests.SignMultipleTransactionsResponse
 	signMultipleTransactionsResponse, err = createSignMultipleTransactionsResponse(marshalledTxs)
 	if err != nil {
 		debugErr = fmt.Errorf("%w while creating response", err)
@@ -331,6 +660,25 @@ func (gg *guardianGroup) signMultipleTransactions(c *gin.Context) {
 	returnStatus(c, signMultipleTransactionsResponse, http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
 }
 
+// previewTransactions returns the human-readable summary of the transactions and the guardian which would sign them,
+// without requiring a code
+func (gg *guardianGroup) previewTransactions(c *gin.Context) {
+	var request requests.SignMultipleTransactions
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	retData, err := gg.facade.PreviewTransactions(request)
+	if err != nil {
+		handleErrorAndReturn(c, nil, err.Error())
+		return
+	}
+
+	returnStatus(c, retData, http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
+}
+
 func logSignMultipleTransactions(userIp string, userAgent string, request *requests.SignMultipleTransactions, debugErr error) {
 	logArgs := []interface{}{
 		"route", signMultipleTransactionsPath,
@@ -353,6 +701,22 @@ func logSignMultipleTransactions(userIp string, userAgent string, request *reque
 	logArgs = append(logArgs, "error", debugErr.Error())
 }
 
+func getAuditDataForMultipleTransactions(
+	requestTxs []transaction.FrontendTransaction,
+	response *requests.SignMultipleTransactionsResponse,
+) (string, string, []transaction.FrontendTransaction) {
+	auditedTxs := requestTxs
+	if response != nil {
+		auditedTxs = response.Txs
+	}
+	if len(auditedTxs) == 0 {
+		return "", "", nil
+	}
+
+	// all the transactions must have the same sender and guardian in order to be signed
+	return auditedTxs[0].Sender, auditedTxs[0].GuardianAddr, auditedTxs
+}
+
 func createSignMultipleTransactionsResponse(marshalledTxs [][]byte) (*requests.SignMultipleTransactionsResponse, error) {
 	signMultipleTransactionsResponse := &requests.SignMultipleTransactionsResponse{
 		Txs: make([]transaction.FrontendTransaction, 0),
@@ -390,6 +754,7 @@ func (gg *guardianGroup) register(c *gin.Context) {
 	userAgent := c.GetString(mfaMiddleware.UserAgentKey)
 	defer func() {
 		logRegister(userIp, userAgent, userAddress, retData, debugErr)
+		recordAuditEntry(gg.facade, c, getBech32Address(userAddress), retData.GuardianAddress, debugErr, nil)
 	}()
 
 	userAddress, err := gg.extractAddressContext(c)
@@ -407,7 +772,7 @@ func (gg *guardianGroup) register(c *gin.Context) {
 		return
 	}
 
-	retData.OTP, retData.GuardianAddress, err = gg.facade.RegisterUser(userAddress, request)
+	retData.OTP, retData.GuardianAddress, err = gg.facade.RegisterUser(userAddress, userIp, request)
 	if err != nil {
 		debugErr = fmt.Errorf("%w while registering", err)
 		handleErrorAndReturn(c, retData, err.Error())
@@ -449,7 +814,7 @@ func logRegister(userIp string, userAgent string, userAddress sdkCore.AddressHan
 	logArgs = append(logArgs, "error", debugErr.Error())
 }
 
-// verifyCode validates a code
+// verifyCode validates a code and returns the recovery codes, if new ones were generated
 func (gg *guardianGroup) verifyCode(c *gin.Context) {
 	var request requests.VerificationPayload
 	var userAddress sdkCore.AddressHandler
@@ -459,6 +824,7 @@ func (gg *guardianGroup) verifyCode(c *gin.Context) {
 	userAgent := c.GetString(mfaMiddleware.UserAgentKey)
 	defer func() {
 		logVerifyCode(userIp, userAgent, userAddress, request, debugErr)
+		recordAuditEntry(gg.facade, c, getBech32Address(userAddress), request.Guardian, debugErr, nil)
 	}()
 
 	userAddress, err := gg.extractAddressContext(c)
@@ -475,19 +841,96 @@ func (gg *guardianGroup) verifyCode(c *gin.Context) {
 		return
 	}
 
-	otpVerifyCodeData, err := gg.facade.VerifyCode(userAddress, userIp, request)
+	retData, otpVerifyCodeData, err := gg.facade.VerifyCode(userAddress, userIp, request)
 	if err != nil {
 		debugErr = fmt.Errorf("%w while verifying code", err)
 		handleErrorAndReturn(c, getVerifyCodeResponse(otpVerifyCodeData), err.Error())
 		return
 	}
+	returnStatus(c, retData, http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
+}
+
+// regenerateRecoveryCodes replaces the recovery codes of the user if the verification passed
+func (gg *guardianGroup) regenerateRecoveryCodes(c *gin.Context) {
+	var request requests.VerificationPayload
+	var userAddress sdkCore.AddressHandler
+	var debugErr error
+
+	userIp := c.GetString(mfaMiddleware.UserIpKey)
+	userAgent := c.GetString(mfaMiddleware.UserAgentKey)
+	defer func() {
+		logVerifyCodeForRoute(regenerateRecoveryCodesPath, userIp, userAgent, userAddress, request, debugErr)
+		recordAuditEntry(gg.facade, c, getBech32Address(userAddress), request.Guardian, debugErr, nil)
+	}()
+
+	userAddress, err := gg.extractAddressContext(c)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while extracting user address", err)
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	err = json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while decoding request", err)
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	retData, otpVerifyCodeData, err := gg.facade.RegenerateRecoveryCodes(userAddress, userIp, request)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while regenerating recovery codes", err)
+		handleErrorAndReturn(c, getVerifyCodeResponse(otpVerifyCodeData), err.Error())
+		return
+	}
+
+	returnStatus(c, retData, http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
+}
+
+// deregister removes the user if the verification passed and none of its guardians is set on chain
+func (gg *guardianGroup) deregister(c *gin.Context) {
+	var request requests.VerificationPayload
+	var userAddress sdkCore.AddressHandler
+	var debugErr error
+
+	userIp := c.GetString(mfaMiddleware.UserIpKey)
+	userAgent := c.GetString(mfaMiddleware.UserAgentKey)
+	defer func() {
+		logVerifyCodeForRoute(deregisterPath, userIp, userAgent, userAddress, request, debugErr)
+		recordAuditEntry(gg.facade, c, getBech32Address(userAddress), request.Guardian, debugErr, nil)
+	}()
+
+	userAddress, err := gg.extractAddressContext(c)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while extracting user address", err)
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	err = json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while decoding request", err)
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), chainApiShared.ReturnCodeRequestError)
+		return
+	}
+
+	otpVerifyCodeData, err := gg.facade.DeregisterUser(userAddress, userIp, request)
+	if err != nil {
+		debugErr = fmt.Errorf("%w while deregistering user", err)
+		handleErrorAndReturn(c, getVerifyCodeResponse(otpVerifyCodeData), err.Error())
+		return
+	}
 
 	returnStatus(c, nil, http.StatusOK, "", chainApiShared.ReturnCodeSuccess)
 }
 
 func logVerifyCode(userIp string, userAgent string, userAddress sdkCore.AddressHandler, request requests.VerificationPayload, debugErr error) {
+	logVerifyCodeForRoute(verifyCodePath, userIp, userAgent, userAddress, request, debugErr)
+}
+
+func logVerifyCodeForRoute(route string, userIp string, userAgent string, userAddress sdkCore.AddressHandler, request requests.VerificationPayload, debugErr error) {
 	logArgs := []interface{}{
-		"route", verifyCodePath,
+		"route", route,
 		"ip", userIp,
 		"user agent", userAgent,
 		"guardian", request.Guardian,
@@ -547,7 +990,25 @@ func returnStatus(c *gin.Context, data interface{}, httpStatus int, err string,
 }
 
 func handleHTTPError(err string) (int, chainApiShared.ReturnCode) {
+	if strings.Contains(err, handlers.ErrTxPolicyRejected.Error()) {
+		return http.StatusForbidden, shared.ReturnCodeTxPolicyRejected
+	}
+
+	if strings.Contains(err, handlers.ErrTxPolicyConfirmationRequired.Error()) {
+		return http.StatusPreconditionRequired, shared.ReturnCodeTxPolicyConfirmationRequired
+	}
+
+	if strings.Contains(err, resolver.ErrSpendingLimitExceeded.Error()) {
+		return http.StatusForbidden, shared.ReturnCodeSpendingLimitExceeded
+	}
+
 	if strings.Contains(err, wrongCodeError) ||
+		strings.Contains(err, resolver.ErrInvalidSpendingPolicy.Error()) ||
+		strings.Contains(err, handlers.ErrInvalidWebAuthnResponse.Error()) ||
+		strings.Contains(err, resolver.ErrWebAuthnNotRegistered.Error()) ||
+		strings.Contains(err, resolver.ErrInvalidRecoveryCode.Error()) ||
+		strings.Contains(err, resolver.ErrEmptyUserIp.Error()) ||
+		strings.Contains(err, resolver.ErrInvalidHistoryLimit.Error()) ||
 		strings.Contains(err, resolver.ErrTooManyTransactionsToSign.Error()) ||
 		strings.Contains(err, resolver.ErrNoTransactionToSign.Error()) ||
 		strings.Contains(err, resolver.ErrGuardianMismatch.Error()) ||
@@ -563,7 +1024,8 @@ func handleHTTPError(err string) (int, chainApiShared.ReturnCode) {
 		return http.StatusTooManyRequests, chainApiShared.ReturnCodeRequestError
 	}
 
-	if strings.Contains(err, handlers.ErrRegistrationFailed.Error()) {
+	if strings.Contains(err, handlers.ErrRegistrationFailed.Error()) ||
+		strings.Contains(err, resolver.ErrGuardianSetOnChain.Error()) {
 		return http.StatusForbidden, chainApiShared.ReturnCodeRequestError
 	}
 
@@ -594,6 +1056,20 @@ func (gg *guardianGroup) extractAddressContext(c *gin.Context) (sdkCore.AddressH
 	return data.NewAddressFromBech32String(userAddressStr)
 }
 
+func getUint32QueryParam(c *gin.Context, name string) (uint32, error) {
+	value := c.Query(name)
+	if len(value) == 0 {
+		return 0, nil
+	}
+
+	parsedValue, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s query param: %w", name, err)
+	}
+
+	return uint32(parsedValue), nil
+}
+
 func getPrintableData(txData interface{}) string {
 	txDataBuff, err := json.Marshal(txData)
 	if err != nil {
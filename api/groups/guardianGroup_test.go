@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/multiversx/mx-multi-factor-auth-go-service/api/groups"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/api/shared"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
@@ -722,7 +723,7 @@ func TestGuardianGroup_register(t *testing.T) {
 		t.Parallel()
 
 		facade := mockFacade.GuardianFacadeStub{
-			RegisterUserCalled: func(userAddress sdkCore.AddressHandler, request requests.RegistrationPayload) (*requests.OTP, string, error) {
+			RegisterUserCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.RegistrationPayload) (*requests.OTP, string, error) {
 				return &requests.OTP{}, "", expectedError
 			},
 		}
@@ -755,7 +756,7 @@ func TestGuardianGroup_register(t *testing.T) {
 		}
 		expectedGuardian := "guardian"
 		facade := mockFacade.GuardianFacadeStub{
-			RegisterUserCalled: func(userAddress sdkCore.AddressHandler, request requests.RegistrationPayload) (*requests.OTP, string, error) {
+			RegisterUserCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.RegistrationPayload) (*requests.OTP, string, error) {
 				return expectedOtpInfo, expectedGuardian, nil
 			},
 		}
@@ -827,8 +828,8 @@ func TestGuardianGroup_verifyCode(t *testing.T) {
 		t.Parallel()
 
 		facade := mockFacade.GuardianFacadeStub{
-			VerifyCodeCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error) {
-				return nil, expectedError
+			VerifyCodeCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error) {
+				return nil, nil, expectedError
 			},
 		}
 
@@ -853,8 +854,8 @@ func TestGuardianGroup_verifyCode(t *testing.T) {
 		t.Parallel()
 
 		facade := mockFacade.GuardianFacadeStub{
-			VerifyCodeCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error) {
-				return nil, wrongCodeError
+			VerifyCodeCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error) {
+				return nil, nil, wrongCodeError
 			},
 		}
 
@@ -879,8 +880,8 @@ func TestGuardianGroup_verifyCode(t *testing.T) {
 		t.Parallel()
 
 		facade := mockFacade.GuardianFacadeStub{
-			VerifyCodeCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error) {
-				return nil, nil
+			VerifyCodeCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error) {
+				return nil, nil, nil
 			},
 		}
 
@@ -897,6 +898,985 @@ func TestGuardianGroup_verifyCode(t *testing.T) {
 
 		require.Equal(t, http.StatusOK, resp.Code)
 	})
+	t.Run("should work and return the new recovery codes", func(t *testing.T) {
+		t.Parallel()
+
+		expectedRecoveryCodes := &requests.RecoveryCodesResponse{
+			RecoveryCodes: []string{"AAAA-BBBB-CCCC-DDDD"},
+		}
+		facade := mockFacade.GuardianFacadeStub{
+			VerifyCodeCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error) {
+				return expectedRecoveryCodes, nil, nil
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/verify-code", requestToReader(requests.VerificationPayload{}))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		expectedGenResponse := createExpectedGeneralResponse(expectedRecoveryCodes, "")
+
+		assert.Equal(t, expectedGenResponse.Data, statusRsp.Data)
+		require.Equal(t, http.StatusOK, resp.Code)
+	})
+}
+
+func TestGuardianGroup_regenerateRecoveryCodes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty address", func(t *testing.T) {
+		t.Parallel()
+
+		gg, _ := groups.NewGuardianGroup(&mockFacade.GuardianFacadeStub{})
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), "")
+
+		req, _ := http.NewRequest("POST", "/guardian/recovery-codes/regenerate", strings.NewReader(""))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, "bech32"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("empty body", func(t *testing.T) {
+		t.Parallel()
+
+		gg, _ := groups.NewGuardianGroup(&mockFacade.GuardianFacadeStub{})
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/recovery-codes/regenerate", strings.NewReader(""))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, "EOF"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("facade returns wrong code", func(t *testing.T) {
+		t.Parallel()
+
+		facade := mockFacade.GuardianFacadeStub{
+			RegenerateRecoveryCodesCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error) {
+				return nil, nil, wrongCodeError
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/recovery-codes/regenerate", requestToReader(requests.VerificationPayload{}))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		expectedGenResponse := createExpectedGeneralResponse(&requests.OTPCodeVerifyDataResponse{}, "")
+
+		assert.Equal(t, expectedGenResponse.Data, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, wrongCodeError.Error()))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		providedRequest := requests.VerificationPayload{
+			Code:     "123456",
+			Guardian: "guardian",
+		}
+		expectedRecoveryCodes := &requests.RecoveryCodesResponse{
+			RecoveryCodes: []string{"AAAA-BBBB-CCCC-DDDD", "EEEE-FFFF-GGGG-HHHH"},
+		}
+		facade := mockFacade.GuardianFacadeStub{
+			RegenerateRecoveryCodesCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error) {
+				assert.Equal(t, providedRequest, request)
+				return expectedRecoveryCodes, nil, nil
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/recovery-codes/regenerate", requestToReader(providedRequest))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		expectedGenResponse := createExpectedGeneralResponse(expectedRecoveryCodes, "")
+
+		assert.Equal(t, expectedGenResponse.Data, statusRsp.Data)
+		assert.Equal(t, expectedGenResponse.Error, statusRsp.Error)
+		require.Equal(t, http.StatusOK, resp.Code)
+	})
+}
+
+func TestGuardianGroup_deregister(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty address", func(t *testing.T) {
+		t.Parallel()
+
+		gg, _ := groups.NewGuardianGroup(&mockFacade.GuardianFacadeStub{})
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), "")
+
+		req, _ := http.NewRequest("POST", "/guardian/deregister", strings.NewReader(""))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, "bech32"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("empty body", func(t *testing.T) {
+		t.Parallel()
+
+		gg, _ := groups.NewGuardianGroup(&mockFacade.GuardianFacadeStub{})
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/deregister", strings.NewReader(""))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, "EOF"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("facade returns wrong code", func(t *testing.T) {
+		t.Parallel()
+
+		facade := mockFacade.GuardianFacadeStub{
+			DeregisterUserCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error) {
+				return nil, wrongCodeError
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/deregister", requestToReader(requests.VerificationPayload{}))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		expectedGenResponse := createExpectedGeneralResponse(&requests.OTPCodeVerifyDataResponse{}, "")
+
+		assert.Equal(t, expectedGenResponse.Data, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, wrongCodeError.Error()))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("facade returns guardian set on chain", func(t *testing.T) {
+		t.Parallel()
+
+		facade := mockFacade.GuardianFacadeStub{
+			DeregisterUserCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error) {
+				return nil, resolver.ErrGuardianSetOnChain
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/deregister", requestToReader(requests.VerificationPayload{}))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.True(t, strings.Contains(statusRsp.Error, resolver.ErrGuardianSetOnChain.Error()))
+		require.Equal(t, http.StatusForbidden, resp.Code)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		providedRequest := requests.VerificationPayload{
+			Code:     "123456",
+			Guardian: "guardian",
+		}
+		wasCalled := false
+		facade := mockFacade.GuardianFacadeStub{
+			DeregisterUserCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error) {
+				assert.Equal(t, providedRequest, request)
+				bech32Addr, _ := userAddress.AddressAsBech32String()
+				assert.Equal(t, providedAddr, bech32Addr)
+				wasCalled = true
+				return nil, nil
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/deregister", requestToReader(providedRequest))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.Empty(t, statusRsp.Error)
+		assert.True(t, wasCalled)
+		require.Equal(t, http.StatusOK, resp.Code)
+	})
+}
+
+func TestGuardianGroup_setSpendingPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty address", func(t *testing.T) {
+		t.Parallel()
+
+		gg, _ := groups.NewGuardianGroup(&mockFacade.GuardianFacadeStub{})
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), "")
+
+		req, _ := http.NewRequest("POST", "/guardian/set-spending-policy", strings.NewReader(""))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, "bech32"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("empty body", func(t *testing.T) {
+		t.Parallel()
+
+		gg, _ := groups.NewGuardianGroup(&mockFacade.GuardianFacadeStub{})
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/set-spending-policy", strings.NewReader(""))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, "EOF"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("facade returns invalid policy", func(t *testing.T) {
+		t.Parallel()
+
+		facade := mockFacade.GuardianFacadeStub{
+			SetSpendingPolicyCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.SetSpendingPolicy) (*requests.OTPCodeVerifyData, error) {
+				return nil, resolver.ErrInvalidSpendingPolicy
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/set-spending-policy", requestToReader(requests.SetSpendingPolicy{}))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		expectedGenResponse := createExpectedGeneralResponse(&requests.OTPCodeVerifyDataResponse{}, "")
+
+		assert.Equal(t, expectedGenResponse.Data, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, resolver.ErrInvalidSpendingPolicy.Error()))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		providedRequest := requests.SetSpendingPolicy{
+			Code:     "123456",
+			Guardian: "guardian",
+			Limits: []requests.SpendingLimit{
+				{
+					Token: "EGLD",
+					Daily: "1000",
+				},
+			},
+			TrustedReceivers: []string{providedAddr},
+		}
+		wasCalled := false
+		facade := mockFacade.GuardianFacadeStub{
+			SetSpendingPolicyCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.SetSpendingPolicy) (*requests.OTPCodeVerifyData, error) {
+				wasCalled = true
+				assert.Equal(t, providedRequest, request)
+				return nil, nil
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/set-spending-policy", requestToReader(providedRequest))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.True(t, wasCalled)
+	})
+}
+
+func TestGuardianGroup_spendingPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty address", func(t *testing.T) {
+		t.Parallel()
+
+		gg, _ := groups.NewGuardianGroup(&mockFacade.GuardianFacadeStub{})
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), "")
+
+		req, _ := http.NewRequest("GET", "/guardian/spending-policy", nil)
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, "bech32"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("facade returns error", func(t *testing.T) {
+		t.Parallel()
+
+		facade := mockFacade.GuardianFacadeStub{
+			GetSpendingPolicyCalled: func(userAddress sdkCore.AddressHandler) (*requests.SpendingPolicyResponse, error) {
+				return nil, expectedError
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("GET", "/guardian/spending-policy", nil)
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, expectedError.Error()))
+		require.Equal(t, http.StatusInternalServerError, resp.Code)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		expectedData := &requests.SpendingPolicyResponse{
+			Active: requests.SpendingPolicy{
+				Limits: []requests.SpendingLimit{
+					{
+						Token:  "EGLD",
+						Weekly: "1000",
+					},
+				},
+				TrustedReceivers: []string{providedAddr},
+			},
+			Spent: []requests.SpentAmount{
+				{
+					Token:       "EGLD",
+					DailySpent:  "10",
+					WeeklySpent: "100",
+				},
+			},
+		}
+		facade := mockFacade.GuardianFacadeStub{
+			GetSpendingPolicyCalled: func(userAddress sdkCore.AddressHandler) (*requests.SpendingPolicyResponse, error) {
+				return expectedData, nil
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("GET", "/guardian/spending-policy", nil)
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		expectedGenResponse := createExpectedGeneralResponse(expectedData, "")
+
+		assert.Equal(t, expectedGenResponse.Data, statusRsp.Data)
+		assert.Equal(t, expectedGenResponse.Error, statusRsp.Error)
+		require.Equal(t, http.StatusOK, resp.Code)
+	})
+}
+
+func TestGuardianGroup_status(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty address", func(t *testing.T) {
+		t.Parallel()
+
+		gg, _ := groups.NewGuardianGroup(&mockFacade.GuardianFacadeStub{})
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), "")
+
+		req, _ := http.NewRequest("GET", "/guardian/status", nil)
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, "bech32"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("facade returns error", func(t *testing.T) {
+		t.Parallel()
+
+		facade := mockFacade.GuardianFacadeStub{
+			GetGuardianStatusCalled: func(userAddress sdkCore.AddressHandler, userIp string) (*requests.GuardianStatusResponse, error) {
+				return nil, expectedError
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("GET", "/guardian/status", nil)
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, expectedError.Error()))
+		require.Equal(t, http.StatusInternalServerError, resp.Code)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		expectedData := &requests.GuardianStatusResponse{
+			Guardians: []requests.GuardianStatus{
+				{
+					Address:                  "erd1guardian1",
+					Usable:                   true,
+					ActivationState:          "Active",
+					VerifiedTimestamp:        1700000000,
+					LastOTPChangeTimestamp:   1699999000,
+					RegistrationAllowedAfter: 120,
+				},
+				{
+					Address:         "erd1guardian2",
+					ActivationState: "Untracked",
+				},
+			},
+			SecurityModeActive:   true,
+			SecurityModeNoExpire: true,
+			VerifyData: &requests.OTPCodeVerifyData{
+				RemainingTrials:        3,
+				SecurityModeResetAfter: -1,
+			},
+		}
+		facade := mockFacade.GuardianFacadeStub{
+			GetGuardianStatusCalled: func(userAddress sdkCore.AddressHandler, userIp string) (*requests.GuardianStatusResponse, error) {
+				return expectedData, nil
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("GET", "/guardian/status", nil)
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		expectedGenResponse := createExpectedGeneralResponse(expectedData, "")
+
+		assert.Equal(t, expectedGenResponse.Data, statusRsp.Data)
+		assert.Equal(t, expectedGenResponse.Error, statusRsp.Error)
+		require.Equal(t, http.StatusOK, resp.Code)
+	})
+}
+
+func TestGuardianGroup_previewTransactions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty body", func(t *testing.T) {
+		t.Parallel()
+
+		gg, _ := groups.NewGuardianGroup(&mockFacade.GuardianFacadeStub{})
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/preview-transactions", strings.NewReader(""))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, "EOF"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("facade returns error", func(t *testing.T) {
+		t.Parallel()
+
+		facade := mockFacade.GuardianFacadeStub{
+			PreviewTransactionsCalled: func(request requests.SignMultipleTransactions) (*requests.PreviewTransactionsResponse, error) {
+				return nil, expectedError
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		request := requests.SignMultipleTransactions{
+			Txs: []transaction.FrontendTransaction{{}},
+		}
+		req, _ := http.NewRequest("POST", "/guardian/preview-transactions", requestToReader(request))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, expectedError.Error()))
+		require.Equal(t, http.StatusInternalServerError, resp.Code)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		providedRequest := requests.SignMultipleTransactions{
+			Txs: []transaction.FrontendTransaction{
+				{
+					Nonce:        1,
+					Sender:       providedAddr,
+					Receiver:     "erd1receiver",
+					Value:        "100",
+					GuardianAddr: "erd1guardian",
+				},
+			},
+		}
+		expectedData := &requests.PreviewTransactionsResponse{
+			Guardian: requests.GuardianStatus{
+				Address:         "erd1guardian",
+				Usable:          true,
+				ActivationState: "Active",
+			},
+			ConfirmationRequired: true,
+			Transactions: []requests.TransactionSummary{
+				{
+					Nonce:     1,
+					Sender:    providedAddr,
+					Receiver:  "erd1receiver",
+					Type:      "egld-transfer",
+					Transfers: []requests.TokenTransfer{{Token: "EGLD", Amount: "100"}},
+					MaxFee:    "0",
+				},
+			},
+		}
+		facade := mockFacade.GuardianFacadeStub{
+			PreviewTransactionsCalled: func(request requests.SignMultipleTransactions) (*requests.PreviewTransactionsResponse, error) {
+				assert.Equal(t, providedRequest, request)
+				return expectedData, nil
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/preview-transactions", requestToReader(providedRequest))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		expectedGenResponse := createExpectedGeneralResponse(expectedData, "")
+
+		assert.Equal(t, expectedGenResponse.Data, statusRsp.Data)
+		assert.Equal(t, expectedGenResponse.Error, statusRsp.Error)
+		require.Equal(t, http.StatusOK, resp.Code)
+	})
+}
+
+func TestGuardianGroup_history(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty address", func(t *testing.T) {
+		t.Parallel()
+
+		gg, _ := groups.NewGuardianGroup(&mockFacade.GuardianFacadeStub{})
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), "")
+
+		req, _ := http.NewRequest("GET", "/guardian/history", nil)
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, "bech32"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("invalid offset", func(t *testing.T) {
+		t.Parallel()
+
+		gg, _ := groups.NewGuardianGroup(&mockFacade.GuardianFacadeStub{})
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("GET", "/guardian/history?offset=-1", nil)
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, "offset"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("invalid limit", func(t *testing.T) {
+		t.Parallel()
+
+		gg, _ := groups.NewGuardianGroup(&mockFacade.GuardianFacadeStub{})
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("GET", "/guardian/history?limit=abc", nil)
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, "limit"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("facade returns invalid limit error", func(t *testing.T) {
+		t.Parallel()
+
+		facade := mockFacade.GuardianFacadeStub{
+			GetHistoryCalled: func(userAddress sdkCore.AddressHandler, offset uint32, limit uint32) (*requests.UserHistoryResponse, error) {
+				return nil, resolver.ErrInvalidHistoryLimit
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("GET", "/guardian/history?limit=1000", nil)
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, resolver.ErrInvalidHistoryLimit.Error()))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		expectedData := &requests.UserHistoryResponse{
+			Entries: []requests.HistoryEntry{
+				{
+					Timestamp: 100,
+					Route:     "/guardian/sign-transaction",
+					UserIp:    "127.0.0.1",
+					TxHashes:  []string{"hash"},
+					Outcome:   "success",
+				},
+			},
+			Offset: 10,
+			Limit:  5,
+		}
+		facade := mockFacade.GuardianFacadeStub{
+			GetHistoryCalled: func(userAddress sdkCore.AddressHandler, offset uint32, limit uint32) (*requests.UserHistoryResponse, error) {
+				bech32Addr, _ := userAddress.AddressAsBech32String()
+				assert.Equal(t, providedAddr, bech32Addr)
+				assert.Equal(t, uint32(10), offset)
+				assert.Equal(t, uint32(5), limit)
+				return expectedData, nil
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("GET", "/guardian/history?offset=10&limit=5", nil)
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		expectedGenResponse := createExpectedGeneralResponse(expectedData, "")
+
+		assert.Equal(t, expectedGenResponse.Data, statusRsp.Data)
+		assert.Equal(t, expectedGenResponse.Error, statusRsp.Error)
+		require.Equal(t, http.StatusOK, resp.Code)
+	})
+}
+
+func TestGuardianGroup_webAuthnChallenge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty address", func(t *testing.T) {
+		t.Parallel()
+
+		gg, _ := groups.NewGuardianGroup(&mockFacade.GuardianFacadeStub{})
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), "")
+
+		req, _ := http.NewRequest("POST", "/guardian/webauthn-challenge", requestToReader(requests.WebAuthnChallenge{}))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, "bech32"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("empty body", func(t *testing.T) {
+		t.Parallel()
+
+		gg, _ := groups.NewGuardianGroup(&mockFacade.GuardianFacadeStub{})
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/webauthn-challenge", strings.NewReader(""))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, "EOF"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("facade returns error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedOtpCodeVerifyData := &requests.OTPCodeVerifyData{
+			RemainingTrials:             1,
+			ResetAfter:                  100,
+			SecurityModeRemainingTrials: 50,
+			SecurityModeResetAfter:      600,
+		}
+		facade := mockFacade.GuardianFacadeStub{
+			WebAuthnChallengeCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.WebAuthnChallenge) (*requests.WebAuthnChallengeResponse, *requests.OTPCodeVerifyData, error) {
+				return nil, expectedOtpCodeVerifyData, core.ErrTooManyFailedAttempts
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/webauthn-challenge", requestToReader(requests.WebAuthnChallenge{}))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		expectedGenResponse := createExpectedGeneralResponse(&requests.OTPCodeVerifyDataResponse{VerifyData: expectedOtpCodeVerifyData}, "")
+
+		assert.Equal(t, expectedGenResponse.Data, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, core.ErrTooManyFailedAttempts.Error()))
+		require.Equal(t, http.StatusTooManyRequests, resp.Code)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		providedRequest := requests.WebAuthnChallenge{
+			Guardian: "guardian",
+		}
+		expectedResponse := &requests.WebAuthnChallengeResponse{
+			Challenge:    "challenge",
+			CredentialID: "credential",
+		}
+		facade := mockFacade.GuardianFacadeStub{
+			WebAuthnChallengeCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.WebAuthnChallenge) (*requests.WebAuthnChallengeResponse, *requests.OTPCodeVerifyData, error) {
+				bech32Addr, _ := userAddress.AddressAsBech32String()
+				assert.Equal(t, providedAddr, bech32Addr)
+				assert.Equal(t, providedRequest, request)
+				return expectedResponse, nil, nil
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/webauthn-challenge", requestToReader(providedRequest))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		expectedGenResponse := createExpectedGeneralResponse(expectedResponse, "")
+
+		assert.Equal(t, expectedGenResponse.Data, statusRsp.Data)
+		assert.Equal(t, expectedGenResponse.Error, statusRsp.Error)
+		require.Equal(t, http.StatusOK, resp.Code)
+	})
+}
+
+func TestGuardianGroup_registerWebAuthn(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty address", func(t *testing.T) {
+		t.Parallel()
+
+		gg, _ := groups.NewGuardianGroup(&mockFacade.GuardianFacadeStub{})
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), "")
+
+		req, _ := http.NewRequest("POST", "/guardian/register-webauthn", strings.NewReader(""))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, "bech32"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("empty body", func(t *testing.T) {
+		t.Parallel()
+
+		gg, _ := groups.NewGuardianGroup(&mockFacade.GuardianFacadeStub{})
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/register-webauthn", strings.NewReader(""))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		assert.Nil(t, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, "EOF"))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("facade returns invalid webauthn response", func(t *testing.T) {
+		t.Parallel()
+
+		facade := mockFacade.GuardianFacadeStub{
+			RegisterWebAuthnCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.RegisterWebAuthn) (*requests.OTPCodeVerifyData, error) {
+				return nil, handlers.ErrInvalidWebAuthnResponse
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/register-webauthn", requestToReader(requests.RegisterWebAuthn{}))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		statusRsp := generalResponse{}
+		loadResponse(resp.Body, &statusRsp)
+
+		expectedGenResponse := createExpectedGeneralResponse(&requests.OTPCodeVerifyDataResponse{}, "")
+
+		assert.Equal(t, expectedGenResponse.Data, statusRsp.Data)
+		assert.True(t, strings.Contains(statusRsp.Error, handlers.ErrInvalidWebAuthnResponse.Error()))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		providedRequest := requests.RegisterWebAuthn{
+			Code:     "123456",
+			Guardian: "guardian",
+			Credential: requests.WebAuthnRegistration{
+				ClientDataJSON:    "client data",
+				AttestationObject: "attestation object",
+			},
+		}
+		wasCalled := false
+		facade := mockFacade.GuardianFacadeStub{
+			RegisterWebAuthnCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.RegisterWebAuthn) (*requests.OTPCodeVerifyData, error) {
+				wasCalled = true
+				assert.Equal(t, providedRequest, request)
+				return nil, nil
+			},
+		}
+
+		gg, _ := groups.NewGuardianGroup(&facade)
+
+		ws := startWebServer(gg, "guardian", getServiceRoutesConfig(), providedAddr)
+
+		req, _ := http.NewRequest("POST", "/guardian/register-webauthn", requestToReader(providedRequest))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.True(t, wasCalled)
+	})
 }
 
 func TestGuardianGroup_registeredUsers(t *testing.T) {
@@ -1055,6 +2035,14 @@ func TestHandleHTTPError(t *testing.T) {
 		{resolver.ErrGuardianMismatch.Error(), http.StatusBadRequest, chainApiShared.ReturnCodeRequestError},
 		{core.ErrTooManyFailedAttempts.Error(), http.StatusTooManyRequests, chainApiShared.ReturnCodeRequestError},
 		{handlers.ErrRegistrationFailed.Error(), http.StatusForbidden, chainApiShared.ReturnCodeRequestError},
+		{handlers.ErrTxPolicyRejected.Error(), http.StatusForbidden, shared.ReturnCodeTxPolicyRejected},
+		{handlers.ErrTxPolicyConfirmationRequired.Error() + " with codeError " + wrongCodeError.Error(), http.StatusPreconditionRequired, shared.ReturnCodeTxPolicyConfirmationRequired},
+		{resolver.ErrSpendingLimitExceeded.Error(), http.StatusForbidden, shared.ReturnCodeSpendingLimitExceeded},
+		{resolver.ErrInvalidSpendingPolicy.Error(), http.StatusBadRequest, chainApiShared.ReturnCodeRequestError},
+		{handlers.ErrInvalidWebAuthnResponse.Error(), http.StatusBadRequest, chainApiShared.ReturnCodeRequestError},
+		{resolver.ErrWebAuthnNotRegistered.Error(), http.StatusBadRequest, chainApiShared.ReturnCodeRequestError},
+		{resolver.ErrInvalidRecoveryCode.Error(), http.StatusBadRequest, chainApiShared.ReturnCodeRequestError},
+		{resolver.ErrEmptyUserIp.Error(), http.StatusBadRequest, chainApiShared.ReturnCodeRequestError},
 		{"other internal error", http.StatusInternalServerError, chainApiShared.ReturnCodeInternalError},
 	}
 
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/multiversx/mx-chain-go/api/shared"
+)
+
+const (
+	bearerPrefix         = "Bearer "
+	minAdminAPIKeyLength = 32
+)
+
+type adminAuth struct {
+	apiKey []byte
+}
+
+// NewAdminAuth returns a new instance of adminAuth, which requires the provided static key as bearer token.
+// It should be used on the admin group only. An empty key rejects all the requests
+func NewAdminAuth(apiKey string) (*adminAuth, error) {
+	if len(apiKey) > 0 && len(apiKey) < minAdminAPIKeyLength {
+		return nil, fmt.Errorf("%w, it should have at least %d characters", ErrInvalidAdminAPIKey, minAdminAPIKeyLength)
+	}
+
+	return &adminAuth{
+		apiKey: []byte(apiKey),
+	}, nil
+}
+
+// MiddlewareHandlerFunc returns the handler func used by the gin server when processing requests
+func (middleware *adminAuth) MiddlewareHandlerFunc() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !middleware.isAuthorized(c.Request.Header.Get("Authorization")) {
+			log.Debug("admin request rejected", "path", c.Request.URL.Path, "ip", c.ClientIP())
+			c.AbortWithStatusJSON(
+				http.StatusUnauthorized,
+				shared.GenericAPIResponse{
+					Data:  nil,
+					Error: ErrUnauthorizedAdminRequest.Error(),
+					Code:  shared.ReturnCodeRequestError,
+				},
+			)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func (middleware *adminAuth) isAuthorized(authHeader string) bool {
+	if len(middleware.apiKey) == 0 || !strings.HasPrefix(authHeader, bearerPrefix) {
+		return false
+	}
+
+	providedKey := []byte(strings.TrimPrefix(authHeader, bearerPrefix))
+
+	return subtle.ConstantTimeCompare(providedKey, middleware.apiKey) == 1
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (middleware *adminAuth) IsInterfaceNil() bool {
+	return middleware == nil
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const providedAdminAPIKey = "0123456789abcdef0123456789abcdef"
+
+func startServerWithAdminAuth(t *testing.T, apiKey string) *gin.Engine {
+	ws := gin.New()
+	ws.Use(cors.Default())
+
+	adminAuthMiddleware, err := NewAdminAuth(apiKey)
+	require.Nil(t, err)
+	require.False(t, check.IfNil(adminAuthMiddleware))
+
+	handler := func(c *gin.Context) {
+		c.JSON(http.StatusOK, nil)
+	}
+	ws.Group("/admin", adminAuthMiddleware.MiddlewareHandlerFunc()).Handle(http.MethodGet, "/user-state", handler)
+	ws.Group("/guardian").Handle(http.MethodGet, "/config", handler)
+
+	return ws
+}
+
+func sendAdminRequest(ws *gin.Engine, path string, authHeader string) int {
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	if len(authHeader) > 0 {
+		req.Header.Set("Authorization", authHeader)
+	}
+	resp := httptest.NewRecorder()
+	ws.ServeHTTP(resp, req)
+
+	return resp.Code
+}
+
+func TestNewAdminAuth(t *testing.T) {
+	t.Parallel()
+
+	adminAuthMiddleware, err := NewAdminAuth("short key")
+	assert.True(t, errors.Is(err, ErrInvalidAdminAPIKey))
+	assert.Nil(t, adminAuthMiddleware)
+
+	adminAuthMiddleware, err = NewAdminAuth("")
+	assert.Nil(t, err)
+	assert.False(t, adminAuthMiddleware.IsInterfaceNil())
+
+	adminAuthMiddleware, err = NewAdminAuth(providedAdminAPIKey)
+	assert.Nil(t, err)
+	assert.False(t, adminAuthMiddleware.IsInterfaceNil())
+}
+
+func TestAdminAuth_MiddlewareHandlerFunc(t *testing.T) {
+	t.Parallel()
+
+	t.Run("non admin routes should not require the key", func(t *testing.T) {
+		t.Parallel()
+
+		ws := startServerWithAdminAuth(t, providedAdminAPIKey)
+		assert.Equal(t, http.StatusOK, sendAdminRequest(ws, "/guardian/config", ""))
+	})
+	t.Run("missing key should error", func(t *testing.T) {
+		t.Parallel()
+
+		ws := startServerWithAdminAuth(t, providedAdminAPIKey)
+		assert.Equal(t, http.StatusUnauthorized, sendAdminRequest(ws, "/admin/user-state", ""))
+		assert.Equal(t, http.StatusUnauthorized, sendAdminRequest(ws, "/admin/user-state", providedAdminAPIKey))
+	})
+	t.Run("wrong key should error", func(t *testing.T) {
+		t.Parallel()
+
+		ws := startServerWithAdminAuth(t, providedAdminAPIKey)
+		wrongKey := strings.ToUpper(providedAdminAPIKey)
+		assert.Equal(t, http.StatusUnauthorized, sendAdminRequest(ws, "/admin/user-state", bearerPrefix+wrongKey))
+		assert.Equal(t, http.StatusUnauthorized, sendAdminRequest(ws, "/admin/user-state", bearerPrefix+providedAdminAPIKey[1:]))
+	})
+	t.Run("no configured key should reject all admin requests", func(t *testing.T) {
+		t.Parallel()
+
+		ws := startServerWithAdminAuth(t, "")
+		assert.Equal(t, http.StatusUnauthorized, sendAdminRequest(ws, "/admin/user-state", bearerPrefix))
+		assert.Equal(t, http.StatusOK, sendAdminRequest(ws, "/guardian/config", ""))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		ws := startServerWithAdminAuth(t, providedAdminAPIKey)
+		assert.Equal(t, http.StatusOK, sendAdminRequest(ws, "/admin/user-state", bearerPrefix+providedAdminAPIKey))
+	})
+}
@@ -13,3 +13,9 @@ var ErrContentLengthTooLarge = errors.New("content length too large")
 
 // ErrInvalidPath signals that the path is invalid
 var ErrInvalidPath = errors.New("invalid path")
+
+// ErrInvalidAdminAPIKey signals that an invalid admin api key has been provided
+var ErrInvalidAdminAPIKey = errors.New("invalid admin api key")
+
+// ErrUnauthorizedAdminRequest signals that the admin request is not authorized
+var ErrUnauthorizedAdminRequest = errors.New("unauthorized admin request")
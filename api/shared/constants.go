@@ -0,0 +1,14 @@
+package shared
+
+import chainApiShared "github.com/multiversx/mx-chain-go/api/shared"
+
+const (
+	// ReturnCodeTxPolicyRejected signals that the transactions were rejected by the guardian policy
+	ReturnCodeTxPolicyRejected chainApiShared.ReturnCode = "tx_policy_rejected"
+
+	// ReturnCodeTxPolicyConfirmationRequired signals that the transactions require a second code as confirmation
+	ReturnCodeTxPolicyConfirmationRequired chainApiShared.ReturnCode = "tx_policy_confirmation_required"
+
+	// ReturnCodeSpendingLimitExceeded signals that the transactions would exceed the spending limits set by the user
+	ReturnCodeSpendingLimitExceeded chainApiShared.ReturnCode = "spending_limit_exceeded"
+)
@@ -2,6 +2,7 @@ package shared
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
 	"github.com/multiversx/mx-sdk-go/core"
 
 	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
@@ -21,17 +22,35 @@ type GroupHandler interface {
 
 // FacadeHandler defines all the methods that a facade should implement
 type FacadeHandler interface {
-	VerifyCode(userAddress core.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error)
-	RegisterUser(userAddress core.AddressHandler, request requests.RegistrationPayload) (*requests.OTP, string, error)
+	VerifyCode(userAddress core.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error)
+	RegisterUser(userAddress core.AddressHandler, userIp string, request requests.RegistrationPayload) (*requests.OTP, string, error)
 	SignMessage(userIp string, request requests.SignMessage) ([]byte, *requests.OTPCodeVerifyData, error)
 	SignTransaction(userIp string, request requests.SignTransaction) ([]byte, *requests.OTPCodeVerifyData, error)
 	SignMultipleTransactions(userIp string, request requests.SignMultipleTransactions) ([][]byte, *requests.OTPCodeVerifyData, error)
 	SetSecurityModeNoExpire(userIp string, request requests.SecurityModeNoExpire) (*requests.OTPCodeVerifyData, error)
 	UnsetSecurityModeNoExpire(userIp string, request requests.SecurityModeNoExpire) (*requests.OTPCodeVerifyData, error)
+	SetSpendingPolicy(userAddress core.AddressHandler, userIp string, request requests.SetSpendingPolicy) (*requests.OTPCodeVerifyData, error)
+	GetSpendingPolicy(userAddress core.AddressHandler) (*requests.SpendingPolicyResponse, error)
+	WebAuthnChallenge(userAddress core.AddressHandler, userIp string, request requests.WebAuthnChallenge) (*requests.WebAuthnChallengeResponse, *requests.OTPCodeVerifyData, error)
+	RegisterWebAuthn(userAddress core.AddressHandler, userIp string, request requests.RegisterWebAuthn) (*requests.OTPCodeVerifyData, error)
+	RegenerateRecoveryCodes(userAddress core.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error)
+	DeregisterUser(userAddress core.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error)
 	RegisteredUsers() (uint32, error)
 	TcsConfig() *tcsCore.TcsConfig
+	GetUserState(userAddress core.AddressHandler) (*requests.UserStateResponse, error)
+	ForceUnsetSecurityMode(userAddress core.AddressHandler) error
+	ResetRateLimiter(userAddress core.AddressHandler, userIp string) error
+	MarkGuardianNotUsable(userAddress core.AddressHandler, guardian string) error
+	ReEncryptUser(userAddress core.AddressHandler) error
+	StartReEncryption() error
+	GetReEncryptionStatus() *requests.ReEncryptionStatusResponse
+	ExportUserData(userAddress core.AddressHandler) (*requests.UserAuditDataResponse, error)
+	GetHistory(userAddress core.AddressHandler, offset uint32, limit uint32) (*requests.UserHistoryResponse, error)
+	GetGuardianStatus(userAddress core.AddressHandler, userIp string) (*requests.GuardianStatusResponse, error)
+	PreviewTransactions(request requests.SignMultipleTransactions) (*requests.PreviewTransactionsResponse, error)
 	GetMetrics() map[string]*requests.EndpointMetricsResponse
 	GetMetricsForPrometheus() string
+	RecordAuditEntry(entry tcsCore.AuditEntry, txs []transaction.FrontendTransaction)
 	IsInterfaceNil() bool
 }
 
@@ -0,0 +1,470 @@
+package chainSimulator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/data/api"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	sdkData "github.com/multiversx/mx-sdk-go/data"
+)
+
+var log = logger.GetOrCreate("chainSimulator")
+
+const (
+	addressPathPrefix   = "/address/"
+	guardianDataSuffix  = "/guardian-data"
+	blocksPathPrefix    = "/blocks/"
+	hyperBlockByHash    = "/hyperblock/by-hash/"
+	controlPathPrefix   = "/simulator"
+	balanceSuffix       = "/balance"
+	guardianSuffix      = "/guardian"
+	guardedSuffix       = "/guarded"
+	epochsPath          = controlPathPrefix + "/epochs"
+	successfulCode      = "successful"
+	badRequestCode      = "bad_request"
+	internalErrorCode   = "internal_issue"
+	shutdownTimeout     = time.Second
+	readHeaderTimeout   = 10 * time.Second
+	maxControlBodyBytes = 4096
+)
+
+// ArgsChainSimulator is the DTO used to create a new instance of the chain simulator
+type ArgsChainSimulator struct {
+	// DefaultBalance is the balance of the accounts without an explicitly set one
+	DefaultBalance string
+	// EpochDuration is the time after which the epoch is incremented, zero meaning that the epochs only change
+	// through AdvanceEpochs
+	EpochDuration time.Duration
+	// GuardianActivationEpochs is the number of epochs a pending guardian waits until it becomes active
+	GuardianActivationEpochs uint32
+}
+
+type accountState struct {
+	balance         string
+	nonce           uint64
+	activeGuardian  *api.Guardian
+	pendingGuardian *api.Guardian
+	guarded         bool
+}
+
+// chainSimulator is an in-process replacement of the MultiversX API, serving the accounts, the guardian data
+// and the blocks needed by the service, for local development and offline integration tests
+type chainSimulator struct {
+	mut                      sync.Mutex
+	accounts                 map[string]*accountState
+	defaultBalance           string
+	epochDuration            time.Duration
+	guardianActivationEpochs uint32
+	startTime                time.Time
+	advancedEpochs           uint32
+	server                   *http.Server
+}
+
+// NewChainSimulator returns a new instance of the chain simulator
+func NewChainSimulator(args ArgsChainSimulator) (*chainSimulator, error) {
+	if !isValidBalance(args.DefaultBalance) {
+		return nil, fmt.Errorf("%w for the default balance: %s", ErrInvalidBalance, args.DefaultBalance)
+	}
+
+	return &chainSimulator{
+		accounts:                 make(map[string]*accountState),
+		defaultBalance:           args.DefaultBalance,
+		epochDuration:            args.EpochDuration,
+		guardianActivationEpochs: args.GuardianActivationEpochs,
+		startTime:                time.Now(),
+	}, nil
+}
+
+// SetBalance sets the balance of the provided account
+func (cs *chainSimulator) SetBalance(address string, balance string) error {
+	if !isValidBalance(balance) {
+		return fmt.Errorf("%w: %s", ErrInvalidBalance, balance)
+	}
+
+	cs.mut.Lock()
+	defer cs.mut.Unlock()
+
+	account, err := cs.getAccountForUpdate(address)
+	if err != nil {
+		return err
+	}
+
+	account.balance = balance
+
+	return nil
+}
+
+// SetGuardian behaves as a SetGuardian transaction not co-signed by an active guardian: the provided guardian
+// replaces the pending one and becomes active after the configured number of epochs
+func (cs *chainSimulator) SetGuardian(address string, guardian string, serviceUID string) error {
+	err := checkAddress(guardian)
+	if err != nil {
+		return err
+	}
+
+	cs.mut.Lock()
+	defer cs.mut.Unlock()
+
+	account, err := cs.getAccountForUpdate(address)
+	if err != nil {
+		return err
+	}
+
+	account.pendingGuardian = &api.Guardian{
+		Address:         guardian,
+		ActivationEpoch: cs.currentEpoch() + cs.guardianActivationEpochs,
+		ServiceUID:      serviceUID,
+	}
+
+	return nil
+}
+
+// SetActiveGuardian behaves as a SetGuardian transaction co-signed by the active guardian: the provided guardian
+// becomes active in the current epoch, replacing both the active and the pending ones
+func (cs *chainSimulator) SetActiveGuardian(address string, guardian string, serviceUID string) error {
+	err := checkAddress(guardian)
+	if err != nil {
+		return err
+	}
+
+	cs.mut.Lock()
+	defer cs.mut.Unlock()
+
+	account, err := cs.getAccountForUpdate(address)
+	if err != nil {
+		return err
+	}
+
+	account.activeGuardian = &api.Guardian{
+		Address:         guardian,
+		ActivationEpoch: cs.currentEpoch(),
+		ServiceUID:      serviceUID,
+	}
+	account.pendingGuardian = nil
+
+	return nil
+}
+
+// SetGuarded behaves as a GuardAccount, respectively an UnGuardAccount transaction
+func (cs *chainSimulator) SetGuarded(address string, guarded bool) error {
+	cs.mut.Lock()
+	defer cs.mut.Unlock()
+
+	account, err := cs.getAccountForUpdate(address)
+	if err != nil {
+		return err
+	}
+	if guarded && account.activeGuardian == nil {
+		return fmt.Errorf("%w: %s", ErrAccountHasNoActiveGuardian, address)
+	}
+
+	account.guarded = guarded
+
+	return nil
+}
+
+// AdvanceEpochs moves the current epoch forward, activating the pending guardians which reached their activation epoch
+func (cs *chainSimulator) AdvanceEpochs(numEpochs uint32) {
+	cs.mut.Lock()
+	defer cs.mut.Unlock()
+
+	cs.advancedEpochs += numEpochs
+}
+
+// CurrentEpoch returns the current epoch
+func (cs *chainSimulator) CurrentEpoch() uint32 {
+	cs.mut.Lock()
+	defer cs.mut.Unlock()
+
+	return cs.currentEpoch()
+}
+
+// GetAccount returns the account of the provided address, as served by the API
+func (cs *chainSimulator) GetAccount(address string) (*sdkData.Account, error) {
+	cs.mut.Lock()
+	defer cs.mut.Unlock()
+
+	account, err := cs.getAccount(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdkData.Account{
+		Address: address,
+		Nonce:   account.nonce,
+		Balance: account.balance,
+	}, nil
+}
+
+// GetGuardianData returns the guardian data of the provided address, as served by the API
+func (cs *chainSimulator) GetGuardianData(address string) (*api.GuardianData, error) {
+	cs.mut.Lock()
+	defer cs.mut.Unlock()
+
+	account, err := cs.getAccount(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.GuardianData{
+		ActiveGuardian:  copyGuardian(account.activeGuardian),
+		PendingGuardian: copyGuardian(account.pendingGuardian),
+		Guarded:         account.guarded,
+	}, nil
+}
+
+// must be called under mutex protection
+func (cs *chainSimulator) currentEpoch() uint32 {
+	epoch := cs.advancedEpochs
+	if cs.epochDuration > 0 {
+		epoch += uint32(time.Since(cs.startTime) / cs.epochDuration)
+	}
+
+	return epoch
+}
+
+// getAccount returns the state of the account, with the default balance if it was never changed, after activating
+// its pending guardian if the activation epoch was reached. Must be called under mutex protection
+func (cs *chainSimulator) getAccount(address string) (*accountState, error) {
+	err := checkAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	account, found := cs.accounts[address]
+	if !found {
+		return &accountState{
+			balance: cs.defaultBalance,
+		}, nil
+	}
+
+	if account.pendingGuardian != nil && account.pendingGuardian.ActivationEpoch <= cs.currentEpoch() {
+		account.activeGuardian = account.pendingGuardian
+		account.pendingGuardian = nil
+	}
+
+	return account, nil
+}
+
+// getAccountForUpdate returns the state of the account, adding it to the simulated accounts if needed.
+// Must be called under mutex protection
+func (cs *chainSimulator) getAccountForUpdate(address string) (*accountState, error) {
+	account, err := cs.getAccount(address)
+	if err != nil {
+		return nil, err
+	}
+
+	cs.accounts[address] = account
+
+	return account, nil
+}
+
+func checkAddress(address string) error {
+	_, err := sdkData.NewAddressFromBech32String(address)
+	return err
+}
+
+func isValidBalance(balance string) bool {
+	value, ok := big.NewInt(0).SetString(balance, 10)
+	return ok && value.Sign() >= 0
+}
+
+func copyGuardian(guardian *api.Guardian) *api.Guardian {
+	if guardian == nil {
+		return nil
+	}
+
+	guardianCopy := *guardian
+	return &guardianCopy
+}
+
+// ServeHTTP serves the address/<address>, address/<address>/guardian-data and blocks/<hash> endpoints of the API,
+// the hyperblock/by-hash/<hash> endpoint of the gateway, together with the simulator/... endpoints changing the
+// state of the simulated chain
+func (cs *chainSimulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(path, addressPathPrefix):
+		cs.serveAddress(w, strings.TrimPrefix(path, addressPathPrefix))
+	case r.Method == http.MethodGet && strings.HasPrefix(path, blocksPathPrefix):
+		writeJSON(w, http.StatusOK, sdkData.Block{
+			Timestamp: int(time.Now().Unix()),
+		})
+	case r.Method == http.MethodGet && strings.HasPrefix(path, hyperBlockByHash):
+		writeResponse(w, http.StatusOK, map[string]interface{}{"hyperblock": sdkData.HyperBlock{
+			Hash:      strings.TrimPrefix(path, hyperBlockByHash),
+			Timestamp: uint64(time.Now().Unix()),
+		}}, "", successfulCode)
+	case r.Method == http.MethodPost && strings.HasPrefix(path, controlPathPrefix+addressPathPrefix):
+		cs.serveControlAddress(w, r, strings.TrimPrefix(path, controlPathPrefix+addressPathPrefix))
+	case r.Method == http.MethodPost && path == epochsPath:
+		cs.serveAdvanceEpochs(w, r)
+	default:
+		writeResponse(w, http.StatusNotFound, nil, fmt.Sprintf("unknown route %s %s", r.Method, path), badRequestCode)
+	}
+}
+
+func (cs *chainSimulator) serveAddress(w http.ResponseWriter, addressPath string) {
+	if strings.HasSuffix(addressPath, guardianDataSuffix) {
+		guardianData, err := cs.GetGuardianData(strings.TrimSuffix(addressPath, guardianDataSuffix))
+		if err != nil {
+			writeResponse(w, http.StatusBadRequest, nil, err.Error(), badRequestCode)
+			return
+		}
+
+		writeResponse(w, http.StatusOK, map[string]interface{}{"guardianData": guardianData}, "", successfulCode)
+		return
+	}
+
+	account, err := cs.GetAccount(addressPath)
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, nil, err.Error(), badRequestCode)
+		return
+	}
+
+	writeResponse(w, http.StatusOK, map[string]interface{}{"account": account}, "", successfulCode)
+}
+
+type controlRequest struct {
+	Balance    string `json:"balance"`
+	Guardian   string `json:"guardian"`
+	ServiceUID string `json:"serviceUID"`
+	Active     bool   `json:"active"`
+	Guarded    bool   `json:"guarded"`
+	NumEpochs  uint32 `json:"numEpochs"`
+}
+
+// serveControlAddress handles:
+//   - simulator/address/<address>/balance with {"balance"}
+//   - simulator/address/<address>/guardian with {"guardian", "serviceUID", "active"}, the guardian being pending unless active is set
+//   - simulator/address/<address>/guarded with {"guarded"}
+func (cs *chainSimulator) serveControlAddress(w http.ResponseWriter, r *http.Request, addressPath string) {
+	request, ok := readControlRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var err error
+	switch {
+	case strings.HasSuffix(addressPath, balanceSuffix):
+		err = cs.SetBalance(strings.TrimSuffix(addressPath, balanceSuffix), request.Balance)
+	case strings.HasSuffix(addressPath, guardianSuffix) && request.Active:
+		err = cs.SetActiveGuardian(strings.TrimSuffix(addressPath, guardianSuffix), request.Guardian, request.ServiceUID)
+	case strings.HasSuffix(addressPath, guardianSuffix):
+		err = cs.SetGuardian(strings.TrimSuffix(addressPath, guardianSuffix), request.Guardian, request.ServiceUID)
+	case strings.HasSuffix(addressPath, guardedSuffix):
+		err = cs.SetGuarded(strings.TrimSuffix(addressPath, guardedSuffix), request.Guarded)
+	default:
+		writeResponse(w, http.StatusNotFound, nil, fmt.Sprintf("unknown route %s", r.URL.Path), badRequestCode)
+		return
+	}
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, nil, err.Error(), badRequestCode)
+		return
+	}
+
+	writeResponse(w, http.StatusOK, nil, "", successfulCode)
+}
+
+// serveAdvanceEpochs handles simulator/epochs with {"numEpochs"}
+func (cs *chainSimulator) serveAdvanceEpochs(w http.ResponseWriter, r *http.Request) {
+	request, ok := readControlRequest(w, r)
+	if !ok {
+		return
+	}
+
+	cs.AdvanceEpochs(request.NumEpochs)
+	writeResponse(w, http.StatusOK, map[string]interface{}{"epoch": cs.CurrentEpoch()}, "", successfulCode)
+}
+
+func readControlRequest(w http.ResponseWriter, r *http.Request) (*controlRequest, bool) {
+	request := &controlRequest{}
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxControlBodyBytes)).Decode(request)
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, nil, err.Error(), badRequestCode)
+		return nil, false
+	}
+
+	return request, true
+}
+
+func writeResponse(w http.ResponseWriter, status int, data interface{}, errMessage string, code string) {
+	writeJSON(w, status, map[string]interface{}{
+		"data":  data,
+		"error": errMessage,
+		"code":  code,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, response interface{}) {
+	buff, err := json.Marshal(response)
+	if err != nil {
+		status = http.StatusInternalServerError
+		buff = []byte(fmt.Sprintf(`{"data":null,"error":"%s","code":"%s"}`, err.Error(), internalErrorCode))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, err = w.Write(buff)
+	if err != nil {
+		log.Debug("could not write the response", "error", err)
+	}
+}
+
+// Start starts serving on the provided interface, returning the URL to be used as API network address.
+// A zero port selects a free one
+func (cs *chainSimulator) Start(listenAddress string) (string, error) {
+	cs.mut.Lock()
+	defer cs.mut.Unlock()
+
+	if cs.server != nil {
+		return "", ErrSimulatorAlreadyStarted
+	}
+
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return "", err
+	}
+
+	cs.server = &http.Server{
+		Handler:           cs,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	go func() {
+		errServe := cs.server.Serve(listener)
+		if errServe != http.ErrServerClosed {
+			log.Error("chain simulator stopped", "error", errServe)
+		}
+	}()
+
+	return "http://" + listener.Addr().String(), nil
+}
+
+// Close stops serving, if started
+func (cs *chainSimulator) Close() error {
+	cs.mut.Lock()
+	server := cs.server
+	cs.mut.Unlock()
+
+	if server == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	return server.Shutdown(ctx)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (cs *chainSimulator) IsInterfaceNil() bool {
+	return cs == nil
+}
@@ -0,0 +1,53 @@
+package chainSimulator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+)
+
+// CreateChainSimulator will create a new chain simulator, holding the configured accounts
+func CreateChainSimulator(cfg config.ChainSimulatorConfig) (*chainSimulator, error) {
+	args := ArgsChainSimulator{
+		DefaultBalance:           cfg.DefaultBalance,
+		EpochDuration:            time.Duration(cfg.EpochDurationInSec) * time.Second,
+		GuardianActivationEpochs: cfg.GuardianActivationEpochs,
+	}
+	simulator, err := NewChainSimulator(args)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, account := range cfg.Accounts {
+		err = simulator.setAccount(account)
+		if err != nil {
+			return nil, fmt.Errorf("%w for the simulated account %s", err, account.Address)
+		}
+	}
+
+	return simulator, nil
+}
+
+func (cs *chainSimulator) setAccount(account config.SimulatedAccountConfig) error {
+	if len(account.Balance) > 0 {
+		err := cs.SetBalance(account.Address, account.Balance)
+		if err != nil {
+			return err
+		}
+	}
+	if len(account.ActiveGuardian) > 0 {
+		err := cs.SetActiveGuardian(account.Address, account.ActiveGuardian, account.ServiceUID)
+		if err != nil {
+			return err
+		}
+	}
+	if len(account.PendingGuardian) > 0 {
+		err := cs.SetGuardian(account.Address, account.PendingGuardian, account.ServiceUID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return cs.SetGuarded(account.Address, account.Guarded)
+}
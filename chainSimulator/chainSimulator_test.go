@@ -0,0 +1,309 @@
+package chainSimulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/data/api"
+	sdkHttp "github.com/multiversx/mx-sdk-go/core/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+)
+
+const (
+	userAddress     = "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th"
+	guardianAddress = "erd1k2s324ww2g0yj38qn2ch2jwctdy8mnfxep94q9arncc6xecg3xaq6mjse8"
+	otherGuardian   = "erd1r69gk66fmedhhcg24g2c5kn2f2a5k4kvpr6jfw67dn2lyydd8cfswy6ede"
+)
+
+func createMockArgs() ArgsChainSimulator {
+	return ArgsChainSimulator{
+		DefaultBalance:           "1000",
+		GuardianActivationEpochs: 2,
+	}
+}
+
+func TestNewChainSimulator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid default balance should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.DefaultBalance = "-1"
+		cs, err := NewChainSimulator(args)
+		assert.True(t, errors.Is(err, ErrInvalidBalance))
+		assert.Nil(t, cs)
+
+		args.DefaultBalance = "not a number"
+		cs, err = NewChainSimulator(args)
+		assert.True(t, errors.Is(err, ErrInvalidBalance))
+		assert.Nil(t, cs)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		cs, err := NewChainSimulator(createMockArgs())
+		assert.Nil(t, err)
+		assert.False(t, cs.IsInterfaceNil())
+	})
+}
+
+func TestChainSimulator_Accounts(t *testing.T) {
+	t.Parallel()
+
+	cs, err := NewChainSimulator(createMockArgs())
+	require.Nil(t, err)
+
+	_, err = cs.GetAccount("invalid address")
+	assert.NotNil(t, err)
+	assert.NotNil(t, cs.SetBalance("invalid address", "10"))
+	assert.True(t, errors.Is(cs.SetBalance(userAddress, "-10"), ErrInvalidBalance))
+
+	account, err := cs.GetAccount(userAddress)
+	require.Nil(t, err)
+	assert.Equal(t, userAddress, account.Address)
+	assert.Equal(t, "1000", account.Balance)
+
+	require.Nil(t, cs.SetBalance(userAddress, "0"))
+	account, err = cs.GetAccount(userAddress)
+	require.Nil(t, err)
+	assert.Equal(t, "0", account.Balance)
+}
+
+func TestChainSimulator_Guardians(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid guardian should error", func(t *testing.T) {
+		t.Parallel()
+
+		cs, _ := NewChainSimulator(createMockArgs())
+		assert.NotNil(t, cs.SetGuardian(userAddress, "invalid guardian", ""))
+		assert.NotNil(t, cs.SetActiveGuardian(userAddress, "invalid guardian", ""))
+	})
+	t.Run("account without active guardian cannot be guarded", func(t *testing.T) {
+		t.Parallel()
+
+		cs, _ := NewChainSimulator(createMockArgs())
+		assert.True(t, errors.Is(cs.SetGuarded(userAddress, true), ErrAccountHasNoActiveGuardian))
+		assert.Nil(t, cs.SetGuarded(userAddress, false))
+
+		require.Nil(t, cs.SetGuardian(userAddress, guardianAddress, "uid"))
+		assert.True(t, errors.Is(cs.SetGuarded(userAddress, true), ErrAccountHasNoActiveGuardian))
+	})
+	t.Run("pending guardian becomes active after the activation epochs", func(t *testing.T) {
+		t.Parallel()
+
+		cs, _ := NewChainSimulator(createMockArgs())
+		guardianData, err := cs.GetGuardianData(userAddress)
+		require.Nil(t, err)
+		assert.Equal(t, &api.GuardianData{}, guardianData)
+
+		require.Nil(t, cs.SetGuardian(userAddress, guardianAddress, "uid"))
+		guardianData, _ = cs.GetGuardianData(userAddress)
+		assert.Nil(t, guardianData.ActiveGuardian)
+		assert.Equal(t, &api.Guardian{Address: guardianAddress, ActivationEpoch: 2, ServiceUID: "uid"}, guardianData.PendingGuardian)
+
+		cs.AdvanceEpochs(1)
+		guardianData, _ = cs.GetGuardianData(userAddress)
+		assert.Nil(t, guardianData.ActiveGuardian)
+		assert.NotNil(t, guardianData.PendingGuardian)
+
+		cs.AdvanceEpochs(1)
+		assert.Equal(t, uint32(2), cs.CurrentEpoch())
+		guardianData, _ = cs.GetGuardianData(userAddress)
+		assert.Equal(t, &api.Guardian{Address: guardianAddress, ActivationEpoch: 2, ServiceUID: "uid"}, guardianData.ActiveGuardian)
+		assert.Nil(t, guardianData.PendingGuardian)
+		assert.False(t, guardianData.Guarded)
+
+		require.Nil(t, cs.SetGuarded(userAddress, true))
+		require.Nil(t, cs.SetGuardian(userAddress, otherGuardian, "uid"))
+		guardianData, _ = cs.GetGuardianData(userAddress)
+		assert.Equal(t, guardianAddress, guardianData.ActiveGuardian.Address)
+		assert.Equal(t, &api.Guardian{Address: otherGuardian, ActivationEpoch: 4, ServiceUID: "uid"}, guardianData.PendingGuardian)
+		assert.True(t, guardianData.Guarded)
+
+		// the returned data is a copy
+		guardianData.ActiveGuardian.Address = otherGuardian
+		guardianData, _ = cs.GetGuardianData(userAddress)
+		assert.Equal(t, guardianAddress, guardianData.ActiveGuardian.Address)
+	})
+	t.Run("active guardian replaces both guardians", func(t *testing.T) {
+		t.Parallel()
+
+		cs, _ := NewChainSimulator(createMockArgs())
+		cs.AdvanceEpochs(5)
+		require.Nil(t, cs.SetGuardian(userAddress, guardianAddress, "uid"))
+		require.Nil(t, cs.SetActiveGuardian(userAddress, otherGuardian, "other uid"))
+
+		guardianData, _ := cs.GetGuardianData(userAddress)
+		assert.Equal(t, &api.Guardian{Address: otherGuardian, ActivationEpoch: 5, ServiceUID: "other uid"}, guardianData.ActiveGuardian)
+		assert.Nil(t, guardianData.PendingGuardian)
+	})
+	t.Run("epochs advance with time", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.EpochDuration = 50 * time.Millisecond
+		cs, _ := NewChainSimulator(args)
+		require.Nil(t, cs.SetGuardian(userAddress, guardianAddress, "uid"))
+
+		assert.Eventually(t, func() bool {
+			guardianData, _ := cs.GetGuardianData(userAddress)
+			return guardianData.ActiveGuardian != nil
+		}, time.Second, 10*time.Millisecond)
+		assert.True(t, cs.CurrentEpoch() >= 2)
+	})
+}
+
+func TestChainSimulator_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	cs, _ := NewChainSimulator(createMockArgs())
+
+	t.Run("unknown routes", func(t *testing.T) {
+		for _, route := range []string{"GET /network/config", "POST /address/" + userAddress, "POST /simulator/address/" + userAddress + "/nonce"} {
+			parts := strings.Split(route, " ")
+			resp := serve(cs, parts[0], parts[1], "{}")
+			assert.Equal(t, http.StatusNotFound, resp.Code, route)
+			assert.Contains(t, resp.Body.String(), `"code":"bad_request"`)
+		}
+	})
+	t.Run("invalid requests", func(t *testing.T) {
+		resp := serve(cs, http.MethodGet, "/address/invalid", "")
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+		resp = serve(cs, http.MethodPost, "/simulator/epochs", "not json")
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+		resp = serve(cs, http.MethodPost, "/simulator/address/"+userAddress+"/guarded", `{"guarded":true}`)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), ErrAccountHasNoActiveGuardian.Error())
+	})
+	t.Run("control endpoints", func(t *testing.T) {
+		resp := serve(cs, http.MethodPost, "/simulator/address/"+userAddress+"/balance", `{"balance":"5"}`)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		resp = serve(cs, http.MethodPost, "/simulator/address/"+userAddress+"/guardian", fmt.Sprintf(`{"guardian":"%s","serviceUID":"uid","active":true}`, guardianAddress))
+		assert.Equal(t, http.StatusOK, resp.Code)
+		resp = serve(cs, http.MethodPost, "/simulator/address/"+userAddress+"/guardian", fmt.Sprintf(`{"guardian":"%s","serviceUID":"uid"}`, otherGuardian))
+		assert.Equal(t, http.StatusOK, resp.Code)
+		resp = serve(cs, http.MethodPost, "/simulator/address/"+userAddress+"/guarded", `{"guarded":true}`)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		resp = serve(cs, http.MethodPost, "/simulator/epochs", `{"numEpochs":1}`)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `{"data":{"epoch":1},"error":"","code":"successful"}`, resp.Body.String())
+
+		account, _ := cs.GetAccount(userAddress)
+		assert.Equal(t, "5", account.Balance)
+		guardianData, _ := cs.GetGuardianData(userAddress)
+		assert.Equal(t, guardianAddress, guardianData.ActiveGuardian.Address)
+		assert.Equal(t, otherGuardian, guardianData.PendingGuardian.Address)
+		assert.True(t, guardianData.Guarded)
+	})
+}
+
+func serve(cs *chainSimulator, method string, path string, body string) *httptest.ResponseRecorder {
+	resp := httptest.NewRecorder()
+	cs.ServeHTTP(resp, httptest.NewRequest(method, path, strings.NewReader(body)))
+
+	return resp
+}
+
+func TestChainSimulator_StartShouldServeTheApi(t *testing.T) {
+	t.Parallel()
+
+	cs, _ := NewChainSimulator(createMockArgs())
+	require.Nil(t, cs.SetActiveGuardian(userAddress, guardianAddress, "uid"))
+	require.Nil(t, cs.SetGuardian(userAddress, otherGuardian, "uid"))
+
+	networkAddress, err := cs.Start("localhost:0")
+	require.Nil(t, err)
+	defer func() {
+		assert.Nil(t, cs.Close())
+	}()
+
+	_, err = cs.Start("localhost:0")
+	assert.Equal(t, ErrSimulatorAlreadyStarted, err)
+
+	httpClientWrapper, err := core.NewHttpClientWrapper(sdkHttp.NewHttpClientWrapper(nil, networkAddress))
+	require.Nil(t, err)
+
+	account, err := httpClientWrapper.GetAccount(context.Background(), userAddress)
+	require.Nil(t, err)
+	assert.Equal(t, "1000", account.Balance)
+
+	guardianData, err := httpClientWrapper.GetGuardianData(context.Background(), userAddress)
+	require.Nil(t, err)
+	assert.Equal(t, guardianAddress, guardianData.ActiveGuardian.Address)
+	assert.Equal(t, otherGuardian, guardianData.PendingGuardian.Address)
+
+	resp, err := http.Get(networkAddress + "/blocks/hash")
+	require.Nil(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), `"timestamp":`)
+
+	gatewayResp, err := http.Get(networkAddress + "/hyperblock/by-hash/hash")
+	require.Nil(t, err)
+	defer func() {
+		_ = gatewayResp.Body.Close()
+	}()
+	body, _ = io.ReadAll(gatewayResp.Body)
+	assert.Contains(t, string(body), `"hyperblock":{`)
+	assert.Contains(t, string(body), `"hash":"hash"`)
+}
+
+func TestCreateChainSimulator(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.ChainSimulatorConfig{
+		DefaultBalance:           "1000",
+		GuardianActivationEpochs: 20,
+		Accounts: []config.SimulatedAccountConfig{
+			{
+				Address:         userAddress,
+				Balance:         "0",
+				ActiveGuardian:  guardianAddress,
+				PendingGuardian: otherGuardian,
+				ServiceUID:      "uid",
+				Guarded:         true,
+			},
+		},
+	}
+
+	t.Run("invalid account should error", func(t *testing.T) {
+		t.Parallel()
+
+		invalidCfg := cfg
+		invalidCfg.Accounts = []config.SimulatedAccountConfig{{Address: userAddress, Guarded: true}}
+		cs, err := CreateChainSimulator(invalidCfg)
+		assert.True(t, errors.Is(err, ErrAccountHasNoActiveGuardian))
+		assert.Contains(t, err.Error(), userAddress)
+		assert.Nil(t, cs)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		cs, err := CreateChainSimulator(cfg)
+		require.Nil(t, err)
+
+		account, _ := cs.GetAccount(userAddress)
+		assert.Equal(t, "0", account.Balance)
+		guardianData, _ := cs.GetGuardianData(userAddress)
+		assert.Equal(t, &api.Guardian{Address: guardianAddress, ServiceUID: "uid"}, guardianData.ActiveGuardian)
+		assert.Equal(t, &api.Guardian{Address: otherGuardian, ActivationEpoch: 20, ServiceUID: "uid"}, guardianData.PendingGuardian)
+		assert.True(t, guardianData.Guarded)
+	})
+}
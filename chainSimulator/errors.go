@@ -0,0 +1,12 @@
+package chainSimulator
+
+import "errors"
+
+// ErrInvalidBalance signals that an invalid balance has been provided
+var ErrInvalidBalance = errors.New("invalid balance")
+
+// ErrAccountHasNoActiveGuardian signals that an account without an active guardian cannot be guarded
+var ErrAccountHasNoActiveGuardian = errors.New("account has no active guardian")
+
+// ErrSimulatorAlreadyStarted signals that the simulator has already been started
+var ErrSimulatorAlreadyStarted = errors.New("chain simulator already started")
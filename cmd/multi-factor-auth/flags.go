@@ -89,6 +89,12 @@ var (
 		Name:  "start-swagger-ui",
 		Usage: "If set to true, will start a Swagger UI on the root",
 	}
+	// simulateChain defines a flag that specifies if the chain simulator should be used instead of the API
+	simulateChain = cli.BoolFlag{
+		Name: "simulate-chain",
+		Usage: "If set to true, the accounts and the guardian data are served by an in-process chain simulator, " +
+			"set in the ChainSimulator section of the external configuration file, instead of the API. For local testing only",
+	}
 )
 
 func getFlags() []cli.Flag {
@@ -104,6 +110,7 @@ func getFlags() []cli.Flag {
 		profileMode,
 		restApiInterface,
 		startSwaggerUI,
+		simulateChain,
 	}
 }
 func getFlagsConfig(ctx *cli.Context) config.ContextFlagsConfig {
@@ -120,6 +127,7 @@ func getFlagsConfig(ctx *cli.Context) config.ContextFlagsConfig {
 	flagsConfig.EnablePprof = ctx.GlobalBool(profileMode.Name)
 	flagsConfig.RestApiInterface = ctx.GlobalString(restApiInterface.Name)
 	flagsConfig.StartSwaggerUI = ctx.GlobalBool(startSwaggerUI.Name)
+	flagsConfig.SimulateChain = ctx.GlobalBool(simulateChain.Name)
 
 	return flagsConfig
 }
@@ -82,7 +82,8 @@ type _ struct {
 
 // swagger:route POST /verify-code Guardian verifyCodeRequest
 // Verify code.
-// Verifies the provided code against the user and guardian
+// Verifies the provided code against the user and guardian.
+// The first successful verification also returns the recovery codes of the user
 //
 // security:
 // - bearer:
@@ -96,9 +97,9 @@ type _ struct {
 type _ struct {
 	// in:body
 	Body struct {
-		// Empty data field
+		// RecoveryCodesResponse, only set when new recovery codes were generated
 		// x-nullable:true
-		Data string `json:"data"`
+		Data requests.RecoveryCodesResponse `json:"data"`
 		// HTTP status code
 		Code string `json:"code"`
 		// Internal error
@@ -289,3 +290,286 @@ type _ struct {
 	// required:true
 	Payload requests.SecurityModeNoExpire
 }
+
+// swagger:route POST /set-spending-policy Guardian setSpendingPolicyRequest
+// Set spending policy.
+// Sets the daily and weekly spending limits and the trusted receivers of the user.
+// A policy which is not stricter than the active one only becomes active after the configured delay
+//
+// security:
+// - bearer:
+// responses:
+// 200: setSpendingPolicyResponse
+
+// The status of the operation
+// swagger:response setSpendingPolicyResponse
+type _ struct {
+	// in:body
+	Body struct {
+		// Empty data field
+		// x-nullable:true
+		Data string `json:"data"`
+		// HTTP status code
+		Code string `json:"code"`
+		// Internal error
+		Error string `json:"error"`
+	}
+}
+
+// swagger:parameters setSpendingPolicyRequest
+type _ struct {
+	// SetSpendingPolicy payload
+	// in:body
+	// required:true
+	Payload requests.SetSpendingPolicy
+}
+
+// swagger:route GET /spending-policy Guardian spendingPolicy
+// Returns the spending policy.
+// Returns the active and the pending spending policies of the user, along with the amounts spent in the current day and week
+//
+// security:
+// - bearer:
+// responses:
+// 200: spendingPolicyResponse
+
+// The spending policy of the user
+// swagger:response spendingPolicyResponse
+type _ struct {
+	// in:body
+	Body struct {
+		// SpendingPolicyResponse
+		Data requests.SpendingPolicyResponse `json:"data"`
+		// HTTP status code
+		Code string `json:"code"`
+		// Internal error
+		Error string `json:"error"`
+	}
+}
+
+// swagger:route GET /history Guardian historyRequest
+// Signing history.
+// Returns the co-signed transactions and messages of the user, along with the failed attempts and their ips, newest first
+//
+// security:
+// - bearer:
+// responses:
+// 200: historyResponse
+
+// The signing history of the user
+// swagger:response historyResponse
+type _ struct {
+	// in:body
+	Body struct {
+		// UserHistoryResponse
+		Data requests.UserHistoryResponse `json:"data"`
+		// HTTP status code
+		Code string `json:"code"`
+		// Internal error
+		Error string `json:"error"`
+	}
+}
+
+// swagger:parameters historyRequest
+type _ struct {
+	// Number of the newest entries to skip
+	// in:query
+	Offset uint32 `json:"offset"`
+	// Maximum number of entries to return, 20 by default and at most 100
+	// in:query
+	Limit uint32 `json:"limit"`
+}
+
+// swagger:route GET /status Guardian guardianStatus
+// Guardian status.
+// Returns the guardians of the user, whether they can be used for signing and their activation state:
+// Untracked, Verified, PendingOnChain, Active or Replaced. A verified guardian becomes usable once it is active on chain.
+// For each guardian, the last otp change and the seconds left until it can be registered again are also returned,
+// along with the remaining verification trials and the security mode of the user. No verification trial is consumed
+//
+// security:
+// - bearer:
+// responses:
+// 200: guardianStatusResponse
+
+// The status of the user guardians
+// swagger:response guardianStatusResponse
+type _ struct {
+	// in:body
+	Body struct {
+		// GuardianStatusResponse
+		Data requests.GuardianStatusResponse `json:"data"`
+		// HTTP status code
+		Code string `json:"code"`
+		// Internal error
+		Error string `json:"error"`
+	}
+}
+
+// swagger:route POST /preview-transactions Guardian previewTransactionsRequest
+// Preview transactions.
+// Validates the transactions the same way as for signing and returns a human-readable summary of each of them:
+// its type, the final receiver, the decoded function call and token transfers and the guardian set, if any.
+// The guardian which would sign the transactions is also returned. The codes are not needed, no verification trial
+// is consumed and the spending limits are only checked when signing. This request does not need the Authorization header
+//
+// responses:
+// 200: previewTransactionsResponse
+
+// The summary of the transactions and the guardian which would sign them
+// swagger:response previewTransactionsResponse
+type _ struct {
+	// in:body
+	Body struct {
+		// PreviewTransactionsResponse
+		Data requests.PreviewTransactionsResponse `json:"data"`
+		// HTTP status code
+		Code string `json:"code"`
+		// Internal error
+		Error string `json:"error"`
+	}
+}
+
+// swagger:parameters previewTransactionsRequest
+type _ struct {
+	// Preview transactions payload, same as for signing multiple transactions
+	// in:body
+	// required:true
+	Payload requests.SignMultipleTransactions
+}
+
+// swagger:route POST /webauthn-challenge Guardian webAuthnChallengeRequest
+// WebAuthn challenge.
+// Returns a new challenge to be signed by the WebAuthn credential of the guardian, bound to the transactions or
+// to the message of the request, if any. The signed assertion can be sent instead of the first code.
+// No challenge is issued while the verifications of the user are blocked
+//
+// security:
+// - bearer:
+// responses:
+// 400: verifyCodeResponseBadRequest
+// 429: verifyCodeResponseTooManyRequests
+// 200: webAuthnChallengeResponse
+
+// The challenge and the id of the registered credential
+// swagger:response webAuthnChallengeResponse
+type _ struct {
+	// in:body
+	Body struct {
+		// WebAuthnChallengeResponse
+		Data requests.WebAuthnChallengeResponse `json:"data"`
+		// HTTP status code
+		Code string `json:"code"`
+		// Internal error
+		Error string `json:"error"`
+	}
+}
+
+// swagger:parameters webAuthnChallengeRequest
+type _ struct {
+	// WebAuthnChallenge payload
+	// in:body
+	// required:true
+	Payload requests.WebAuthnChallenge
+}
+
+// swagger:route POST /register-webauthn Guardian registerWebAuthnRequest
+// Register WebAuthn credential.
+// Registers the WebAuthn credential created for a challenge, replacing the previous one of the guardian
+//
+// security:
+// - bearer:
+// responses:
+// 400: verifyCodeResponseBadRequest
+// 429: verifyCodeResponseTooManyRequests
+// 200: registerWebAuthnResponse
+
+// The status of the operation
+// swagger:response registerWebAuthnResponse
+type _ struct {
+	// in:body
+	Body struct {
+		// Empty data field
+		// x-nullable:true
+		Data string `json:"data"`
+		// HTTP status code
+		Code string `json:"code"`
+		// Internal error
+		Error string `json:"error"`
+	}
+}
+
+// swagger:parameters registerWebAuthnRequest
+type _ struct {
+	// RegisterWebAuthn payload
+	// in:body
+	// required:true
+	Payload requests.RegisterWebAuthn
+}
+
+// swagger:route POST /recovery-codes/regenerate Guardian regenerateRecoveryCodesRequest
+// Regenerate recovery codes.
+// Verifies the provided code and replaces the recovery codes of the user with a new set
+//
+// security:
+// - bearer:
+// responses:
+// 400: verifyCodeResponseBadRequest
+// 429: verifyCodeResponseTooManyRequests
+// 200: regenerateRecoveryCodesResponse
+
+// The new recovery codes
+// swagger:response regenerateRecoveryCodesResponse
+type _ struct {
+	// in:body
+	Body struct {
+		// RecoveryCodesResponse
+		Data requests.RecoveryCodesResponse `json:"data"`
+		// HTTP status code
+		Code string `json:"code"`
+		// Internal error
+		Error string `json:"error"`
+	}
+}
+
+// swagger:parameters regenerateRecoveryCodesRequest
+type _ struct {
+	// Verify code payload
+	// in:body
+	// required:true
+	Payload requests.VerificationPayload
+}
+
+// swagger:route POST /deregister Guardian deregisterRequest
+// Deregister user.
+// Verifies the provided code and removes the user, if none of its guardians is active or pending on chain
+//
+// security:
+// - bearer:
+// responses:
+// 400: verifyCodeResponseBadRequest
+// 429: verifyCodeResponseTooManyRequests
+// 200: deregisterResponse
+
+// The status of the operation
+// swagger:response deregisterResponse
+type _ struct {
+	// in:body
+	Body struct {
+		// Empty data field
+		// x-nullable:true
+		Data string `json:"data"`
+		// HTTP status code
+		Code string `json:"code"`
+		// Internal error
+		Error string `json:"error"`
+	}
+}
+
+// swagger:parameters deregisterRequest
+type _ struct {
+	// Verify code payload
+	// in:body
+	// required:true
+	Payload requests.VerificationPayload
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/urfave/cli"
+)
+
+var (
+	logLevel = cli.StringFlag{
+		Name: "log-level",
+		Usage: "This flag specifies the logger `level(s)`. It can contain multiple comma-separated value. For example" +
+			", if set to *:INFO the logs for all packages will have the INFO level. However, if set to *:INFO,api:DEBUG" +
+			" the logs for all packages will have the INFO level, excepting the api package which will receive a DEBUG" +
+			" log level.",
+		Value: "*:" + logger.LogInfo.String(),
+	}
+	// sourceConfigurationFile defines a flag for the path to the main toml configuration file of the source storage
+	sourceConfigurationFile = cli.StringFlag{
+		Name: "source-config",
+		Usage: "The `" + filePathPlaceholder + "` for the main configuration file of the service currently holding " +
+			"the users. Its General.DBType, ShardedStorage and SQLite sections describe the source storage.",
+		Value: "config/config.toml",
+	}
+	// sourceConfigurationExternalFile defines a flag for the path to the external toml configuration file of the source storage
+	sourceConfigurationExternalFile = cli.StringFlag{
+		Name: "source-config-external",
+		Usage: "The `" + filePathPlaceholder + "` for the external configuration file of the service currently " +
+			"holding the users. Its MongoDB or Postgres section is used when the source storage is mongoDB or postgres.",
+		Value: "config/external.toml",
+	}
+	// destinationConfigurationFile defines a flag for the path to the main toml configuration file of the destination storage
+	destinationConfigurationFile = cli.StringFlag{
+		Name: "destination-config",
+		Usage: "The `" + filePathPlaceholder + "` for the main configuration file the service will be started with " +
+			"after the migration. Its General.DBType, ShardedStorage and SQLite sections describe the destination storage.",
+	}
+	// destinationConfigurationExternalFile defines a flag for the path to the external toml configuration file of the destination storage
+	destinationConfigurationExternalFile = cli.StringFlag{
+		Name: "destination-config-external",
+		Usage: "The `" + filePathPlaceholder + "` for the external configuration file the service will be started " +
+			"with after the migration. Its MongoDB or Postgres section is used when the destination storage is mongoDB or postgres.",
+	}
+)
+
+func getFlags() []cli.Flag {
+	return []cli.Flag{
+		logLevel,
+		sourceConfigurationFile,
+		sourceConfigurationExternalFile,
+		destinationConfigurationFile,
+		destinationConfigurationExternalFile,
+	}
+}
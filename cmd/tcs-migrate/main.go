@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+
+	chainCore "github.com/multiversx/mx-chain-core-go/core"
+	factoryMarshalizer "github.com/multiversx/mx-chain-core-go/marshal/factory"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	storageFactory "github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage/factory"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage/migration"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/metrics"
+	"github.com/urfave/cli"
+)
+
+const filePathPlaceholder = "[path]"
+
+var log = logger.GetOrCreate("main")
+
+var errMissingDestinationConfig = errors.New("the destination configuration files must be provided")
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "TCS migrate CLI app"
+	app.Usage = "This tool copies all the users of the multi-factor authentication service from one storage to another. " +
+		"It works offline, so the service must be stopped while it runs"
+	app.Flags = getFlags()
+	app.Authors = []cli.Author{
+		{
+			Name:  "The MultiversX Team",
+			Email: "contact@multiversx.com",
+		},
+	}
+
+	app.Action = migrate
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+func migrate(ctx *cli.Context) error {
+	err := logger.SetLogLevel(ctx.GlobalString(logLevel.Name))
+	if err != nil {
+		return err
+	}
+
+	destinationConfigFile := ctx.GlobalString(destinationConfigurationFile.Name)
+	destinationExternalConfigFile := ctx.GlobalString(destinationConfigurationExternalFile.Name)
+	if len(destinationConfigFile) == 0 || len(destinationExternalConfigFile) == 0 {
+		return errMissingDestinationConfig
+	}
+
+	statusMetricsHandler := metrics.NewStatusMetrics()
+	source, err := createStorage(ctx.GlobalString(sourceConfigurationFile.Name), ctx.GlobalString(sourceConfigurationExternalFile.Name), statusMetricsHandler)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		log.LogIfError(source.Close())
+	}()
+
+	destination, err := createStorage(destinationConfigFile, destinationExternalConfigFile, statusMetricsHandler)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		log.LogIfError(destination.Close())
+	}()
+
+	userDataMarshaller, err := factoryMarshalizer.NewMarshalizer(factoryMarshalizer.GogoProtobuf)
+	if err != nil {
+		return err
+	}
+
+	migrator, err := migration.NewStorageMigrator(migration.ArgsStorageMigrator{
+		Source:             source,
+		Destination:        destination,
+		UserDataMarshaller: userDataMarshaller,
+	})
+	if err != nil {
+		return err
+	}
+
+	migrationCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+		<-sigs
+
+		log.Info("migration interrupted, it can be resumed by running the tool again")
+		cancel()
+	}()
+
+	log.Info("starting the migration")
+	result, err := migrator.Migrate(migrationCtx)
+	if err != nil {
+		return err
+	}
+
+	log.Info("migration completed",
+		"reserved indexes up to", result.ReservedIndexesUpTo,
+		"source entries", result.NumSourceEntries,
+		"migrated entries", result.NumMigratedEntries,
+		"already existing entries", result.NumExistingEntries,
+		"verified entries", result.NumVerifiedEntries)
+
+	return nil
+}
+
+func createStorage(configFile string, externalConfigFile string, statusMetricsHandler core.StatusMetricsHandler) (core.StorageWithIndex, error) {
+	cfg := config.Config{}
+	err := chainCore.LoadTomlFile(&cfg, configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	externalCfg := config.ExternalConfig{}
+	err = chainCore.LoadTomlFile(&externalCfg, externalConfigFile)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("opening storage", "config", configFile, "db type", cfg.General.DBType)
+
+	return storageFactory.NewStorageWithIndexFactory(cfg, externalCfg, statusMetricsHandler).Create()
+}
@@ -21,23 +21,40 @@ type Config struct {
 	Antiflood        AntifloodConfig
 	ServiceResolver  ServiceResolverConfig
 	ShardedStorage   ShardedStorageConfig
+	SQLite           SQLDBConfig
 	TwoFactor        TwoFactorConfig
 	NativeAuthServer NativeAuthServerConfig
 	PubKey           PubkeyConfig
+	TxPolicy         TxPolicyConfig
+	WebAuthn         WebAuthnConfig
+	Admin            AdminConfig
+	Audit            AuditConfig
+	Notifier         NotifierConfig
+	KeyProvider      KeyProviderConfig
 }
 
 // ExternalConfig defines the configuration for external components
 type ExternalConfig struct {
-	Api     ApiConfig
-	MongoDB MongoDBConfig
-	Redis   RedisConfig
-	Gin     GinConfig
+	Api            ApiConfig
+	ChainSimulator ChainSimulatorConfig
+	MongoDB        MongoDBConfig
+	Postgres       SQLDBConfig
+	Redis          RedisConfig
+	Gin            GinConfig
 }
 
 // ShardedStorageConfig is the configuration for the sharded storage
 type ShardedStorageConfig struct {
 	NumberOfBuckets uint32
 	Users           StorageConfig
+	Resharding      ShardedStorageReshardingConfig
+}
+
+// ShardedStorageReshardingConfig is the configuration of the previous sharded storage layout, used while resharding
+type ShardedStorageReshardingConfig struct {
+	Enabled                 bool
+	PreviousNumberOfBuckets uint32
+	PreviousUsers           StorageConfig
 }
 
 // StorageConfig will map the storage unit configuration
@@ -59,6 +76,7 @@ type ContextFlagsConfig struct {
 	EnableLogName             bool
 	EnablePprof               bool
 	StartSwaggerUI            bool
+	SimulateChain             bool
 }
 
 // WebServerAntifloodConfig will hold the anti-flooding parameters for the web server
@@ -104,6 +122,8 @@ type RouteConfig struct {
 type GuardianConfig struct {
 	MnemonicFile         string
 	RequestTimeInSeconds int
+	EncryptionKeyID      uint32
+	RejectLegacyRecords  bool
 }
 
 // GeneralConfig holds the general configuration for the service
@@ -114,7 +134,32 @@ type GeneralConfig struct {
 
 // ApiConfig will hold settings related to the Api
 type ApiConfig struct {
-	NetworkAddress string
+	ClientMode                string
+	NetworkAddress            string
+	FallbackNetworkAddresses  []string
+	UnhealthyBackoffInSec     uint64
+	GuardianDataCacheTTLInSec uint64
+	GuardianDataCache         common.CacheConfig
+}
+
+// ChainSimulatorConfig will hold settings related to the chain simulator, used instead of the API when the
+// service is started with the simulate-chain flag
+type ChainSimulatorConfig struct {
+	ListenAddress            string
+	DefaultBalance           string
+	EpochDurationInSec       uint64
+	GuardianActivationEpochs uint32
+	Accounts                 []SimulatedAccountConfig
+}
+
+// SimulatedAccountConfig will hold the initial state of an account served by the chain simulator
+type SimulatedAccountConfig struct {
+	Address         string
+	Balance         string
+	ActiveGuardian  string
+	PendingGuardian string
+	ServiceUID      string
+	Guarded         bool
 }
 
 // LogsConfig will hold settings related to the logging sub-system
@@ -124,29 +169,98 @@ type LogsConfig struct {
 
 // ServiceResolverConfig will hold settings related to the service resolver
 type ServiceResolverConfig struct {
-	RequestTimeInSeconds             uint64
-	SkipTxUserSigVerify              bool
-	MaxTransactionsAllowedForSigning int
-	DelayBetweenOTPWritesInSec       uint64
+	RequestTimeInSeconds               uint64
+	SkipTxUserSigVerify                bool
+	MaxTransactionsAllowedForSigning   int
+	DelayBetweenOTPWritesInSec         uint64
+	SpendingPolicyActivationDelayInSec uint64
+	GuardianActivationCheckInSec       uint64
+	GuardianActivationWindowInSec      uint64
 }
 
 // TwoFactorConfig will hold settings related to the two factor totp
 type TwoFactorConfig struct {
 	Issuer                           string
+	Type                             string
+	Algorithm                        string
 	Digits                           int
+	PeriodInSec                      uint32
+	Skew                             uint32
+	AcceptedDriftSteps               uint32
 	BackoffTimeInSeconds             uint64
 	MaxFailures                      int64
 	SecurityModeMaxFailures          int64
 	SecurityModeBackoffTimeInSeconds uint64
 }
 
+// TxPolicyConfig will hold settings related to the policy applied on transactions before co-signing
+type TxPolicyConfig struct {
+	Enabled                bool
+	MaxTxValue             string
+	ConfirmationTxValue    string
+	MaxDailySpend          string
+	ConfirmationDailySpend string
+	TokenLimits            []TokenTxPolicyConfig
+	AllowedReceivers       []string
+	DeniedReceivers        []string
+	DeniedFunctions        []string
+	ConfirmationFunctions  []string
+}
+
+// TokenTxPolicyConfig will hold the value rules applied on the transfers of an ESDT token
+type TokenTxPolicyConfig struct {
+	Token                  string
+	MaxTxValue             string
+	ConfirmationTxValue    string
+	MaxDailySpend          string
+	ConfirmationDailySpend string
+}
+
+// WebAuthnConfig will hold settings related to the WebAuthn credentials accepted as second factor
+type WebAuthnConfig struct {
+	RPID                    string
+	RPOrigins               []string
+	ChallengeTimeoutInSec   uint64
+	RequireUserVerification bool
+}
+
+// AdminConfig will hold settings related to the admin API package
+type AdminConfig struct {
+	APIKeyFile string
+}
+
+// AuditConfig will hold settings related to the audit log of the service decisions
+type AuditConfig struct {
+	SinkType string
+	FilePath string
+	Storage  StorageConfig
+}
+
 // MongoDBConfig maps the mongodb configuration
 type MongoDBConfig struct {
-	URI                   string
-	DBName                string
-	ConnectTimeoutInSec   uint32
+	URI                    string
+	DBName                 string
+	ConnectTimeoutInSec    uint32
+	OperationTimeoutInSec  uint32
+	UsersCollectionsPrefix string
+	NumUsersCollections    uint32
+	Resharding             MongoDBReshardingConfig
+}
+
+// MongoDBReshardingConfig is the configuration of the previous users collections layout, used while resharding
+type MongoDBReshardingConfig struct {
+	Enabled                        bool
+	PreviousUsersCollectionsPrefix string
+	PreviousNumUsersCollections    uint32
+}
+
+// SQLDBConfig maps the configuration of a sql database
+type SQLDBConfig struct {
+	DSN                   string
 	OperationTimeoutInSec uint32
-	NumUsersCollections   uint32
+	MaxOpenConnections    uint32
+	UsersTablesPrefix     string
+	NumUsersTables        uint32
 }
 
 // NativeAuthServerConfig will hold settings related to the native auth server
@@ -178,3 +292,34 @@ type PubkeyConfig struct {
 	Type   string
 	Hrp    string
 }
+
+// NotifierConfig will hold settings related to the security events emitted outside the service
+type NotifierConfig struct {
+	Type    string
+	Webhook WebhookConfig
+}
+
+// WebhookConfig will hold settings related to the HTTP webhook notifier
+type WebhookConfig struct {
+	URL                 string
+	SecretFile          string
+	MaxRetries          uint32
+	RetryDelayInMs      uint64
+	RequestTimeoutInSec uint64
+	QueueSize           uint32
+}
+
+// KeyProviderConfig will hold settings related to unwrapping the mnemonic read from Guardian.MnemonicFile
+type KeyProviderConfig struct {
+	Type           string
+	PassphraseFile string
+	KMS            KMSConfig
+}
+
+// KMSConfig will hold settings related to the HTTP KMS key provider
+type KMSConfig struct {
+	URL                 string
+	KeyID               string
+	AuthTokenFile       string
+	RequestTimeoutInSec uint64
+}
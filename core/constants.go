@@ -12,11 +12,31 @@ const LevelDB DBType = "levelDB"
 // MongoDB is the mongo db identifier
 const MongoDB DBType = "mongoDB"
 
+// PostgresDB is the PostgreSQL db identifier
+const PostgresDB DBType = "postgres"
+
+// SQLiteDB is the local SQLite db identifier
+const SQLiteDB DBType = "sqlite"
+
+// MemoryDB is the in-memory db identifier, used for local development
+const MemoryDB DBType = "memory"
+
 const (
 	getAccountEndpointFormat      = "address/%s"
 	getGuardianDataEndpointFormat = "address/%s/guardian-data"
 )
 
+// ApiClientMode defines the format of the chain API the service connects to
+type ApiClientMode string
+
+const (
+	// PublicApiClientMode connects to a MultiversX API instance, like api.multiversx.com
+	PublicApiClientMode ApiClientMode = "api"
+
+	// GatewayClientMode connects to a MultiversX proxy (gateway), like the one of an observing squad
+	GatewayClientMode ApiClientMode = "gateway"
+)
+
 // RedisConnType defines the redis connection type
 type RedisConnType string
 
@@ -26,7 +46,115 @@ const (
 
 	// RedisSentinelConnType specifies a redis connection to a setup with sentinel
 	RedisSentinelConnType RedisConnType = "sentinel"
+
+	// RedisMemoryConnType specifies an in-process replacement of redis, used for local development
+	RedisMemoryConnType RedisConnType = "memory"
 )
 
 // NoExpiryValue is the returned value for a persistent key expiry time
 const NoExpiryValue = -1
+
+const (
+	// TOTPType is the type of the otps based on the current time, as defined by RFC 6238
+	TOTPType = "totp"
+
+	// HOTPType is the type of the otps based on a counter incremented on each use, as defined by RFC 4226
+	HOTPType = "hotp"
+)
+
+// AuditSinkType defines the storage type of the audit log
+type AuditSinkType string
+
+const (
+	// LevelDBAuditSink stores the audit log into a local levelDB
+	LevelDBAuditSink AuditSinkType = "levelDB"
+
+	// MongoDBAuditSink stores the audit log into a mongoDB collection
+	MongoDBAuditSink AuditSinkType = "mongoDB"
+
+	// FileAuditSink stores the audit log into a local file, one JSON entry per line
+	FileAuditSink AuditSinkType = "file"
+)
+
+// NotifierType defines the transport used to emit the security events
+type NotifierType string
+
+const (
+	// WebhookNotifier posts the security events to an HTTP endpoint, signed with HMAC-SHA256
+	WebhookNotifier NotifierType = "webhook"
+
+	// RedisNotifier publishes the security events on a redis pub/sub channel
+	RedisNotifier NotifierType = "redis"
+)
+
+// SecurityEventType defines the type of security relevant event
+type SecurityEventType string
+
+const (
+	// SecurityModeActivatedEvent is emitted when the failed attempts of a user activate the security mode
+	SecurityModeActivatedEvent SecurityEventType = "security-mode-activated"
+
+	// UserFrozenEvent is emitted when the failed attempts of a user from an ip freeze the verification
+	UserFrozenEvent SecurityEventType = "user-frozen"
+
+	// GuardianRegisteredEvent is emitted when a new otp is registered for a guardian of the user
+	GuardianRegisteredEvent SecurityEventType = "guardian-registered"
+
+	// UserDeregisteredEvent is emitted when a user removes its registration from the service
+	UserDeregisteredEvent SecurityEventType = "user-deregistered"
+)
+
+// KeyProviderType defines the way the mnemonic of the service is unwrapped
+type KeyProviderType string
+
+const (
+	// FileKeyProvider reads the mnemonic in plain text from a file
+	FileKeyProvider KeyProviderType = "file"
+
+	// KeystoreKeyProvider decrypts the mnemonic from a passphrase encrypted keystore file
+	KeystoreKeyProvider KeyProviderType = "keystore"
+
+	// KMSKeyProvider asks an HTTP KMS to unwrap the mnemonic
+	KMSKeyProvider KeyProviderType = "kms"
+)
+
+// EncryptionVersion defines the scheme used to encrypt the secrets of a user
+type EncryptionVersion uint32
+
+const (
+	// LegacyEncryption encrypts each secret independently, with x25519
+	LegacyEncryption EncryptionVersion = 0
+
+	// AEADEncryption encrypts each secret with XChaCha20-Poly1305, binding it to the user address and field name
+	AEADEncryption EncryptionVersion = 1
+
+	// RecordAEADEncryption encrypts each secret as AEADEncryption, the empty ones included, binding it to the version
+	// as well, and authenticates the whole record with a tag bound to the user address
+	RecordAEADEncryption EncryptionVersion = 2
+)
+
+// TransactionType defines the kind of operation done by a transaction, as reported by the transactions preview
+type TransactionType string
+
+const (
+	// EGLDTransferTransaction only transfers EGLD, the data field being at most a note
+	EGLDTransferTransaction TransactionType = "egld-transfer"
+
+	// ESDTTransferTransaction transfers a fungible token through ESDTTransfer
+	ESDTTransferTransaction TransactionType = "esdt-transfer"
+
+	// NFTTransferTransaction transfers a NFT, SFT or Meta ESDT through ESDTNFTTransfer
+	NFTTransferTransaction TransactionType = "nft-transfer"
+
+	// MultiTokenTransferTransaction transfers multiple tokens through MultiESDTNFTTransfer
+	MultiTokenTransferTransaction TransactionType = "multi-token-transfer"
+
+	// SmartContractCallTransaction calls a function of a smart contract
+	SmartContractCallTransaction TransactionType = "smart-contract-call"
+
+	// BuiltInFunctionCallTransaction calls a built-in function on the account of the sender
+	BuiltInFunctionCallTransaction TransactionType = "built-in-function-call"
+
+	// GuardianOperationTransaction sets a new guardian, guards or unguards the account of the sender
+	GuardianOperationTransaction TransactionType = "guardian-operation"
+)
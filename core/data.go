@@ -19,3 +19,32 @@ const (
 	// MissingGuardian represents a guardian missing from chain
 	MissingGuardian
 )
+
+const (
+	// AuditOutcomeSuccess marks an audit entry of a request accepted by the service
+	AuditOutcomeSuccess = "success"
+	// AuditOutcomeFailure marks an audit entry of a request rejected by the service
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditEntry holds the details of a decision taken by the service, kept for later inspection
+type AuditEntry struct {
+	Timestamp   int64    `json:"timestamp" bson:"timestamp"`
+	Route       string   `json:"route" bson:"route"`
+	UserAddress string   `json:"user-address" bson:"user_address"`
+	Guardian    string   `json:"guardian,omitempty" bson:"guardian,omitempty"`
+	UserIp      string   `json:"ip" bson:"ip"`
+	UserAgent   string   `json:"user-agent" bson:"user_agent"`
+	TxHashes    []string `json:"tx-hashes,omitempty" bson:"tx_hashes,omitempty"`
+	Outcome     string   `json:"outcome" bson:"outcome"`
+	ErrorClass  string   `json:"error-class,omitempty" bson:"error_class,omitempty"`
+}
+
+// SecurityEvent defines a security relevant event emitted outside the service
+type SecurityEvent struct {
+	Type        SecurityEventType `json:"type"`
+	Timestamp   int64             `json:"timestamp"`
+	UserAddress string            `json:"user-address"`
+	UserIp      string            `json:"ip,omitempty"`
+	Guardian    string            `json:"guardian,omitempty"`
+}
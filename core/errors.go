@@ -29,6 +29,9 @@ var ErrNilBucket = errors.New("nil bucket")
 // ErrNilMongoDBClient signals that a nil mongodb client has been provided
 var ErrNilMongoDBClient = errors.New("nil mongodb client")
 
+// ErrNilSQLDBClient signals that a nil sql db client has been provided
+var ErrNilSQLDBClient = errors.New("nil sql db client")
+
 // ErrNilHttpClient signals that a nil http client has been provided
 var ErrNilHttpClient = errors.New("nil http client")
 
@@ -41,6 +44,9 @@ var ErrNilFacadeHandler = errors.New("nil facade handler")
 // ErrNilMetricsHandler signals that a nil metrics handler has been provided
 var ErrNilMetricsHandler = errors.New("nil metrics handler")
 
+// ErrNilRangeHandler signals that a nil range handler has been provided
+var ErrNilRangeHandler = errors.New("nil range handler")
+
 // ErrInvalidRedisConnType signals that an invalid redis connection type has been provided
 var ErrInvalidRedisConnType = errors.New("invalid redis connection type")
 
@@ -67,6 +67,15 @@ func (hcw *httpClientWrapper) GetGuardianData(ctx context.Context, address strin
 	return guardianDataResp.Data.GuardianData, nil
 }
 
+// GetFreshGuardianData returns the guardian data for the provided address, the same as GetGuardianData
+func (hcw *httpClientWrapper) GetFreshGuardianData(ctx context.Context, address string) (*api.GuardianData, error) {
+	return hcw.GetGuardianData(ctx, address)
+}
+
+// InvalidateGuardianData does nothing, as the guardian data is always fetched from the API
+func (hcw *httpClientWrapper) InvalidateGuardianData(_ string) {
+}
+
 func (hcw *httpClientWrapper) getData(ctx context.Context, endpoint string) ([]byte, error) {
 	buff, code, err := hcw.httpClient.GetHTTP(ctx, endpoint)
 	if err != nil || code != http.StatusOK {
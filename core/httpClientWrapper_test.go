@@ -126,6 +126,10 @@ func TestHttpClientWrapper_GetGuardianData(t *testing.T) {
 		guardianData, err := wrapper.GetGuardianData(context.Background(), providedAddress)
 		require.NoError(t, err)
 		require.Equal(t, providedData, guardianData)
+
+		guardianData, err = wrapper.GetFreshGuardianData(context.Background(), providedAddress)
+		require.NoError(t, err)
+		require.Equal(t, providedData, guardianData)
 	})
 }
 
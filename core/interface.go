@@ -32,21 +32,45 @@ type PubkeyConverter interface {
 
 // ServiceResolver defines the methods available for a service
 type ServiceResolver interface {
-	RegisterUser(userAddress core.AddressHandler, request requests.RegistrationPayload) (*requests.OTP, string, error)
-	VerifyCode(userAddress core.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error)
+	RegisterUser(userAddress core.AddressHandler, userIp string, request requests.RegistrationPayload) (*requests.OTP, string, error)
+	VerifyCode(userAddress core.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error)
 	SignMessage(userIp string, request requests.SignMessage) ([]byte, *requests.OTPCodeVerifyData, error)
 	SetSecurityModeNoExpire(userIp string, request requests.SecurityModeNoExpire) (*requests.OTPCodeVerifyData, error)
 	UnsetSecurityModeNoExpire(userIp string, request requests.SecurityModeNoExpire) (*requests.OTPCodeVerifyData, error)
 	SignTransaction(userIp string, request requests.SignTransaction) ([]byte, *requests.OTPCodeVerifyData, error)
 	SignMultipleTransactions(userIp string, request requests.SignMultipleTransactions) ([][]byte, *requests.OTPCodeVerifyData, error)
+	SetSpendingPolicy(userAddress core.AddressHandler, userIp string, request requests.SetSpendingPolicy) (*requests.OTPCodeVerifyData, error)
+	GetSpendingPolicy(userAddress core.AddressHandler) (*requests.SpendingPolicyResponse, error)
+	WebAuthnChallenge(userAddress core.AddressHandler, userIp string, request requests.WebAuthnChallenge) (*requests.WebAuthnChallengeResponse, *requests.OTPCodeVerifyData, error)
+	RegisterWebAuthn(userAddress core.AddressHandler, userIp string, request requests.RegisterWebAuthn) (*requests.OTPCodeVerifyData, error)
+	RegenerateRecoveryCodes(userAddress core.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error)
+	DeregisterUser(userAddress core.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error)
 	RegisteredUsers() (uint32, error)
 	TcsConfig() *TcsConfig
+	GetUserState(userAddress core.AddressHandler) (*requests.UserStateResponse, error)
+	ForceUnsetSecurityMode(userAddress core.AddressHandler) error
+	ResetRateLimiter(userAddress core.AddressHandler, userIp string) error
+	MarkGuardianNotUsable(userAddress core.AddressHandler, guardian string) error
+	ReEncryptUser(userAddress core.AddressHandler) error
+	StartReEncryption() error
+	GetReEncryptionStatus() *requests.ReEncryptionStatusResponse
+	ExportUserData(userAddress core.AddressHandler) (*requests.UserAuditDataResponse, error)
+	GetHistory(userAddress core.AddressHandler, offset uint32, limit uint32) (*requests.UserHistoryResponse, error)
+	GetGuardianStatus(userAddress core.AddressHandler, userIp string) (*requests.GuardianStatusResponse, error)
+	PreviewTransactions(request requests.SignMultipleTransactions) (*requests.PreviewTransactionsResponse, error)
+	ReconcileGuardianActivations(ctx context.Context) error
+	IsInterfaceNil() bool
+}
+
+// KeyProvider defines the methods for a component able to unwrap the mnemonic of the service
+type KeyProvider interface {
+	GetMnemonic() (data.Mnemonic, error)
 	IsInterfaceNil() bool
 }
 
 // KeysGenerator defines the methods for a component able to generate unique HD keys
 type KeysGenerator interface {
-	GenerateManagedKey() (crypto.PrivateKey, error)
+	GenerateManagedKey(keyID uint32) (crypto.PrivateKey, error)
 	GenerateKeys(index uint32) ([]crypto.PrivateKey, error)
 	IsInterfaceNil() bool
 }
@@ -59,6 +83,7 @@ type Storer interface {
 	Has(key []byte) error
 	SearchFirst(key []byte) ([]byte, error)
 	Remove(key []byte) error
+	RangeKeys(handler func(key []byte, val []byte) bool)
 	ClearCache()
 	Close() error
 	IsInterfaceNil() bool
@@ -88,20 +113,30 @@ type IndexHandler interface {
 	Put(key, data []byte) error
 	Get(key []byte) ([]byte, error)
 	Has(key []byte) error
+	Remove(key []byte) error
+	PutIfNotExists(key, data []byte) (bool, error)
+	RemoveIfEqual(key, data []byte) error
 	Close() error
 	AllocateBucketIndex() (uint32, error)
+	ReserveBucketIndex(index uint32) error
 	GetLastIndex() (uint32, error)
+	RangeKeys(ctx context.Context, handler func(key []byte, val []byte) bool) error
 	IsInterfaceNil() bool
 }
 
 // StorageWithIndex defines the methods for a component that holds multiple BucketIndexHandler
 type StorageWithIndex interface {
 	AllocateIndex(address []byte) (uint32, error)
+	ReserveIndex(index uint32) error
+	GetLastAllocatedIndex() (uint32, error)
+	ReserveIndexesUpTo(finalIndex uint32) error
 	Put(key, data []byte) error
 	Get(key []byte) ([]byte, error)
 	Has(key []byte) error
+	Remove(key []byte) error
 	Close() error
 	Count() (uint32, error)
+	RangeKeys(ctx context.Context, handler func(key []byte, val []byte) bool) error
 	IsInterfaceNil() bool
 }
 
@@ -115,6 +150,8 @@ type HttpClient interface {
 type HttpClientWrapper interface {
 	GetAccount(ctx context.Context, address string) (*data.Account, error)
 	GetGuardianData(ctx context.Context, address string) (*api.GuardianData, error)
+	GetFreshGuardianData(ctx context.Context, address string) (*api.GuardianData, error)
+	InvalidateGuardianData(address string)
 	IsInterfaceNil() bool
 }
 
@@ -131,3 +168,30 @@ type StatusMetricsHandler interface {
 	GetMetricsForPrometheus() string
 	IsInterfaceNil() bool
 }
+
+// AuditSink defines the append-only storage of the audit entries
+type AuditSink interface {
+	Append(entry AuditEntry) error
+	GetUserEntries(userAddress string, offset uint32, limit uint32) ([]AuditEntry, error)
+	Close() error
+	IsInterfaceNil() bool
+}
+
+// Notifier defines the methods for a component able to emit the security events outside the service
+type Notifier interface {
+	Notify(event SecurityEvent)
+	Close() error
+	IsInterfaceNil() bool
+}
+
+// GuardianActivationWorker defines the component which checks in background the activation of the guardians
+type GuardianActivationWorker interface {
+	Close() error
+	IsInterfaceNil() bool
+}
+
+// AuditLogger defines the behavior of a component that records the decisions of the service
+type AuditLogger interface {
+	Record(entry AuditEntry, txs []transaction.FrontendTransaction)
+	IsInterfaceNil() bool
+}
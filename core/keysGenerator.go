@@ -46,10 +46,12 @@ func checkArgs(args ArgGuardianKeyGenerator) error {
 	return nil
 }
 
-// GenerateManagedKey generates one HD key based on a constant index, which will only be used by the service
-func (generator *guardianKeyGenerator) GenerateManagedKey() (crypto.PrivateKey, error) {
+// GenerateManagedKey generates one HD key based on a constant index, which will only be used by the service.
+// Each key id is derived on its own account, key id 0 being the initial managed key. As all the key ids are
+// derived from the same mnemonic, a new key id does not protect against the exposure of the mnemonic itself
+func (generator *guardianKeyGenerator) GenerateManagedKey(keyID uint32) (crypto.PrivateKey, error) {
 	wallet := interactors.NewWallet()
-	privateKeyBytes := wallet.GetPrivateKeyFromMnemonic(generator.mnemonic, 0, managedKeyIndex)
+	privateKeyBytes := wallet.GetPrivateKeyFromMnemonic(generator.mnemonic, keyID, managedKeyIndex)
 	return generator.keyGen.PrivateKeyFromByteArray(privateKeyBytes)
 }
 
@@ -158,11 +158,24 @@ func TestGuardianKeyGenerator_GenerateManagedKey(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotNil(t, kg)
 
-	key, err := kg.GenerateManagedKey()
+	key, err := kg.GenerateManagedKey(0)
 	assert.Nil(t, err)
 	keyBytes, err := key.ToByteArray()
 	assert.Nil(t, err)
 	assert.Equal(t, "413f42575f7f26fad3317a778771212fdb80245850981e48b58a4f25e344e8f90139472eff6886771a982f3083da5d421f24c29181e63888228dc81ca60d69e1", hex.EncodeToString(keyBytes))
+
+	keysMap := make(map[string]struct{})
+	checkKeyAndAddToMap(t, key, keysMap)
+	for keyID := uint32(1); keyID < 5; keyID++ {
+		key, err = kg.GenerateManagedKey(keyID)
+		assert.Nil(t, err)
+		checkKeyAndAddToMap(t, key, keysMap)
+	}
+
+	guardianKeys, err := kg.GenerateKeys(1)
+	assert.Nil(t, err)
+	checkKeyAndAddToMap(t, guardianKeys[0], keysMap)
+	checkKeyAndAddToMap(t, guardianKeys[1], keysMap)
 }
 
 func checkKeyAndAddToMap(t *testing.T, key crypto.PrivateKey, keysMap map[string]struct{}) {
@@ -9,19 +9,21 @@ import (
 // SignMessage is the JSON request the service is receiving
 // when a user sends a new message to be signed by the guardian
 type SignMessage struct {
-	Code         string `json:"code"`
-	SecondCode   string `json:"second-code"`
-	Message      string `json:"message"`
-	UserAddr     string `json:"user"`
-	GuardianAddr string `json:"guardian"`
+	Code         string             `json:"code"`
+	SecondCode   string             `json:"second-code"`
+	Assertion    *WebAuthnAssertion `json:"webauthn-assertion,omitempty"`
+	Message      string             `json:"message"`
+	UserAddr     string             `json:"user"`
+	GuardianAddr string             `json:"guardian"`
 }
 
 // SecurityModeNoExpire is the JSON request the service is receiving
 // when a user wants to set/unset the security mode
 type SecurityModeNoExpire struct {
-	Code       string `json:"code"`
-	SecondCode string `json:"second-code"`
-	UserAddr   string `json:"user"`
+	Code         string `json:"code"`
+	SecondCode   string `json:"second-code"`
+	RecoveryCode string `json:"recovery-code,omitempty"`
+	UserAddr     string `json:"user"`
 }
 
 // SignMessageResponse is the service response to the sign message request
@@ -35,6 +37,7 @@ type SignMessageResponse struct {
 type SignTransaction struct {
 	Code       string                          `json:"code"`
 	SecondCode string                          `json:"second-code"`
+	Assertion  *WebAuthnAssertion              `json:"webauthn-assertion,omitempty"`
 	Tx         transaction.FrontendTransaction `json:"transaction"`
 }
 
@@ -48,6 +51,7 @@ type SignTransactionResponse struct {
 type SignMultipleTransactions struct {
 	Code       string                            `json:"code"`
 	SecondCode string                            `json:"second-code"`
+	Assertion  *WebAuthnAssertion                `json:"webauthn-assertion,omitempty"`
 	Txs        []transaction.FrontendTransaction `json:"transactions"`
 }
 
@@ -58,14 +62,98 @@ type SignMultipleTransactionsResponse struct {
 
 // VerificationPayload represents the JSON requests a user uses to validate the authentication code
 type VerificationPayload struct {
-	Code       string `json:"code"`
-	SecondCode string `json:"second-code"`
-	Guardian   string `json:"guardian"`
+	Code       string             `json:"code"`
+	SecondCode string             `json:"second-code"`
+	Assertion  *WebAuthnAssertion `json:"webauthn-assertion,omitempty"`
+	Guardian   string             `json:"guardian"`
+}
+
+// WebAuthnAssertion is the signed assertion of a WebAuthn authenticator, accepted in place of the codes.
+// All the fields are base64url encoded
+type WebAuthnAssertion struct {
+	CredentialID      string `json:"credential-id"`
+	ClientDataJSON    string `json:"client-data-json"`
+	AuthenticatorData string `json:"authenticator-data"`
+	Signature         string `json:"signature"`
+}
+
+// WebAuthnRegistration is the attestation of a new WebAuthn credential. All the fields are base64url encoded
+type WebAuthnRegistration struct {
+	ClientDataJSON    string `json:"client-data-json"`
+	AttestationObject string `json:"attestation-object"`
+}
+
+// WebAuthnChallenge is the JSON request the service is receiving
+// when a user needs a challenge for a WebAuthn ceremony. The challenge is bound to the transactions or to the
+// message to be signed, if any, so the assertion can only be used for them
+type WebAuthnChallenge struct {
+	Guardian     string                            `json:"guardian"`
+	Transactions []transaction.FrontendTransaction `json:"transactions,omitempty"`
+	Message      string                            `json:"message,omitempty"`
+}
+
+// WebAuthnChallengeResponse is the service response to the WebAuthn challenge request
+type WebAuthnChallengeResponse struct {
+	Challenge    string `json:"challenge"`
+	CredentialID string `json:"credential-id,omitempty"`
+}
+
+// RegisterWebAuthn is the JSON request the service is receiving
+// when a user wants to register a WebAuthn credential for a guardian
+type RegisterWebAuthn struct {
+	Code       string               `json:"code"`
+	SecondCode string               `json:"second-code"`
+	Guardian   string               `json:"guardian"`
+	Credential WebAuthnRegistration `json:"credential"`
+}
+
+// SpendingLimit holds the daily and weekly caps of a token, as decimal strings of the smallest denomination
+type SpendingLimit struct {
+	Token  string `json:"token"`
+	Daily  string `json:"daily,omitempty"`
+	Weekly string `json:"weekly,omitempty"`
+}
+
+// SetSpendingPolicy is the JSON request the service is receiving
+// when a user wants to set the spending limits and the trusted receivers
+type SetSpendingPolicy struct {
+	Code             string          `json:"code"`
+	SecondCode       string          `json:"second-code"`
+	Guardian         string          `json:"guardian"`
+	Limits           []SpendingLimit `json:"limits"`
+	TrustedReceivers []string        `json:"trusted-receivers"`
+}
+
+// SpendingPolicy defines the spending limits and the trusted receivers of a user
+type SpendingPolicy struct {
+	Limits              []SpendingLimit `json:"limits"`
+	TrustedReceivers    []string        `json:"trusted-receivers"`
+	ActivationTimestamp int64           `json:"activation-timestamp,omitempty"`
+}
+
+// SpentAmount defines the amount of a token co-signed during the current day and week
+type SpentAmount struct {
+	Token       string `json:"token"`
+	DailySpent  string `json:"daily-spent"`
+	WeeklySpent string `json:"weekly-spent"`
+}
+
+// SpendingPolicyResponse is the service response to the spending policy request
+type SpendingPolicyResponse struct {
+	Active  SpendingPolicy  `json:"active"`
+	Pending *SpendingPolicy `json:"pending,omitempty"`
+	Spent   []SpentAmount   `json:"spent"`
 }
 
 // RegistrationPayload represents the JSON requests a user uses to require a new provider registration
 type RegistrationPayload struct {
-	Tag string `json:"tag"`
+	Tag          string `json:"tag"`
+	RecoveryCode string `json:"recovery-code,omitempty"`
+}
+
+// RecoveryCodesResponse holds the newly generated recovery codes, which are only returned once
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery-codes"`
 }
 
 // RegisterReturnData represents the returned data for a registration request
@@ -121,3 +209,139 @@ type OTP struct {
 	Secret              string `json:"secret,omitempty"`
 	TimeSinceGeneration int64  `json:"seconds_since_generation,omitempty"`
 }
+
+// AdminUserRequest is the JSON request the service is receiving
+// when an operator acts on the state of a user
+type AdminUserRequest struct {
+	UserAddr string `json:"user"`
+}
+
+// AdminResetRateLimiter is the JSON request the service is receiving
+// when an operator resets the failed trials of a user from an ip
+type AdminResetRateLimiter struct {
+	UserAddr string `json:"user"`
+	UserIp   string `json:"ip"`
+}
+
+// AdminGuardianRequest is the JSON request the service is receiving
+// when an operator acts on a guardian of a user
+type AdminGuardianRequest struct {
+	UserAddr string `json:"user"`
+	Guardian string `json:"guardian"`
+}
+
+// GuardianStateResponse defines the state of a guardian, without any of its secrets
+type GuardianStateResponse struct {
+	Address                string `json:"address"`
+	State                  string `json:"state"`
+	OTPType                string `json:"otp-type,omitempty"`
+	LastOTPChangeTimestamp int64  `json:"last-otp-change-timestamp,omitempty"`
+	WebAuthnRegistered     bool   `json:"webauthn-registered"`
+}
+
+// GuardianStatus defines the usability and the on chain activation progress of a guardian, one of Untracked,
+// Verified, PendingOnChain, Active or Replaced
+type GuardianStatus struct {
+	Address                string `json:"address"`
+	Usable                 bool   `json:"usable"`
+	ActivationState        string `json:"activation-state"`
+	VerifiedTimestamp      int64  `json:"verified-timestamp,omitempty"`
+	LastOTPChangeTimestamp int64  `json:"last-otp-change-timestamp,omitempty"`
+	// the time left until the otp of the guardian can be registered again, in seconds
+	RegistrationAllowedAfter int64 `json:"registration-allowed-after"`
+}
+
+// GuardianStatusResponse is the service response to the guardian status request
+type GuardianStatusResponse struct {
+	Guardians            []GuardianStatus   `json:"guardians"`
+	SecurityModeActive   bool               `json:"security-mode-active"`
+	SecurityModeNoExpire bool               `json:"security-mode-no-expire"`
+	VerifyData           *OTPCodeVerifyData `json:"verification-retry-info"`
+}
+
+// UserStateResponse is the service response to the admin user state request
+type UserStateResponse struct {
+	Address                string                  `json:"address"`
+	Index                  uint32                  `json:"index"`
+	Guardians              []GuardianStateResponse `json:"guardians"`
+	RecoveryCodesRemaining int                     `json:"recovery-codes-remaining"`
+	EncryptionKeyID        uint32                  `json:"encryption-key-id"`
+	EncryptionVersion      uint32                  `json:"encryption-version"`
+}
+
+// ReEncryptionStatusResponse is the service response to the admin re-encryption status request, the counters
+// belonging to the last re-encryption job started since the service runs
+type ReEncryptionStatusResponse struct {
+	Running         bool  `json:"running"`
+	StartTimestamp  int64 `json:"start-timestamp"`
+	FinishTimestamp int64 `json:"finish-timestamp,omitempty"`
+	NumUsers        int   `json:"num-users"`
+	NumOnOldKeys    int   `json:"num-on-old-keys"`
+	NumReEncrypted  int   `json:"num-re-encrypted"`
+	NumFailed       int   `json:"num-failed"`
+	// the number of users still encrypted with each of the old key ids
+	RemainingOnOldKeys map[uint32]int `json:"remaining-on-old-keys"`
+	Error              string         `json:"error,omitempty"`
+}
+
+// UserAuditDataResponse is the service response to the admin audit data export request
+type UserAuditDataResponse struct {
+	State           UserStateResponse      `json:"state"`
+	SpendingPolicy  SpendingPolicyResponse `json:"spending-policy"`
+	ExportTimestamp int64                  `json:"export-timestamp"`
+}
+
+// HistoryEntry defines one action done on behalf of the user, as seen by the service
+type HistoryEntry struct {
+	Timestamp  int64    `json:"timestamp"`
+	Route      string   `json:"route"`
+	Guardian   string   `json:"guardian,omitempty"`
+	UserIp     string   `json:"ip"`
+	UserAgent  string   `json:"user-agent"`
+	TxHashes   []string `json:"tx-hashes,omitempty"`
+	Outcome    string   `json:"outcome"`
+	ErrorClass string   `json:"error-class,omitempty"`
+}
+
+// UserHistoryResponse is the service response to the signing history request, newest entries first
+type UserHistoryResponse struct {
+	Entries []HistoryEntry `json:"entries"`
+	Offset  uint32         `json:"offset"`
+	Limit   uint32         `json:"limit"`
+}
+
+// TokenTransfer defines a transfer of EGLD or of an ESDT token, the nonce being set only for the NFTs, SFTs and
+// Meta ESDTs
+type TokenTransfer struct {
+	Token  string `json:"token"`
+	Nonce  uint64 `json:"nonce,omitempty"`
+	Amount string `json:"amount"`
+}
+
+// TransactionSummary is the human-readable summary of a transaction, built from its decoded data field.
+// The receiver is the final receiver of the transfers, which differs from the transaction receiver for the NFT
+// and multi token transfers
+type TransactionSummary struct {
+	Nonce                 uint64          `json:"nonce"`
+	Sender                string          `json:"sender"`
+	Receiver              string          `json:"receiver"`
+	Type                  string          `json:"type"`
+	BuiltInFunction       string          `json:"built-in-function,omitempty"`
+	Function              string          `json:"function,omitempty"`
+	Arguments             []string        `json:"arguments,omitempty"`
+	Transfers             []TokenTransfer `json:"transfers"`
+	NewGuardian           string          `json:"new-guardian,omitempty"`
+	NewGuardianServiceUID string          `json:"new-guardian-service-uid,omitempty"`
+	MaxFee                string          `json:"max-fee"`
+	TrustedReceiver       bool            `json:"trusted-receiver"`
+}
+
+// PreviewTransactionsResponse is the service response to the preview transactions request. The spent amounts include
+// the previewed transactions and are only returned for the tokens with spending limits
+type PreviewTransactionsResponse struct {
+	Guardian             GuardianStatus       `json:"guardian"`
+	ConfirmationRequired bool                 `json:"confirmation-required"`
+	Transactions         []TransactionSummary `json:"transactions"`
+	SpendingLimits       []SpendingLimit      `json:"spending-limits"`
+	SpentAfterSigning    []SpentAmount        `json:"spent-after-signing"`
+}
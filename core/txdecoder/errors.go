@@ -0,0 +1,12 @@
+package txdecoder
+
+import "errors"
+
+// ErrNilPubKeyConverter signals that a nil pub key converter was provided
+var ErrNilPubKeyConverter = errors.New("nil pub key converter")
+
+// ErrInvalidDataField signals that the data field of the transaction could not be decoded
+var ErrInvalidDataField = errors.New("invalid data field")
+
+// ErrInvalidValue signals that the value of the transaction is invalid
+var ErrInvalidValue = errors.New("invalid value")
@@ -0,0 +1,246 @@
+package txdecoder
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	chainCore "github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+)
+
+const (
+	// EGLDToken is the identifier used for the native token transfers
+	EGLDToken = "EGLD"
+
+	argsSeparator = "@"
+
+	esdtTransferNumArgs                = 2
+	esdtNFTTransferNumArgs             = 4
+	multiESDTNFTTransferMinNumArgs     = 2
+	multiESDTNFTTransferArgsPerToken   = 3
+	multiESDTNFTTransferNumTokensIndex = 1
+)
+
+// Transfer holds a token transfer performed by a transaction
+type Transfer struct {
+	Token  string
+	Nonce  uint64
+	Amount *big.Int
+}
+
+// DecodedTransaction holds the information extracted from a transaction and its data field
+type DecodedTransaction struct {
+	// Receiver is the final receiver of the transfers, which differs from the transaction receiver for the NFT transfers
+	Receiver []byte
+	// BuiltInFunction is the ESDT transfer built-in function used, if any
+	BuiltInFunction string
+	// Function is the called function, without the ESDT transfer built-in function
+	Function string
+	// Arguments are the hex encoded arguments of the called function
+	Arguments []string
+	// Transfers holds the EGLD and ESDT transfers of the transaction
+	Transfers []Transfer
+}
+
+type txDecoder struct {
+	pubKeyConverter core.PubkeyConverter
+}
+
+// NewTxDecoder returns a new instance of txDecoder
+func NewTxDecoder(pubKeyConverter core.PubkeyConverter) (*txDecoder, error) {
+	if check.IfNil(pubKeyConverter) {
+		return nil, ErrNilPubKeyConverter
+	}
+
+	return &txDecoder{
+		pubKeyConverter: pubKeyConverter,
+	}, nil
+}
+
+// Decode extracts the final receiver, the called function and the transfers of the provided transaction
+func (decoder *txDecoder) Decode(tx transaction.FrontendTransaction) (*DecodedTransaction, error) {
+	receiver, err := decoder.pubKeyConverter.Decode(tx.Receiver)
+	if err != nil {
+		return nil, fmt.Errorf("%w for receiver %s", err, tx.Receiver)
+	}
+
+	value, err := parseValue(tx.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	decodedTx := &DecodedTransaction{
+		Receiver:  receiver,
+		Arguments: make([]string, 0),
+		Transfers: make([]Transfer, 0),
+	}
+	if value.Sign() > 0 {
+		decodedTx.Transfers = append(decodedTx.Transfers, Transfer{
+			Token:  EGLDToken,
+			Amount: value,
+		})
+	}
+	if len(tx.Data) == 0 {
+		return decodedTx, nil
+	}
+
+	tokens := strings.Split(string(tx.Data), argsSeparator)
+	function, args := tokens[0], tokens[1:]
+
+	switch function {
+	case chainCore.BuiltInFunctionESDTTransfer:
+		decodedTx.BuiltInFunction = function
+		return decodeESDTTransfer(decodedTx, args)
+	case chainCore.BuiltInFunctionESDTNFTTransfer:
+		decodedTx.BuiltInFunction = function
+		return decodeESDTNFTTransfer(decodedTx, args)
+	case chainCore.BuiltInFunctionMultiESDTNFTTransfer:
+		decodedTx.BuiltInFunction = function
+		return decodeMultiESDTNFTTransfer(decodedTx, args)
+	default:
+		decodedTx.Function = function
+		decodedTx.Arguments = args
+		return decodedTx, nil
+	}
+}
+
+func decodeESDTTransfer(decodedTx *DecodedTransaction, args []string) (*DecodedTransaction, error) {
+	if len(args) < esdtTransferNumArgs {
+		return nil, fmt.Errorf("%w, not enough arguments for %s", ErrInvalidDataField, chainCore.BuiltInFunctionESDTTransfer)
+	}
+
+	transfer, err := decodeTransfer(args[0], "", args[1])
+	if err != nil {
+		return nil, err
+	}
+	decodedTx.Transfers = append(decodedTx.Transfers, *transfer)
+
+	return decodeNestedFunction(decodedTx, args[esdtTransferNumArgs:])
+}
+
+func decodeESDTNFTTransfer(decodedTx *DecodedTransaction, args []string) (*DecodedTransaction, error) {
+	if len(args) < esdtNFTTransferNumArgs {
+		return nil, fmt.Errorf("%w, not enough arguments for %s", ErrInvalidDataField, chainCore.BuiltInFunctionESDTNFTTransfer)
+	}
+
+	transfer, err := decodeTransfer(args[0], args[1], args[2])
+	if err != nil {
+		return nil, err
+	}
+	decodedTx.Transfers = append(decodedTx.Transfers, *transfer)
+
+	decodedTx.Receiver, err = hex.DecodeString(args[3])
+	if err != nil {
+		return nil, fmt.Errorf("%w, invalid receiver: %s", ErrInvalidDataField, err.Error())
+	}
+
+	return decodeNestedFunction(decodedTx, args[esdtNFTTransferNumArgs:])
+}
+
+func decodeMultiESDTNFTTransfer(decodedTx *DecodedTransaction, args []string) (*DecodedTransaction, error) {
+	if len(args) < multiESDTNFTTransferMinNumArgs {
+		return nil, fmt.Errorf("%w, not enough arguments for %s", ErrInvalidDataField, chainCore.BuiltInFunctionMultiESDTNFTTransfer)
+	}
+
+	var err error
+	decodedTx.Receiver, err = hex.DecodeString(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w, invalid receiver: %s", ErrInvalidDataField, err.Error())
+	}
+
+	numTokens, err := decodeUint64(args[multiESDTNFTTransferNumTokensIndex])
+	if err != nil {
+		return nil, fmt.Errorf("%w, invalid number of tokens: %s", ErrInvalidDataField, err.Error())
+	}
+
+	tokensArgs := args[multiESDTNFTTransferMinNumArgs:]
+	if uint64(len(tokensArgs)) < numTokens*multiESDTNFTTransferArgsPerToken {
+		return nil, fmt.Errorf("%w, not enough arguments for %d tokens", ErrInvalidDataField, numTokens)
+	}
+
+	for i := uint64(0); i < numTokens; i++ {
+		tokenArgs := tokensArgs[i*multiESDTNFTTransferArgsPerToken:]
+		transfer, errDecode := decodeTransfer(tokenArgs[0], tokenArgs[1], tokenArgs[2])
+		if errDecode != nil {
+			return nil, fmt.Errorf("%w for token #%d", errDecode, i)
+		}
+		decodedTx.Transfers = append(decodedTx.Transfers, *transfer)
+	}
+
+	return decodeNestedFunction(decodedTx, tokensArgs[numTokens*multiESDTNFTTransferArgsPerToken:])
+}
+
+func decodeNestedFunction(decodedTx *DecodedTransaction, args []string) (*DecodedTransaction, error) {
+	if len(args) == 0 {
+		return decodedTx, nil
+	}
+
+	function, err := hex.DecodeString(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w, invalid function: %s", ErrInvalidDataField, err.Error())
+	}
+
+	decodedTx.Function = string(function)
+	decodedTx.Arguments = args[1:]
+
+	return decodedTx, nil
+}
+
+func decodeTransfer(tokenArg string, nonceArg string, amountArg string) (*Transfer, error) {
+	token, err := hex.DecodeString(tokenArg)
+	if err != nil || len(token) == 0 {
+		return nil, fmt.Errorf("%w, invalid token %s", ErrInvalidDataField, tokenArg)
+	}
+
+	nonce, err := decodeUint64(nonceArg)
+	if err != nil {
+		return nil, fmt.Errorf("%w, invalid nonce: %s", ErrInvalidDataField, err.Error())
+	}
+
+	amountBytes, err := hex.DecodeString(amountArg)
+	if err != nil {
+		return nil, fmt.Errorf("%w, invalid amount: %s", ErrInvalidDataField, err.Error())
+	}
+
+	return &Transfer{
+		Token:  string(token),
+		Nonce:  nonce,
+		Amount: big.NewInt(0).SetBytes(amountBytes),
+	}, nil
+}
+
+func decodeUint64(arg string) (uint64, error) {
+	if len(arg) == 0 {
+		return 0, nil
+	}
+
+	value, ok := big.NewInt(0).SetString(arg, 16)
+	if !ok || !value.IsUint64() {
+		return 0, fmt.Errorf("invalid value %s", arg)
+	}
+
+	return value.Uint64(), nil
+}
+
+func parseValue(value string) (*big.Int, error) {
+	if len(value) == 0 {
+		return big.NewInt(0), nil
+	}
+
+	txValue, ok := big.NewInt(0).SetString(value, 10)
+	if !ok || txValue.Sign() < 0 {
+		return nil, fmt.Errorf("%w %s", ErrInvalidValue, value)
+	}
+
+	return txValue, nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (decoder *txDecoder) IsInterfaceNil() bool {
+	return decoder == nil
+}
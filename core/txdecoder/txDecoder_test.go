@@ -0,0 +1,176 @@
+package txdecoder
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/data/mock"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var expectedErr = errors.New("expected error")
+
+func createPubKeyConverter() *mock.PubkeyConverterStub {
+	return &mock.PubkeyConverterStub{
+		DecodeCalled: func(humanReadable string) ([]byte, error) {
+			return []byte(humanReadable), nil
+		},
+	}
+}
+
+func hexArg(arg string) string {
+	return hex.EncodeToString([]byte(arg))
+}
+
+func TestNewTxDecoder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil pub key converter should error", func(t *testing.T) {
+		t.Parallel()
+
+		decoder, err := NewTxDecoder(nil)
+		assert.Equal(t, ErrNilPubKeyConverter, err)
+		assert.True(t, decoder.IsInterfaceNil())
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		decoder, err := NewTxDecoder(createPubKeyConverter())
+		assert.Nil(t, err)
+		assert.False(t, decoder.IsInterfaceNil())
+	})
+}
+
+func TestTxDecoder_Decode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid receiver should error", func(t *testing.T) {
+		t.Parallel()
+
+		decoder, _ := NewTxDecoder(&mock.PubkeyConverterStub{
+			DecodeCalled: func(humanReadable string) ([]byte, error) {
+				return nil, expectedErr
+			},
+		})
+		decodedTx, err := decoder.Decode(transaction.FrontendTransaction{Receiver: "receiver"})
+		assert.True(t, errors.Is(err, expectedErr))
+		assert.Nil(t, decodedTx)
+	})
+	t.Run("invalid or negative value should error", func(t *testing.T) {
+		t.Parallel()
+
+		decoder, _ := NewTxDecoder(createPubKeyConverter())
+		decodedTx, err := decoder.Decode(transaction.FrontendTransaction{Value: "abc"})
+		assert.True(t, errors.Is(err, ErrInvalidValue))
+		assert.Nil(t, decodedTx)
+
+		decodedTx, err = decoder.Decode(transaction.FrontendTransaction{Value: "-1"})
+		assert.True(t, errors.Is(err, ErrInvalidValue))
+		assert.Nil(t, decodedTx)
+	})
+	t.Run("malformed built-in function data field should error", func(t *testing.T) {
+		t.Parallel()
+
+		decoder, _ := NewTxDecoder(createPubKeyConverter())
+		malformedDataFields := []string{
+			"ESDTTransfer@" + hexArg("TKN-123456"),
+			"ESDTTransfer@zz@01",
+			"ESDTTransfer@" + hexArg("TKN-123456") + "@01@zz",
+			"ESDTNFTTransfer@" + hexArg("NFT-123456") + "@01@01",
+			"ESDTNFTTransfer@" + hexArg("NFT-123456") + "@01@01@zz",
+			"MultiESDTNFTTransfer@" + hexArg("receiver"),
+			"MultiESDTNFTTransfer@" + hexArg("receiver") + "@02@" + hexArg("TKN-123456") + "@@01",
+		}
+		for _, dataField := range malformedDataFields {
+			decodedTx, err := decoder.Decode(transaction.FrontendTransaction{Data: []byte(dataField)})
+			assert.True(t, errors.Is(err, ErrInvalidDataField), dataField)
+			assert.Nil(t, decodedTx)
+		}
+	})
+	t.Run("EGLD transfer with function call should work", func(t *testing.T) {
+		t.Parallel()
+
+		decoder, _ := NewTxDecoder(createPubKeyConverter())
+		decodedTx, err := decoder.Decode(transaction.FrontendTransaction{
+			Receiver: "receiver",
+			Value:    "1000",
+			Data:     []byte("claim@01@02"),
+		})
+		require.Nil(t, err)
+
+		expectedTx := &DecodedTransaction{
+			Receiver:  []byte("receiver"),
+			Function:  "claim",
+			Arguments: []string{"01", "02"},
+			Transfers: []Transfer{{Token: EGLDToken, Amount: big.NewInt(1000)}},
+		}
+		assert.Equal(t, expectedTx, decodedTx)
+	})
+	t.Run("ESDTTransfer with nested function should work", func(t *testing.T) {
+		t.Parallel()
+
+		decoder, _ := NewTxDecoder(createPubKeyConverter())
+		decodedTx, err := decoder.Decode(transaction.FrontendTransaction{
+			Receiver: "receiver",
+			Value:    "0",
+			Data:     []byte("ESDTTransfer@" + hexArg("TKN-123456") + "@0a@" + hexArg("swap") + "@01"),
+		})
+		require.Nil(t, err)
+
+		expectedTx := &DecodedTransaction{
+			Receiver:        []byte("receiver"),
+			BuiltInFunction: "ESDTTransfer",
+			Function:        "swap",
+			Arguments:       []string{"01"},
+			Transfers:       []Transfer{{Token: "TKN-123456", Amount: big.NewInt(10)}},
+		}
+		assert.Equal(t, expectedTx, decodedTx)
+	})
+	t.Run("ESDTNFTTransfer should return the receiver from the data field", func(t *testing.T) {
+		t.Parallel()
+
+		decoder, _ := NewTxDecoder(createPubKeyConverter())
+		decodedTx, err := decoder.Decode(transaction.FrontendTransaction{
+			Receiver: "sender",
+			Data:     []byte("ESDTNFTTransfer@" + hexArg("NFT-123456") + "@05@01@" + hexArg("receiver")),
+		})
+		require.Nil(t, err)
+
+		expectedTx := &DecodedTransaction{
+			Receiver:        []byte("receiver"),
+			BuiltInFunction: "ESDTNFTTransfer",
+			Arguments:       make([]string, 0),
+			Transfers:       []Transfer{{Token: "NFT-123456", Nonce: 5, Amount: big.NewInt(1)}},
+		}
+		assert.Equal(t, expectedTx, decodedTx)
+	})
+	t.Run("MultiESDTNFTTransfer should return all the transfers", func(t *testing.T) {
+		t.Parallel()
+
+		decoder, _ := NewTxDecoder(createPubKeyConverter())
+		decodedTx, err := decoder.Decode(transaction.FrontendTransaction{
+			Receiver: "sender",
+			Data: []byte("MultiESDTNFTTransfer@" + hexArg("receiver") + "@02@" +
+				hexArg("TKN-123456") + "@@64@" +
+				hexArg("NFT-123456") + "@07@01@" +
+				hexArg("deposit")),
+		})
+		require.Nil(t, err)
+
+		expectedTx := &DecodedTransaction{
+			Receiver:        []byte("receiver"),
+			BuiltInFunction: "MultiESDTNFTTransfer",
+			Function:        "deposit",
+			Arguments:       make([]string, 0),
+			Transfers: []Transfer{
+				{Token: "TKN-123456", Amount: big.NewInt(100)},
+				{Token: "NFT-123456", Nonce: 7, Amount: big.NewInt(1)},
+			},
+		}
+		assert.Equal(t, expectedTx, decodedTx)
+	})
+}
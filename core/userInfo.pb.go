@@ -51,16 +51,134 @@ func (GuardianState) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_9abb1e7c7c5082b5, []int{0}
 }
 
-// OTPInfo holds the encrypted otp along with its last update timestamp
+// ActivationState represents the on chain activation progress of a verified guardian
+type ActivationState int32
+
+const (
+	// Untracked represents a guardian not verified yet, or verified before the activation was tracked
+	Untracked ActivationState = 0
+	// Verified represents a guardian with a verified otp, not yet set on chain
+	Verified ActivationState = 1
+	// PendingOnChain represents a guardian set on chain as pending guardian, until its activation epoch
+	PendingOnChain ActivationState = 2
+	// Active represents the active guardian on chain
+	Active ActivationState = 3
+	// Replaced represents a guardian which was set on chain, but was replaced afterwards
+	Replaced ActivationState = 4
+)
+
+var ActivationState_name = map[int32]string{
+	0: "Untracked",
+	1: "Verified",
+	2: "PendingOnChain",
+	3: "Active",
+	4: "Replaced",
+}
+
+var ActivationState_value = map[string]int32{
+	"Untracked":      0,
+	"Verified":       1,
+	"PendingOnChain": 2,
+	"Active":         3,
+	"Replaced":       4,
+}
+
+func (ActivationState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_9abb1e7c7c5082b5, []int{1}
+}
+
+// OTPParams holds the parameters the otp was generated with. Empty for otps generated before they became configurable.
+// Counter is the next accepted counter for hotp, respectively the next accepted time step for totp
+type OTPParams struct {
+	Type      string `protobuf:"bytes,1,opt,name=Type,proto3" json:"Type,omitempty"`
+	Algorithm string `protobuf:"bytes,2,opt,name=Algorithm,proto3" json:"Algorithm,omitempty"`
+	Digits    uint32 `protobuf:"varint,3,opt,name=Digits,proto3" json:"Digits,omitempty"`
+	Period    uint32 `protobuf:"varint,4,opt,name=Period,proto3" json:"Period,omitempty"`
+	Skew      uint32 `protobuf:"varint,5,opt,name=Skew,proto3" json:"Skew,omitempty"`
+	Counter   uint64 `protobuf:"varint,6,opt,name=Counter,proto3" json:"Counter,omitempty"`
+}
+
+func (m *OTPParams) Reset()      { *m = OTPParams{} }
+func (*OTPParams) ProtoMessage() {}
+func (*OTPParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9abb1e7c7c5082b5, []int{0}
+}
+func (m *OTPParams) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *OTPParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *OTPParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OTPParams.Merge(m, src)
+}
+func (m *OTPParams) XXX_Size() int {
+	return m.Size()
+}
+func (m *OTPParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_OTPParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OTPParams proto.InternalMessageInfo
+
+func (m *OTPParams) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *OTPParams) GetAlgorithm() string {
+	if m != nil {
+		return m.Algorithm
+	}
+	return ""
+}
+
+func (m *OTPParams) GetDigits() uint32 {
+	if m != nil {
+		return m.Digits
+	}
+	return 0
+}
+
+func (m *OTPParams) GetPeriod() uint32 {
+	if m != nil {
+		return m.Period
+	}
+	return 0
+}
+
+func (m *OTPParams) GetSkew() uint32 {
+	if m != nil {
+		return m.Skew
+	}
+	return 0
+}
+
+func (m *OTPParams) GetCounter() uint64 {
+	if m != nil {
+		return m.Counter
+	}
+	return 0
+}
+
+// OTPInfo holds the encrypted otp along with its last update timestamp and its parameters
 type OTPInfo struct {
-	OTP                     []byte `protobuf:"bytes,1,opt,name=OTP,proto3" json:"OTP,omitempty"`
-	LastTOTPChangeTimestamp int64  `protobuf:"varint,2,opt,name=LastTOTPChangeTimestamp,proto3" json:"LastTOTPChangeTimestamp,omitempty"`
+	OTP                     []byte    `protobuf:"bytes,1,opt,name=OTP,proto3" json:"OTP,omitempty"`
+	LastTOTPChangeTimestamp int64     `protobuf:"varint,2,opt,name=LastTOTPChangeTimestamp,proto3" json:"LastTOTPChangeTimestamp,omitempty"`
+	Params                  OTPParams `protobuf:"bytes,3,opt,name=Params,proto3" json:"Params"`
 }
 
 func (m *OTPInfo) Reset()      { *m = OTPInfo{} }
 func (*OTPInfo) ProtoMessage() {}
 func (*OTPInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9abb1e7c7c5082b5, []int{0}
+	return fileDescriptor_9abb1e7c7c5082b5, []int{1}
 }
 func (m *OTPInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -99,18 +217,94 @@ func (m *OTPInfo) GetLastTOTPChangeTimestamp() int64 {
 	return 0
 }
 
-// GuardianInfo holds details about a guardian
+func (m *OTPInfo) GetParams() OTPParams {
+	if m != nil {
+		return m.Params
+	}
+	return OTPParams{}
+}
+
+// WebAuthnInfo holds the WebAuthn credential of a guardian along with the issue timestamp, in nanoseconds,
+// of the last challenge it consumed. Older challenges are rejected, so a challenge can only be used once
+type WebAuthnInfo struct {
+	CredentialID           []byte `protobuf:"bytes,1,opt,name=CredentialID,proto3" json:"CredentialID,omitempty"`
+	PublicKey              []byte `protobuf:"bytes,2,opt,name=PublicKey,proto3" json:"PublicKey,omitempty"`
+	SignCount              uint32 `protobuf:"varint,3,opt,name=SignCount,proto3" json:"SignCount,omitempty"`
+	LastChallengeTimestamp int64  `protobuf:"varint,5,opt,name=LastChallengeTimestamp,proto3" json:"LastChallengeTimestamp,omitempty"`
+}
+
+func (m *WebAuthnInfo) Reset()      { *m = WebAuthnInfo{} }
+func (*WebAuthnInfo) ProtoMessage() {}
+func (*WebAuthnInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9abb1e7c7c5082b5, []int{2}
+}
+func (m *WebAuthnInfo) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *WebAuthnInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *WebAuthnInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WebAuthnInfo.Merge(m, src)
+}
+func (m *WebAuthnInfo) XXX_Size() int {
+	return m.Size()
+}
+func (m *WebAuthnInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_WebAuthnInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WebAuthnInfo proto.InternalMessageInfo
+
+func (m *WebAuthnInfo) GetCredentialID() []byte {
+	if m != nil {
+		return m.CredentialID
+	}
+	return nil
+}
+
+func (m *WebAuthnInfo) GetPublicKey() []byte {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
+func (m *WebAuthnInfo) GetSignCount() uint32 {
+	if m != nil {
+		return m.SignCount
+	}
+	return 0
+}
+
+func (m *WebAuthnInfo) GetLastChallengeTimestamp() int64 {
+	if m != nil {
+		return m.LastChallengeTimestamp
+	}
+	return 0
+}
+
+// GuardianInfo holds details about a guardian. A verified guardian becomes usable once it is seen active on chain,
+// VerifiedTimestamp being the time its otp was last verified
 type GuardianInfo struct {
-	PublicKey  []byte        `protobuf:"bytes,1,opt,name=PublicKey,proto3" json:"PublicKey,omitempty"`
-	PrivateKey []byte        `protobuf:"bytes,2,opt,name=PrivateKey,proto3" json:"PrivateKey,omitempty"`
-	State      GuardianState `protobuf:"varint,3,opt,name=State,proto3,enum=proto.GuardianState" json:"State,omitempty"`
-	OTPData    OTPInfo       `protobuf:"bytes,4,opt,name=OTPData,proto3" json:"OTPData"`
+	PublicKey         []byte          `protobuf:"bytes,1,opt,name=PublicKey,proto3" json:"PublicKey,omitempty"`
+	PrivateKey        []byte          `protobuf:"bytes,2,opt,name=PrivateKey,proto3" json:"PrivateKey,omitempty"`
+	State             GuardianState   `protobuf:"varint,3,opt,name=State,proto3,enum=proto.GuardianState" json:"State,omitempty"`
+	OTPData           OTPInfo         `protobuf:"bytes,4,opt,name=OTPData,proto3" json:"OTPData"`
+	WebAuthnData      WebAuthnInfo    `protobuf:"bytes,5,opt,name=WebAuthnData,proto3" json:"WebAuthnData"`
+	ActivationState   ActivationState `protobuf:"varint,6,opt,name=ActivationState,proto3,enum=proto.ActivationState" json:"ActivationState,omitempty"`
+	VerifiedTimestamp int64           `protobuf:"varint,7,opt,name=VerifiedTimestamp,proto3" json:"VerifiedTimestamp,omitempty"`
 }
 
 func (m *GuardianInfo) Reset()      { *m = GuardianInfo{} }
 func (*GuardianInfo) ProtoMessage() {}
 func (*GuardianInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9abb1e7c7c5082b5, []int{1}
+	return fileDescriptor_9abb1e7c7c5082b5, []int{3}
 }
 func (m *GuardianInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -163,17 +357,45 @@ func (m *GuardianInfo) GetOTPData() OTPInfo {
 	return OTPInfo{}
 }
 
-// UserInfo holds info about both user's guardians and its unique index
+func (m *GuardianInfo) GetWebAuthnData() WebAuthnInfo {
+	if m != nil {
+		return m.WebAuthnData
+	}
+	return WebAuthnInfo{}
+}
+
+func (m *GuardianInfo) GetActivationState() ActivationState {
+	if m != nil {
+		return m.ActivationState
+	}
+	return Untracked
+}
+
+func (m *GuardianInfo) GetVerifiedTimestamp() int64 {
+	if m != nil {
+		return m.VerifiedTimestamp
+	}
+	return 0
+}
+
+// UserInfo holds info about both user's guardians and its unique index.
+// EncryptionKeyID is the id of the managed key the secrets were encrypted with,
+// EncryptionVersion is the scheme used to encrypt them and RecordTag authenticates the whole record
 type UserInfo struct {
-	Index          uint32       `protobuf:"varint,1,opt,name=Index,proto3" json:"Index,omitempty"`
-	FirstGuardian  GuardianInfo `protobuf:"bytes,2,opt,name=FirstGuardian,proto3" json:"FirstGuardian"`
-	SecondGuardian GuardianInfo `protobuf:"bytes,3,opt,name=SecondGuardian,proto3" json:"SecondGuardian"`
+	Index             uint32       `protobuf:"varint,1,opt,name=Index,proto3" json:"Index,omitempty"`
+	FirstGuardian     GuardianInfo `protobuf:"bytes,2,opt,name=FirstGuardian,proto3" json:"FirstGuardian"`
+	SecondGuardian    GuardianInfo `protobuf:"bytes,3,opt,name=SecondGuardian,proto3" json:"SecondGuardian"`
+	SpendingData      []byte       `protobuf:"bytes,4,opt,name=SpendingData,proto3" json:"SpendingData,omitempty"`
+	RecoveryCodes     [][]byte     `protobuf:"bytes,5,rep,name=RecoveryCodes,proto3" json:"RecoveryCodes,omitempty"`
+	EncryptionKeyID   uint32       `protobuf:"varint,6,opt,name=EncryptionKeyID,proto3" json:"EncryptionKeyID,omitempty"`
+	EncryptionVersion uint32       `protobuf:"varint,7,opt,name=EncryptionVersion,proto3" json:"EncryptionVersion,omitempty"`
+	RecordTag         []byte       `protobuf:"bytes,8,opt,name=RecordTag,proto3" json:"RecordTag,omitempty"`
 }
 
 func (m *UserInfo) Reset()      { *m = UserInfo{} }
 func (*UserInfo) ProtoMessage() {}
 func (*UserInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9abb1e7c7c5082b5, []int{2}
+	return fileDescriptor_9abb1e7c7c5082b5, []int{4}
 }
 func (m *UserInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -219,436 +441,2765 @@ func (m *UserInfo) GetSecondGuardian() GuardianInfo {
 	return GuardianInfo{}
 }
 
-func init() {
-	proto.RegisterEnum("proto.GuardianState", GuardianState_name, GuardianState_value)
-	proto.RegisterType((*OTPInfo)(nil), "proto.OTPInfo")
-	proto.RegisterType((*GuardianInfo)(nil), "proto.GuardianInfo")
-	proto.RegisterType((*UserInfo)(nil), "proto.UserInfo")
+func (m *UserInfo) GetSpendingData() []byte {
+	if m != nil {
+		return m.SpendingData
+	}
+	return nil
 }
 
-func init() { proto.RegisterFile("userInfo.proto", fileDescriptor_9abb1e7c7c5082b5) }
-
-var fileDescriptor_9abb1e7c7c5082b5 = []byte{
-	// 400 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x50, 0xc1, 0x6e, 0xd3, 0x40,
-	0x10, 0xdd, 0xa9, 0x93, 0x42, 0xa7, 0xb5, 0x55, 0x2d, 0x95, 0xb0, 0x10, 0x5a, 0xac, 0x9c, 0xac,
-	0x48, 0xb8, 0x52, 0xb8, 0x70, 0x02, 0x51, 0x10, 0xa8, 0x02, 0x11, 0xcb, 0x75, 0x2e, 0xdc, 0xd6,
-	0xce, 0xd6, 0xb5, 0xd4, 0x78, 0x2b, 0x7b, 0x8d, 0xe0, 0xc6, 0x27, 0xf0, 0x11, 0x1c, 0xfa, 0x29,
-	0x3d, 0xe6, 0x98, 0x13, 0x22, 0x9b, 0x0b, 0xc7, 0x7e, 0x02, 0xca, 0xda, 0x81, 0x24, 0x12, 0xea,
-	0x69, 0x67, 0xe6, 0xbd, 0x79, 0xf3, 0xde, 0xa2, 0x53, 0x57, 0xa2, 0x3c, 0x2d, 0xce, 0x65, 0x70,
-	0x55, 0x4a, 0x25, 0x69, 0xd7, 0x3c, 0x8f, 0x9e, 0x66, 0xb9, 0xba, 0xa8, 0x93, 0x20, 0x95, 0x93,
-	0xe3, 0x4c, 0x66, 0xf2, 0xd8, 0x8c, 0x93, 0xfa, 0xdc, 0x74, 0xa6, 0x31, 0x55, 0xb3, 0xd5, 0x1b,
-	0xe1, 0xbd, 0x61, 0x1c, 0x2e, 0x65, 0xe8, 0x21, 0x5a, 0xc3, 0x38, 0x74, 0xc1, 0x03, 0xff, 0x20,
-	0x5a, 0x96, 0xf4, 0x39, 0x3e, 0xfc, 0xc0, 0x2b, 0x15, 0x0f, 0xe3, 0xf0, 0xf5, 0x05, 0x2f, 0x32,
-	0x11, 0xe7, 0x13, 0x51, 0x29, 0x3e, 0xb9, 0x72, 0x77, 0x3c, 0xf0, 0xad, 0xe8, 0x7f, 0x70, 0xef,
-	0x1a, 0xf0, 0xe0, 0x5d, 0xcd, 0xcb, 0x71, 0xce, 0x0b, 0x23, 0xfe, 0x18, 0xf7, 0xc2, 0x3a, 0xb9,
-	0xcc, 0xd3, 0xf7, 0xe2, 0x6b, 0x7b, 0xe2, 0xdf, 0x80, 0x32, 0xc4, 0xb0, 0xcc, 0x3f, 0x73, 0x25,
-	0x96, 0xf0, 0x8e, 0x81, 0xd7, 0x26, 0xb4, 0x8f, 0xdd, 0x33, 0xc5, 0x95, 0x70, 0x2d, 0x0f, 0x7c,
-	0x67, 0x70, 0xd4, 0x98, 0x0f, 0x56, 0x17, 0x0c, 0x16, 0x35, 0x14, 0x1a, 0x98, 0x44, 0x6f, 0xb8,
-	0xe2, 0x6e, 0xc7, 0x03, 0x7f, 0x7f, 0xe0, 0xb4, 0xec, 0x36, 0xe7, 0x49, 0xe7, 0xe6, 0xe7, 0x13,
-	0x12, 0xad, 0x48, 0xbd, 0x1f, 0x80, 0xf7, 0x47, 0xed, 0x57, 0xd2, 0x23, 0xec, 0x9e, 0x16, 0x63,
-	0xf1, 0xc5, 0x58, 0xb4, 0xa3, 0xa6, 0xa1, 0x2f, 0xd1, 0x7e, 0x9b, 0x97, 0x95, 0x5a, 0xdd, 0x33,
-	0x0e, 0xf7, 0x07, 0x0f, 0xb6, 0x6c, 0xac, 0xa9, 0x6f, 0xf2, 0xe9, 0x2b, 0x74, 0xce, 0x44, 0x2a,
-	0x8b, 0xf1, 0x5f, 0x05, 0xeb, 0x2e, 0x85, 0xad, 0x85, 0x7e, 0x1f, 0xed, 0x8d, 0xb8, 0xd4, 0xc6,
-	0xbd, 0x8f, 0x52, 0x8d, 0x2a, 0x9e, 0x5c, 0x8a, 0x43, 0x42, 0x11, 0x77, 0xdb, 0x1a, 0x4e, 0x5e,
-	0x4c, 0xe7, 0x8c, 0xcc, 0xe6, 0x8c, 0xdc, 0xce, 0x19, 0x7c, 0xd3, 0x0c, 0xae, 0x35, 0x83, 0x1b,
-	0xcd, 0x60, 0xaa, 0x19, 0xcc, 0x34, 0x83, 0x5f, 0x9a, 0xc1, 0x6f, 0xcd, 0xc8, 0xad, 0x66, 0xf0,
-	0x7d, 0xc1, 0xc8, 0x74, 0xc1, 0xc8, 0x6c, 0xc1, 0xc8, 0xa7, 0x4e, 0x2a, 0x4b, 0x91, 0xec, 0x1a,
-	0x57, 0xcf, 0xfe, 0x04, 0x00, 0x00, 0xff, 0xff, 0x84, 0x51, 0x9f, 0x19, 0x63, 0x02, 0x00, 0x00,
+func (m *UserInfo) GetRecoveryCodes() [][]byte {
+	if m != nil {
+		return m.RecoveryCodes
+	}
+	return nil
 }
 
-func (x GuardianState) String() string {
-	s, ok := GuardianState_name[int32(x)]
-	if ok {
-		return s
+func (m *UserInfo) GetEncryptionKeyID() uint32 {
+	if m != nil {
+		return m.EncryptionKeyID
 	}
-	return strconv.Itoa(int(x))
+	return 0
 }
-func (this *OTPInfo) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
-	}
 
-	that1, ok := that.(*OTPInfo)
-	if !ok {
-		that2, ok := that.(OTPInfo)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
-	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if !bytes.Equal(this.OTP, that1.OTP) {
-		return false
-	}
-	if this.LastTOTPChangeTimestamp != that1.LastTOTPChangeTimestamp {
-		return false
+func (m *UserInfo) GetEncryptionVersion() uint32 {
+	if m != nil {
+		return m.EncryptionVersion
 	}
-	return true
+	return 0
 }
-func (this *GuardianInfo) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *UserInfo) GetRecordTag() []byte {
+	if m != nil {
+		return m.RecordTag
 	}
+	return nil
+}
 
-	that1, ok := that.(*GuardianInfo)
-	if !ok {
-		that2, ok := that.(GuardianInfo)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+// SpendingLimit holds the daily and weekly caps of a token, as decimal strings
+type SpendingLimit struct {
+	Token  string `protobuf:"bytes,1,opt,name=Token,proto3" json:"Token,omitempty"`
+	Daily  string `protobuf:"bytes,2,opt,name=Daily,proto3" json:"Daily,omitempty"`
+	Weekly string `protobuf:"bytes,3,opt,name=Weekly,proto3" json:"Weekly,omitempty"`
+}
+
+func (m *SpendingLimit) Reset()      { *m = SpendingLimit{} }
+func (*SpendingLimit) ProtoMessage() {}
+func (*SpendingLimit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9abb1e7c7c5082b5, []int{5}
+}
+func (m *SpendingLimit) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SpendingLimit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+	return b[:n], nil
+}
+func (m *SpendingLimit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpendingLimit.Merge(m, src)
+}
+func (m *SpendingLimit) XXX_Size() int {
+	return m.Size()
+}
+func (m *SpendingLimit) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpendingLimit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SpendingLimit proto.InternalMessageInfo
+
+func (m *SpendingLimit) GetToken() string {
+	if m != nil {
+		return m.Token
 	}
-	if !bytes.Equal(this.PublicKey, that1.PublicKey) {
-		return false
+	return ""
+}
+
+func (m *SpendingLimit) GetDaily() string {
+	if m != nil {
+		return m.Daily
 	}
-	if !bytes.Equal(this.PrivateKey, that1.PrivateKey) {
-		return false
+	return ""
+}
+
+func (m *SpendingLimit) GetWeekly() string {
+	if m != nil {
+		return m.Weekly
 	}
-	if this.State != that1.State {
-		return false
+	return ""
+}
+
+// SpendingPolicy holds the spending limits and the trusted receivers set by the user
+type SpendingPolicy struct {
+	Limits              []SpendingLimit `protobuf:"bytes,1,rep,name=Limits,proto3" json:"Limits"`
+	TrustedReceivers    [][]byte        `protobuf:"bytes,2,rep,name=TrustedReceivers,proto3" json:"TrustedReceivers,omitempty"`
+	ActivationTimestamp int64           `protobuf:"varint,3,opt,name=ActivationTimestamp,proto3" json:"ActivationTimestamp,omitempty"`
+}
+
+func (m *SpendingPolicy) Reset()      { *m = SpendingPolicy{} }
+func (*SpendingPolicy) ProtoMessage() {}
+func (*SpendingPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9abb1e7c7c5082b5, []int{6}
+}
+func (m *SpendingPolicy) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SpendingPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
-	if !this.OTPData.Equal(&that1.OTPData) {
-		return false
+	return b[:n], nil
+}
+func (m *SpendingPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpendingPolicy.Merge(m, src)
+}
+func (m *SpendingPolicy) XXX_Size() int {
+	return m.Size()
+}
+func (m *SpendingPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpendingPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SpendingPolicy proto.InternalMessageInfo
+
+func (m *SpendingPolicy) GetLimits() []SpendingLimit {
+	if m != nil {
+		return m.Limits
 	}
-	return true
+	return nil
 }
-func (this *UserInfo) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *SpendingPolicy) GetTrustedReceivers() [][]byte {
+	if m != nil {
+		return m.TrustedReceivers
 	}
+	return nil
+}
 
-	that1, ok := that.(*UserInfo)
-	if !ok {
-		that2, ok := that.(UserInfo)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+func (m *SpendingPolicy) GetActivationTimestamp() int64 {
+	if m != nil {
+		return m.ActivationTimestamp
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if this.Index != that1.Index {
-		return false
-	}
-	if !this.FirstGuardian.Equal(&that1.FirstGuardian) {
-		return false
-	}
-	if !this.SecondGuardian.Equal(&that1.SecondGuardian) {
-		return false
-	}
-	return true
-}
-func (this *OTPInfo) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 6)
-	s = append(s, "&core.OTPInfo{")
-	s = append(s, "OTP: "+fmt.Sprintf("%#v", this.OTP)+",\n")
-	s = append(s, "LastTOTPChangeTimestamp: "+fmt.Sprintf("%#v", this.LastTOTPChangeTimestamp)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+	return 0
 }
-func (this *GuardianInfo) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 8)
-	s = append(s, "&core.GuardianInfo{")
-	s = append(s, "PublicKey: "+fmt.Sprintf("%#v", this.PublicKey)+",\n")
-	s = append(s, "PrivateKey: "+fmt.Sprintf("%#v", this.PrivateKey)+",\n")
-	s = append(s, "State: "+fmt.Sprintf("%#v", this.State)+",\n")
-	s = append(s, "OTPData: "+strings.Replace(this.OTPData.GoString(), `&`, ``, 1)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+
+// SpentAmount holds the amount of a token co-signed during the current day and week
+type SpentAmount struct {
+	Token       string `protobuf:"bytes,1,opt,name=Token,proto3" json:"Token,omitempty"`
+	Day         int64  `protobuf:"varint,2,opt,name=Day,proto3" json:"Day,omitempty"`
+	DailySpent  string `protobuf:"bytes,3,opt,name=DailySpent,proto3" json:"DailySpent,omitempty"`
+	Week        int64  `protobuf:"varint,4,opt,name=Week,proto3" json:"Week,omitempty"`
+	WeeklySpent string `protobuf:"bytes,5,opt,name=WeeklySpent,proto3" json:"WeeklySpent,omitempty"`
 }
-func (this *UserInfo) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 7)
-	s = append(s, "&core.UserInfo{")
-	s = append(s, "Index: "+fmt.Sprintf("%#v", this.Index)+",\n")
-	s = append(s, "FirstGuardian: "+strings.Replace(this.FirstGuardian.GoString(), `&`, ``, 1)+",\n")
-	s = append(s, "SecondGuardian: "+strings.Replace(this.SecondGuardian.GoString(), `&`, ``, 1)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+
+func (m *SpentAmount) Reset()      { *m = SpentAmount{} }
+func (*SpentAmount) ProtoMessage() {}
+func (*SpentAmount) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9abb1e7c7c5082b5, []int{7}
 }
-func valueToGoStringUserInfo(v interface{}, typ string) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
-	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+func (m *SpentAmount) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-func (m *OTPInfo) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (m *SpentAmount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return b[:n], nil
 }
-
-func (m *OTPInfo) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *SpentAmount) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpentAmount.Merge(m, src)
+}
+func (m *SpentAmount) XXX_Size() int {
+	return m.Size()
+}
+func (m *SpentAmount) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpentAmount.DiscardUnknown(m)
 }
 
-func (m *OTPInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.LastTOTPChangeTimestamp != 0 {
-		i = encodeVarintUserInfo(dAtA, i, uint64(m.LastTOTPChangeTimestamp))
-		i--
-		dAtA[i] = 0x10
-	}
-	if len(m.OTP) > 0 {
-		i -= len(m.OTP)
-		copy(dAtA[i:], m.OTP)
-		i = encodeVarintUserInfo(dAtA, i, uint64(len(m.OTP)))
-		i--
-		dAtA[i] = 0xa
+var xxx_messageInfo_SpentAmount proto.InternalMessageInfo
+
+func (m *SpentAmount) GetToken() string {
+	if m != nil {
+		return m.Token
 	}
-	return len(dAtA) - i, nil
+	return ""
 }
 
-func (m *GuardianInfo) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *SpentAmount) GetDay() int64 {
+	if m != nil {
+		return m.Day
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *GuardianInfo) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *SpentAmount) GetDailySpent() string {
+	if m != nil {
+		return m.DailySpent
+	}
+	return ""
 }
 
-func (m *GuardianInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	{
-		size, err := m.OTPData.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
-		}
-		i -= size
-		i = encodeVarintUserInfo(dAtA, i, uint64(size))
-	}
-	i--
-	dAtA[i] = 0x22
-	if m.State != 0 {
-		i = encodeVarintUserInfo(dAtA, i, uint64(m.State))
-		i--
-		dAtA[i] = 0x18
-	}
-	if len(m.PrivateKey) > 0 {
-		i -= len(m.PrivateKey)
-		copy(dAtA[i:], m.PrivateKey)
-		i = encodeVarintUserInfo(dAtA, i, uint64(len(m.PrivateKey)))
-		i--
-		dAtA[i] = 0x12
+func (m *SpentAmount) GetWeek() int64 {
+	if m != nil {
+		return m.Week
 	}
-	if len(m.PublicKey) > 0 {
-		i -= len(m.PublicKey)
-		copy(dAtA[i:], m.PublicKey)
-		i = encodeVarintUserInfo(dAtA, i, uint64(len(m.PublicKey)))
-		i--
-		dAtA[i] = 0xa
+	return 0
+}
+
+func (m *SpentAmount) GetWeeklySpent() string {
+	if m != nil {
+		return m.WeeklySpent
 	}
-	return len(dAtA) - i, nil
+	return ""
 }
 
-func (m *UserInfo) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+// UserSpending holds the active and the pending spending policies along with the tracked spent amounts
+type UserSpending struct {
+	Active  SpendingPolicy  `protobuf:"bytes,1,opt,name=Active,proto3" json:"Active"`
+	Pending *SpendingPolicy `protobuf:"bytes,2,opt,name=Pending,proto3" json:"Pending,omitempty"`
+	Spent   []SpentAmount   `protobuf:"bytes,3,rep,name=Spent,proto3" json:"Spent"`
+}
+
+func (m *UserSpending) Reset()      { *m = UserSpending{} }
+func (*UserSpending) ProtoMessage() {}
+func (*UserSpending) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9abb1e7c7c5082b5, []int{8}
+}
+func (m *UserSpending) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *UserSpending) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return b[:n], nil
 }
-
-func (m *UserInfo) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *UserSpending) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UserSpending.Merge(m, src)
+}
+func (m *UserSpending) XXX_Size() int {
+	return m.Size()
+}
+func (m *UserSpending) XXX_DiscardUnknown() {
+	xxx_messageInfo_UserSpending.DiscardUnknown(m)
 }
 
-func (m *UserInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	{
-		size, err := m.SecondGuardian.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
-		}
-		i -= size
-		i = encodeVarintUserInfo(dAtA, i, uint64(size))
-	}
-	i--
-	dAtA[i] = 0x1a
-	{
-		size, err := m.FirstGuardian.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
-		}
-		i -= size
-		i = encodeVarintUserInfo(dAtA, i, uint64(size))
-	}
-	i--
-	dAtA[i] = 0x12
-	if m.Index != 0 {
-		i = encodeVarintUserInfo(dAtA, i, uint64(m.Index))
-		i--
-		dAtA[i] = 0x8
+var xxx_messageInfo_UserSpending proto.InternalMessageInfo
+
+func (m *UserSpending) GetActive() SpendingPolicy {
+	if m != nil {
+		return m.Active
 	}
-	return len(dAtA) - i, nil
+	return SpendingPolicy{}
 }
 
-func encodeVarintUserInfo(dAtA []byte, offset int, v uint64) int {
-	offset -= sovUserInfo(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *UserSpending) GetPending() *SpendingPolicy {
+	if m != nil {
+		return m.Pending
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return nil
 }
-func (m *OTPInfo) Size() (n int) {
-	if m == nil {
-		return 0
+
+func (m *UserSpending) GetSpent() []SpentAmount {
+	if m != nil {
+		return m.Spent
 	}
-	var l int
-	_ = l
-	l = len(m.OTP)
-	if l > 0 {
-		n += 1 + l + sovUserInfo(uint64(l))
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("proto.GuardianState", GuardianState_name, GuardianState_value)
+	proto.RegisterEnum("proto.ActivationState", ActivationState_name, ActivationState_value)
+	proto.RegisterType((*OTPParams)(nil), "proto.OTPParams")
+	proto.RegisterType((*OTPInfo)(nil), "proto.OTPInfo")
+	proto.RegisterType((*WebAuthnInfo)(nil), "proto.WebAuthnInfo")
+	proto.RegisterType((*GuardianInfo)(nil), "proto.GuardianInfo")
+	proto.RegisterType((*UserInfo)(nil), "proto.UserInfo")
+	proto.RegisterType((*SpendingLimit)(nil), "proto.SpendingLimit")
+	proto.RegisterType((*SpendingPolicy)(nil), "proto.SpendingPolicy")
+	proto.RegisterType((*SpentAmount)(nil), "proto.SpentAmount")
+	proto.RegisterType((*UserSpending)(nil), "proto.UserSpending")
+}
+
+func init() { proto.RegisterFile("userInfo.proto", fileDescriptor_9abb1e7c7c5082b5) }
+
+var fileDescriptor_9abb1e7c7c5082b5 = []byte{
+	// 974 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x55, 0x4b, 0x6f, 0x23, 0x45,
+	0x10, 0x76, 0xfb, 0x95, 0xa4, 0xfc, 0xd8, 0xa1, 0x77, 0x09, 0x23, 0x84, 0x06, 0xcb, 0xe2, 0x60,
+	0x45, 0xe0, 0x20, 0xaf, 0x84, 0xb8, 0xf0, 0xc8, 0xda, 0x80, 0xc2, 0xae, 0xc8, 0xa8, 0x3d, 0xd9,
+	0x95, 0x10, 0x97, 0xf6, 0x4c, 0xc7, 0x6e, 0x65, 0x3c, 0x63, 0xcd, 0xb4, 0x03, 0xbe, 0x71, 0xe1,
+	0xc4, 0x85, 0x3b, 0x37, 0xc4, 0x81, 0x03, 0x3f, 0x64, 0x8f, 0xb9, 0x91, 0x13, 0x22, 0x0e, 0x07,
+	0x8e, 0xfb, 0x13, 0x50, 0x3f, 0x26, 0xe3, 0x71, 0x76, 0xe1, 0xe4, 0xaa, 0xaf, 0xaa, 0xba, 0xbf,
+	0xfa, 0xba, 0xa6, 0x0c, 0xed, 0x65, 0xca, 0x92, 0xe3, 0xe8, 0x2c, 0xee, 0x2f, 0x92, 0x58, 0xc4,
+	0xb8, 0xa6, 0x7e, 0xde, 0x7c, 0x6f, 0xca, 0xc5, 0x6c, 0x39, 0xe9, 0xfb, 0xf1, 0xfc, 0x70, 0x1a,
+	0x4f, 0xe3, 0x43, 0x05, 0x4f, 0x96, 0x67, 0xca, 0x53, 0x8e, 0xb2, 0x74, 0x55, 0xf7, 0x67, 0x04,
+	0x7b, 0x27, 0x9e, 0xeb, 0xd2, 0x84, 0xce, 0x53, 0x8c, 0xa1, 0xea, 0xad, 0x16, 0xcc, 0x46, 0x1d,
+	0xd4, 0xdb, 0x23, 0xca, 0xc6, 0x6f, 0xc1, 0xde, 0x51, 0x38, 0x8d, 0x13, 0x2e, 0x66, 0x73, 0xbb,
+	0xac, 0x02, 0x39, 0x80, 0xf7, 0xa1, 0x3e, 0xe2, 0x53, 0x2e, 0x52, 0xbb, 0xd2, 0x41, 0xbd, 0x16,
+	0x31, 0x9e, 0xc4, 0x5d, 0x96, 0xf0, 0x38, 0xb0, 0xab, 0x1a, 0xd7, 0x9e, 0xbc, 0x61, 0x7c, 0xce,
+	0xbe, 0xb5, 0x6b, 0x0a, 0x55, 0x36, 0xb6, 0x61, 0x67, 0x18, 0x2f, 0x23, 0xc1, 0x12, 0xbb, 0xde,
+	0x41, 0xbd, 0x2a, 0xc9, 0xdc, 0xee, 0x0f, 0x08, 0x76, 0x4e, 0x3c, 0x57, 0x76, 0x89, 0x2d, 0xa8,
+	0x9c, 0x78, 0xae, 0xa2, 0xd6, 0x24, 0xd2, 0xc4, 0x1f, 0xc2, 0x1b, 0x4f, 0x68, 0x2a, 0xbc, 0x13,
+	0xcf, 0x1d, 0xce, 0x68, 0x34, 0x65, 0x1e, 0x9f, 0xb3, 0x54, 0xd0, 0xf9, 0x42, 0xf1, 0xac, 0x90,
+	0x57, 0x85, 0x71, 0x1f, 0xea, 0xba, 0x63, 0xc5, 0xba, 0x31, 0xb0, 0xb4, 0x1a, 0xfd, 0x5b, 0x25,
+	0x1e, 0x55, 0x9f, 0xff, 0xf9, 0x76, 0x89, 0x98, 0xac, 0xee, 0xef, 0x08, 0x9a, 0xcf, 0xd8, 0xe4,
+	0x68, 0x29, 0x66, 0x91, 0x22, 0xd3, 0x85, 0xe6, 0x30, 0x61, 0x01, 0x8b, 0x04, 0xa7, 0xe1, 0xf1,
+	0xc8, 0xb0, 0x2a, 0x60, 0x52, 0x38, 0x77, 0x39, 0x09, 0xb9, 0xff, 0x98, 0xad, 0x14, 0xa1, 0x26,
+	0xc9, 0x01, 0x19, 0x1d, 0xf3, 0x69, 0xa4, 0x3a, 0x35, 0xda, 0xe5, 0x00, 0xfe, 0x00, 0xf6, 0x25,
+	0xf7, 0xe1, 0x8c, 0x86, 0x21, 0x2b, 0x74, 0x56, 0x53, 0x9d, 0xbd, 0x22, 0xfa, 0x65, 0x75, 0xb7,
+	0x6a, 0xd5, 0xba, 0x7f, 0x94, 0xa1, 0xf9, 0xc5, 0x92, 0x26, 0x01, 0xa7, 0x9a, 0x6e, 0x81, 0x0a,
+	0xda, 0xa6, 0xe2, 0x00, 0xb8, 0x09, 0xbf, 0xa0, 0x82, 0xe5, 0x4c, 0x37, 0x10, 0x7c, 0x00, 0xb5,
+	0xb1, 0xa0, 0x82, 0x29, 0x9a, 0xed, 0xc1, 0x03, 0x23, 0x56, 0x76, 0x83, 0x8a, 0x11, 0x9d, 0x82,
+	0xfb, 0xea, 0xc1, 0x46, 0x54, 0x50, 0xf5, 0xf0, 0x8d, 0x41, 0x3b, 0x97, 0x56, 0x52, 0x31, 0xc2,
+	0x66, 0x49, 0xf8, 0xa3, 0x5c, 0x58, 0x55, 0x54, 0x53, 0x45, 0xf7, 0x4d, 0xd1, 0xa6, 0xe6, 0xa6,
+	0xb2, 0x90, 0x8e, 0x3f, 0x85, 0x7b, 0x47, 0xbe, 0x90, 0x4c, 0x79, 0xac, 0x89, 0xa8, 0x11, 0x6a,
+	0x0f, 0xf6, 0xcd, 0x09, 0x5b, 0x51, 0xb2, 0x9d, 0x8e, 0xdf, 0x85, 0xd7, 0x9e, 0xb2, 0x84, 0x9f,
+	0x71, 0x16, 0xe4, 0x22, 0xef, 0x28, 0x91, 0xef, 0x06, 0xba, 0x7f, 0x97, 0x61, 0xf7, 0xd4, 0x7c,
+	0x77, 0xf8, 0x01, 0xd4, 0x8e, 0xa3, 0x80, 0x7d, 0xa7, 0x14, 0x6d, 0x11, 0xed, 0xe0, 0x4f, 0xa0,
+	0xf5, 0x39, 0x4f, 0x52, 0x91, 0xc9, 0x63, 0x97, 0x0b, 0x2d, 0x6d, 0xbe, 0x8b, 0x69, 0xa9, 0x98,
+	0x8f, 0x8f, 0xa0, 0x3d, 0x66, 0x7e, 0x1c, 0x05, 0xb7, 0x27, 0x54, 0xfe, 0xef, 0x84, 0xad, 0x02,
+	0x39, 0x9e, 0xe3, 0x05, 0x8b, 0x02, 0x1e, 0x4d, 0x6f, 0x9f, 0xa2, 0x49, 0x0a, 0x18, 0x7e, 0x07,
+	0x5a, 0x84, 0xf9, 0xf1, 0x05, 0x4b, 0x56, 0xc3, 0x38, 0x60, 0xa9, 0x5d, 0xeb, 0x54, 0x7a, 0x4d,
+	0x52, 0x04, 0x71, 0x0f, 0xee, 0x7d, 0x16, 0xf9, 0xc9, 0x6a, 0x21, 0x15, 0x7b, 0xcc, 0x56, 0xc7,
+	0x23, 0x25, 0x70, 0x8b, 0x6c, 0xc3, 0x52, 0xc8, 0x1c, 0x7a, 0xca, 0x92, 0x94, 0xc7, 0x91, 0x12,
+	0xb2, 0x45, 0xee, 0x06, 0xe4, 0x44, 0xca, 0x8b, 0x92, 0xc0, 0xa3, 0x53, 0x7b, 0x57, 0x4f, 0xe4,
+	0x2d, 0xd0, 0x1d, 0x43, 0x2b, 0xe3, 0xfa, 0x84, 0xcf, 0xb9, 0x90, 0x52, 0x7b, 0xf1, 0x39, 0x8b,
+	0xcc, 0x66, 0xd2, 0x8e, 0x44, 0x47, 0x94, 0x87, 0x2b, 0xb3, 0x96, 0xb4, 0x23, 0x57, 0xcf, 0x33,
+	0xc6, 0xce, 0xc3, 0x95, 0xd2, 0x6d, 0x8f, 0x18, 0xaf, 0xfb, 0x0b, 0x82, 0x76, 0x76, 0xaa, 0x1b,
+	0x87, 0xdc, 0x5f, 0xe1, 0x01, 0xd4, 0xd5, 0xf9, 0xa9, 0x8d, 0x3a, 0x95, 0x5e, 0xe3, 0x76, 0xb4,
+	0x0b, 0x97, 0x67, 0xbb, 0x40, 0x67, 0xe2, 0x03, 0xb0, 0xbc, 0x64, 0x99, 0x0a, 0x16, 0x10, 0xe6,
+	0x33, 0x7e, 0xc1, 0x92, 0xd4, 0x2e, 0x2b, 0xe9, 0xee, 0xe0, 0xf8, 0x7d, 0xb8, 0x9f, 0xcf, 0x5b,
+	0x3e, 0x5e, 0x15, 0x35, 0x5e, 0x2f, 0x0b, 0x75, 0x7f, 0x44, 0xd0, 0x90, 0xb7, 0x8b, 0xa3, 0xb9,
+	0x5a, 0x04, 0x2f, 0x6f, 0xdc, 0x82, 0xca, 0x88, 0xae, 0xcc, 0x96, 0x93, 0xa6, 0xfc, 0x86, 0x55,
+	0xf7, 0xaa, 0xd6, 0x34, 0xbe, 0x81, 0xc8, 0xbd, 0x2b, 0x65, 0x50, 0x93, 0x50, 0x21, 0xca, 0xc6,
+	0x1d, 0x68, 0x68, 0x69, 0x74, 0x51, 0x4d, 0x15, 0x6d, 0x42, 0xdd, 0x5f, 0x11, 0x34, 0xe5, 0xb8,
+	0x67, 0x7a, 0xe0, 0x87, 0x50, 0x57, 0xac, 0xf5, 0x5f, 0x44, 0x63, 0xf0, 0xfa, 0x96, 0x60, 0x5a,
+	0xd7, 0x4c, 0x31, 0x9d, 0x8a, 0x0f, 0x61, 0xc7, 0xd5, 0x61, 0xbb, 0xfc, 0x1f, 0x55, 0x24, 0xcb,
+	0xc2, 0x7d, 0xa8, 0x65, 0x7d, 0xc8, 0x57, 0xc1, 0x1b, 0xe9, 0x46, 0x17, 0x73, 0x83, 0x4e, 0x3b,
+	0x38, 0x80, 0x56, 0x61, 0x19, 0xe1, 0x16, 0xec, 0x7d, 0x15, 0x8b, 0xd3, 0x94, 0x4e, 0x42, 0x66,
+	0x95, 0x30, 0x40, 0xdd, 0xd8, 0xe8, 0xe0, 0x9b, 0x3b, 0x1b, 0x43, 0x66, 0x9f, 0x46, 0x22, 0xa1,
+	0xfe, 0x39, 0x0b, 0xac, 0x12, 0x6e, 0xc2, 0x6e, 0xf6, 0xe1, 0x5b, 0x08, 0x63, 0x68, 0x1b, 0x5a,
+	0x27, 0xd1, 0x70, 0x46, 0x79, 0x64, 0x95, 0xe5, 0x79, 0xba, 0x35, 0xab, 0x22, 0xb3, 0x09, 0x5b,
+	0x84, 0xd4, 0x67, 0x81, 0x55, 0x7d, 0xf4, 0xf1, 0xe5, 0xb5, 0x53, 0xba, 0xba, 0x76, 0x4a, 0x2f,
+	0xae, 0x1d, 0xf4, 0xfd, 0xda, 0x41, 0xbf, 0xad, 0x1d, 0xf4, 0x7c, 0xed, 0xa0, 0xcb, 0xb5, 0x83,
+	0xae, 0xd6, 0x0e, 0xfa, 0x6b, 0xed, 0xa0, 0x7f, 0xd6, 0x4e, 0xe9, 0xc5, 0xda, 0x41, 0x3f, 0xdd,
+	0x38, 0xa5, 0xcb, 0x1b, 0xa7, 0x74, 0x75, 0xe3, 0x94, 0xbe, 0xae, 0xfa, 0x71, 0xc2, 0x26, 0x75,
+	0xd5, 0xe9, 0xc3, 0x7f, 0x07, 0x00, 0x90, 0x3e, 0x7b, 0x5e, 0xdd, 0x07, 0x00, 0x00,
+}
+
+func (x GuardianState) String() string {
+	s, ok := GuardianState_name[int32(x)]
+	if ok {
+		return s
 	}
-	if m.LastTOTPChangeTimestamp != 0 {
-		n += 1 + sovUserInfo(uint64(m.LastTOTPChangeTimestamp))
+	return strconv.Itoa(int(x))
+}
+func (x ActivationState) String() string {
+	s, ok := ActivationState_name[int32(x)]
+	if ok {
+		return s
 	}
-	return n
+	return strconv.Itoa(int(x))
 }
+func (this *OTPParams) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *GuardianInfo) Size() (n int) {
-	if m == nil {
-		return 0
+	that1, ok := that.(*OTPParams)
+	if !ok {
+		that2, ok := that.(OTPParams)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	var l int
-	_ = l
-	l = len(m.PublicKey)
-	if l > 0 {
-		n += 1 + l + sovUserInfo(uint64(l))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	l = len(m.PrivateKey)
-	if l > 0 {
-		n += 1 + l + sovUserInfo(uint64(l))
+	if this.Type != that1.Type {
+		return false
 	}
-	if m.State != 0 {
-		n += 1 + sovUserInfo(uint64(m.State))
+	if this.Algorithm != that1.Algorithm {
+		return false
 	}
-	l = m.OTPData.Size()
-	n += 1 + l + sovUserInfo(uint64(l))
-	return n
+	if this.Digits != that1.Digits {
+		return false
+	}
+	if this.Period != that1.Period {
+		return false
+	}
+	if this.Skew != that1.Skew {
+		return false
+	}
+	if this.Counter != that1.Counter {
+		return false
+	}
+	return true
 }
+func (this *OTPInfo) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *UserInfo) Size() (n int) {
-	if m == nil {
-		return 0
+	that1, ok := that.(*OTPInfo)
+	if !ok {
+		that2, ok := that.(OTPInfo)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	var l int
-	_ = l
-	if m.Index != 0 {
-		n += 1 + sovUserInfo(uint64(m.Index))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	l = m.FirstGuardian.Size()
-	n += 1 + l + sovUserInfo(uint64(l))
-	l = m.SecondGuardian.Size()
-	n += 1 + l + sovUserInfo(uint64(l))
-	return n
+	if !bytes.Equal(this.OTP, that1.OTP) {
+		return false
+	}
+	if this.LastTOTPChangeTimestamp != that1.LastTOTPChangeTimestamp {
+		return false
+	}
+	if !this.Params.Equal(&that1.Params) {
+		return false
+	}
+	return true
 }
+func (this *WebAuthnInfo) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func sovUserInfo(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
+	that1, ok := that.(*WebAuthnInfo)
+	if !ok {
+		that2, ok := that.(WebAuthnInfo)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.CredentialID, that1.CredentialID) {
+		return false
+	}
+	if !bytes.Equal(this.PublicKey, that1.PublicKey) {
+		return false
+	}
+	if this.SignCount != that1.SignCount {
+		return false
+	}
+	if this.LastChallengeTimestamp != that1.LastChallengeTimestamp {
+		return false
+	}
+	return true
 }
-func sozUserInfo(x uint64) (n int) {
-	return sovUserInfo(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+func (this *GuardianInfo) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*GuardianInfo)
+	if !ok {
+		that2, ok := that.(GuardianInfo)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.PublicKey, that1.PublicKey) {
+		return false
+	}
+	if !bytes.Equal(this.PrivateKey, that1.PrivateKey) {
+		return false
+	}
+	if this.State != that1.State {
+		return false
+	}
+	if !this.OTPData.Equal(&that1.OTPData) {
+		return false
+	}
+	if !this.WebAuthnData.Equal(&that1.WebAuthnData) {
+		return false
+	}
+	if this.ActivationState != that1.ActivationState {
+		return false
+	}
+	if this.VerifiedTimestamp != that1.VerifiedTimestamp {
+		return false
+	}
+	return true
 }
-func (this *OTPInfo) String() string {
-	if this == nil {
-		return "nil"
+func (this *UserInfo) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	s := strings.Join([]string{`&OTPInfo{`,
-		`OTP:` + fmt.Sprintf("%v", this.OTP) + `,`,
-		`LastTOTPChangeTimestamp:` + fmt.Sprintf("%v", this.LastTOTPChangeTimestamp) + `,`,
-		`}`,
-	}, "")
-	return s
+
+	that1, ok := that.(*UserInfo)
+	if !ok {
+		that2, ok := that.(UserInfo)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Index != that1.Index {
+		return false
+	}
+	if !this.FirstGuardian.Equal(&that1.FirstGuardian) {
+		return false
+	}
+	if !this.SecondGuardian.Equal(&that1.SecondGuardian) {
+		return false
+	}
+	if !bytes.Equal(this.SpendingData, that1.SpendingData) {
+		return false
+	}
+	if len(this.RecoveryCodes) != len(that1.RecoveryCodes) {
+		return false
+	}
+	for i := range this.RecoveryCodes {
+		if !bytes.Equal(this.RecoveryCodes[i], that1.RecoveryCodes[i]) {
+			return false
+		}
+	}
+	if this.EncryptionKeyID != that1.EncryptionKeyID {
+		return false
+	}
+	if this.EncryptionVersion != that1.EncryptionVersion {
+		return false
+	}
+	if !bytes.Equal(this.RecordTag, that1.RecordTag) {
+		return false
+	}
+	return true
 }
-func (this *GuardianInfo) String() string {
-	if this == nil {
-		return "nil"
+func (this *SpendingLimit) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	s := strings.Join([]string{`&GuardianInfo{`,
-		`PublicKey:` + fmt.Sprintf("%v", this.PublicKey) + `,`,
-		`PrivateKey:` + fmt.Sprintf("%v", this.PrivateKey) + `,`,
-		`State:` + fmt.Sprintf("%v", this.State) + `,`,
-		`OTPData:` + strings.Replace(strings.Replace(this.OTPData.String(), "OTPInfo", "OTPInfo", 1), `&`, ``, 1) + `,`,
-		`}`,
-	}, "")
-	return s
+
+	that1, ok := that.(*SpendingLimit)
+	if !ok {
+		that2, ok := that.(SpendingLimit)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Token != that1.Token {
+		return false
+	}
+	if this.Daily != that1.Daily {
+		return false
+	}
+	if this.Weekly != that1.Weekly {
+		return false
+	}
+	return true
 }
-func (this *UserInfo) String() string {
-	if this == nil {
-		return "nil"
+func (this *SpendingPolicy) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	s := strings.Join([]string{`&UserInfo{`,
-		`Index:` + fmt.Sprintf("%v", this.Index) + `,`,
-		`FirstGuardian:` + strings.Replace(strings.Replace(this.FirstGuardian.String(), "GuardianInfo", "GuardianInfo", 1), `&`, ``, 1) + `,`,
-		`SecondGuardian:` + strings.Replace(strings.Replace(this.SecondGuardian.String(), "GuardianInfo", "GuardianInfo", 1), `&`, ``, 1) + `,`,
-		`}`,
-	}, "")
-	return s
+
+	that1, ok := that.(*SpendingPolicy)
+	if !ok {
+		that2, ok := that.(SpendingPolicy)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Limits) != len(that1.Limits) {
+		return false
+	}
+	for i := range this.Limits {
+		if !this.Limits[i].Equal(&that1.Limits[i]) {
+			return false
+		}
+	}
+	if len(this.TrustedReceivers) != len(that1.TrustedReceivers) {
+		return false
+	}
+	for i := range this.TrustedReceivers {
+		if !bytes.Equal(this.TrustedReceivers[i], that1.TrustedReceivers[i]) {
+			return false
+		}
+	}
+	if this.ActivationTimestamp != that1.ActivationTimestamp {
+		return false
+	}
+	return true
 }
-func valueToStringUserInfo(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
+func (this *SpentAmount) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*SpentAmount)
+	if !ok {
+		that2, ok := that.(SpentAmount)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Token != that1.Token {
+		return false
+	}
+	if this.Day != that1.Day {
+		return false
+	}
+	if this.DailySpent != that1.DailySpent {
+		return false
+	}
+	if this.Week != that1.Week {
+		return false
+	}
+	if this.WeeklySpent != that1.WeeklySpent {
+		return false
+	}
+	return true
+}
+func (this *UserSpending) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*UserSpending)
+	if !ok {
+		that2, ok := that.(UserSpending)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.Active.Equal(&that1.Active) {
+		return false
+	}
+	if !this.Pending.Equal(that1.Pending) {
+		return false
+	}
+	if len(this.Spent) != len(that1.Spent) {
+		return false
+	}
+	for i := range this.Spent {
+		if !this.Spent[i].Equal(&that1.Spent[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *OTPParams) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 10)
+	s = append(s, "&core.OTPParams{")
+	s = append(s, "Type: "+fmt.Sprintf("%#v", this.Type)+",\n")
+	s = append(s, "Algorithm: "+fmt.Sprintf("%#v", this.Algorithm)+",\n")
+	s = append(s, "Digits: "+fmt.Sprintf("%#v", this.Digits)+",\n")
+	s = append(s, "Period: "+fmt.Sprintf("%#v", this.Period)+",\n")
+	s = append(s, "Skew: "+fmt.Sprintf("%#v", this.Skew)+",\n")
+	s = append(s, "Counter: "+fmt.Sprintf("%#v", this.Counter)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *OTPInfo) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&core.OTPInfo{")
+	s = append(s, "OTP: "+fmt.Sprintf("%#v", this.OTP)+",\n")
+	s = append(s, "LastTOTPChangeTimestamp: "+fmt.Sprintf("%#v", this.LastTOTPChangeTimestamp)+",\n")
+	s = append(s, "Params: "+strings.Replace(this.Params.GoString(), `&`, ``, 1)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *WebAuthnInfo) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&core.WebAuthnInfo{")
+	s = append(s, "CredentialID: "+fmt.Sprintf("%#v", this.CredentialID)+",\n")
+	s = append(s, "PublicKey: "+fmt.Sprintf("%#v", this.PublicKey)+",\n")
+	s = append(s, "SignCount: "+fmt.Sprintf("%#v", this.SignCount)+",\n")
+	s = append(s, "LastChallengeTimestamp: "+fmt.Sprintf("%#v", this.LastChallengeTimestamp)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *GuardianInfo) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 11)
+	s = append(s, "&core.GuardianInfo{")
+	s = append(s, "PublicKey: "+fmt.Sprintf("%#v", this.PublicKey)+",\n")
+	s = append(s, "PrivateKey: "+fmt.Sprintf("%#v", this.PrivateKey)+",\n")
+	s = append(s, "State: "+fmt.Sprintf("%#v", this.State)+",\n")
+	s = append(s, "OTPData: "+strings.Replace(this.OTPData.GoString(), `&`, ``, 1)+",\n")
+	s = append(s, "WebAuthnData: "+strings.Replace(this.WebAuthnData.GoString(), `&`, ``, 1)+",\n")
+	s = append(s, "ActivationState: "+fmt.Sprintf("%#v", this.ActivationState)+",\n")
+	s = append(s, "VerifiedTimestamp: "+fmt.Sprintf("%#v", this.VerifiedTimestamp)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *UserInfo) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 12)
+	s = append(s, "&core.UserInfo{")
+	s = append(s, "Index: "+fmt.Sprintf("%#v", this.Index)+",\n")
+	s = append(s, "FirstGuardian: "+strings.Replace(this.FirstGuardian.GoString(), `&`, ``, 1)+",\n")
+	s = append(s, "SecondGuardian: "+strings.Replace(this.SecondGuardian.GoString(), `&`, ``, 1)+",\n")
+	s = append(s, "SpendingData: "+fmt.Sprintf("%#v", this.SpendingData)+",\n")
+	s = append(s, "RecoveryCodes: "+fmt.Sprintf("%#v", this.RecoveryCodes)+",\n")
+	s = append(s, "EncryptionKeyID: "+fmt.Sprintf("%#v", this.EncryptionKeyID)+",\n")
+	s = append(s, "EncryptionVersion: "+fmt.Sprintf("%#v", this.EncryptionVersion)+",\n")
+	s = append(s, "RecordTag: "+fmt.Sprintf("%#v", this.RecordTag)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *SpendingLimit) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&core.SpendingLimit{")
+	s = append(s, "Token: "+fmt.Sprintf("%#v", this.Token)+",\n")
+	s = append(s, "Daily: "+fmt.Sprintf("%#v", this.Daily)+",\n")
+	s = append(s, "Weekly: "+fmt.Sprintf("%#v", this.Weekly)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *SpendingPolicy) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&core.SpendingPolicy{")
+	if this.Limits != nil {
+		vs := make([]SpendingLimit, len(this.Limits))
+		for i := range vs {
+			vs[i] = this.Limits[i]
+		}
+		s = append(s, "Limits: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "TrustedReceivers: "+fmt.Sprintf("%#v", this.TrustedReceivers)+",\n")
+	s = append(s, "ActivationTimestamp: "+fmt.Sprintf("%#v", this.ActivationTimestamp)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *SpentAmount) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&core.SpentAmount{")
+	s = append(s, "Token: "+fmt.Sprintf("%#v", this.Token)+",\n")
+	s = append(s, "Day: "+fmt.Sprintf("%#v", this.Day)+",\n")
+	s = append(s, "DailySpent: "+fmt.Sprintf("%#v", this.DailySpent)+",\n")
+	s = append(s, "Week: "+fmt.Sprintf("%#v", this.Week)+",\n")
+	s = append(s, "WeeklySpent: "+fmt.Sprintf("%#v", this.WeeklySpent)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *UserSpending) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&core.UserSpending{")
+	s = append(s, "Active: "+strings.Replace(this.Active.GoString(), `&`, ``, 1)+",\n")
+	if this.Pending != nil {
+		s = append(s, "Pending: "+fmt.Sprintf("%#v", this.Pending)+",\n")
+	}
+	if this.Spent != nil {
+		vs := make([]SpentAmount, len(this.Spent))
+		for i := range vs {
+			vs[i] = this.Spent[i]
+		}
+		s = append(s, "Spent: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringUserInfo(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
 		return "nil"
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+func (m *OTPParams) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *OTPParams) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *OTPParams) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Counter != 0 {
+		i = encodeVarintUserInfo(dAtA, i, uint64(m.Counter))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Skew != 0 {
+		i = encodeVarintUserInfo(dAtA, i, uint64(m.Skew))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Period != 0 {
+		i = encodeVarintUserInfo(dAtA, i, uint64(m.Period))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Digits != 0 {
+		i = encodeVarintUserInfo(dAtA, i, uint64(m.Digits))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Algorithm) > 0 {
+		i -= len(m.Algorithm)
+		copy(dAtA[i:], m.Algorithm)
+		i = encodeVarintUserInfo(dAtA, i, uint64(len(m.Algorithm)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Type) > 0 {
+		i -= len(m.Type)
+		copy(dAtA[i:], m.Type)
+		i = encodeVarintUserInfo(dAtA, i, uint64(len(m.Type)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *OTPInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *OTPInfo) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *OTPInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	{
+		size, err := m.Params.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintUserInfo(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x1a
+	if m.LastTOTPChangeTimestamp != 0 {
+		i = encodeVarintUserInfo(dAtA, i, uint64(m.LastTOTPChangeTimestamp))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.OTP) > 0 {
+		i -= len(m.OTP)
+		copy(dAtA[i:], m.OTP)
+		i = encodeVarintUserInfo(dAtA, i, uint64(len(m.OTP)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *WebAuthnInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WebAuthnInfo) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *WebAuthnInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.LastChallengeTimestamp != 0 {
+		i = encodeVarintUserInfo(dAtA, i, uint64(m.LastChallengeTimestamp))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.SignCount != 0 {
+		i = encodeVarintUserInfo(dAtA, i, uint64(m.SignCount))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.PublicKey) > 0 {
+		i -= len(m.PublicKey)
+		copy(dAtA[i:], m.PublicKey)
+		i = encodeVarintUserInfo(dAtA, i, uint64(len(m.PublicKey)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.CredentialID) > 0 {
+		i -= len(m.CredentialID)
+		copy(dAtA[i:], m.CredentialID)
+		i = encodeVarintUserInfo(dAtA, i, uint64(len(m.CredentialID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *GuardianInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GuardianInfo) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GuardianInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.VerifiedTimestamp != 0 {
+		i = encodeVarintUserInfo(dAtA, i, uint64(m.VerifiedTimestamp))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.ActivationState != 0 {
+		i = encodeVarintUserInfo(dAtA, i, uint64(m.ActivationState))
+		i--
+		dAtA[i] = 0x30
+	}
+	{
+		size, err := m.WebAuthnData.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintUserInfo(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x2a
+	{
+		size, err := m.OTPData.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintUserInfo(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x22
+	if m.State != 0 {
+		i = encodeVarintUserInfo(dAtA, i, uint64(m.State))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.PrivateKey) > 0 {
+		i -= len(m.PrivateKey)
+		copy(dAtA[i:], m.PrivateKey)
+		i = encodeVarintUserInfo(dAtA, i, uint64(len(m.PrivateKey)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.PublicKey) > 0 {
+		i -= len(m.PublicKey)
+		copy(dAtA[i:], m.PublicKey)
+		i = encodeVarintUserInfo(dAtA, i, uint64(len(m.PublicKey)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *UserInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UserInfo) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UserInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.RecordTag) > 0 {
+		i -= len(m.RecordTag)
+		copy(dAtA[i:], m.RecordTag)
+		i = encodeVarintUserInfo(dAtA, i, uint64(len(m.RecordTag)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if m.EncryptionVersion != 0 {
+		i = encodeVarintUserInfo(dAtA, i, uint64(m.EncryptionVersion))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.EncryptionKeyID != 0 {
+		i = encodeVarintUserInfo(dAtA, i, uint64(m.EncryptionKeyID))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.RecoveryCodes) > 0 {
+		for iNdEx := len(m.RecoveryCodes) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.RecoveryCodes[iNdEx])
+			copy(dAtA[i:], m.RecoveryCodes[iNdEx])
+			i = encodeVarintUserInfo(dAtA, i, uint64(len(m.RecoveryCodes[iNdEx])))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if len(m.SpendingData) > 0 {
+		i -= len(m.SpendingData)
+		copy(dAtA[i:], m.SpendingData)
+		i = encodeVarintUserInfo(dAtA, i, uint64(len(m.SpendingData)))
+		i--
+		dAtA[i] = 0x22
+	}
+	{
+		size, err := m.SecondGuardian.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintUserInfo(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x1a
+	{
+		size, err := m.FirstGuardian.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintUserInfo(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	if m.Index != 0 {
+		i = encodeVarintUserInfo(dAtA, i, uint64(m.Index))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SpendingLimit) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpendingLimit) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SpendingLimit) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Weekly) > 0 {
+		i -= len(m.Weekly)
+		copy(dAtA[i:], m.Weekly)
+		i = encodeVarintUserInfo(dAtA, i, uint64(len(m.Weekly)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Daily) > 0 {
+		i -= len(m.Daily)
+		copy(dAtA[i:], m.Daily)
+		i = encodeVarintUserInfo(dAtA, i, uint64(len(m.Daily)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Token) > 0 {
+		i -= len(m.Token)
+		copy(dAtA[i:], m.Token)
+		i = encodeVarintUserInfo(dAtA, i, uint64(len(m.Token)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SpendingPolicy) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpendingPolicy) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SpendingPolicy) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.ActivationTimestamp != 0 {
+		i = encodeVarintUserInfo(dAtA, i, uint64(m.ActivationTimestamp))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.TrustedReceivers) > 0 {
+		for iNdEx := len(m.TrustedReceivers) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.TrustedReceivers[iNdEx])
+			copy(dAtA[i:], m.TrustedReceivers[iNdEx])
+			i = encodeVarintUserInfo(dAtA, i, uint64(len(m.TrustedReceivers[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Limits) > 0 {
+		for iNdEx := len(m.Limits) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Limits[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintUserInfo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SpentAmount) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpentAmount) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SpentAmount) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.WeeklySpent) > 0 {
+		i -= len(m.WeeklySpent)
+		copy(dAtA[i:], m.WeeklySpent)
+		i = encodeVarintUserInfo(dAtA, i, uint64(len(m.WeeklySpent)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Week != 0 {
+		i = encodeVarintUserInfo(dAtA, i, uint64(m.Week))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.DailySpent) > 0 {
+		i -= len(m.DailySpent)
+		copy(dAtA[i:], m.DailySpent)
+		i = encodeVarintUserInfo(dAtA, i, uint64(len(m.DailySpent)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Day != 0 {
+		i = encodeVarintUserInfo(dAtA, i, uint64(m.Day))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Token) > 0 {
+		i -= len(m.Token)
+		copy(dAtA[i:], m.Token)
+		i = encodeVarintUserInfo(dAtA, i, uint64(len(m.Token)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *UserSpending) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UserSpending) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UserSpending) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Spent) > 0 {
+		for iNdEx := len(m.Spent) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Spent[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintUserInfo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.Pending != nil {
+		{
+			size, err := m.Pending.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintUserInfo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	{
+		size, err := m.Active.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintUserInfo(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintUserInfo(dAtA []byte, offset int, v uint64) int {
+	offset -= sovUserInfo(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *OTPParams) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Type)
+	if l > 0 {
+		n += 1 + l + sovUserInfo(uint64(l))
+	}
+	l = len(m.Algorithm)
+	if l > 0 {
+		n += 1 + l + sovUserInfo(uint64(l))
+	}
+	if m.Digits != 0 {
+		n += 1 + sovUserInfo(uint64(m.Digits))
+	}
+	if m.Period != 0 {
+		n += 1 + sovUserInfo(uint64(m.Period))
+	}
+	if m.Skew != 0 {
+		n += 1 + sovUserInfo(uint64(m.Skew))
+	}
+	if m.Counter != 0 {
+		n += 1 + sovUserInfo(uint64(m.Counter))
+	}
+	return n
+}
+
+func (m *OTPInfo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.OTP)
+	if l > 0 {
+		n += 1 + l + sovUserInfo(uint64(l))
+	}
+	if m.LastTOTPChangeTimestamp != 0 {
+		n += 1 + sovUserInfo(uint64(m.LastTOTPChangeTimestamp))
+	}
+	l = m.Params.Size()
+	n += 1 + l + sovUserInfo(uint64(l))
+	return n
+}
+
+func (m *WebAuthnInfo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.CredentialID)
+	if l > 0 {
+		n += 1 + l + sovUserInfo(uint64(l))
+	}
+	l = len(m.PublicKey)
+	if l > 0 {
+		n += 1 + l + sovUserInfo(uint64(l))
+	}
+	if m.SignCount != 0 {
+		n += 1 + sovUserInfo(uint64(m.SignCount))
+	}
+	if m.LastChallengeTimestamp != 0 {
+		n += 1 + sovUserInfo(uint64(m.LastChallengeTimestamp))
+	}
+	return n
+}
+
+func (m *GuardianInfo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.PublicKey)
+	if l > 0 {
+		n += 1 + l + sovUserInfo(uint64(l))
+	}
+	l = len(m.PrivateKey)
+	if l > 0 {
+		n += 1 + l + sovUserInfo(uint64(l))
+	}
+	if m.State != 0 {
+		n += 1 + sovUserInfo(uint64(m.State))
+	}
+	l = m.OTPData.Size()
+	n += 1 + l + sovUserInfo(uint64(l))
+	l = m.WebAuthnData.Size()
+	n += 1 + l + sovUserInfo(uint64(l))
+	if m.ActivationState != 0 {
+		n += 1 + sovUserInfo(uint64(m.ActivationState))
+	}
+	if m.VerifiedTimestamp != 0 {
+		n += 1 + sovUserInfo(uint64(m.VerifiedTimestamp))
+	}
+	return n
+}
+
+func (m *UserInfo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Index != 0 {
+		n += 1 + sovUserInfo(uint64(m.Index))
+	}
+	l = m.FirstGuardian.Size()
+	n += 1 + l + sovUserInfo(uint64(l))
+	l = m.SecondGuardian.Size()
+	n += 1 + l + sovUserInfo(uint64(l))
+	l = len(m.SpendingData)
+	if l > 0 {
+		n += 1 + l + sovUserInfo(uint64(l))
+	}
+	if len(m.RecoveryCodes) > 0 {
+		for _, b := range m.RecoveryCodes {
+			l = len(b)
+			n += 1 + l + sovUserInfo(uint64(l))
+		}
+	}
+	if m.EncryptionKeyID != 0 {
+		n += 1 + sovUserInfo(uint64(m.EncryptionKeyID))
+	}
+	if m.EncryptionVersion != 0 {
+		n += 1 + sovUserInfo(uint64(m.EncryptionVersion))
+	}
+	l = len(m.RecordTag)
+	if l > 0 {
+		n += 1 + l + sovUserInfo(uint64(l))
+	}
+	return n
+}
+
+func (m *SpendingLimit) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Token)
+	if l > 0 {
+		n += 1 + l + sovUserInfo(uint64(l))
+	}
+	l = len(m.Daily)
+	if l > 0 {
+		n += 1 + l + sovUserInfo(uint64(l))
+	}
+	l = len(m.Weekly)
+	if l > 0 {
+		n += 1 + l + sovUserInfo(uint64(l))
+	}
+	return n
+}
+
+func (m *SpendingPolicy) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Limits) > 0 {
+		for _, e := range m.Limits {
+			l = e.Size()
+			n += 1 + l + sovUserInfo(uint64(l))
+		}
+	}
+	if len(m.TrustedReceivers) > 0 {
+		for _, b := range m.TrustedReceivers {
+			l = len(b)
+			n += 1 + l + sovUserInfo(uint64(l))
+		}
+	}
+	if m.ActivationTimestamp != 0 {
+		n += 1 + sovUserInfo(uint64(m.ActivationTimestamp))
+	}
+	return n
+}
+
+func (m *SpentAmount) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Token)
+	if l > 0 {
+		n += 1 + l + sovUserInfo(uint64(l))
+	}
+	if m.Day != 0 {
+		n += 1 + sovUserInfo(uint64(m.Day))
+	}
+	l = len(m.DailySpent)
+	if l > 0 {
+		n += 1 + l + sovUserInfo(uint64(l))
+	}
+	if m.Week != 0 {
+		n += 1 + sovUserInfo(uint64(m.Week))
+	}
+	l = len(m.WeeklySpent)
+	if l > 0 {
+		n += 1 + l + sovUserInfo(uint64(l))
+	}
+	return n
+}
+
+func (m *UserSpending) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Active.Size()
+	n += 1 + l + sovUserInfo(uint64(l))
+	if m.Pending != nil {
+		l = m.Pending.Size()
+		n += 1 + l + sovUserInfo(uint64(l))
+	}
+	if len(m.Spent) > 0 {
+		for _, e := range m.Spent {
+			l = e.Size()
+			n += 1 + l + sovUserInfo(uint64(l))
+		}
+	}
+	return n
+}
+
+func sovUserInfo(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozUserInfo(x uint64) (n int) {
+	return sovUserInfo(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *OTPParams) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&OTPParams{`,
+		`Type:` + fmt.Sprintf("%v", this.Type) + `,`,
+		`Algorithm:` + fmt.Sprintf("%v", this.Algorithm) + `,`,
+		`Digits:` + fmt.Sprintf("%v", this.Digits) + `,`,
+		`Period:` + fmt.Sprintf("%v", this.Period) + `,`,
+		`Skew:` + fmt.Sprintf("%v", this.Skew) + `,`,
+		`Counter:` + fmt.Sprintf("%v", this.Counter) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *OTPInfo) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&OTPInfo{`,
+		`OTP:` + fmt.Sprintf("%v", this.OTP) + `,`,
+		`LastTOTPChangeTimestamp:` + fmt.Sprintf("%v", this.LastTOTPChangeTimestamp) + `,`,
+		`Params:` + strings.Replace(strings.Replace(this.Params.String(), "OTPParams", "OTPParams", 1), `&`, ``, 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *WebAuthnInfo) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&WebAuthnInfo{`,
+		`CredentialID:` + fmt.Sprintf("%v", this.CredentialID) + `,`,
+		`PublicKey:` + fmt.Sprintf("%v", this.PublicKey) + `,`,
+		`SignCount:` + fmt.Sprintf("%v", this.SignCount) + `,`,
+		`LastChallengeTimestamp:` + fmt.Sprintf("%v", this.LastChallengeTimestamp) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *GuardianInfo) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&GuardianInfo{`,
+		`PublicKey:` + fmt.Sprintf("%v", this.PublicKey) + `,`,
+		`PrivateKey:` + fmt.Sprintf("%v", this.PrivateKey) + `,`,
+		`State:` + fmt.Sprintf("%v", this.State) + `,`,
+		`OTPData:` + strings.Replace(strings.Replace(this.OTPData.String(), "OTPInfo", "OTPInfo", 1), `&`, ``, 1) + `,`,
+		`WebAuthnData:` + strings.Replace(strings.Replace(this.WebAuthnData.String(), "WebAuthnInfo", "WebAuthnInfo", 1), `&`, ``, 1) + `,`,
+		`ActivationState:` + fmt.Sprintf("%v", this.ActivationState) + `,`,
+		`VerifiedTimestamp:` + fmt.Sprintf("%v", this.VerifiedTimestamp) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *UserInfo) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&UserInfo{`,
+		`Index:` + fmt.Sprintf("%v", this.Index) + `,`,
+		`FirstGuardian:` + strings.Replace(strings.Replace(this.FirstGuardian.String(), "GuardianInfo", "GuardianInfo", 1), `&`, ``, 1) + `,`,
+		`SecondGuardian:` + strings.Replace(strings.Replace(this.SecondGuardian.String(), "GuardianInfo", "GuardianInfo", 1), `&`, ``, 1) + `,`,
+		`SpendingData:` + fmt.Sprintf("%v", this.SpendingData) + `,`,
+		`RecoveryCodes:` + fmt.Sprintf("%v", this.RecoveryCodes) + `,`,
+		`EncryptionKeyID:` + fmt.Sprintf("%v", this.EncryptionKeyID) + `,`,
+		`EncryptionVersion:` + fmt.Sprintf("%v", this.EncryptionVersion) + `,`,
+		`RecordTag:` + fmt.Sprintf("%v", this.RecordTag) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SpendingLimit) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SpendingLimit{`,
+		`Token:` + fmt.Sprintf("%v", this.Token) + `,`,
+		`Daily:` + fmt.Sprintf("%v", this.Daily) + `,`,
+		`Weekly:` + fmt.Sprintf("%v", this.Weekly) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SpendingPolicy) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForLimits := "[]SpendingLimit{"
+	for _, f := range this.Limits {
+		repeatedStringForLimits += strings.Replace(strings.Replace(f.String(), "SpendingLimit", "SpendingLimit", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForLimits += "}"
+	s := strings.Join([]string{`&SpendingPolicy{`,
+		`Limits:` + repeatedStringForLimits + `,`,
+		`TrustedReceivers:` + fmt.Sprintf("%v", this.TrustedReceivers) + `,`,
+		`ActivationTimestamp:` + fmt.Sprintf("%v", this.ActivationTimestamp) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SpentAmount) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SpentAmount{`,
+		`Token:` + fmt.Sprintf("%v", this.Token) + `,`,
+		`Day:` + fmt.Sprintf("%v", this.Day) + `,`,
+		`DailySpent:` + fmt.Sprintf("%v", this.DailySpent) + `,`,
+		`Week:` + fmt.Sprintf("%v", this.Week) + `,`,
+		`WeeklySpent:` + fmt.Sprintf("%v", this.WeeklySpent) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *UserSpending) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForSpent := "[]SpentAmount{"
+	for _, f := range this.Spent {
+		repeatedStringForSpent += strings.Replace(strings.Replace(f.String(), "SpentAmount", "SpentAmount", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForSpent += "}"
+	s := strings.Join([]string{`&UserSpending{`,
+		`Active:` + strings.Replace(strings.Replace(this.Active.String(), "SpendingPolicy", "SpendingPolicy", 1), `&`, ``, 1) + `,`,
+		`Pending:` + strings.Replace(this.Pending.String(), "SpendingPolicy", "SpendingPolicy", 1) + `,`,
+		`Spent:` + repeatedStringForSpent + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringUserInfo(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *OTPParams) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowUserInfo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: OTPParams: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: OTPParams: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Type = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Algorithm", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Algorithm = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Digits", wireType)
+			}
+			m.Digits = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Digits |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Period", wireType)
+			}
+			m.Period = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Period |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Skew", wireType)
+			}
+			m.Skew = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Skew |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Counter", wireType)
+			}
+			m.Counter = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Counter |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipUserInfo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *OTPInfo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowUserInfo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: OTPInfo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: OTPInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OTP", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OTP = append(m.OTP[:0], dAtA[iNdEx:postIndex]...)
+			if m.OTP == nil {
+				m.OTP = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastTOTPChangeTimestamp", wireType)
+			}
+			m.LastTOTPChangeTimestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastTOTPChangeTimestamp |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Params", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Params.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipUserInfo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WebAuthnInfo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowUserInfo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WebAuthnInfo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WebAuthnInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CredentialID", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CredentialID = append(m.CredentialID[:0], dAtA[iNdEx:postIndex]...)
+			if m.CredentialID == nil {
+				m.CredentialID = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PublicKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PublicKey = append(m.PublicKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.PublicKey == nil {
+				m.PublicKey = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SignCount", wireType)
+			}
+			m.SignCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SignCount |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastChallengeTimestamp", wireType)
+			}
+			m.LastChallengeTimestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastChallengeTimestamp |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipUserInfo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GuardianInfo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowUserInfo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GuardianInfo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GuardianInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PublicKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PublicKey = append(m.PublicKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.PublicKey == nil {
+				m.PublicKey = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PrivateKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PrivateKey = append(m.PrivateKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.PrivateKey == nil {
+				m.PrivateKey = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+			}
+			m.State = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.State |= GuardianState(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OTPData", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.OTPData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WebAuthnData", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.WebAuthnData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ActivationState", wireType)
+			}
+			m.ActivationState = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ActivationState |= ActivationState(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field VerifiedTimestamp", wireType)
+			}
+			m.VerifiedTimestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.VerifiedTimestamp |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipUserInfo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UserInfo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowUserInfo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UserInfo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UserInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FirstGuardian", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.FirstGuardian.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SecondGuardian", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.SecondGuardian.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpendingData", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SpendingData = append(m.SpendingData[:0], dAtA[iNdEx:postIndex]...)
+			if m.SpendingData == nil {
+				m.SpendingData = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RecoveryCodes", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RecoveryCodes = append(m.RecoveryCodes, make([]byte, postIndex-iNdEx))
+			copy(m.RecoveryCodes[len(m.RecoveryCodes)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EncryptionKeyID", wireType)
+			}
+			m.EncryptionKeyID = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EncryptionKeyID |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EncryptionVersion", wireType)
+			}
+			m.EncryptionVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EncryptionVersion |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RecordTag", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RecordTag = append(m.RecordTag[:0], dAtA[iNdEx:postIndex]...)
+			if m.RecordTag == nil {
+				m.RecordTag = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipUserInfo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
 }
-func (m *OTPInfo) Unmarshal(dAtA []byte) error {
+func (m *SpendingLimit) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -671,17 +3222,17 @@ func (m *OTPInfo) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: OTPInfo: wiretype end group for non-group")
+			return fmt.Errorf("proto: SpendingLimit: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: OTPInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SpendingLimit: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OTP", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Token", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowUserInfo
@@ -691,31 +3242,29 @@ func (m *OTPInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthUserInfo
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthUserInfo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.OTP = append(m.OTP[:0], dAtA[iNdEx:postIndex]...)
-			if m.OTP == nil {
-				m.OTP = []byte{}
-			}
+			m.Token = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LastTOTPChangeTimestamp", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Daily", wireType)
 			}
-			m.LastTOTPChangeTimestamp = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowUserInfo
@@ -725,11 +3274,56 @@ func (m *OTPInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.LastTOTPChangeTimestamp |= int64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Daily = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Weekly", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Weekly = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipUserInfo(dAtA[iNdEx:])
@@ -754,7 +3348,7 @@ func (m *OTPInfo) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *GuardianInfo) Unmarshal(dAtA []byte) error {
+func (m *SpendingPolicy) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -777,17 +3371,17 @@ func (m *GuardianInfo) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: GuardianInfo: wiretype end group for non-group")
+			return fmt.Errorf("proto: SpendingPolicy: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GuardianInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SpendingPolicy: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field PublicKey", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Limits", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowUserInfo
@@ -797,29 +3391,29 @@ func (m *GuardianInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthUserInfo
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthUserInfo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.PublicKey = append(m.PublicKey[:0], dAtA[iNdEx:postIndex]...)
-			if m.PublicKey == nil {
-				m.PublicKey = []byte{}
+			m.Limits = append(m.Limits, SpendingLimit{})
+			if err := m.Limits[len(m.Limits)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field PrivateKey", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TrustedReceivers", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -846,16 +3440,14 @@ func (m *GuardianInfo) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.PrivateKey = append(m.PrivateKey[:0], dAtA[iNdEx:postIndex]...)
-			if m.PrivateKey == nil {
-				m.PrivateKey = []byte{}
-			}
+			m.TrustedReceivers = append(m.TrustedReceivers, make([]byte, postIndex-iNdEx))
+			copy(m.TrustedReceivers[len(m.TrustedReceivers)-1], dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ActivationTimestamp", wireType)
 			}
-			m.State = 0
+			m.ActivationTimestamp = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowUserInfo
@@ -865,16 +3457,69 @@ func (m *GuardianInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.State |= GuardianState(b&0x7F) << shift
+				m.ActivationTimestamp |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipUserInfo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SpentAmount) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowUserInfo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SpentAmount: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SpentAmount: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OTPData", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Token", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowUserInfo
@@ -884,24 +3529,125 @@ func (m *GuardianInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthUserInfo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthUserInfo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.OTPData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Token = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Day", wireType)
+			}
+			m.Day = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Day |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DailySpent", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DailySpent = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Week", wireType)
+			}
+			m.Week = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Week |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WeeklySpent", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUserInfo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
+			m.WeeklySpent = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -927,7 +3673,7 @@ func (m *GuardianInfo) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *UserInfo) Unmarshal(dAtA []byte) error {
+func (m *UserSpending) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -950,17 +3696,17 @@ func (m *UserInfo) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: UserInfo: wiretype end group for non-group")
+			return fmt.Errorf("proto: UserSpending: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: UserInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: UserSpending: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Active", wireType)
 			}
-			m.Index = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowUserInfo
@@ -970,14 +3716,28 @@ func (m *UserInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Index |= uint32(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthUserInfo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Active.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field FirstGuardian", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Pending", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1004,13 +3764,16 @@ func (m *UserInfo) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.FirstGuardian.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Pending == nil {
+				m.Pending = &SpendingPolicy{}
+			}
+			if err := m.Pending.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SecondGuardian", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Spent", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1037,7 +3800,8 @@ func (m *UserInfo) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.SecondGuardian.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Spent = append(m.Spent, SpentAmount{})
+			if err := m.Spent[len(m.Spent)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -7,3 +7,6 @@ var ErrNilServiceResolver = errors.New("nil service resolver")
 
 // ErrInvalidValue signals that an invalid value was provided
 var ErrInvalidValue = errors.New("invalid value")
+
+// ErrNilAuditLogger signals that a nil audit logger was provided
+var ErrNilAuditLogger = errors.New("nil audit logger")
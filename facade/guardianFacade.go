@@ -2,6 +2,7 @@ package facade
 
 import (
 	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
 	sdkCore "github.com/multiversx/mx-sdk-go/core"
 
 	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
@@ -12,11 +13,13 @@ import (
 type ArgsGuardianFacade struct {
 	ServiceResolver      core.ServiceResolver
 	StatusMetricsHandler core.StatusMetricsHandler
+	AuditLogger          core.AuditLogger
 }
 
 type guardianFacade struct {
 	serviceResolver core.ServiceResolver
 	statusMetrics   core.StatusMetricsHandler
+	auditLogger     core.AuditLogger
 }
 
 // NewGuardianFacade returns a new instance of guardianFacade
@@ -27,22 +30,26 @@ func NewGuardianFacade(args ArgsGuardianFacade) (*guardianFacade, error) {
 	if check.IfNil(args.StatusMetricsHandler) {
 		return nil, core.ErrNilMetricsHandler
 	}
+	if check.IfNil(args.AuditLogger) {
+		return nil, ErrNilAuditLogger
+	}
 
 	return &guardianFacade{
 		serviceResolver: args.ServiceResolver,
 		statusMetrics:   args.StatusMetricsHandler,
+		auditLogger:     args.AuditLogger,
 	}, nil
 }
 
-// VerifyCode validates the code received
-func (gf *guardianFacade) VerifyCode(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error) {
+// VerifyCode validates the code received and returns the recovery codes, if new ones were generated
+func (gf *guardianFacade) VerifyCode(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error) {
 	return gf.serviceResolver.VerifyCode(userAddress, userIp, request)
 }
 
 // RegisterUser creates a new OTP and (optionally) returns some information required
 // for the user to set up the OTP on his end (eg: QR code).
-func (gf *guardianFacade) RegisterUser(userAddress sdkCore.AddressHandler, request requests.RegistrationPayload) (*requests.OTP, string, error) {
-	return gf.serviceResolver.RegisterUser(userAddress, request)
+func (gf *guardianFacade) RegisterUser(userAddress sdkCore.AddressHandler, userIp string, request requests.RegistrationPayload) (*requests.OTP, string, error) {
+	return gf.serviceResolver.RegisterUser(userAddress, userIp, request)
 }
 
 // SignMessage validates user's message, then signs it from guardian and returns the message.
@@ -70,11 +77,97 @@ func (gf *guardianFacade) SignMultipleTransactions(userIp string, request reques
 	return gf.serviceResolver.SignMultipleTransactions(userIp, request)
 }
 
+// SetSpendingPolicy verifies the code and then sets the spending limits and the trusted receivers of the user
+func (gf *guardianFacade) SetSpendingPolicy(userAddress sdkCore.AddressHandler, userIp string, request requests.SetSpendingPolicy) (*requests.OTPCodeVerifyData, error) {
+	return gf.serviceResolver.SetSpendingPolicy(userAddress, userIp, request)
+}
+
+// GetSpendingPolicy returns the spending policies of the user along with the amounts spent in the current day and week
+func (gf *guardianFacade) GetSpendingPolicy(userAddress sdkCore.AddressHandler) (*requests.SpendingPolicyResponse, error) {
+	return gf.serviceResolver.GetSpendingPolicy(userAddress)
+}
+
+// WebAuthnChallenge issues a new challenge for the WebAuthn credential of the guardian
+func (gf *guardianFacade) WebAuthnChallenge(userAddress sdkCore.AddressHandler, userIp string, request requests.WebAuthnChallenge) (*requests.WebAuthnChallengeResponse, *requests.OTPCodeVerifyData, error) {
+	return gf.serviceResolver.WebAuthnChallenge(userAddress, userIp, request)
+}
+
+// RegisterWebAuthn verifies the code and then registers the WebAuthn credential for the guardian
+func (gf *guardianFacade) RegisterWebAuthn(userAddress sdkCore.AddressHandler, userIp string, request requests.RegisterWebAuthn) (*requests.OTPCodeVerifyData, error) {
+	return gf.serviceResolver.RegisterWebAuthn(userAddress, userIp, request)
+}
+
+// RegenerateRecoveryCodes verifies the code and then replaces the recovery codes of the user
+func (gf *guardianFacade) RegenerateRecoveryCodes(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error) {
+	return gf.serviceResolver.RegenerateRecoveryCodes(userAddress, userIp, request)
+}
+
+// DeregisterUser verifies the code and then removes the user, if none of its guardians is set on chain
+func (gf *guardianFacade) DeregisterUser(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error) {
+	return gf.serviceResolver.DeregisterUser(userAddress, userIp, request)
+}
+
 // RegisteredUsers returns the number of registered users
 func (gf *guardianFacade) RegisteredUsers() (uint32, error) {
 	return gf.serviceResolver.RegisteredUsers()
 }
 
+// GetUserState returns the state of the user guardians, without any of their secrets
+func (gf *guardianFacade) GetUserState(userAddress sdkCore.AddressHandler) (*requests.UserStateResponse, error) {
+	return gf.serviceResolver.GetUserState(userAddress)
+}
+
+// ForceUnsetSecurityMode unsets the security mode of the user and resets its failed trials, without requiring any code
+func (gf *guardianFacade) ForceUnsetSecurityMode(userAddress sdkCore.AddressHandler) error {
+	return gf.serviceResolver.ForceUnsetSecurityMode(userAddress)
+}
+
+// ResetRateLimiter resets the failed trials of the user from the provided ip
+func (gf *guardianFacade) ResetRateLimiter(userAddress sdkCore.AddressHandler, userIp string) error {
+	return gf.serviceResolver.ResetRateLimiter(userAddress, userIp)
+}
+
+// ReEncryptUser re-encrypts the secrets of the user with the current encryption key
+func (gf *guardianFacade) ReEncryptUser(userAddress sdkCore.AddressHandler) error {
+	return gf.serviceResolver.ReEncryptUser(userAddress)
+}
+
+// StartReEncryption starts re-encrypting in background all the users which are not encrypted with the current key
+func (gf *guardianFacade) StartReEncryption() error {
+	return gf.serviceResolver.StartReEncryption()
+}
+
+// GetReEncryptionStatus returns the progress of the last re-encryption of all the users
+func (gf *guardianFacade) GetReEncryptionStatus() *requests.ReEncryptionStatusResponse {
+	return gf.serviceResolver.GetReEncryptionStatus()
+}
+
+// MarkGuardianNotUsable marks the provided guardian of the user as not usable
+func (gf *guardianFacade) MarkGuardianNotUsable(userAddress sdkCore.AddressHandler, guardian string) error {
+	return gf.serviceResolver.MarkGuardianNotUsable(userAddress, guardian)
+}
+
+// ExportUserData returns all the data held for the user which can be inspected by an operator
+func (gf *guardianFacade) ExportUserData(userAddress sdkCore.AddressHandler) (*requests.UserAuditDataResponse, error) {
+	return gf.serviceResolver.ExportUserData(userAddress)
+}
+
+// GetHistory returns the signing history of the user, newest entries first
+func (gf *guardianFacade) GetHistory(userAddress sdkCore.AddressHandler, offset uint32, limit uint32) (*requests.UserHistoryResponse, error) {
+	return gf.serviceResolver.GetHistory(userAddress, offset, limit)
+}
+
+// GetGuardianStatus returns the usability and the on chain activation progress of the user guardians, along with
+// the remaining verification trials and the security mode of the user
+func (gf *guardianFacade) GetGuardianStatus(userAddress sdkCore.AddressHandler, userIp string) (*requests.GuardianStatusResponse, error) {
+	return gf.serviceResolver.GetGuardianStatus(userAddress, userIp)
+}
+
+// PreviewTransactions validates the transactions and returns their human-readable summary, without requiring a code
+func (gf *guardianFacade) PreviewTransactions(request requests.SignMultipleTransactions) (*requests.PreviewTransactionsResponse, error) {
+	return gf.serviceResolver.PreviewTransactions(request)
+}
+
 // TcsConfig returns the current configuration of the TCS
 func (gf *guardianFacade) TcsConfig() *core.TcsConfig {
 	return gf.serviceResolver.TcsConfig()
@@ -90,6 +183,11 @@ func (gf *guardianFacade) GetMetricsForPrometheus() string {
 	return gf.statusMetrics.GetMetricsForPrometheus()
 }
 
+// RecordAuditEntry will append the entry to the audit log, along with the hashes of the provided transactions
+func (gf *guardianFacade) RecordAuditEntry(entry core.AuditEntry, txs []transaction.FrontendTransaction) {
+	gf.auditLogger.Record(entry, txs)
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (gf *guardianFacade) IsInterfaceNil() bool {
 	return gf == nil
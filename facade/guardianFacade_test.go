@@ -19,6 +19,7 @@ func createMockArguments() ArgsGuardianFacade {
 	return ArgsGuardianFacade{
 		ServiceResolver:      &testscommon.ServiceResolverStub{},
 		StatusMetricsHandler: &testscommon.StatusMetricsStub{},
+		AuditLogger:          &testscommon.AuditLoggerStub{},
 	}
 }
 
@@ -47,6 +48,17 @@ func TestNewGuardianFacade(t *testing.T) {
 		assert.True(t, errors.Is(err, core.ErrNilMetricsHandler))
 	})
 
+	t.Run("nil audit logger", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArguments()
+		args.AuditLogger = nil
+
+		facadeInstance, err := NewGuardianFacade(args)
+		assert.Nil(t, facadeInstance)
+		assert.True(t, errors.Is(err, ErrNilAuditLogger))
+	})
+
 	t.Run("should work", func(t *testing.T) {
 		t.Parallel()
 
@@ -113,14 +125,47 @@ func TestGuardianFacade_Getters(t *testing.T) {
 	}
 	wasUnsetSecurityModeNoExpireCalled := false
 
+	providedSetSpendingPolicyRequest := requests.SetSpendingPolicy{
+		Code:     "123456",
+		Guardian: "guardian",
+		Limits:   []requests.SpendingLimit{{Token: "EGLD", Daily: "100"}},
+	}
+	wasSetSpendingPolicyCalled := false
+	expectedSpendingPolicy := &requests.SpendingPolicyResponse{
+		Active: requests.SpendingPolicy{
+			Limits: []requests.SpendingLimit{{Token: "EGLD", Daily: "100"}},
+		},
+	}
+	wasGetSpendingPolicyCalled := false
+
+	providedWebAuthnChallengeRequest := requests.WebAuthnChallenge{
+		Guardian: "guardian",
+	}
+	expectedWebAuthnChallenge := &requests.WebAuthnChallengeResponse{
+		Challenge: "challenge",
+	}
+	wasWebAuthnChallengeCalled := false
+	providedRegisterWebAuthnRequest := requests.RegisterWebAuthn{
+		Code:     "123456",
+		Guardian: "guardian",
+	}
+	wasRegisterWebAuthnCalled := false
+
+	expectedRecoveryCodes := &requests.RecoveryCodesResponse{
+		RecoveryCodes: []string{"AAAA-BBBB-CCCC-DDDD"},
+	}
+	wasRegenerateRecoveryCodesCalled := false
+	wasDeregisterUserCalled := false
+
 	args.ServiceResolver = &testscommon.ServiceResolverStub{
-		VerifyCodeCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error) {
+		VerifyCodeCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error) {
 			assert.Equal(t, providedVerifyCodeReq, request)
 			wasVerifyCodeCalled = true
-			return nil, nil
+			return nil, nil, nil
 		},
-		RegisterUserCalled: func(userAddress sdkCore.AddressHandler, request requests.RegistrationPayload) (*requests.OTP, string, error) {
+		RegisterUserCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.RegistrationPayload) (*requests.OTP, string, error) {
 			assert.Equal(t, providedUserAddress, userAddress)
+			assert.Equal(t, providedIp, userIp)
 			wasRegisterUserCalled = true
 			return expectedOtpInfo, expectedGuardian, nil
 		},
@@ -154,6 +199,46 @@ func TestGuardianFacade_Getters(t *testing.T) {
 			wasSignMultipleTransactionCalled = true
 			return expectedSignMultipleTxsResponse, nil, nil
 		},
+		SetSpendingPolicyCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.SetSpendingPolicy) (*requests.OTPCodeVerifyData, error) {
+			assert.Equal(t, providedUserAddress, userAddress)
+			assert.Equal(t, providedIp, userIp)
+			assert.Equal(t, providedSetSpendingPolicyRequest, request)
+			wasSetSpendingPolicyCalled = true
+			return nil, nil
+		},
+		GetSpendingPolicyCalled: func(userAddress sdkCore.AddressHandler) (*requests.SpendingPolicyResponse, error) {
+			assert.Equal(t, providedUserAddress, userAddress)
+			wasGetSpendingPolicyCalled = true
+			return expectedSpendingPolicy, nil
+		},
+		WebAuthnChallengeCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.WebAuthnChallenge) (*requests.WebAuthnChallengeResponse, *requests.OTPCodeVerifyData, error) {
+			assert.Equal(t, providedUserAddress, userAddress)
+			assert.Equal(t, providedIp, userIp)
+			assert.Equal(t, providedWebAuthnChallengeRequest, request)
+			wasWebAuthnChallengeCalled = true
+			return expectedWebAuthnChallenge, nil, nil
+		},
+		RegisterWebAuthnCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.RegisterWebAuthn) (*requests.OTPCodeVerifyData, error) {
+			assert.Equal(t, providedUserAddress, userAddress)
+			assert.Equal(t, providedIp, userIp)
+			assert.Equal(t, providedRegisterWebAuthnRequest, request)
+			wasRegisterWebAuthnCalled = true
+			return nil, nil
+		},
+		RegenerateRecoveryCodesCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error) {
+			assert.Equal(t, providedUserAddress, userAddress)
+			assert.Equal(t, providedIp, userIp)
+			assert.Equal(t, providedVerifyCodeReq, request)
+			wasRegenerateRecoveryCodesCalled = true
+			return expectedRecoveryCodes, nil, nil
+		},
+		DeregisterUserCalled: func(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error) {
+			assert.Equal(t, providedUserAddress, userAddress)
+			assert.Equal(t, providedIp, userIp)
+			assert.Equal(t, providedVerifyCodeReq, request)
+			wasDeregisterUserCalled = true
+			return nil, nil
+		},
 		RegisteredUsersCalled: func() (uint32, error) {
 			wasRegisteredUsersCalled = true
 			return providedCount, nil
@@ -179,11 +264,11 @@ func TestGuardianFacade_Getters(t *testing.T) {
 	}
 	facadeInstance, _ := NewGuardianFacade(args)
 
-	_, err := facadeInstance.VerifyCode(providedUserAddress, "userIp", providedVerifyCodeReq)
+	_, _, err := facadeInstance.VerifyCode(providedUserAddress, "userIp", providedVerifyCodeReq)
 	assert.Nil(t, err)
 	assert.True(t, wasVerifyCodeCalled)
 
-	otpInfo, guardian, err := facadeInstance.RegisterUser(providedUserAddress, requests.RegistrationPayload{})
+	otpInfo, guardian, err := facadeInstance.RegisterUser(providedUserAddress, providedIp, requests.RegistrationPayload{})
 	assert.Nil(t, err)
 	assert.Equal(t, expectedOtpInfo, otpInfo)
 	assert.Equal(t, expectedGuardian, guardian)
@@ -212,6 +297,33 @@ func TestGuardianFacade_Getters(t *testing.T) {
 	assert.Equal(t, expectedSignMultipleTxsResponse, signedTxs)
 	assert.True(t, wasSignMultipleTransactionCalled)
 
+	_, err = facadeInstance.SetSpendingPolicy(providedUserAddress, providedIp, providedSetSpendingPolicyRequest)
+	assert.Nil(t, err)
+	assert.True(t, wasSetSpendingPolicyCalled)
+
+	spendingPolicy, err := facadeInstance.GetSpendingPolicy(providedUserAddress)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedSpendingPolicy, spendingPolicy)
+	assert.True(t, wasGetSpendingPolicyCalled)
+
+	webAuthnChallenge, _, err := facadeInstance.WebAuthnChallenge(providedUserAddress, providedIp, providedWebAuthnChallengeRequest)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedWebAuthnChallenge, webAuthnChallenge)
+	assert.True(t, wasWebAuthnChallengeCalled)
+
+	_, err = facadeInstance.RegisterWebAuthn(providedUserAddress, providedIp, providedRegisterWebAuthnRequest)
+	assert.Nil(t, err)
+	assert.True(t, wasRegisterWebAuthnCalled)
+
+	recoveryCodes, _, err := facadeInstance.RegenerateRecoveryCodes(providedUserAddress, providedIp, providedVerifyCodeReq)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedRecoveryCodes, recoveryCodes)
+	assert.True(t, wasRegenerateRecoveryCodesCalled)
+
+	_, err = facadeInstance.DeregisterUser(providedUserAddress, providedIp, providedVerifyCodeReq)
+	assert.Nil(t, err)
+	assert.True(t, wasDeregisterUserCalled)
+
 	count, err := facadeInstance.RegisteredUsers()
 	assert.Nil(t, err)
 	assert.Equal(t, providedCount, count)
@@ -226,6 +338,107 @@ func TestGuardianFacade_Getters(t *testing.T) {
 	assert.True(t, wasGetMetricsForPrometheusCalled)
 }
 
+func TestGuardianFacade_AdminMethods(t *testing.T) {
+	t.Parallel()
+
+	providedUserAddress, _ := data.NewAddressFromBech32String("erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th")
+	expectedUserState := &requests.UserStateResponse{
+		Address: "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th",
+	}
+	expectedAuditData := &requests.UserAuditDataResponse{
+		ExportTimestamp: 1000,
+	}
+	expectedReEncryptionStatus := &requests.ReEncryptionStatusResponse{
+		NumReEncrypted: 10,
+	}
+	calledMethods := make(map[string]bool)
+
+	args := createMockArguments()
+	args.ServiceResolver = &testscommon.ServiceResolverStub{
+		GetUserStateCalled: func(userAddress sdkCore.AddressHandler) (*requests.UserStateResponse, error) {
+			assert.Equal(t, providedUserAddress, userAddress)
+			calledMethods["GetUserState"] = true
+			return expectedUserState, nil
+		},
+		ForceUnsetSecurityModeCalled: func(userAddress sdkCore.AddressHandler) error {
+			assert.Equal(t, providedUserAddress, userAddress)
+			calledMethods["ForceUnsetSecurityMode"] = true
+			return nil
+		},
+		ResetRateLimiterCalled: func(userAddress sdkCore.AddressHandler, userIp string) error {
+			assert.Equal(t, providedUserAddress, userAddress)
+			assert.Equal(t, "127.0.0.1", userIp)
+			calledMethods["ResetRateLimiter"] = true
+			return nil
+		},
+		MarkGuardianNotUsableCalled: func(userAddress sdkCore.AddressHandler, guardian string) error {
+			assert.Equal(t, providedUserAddress, userAddress)
+			assert.Equal(t, "guardian", guardian)
+			calledMethods["MarkGuardianNotUsable"] = true
+			return nil
+		},
+		ReEncryptUserCalled: func(userAddress sdkCore.AddressHandler) error {
+			assert.Equal(t, providedUserAddress, userAddress)
+			calledMethods["ReEncryptUser"] = true
+			return nil
+		},
+		StartReEncryptionCalled: func() error {
+			calledMethods["StartReEncryption"] = true
+			return nil
+		},
+		GetReEncryptionStatusCalled: func() *requests.ReEncryptionStatusResponse {
+			calledMethods["GetReEncryptionStatus"] = true
+			return expectedReEncryptionStatus
+		},
+		ExportUserDataCalled: func(userAddress sdkCore.AddressHandler) (*requests.UserAuditDataResponse, error) {
+			assert.Equal(t, providedUserAddress, userAddress)
+			calledMethods["ExportUserData"] = true
+			return expectedAuditData, nil
+		},
+	}
+	facadeInstance, _ := NewGuardianFacade(args)
+
+	userState, err := facadeInstance.GetUserState(providedUserAddress)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedUserState, userState)
+
+	assert.Nil(t, facadeInstance.ForceUnsetSecurityMode(providedUserAddress))
+	assert.Nil(t, facadeInstance.ResetRateLimiter(providedUserAddress, "127.0.0.1"))
+	assert.Nil(t, facadeInstance.MarkGuardianNotUsable(providedUserAddress, "guardian"))
+	assert.Nil(t, facadeInstance.ReEncryptUser(providedUserAddress))
+	assert.Nil(t, facadeInstance.StartReEncryption())
+	assert.Equal(t, expectedReEncryptionStatus, facadeInstance.GetReEncryptionStatus())
+
+	auditData, err := facadeInstance.ExportUserData(providedUserAddress)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedAuditData, auditData)
+
+	assert.Equal(t, 8, len(calledMethods))
+}
+
+func TestGuardianFacade_RecordAuditEntry(t *testing.T) {
+	t.Parallel()
+
+	providedEntry := core.AuditEntry{
+		Route:   "/sign-transaction",
+		Outcome: core.AuditOutcomeSuccess,
+	}
+	providedTxs := []transaction.FrontendTransaction{{Nonce: 7}}
+	wasCalled := false
+	args := createMockArguments()
+	args.AuditLogger = &testscommon.AuditLoggerStub{
+		RecordCalled: func(entry core.AuditEntry, txs []transaction.FrontendTransaction) {
+			assert.Equal(t, providedEntry, entry)
+			assert.Equal(t, providedTxs, txs)
+			wasCalled = true
+		},
+	}
+	facadeInstance, _ := NewGuardianFacade(args)
+
+	facadeInstance.RecordAuditEntry(providedEntry, providedTxs)
+	assert.True(t, wasCalled)
+}
+
 func TestGuardianFacade_IsInterfaceNil(t *testing.T) {
 	t.Parallel()
 
@@ -235,3 +448,28 @@ func TestGuardianFacade_IsInterfaceNil(t *testing.T) {
 	facadeInstance, _ = NewGuardianFacade(createMockArguments())
 	assert.False(t, facadeInstance.IsInterfaceNil())
 }
+
+func TestGuardianFacade_GetHistory(t *testing.T) {
+	t.Parallel()
+
+	providedUserAddress, _ := data.NewAddressFromBech32String("erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th")
+	expectedHistory := &requests.UserHistoryResponse{
+		Entries: []requests.HistoryEntry{{Route: "/sign-transaction"}},
+		Offset:  1,
+		Limit:   2,
+	}
+	args := createMockArguments()
+	args.ServiceResolver = &testscommon.ServiceResolverStub{
+		GetHistoryCalled: func(userAddress sdkCore.AddressHandler, offset uint32, limit uint32) (*requests.UserHistoryResponse, error) {
+			assert.Equal(t, providedUserAddress, userAddress)
+			assert.Equal(t, uint32(1), offset)
+			assert.Equal(t, uint32(2), limit)
+			return expectedHistory, nil
+		},
+	}
+	facadeInstance, _ := NewGuardianFacade(args)
+
+	history, err := facadeInstance.GetHistory(providedUserAddress, 1, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedHistory, history)
+}
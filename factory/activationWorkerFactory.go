@@ -0,0 +1,25 @@
+package factory
+
+import (
+	"time"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/reconciliation"
+)
+
+// CreateGuardianActivationWorker will create the worker which periodically checks on chain the activation of the
+// guardians. If no check period is configured, the guardians are reconciled only when the users sign
+func CreateGuardianActivationWorker(configs *config.Configs, reconciler reconciliation.GuardianActivationReconciler) (core.GuardianActivationWorker, error) {
+	checkPeriodInSec := configs.GeneralConfig.ServiceResolver.GuardianActivationCheckInSec
+	if checkPeriodInSec == 0 {
+		log.Warn("no guardian activation check period provided, the guardians are checked on chain only on demand")
+		return reconciliation.NewDisabledActivationWorker(), nil
+	}
+
+	args := reconciliation.ArgsActivationWorker{
+		Reconciler: reconciler,
+		Period:     time.Duration(checkPeriodInSec) * time.Second,
+	}
+	return reconciliation.NewActivationWorker(args)
+}
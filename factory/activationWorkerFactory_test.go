@@ -0,0 +1,45 @@
+package factory
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/reconciliation"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+func TestCreateGuardianActivationWorker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no check period should create a disabled worker", func(t *testing.T) {
+		t.Parallel()
+
+		worker, err := CreateGuardianActivationWorker(&config.Configs{}, nil)
+		require.Nil(t, err)
+		assert.Equal(t, "*reconciliation.disabledActivationWorker", fmt.Sprintf("%T", worker))
+		assert.Nil(t, worker.Close())
+	})
+	t.Run("nil reconciler should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &config.Configs{}
+		cfg.GeneralConfig.ServiceResolver.GuardianActivationCheckInSec = 60
+		worker, err := CreateGuardianActivationWorker(cfg, nil)
+		assert.Equal(t, reconciliation.ErrNilReconciler, err)
+		assert.Nil(t, worker)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &config.Configs{}
+		cfg.GeneralConfig.ServiceResolver.GuardianActivationCheckInSec = 60
+		worker, err := CreateGuardianActivationWorker(cfg, &testscommon.ServiceResolverStub{})
+		require.Nil(t, err)
+		assert.Equal(t, "*reconciliation.activationWorker", fmt.Sprintf("%T", worker))
+		assert.Nil(t, worker.Close())
+	})
+}
@@ -0,0 +1,56 @@
+package factory
+
+import (
+	"fmt"
+
+	storageFactory "github.com/multiversx/mx-chain-storage-go/factory"
+	"github.com/multiversx/mx-sdk-go/builders"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/audit"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/mongodb"
+)
+
+// CreateAuditLogger will create a new audit logger, writing into the provided audit sink
+func CreateAuditLogger(sink core.AuditSink, cryptoComponents *cryptoComponentsHolder) (core.AuditLogger, error) {
+	txHashComputer, err := builders.NewTxBuilder(cryptoComponents.Signer())
+	if err != nil {
+		return nil, err
+	}
+
+	argsAuditLogger := audit.ArgsAuditLogger{
+		Sink:           sink,
+		TxHashComputer: txHashComputer,
+	}
+	return audit.NewAuditLogger(argsAuditLogger)
+}
+
+// CreateAuditSink will create the configured audit sink
+func CreateAuditSink(configs *config.Configs, metricsHandler core.StatusMetricsHandler) (core.AuditSink, error) {
+	auditConfig := configs.GeneralConfig.Audit
+	switch core.AuditSinkType(auditConfig.SinkType) {
+	case "":
+		log.Warn("no audit sink type provided, the audit log is disabled")
+		return audit.NewDisabledSink(), nil
+	case core.FileAuditSink:
+		return audit.NewFileSink(auditConfig.FilePath)
+	case core.LevelDBAuditSink:
+		storer, err := storageFactory.NewStorageUnitFromConf(auditConfig.Storage.Cache, auditConfig.Storage.DB)
+		if err != nil {
+			return nil, err
+		}
+
+		return audit.NewStorerSink(storer)
+	case core.MongoDBAuditSink:
+		client, err := mongodb.CreateMongoDBClient(configs.ExternalConfig.MongoDB, metricsHandler)
+		if err != nil {
+			return nil, err
+		}
+
+		return audit.NewMongoDBSink(client)
+	default:
+		return nil, fmt.Errorf("%w, unknown audit sink type %s", handlers.ErrInvalidConfig, auditConfig.SinkType)
+	}
+}
@@ -0,0 +1,65 @@
+package factory
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+func TestCreateAuditSink(t *testing.T) {
+	t.Parallel()
+
+	cryptoComponents, err := CreateCoreCryptoComponents(config.PubkeyConfig{
+		Length: 32,
+		Type:   "bech32",
+		Hrp:    "erd",
+	})
+	require.Nil(t, err)
+
+	t.Run("unknown sink type should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &config.Configs{}
+		cfg.GeneralConfig.Audit.SinkType = "unknown"
+
+		sink, err := CreateAuditSink(cfg, &testscommon.StatusMetricsStub{})
+		assert.Nil(t, sink)
+		assert.True(t, errors.Is(err, handlers.ErrInvalidConfig))
+	})
+	t.Run("empty sink type should create a disabled audit log", func(t *testing.T) {
+		t.Parallel()
+
+		sink, err := CreateAuditSink(&config.Configs{}, &testscommon.StatusMetricsStub{})
+		require.Nil(t, err)
+		assert.False(t, sink.IsInterfaceNil())
+		assert.Nil(t, sink.Close())
+	})
+	t.Run("file sink type should work", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &config.Configs{}
+		cfg.GeneralConfig.Audit.SinkType = string(core.FileAuditSink)
+		cfg.GeneralConfig.Audit.FilePath = filepath.Join(t.TempDir(), "audit.jsonl")
+
+		sink, err := CreateAuditSink(cfg, &testscommon.StatusMetricsStub{})
+		require.Nil(t, err)
+
+		auditLogger, err := CreateAuditLogger(sink, cryptoComponents)
+		require.Nil(t, err)
+
+		auditLogger.Record(core.AuditEntry{UserAddress: "user", Outcome: core.AuditOutcomeSuccess}, []transaction.FrontendTransaction{{}})
+		entries, err := sink.GetUserEntries("user", 0, 10)
+		require.Nil(t, err)
+		assert.Equal(t, 1, len(entries))
+		assert.Nil(t, sink.Close())
+	})
+}
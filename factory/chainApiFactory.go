@@ -0,0 +1,77 @@
+package factory
+
+import (
+	"fmt"
+	"time"
+
+	storageGoFactory "github.com/multiversx/mx-chain-storage-go/factory"
+	"github.com/multiversx/mx-sdk-go/authentication"
+	"github.com/multiversx/mx-sdk-go/core/http"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/chainapi"
+)
+
+// CreateChainApiClients will create the http client calling the configured chain API endpoints, with failover,
+// in the configured client mode, together with the wrapper fetching the accounts and caching the guardian data through it
+func CreateChainApiClients(cfg config.ApiConfig, metricsHandler core.StatusMetricsHandler) (authentication.HttpClientWrapper, core.HttpClientWrapper, error) {
+	networkAddresses := append([]string{cfg.NetworkAddress}, cfg.FallbackNetworkAddresses...)
+	endpoints := make([]chainapi.Endpoint, 0, len(networkAddresses))
+	for _, networkAddress := range networkAddresses {
+		endpoints = append(endpoints, chainapi.Endpoint{
+			NetworkAddress: networkAddress,
+			Client:         http.NewHttpClientWrapper(nil, networkAddress),
+		})
+	}
+
+	argsFailoverHttpClient := chainapi.ArgsFailoverHttpClient{
+		Endpoints:        endpoints,
+		UnhealthyBackoff: time.Duration(cfg.UnhealthyBackoffInSec) * time.Second,
+		MetricsHandler:   metricsHandler,
+	}
+	failoverHttpClient, err := chainapi.NewFailoverHttpClient(argsFailoverHttpClient)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	httpClient, err := createChainApiHttpClient(core.ApiClientMode(cfg.ClientMode), failoverHttpClient)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	httpClientWrapper, err := core.NewHttpClientWrapper(httpClient)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cacher, err := storageGoFactory.NewCache(cfg.GuardianDataCache)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	argsCachedHttpClientWrapper := chainapi.ArgsCachedHttpClientWrapper{
+		HttpClientWrapper: httpClientWrapper,
+		Cacher:            cacher,
+		GuardianDataTTL:   time.Duration(cfg.GuardianDataCacheTTLInSec) * time.Second,
+	}
+	cachedHttpClientWrapper, err := chainapi.NewCachedHttpClientWrapper(argsCachedHttpClientWrapper)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return httpClient, cachedHttpClientWrapper, nil
+}
+
+func createChainApiHttpClient(mode core.ApiClientMode, httpClient authentication.HttpClientWrapper) (authentication.HttpClientWrapper, error) {
+	switch mode {
+	// an empty mode keeps the behaviour of the configs written before the mode was added
+	case core.PublicApiClientMode, "":
+		return httpClient, nil
+	case core.GatewayClientMode:
+		return chainapi.NewGatewayHttpClient(httpClient)
+	default:
+		return nil, fmt.Errorf("%w, unknown api client mode %s", handlers.ErrInvalidConfig, mode)
+	}
+}
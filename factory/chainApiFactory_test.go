@@ -0,0 +1,93 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+func createChainApiConfig(networkAddress string, mode core.ApiClientMode) config.ApiConfig {
+	return config.ApiConfig{
+		ClientMode:                string(mode),
+		NetworkAddress:            networkAddress,
+		UnhealthyBackoffInSec:     30,
+		GuardianDataCacheTTLInSec: 6,
+		GuardianDataCache: common.CacheConfig{
+			Name:     "GuardianDataCache",
+			Capacity: 100,
+			Type:     "LRU",
+		},
+	}
+}
+
+func TestCreateChainApiClients(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/blocks/hash":
+			_, _ = w.Write([]byte(`{"timestamp":1}`))
+		case "/hyperblock/by-hash/hash":
+			_, _ = w.Write([]byte(`{"data":{"hyperblock":{"timestamp":2}},"error":"","code":"successful"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Run("unknown client mode should error", func(t *testing.T) {
+		t.Parallel()
+
+		httpClient, httpClientWrapper, err := CreateChainApiClients(createChainApiConfig(server.URL, "unknown"), &testscommon.StatusMetricsStub{})
+		assert.True(t, errors.Is(err, handlers.ErrInvalidConfig))
+		assert.Nil(t, httpClient)
+		assert.Nil(t, httpClientWrapper)
+	})
+	t.Run("invalid cache config should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createChainApiConfig(server.URL, core.PublicApiClientMode)
+		cfg.GuardianDataCache.Capacity = 0
+		httpClient, httpClientWrapper, err := CreateChainApiClients(cfg, &testscommon.StatusMetricsStub{})
+		assert.NotNil(t, err)
+		assert.Nil(t, httpClient)
+		assert.Nil(t, httpClientWrapper)
+	})
+
+	testClientMode := func(mode core.ApiClientMode, expectedBlock string) {
+		httpClient, httpClientWrapper, err := CreateChainApiClients(createChainApiConfig(server.URL, mode), &testscommon.StatusMetricsStub{})
+		require.Nil(t, err)
+		assert.False(t, httpClientWrapper.IsInterfaceNil())
+
+		buff, code, err := httpClient.GetHTTP(context.Background(), "blocks/hash")
+		require.Nil(t, err)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, expectedBlock, string(buff))
+	}
+	t.Run("empty client mode should use the api", func(t *testing.T) {
+		t.Parallel()
+
+		testClientMode("", `{"timestamp":1}`)
+	})
+	t.Run("api client mode should work", func(t *testing.T) {
+		t.Parallel()
+
+		testClientMode(core.PublicApiClientMode, `{"timestamp":1}`)
+	})
+	t.Run("gateway client mode should work", func(t *testing.T) {
+		t.Parallel()
+
+		testClientMode(core.GatewayClientMode, `{"timestamp":2}`)
+	})
+}
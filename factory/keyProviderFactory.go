@@ -0,0 +1,65 @@
+package factory
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/keyprovider"
+)
+
+// CreateKeyProvider will create the configured provider of the mnemonic used to derive all the keys of the service
+func CreateKeyProvider(configs *config.Configs) (core.KeyProvider, error) {
+	mnemonicFile := configs.GeneralConfig.Guardian.MnemonicFile
+	keyProviderConfig := configs.GeneralConfig.KeyProvider
+	switch core.KeyProviderType(keyProviderConfig.Type) {
+	case "":
+		log.Warn("no key provider type provided, the mnemonic is read in plain text")
+		return keyprovider.NewFileKeyProvider(mnemonicFile)
+	case core.FileKeyProvider:
+		return keyprovider.NewFileKeyProvider(mnemonicFile)
+	case core.KeystoreKeyProvider:
+		return createKeystoreKeyProvider(mnemonicFile, keyProviderConfig)
+	case core.KMSKeyProvider:
+		return createKMSKeyProvider(mnemonicFile, keyProviderConfig.KMS)
+	default:
+		return nil, fmt.Errorf("%w, unknown key provider type %s", handlers.ErrInvalidConfig, keyProviderConfig.Type)
+	}
+}
+
+func createKeystoreKeyProvider(keystoreFile string, cfg config.KeyProviderConfig) (core.KeyProvider, error) {
+	passphrase, err := os.ReadFile(cfg.PassphraseFile)
+	if err != nil {
+		return nil, err
+	}
+
+	args := keyprovider.ArgsKeystoreKeyProvider{
+		KeystoreFile: keystoreFile,
+		Passphrase:   []byte(strings.TrimSpace(string(passphrase))),
+	}
+	return keyprovider.NewKeystoreKeyProvider(args)
+}
+
+func createKMSKeyProvider(wrappedMnemonicFile string, cfg config.KMSConfig) (core.KeyProvider, error) {
+	authToken := ""
+	if len(cfg.AuthTokenFile) > 0 {
+		authTokenBytes, err := os.ReadFile(cfg.AuthTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		authToken = strings.TrimSpace(string(authTokenBytes))
+	}
+
+	args := keyprovider.ArgsKMSKeyProvider{
+		URL:                 cfg.URL,
+		KeyID:               cfg.KeyID,
+		AuthToken:           authToken,
+		WrappedMnemonicFile: wrappedMnemonicFile,
+		RequestTimeout:      time.Duration(cfg.RequestTimeoutInSec) * time.Second,
+	}
+	return keyprovider.NewKMSKeyProvider(args)
+}
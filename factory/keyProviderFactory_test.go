@@ -0,0 +1,110 @@
+package factory
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+)
+
+func TestCreateKeyProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown key provider type should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &config.Configs{}
+		cfg.GeneralConfig.KeyProvider.Type = "unknown"
+
+		provider, err := CreateKeyProvider(cfg)
+		assert.Nil(t, provider)
+		assert.True(t, errors.Is(err, handlers.ErrInvalidConfig))
+	})
+	t.Run("empty key provider type should read the mnemonic in plain text", func(t *testing.T) {
+		t.Parallel()
+
+		mnemonicFile := filepath.Join(t.TempDir(), "mnemonic")
+		require.Nil(t, os.WriteFile(mnemonicFile, []byte("mnemonic"), 0600))
+
+		cfg := &config.Configs{}
+		cfg.GeneralConfig.Guardian.MnemonicFile = mnemonicFile
+
+		provider, err := CreateKeyProvider(cfg)
+		require.Nil(t, err)
+		mnemonic, err := provider.GetMnemonic()
+		assert.Nil(t, err)
+		assert.Equal(t, "mnemonic", string(mnemonic))
+	})
+	t.Run("file key provider should work", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &config.Configs{}
+		cfg.GeneralConfig.Guardian.MnemonicFile = "mnemonic"
+		cfg.GeneralConfig.KeyProvider.Type = string(core.FileKeyProvider)
+
+		provider, err := CreateKeyProvider(cfg)
+		assert.Nil(t, err)
+		assert.False(t, provider.IsInterfaceNil())
+	})
+	t.Run("keystore key provider without passphrase file should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &config.Configs{}
+		cfg.GeneralConfig.Guardian.MnemonicFile = "keystore.json"
+		cfg.GeneralConfig.KeyProvider.Type = string(core.KeystoreKeyProvider)
+		cfg.GeneralConfig.KeyProvider.PassphraseFile = filepath.Join(t.TempDir(), "missing")
+
+		provider, err := CreateKeyProvider(cfg)
+		assert.Nil(t, provider)
+		assert.NotNil(t, err)
+	})
+	t.Run("keystore key provider should work", func(t *testing.T) {
+		t.Parallel()
+
+		passphraseFile := filepath.Join(t.TempDir(), "passphrase")
+		require.Nil(t, os.WriteFile(passphraseFile, []byte("passphrase\n"), 0600))
+
+		cfg := &config.Configs{}
+		cfg.GeneralConfig.Guardian.MnemonicFile = "keystore.json"
+		cfg.GeneralConfig.KeyProvider.Type = string(core.KeystoreKeyProvider)
+		cfg.GeneralConfig.KeyProvider.PassphraseFile = passphraseFile
+
+		provider, err := CreateKeyProvider(cfg)
+		assert.Nil(t, err)
+		assert.False(t, provider.IsInterfaceNil())
+	})
+	t.Run("kms key provider without auth token file should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &config.Configs{}
+		cfg.GeneralConfig.Guardian.MnemonicFile = "mnemonic.wrapped"
+		cfg.GeneralConfig.KeyProvider.Type = string(core.KMSKeyProvider)
+		cfg.GeneralConfig.KeyProvider.KMS.URL = "http://localhost"
+		cfg.GeneralConfig.KeyProvider.KMS.KeyID = "key-id"
+		cfg.GeneralConfig.KeyProvider.KMS.AuthTokenFile = filepath.Join(t.TempDir(), "missing")
+
+		provider, err := CreateKeyProvider(cfg)
+		assert.Nil(t, provider)
+		assert.NotNil(t, err)
+	})
+	t.Run("kms key provider should work", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &config.Configs{}
+		cfg.GeneralConfig.Guardian.MnemonicFile = "mnemonic.wrapped"
+		cfg.GeneralConfig.KeyProvider.Type = string(core.KMSKeyProvider)
+		cfg.GeneralConfig.KeyProvider.KMS.URL = "http://localhost"
+		cfg.GeneralConfig.KeyProvider.KMS.KeyID = "key-id"
+
+		provider, err := CreateKeyProvider(cfg)
+		assert.Nil(t, err)
+		assert.False(t, provider.IsInterfaceNil())
+	})
+}
@@ -0,0 +1,61 @@
+package factory
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/notifier"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/redis"
+)
+
+// CreateNotifier will create the configured notifier of the security events
+func CreateNotifier(configs *config.Configs) (core.Notifier, error) {
+	notifierConfig := configs.GeneralConfig.Notifier
+	switch core.NotifierType(notifierConfig.Type) {
+	case "":
+		log.Warn("no notifier type provided, the security events are not emitted")
+		return notifier.NewDisabledNotifier(), nil
+	case core.WebhookNotifier:
+		return createWebhookNotifier(notifierConfig.Webhook)
+	case core.RedisNotifier:
+		return createRedisNotifier(configs.ExternalConfig.Redis)
+	default:
+		return nil, fmt.Errorf("%w, unknown notifier type %s", handlers.ErrInvalidConfig, notifierConfig.Type)
+	}
+}
+
+func createWebhookNotifier(cfg config.WebhookConfig) (core.Notifier, error) {
+	secret, err := os.ReadFile(cfg.SecretFile)
+	if err != nil {
+		return nil, err
+	}
+
+	args := notifier.ArgsWebhookNotifier{
+		URL:            cfg.URL,
+		Secret:         []byte(strings.TrimSpace(string(secret))),
+		MaxRetries:     cfg.MaxRetries,
+		RetryDelay:     time.Duration(cfg.RetryDelayInMs) * time.Millisecond,
+		RequestTimeout: time.Duration(cfg.RequestTimeoutInSec) * time.Second,
+		QueueSize:      cfg.QueueSize,
+	}
+	return notifier.NewWebhookNotifier(args)
+}
+
+func createRedisNotifier(cfg config.RedisConfig) (core.Notifier, error) {
+	publisher, err := redis.CreateRedisPublisher(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	args := notifier.ArgsPubSubNotifier{
+		Publisher:        publisher,
+		Channel:          cfg.Channel,
+		OperationTimeout: time.Duration(cfg.OperationTimeoutInSec) * time.Second,
+	}
+	return notifier.NewPubSubNotifier(args)
+}
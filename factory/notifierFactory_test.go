@@ -0,0 +1,67 @@
+package factory
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+)
+
+func TestCreateNotifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown notifier type should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &config.Configs{}
+		cfg.GeneralConfig.Notifier.Type = "unknown"
+
+		n, err := CreateNotifier(cfg)
+		assert.Nil(t, n)
+		assert.True(t, errors.Is(err, handlers.ErrInvalidConfig))
+	})
+	t.Run("empty notifier type should create a disabled notifier", func(t *testing.T) {
+		t.Parallel()
+
+		n, err := CreateNotifier(&config.Configs{})
+		require.Nil(t, err)
+		assert.False(t, n.IsInterfaceNil())
+		assert.Nil(t, n.Close())
+	})
+	t.Run("webhook notifier without secret file should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &config.Configs{}
+		cfg.GeneralConfig.Notifier.Type = string(core.WebhookNotifier)
+		cfg.GeneralConfig.Notifier.Webhook.URL = "http://localhost"
+		cfg.GeneralConfig.Notifier.Webhook.SecretFile = filepath.Join(t.TempDir(), "missing")
+
+		n, err := CreateNotifier(cfg)
+		assert.Nil(t, n)
+		assert.NotNil(t, err)
+	})
+	t.Run("webhook notifier should work", func(t *testing.T) {
+		t.Parallel()
+
+		secretFile := filepath.Join(t.TempDir(), "secret")
+		require.Nil(t, os.WriteFile(secretFile, []byte("secret\n"), 0600))
+
+		cfg := &config.Configs{}
+		cfg.GeneralConfig.Notifier.Type = string(core.WebhookNotifier)
+		cfg.GeneralConfig.Notifier.Webhook.URL = "http://localhost"
+		cfg.GeneralConfig.Notifier.Webhook.SecretFile = secretFile
+		cfg.GeneralConfig.Notifier.Webhook.QueueSize = 10
+
+		n, err := CreateNotifier(cfg)
+		require.Nil(t, err)
+		assert.False(t, n.IsInterfaceNil())
+		assert.Nil(t, n.Close())
+	})
+}
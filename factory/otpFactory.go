@@ -1,33 +1,48 @@
 package factory
 
 import (
-	"crypto"
-
 	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/secureOtp"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/twofactor"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/twofactor/rfc"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/twofactor/sec51"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/redis"
 )
 
-const hashType = crypto.SHA1
-
 // CreateOTPHandler will create a new otp handler instance
 func CreateOTPHandler(configs *config.Configs) (handlers.TOTPHandler, error) {
-	otpProvider := sec51.NewSec51Wrapper(configs.GeneralConfig.TwoFactor.Digits, configs.GeneralConfig.TwoFactor.Issuer)
-	return twofactor.NewTwoFactorHandler(otpProvider, hashType)
+	twoFactorConfig := configs.GeneralConfig.TwoFactor
+	otpProvider, err := rfc.NewOTPProvider(twoFactorConfig.Issuer, twoFactorConfig.AcceptedDriftSteps)
+	if err != nil {
+		return nil, err
+	}
+
+	args := twofactor.ArgsTwoFactorHandler{
+		OTPProvider:       otpProvider,
+		LegacyOTPProvider: sec51.NewSec51Wrapper(twoFactorConfig.Issuer),
+		Params: core.OTPParams{
+			Type:      twoFactorConfig.Type,
+			Algorithm: twoFactorConfig.Algorithm,
+			Digits:    uint32(twoFactorConfig.Digits),
+			Period:    twoFactorConfig.PeriodInSec,
+			Skew:      twoFactorConfig.Skew,
+		},
+	}
+	return twofactor.NewTwoFactorHandler(args)
 }
 
 // CreateSecureOTPHandler will create a new otp handler instance
-func CreateSecureOTPHandler(configs *config.Configs) (handlers.SecureOtpHandler, error) {
-	rateLimiter, err := redis.CreateRedisRateLimiter(configs.ExternalConfig.Redis, configs.GeneralConfig.TwoFactor)
+func CreateSecureOTPHandler(configs *config.Configs, redisStorer redis.RedisStorer, notifier core.Notifier) (handlers.SecureOtpHandler, error) {
+	rateLimiter, err := redis.CreateRedisRateLimiter(redisStorer, configs.ExternalConfig.Redis, configs.GeneralConfig.TwoFactor)
 	if err != nil {
 		return nil, err
 	}
 
 	secureOtpArgs := secureOtp.ArgsSecureOtpHandler{
 		RateLimiter: rateLimiter,
+		Notifier:    notifier,
 	}
 	return secureOtp.NewSecureOtpHandler(secureOtpArgs)
 }
@@ -1,17 +1,19 @@
 package factory
 
 import (
-	"io/ioutil"
-
 	"github.com/multiversx/mx-chain-core-go/hashing/keccak"
 	factoryMarshalizer "github.com/multiversx/mx-chain-core-go/marshal/factory"
+	crypto "github.com/multiversx/mx-chain-crypto-go"
 	"github.com/multiversx/mx-sdk-go/builders"
-	"github.com/multiversx/mx-sdk-go/data"
 
 	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/txdecoder"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/encryption"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/txpolicy"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/webauthn"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/redis"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/resolver"
 )
 
@@ -23,6 +25,9 @@ func CreateServiceResolver(
 	registeredUsersDB core.StorageWithIndex,
 	twoFactorHandler handlers.TOTPHandler,
 	secureOtpHandler handlers.SecureOtpHandler,
+	redisStorer redis.RedisStorer,
+	auditSink core.AuditSink,
+	notifier core.Notifier,
 ) (core.ServiceResolver, error) {
 	gogoMarshaller, err := factoryMarshalizer.NewMarshalizer(factoryMarshalizer.GogoProtobuf)
 	if err != nil {
@@ -44,12 +49,17 @@ func CreateServiceResolver(
 		return nil, err
 	}
 
-	mnemonic, err := ioutil.ReadFile(configs.GeneralConfig.Guardian.MnemonicFile)
+	keyProvider, err := CreateKeyProvider(configs)
+	if err != nil {
+		return nil, err
+	}
+
+	mnemonic, err := keyProvider.GetMnemonic()
 	if err != nil {
 		return nil, err
 	}
 	argsGuardianKeyGenerator := core.ArgGuardianKeyGenerator{
-		Mnemonic: data.Mnemonic(mnemonic),
+		Mnemonic: mnemonic,
 		KeyGen:   cryptoComponents.KeyGenerator(),
 	}
 	guardianKeyGenerator, err := core.NewGuardianKeyGenerator(argsGuardianKeyGenerator)
@@ -62,17 +72,52 @@ func CreateServiceResolver(
 		return nil, err
 	}
 
-	managedPrivateKey, err := guardianKeyGenerator.GenerateManagedKey()
+	currentKeyID := configs.GeneralConfig.Guardian.EncryptionKeyID
+	managedKeys := make(map[uint32]crypto.PrivateKey, currentKeyID+1)
+	for keyID := uint32(0); keyID <= currentKeyID; keyID++ {
+		managedKeys[keyID], err = guardianKeyGenerator.GenerateManagedKey(keyID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	argsKeyRing := encryption.ArgsKeyRing{
+		Marshaller:   jsonMarshaller,
+		KeyGen:       cryptoComponents.KeyGenerator(),
+		ManagedKeys:  managedKeys,
+		CurrentKeyID: currentKeyID,
+	}
+	encryptor, err := encryption.NewKeyRing(argsKeyRing)
+	if err != nil {
+		return nil, err
+	}
+
+	userEncryptor, err := resolver.NewUserEncryptor(encryptor, configs.GeneralConfig.Guardian.RejectLegacyRecords)
 	if err != nil {
 		return nil, err
 	}
 
-	encryptor, err := encryption.NewEncryptor(jsonMarshaller, cryptoComponents.KeyGenerator(), managedPrivateKey)
+	argsTxPolicyHandler := txpolicy.ArgsTxPolicyHandler{
+		Config:                configs.GeneralConfig.TxPolicy,
+		PubKeyConverter:       cryptoComponents.PubkeyConverter(),
+		Storer:                redisStorer,
+		OperationTimeoutInSec: configs.ExternalConfig.Redis.OperationTimeoutInSec,
+	}
+	txPolicyHandler, err := txpolicy.NewTxPolicyHandler(argsTxPolicyHandler)
 	if err != nil {
 		return nil, err
 	}
 
-	userEncryptor, err := resolver.NewUserEncryptor(encryptor)
+	txDecoder, err := txdecoder.NewTxDecoder(cryptoComponents.PubkeyConverter())
+	if err != nil {
+		return nil, err
+	}
+
+	argsWebAuthnHandler := webauthn.ArgsWebAuthnHandler{
+		Config:    configs.GeneralConfig.WebAuthn,
+		Encryptor: encryptor,
+	}
+	webAuthnHandler, err := webauthn.NewWebAuthnHandler(argsWebAuthnHandler)
 	if err != nil {
 		return nil, err
 	}
@@ -83,10 +128,15 @@ func CreateServiceResolver(
 		UserEncryptor:                 userEncryptor,
 		TOTPHandler:                   twoFactorHandler,
 		SecureOtpHandler:              secureOtpHandler,
+		TxPolicyHandler:               txPolicyHandler,
+		TxDecoder:                     txDecoder,
+		WebAuthnHandler:               webAuthnHandler,
 		HttpClientWrapper:             httpClientWrapper,
 		KeysGenerator:                 guardianKeyGenerator,
 		PubKeyConverter:               cryptoComponents.PubkeyConverter(),
 		RegisteredUsersDB:             registeredUsersDB,
+		AuditSink:                     auditSink,
+		Notifier:                      notifier,
 		UserDataMarshaller:            gogoMarshaller,
 		TxMarshaller:                  jsonTxMarshaller,
 		TxHasher:                      txHasher,
@@ -2,7 +2,10 @@ package factory
 
 import (
 	"io"
+	"os"
+	"strings"
 
+	logger "github.com/multiversx/mx-chain-logger-go"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/api/gin"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
@@ -10,6 +13,8 @@ import (
 	"github.com/multiversx/mx-sdk-go/authentication"
 )
 
+var log = logger.GetOrCreate("factory")
+
 // StartWebServer creates and starts a web server able to respond with the metrics holder information
 func StartWebServer(
 	configs config.Configs,
@@ -18,10 +23,12 @@ func StartWebServer(
 	tokenHandler authentication.AuthTokenHandler,
 	whitelistHandler core.NativeAuthWhitelistHandler,
 	statusMetricsHandler core.StatusMetricsHandler,
+	auditLogger core.AuditLogger,
 ) (io.Closer, error) {
 	argsFacade := facade.ArgsGuardianFacade{
 		ServiceResolver:      serviceResolver,
 		StatusMetricsHandler: statusMetricsHandler,
+		AuditLogger:          auditLogger,
 	}
 
 	guardianFacade, err := facade.NewGuardianFacade(argsFacade)
@@ -29,6 +36,11 @@ func StartWebServer(
 		return nil, err
 	}
 
+	adminAPIKey, err := loadAdminAPIKey(configs.GeneralConfig.Admin.APIKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
 	httpServerArgs := gin.ArgsNewWebServer{
 		Facade:                     guardianFacade,
 		Config:                     configs,
@@ -36,6 +48,7 @@ func StartWebServer(
 		TokenHandler:               tokenHandler,
 		NativeAuthWhitelistHandler: whitelistHandler,
 		StatusMetricsHandler:       statusMetricsHandler,
+		AdminAPIKey:                adminAPIKey,
 	}
 
 	httpServerWrapper, err := gin.NewWebServerHandler(httpServerArgs)
@@ -50,3 +63,17 @@ func StartWebServer(
 
 	return httpServerWrapper, nil
 }
+
+func loadAdminAPIKey(apiKeyFile string) (string, error) {
+	if len(apiKeyFile) == 0 {
+		log.Warn("no admin api key file provided, the admin routes will reject all requests")
+		return "", nil
+	}
+
+	apiKey, err := os.ReadFile(apiKeyFile)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(apiKey)), nil
+}
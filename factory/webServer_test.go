@@ -36,10 +36,24 @@ func TestStartWebServer(t *testing.T) {
 		&mock.AuthTokenHandlerStub{},
 		&middleware.NativeAuthWhitelistHandlerStub{},
 		&testscommon.StatusMetricsStub{},
+		&testscommon.AuditLoggerStub{},
 	)
 	assert.Nil(t, err)
 	assert.NotNil(t, webServer)
 
 	err = webServer.Close()
 	assert.Nil(t, err)
+
+	cfg.GeneralConfig.Admin.APIKeyFile = "testdata/missing.key"
+	webServer, err = StartWebServer(
+		cfg,
+		&testscommon.ServiceResolverStub{},
+		&mock.AuthServerStub{},
+		&mock.AuthTokenHandlerStub{},
+		&middleware.NativeAuthWhitelistHandlerStub{},
+		&testscommon.StatusMetricsStub{},
+		&testscommon.AuditLoggerStub{},
+	)
+	assert.NotNil(t, err)
+	assert.Nil(t, webServer)
 }
@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"encoding/hex"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	logger "github.com/multiversx/mx-chain-logger-go"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+)
+
+var log = logger.GetOrCreate("audit")
+
+// ArgsAuditLogger is the DTO used to create a new instance of auditLogger
+type ArgsAuditLogger struct {
+	Sink           core.AuditSink
+	TxHashComputer TxHashComputer
+}
+
+type auditLogger struct {
+	sink           core.AuditSink
+	txHashComputer TxHashComputer
+	getTimeHandler func() time.Time
+}
+
+// NewAuditLogger returns a new instance of auditLogger
+func NewAuditLogger(args ArgsAuditLogger) (*auditLogger, error) {
+	if check.IfNil(args.Sink) {
+		return nil, ErrNilAuditSink
+	}
+	if check.IfNil(args.TxHashComputer) {
+		return nil, ErrNilTxHashComputer
+	}
+
+	return &auditLogger{
+		sink:           args.Sink,
+		txHashComputer: args.TxHashComputer,
+		getTimeHandler: time.Now,
+	}, nil
+}
+
+// Record appends the entry to the audit sink, along with the hashes of the provided transactions.
+// The request was already answered at this point, so a failure is only logged
+func (al *auditLogger) Record(entry core.AuditEntry, txs []transaction.FrontendTransaction) {
+	if entry.Timestamp == 0 {
+		entry.Timestamp = al.getTimeHandler().Unix()
+	}
+	entry.TxHashes = al.computeTxHashes(txs)
+
+	err := al.sink.Append(entry)
+	if err != nil {
+		log.Error("could not append audit entry",
+			"route", entry.Route,
+			"user address", entry.UserAddress,
+			"outcome", entry.Outcome,
+			"error", err.Error())
+	}
+}
+
+func (al *auditLogger) computeTxHashes(txs []transaction.FrontendTransaction) []string {
+	if len(txs) == 0 {
+		return nil
+	}
+
+	txHashes := make([]string, 0, len(txs))
+	for i := range txs {
+		// transactions rejected before being signed by the user have no hash
+		if len(txs[i].Signature) == 0 {
+			continue
+		}
+
+		txHash, err := al.txHashComputer.ComputeTxHash(&txs[i])
+		if err != nil {
+			log.Debug("could not compute tx hash for audit entry", "index", i, "error", err.Error())
+			continue
+		}
+
+		txHashes = append(txHashes, hex.EncodeToString(txHash))
+	}
+
+	return txHashes
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (al *auditLogger) IsInterfaceNil() bool {
+	return al == nil
+}
@@ -0,0 +1,118 @@
+package audit
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+var expectedErr = errors.New("expected error")
+
+func createMockArgs() ArgsAuditLogger {
+	return ArgsAuditLogger{
+		Sink:           &testscommon.AuditSinkStub{},
+		TxHashComputer: &testscommon.GuardedTxBuilderStub{},
+	}
+}
+
+func TestNewAuditLogger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil sink should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Sink = nil
+		al, err := NewAuditLogger(args)
+		assert.Nil(t, al)
+		assert.Equal(t, ErrNilAuditSink, err)
+	})
+	t.Run("nil tx hash computer should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.TxHashComputer = nil
+		al, err := NewAuditLogger(args)
+		assert.Nil(t, al)
+		assert.Equal(t, ErrNilTxHashComputer, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		al, err := NewAuditLogger(createMockArgs())
+		assert.Nil(t, err)
+		assert.False(t, al.IsInterfaceNil())
+	})
+}
+
+func TestAuditLogger_Record(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should append the entry with timestamp and tx hashes", func(t *testing.T) {
+		t.Parallel()
+
+		var appendedEntry core.AuditEntry
+		args := createMockArgs()
+		args.Sink = &testscommon.AuditSinkStub{
+			AppendCalled: func(entry core.AuditEntry) error {
+				appendedEntry = entry
+				return nil
+			},
+		}
+		args.TxHashComputer = &testscommon.GuardedTxBuilderStub{
+			ComputeTxHashCalled: func(tx *transaction.FrontendTransaction) ([]byte, error) {
+				if tx.Nonce == 2 {
+					return nil, expectedErr
+				}
+				return []byte(tx.Signature), nil
+			},
+		}
+		al, _ := NewAuditLogger(args)
+		al.getTimeHandler = func() time.Time {
+			return time.Unix(1000, 0)
+		}
+
+		txs := []transaction.FrontendTransaction{
+			{Nonce: 0, Signature: "sig0"},
+			{Nonce: 1},
+			{Nonce: 2, Signature: "sig2"},
+			{Nonce: 3, Signature: "sig3"},
+		}
+		entry := core.AuditEntry{
+			Route:       "/sign-multiple-transactions",
+			UserAddress: "user",
+			Outcome:     core.AuditOutcomeSuccess,
+		}
+		al.Record(entry, txs)
+
+		expectedEntry := entry
+		expectedEntry.Timestamp = 1000
+		expectedEntry.TxHashes = []string{hex.EncodeToString([]byte("sig0")), hex.EncodeToString([]byte("sig3"))}
+		assert.Equal(t, expectedEntry, appendedEntry)
+	})
+	t.Run("sink error should not panic", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Sink = &testscommon.AuditSinkStub{
+			AppendCalled: func(entry core.AuditEntry) error {
+				assert.Equal(t, int64(5), entry.Timestamp)
+				assert.Nil(t, entry.TxHashes)
+				return expectedErr
+			},
+		}
+		al, _ := NewAuditLogger(args)
+
+		require.NotPanics(t, func() {
+			al.Record(core.AuditEntry{Timestamp: 5, Outcome: core.AuditOutcomeFailure}, nil)
+		})
+	})
+}
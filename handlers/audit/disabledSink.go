@@ -0,0 +1,31 @@
+package audit
+
+import "github.com/multiversx/mx-multi-factor-auth-go-service/core"
+
+type disabledSink struct {
+}
+
+// NewDisabledSink returns a new instance of disabledSink, used when no audit log is configured
+func NewDisabledSink() *disabledSink {
+	return &disabledSink{}
+}
+
+// Append does nothing
+func (ds *disabledSink) Append(_ core.AuditEntry) error {
+	return nil
+}
+
+// GetUserEntries returns no entries
+func (ds *disabledSink) GetUserEntries(_ string, _ uint32, _ uint32) ([]core.AuditEntry, error) {
+	return make([]core.AuditEntry, 0), nil
+}
+
+// Close does nothing
+func (ds *disabledSink) Close() error {
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ds *disabledSink) IsInterfaceNil() bool {
+	return ds == nil
+}
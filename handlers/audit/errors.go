@@ -0,0 +1,18 @@
+package audit
+
+import "errors"
+
+// ErrNilAuditSink is returned when a nil audit sink is provided
+var ErrNilAuditSink = errors.New("nil audit sink")
+
+// ErrNilTxHashComputer is returned when a nil tx hash computer is provided
+var ErrNilTxHashComputer = errors.New("nil tx hash computer")
+
+// ErrNilStorer is returned when a nil storer is provided
+var ErrNilStorer = errors.New("nil storer")
+
+// ErrNilMongoDBClient is returned when a nil mongodb client is provided
+var ErrNilMongoDBClient = errors.New("nil mongodb client")
+
+// ErrEmptyFilePath is returned when an empty file path is provided
+var ErrEmptyFilePath = errors.New("empty file path")
@@ -0,0 +1,120 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+)
+
+const (
+	auditFilePermissions = 0600
+	auditDirPermissions  = 0700
+)
+
+const maxLineSize = 1024 * 1024
+
+type fileSink struct {
+	mut      sync.Mutex
+	file     *os.File
+	filePath string
+}
+
+// NewFileSink returns a new instance of fileSink, which appends the audit entries as JSON lines into the provided file
+func NewFileSink(filePath string) (*fileSink, error) {
+	if len(filePath) == 0 {
+		return nil, ErrEmptyFilePath
+	}
+
+	err := os.MkdirAll(filepath.Dir(filePath), auditDirPermissions)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, auditFilePermissions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileSink{
+		file:     file,
+		filePath: filePath,
+	}, nil
+}
+
+// Append writes the entry as a new line and syncs the file, so that the entry is not lost on a crash
+func (fs *fileSink) Append(entry core.AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	fs.mut.Lock()
+	defer fs.mut.Unlock()
+
+	_, err = fs.file.Write(line)
+	if err != nil {
+		return err
+	}
+
+	return fs.file.Sync()
+}
+
+// GetUserEntries returns the entries of the user, newest first. The whole file is scanned, so this sink
+// is meant for small deployments. The file is read through its own handle, without blocking the appends, so the
+// lines which cannot be decoded, such as a line still being written or a corrupted one, are skipped
+func (fs *fileSink) GetUserEntries(userAddress string, offset uint32, limit uint32) ([]core.AuditEntry, error) {
+	file, err := os.Open(fs.filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	userEntries := make([]core.AuditEntry, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+	lineIndex := 0
+	for scanner.Scan() {
+		lineIndex++
+		entry := core.AuditEntry{}
+		err = json.Unmarshal(scanner.Bytes(), &entry)
+		if err != nil {
+			log.Warn("skipped audit line which cannot be decoded", "file", fs.filePath, "line", lineIndex, "error", err.Error())
+			continue
+		}
+
+		if entry.UserAddress == userAddress {
+			userEntries = append(userEntries, entry)
+		}
+	}
+	err = scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]core.AuditEntry, 0, limit)
+	for idx := len(userEntries) - 1 - int(offset); idx >= 0 && uint32(len(entries)) < limit; idx-- {
+		entries = append(entries, userEntries[idx])
+	}
+
+	return entries, nil
+}
+
+// Close closes the underlying file
+func (fs *fileSink) Close() error {
+	fs.mut.Lock()
+	defer fs.mut.Unlock()
+
+	return fs.file.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (fs *fileSink) IsInterfaceNil() bool {
+	return fs == nil
+}
@@ -0,0 +1,9 @@
+package audit
+
+import "github.com/multiversx/mx-chain-core-go/data/transaction"
+
+// TxHashComputer defines the component able to compute the hash of a signed transaction
+type TxHashComputer interface {
+	ComputeTxHash(tx *transaction.FrontendTransaction) ([]byte, error)
+	IsInterfaceNil() bool
+}
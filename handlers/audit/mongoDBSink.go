@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/mongodb"
+)
+
+const (
+	userAddressField = "user_address"
+	timestampField   = "timestamp"
+)
+
+type mongoDBSink struct {
+	client mongodb.MongoDBClient
+}
+
+// NewMongoDBSink returns a new instance of mongoDBSink, which inserts the audit entries as documents of the audit collection
+func NewMongoDBSink(client mongodb.MongoDBClient) (*mongoDBSink, error) {
+	if check.IfNil(client) {
+		return nil, ErrNilMongoDBClient
+	}
+
+	return &mongoDBSink{
+		client: client,
+	}, nil
+}
+
+// Append inserts the entry as a new document, so that it can be queried by any of its fields
+func (ms *mongoDBSink) Append(entry core.AuditEntry) error {
+	return ms.client.InsertOne(mongodb.AuditCollectionID, entry)
+}
+
+// GetUserEntries returns the entries of the user, newest first
+func (ms *mongoDBSink) GetUserEntries(userAddress string, offset uint32, limit uint32) ([]core.AuditEntry, error) {
+	filter := bson.D{{Key: userAddressField, Value: userAddress}}
+	opts := options.Find().
+		SetSort(bson.D{{Key: timestampField, Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	entries := make([]core.AuditEntry, 0, limit)
+	err := ms.client.Find(mongodb.AuditCollectionID, filter, opts, &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Close closes the underlying mongodb client
+func (ms *mongoDBSink) Close() error {
+	return ms.client.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ms *mongoDBSink) IsInterfaceNil() bool {
+	return ms == nil
+}
@@ -0,0 +1,352 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/mongodb"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+func createAuditEntry(route string) core.AuditEntry {
+	return core.AuditEntry{
+		Timestamp:   1000,
+		Route:       route,
+		UserAddress: "user",
+		Guardian:    "guardian",
+		UserIp:      "127.0.0.1",
+		UserAgent:   "agent",
+		TxHashes:    []string{"hash"},
+		Outcome:     core.AuditOutcomeFailure,
+		ErrorClass:  "wrong code",
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty file path should error", func(t *testing.T) {
+		t.Parallel()
+
+		fs, err := NewFileSink("")
+		assert.Nil(t, fs)
+		assert.Equal(t, ErrEmptyFilePath, err)
+	})
+	t.Run("should append lines and keep the previous ones", func(t *testing.T) {
+		t.Parallel()
+
+		filePath := filepath.Join(t.TempDir(), "audit", "audit.jsonl")
+		fs, err := NewFileSink(filePath)
+		require.Nil(t, err)
+		assert.False(t, fs.IsInterfaceNil())
+		require.Nil(t, fs.Append(createAuditEntry("/first")))
+		require.Nil(t, fs.Close())
+
+		fs, err = NewFileSink(filePath)
+		require.Nil(t, err)
+		require.Nil(t, fs.Append(createAuditEntry("/second")))
+		require.Nil(t, fs.Close())
+
+		file, err := os.Open(filePath)
+		require.Nil(t, err)
+		defer func() {
+			_ = file.Close()
+		}()
+
+		entries := make([]core.AuditEntry, 0)
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			entry := core.AuditEntry{}
+			require.Nil(t, json.Unmarshal(scanner.Bytes(), &entry))
+			entries = append(entries, entry)
+		}
+		assert.Equal(t, []core.AuditEntry{createAuditEntry("/first"), createAuditEntry("/second")}, entries)
+	})
+	t.Run("should return the entries of the user, newest first", func(t *testing.T) {
+		t.Parallel()
+
+		fs, err := NewFileSink(filepath.Join(t.TempDir(), "audit.jsonl"))
+		require.Nil(t, err)
+		defer func() {
+			_ = fs.Close()
+		}()
+
+		require.Nil(t, fs.Append(createUserAuditEntry("/first", "user")))
+		require.Nil(t, fs.Append(createUserAuditEntry("/other", "other user")))
+		require.Nil(t, fs.Append(createUserAuditEntry("/second", "user")))
+		require.Nil(t, fs.Append(createUserAuditEntry("/third", "user")))
+
+		entries, err := fs.GetUserEntries("user", 0, 10)
+		require.Nil(t, err)
+		expectedEntries := []core.AuditEntry{
+			createUserAuditEntry("/third", "user"),
+			createUserAuditEntry("/second", "user"),
+			createUserAuditEntry("/first", "user"),
+		}
+		assert.Equal(t, expectedEntries, entries)
+
+		entries, err = fs.GetUserEntries("user", 1, 1)
+		require.Nil(t, err)
+		assert.Equal(t, []core.AuditEntry{createUserAuditEntry("/second", "user")}, entries)
+
+		entries, err = fs.GetUserEntries("unknown user", 0, 10)
+		require.Nil(t, err)
+		assert.Equal(t, 0, len(entries))
+	})
+	t.Run("should skip the lines which cannot be decoded", func(t *testing.T) {
+		t.Parallel()
+
+		filePath := filepath.Join(t.TempDir(), "audit.jsonl")
+		fs, err := NewFileSink(filePath)
+		require.Nil(t, err)
+		defer func() {
+			_ = fs.Close()
+		}()
+
+		require.Nil(t, fs.Append(createUserAuditEntry("/first", "user")))
+		_, err = fs.file.Write([]byte("{\"corrupted\n"))
+		require.Nil(t, err)
+		require.Nil(t, fs.Append(createUserAuditEntry("/second", "user")))
+		// a line still being written has no line ending yet
+		_, err = fs.file.Write([]byte("{\"timestamp\":"))
+		require.Nil(t, err)
+
+		entries, err := fs.GetUserEntries("user", 0, 10)
+		require.Nil(t, err)
+		expectedEntries := []core.AuditEntry{
+			createUserAuditEntry("/second", "user"),
+			createUserAuditEntry("/first", "user"),
+		}
+		assert.Equal(t, expectedEntries, entries)
+	})
+	t.Run("concurrent appends and reads should work", func(t *testing.T) {
+		t.Parallel()
+
+		fs, err := NewFileSink(filepath.Join(t.TempDir(), "audit.jsonl"))
+		require.Nil(t, err)
+		defer func() {
+			_ = fs.Close()
+		}()
+
+		numEntries := 100
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < numEntries; i++ {
+				assert.Nil(t, fs.Append(createUserAuditEntry("/append", "user")))
+			}
+		}()
+		for i := 0; i < numEntries; i++ {
+			_, errGet := fs.GetUserEntries("user", 0, uint32(numEntries))
+			assert.Nil(t, errGet)
+		}
+		<-done
+
+		entries, err := fs.GetUserEntries("user", 0, uint32(numEntries))
+		require.Nil(t, err)
+		assert.Equal(t, numEntries, len(entries))
+	})
+}
+
+func createUserAuditEntry(route string, userAddress string) core.AuditEntry {
+	entry := createAuditEntry(route)
+	entry.UserAddress = userAddress
+
+	return entry
+}
+
+func TestStorerSink(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil storer should error", func(t *testing.T) {
+		t.Parallel()
+
+		ss, err := NewStorerSink(nil)
+		assert.Nil(t, ss)
+		assert.Equal(t, ErrNilStorer, err)
+	})
+	t.Run("should put each entry under the next index of the user", func(t *testing.T) {
+		t.Parallel()
+
+		storedData := make(map[string][]byte)
+		wasClosed := false
+		storer := &testscommon.StorerStub{
+			PutCalled: func(key, data []byte) error {
+				storedData[string(key)] = data
+				return nil
+			},
+			GetCalled: func(key []byte) ([]byte, error) {
+				value, found := storedData[string(key)]
+				if !found {
+					return nil, storage.ErrKeyNotFound
+				}
+				return value, nil
+			},
+			CloseCalled: func() error {
+				wasClosed = true
+				return nil
+			},
+		}
+		ss, err := NewStorerSink(storer)
+		require.Nil(t, err)
+		assert.False(t, ss.IsInterfaceNil())
+
+		require.Nil(t, ss.Append(createUserAuditEntry("/first", "user")))
+		require.Nil(t, ss.Append(createUserAuditEntry("/other", "other user")))
+		require.Nil(t, ss.Append(createUserAuditEntry("/second", "user")))
+		require.Equal(t, 5, len(storedData))
+		assert.Equal(t, []byte("2"), storedData["user_count"])
+
+		storedEntry := core.AuditEntry{}
+		require.Nil(t, json.Unmarshal(storedData["user_0000000001"], &storedEntry))
+		assert.Equal(t, createUserAuditEntry("/second", "user"), storedEntry)
+
+		entries, err := ss.GetUserEntries("user", 0, 10)
+		require.Nil(t, err)
+		assert.Equal(t, []core.AuditEntry{createUserAuditEntry("/second", "user"), createUserAuditEntry("/first", "user")}, entries)
+
+		entries, err = ss.GetUserEntries("user", 1, 10)
+		require.Nil(t, err)
+		assert.Equal(t, []core.AuditEntry{createUserAuditEntry("/first", "user")}, entries)
+
+		entries, err = ss.GetUserEntries("user", 0, 1)
+		require.Nil(t, err)
+		assert.Equal(t, []core.AuditEntry{createUserAuditEntry("/second", "user")}, entries)
+
+		entries, err = ss.GetUserEntries("user", 2, 10)
+		require.Nil(t, err)
+		assert.Equal(t, 0, len(entries))
+
+		entries, err = ss.GetUserEntries("unknown user", 0, 10)
+		require.Nil(t, err)
+		assert.Equal(t, 0, len(entries))
+
+		require.Nil(t, ss.Close())
+		assert.True(t, wasClosed)
+	})
+	t.Run("storer put error should be returned", func(t *testing.T) {
+		t.Parallel()
+
+		storer := &testscommon.StorerStub{
+			GetCalled: func(key []byte) ([]byte, error) {
+				return nil, storage.ErrKeyNotFound
+			},
+			PutCalled: func(key, data []byte) error {
+				return expectedErr
+			},
+		}
+		ss, _ := NewStorerSink(storer)
+
+		assert.Equal(t, expectedErr, ss.Append(createAuditEntry("/first")))
+	})
+	t.Run("storer get error should be returned", func(t *testing.T) {
+		t.Parallel()
+
+		storer := &testscommon.StorerStub{
+			GetCalled: func(key []byte) ([]byte, error) {
+				return nil, expectedErr
+			},
+			PutCalled: func(key, data []byte) error {
+				assert.Fail(t, "should have not been called")
+				return nil
+			},
+		}
+		ss, _ := NewStorerSink(storer)
+
+		assert.Equal(t, expectedErr, ss.Append(createAuditEntry("/first")))
+
+		entries, err := ss.GetUserEntries("user", 0, 10)
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, entries)
+	})
+}
+
+func TestMongoDBSink(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil client should error", func(t *testing.T) {
+		t.Parallel()
+
+		ms, err := NewMongoDBSink(nil)
+		assert.Nil(t, ms)
+		assert.Equal(t, ErrNilMongoDBClient, err)
+	})
+	t.Run("should insert into the audit collection", func(t *testing.T) {
+		t.Parallel()
+
+		wasCalled := false
+		client := &testscommon.MongoDBClientStub{
+			InsertOneCalled: func(coll mongodb.CollectionID, document interface{}) error {
+				assert.Equal(t, mongodb.AuditCollectionID, coll)
+				assert.Equal(t, createAuditEntry("/first"), document)
+				wasCalled = true
+				return nil
+			},
+		}
+		ms, err := NewMongoDBSink(client)
+		require.Nil(t, err)
+		assert.False(t, ms.IsInterfaceNil())
+
+		require.Nil(t, ms.Append(createAuditEntry("/first")))
+		assert.True(t, wasCalled)
+	})
+	t.Run("should find the entries of the user, newest first", func(t *testing.T) {
+		t.Parallel()
+
+		client := &testscommon.MongoDBClientStub{
+			FindCalled: func(coll mongodb.CollectionID, filter interface{}, opts *options.FindOptions, results interface{}) error {
+				assert.Equal(t, mongodb.AuditCollectionID, coll)
+				assert.Equal(t, bson.D{{Key: "user_address", Value: "user"}}, filter)
+				assert.Equal(t, int64(2), *opts.Skip)
+				assert.Equal(t, int64(3), *opts.Limit)
+				assert.Equal(t, bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}, opts.Sort)
+
+				entries := results.(*[]core.AuditEntry)
+				*entries = append(*entries, createAuditEntry("/first"))
+				return nil
+			},
+		}
+		ms, _ := NewMongoDBSink(client)
+
+		entries, err := ms.GetUserEntries("user", 2, 3)
+		require.Nil(t, err)
+		assert.Equal(t, []core.AuditEntry{createAuditEntry("/first")}, entries)
+	})
+	t.Run("find error should be returned", func(t *testing.T) {
+		t.Parallel()
+
+		client := &testscommon.MongoDBClientStub{
+			FindCalled: func(coll mongodb.CollectionID, filter interface{}, opts *options.FindOptions, results interface{}) error {
+				return expectedErr
+			},
+		}
+		ms, _ := NewMongoDBSink(client)
+
+		entries, err := ms.GetUserEntries("user", 0, 3)
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, entries)
+	})
+}
+
+func TestDisabledSink(t *testing.T) {
+	t.Parallel()
+
+	ds := NewDisabledSink()
+	assert.False(t, ds.IsInterfaceNil())
+	assert.Nil(t, ds.Append(createAuditEntry("/first")))
+
+	entries, err := ds.GetUserEntries("user", 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(entries))
+	assert.Nil(t, ds.Close())
+}
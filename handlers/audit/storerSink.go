@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage"
+)
+
+const countKeySuffix = "count"
+
+type storerSink struct {
+	mut    sync.Mutex
+	storer core.Storer
+}
+
+// NewStorerSink returns a new instance of storerSink, which saves the audit entries into a key-value storer (eg: levelDB).
+// The entries of each user are saved under consecutive indexes, along with their count
+func NewStorerSink(storer core.Storer) (*storerSink, error) {
+	if check.IfNil(storer) {
+		return nil, ErrNilStorer
+	}
+
+	return &storerSink{
+		storer: storer,
+	}, nil
+}
+
+// Append saves the entry under the next index of the user, never overwriting a previous one
+func (ss *storerSink) Append(entry core.AuditEntry) error {
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	ss.mut.Lock()
+	defer ss.mut.Unlock()
+
+	count, err := ss.getCount(entry.UserAddress)
+	if err != nil {
+		return err
+	}
+
+	err = ss.storer.Put(createEntryKey(entry.UserAddress, count), value)
+	if err != nil {
+		return err
+	}
+
+	return ss.storer.Put(createCountKey(entry.UserAddress), []byte(strconv.FormatUint(uint64(count+1), 10)))
+}
+
+// GetUserEntries returns the entries of the user, newest first
+func (ss *storerSink) GetUserEntries(userAddress string, offset uint32, limit uint32) ([]core.AuditEntry, error) {
+	ss.mut.Lock()
+	count, err := ss.getCount(userAddress)
+	ss.mut.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]core.AuditEntry, 0, limit)
+	for idx := int64(count) - 1 - int64(offset); idx >= 0 && uint32(len(entries)) < limit; idx-- {
+		value, err := ss.storer.Get(createEntryKey(userAddress, uint32(idx)))
+		if err != nil {
+			return nil, err
+		}
+
+		entry := core.AuditEntry{}
+		err = json.Unmarshal(value, &entry)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (ss *storerSink) getCount(userAddress string) (uint32, error) {
+	value, err := ss.storer.Get(createCountKey(userAddress))
+	if errors.Is(err, storage.ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := strconv.ParseUint(string(value), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(count), nil
+}
+
+func createEntryKey(userAddress string, index uint32) []byte {
+	return []byte(fmt.Sprintf("%s_%010d", userAddress, index))
+}
+
+func createCountKey(userAddress string) []byte {
+	return []byte(fmt.Sprintf("%s_%s", userAddress, countKeySuffix))
+}
+
+// Close closes the underlying storer
+func (ss *storerSink) Close() error {
+	return ss.storer.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ss *storerSink) IsInterfaceNil() bool {
+	return ss == nil
+}
@@ -0,0 +1,118 @@
+package chainapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/api"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/multiversx/mx-sdk-go/data"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+)
+
+// ArgsCachedHttpClientWrapper is the DTO used to create a new instance of cachedHttpClientWrapper
+type ArgsCachedHttpClientWrapper struct {
+	HttpClientWrapper core.HttpClientWrapper
+	Cacher            types.Cacher
+	// GuardianDataTTL is the time the guardian data of an account is served from the cache
+	GuardianDataTTL time.Duration
+}
+
+type cachedGuardianData struct {
+	guardianData *api.GuardianData
+	expireAt     time.Time
+}
+
+type cachedHttpClientWrapper struct {
+	httpClientWrapper core.HttpClientWrapper
+	cacher            types.Cacher
+	guardianDataTTL   time.Duration
+	getTimeHandler    func() time.Time
+}
+
+// NewCachedHttpClientWrapper returns a new instance of cachedHttpClientWrapper, which keeps the guardian data of
+// the accounts for a short time, so that successive requests of the same user do not reach the chain API.
+// The accounts are not cached, as they are only fetched on the first registration
+func NewCachedHttpClientWrapper(args ArgsCachedHttpClientWrapper) (*cachedHttpClientWrapper, error) {
+	if check.IfNil(args.HttpClientWrapper) {
+		return nil, ErrNilHttpClientWrapper
+	}
+	if check.IfNil(args.Cacher) {
+		return nil, ErrNilCacher
+	}
+	if args.GuardianDataTTL <= 0 {
+		return nil, fmt.Errorf("%w for GuardianDataTTL, got %v", ErrInvalidValue, args.GuardianDataTTL)
+	}
+
+	return &cachedHttpClientWrapper{
+		httpClientWrapper: args.HttpClientWrapper,
+		cacher:            args.Cacher,
+		guardianDataTTL:   args.GuardianDataTTL,
+		getTimeHandler:    time.Now,
+	}, nil
+}
+
+// GetAccount returns the account for the provided address, from the chain API
+func (chcw *cachedHttpClientWrapper) GetAccount(ctx context.Context, address string) (*data.Account, error) {
+	return chcw.httpClientWrapper.GetAccount(ctx, address)
+}
+
+// GetGuardianData returns the guardian data for the provided address, from the cache if not expired
+func (chcw *cachedHttpClientWrapper) GetGuardianData(ctx context.Context, address string) (*api.GuardianData, error) {
+	value, found := chcw.cacher.Get([]byte(address))
+	if found {
+		cached, ok := value.(*cachedGuardianData)
+		if ok && chcw.getTimeHandler().Before(cached.expireAt) {
+			return copyGuardianData(cached.guardianData), nil
+		}
+	}
+
+	return chcw.GetFreshGuardianData(ctx, address)
+}
+
+// GetFreshGuardianData returns the guardian data for the provided address from the chain API, ignoring the cache.
+// It should be used before the decisions which cannot rely on data a few seconds old. The cache is refreshed
+func (chcw *cachedHttpClientWrapper) GetFreshGuardianData(ctx context.Context, address string) (*api.GuardianData, error) {
+	guardianData, err := chcw.httpClientWrapper.GetGuardianData(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	chcw.cacher.Put([]byte(address), &cachedGuardianData{
+		guardianData: copyGuardianData(guardianData),
+		expireAt:     chcw.getTimeHandler().Add(chcw.guardianDataTTL),
+	}, 0)
+
+	return guardianData, nil
+}
+
+// InvalidateGuardianData removes the guardian data of the provided address from the cache, so that the next
+// request fetches it from the chain API
+func (chcw *cachedHttpClientWrapper) InvalidateGuardianData(address string) {
+	chcw.cacher.Remove([]byte(address))
+	chcw.httpClientWrapper.InvalidateGuardianData(address)
+}
+
+func copyGuardianData(guardianData *api.GuardianData) *api.GuardianData {
+	guardianDataCopy := &api.GuardianData{
+		Guarded: guardianData.Guarded,
+	}
+	if guardianData.ActiveGuardian != nil {
+		activeGuardian := *guardianData.ActiveGuardian
+		guardianDataCopy.ActiveGuardian = &activeGuardian
+	}
+	if guardianData.PendingGuardian != nil {
+		pendingGuardian := *guardianData.PendingGuardian
+		guardianDataCopy.PendingGuardian = &pendingGuardian
+	}
+
+	return guardianDataCopy
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (chcw *cachedHttpClientWrapper) IsInterfaceNil() bool {
+	return chcw == nil
+}
@@ -0,0 +1,213 @@
+package chainapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/data/api"
+	"github.com/multiversx/mx-chain-storage-go/lrucache"
+	"github.com/multiversx/mx-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+const providedAddress = "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th"
+
+func createMockArgsCachedHttpClientWrapper(httpClientWrapper *testscommon.HttpClientWrapperStub) ArgsCachedHttpClientWrapper {
+	cacher, _ := lrucache.NewCache(10)
+	return ArgsCachedHttpClientWrapper{
+		HttpClientWrapper: httpClientWrapper,
+		Cacher:            cacher,
+		GuardianDataTTL:   time.Minute,
+	}
+}
+
+func TestNewCachedHttpClientWrapper(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil http client wrapper should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsCachedHttpClientWrapper(nil)
+		args.HttpClientWrapper = nil
+		wrapper, err := NewCachedHttpClientWrapper(args)
+		assert.Equal(t, ErrNilHttpClientWrapper, err)
+		assert.Nil(t, wrapper)
+	})
+	t.Run("nil cacher should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsCachedHttpClientWrapper(&testscommon.HttpClientWrapperStub{})
+		args.Cacher = nil
+		wrapper, err := NewCachedHttpClientWrapper(args)
+		assert.Equal(t, ErrNilCacher, err)
+		assert.Nil(t, wrapper)
+	})
+	t.Run("invalid ttl should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsCachedHttpClientWrapper(&testscommon.HttpClientWrapperStub{})
+		args.GuardianDataTTL = 0
+		wrapper, err := NewCachedHttpClientWrapper(args)
+		assert.True(t, errors.Is(err, ErrInvalidValue))
+		assert.Nil(t, wrapper)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		wrapper, err := NewCachedHttpClientWrapper(createMockArgsCachedHttpClientWrapper(&testscommon.HttpClientWrapperStub{}))
+		assert.Nil(t, err)
+		assert.False(t, wrapper.IsInterfaceNil())
+	})
+}
+
+func TestCachedHttpClientWrapper_GetAccount(t *testing.T) {
+	t.Parallel()
+
+	numCalls := 0
+	wrapper, _ := NewCachedHttpClientWrapper(createMockArgsCachedHttpClientWrapper(&testscommon.HttpClientWrapperStub{
+		GetAccountCalled: func(ctx context.Context, address string) (*data.Account, error) {
+			numCalls++
+			return &data.Account{Address: address, Balance: "10"}, nil
+		},
+	}))
+
+	for i := 0; i < 2; i++ {
+		account, err := wrapper.GetAccount(context.Background(), providedAddress)
+		require.Nil(t, err)
+		assert.Equal(t, "10", account.Balance)
+	}
+	assert.Equal(t, 2, numCalls)
+}
+
+func TestCachedHttpClientWrapper_GetGuardianData(t *testing.T) {
+	t.Parallel()
+
+	t.Run("error should not be cached", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := 0
+		wrapper, _ := NewCachedHttpClientWrapper(createMockArgsCachedHttpClientWrapper(&testscommon.HttpClientWrapperStub{
+			GetGuardianDataCalled: func(ctx context.Context, address string) (*api.GuardianData, error) {
+				numCalls++
+				return nil, expectedErr
+			},
+		}))
+
+		for i := 0; i < 2; i++ {
+			guardianData, err := wrapper.GetGuardianData(context.Background(), providedAddress)
+			assert.Equal(t, expectedErr, err)
+			assert.Nil(t, guardianData)
+		}
+		assert.Equal(t, 2, numCalls)
+	})
+	t.Run("should be cached until expired or invalidated", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := 0
+		invalidatedAddresses := make([]string, 0)
+		wrapper, _ := NewCachedHttpClientWrapper(createMockArgsCachedHttpClientWrapper(&testscommon.HttpClientWrapperStub{
+			GetGuardianDataCalled: func(ctx context.Context, address string) (*api.GuardianData, error) {
+				numCalls++
+				return &api.GuardianData{
+					ActiveGuardian: &api.Guardian{Address: "guardian"},
+					Guarded:        true,
+				}, nil
+			},
+			InvalidateGuardianDataCalled: func(address string) {
+				invalidatedAddresses = append(invalidatedAddresses, address)
+			},
+		}))
+		currentTime := time.Now()
+		wrapper.getTimeHandler = func() time.Time {
+			return currentTime
+		}
+
+		guardianData, err := wrapper.GetGuardianData(context.Background(), providedAddress)
+		require.Nil(t, err)
+		assert.Equal(t, 1, numCalls)
+
+		// the returned data can be changed by the caller
+		guardianData.ActiveGuardian.Address = "changed"
+		guardianData, err = wrapper.GetGuardianData(context.Background(), providedAddress)
+		require.Nil(t, err)
+		assert.Equal(t, "guardian", guardianData.ActiveGuardian.Address)
+		assert.Nil(t, guardianData.PendingGuardian)
+		assert.True(t, guardianData.Guarded)
+		assert.Equal(t, 1, numCalls)
+
+		_, _ = wrapper.GetGuardianData(context.Background(), "other address")
+		assert.Equal(t, 2, numCalls)
+
+		currentTime = currentTime.Add(time.Minute)
+		_, _ = wrapper.GetGuardianData(context.Background(), providedAddress)
+		assert.Equal(t, 3, numCalls)
+		_, _ = wrapper.GetGuardianData(context.Background(), providedAddress)
+		assert.Equal(t, 3, numCalls)
+
+		wrapper.InvalidateGuardianData(providedAddress)
+		assert.Equal(t, []string{providedAddress}, invalidatedAddresses)
+		_, _ = wrapper.GetGuardianData(context.Background(), providedAddress)
+		assert.Equal(t, 4, numCalls)
+	})
+}
+
+func TestCachedHttpClientWrapper_GetFreshGuardianData(t *testing.T) {
+	t.Parallel()
+
+	t.Run("error should not change the cache", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := 0
+		wrapper, _ := NewCachedHttpClientWrapper(createMockArgsCachedHttpClientWrapper(&testscommon.HttpClientWrapperStub{
+			GetGuardianDataCalled: func(ctx context.Context, address string) (*api.GuardianData, error) {
+				numCalls++
+				if numCalls > 1 {
+					return nil, expectedErr
+				}
+				return &api.GuardianData{Guarded: true}, nil
+			},
+		}))
+
+		_, err := wrapper.GetGuardianData(context.Background(), providedAddress)
+		require.Nil(t, err)
+
+		guardianData, err := wrapper.GetFreshGuardianData(context.Background(), providedAddress)
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, guardianData)
+
+		guardianData, err = wrapper.GetGuardianData(context.Background(), providedAddress)
+		require.Nil(t, err)
+		assert.True(t, guardianData.Guarded)
+		assert.Equal(t, 2, numCalls)
+	})
+	t.Run("should bypass and refresh the cache", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := 0
+		wrapper, _ := NewCachedHttpClientWrapper(createMockArgsCachedHttpClientWrapper(&testscommon.HttpClientWrapperStub{
+			GetGuardianDataCalled: func(ctx context.Context, address string) (*api.GuardianData, error) {
+				numCalls++
+				return &api.GuardianData{Guarded: numCalls > 1}, nil
+			},
+		}))
+
+		guardianData, err := wrapper.GetGuardianData(context.Background(), providedAddress)
+		require.Nil(t, err)
+		assert.False(t, guardianData.Guarded)
+
+		guardianData, err = wrapper.GetFreshGuardianData(context.Background(), providedAddress)
+		require.Nil(t, err)
+		assert.True(t, guardianData.Guarded)
+		assert.Equal(t, 2, numCalls)
+
+		guardianData, err = wrapper.GetGuardianData(context.Background(), providedAddress)
+		require.Nil(t, err)
+		assert.True(t, guardianData.Guarded)
+		assert.Equal(t, 2, numCalls)
+	})
+}
@@ -0,0 +1,24 @@
+package chainapi
+
+import "errors"
+
+// ErrNoEndpoint is returned when no chain API endpoint is provided
+var ErrNoEndpoint = errors.New("no chain API endpoint")
+
+// ErrEmptyNetworkAddress is returned when an endpoint without network address is provided
+var ErrEmptyNetworkAddress = errors.New("empty network address")
+
+// ErrNilHttpClient is returned when an endpoint without http client is provided
+var ErrNilHttpClient = errors.New("nil http client")
+
+// ErrNilMetricsHandler is returned when a nil metrics handler is provided
+var ErrNilMetricsHandler = errors.New("nil metrics handler")
+
+// ErrNilHttpClientWrapper is returned when a nil http client wrapper is provided
+var ErrNilHttpClientWrapper = errors.New("nil http client wrapper")
+
+// ErrNilCacher is returned when a nil cacher is provided
+var ErrNilCacher = errors.New("nil cacher")
+
+// ErrInvalidValue is returned when an invalid value is provided
+var ErrInvalidValue = errors.New("invalid value")
@@ -0,0 +1,224 @@
+package chainapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-sdk-go/authentication"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/metrics"
+)
+
+const (
+	metricPrefix = "chain-api"
+	// transportErrorCode is recorded in the metrics for the requests which got no response
+	transportErrorCode = http.StatusBadGateway
+)
+
+var log = logger.GetOrCreate("chainapi")
+
+// Endpoint is a chain API endpoint, together with the client used to call it
+type Endpoint struct {
+	NetworkAddress string
+	Client         authentication.HttpClientWrapper
+}
+
+// ArgsFailoverHttpClient is the DTO used to create a new instance of failoverHttpClient
+type ArgsFailoverHttpClient struct {
+	// Endpoints are the chain API endpoints, in the order of preference
+	Endpoints []Endpoint
+	// UnhealthyBackoff is the time an endpoint is skipped for, after it failed
+	UnhealthyBackoff time.Duration
+	MetricsHandler   core.StatusMetricsHandler
+}
+
+type endpointState struct {
+	Endpoint
+	unhealthyUntil time.Time
+}
+
+type failoverHttpClient struct {
+	endpoints        []*endpointState
+	unhealthyBackoff time.Duration
+	metricsHandler   core.StatusMetricsHandler
+	getTimeHandler   func() time.Time
+	mut              sync.RWMutex
+}
+
+// NewFailoverHttpClient returns a new instance of failoverHttpClient, which sends each request to the first healthy
+// endpoint and fails over to the next ones when an endpoint cannot be reached, responds with a server error or
+// throttles the requests. A failed endpoint is marked unhealthy and skipped until the backoff expires, unless all
+// the endpoints are unhealthy. The requests and the upstream errors are recorded in the metrics of each endpoint
+func NewFailoverHttpClient(args ArgsFailoverHttpClient) (*failoverHttpClient, error) {
+	err := checkFailoverArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := make([]*endpointState, 0, len(args.Endpoints))
+	for _, endpoint := range args.Endpoints {
+		endpoints = append(endpoints, &endpointState{
+			Endpoint: endpoint,
+		})
+	}
+
+	return &failoverHttpClient{
+		endpoints:        endpoints,
+		unhealthyBackoff: args.UnhealthyBackoff,
+		metricsHandler:   args.MetricsHandler,
+		getTimeHandler:   time.Now,
+	}, nil
+}
+
+func checkFailoverArgs(args ArgsFailoverHttpClient) error {
+	if len(args.Endpoints) == 0 {
+		return ErrNoEndpoint
+	}
+	for i, endpoint := range args.Endpoints {
+		if len(endpoint.NetworkAddress) == 0 {
+			return fmt.Errorf("%w for endpoint %d", ErrEmptyNetworkAddress, i)
+		}
+		if check.IfNil(endpoint.Client) {
+			return fmt.Errorf("%w for endpoint %s", ErrNilHttpClient, endpoint.NetworkAddress)
+		}
+	}
+	if args.UnhealthyBackoff <= 0 {
+		return fmt.Errorf("%w for UnhealthyBackoff, got %v", ErrInvalidValue, args.UnhealthyBackoff)
+	}
+	if check.IfNil(args.MetricsHandler) {
+		return ErrNilMetricsHandler
+	}
+
+	return nil
+}
+
+// GetHTTP does a GET request on the provided endpoint, failing over to the next chain API endpoints if needed.
+// The response of the last tried endpoint is returned if all of them failed
+func (fhc *failoverHttpClient) GetHTTP(ctx context.Context, endpoint string) ([]byte, int, error) {
+	return fhc.doWithFailover(ctx, func(client authentication.HttpClientWrapper) ([]byte, int, error) {
+		return client.GetHTTP(ctx, endpoint)
+	})
+}
+
+// PostHTTP does a POST request on the provided endpoint, failing over to the next chain API endpoints if needed.
+// Only read requests are sent to the chain API, so resending the request to another endpoint is safe
+func (fhc *failoverHttpClient) PostHTTP(ctx context.Context, endpoint string, data []byte) ([]byte, int, error) {
+	return fhc.doWithFailover(ctx, func(client authentication.HttpClientWrapper) ([]byte, int, error) {
+		return client.PostHTTP(ctx, endpoint, data)
+	})
+}
+
+func (fhc *failoverHttpClient) doWithFailover(
+	ctx context.Context,
+	request func(client authentication.HttpClientWrapper) ([]byte, int, error),
+) ([]byte, int, error) {
+	var buff []byte
+	var code int
+	var err error
+	for _, state := range fhc.getEndpointsInOrder() {
+		startTime := time.Now()
+		buff, code, err = request(state.Client)
+		duration := time.Since(startTime)
+		if !isUpstreamFailure(code, err) {
+			fhc.markHealthy(state)
+			fhc.metricsHandler.AddRequestData(getOpID(state.NetworkAddress), duration, getMetricsStatus(code))
+			return buff, code, err
+		}
+
+		metricsCode := code
+		if err != nil {
+			metricsCode = transportErrorCode
+		}
+		fhc.metricsHandler.AddRequestData(getOpID(state.NetworkAddress), duration, metricsCode)
+		fhc.markUnhealthy(state, code, err)
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return buff, code, err
+}
+
+// getEndpointsInOrder returns the healthy endpoints in the configured order, followed by the unhealthy ones,
+// the ones which become healthy first being tried first
+func (fhc *failoverHttpClient) getEndpointsInOrder() []*endpointState {
+	fhc.mut.RLock()
+	defer fhc.mut.RUnlock()
+
+	now := fhc.getTimeHandler()
+	healthy := make([]*endpointState, 0, len(fhc.endpoints))
+	unhealthy := make([]*endpointState, 0)
+	for _, state := range fhc.endpoints {
+		if now.Before(state.unhealthyUntil) {
+			unhealthy = append(unhealthy, state)
+			continue
+		}
+
+		healthy = append(healthy, state)
+	}
+
+	sort.SliceStable(unhealthy, func(i, j int) bool {
+		return unhealthy[i].unhealthyUntil.Before(unhealthy[j].unhealthyUntil)
+	})
+
+	return append(healthy, unhealthy...)
+}
+
+func (fhc *failoverHttpClient) markHealthy(state *endpointState) {
+	fhc.mut.Lock()
+	defer fhc.mut.Unlock()
+
+	if state.unhealthyUntil.IsZero() {
+		return
+	}
+
+	state.unhealthyUntil = time.Time{}
+	log.Info("chain API endpoint is healthy again", "network address", state.NetworkAddress)
+}
+
+func (fhc *failoverHttpClient) markUnhealthy(state *endpointState, code int, err error) {
+	fhc.mut.Lock()
+	defer fhc.mut.Unlock()
+
+	state.unhealthyUntil = fhc.getTimeHandler().Add(fhc.unhealthyBackoff)
+	log.Warn("chain API endpoint failed, marked as unhealthy",
+		"network address", state.NetworkAddress,
+		"code", code,
+		"error", err,
+		"retry after", fhc.unhealthyBackoff)
+}
+
+// isUpstreamFailure returns true if the endpoint could not handle the request, as opposed to the request
+// itself being rejected, for example for an invalid address
+func isUpstreamFailure(code int, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	return code >= http.StatusInternalServerError || code == http.StatusTooManyRequests
+}
+
+func getMetricsStatus(code int) int {
+	if code == http.StatusOK {
+		return metrics.NonErrorCode
+	}
+
+	return code
+}
+
+func getOpID(networkAddress string) string {
+	return fmt.Sprintf("%s-%s", metricPrefix, networkAddress)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (fhc *failoverHttpClient) IsInterfaceNil() bool {
+	return fhc == nil
+}
@@ -0,0 +1,260 @@
+package chainapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	sdkTestsCommon "github.com/multiversx/mx-sdk-go/testsCommon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/metrics"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+var expectedErr = errors.New("expected error")
+
+type recordedRequest struct {
+	networkAddress string
+	endpoint       string
+}
+
+func createEndpoint(networkAddress string, requests *[]recordedRequest, code int, err error) Endpoint {
+	return Endpoint{
+		NetworkAddress: networkAddress,
+		Client: &sdkTestsCommon.HTTPClientWrapperStub{
+			GetHTTPCalled: func(ctx context.Context, endpoint string) ([]byte, int, error) {
+				*requests = append(*requests, recordedRequest{networkAddress: networkAddress, endpoint: endpoint})
+				return []byte(networkAddress), code, err
+			},
+			PostHTTPCalled: func(ctx context.Context, endpoint string, data []byte) ([]byte, int, error) {
+				*requests = append(*requests, recordedRequest{networkAddress: networkAddress, endpoint: endpoint})
+				return []byte(networkAddress), code, err
+			},
+		},
+	}
+}
+
+func createMockArgsFailoverHttpClient(endpoints ...Endpoint) ArgsFailoverHttpClient {
+	return ArgsFailoverHttpClient{
+		Endpoints:        endpoints,
+		UnhealthyBackoff: time.Minute,
+		MetricsHandler:   &testscommon.StatusMetricsStub{},
+	}
+}
+
+func TestNewFailoverHttpClient(t *testing.T) {
+	t.Parallel()
+
+	requests := make([]recordedRequest, 0)
+	t.Run("no endpoint should error", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewFailoverHttpClient(createMockArgsFailoverHttpClient())
+		assert.Equal(t, ErrNoEndpoint, err)
+		assert.Nil(t, client)
+	})
+	t.Run("empty network address should error", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewFailoverHttpClient(createMockArgsFailoverHttpClient(createEndpoint("", &requests, http.StatusOK, nil)))
+		assert.True(t, errors.Is(err, ErrEmptyNetworkAddress))
+		assert.Nil(t, client)
+	})
+	t.Run("nil http client should error", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewFailoverHttpClient(createMockArgsFailoverHttpClient(Endpoint{NetworkAddress: "primary"}))
+		assert.True(t, errors.Is(err, ErrNilHttpClient))
+		assert.Nil(t, client)
+	})
+	t.Run("invalid unhealthy backoff should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsFailoverHttpClient(createEndpoint("primary", &requests, http.StatusOK, nil))
+		args.UnhealthyBackoff = 0
+		client, err := NewFailoverHttpClient(args)
+		assert.True(t, errors.Is(err, ErrInvalidValue))
+		assert.Nil(t, client)
+	})
+	t.Run("nil metrics handler should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsFailoverHttpClient(createEndpoint("primary", &requests, http.StatusOK, nil))
+		args.MetricsHandler = nil
+		client, err := NewFailoverHttpClient(args)
+		assert.Equal(t, ErrNilMetricsHandler, err)
+		assert.Nil(t, client)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewFailoverHttpClient(createMockArgsFailoverHttpClient(createEndpoint("primary", &requests, http.StatusOK, nil)))
+		assert.Nil(t, err)
+		assert.False(t, client.IsInterfaceNil())
+	})
+}
+
+func TestFailoverHttpClient_GetHTTP(t *testing.T) {
+	t.Parallel()
+
+	t.Run("healthy primary should be used", func(t *testing.T) {
+		t.Parallel()
+
+		requests := make([]recordedRequest, 0)
+		recordedMetrics := make(map[string]int)
+		args := createMockArgsFailoverHttpClient(
+			createEndpoint("primary", &requests, http.StatusOK, nil),
+			createEndpoint("fallback", &requests, http.StatusOK, nil),
+		)
+		args.MetricsHandler = &testscommon.StatusMetricsStub{
+			AddRequestDataCalled: func(path string, duration time.Duration, status int) {
+				recordedMetrics[path] = status
+			},
+		}
+		client, _ := NewFailoverHttpClient(args)
+
+		buff, code, err := client.GetHTTP(context.Background(), "address/erd1")
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []byte("primary"), buff)
+		assert.Equal(t, []recordedRequest{{networkAddress: "primary", endpoint: "address/erd1"}}, requests)
+		assert.Equal(t, map[string]int{"chain-api-primary": metrics.NonErrorCode}, recordedMetrics)
+	})
+	t.Run("rejected request should not fail over", func(t *testing.T) {
+		t.Parallel()
+
+		requests := make([]recordedRequest, 0)
+		client, _ := NewFailoverHttpClient(createMockArgsFailoverHttpClient(
+			createEndpoint("primary", &requests, http.StatusNotFound, nil),
+			createEndpoint("fallback", &requests, http.StatusOK, nil),
+		))
+
+		buff, code, err := client.GetHTTP(context.Background(), "address/invalid")
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, []byte("primary"), buff)
+		assert.Len(t, requests, 1)
+	})
+	t.Run("failed primary should be skipped until the backoff expires", func(t *testing.T) {
+		t.Parallel()
+
+		requests := make([]recordedRequest, 0)
+		recordedMetrics := make(map[string]int)
+		primaryCode := http.StatusInternalServerError
+		args := createMockArgsFailoverHttpClient(
+			Endpoint{
+				NetworkAddress: "primary",
+				Client: &sdkTestsCommon.HTTPClientWrapperStub{
+					GetHTTPCalled: func(ctx context.Context, endpoint string) ([]byte, int, error) {
+						requests = append(requests, recordedRequest{networkAddress: "primary", endpoint: endpoint})
+						return []byte("primary"), primaryCode, nil
+					},
+				},
+			},
+			createEndpoint("fallback", &requests, http.StatusOK, nil),
+		)
+		args.MetricsHandler = &testscommon.StatusMetricsStub{
+			AddRequestDataCalled: func(path string, duration time.Duration, status int) {
+				recordedMetrics[path] = status
+			},
+		}
+		client, _ := NewFailoverHttpClient(args)
+		currentTime := time.Now()
+		client.getTimeHandler = func() time.Time {
+			return currentTime
+		}
+
+		buff, code, err := client.GetHTTP(context.Background(), "endpoint")
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []byte("fallback"), buff)
+		assert.Equal(t, []string{"primary", "fallback"}, getNetworkAddresses(requests))
+		assert.Equal(t, map[string]int{"chain-api-primary": http.StatusInternalServerError, "chain-api-fallback": metrics.NonErrorCode}, recordedMetrics)
+
+		requests = requests[:0]
+		buff, _, _ = client.GetHTTP(context.Background(), "endpoint")
+		assert.Equal(t, []byte("fallback"), buff)
+		assert.Equal(t, []string{"fallback"}, getNetworkAddresses(requests))
+
+		primaryCode = http.StatusOK
+		currentTime = currentTime.Add(time.Minute)
+		requests = requests[:0]
+		buff, _, _ = client.GetHTTP(context.Background(), "endpoint")
+		assert.Equal(t, []byte("primary"), buff)
+		assert.Equal(t, []string{"primary"}, getNetworkAddresses(requests))
+	})
+	t.Run("all endpoints failed should try all of them and return the last response", func(t *testing.T) {
+		t.Parallel()
+
+		requests := make([]recordedRequest, 0)
+		recordedMetrics := make(map[string]int)
+		args := createMockArgsFailoverHttpClient(
+			createEndpoint("primary", &requests, http.StatusBadRequest, expectedErr),
+			createEndpoint("fallback", &requests, http.StatusTooManyRequests, nil),
+		)
+		args.MetricsHandler = &testscommon.StatusMetricsStub{
+			AddRequestDataCalled: func(path string, duration time.Duration, status int) {
+				recordedMetrics[path] = status
+			},
+		}
+		client, _ := NewFailoverHttpClient(args)
+
+		buff, code, err := client.GetHTTP(context.Background(), "endpoint")
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusTooManyRequests, code)
+		assert.Equal(t, []byte("fallback"), buff)
+		assert.Equal(t, map[string]int{"chain-api-primary": transportErrorCode, "chain-api-fallback": http.StatusTooManyRequests}, recordedMetrics)
+
+		// both unhealthy, the one which becomes healthy first is tried first
+		client.markUnhealthy(client.endpoints[0], http.StatusBadRequest, expectedErr)
+		requests = requests[:0]
+		_, code, err = client.GetHTTP(context.Background(), "endpoint")
+		assert.Equal(t, expectedErr, err)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, []string{"fallback", "primary"}, getNetworkAddresses(requests))
+	})
+	t.Run("expired context should stop the failover", func(t *testing.T) {
+		t.Parallel()
+
+		requests := make([]recordedRequest, 0)
+		client, _ := NewFailoverHttpClient(createMockArgsFailoverHttpClient(
+			createEndpoint("primary", &requests, http.StatusBadRequest, context.Canceled),
+			createEndpoint("fallback", &requests, http.StatusOK, nil),
+		))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, _, err := client.GetHTTP(ctx, "endpoint")
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, []string{"primary"}, getNetworkAddresses(requests))
+	})
+}
+
+func TestFailoverHttpClient_PostHTTP(t *testing.T) {
+	t.Parallel()
+
+	requests := make([]recordedRequest, 0)
+	client, _ := NewFailoverHttpClient(createMockArgsFailoverHttpClient(
+		createEndpoint("primary", &requests, http.StatusServiceUnavailable, nil),
+		createEndpoint("fallback", &requests, http.StatusOK, nil),
+	))
+
+	buff, code, err := client.PostHTTP(context.Background(), "endpoint", []byte("data"))
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []byte("fallback"), buff)
+	assert.Equal(t, []string{"primary", "fallback"}, getNetworkAddresses(requests))
+}
+
+func getNetworkAddresses(requests []recordedRequest) []string {
+	networkAddresses := make([]string, 0, len(requests))
+	for _, request := range requests {
+		networkAddresses = append(networkAddresses, request.networkAddress)
+	}
+
+	return networkAddresses
+}
@@ -0,0 +1,80 @@
+package chainapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-sdk-go/authentication"
+	"github.com/multiversx/mx-sdk-go/data"
+)
+
+const (
+	apiBlockByHashPrefix          = "blocks/"
+	gatewayHyperBlockByHashFormat = "hyperblock/by-hash/%s"
+)
+
+type gatewayHttpClient struct {
+	httpClient authentication.HttpClientWrapper
+}
+
+// NewGatewayHttpClient returns a new instance of gatewayHttpClient, which lets the service run against a
+// MultiversX proxy (gateway) instead of the API. The accounts and the guardian data are served by the gateway
+// under the same routes and format, so only the blocks/<hash> requests of the native authentication, known only
+// by the API, are translated to the hyperblock/by-hash/<hash> endpoint of the gateway
+func NewGatewayHttpClient(httpClient authentication.HttpClientWrapper) (*gatewayHttpClient, error) {
+	if check.IfNil(httpClient) {
+		return nil, ErrNilHttpClient
+	}
+
+	return &gatewayHttpClient{
+		httpClient: httpClient,
+	}, nil
+}
+
+// GetHTTP does a GET request on the provided endpoint, translated to the gateway format if needed
+func (ghc *gatewayHttpClient) GetHTTP(ctx context.Context, endpoint string) ([]byte, int, error) {
+	if !strings.HasPrefix(endpoint, apiBlockByHashPrefix) {
+		return ghc.httpClient.GetHTTP(ctx, endpoint)
+	}
+
+	hash := strings.TrimPrefix(endpoint, apiBlockByHashPrefix)
+	return ghc.getBlockByHash(ctx, hash)
+}
+
+// getBlockByHash fetches the hyperblock with the provided hash and returns it in the format of the API block
+func (ghc *gatewayHttpClient) getBlockByHash(ctx context.Context, hash string) ([]byte, int, error) {
+	buff, code, err := ghc.httpClient.GetHTTP(ctx, fmt.Sprintf(gatewayHyperBlockByHashFormat, hash))
+	if err != nil || code != http.StatusOK {
+		return buff, code, err
+	}
+
+	var hyperBlockResp data.HyperBlockResponse
+	err = json.Unmarshal(buff, &hyperBlockResp)
+	if err != nil {
+		return nil, code, err
+	}
+
+	block := data.Block{
+		Timestamp: int(hyperBlockResp.Data.HyperBlock.Timestamp),
+	}
+	blockBuff, err := json.Marshal(block)
+	if err != nil {
+		return nil, code, err
+	}
+
+	return blockBuff, code, nil
+}
+
+// PostHTTP does a POST request on the provided endpoint
+func (ghc *gatewayHttpClient) PostHTTP(ctx context.Context, endpoint string, data []byte) ([]byte, int, error) {
+	return ghc.httpClient.PostHTTP(ctx, endpoint, data)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ghc *gatewayHttpClient) IsInterfaceNil() bool {
+	return ghc == nil
+}
@@ -0,0 +1,122 @@
+package chainapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	sdkTestsCommon "github.com/multiversx/mx-sdk-go/testsCommon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGatewayHttpClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil http client should error", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewGatewayHttpClient(nil)
+		assert.Equal(t, ErrNilHttpClient, err)
+		assert.Nil(t, client)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewGatewayHttpClient(&sdkTestsCommon.HTTPClientWrapperStub{})
+		assert.Nil(t, err)
+		assert.False(t, client.IsInterfaceNil())
+	})
+}
+
+func TestGatewayHttpClient_GetHTTP(t *testing.T) {
+	t.Parallel()
+
+	t.Run("account endpoint should not be translated", func(t *testing.T) {
+		t.Parallel()
+
+		providedResponse := []byte(`{"data":{"account":{"balance":"10"}},"code":"successful"}`)
+		client, _ := NewGatewayHttpClient(&sdkTestsCommon.HTTPClientWrapperStub{
+			GetHTTPCalled: func(ctx context.Context, endpoint string) ([]byte, int, error) {
+				assert.Equal(t, "address/erd1", endpoint)
+				return providedResponse, http.StatusOK, nil
+			},
+		})
+
+		buff, code, err := client.GetHTTP(context.Background(), "address/erd1")
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, providedResponse, buff)
+	})
+	t.Run("block by hash failed should return the gateway response", func(t *testing.T) {
+		t.Parallel()
+
+		providedResponse := []byte(`{"data":null,"error":"block not found","code":"internal_issue"}`)
+		client, _ := NewGatewayHttpClient(&sdkTestsCommon.HTTPClientWrapperStub{
+			GetHTTPCalled: func(ctx context.Context, endpoint string) ([]byte, int, error) {
+				return providedResponse, http.StatusInternalServerError, nil
+			},
+		})
+
+		buff, code, err := client.GetHTTP(context.Background(), "blocks/hash")
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusInternalServerError, code)
+		assert.Equal(t, providedResponse, buff)
+
+		client, _ = NewGatewayHttpClient(&sdkTestsCommon.HTTPClientWrapperStub{
+			GetHTTPCalled: func(ctx context.Context, endpoint string) ([]byte, int, error) {
+				return nil, http.StatusBadRequest, expectedErr
+			},
+		})
+		_, _, err = client.GetHTTP(context.Background(), "blocks/hash")
+		assert.Equal(t, expectedErr, err)
+	})
+	t.Run("invalid hyperblock response should error", func(t *testing.T) {
+		t.Parallel()
+
+		client, _ := NewGatewayHttpClient(&sdkTestsCommon.HTTPClientWrapperStub{
+			GetHTTPCalled: func(ctx context.Context, endpoint string) ([]byte, int, error) {
+				return []byte("not json"), http.StatusOK, nil
+			},
+		})
+
+		buff, _, err := client.GetHTTP(context.Background(), "blocks/hash")
+		assert.NotNil(t, err)
+		assert.Nil(t, buff)
+	})
+	t.Run("block by hash should be translated to hyperblock by hash", func(t *testing.T) {
+		t.Parallel()
+
+		client, _ := NewGatewayHttpClient(&sdkTestsCommon.HTTPClientWrapperStub{
+			GetHTTPCalled: func(ctx context.Context, endpoint string) ([]byte, int, error) {
+				assert.Equal(t, "hyperblock/by-hash/hash", endpoint)
+				return []byte(`{"data":{"hyperblock":{"hash":"hash","nonce":5,"timestamp":1700000000}},"error":"","code":"successful"}`), http.StatusOK, nil
+			},
+		})
+
+		buff, code, err := client.GetHTTP(context.Background(), "blocks/hash")
+		require.Nil(t, err)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, `{"timestamp":1700000000}`, string(buff))
+	})
+}
+
+func TestGatewayHttpClient_PostHTTP(t *testing.T) {
+	t.Parallel()
+
+	wasCalled := false
+	client, _ := NewGatewayHttpClient(&sdkTestsCommon.HTTPClientWrapperStub{
+		PostHTTPCalled: func(ctx context.Context, endpoint string, data []byte) ([]byte, int, error) {
+			wasCalled = true
+			assert.Equal(t, "endpoint", endpoint)
+			assert.Equal(t, []byte("data"), data)
+			return []byte("response"), http.StatusOK, nil
+		},
+	})
+
+	buff, code, err := client.PostHTTP(context.Background(), "endpoint", []byte("data"))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []byte("response"), buff)
+	assert.True(t, wasCalled)
+}
@@ -0,0 +1,74 @@
+package encryption
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	crypto "github.com/multiversx/mx-chain-crypto-go"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+const aeadKeyInfo = "mx-multi-factor-auth-go-service user info aead key"
+
+type aeadEncryptor struct {
+	aead cipher.AEAD
+}
+
+// NewAEADEncryptor creates a new aeadEncryptor instance, using a XChaCha20-Poly1305 key derived from the managed private key
+func NewAEADEncryptor(managedPrivateKey crypto.PrivateKey) (*aeadEncryptor, error) {
+	if check.IfNil(managedPrivateKey) {
+		return nil, ErrNilPrivateKey
+	}
+
+	managedKeyBytes, err := managedPrivateKey.ToByteArray()
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, chacha20poly1305.KeySize)
+	_, err = io.ReadFull(hkdf.New(sha256.New, managedKeyBytes, nil, []byte(aeadKeyInfo)), key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &aeadEncryptor{
+		aead: aead,
+	}, nil
+}
+
+// EncryptData encrypts the provided data, authenticating the associated data as well.
+// The result is the random nonce followed by the sealed data, which is never empty, not even for empty data
+func (enc *aeadEncryptor) EncryptData(data []byte, associatedData []byte) ([]byte, error) {
+	nonce := make([]byte, enc.aead.NonceSize(), enc.aead.NonceSize()+len(data)+enc.aead.Overhead())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return enc.aead.Seal(nonce, nonce, data, associatedData), nil
+}
+
+// DecryptData decrypts the provided data, failing if it was encrypted with different associated data.
+// An empty cipher text is rejected, so a sealed value cannot be removed unnoticed
+func (enc *aeadEncryptor) DecryptData(data []byte, associatedData []byte) ([]byte, error) {
+	if len(data) < enc.aead.NonceSize()+enc.aead.Overhead() {
+		return nil, ErrInvalidCipherText
+	}
+
+	nonce, sealedData := data[:enc.aead.NonceSize()], data[enc.aead.NonceSize():]
+	return enc.aead.Open(nil, nonce, sealedData, associatedData)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (enc *aeadEncryptor) IsInterfaceNil() bool {
+	return enc == nil
+}
@@ -0,0 +1,111 @@
+package encryption
+
+import (
+	"testing"
+
+	"github.com/multiversx/mx-chain-crypto-go/signing"
+	"github.com/multiversx/mx-chain-crypto-go/signing/ed25519"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAEADEncryptor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil sk should return error", func(t *testing.T) {
+		t.Parallel()
+
+		enc, err := NewAEADEncryptor(nil)
+		require.Nil(t, enc)
+		require.Equal(t, ErrNilPrivateKey, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		testSk, _ := signing.NewKeyGenerator(ed25519.NewEd25519()).GeneratePair()
+		enc, err := NewAEADEncryptor(testSk)
+		require.Nil(t, err)
+		require.False(t, enc.IsInterfaceNil())
+	})
+}
+
+func TestAEADEncryptor_EncryptDecrypt(t *testing.T) {
+	t.Parallel()
+
+	testKeygen := signing.NewKeyGenerator(ed25519.NewEd25519())
+	testSk, _ := testKeygen.GeneratePair()
+	dataToEncrypt := []byte("data to encrypt")
+	associatedData := []byte("associated data")
+
+	t.Run("empty data should be sealed", func(t *testing.T) {
+		t.Parallel()
+
+		enc, _ := NewAEADEncryptor(testSk)
+		encData, err := enc.EncryptData(nil, associatedData)
+		require.Nil(t, err)
+		require.NotEmpty(t, encData)
+
+		decData, err := enc.DecryptData(encData, associatedData)
+		require.Nil(t, err)
+		require.Empty(t, decData)
+
+		decData, err = enc.DecryptData(encData, []byte("other associated data"))
+		require.Nil(t, decData)
+		require.NotNil(t, err)
+	})
+	t.Run("empty cipher text should return error", func(t *testing.T) {
+		t.Parallel()
+
+		enc, _ := NewAEADEncryptor(testSk)
+		decData, err := enc.DecryptData(nil, associatedData)
+		require.Nil(t, decData)
+		require.Equal(t, ErrInvalidCipherText, err)
+	})
+	t.Run("too short cipher text should return error", func(t *testing.T) {
+		t.Parallel()
+
+		enc, _ := NewAEADEncryptor(testSk)
+		decData, err := enc.DecryptData([]byte("short"), associatedData)
+		require.Nil(t, decData)
+		require.Equal(t, ErrInvalidCipherText, err)
+	})
+	t.Run("different associated data should return error", func(t *testing.T) {
+		t.Parallel()
+
+		enc, _ := NewAEADEncryptor(testSk)
+		encData, err := enc.EncryptData(dataToEncrypt, associatedData)
+		require.Nil(t, err)
+
+		decData, err := enc.DecryptData(encData, []byte("other associated data"))
+		require.Nil(t, decData)
+		require.NotNil(t, err)
+	})
+	t.Run("different key should return error", func(t *testing.T) {
+		t.Parallel()
+
+		enc, _ := NewAEADEncryptor(testSk)
+		encData, err := enc.EncryptData(dataToEncrypt, associatedData)
+		require.Nil(t, err)
+
+		otherSk, _ := testKeygen.GeneratePair()
+		otherEnc, _ := NewAEADEncryptor(otherSk)
+		decData, err := otherEnc.DecryptData(encData, associatedData)
+		require.Nil(t, decData)
+		require.NotNil(t, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		enc, _ := NewAEADEncryptor(testSk)
+		encData, err := enc.EncryptData(dataToEncrypt, associatedData)
+		require.Nil(t, err)
+		require.NotEqual(t, dataToEncrypt, encData)
+
+		otherEncData, err := enc.EncryptData(dataToEncrypt, associatedData)
+		require.Nil(t, err)
+		require.NotEqual(t, encData, otherEncData)
+
+		decData, err := enc.DecryptData(encData, associatedData)
+		require.Nil(t, err)
+		require.Equal(t, dataToEncrypt, decData)
+	})
+}
@@ -10,3 +10,12 @@ var ErrNilKeyGenerator = errors.New("nil key generator")
 
 // ErrNilPrivateKey is returned when a nil private key is provided
 var ErrNilPrivateKey = errors.New("nil private key")
+
+// ErrNoManagedKeys is returned when no managed key is provided
+var ErrNoManagedKeys = errors.New("no managed keys")
+
+// ErrUnknownKeyID is returned when there is no managed key for the provided key id
+var ErrUnknownKeyID = errors.New("unknown encryption key id")
+
+// ErrInvalidCipherText is returned when the provided cipher text is too short to be decrypted
+var ErrInvalidCipherText = errors.New("invalid cipher text")
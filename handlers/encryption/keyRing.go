@@ -0,0 +1,110 @@
+package encryption
+
+import (
+	"fmt"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	crypto "github.com/multiversx/mx-chain-crypto-go"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+)
+
+// ArgsKeyRing is the DTO used to create a new instance of keyRing
+type ArgsKeyRing struct {
+	Marshaller   core.Marshaller
+	KeyGen       crypto.KeyGenerator
+	ManagedKeys  map[uint32]crypto.PrivateKey
+	CurrentKeyID uint32
+}
+
+type managedKeyEncryptors struct {
+	legacyEncryptor *encryptor
+	aeadEncryptor   *aeadEncryptor
+}
+
+type keyRing struct {
+	encryptors   map[uint32]managedKeyEncryptors
+	currentKeyID uint32
+}
+
+// NewKeyRing creates a new key ring instance, which encrypts with the current managed key
+// and decrypts with any of the provided managed keys, in both the legacy and the AEAD formats
+func NewKeyRing(args ArgsKeyRing) (*keyRing, error) {
+	if len(args.ManagedKeys) == 0 {
+		return nil, ErrNoManagedKeys
+	}
+	_, exists := args.ManagedKeys[args.CurrentKeyID]
+	if !exists {
+		return nil, fmt.Errorf("%w for current key id %d", ErrUnknownKeyID, args.CurrentKeyID)
+	}
+
+	encryptors := make(map[uint32]managedKeyEncryptors, len(args.ManagedKeys))
+	for keyID, managedKey := range args.ManagedKeys {
+		if check.IfNil(managedKey) {
+			return nil, fmt.Errorf("%w for key id %d", ErrNilPrivateKey, keyID)
+		}
+
+		legacyEncryptor, err := NewEncryptor(args.Marshaller, args.KeyGen, managedKey)
+		if err != nil {
+			return nil, err
+		}
+
+		aeadEnc, err := NewAEADEncryptor(managedKey)
+		if err != nil {
+			return nil, err
+		}
+
+		encryptors[keyID] = managedKeyEncryptors{
+			legacyEncryptor: legacyEncryptor,
+			aeadEncryptor:   aeadEnc,
+		}
+	}
+
+	return &keyRing{
+		encryptors:   encryptors,
+		currentKeyID: args.CurrentKeyID,
+	}, nil
+}
+
+// CurrentKeyID returns the id of the key used for encryption
+func (kr *keyRing) CurrentKeyID() uint32 {
+	return kr.currentKeyID
+}
+
+// EncryptData encrypts the provided data with the current key, authenticating the associated data as well
+func (kr *keyRing) EncryptData(data []byte, associatedData []byte) ([]byte, error) {
+	return kr.encryptors[kr.currentKeyID].aeadEncryptor.EncryptData(data, associatedData)
+}
+
+// DecryptData decrypts the provided data with the key of the provided id, checking the associated data
+func (kr *keyRing) DecryptData(keyID uint32, data []byte, associatedData []byte) ([]byte, error) {
+	encryptors, err := kr.getEncryptors(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return encryptors.aeadEncryptor.DecryptData(data, associatedData)
+}
+
+// DecryptLegacyData decrypts the provided data, encrypted in the legacy format with the key of the provided id
+func (kr *keyRing) DecryptLegacyData(keyID uint32, data []byte) ([]byte, error) {
+	encryptors, err := kr.getEncryptors(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return encryptors.legacyEncryptor.DecryptData(data)
+}
+
+func (kr *keyRing) getEncryptors(keyID uint32) (managedKeyEncryptors, error) {
+	encryptors, exists := kr.encryptors[keyID]
+	if !exists {
+		return managedKeyEncryptors{}, fmt.Errorf("%w %d", ErrUnknownKeyID, keyID)
+	}
+
+	return encryptors, nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (kr *keyRing) IsInterfaceNil() bool {
+	return kr == nil
+}
@@ -0,0 +1,151 @@
+package encryption
+
+import (
+	"errors"
+	"testing"
+
+	factoryMarshaller "github.com/multiversx/mx-chain-core-go/marshal/factory"
+	crypto "github.com/multiversx/mx-chain-crypto-go"
+	"github.com/multiversx/mx-chain-crypto-go/signing"
+	"github.com/multiversx/mx-chain-crypto-go/signing/ed25519"
+	"github.com/stretchr/testify/require"
+)
+
+func createMockArgsKeyRing() ArgsKeyRing {
+	testKeygen := signing.NewKeyGenerator(ed25519.NewEd25519())
+	firstSk, _ := testKeygen.GeneratePair()
+	secondSk, _ := testKeygen.GeneratePair()
+	testMarshaller, _ := factoryMarshaller.NewMarshalizer(factoryMarshaller.JsonMarshalizer)
+
+	return ArgsKeyRing{
+		Marshaller: testMarshaller,
+		KeyGen:     testKeygen,
+		ManagedKeys: map[uint32]crypto.PrivateKey{
+			0: firstSk,
+			1: secondSk,
+		},
+		CurrentKeyID: 1,
+	}
+}
+
+func TestNewKeyRing(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no managed keys should return error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsKeyRing()
+		args.ManagedKeys = nil
+		kr, err := NewKeyRing(args)
+		require.Nil(t, kr)
+		require.Equal(t, ErrNoManagedKeys, err)
+	})
+	t.Run("missing current key should return error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsKeyRing()
+		args.CurrentKeyID = 2
+		kr, err := NewKeyRing(args)
+		require.Nil(t, kr)
+		require.True(t, errors.Is(err, ErrUnknownKeyID))
+	})
+	t.Run("nil managed key should return error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsKeyRing()
+		args.ManagedKeys[0] = nil
+		kr, err := NewKeyRing(args)
+		require.Nil(t, kr)
+		require.True(t, errors.Is(err, ErrNilPrivateKey))
+	})
+	t.Run("nil marshaller should return error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsKeyRing()
+		args.Marshaller = nil
+		kr, err := NewKeyRing(args)
+		require.Nil(t, kr)
+		require.Equal(t, ErrNilMarshaller, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		kr, err := NewKeyRing(createMockArgsKeyRing())
+		require.NotNil(t, kr)
+		require.Nil(t, err)
+		require.Equal(t, uint32(1), kr.CurrentKeyID())
+	})
+}
+
+func TestKeyRing_EncryptDecrypt(t *testing.T) {
+	t.Parallel()
+
+	dataToEncrypt := []byte("data to encrypt")
+	associatedData := []byte("associated data")
+
+	t.Run("unknown key id should return error", func(t *testing.T) {
+		t.Parallel()
+
+		kr, _ := NewKeyRing(createMockArgsKeyRing())
+		decData, err := kr.DecryptData(5, []byte("data"), associatedData)
+		require.Nil(t, decData)
+		require.True(t, errors.Is(err, ErrUnknownKeyID))
+
+		decData, err = kr.DecryptLegacyData(5, []byte("data"))
+		require.Nil(t, decData)
+		require.True(t, errors.Is(err, ErrUnknownKeyID))
+	})
+	t.Run("data encrypted with an older key should decrypt only with that key", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsKeyRing()
+		oldKeyRingArgs := args
+		oldKeyRingArgs.CurrentKeyID = 0
+		oldKeyRing, _ := NewKeyRing(oldKeyRingArgs)
+		encData, err := oldKeyRing.EncryptData(dataToEncrypt, associatedData)
+		require.Nil(t, err)
+
+		kr, _ := NewKeyRing(args)
+		decData, err := kr.DecryptData(0, encData, associatedData)
+		require.Nil(t, err)
+		require.Equal(t, dataToEncrypt, decData)
+
+		decData, err = kr.DecryptData(1, encData, associatedData)
+		require.NotNil(t, err)
+		require.Nil(t, decData)
+	})
+	t.Run("should encrypt with the current key", func(t *testing.T) {
+		t.Parallel()
+
+		kr, _ := NewKeyRing(createMockArgsKeyRing())
+		encData, err := kr.EncryptData(dataToEncrypt, associatedData)
+		require.Nil(t, err)
+
+		decData, err := kr.DecryptData(kr.CurrentKeyID(), encData, associatedData)
+		require.Nil(t, err)
+		require.Equal(t, dataToEncrypt, decData)
+	})
+	t.Run("should decrypt the legacy format", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsKeyRing()
+		legacyEncryptor, _ := NewEncryptor(args.Marshaller, args.KeyGen, args.ManagedKeys[0])
+		encData, err := legacyEncryptor.EncryptData(dataToEncrypt)
+		require.Nil(t, err)
+
+		kr, _ := NewKeyRing(args)
+		decData, err := kr.DecryptLegacyData(0, encData)
+		require.Nil(t, err)
+		require.Equal(t, dataToEncrypt, decData)
+	})
+}
+
+func TestKeyRing_IsInterfaceNil(t *testing.T) {
+	t.Parallel()
+
+	var kr *keyRing
+	require.True(t, kr.IsInterfaceNil())
+
+	kr = &keyRing{}
+	require.False(t, kr.IsInterfaceNil())
+}
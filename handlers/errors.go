@@ -13,3 +13,30 @@ var ErrNilOTPProvider = errors.New("nil otp provider")
 
 // ErrNilRateLimiter signals that a nil rate limiter was provided
 var ErrNilRateLimiter = errors.New("nil rate limiter")
+
+// ErrNilNotifier signals that a nil notifier was provided
+var ErrNilNotifier = errors.New("nil notifier")
+
+// ErrNilPubKeyConverter signals that a nil pub key converter was provided
+var ErrNilPubKeyConverter = errors.New("nil pub key converter")
+
+// ErrTxPolicyRejected signals that the transactions were rejected by the guardian policy
+var ErrTxPolicyRejected = errors.New("transaction rejected by policy")
+
+// ErrTxPolicyConfirmationRequired signals that the transactions require a second code as confirmation
+var ErrTxPolicyConfirmationRequired = errors.New("transaction requires confirmation")
+
+// ErrInvalidWebAuthnResponse signals that the WebAuthn response of the authenticator could not be verified
+var ErrInvalidWebAuthnResponse = errors.New("invalid webauthn response")
+
+// ErrInvalidOTPParams signals that the otp parameters are not valid or not supported
+var ErrInvalidOTPParams = errors.New("invalid otp params")
+
+// ErrWrongCode signals that the provided code does not match
+var ErrWrongCode = errors.New("wrong code")
+
+// ErrEmptyCode signals that an empty code was provided
+var ErrEmptyCode = errors.New("empty code")
+
+// ErrNilEncryptor signals that a nil encryptor was provided
+var ErrNilEncryptor = errors.New("nil encryptor")
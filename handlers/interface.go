@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"crypto"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
 
 	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
@@ -10,7 +10,7 @@ import (
 // TOTPHandler defines the methods available for a time based one time password handler
 type TOTPHandler interface {
 	CreateTOTP(account string) (OTP, error)
-	TOTPFromBytes(encryptedMessage []byte) (OTP, error)
+	TOTPFromBytes(encryptedMessage []byte, params core.OTPParams) (OTP, error)
 	IsInterfaceNil() bool
 }
 
@@ -23,9 +23,13 @@ type SecureOtpHandler interface {
 	SetSecurityModeNoExpire(key string) error
 	UnsetSecurityModeNoExpire(key string) error
 	IsVerificationAllowedAndIncreaseTrials(account string, ip string) (*requests.OTPCodeVerifyData, error)
+	GetVerificationTrials(account string, ip string) (*requests.OTPCodeVerifyData, error)
 	Reset(account string, ip string)
+	ResetFreeze(account string, ip string) error
+	ResetSecurityMode(account string) error
 	DecrementSecurityModeFailedTrials(account string) error
 	ExtendSecurityMode(account string) error
+	RemoveAccount(account string) error
 	IsInterfaceNil() bool
 }
 
@@ -36,6 +40,15 @@ type OTP interface {
 	QR() ([]byte, error)
 	ToBytes() ([]byte, error)
 	Url() (string, error)
+	Params() core.OTPParams
+}
+
+// WebAuthnHandler defines the methods available for a WebAuthn credentials handler
+type WebAuthnHandler interface {
+	CreateChallenge(binding []byte) ([]byte, error)
+	VerifyRegistration(credential core.WebAuthnInfo, binding []byte, registration requests.WebAuthnRegistration) (*core.WebAuthnInfo, error)
+	VerifyAssertion(credential core.WebAuthnInfo, binding []byte, assertion requests.WebAuthnAssertion) (*core.WebAuthnInfo, error)
+	IsInterfaceNil() bool
 }
 
 // ShardedStorageFactory defines the methods available for a sharded storage factory
@@ -46,7 +59,14 @@ type ShardedStorageFactory interface {
 
 // OTPProvider defines the methods available for an otp provider
 type OTPProvider interface {
-	GenerateTOTP(account string, hash crypto.Hash) (OTP, error)
-	TOTPFromBytes(encryptedMessage []byte) (OTP, error)
+	GenerateOTP(account string, params core.OTPParams) (OTP, error)
+	OTPFromBytes(otpBytes []byte, params core.OTPParams) (OTP, error)
+	IsInterfaceNil() bool
+}
+
+// TxPolicyHandler defines the methods available for a component that applies the guardian policy on transactions
+type TxPolicyHandler interface {
+	CheckTransactions(userAddress string, txs []transaction.FrontendTransaction) (bool, error)
+	CheckAndRecordTransactions(userAddress string, txs []transaction.FrontendTransaction, isConfirmed bool) error
 	IsInterfaceNil() bool
 }
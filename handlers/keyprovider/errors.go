@@ -0,0 +1,27 @@
+package keyprovider
+
+import "errors"
+
+// ErrEmptyFilePath is returned when an empty file path is provided
+var ErrEmptyFilePath = errors.New("empty file path")
+
+// ErrEmptyMnemonic is returned when the unwrapped mnemonic is empty
+var ErrEmptyMnemonic = errors.New("empty mnemonic")
+
+// ErrEmptyPassphrase is returned when an empty keystore passphrase is provided
+var ErrEmptyPassphrase = errors.New("empty keystore passphrase")
+
+// ErrWrongPassphrase is returned when the keystore cannot be decrypted with the provided passphrase
+var ErrWrongPassphrase = errors.New("wrong keystore passphrase")
+
+// ErrUnsupportedKeystore is returned when the keystore is not a supported mnemonic keystore
+var ErrUnsupportedKeystore = errors.New("unsupported keystore")
+
+// ErrEmptyURL is returned when an empty KMS url is provided
+var ErrEmptyURL = errors.New("empty KMS url")
+
+// ErrEmptyKeyID is returned when an empty KMS key id is provided
+var ErrEmptyKeyID = errors.New("empty KMS key id")
+
+// ErrUnexpectedStatusCode is returned when the KMS does not unwrap the mnemonic
+var ErrUnexpectedStatusCode = errors.New("unexpected status code")
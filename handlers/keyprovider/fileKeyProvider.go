@@ -0,0 +1,40 @@
+package keyprovider
+
+import (
+	"os"
+
+	"github.com/multiversx/mx-sdk-go/data"
+)
+
+type fileKeyProvider struct {
+	mnemonicFile string
+}
+
+// NewFileKeyProvider returns a new instance of fileKeyProvider, which reads the mnemonic in plain text
+func NewFileKeyProvider(mnemonicFile string) (*fileKeyProvider, error) {
+	if len(mnemonicFile) == 0 {
+		return nil, ErrEmptyFilePath
+	}
+
+	return &fileKeyProvider{
+		mnemonicFile: mnemonicFile,
+	}, nil
+}
+
+// GetMnemonic returns the content of the mnemonic file, unchanged
+func (provider *fileKeyProvider) GetMnemonic() (data.Mnemonic, error) {
+	mnemonic, err := os.ReadFile(provider.mnemonicFile)
+	if err != nil {
+		return "", err
+	}
+	if len(mnemonic) == 0 {
+		return "", ErrEmptyMnemonic
+	}
+
+	return data.Mnemonic(mnemonic), nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (provider *fileKeyProvider) IsInterfaceNil() bool {
+	return provider == nil
+}
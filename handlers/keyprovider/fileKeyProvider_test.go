@@ -0,0 +1,66 @@
+package keyprovider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMnemonic = "moral volcano peasant pass circle pen over picture flat shop clap goat never lyrics gather prepare woman film husband gravity behind test tiger improve"
+
+func TestNewFileKeyProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty file path should error", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := NewFileKeyProvider("")
+		assert.Nil(t, provider)
+		assert.Equal(t, ErrEmptyFilePath, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := NewFileKeyProvider("mnemonic")
+		assert.Nil(t, err)
+		assert.False(t, provider.IsInterfaceNil())
+	})
+}
+
+func TestFileKeyProvider_GetMnemonic(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing file should error", func(t *testing.T) {
+		t.Parallel()
+
+		provider, _ := NewFileKeyProvider(filepath.Join(t.TempDir(), "missing"))
+		mnemonic, err := provider.GetMnemonic()
+		assert.Empty(t, mnemonic)
+		assert.NotNil(t, err)
+	})
+	t.Run("empty file should error", func(t *testing.T) {
+		t.Parallel()
+
+		mnemonicFile := filepath.Join(t.TempDir(), "mnemonic")
+		require.Nil(t, os.WriteFile(mnemonicFile, nil, 0600))
+
+		provider, _ := NewFileKeyProvider(mnemonicFile)
+		mnemonic, err := provider.GetMnemonic()
+		assert.Empty(t, mnemonic)
+		assert.Equal(t, ErrEmptyMnemonic, err)
+	})
+	t.Run("should return the file content unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		mnemonicFile := filepath.Join(t.TempDir(), "mnemonic")
+		require.Nil(t, os.WriteFile(mnemonicFile, []byte(testMnemonic+"\n"), 0600))
+
+		provider, _ := NewFileKeyProvider(mnemonicFile)
+		mnemonic, err := provider.GetMnemonic()
+		assert.Nil(t, err)
+		assert.Equal(t, testMnemonic+"\n", string(mnemonic))
+	})
+}
@@ -0,0 +1,170 @@
+package keyprovider
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/multiversx/mx-sdk-go/data"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keystoreKindMnemonic = "mnemonic"
+	keystoreCipher       = "aes-128-ctr"
+	keystoreKDF          = "scrypt"
+	keystoreDerivedLen   = 32
+)
+
+// encryptedKeystore is the MultiversX wallet keystore (version 4), as created with the "mnemonic" kind
+type encryptedKeystore struct {
+	Kind   string `json:"kind"`
+	Crypto struct {
+		Cipher       string `json:"cipher"`
+		CipherText   string `json:"ciphertext"`
+		CipherParams struct {
+			IV string `json:"iv"`
+		} `json:"cipherparams"`
+		KDF       string `json:"kdf"`
+		KDFParams struct {
+			DkLen int    `json:"dklen"`
+			Salt  string `json:"salt"`
+			N     int    `json:"n"`
+			R     int    `json:"r"`
+			P     int    `json:"p"`
+		} `json:"kdfparams"`
+		MAC string `json:"mac"`
+	} `json:"crypto"`
+	Version int `json:"version"`
+}
+
+// ArgsKeystoreKeyProvider is the DTO used to create a new instance of keystoreKeyProvider
+type ArgsKeystoreKeyProvider struct {
+	KeystoreFile string
+	Passphrase   []byte
+}
+
+type keystoreKeyProvider struct {
+	keystoreFile string
+	passphrase   []byte
+}
+
+// NewKeystoreKeyProvider returns a new instance of keystoreKeyProvider, which decrypts the mnemonic
+// from a passphrase encrypted keystore file
+func NewKeystoreKeyProvider(args ArgsKeystoreKeyProvider) (*keystoreKeyProvider, error) {
+	if len(args.KeystoreFile) == 0 {
+		return nil, ErrEmptyFilePath
+	}
+	if len(args.Passphrase) == 0 {
+		return nil, ErrEmptyPassphrase
+	}
+
+	return &keystoreKeyProvider{
+		keystoreFile: args.KeystoreFile,
+		passphrase:   args.Passphrase,
+	}, nil
+}
+
+// GetMnemonic decrypts and returns the mnemonic held by the keystore file
+func (provider *keystoreKeyProvider) GetMnemonic() (data.Mnemonic, error) {
+	buff, err := os.ReadFile(provider.keystoreFile)
+	if err != nil {
+		return "", err
+	}
+
+	keystore := &encryptedKeystore{}
+	err = json.Unmarshal(buff, keystore)
+	if err != nil {
+		return "", err
+	}
+
+	err = checkKeystore(keystore)
+	if err != nil {
+		return "", err
+	}
+
+	mnemonic, err := provider.decrypt(keystore)
+	if err != nil {
+		return "", err
+	}
+	if len(mnemonic) == 0 {
+		return "", ErrEmptyMnemonic
+	}
+
+	return data.Mnemonic(mnemonic), nil
+}
+
+func checkKeystore(keystore *encryptedKeystore) error {
+	if keystore.Kind != keystoreKindMnemonic {
+		return fmt.Errorf("%w, kind %s", ErrUnsupportedKeystore, keystore.Kind)
+	}
+	if keystore.Crypto.Cipher != keystoreCipher {
+		return fmt.Errorf("%w, cipher %s", ErrUnsupportedKeystore, keystore.Crypto.Cipher)
+	}
+	if keystore.Crypto.KDF != keystoreKDF {
+		return fmt.Errorf("%w, kdf %s", ErrUnsupportedKeystore, keystore.Crypto.KDF)
+	}
+	if keystore.Crypto.KDFParams.DkLen != keystoreDerivedLen {
+		return fmt.Errorf("%w, dklen %d", ErrUnsupportedKeystore, keystore.Crypto.KDFParams.DkLen)
+	}
+
+	return nil
+}
+
+func (provider *keystoreKeyProvider) decrypt(keystore *encryptedKeystore) ([]byte, error) {
+	mac, err := hex.DecodeString(keystore.Crypto.MAC)
+	if err != nil {
+		return nil, err
+	}
+
+	iv, err := hex.DecodeString(keystore.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("%w, iv length %d", ErrUnsupportedKeystore, len(iv))
+	}
+
+	cipherText, err := hex.DecodeString(keystore.Crypto.CipherText)
+	if err != nil {
+		return nil, err
+	}
+
+	salt, err := hex.DecodeString(keystore.Crypto.KDFParams.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	kdfParams := keystore.Crypto.KDFParams
+	derivedKey, err := scrypt.Key(provider.passphrase, salt, kdfParams.N, kdfParams.R, kdfParams.P, kdfParams.DkLen)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := hmac.New(sha256.New, derivedKey[16:32])
+	_, _ = hash.Write(cipherText)
+	if !hmac.Equal(hash.Sum(nil), mac) {
+		return nil, ErrWrongPassphrase
+	}
+
+	aesBlock, err := aes.NewCipher(derivedKey[:16])
+	if err != nil {
+		return nil, err
+	}
+
+	stream := cipher.NewCTR(aesBlock, iv)
+	plainText := make([]byte, len(cipherText))
+	stream.XORKeyStream(plainText, cipherText)
+
+	return plainText, nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (provider *keystoreKeyProvider) IsInterfaceNil() bool {
+	return provider == nil
+}
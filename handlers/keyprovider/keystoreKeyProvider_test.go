@@ -0,0 +1,164 @@
+package keyprovider
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/scrypt"
+)
+
+const testPassphrase = "passphrase"
+
+func createKeystore(t *testing.T, mnemonic string, passphrase string) *encryptedKeystore {
+	salt := make([]byte, 32)
+	_, err := rand.Read(salt)
+	require.Nil(t, err)
+	iv := make([]byte, aes.BlockSize)
+	_, err = rand.Read(iv)
+	require.Nil(t, err)
+
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, 4096, 8, 1, keystoreDerivedLen)
+	require.Nil(t, err)
+
+	aesBlock, err := aes.NewCipher(derivedKey[:16])
+	require.Nil(t, err)
+	cipherText := make([]byte, len(mnemonic))
+	cipher.NewCTR(aesBlock, iv).XORKeyStream(cipherText, []byte(mnemonic))
+
+	hash := hmac.New(sha256.New, derivedKey[16:32])
+	_, _ = hash.Write(cipherText)
+
+	keystore := &encryptedKeystore{
+		Kind:    keystoreKindMnemonic,
+		Version: 4,
+	}
+	keystore.Crypto.Cipher = keystoreCipher
+	keystore.Crypto.CipherText = hex.EncodeToString(cipherText)
+	keystore.Crypto.CipherParams.IV = hex.EncodeToString(iv)
+	keystore.Crypto.KDF = keystoreKDF
+	keystore.Crypto.KDFParams.DkLen = keystoreDerivedLen
+	keystore.Crypto.KDFParams.Salt = hex.EncodeToString(salt)
+	keystore.Crypto.KDFParams.N = 4096
+	keystore.Crypto.KDFParams.R = 8
+	keystore.Crypto.KDFParams.P = 1
+	keystore.Crypto.MAC = hex.EncodeToString(hash.Sum(nil))
+
+	return keystore
+}
+
+func writeKeystore(t *testing.T, keystore *encryptedKeystore) string {
+	buff, err := json.Marshal(keystore)
+	require.Nil(t, err)
+
+	keystoreFile := filepath.Join(t.TempDir(), "keystore.json")
+	require.Nil(t, os.WriteFile(keystoreFile, buff, 0600))
+
+	return keystoreFile
+}
+
+func TestNewKeystoreKeyProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty file path should error", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := NewKeystoreKeyProvider(ArgsKeystoreKeyProvider{Passphrase: []byte(testPassphrase)})
+		assert.Nil(t, provider)
+		assert.Equal(t, ErrEmptyFilePath, err)
+	})
+	t.Run("empty passphrase should error", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := NewKeystoreKeyProvider(ArgsKeystoreKeyProvider{KeystoreFile: "keystore.json"})
+		assert.Nil(t, provider)
+		assert.Equal(t, ErrEmptyPassphrase, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := NewKeystoreKeyProvider(ArgsKeystoreKeyProvider{
+			KeystoreFile: "keystore.json",
+			Passphrase:   []byte(testPassphrase),
+		})
+		assert.Nil(t, err)
+		assert.False(t, provider.IsInterfaceNil())
+	})
+}
+
+func TestKeystoreKeyProvider_GetMnemonic(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid json should error", func(t *testing.T) {
+		t.Parallel()
+
+		keystoreFile := filepath.Join(t.TempDir(), "keystore.json")
+		require.Nil(t, os.WriteFile(keystoreFile, []byte(testMnemonic), 0600))
+
+		provider, _ := NewKeystoreKeyProvider(ArgsKeystoreKeyProvider{
+			KeystoreFile: keystoreFile,
+			Passphrase:   []byte(testPassphrase),
+		})
+		mnemonic, err := provider.GetMnemonic()
+		assert.Empty(t, mnemonic)
+		assert.NotNil(t, err)
+	})
+	t.Run("secret key keystore should error", func(t *testing.T) {
+		t.Parallel()
+
+		keystore := createKeystore(t, testMnemonic, testPassphrase)
+		keystore.Kind = "secretKey"
+		provider, _ := NewKeystoreKeyProvider(ArgsKeystoreKeyProvider{
+			KeystoreFile: writeKeystore(t, keystore),
+			Passphrase:   []byte(testPassphrase),
+		})
+		mnemonic, err := provider.GetMnemonic()
+		assert.Empty(t, mnemonic)
+		assert.True(t, errors.Is(err, ErrUnsupportedKeystore))
+	})
+	t.Run("invalid iv should error", func(t *testing.T) {
+		t.Parallel()
+
+		keystore := createKeystore(t, testMnemonic, testPassphrase)
+		keystore.Crypto.CipherParams.IV = "abcd"
+		provider, _ := NewKeystoreKeyProvider(ArgsKeystoreKeyProvider{
+			KeystoreFile: writeKeystore(t, keystore),
+			Passphrase:   []byte(testPassphrase),
+		})
+		mnemonic, err := provider.GetMnemonic()
+		assert.Empty(t, mnemonic)
+		assert.True(t, errors.Is(err, ErrUnsupportedKeystore))
+	})
+	t.Run("wrong passphrase should error", func(t *testing.T) {
+		t.Parallel()
+
+		provider, _ := NewKeystoreKeyProvider(ArgsKeystoreKeyProvider{
+			KeystoreFile: writeKeystore(t, createKeystore(t, testMnemonic, testPassphrase)),
+			Passphrase:   []byte("wrong passphrase"),
+		})
+		mnemonic, err := provider.GetMnemonic()
+		assert.Empty(t, mnemonic)
+		assert.Equal(t, ErrWrongPassphrase, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		provider, _ := NewKeystoreKeyProvider(ArgsKeystoreKeyProvider{
+			KeystoreFile: writeKeystore(t, createKeystore(t, testMnemonic, testPassphrase)),
+			Passphrase:   []byte(testPassphrase),
+		})
+		mnemonic, err := provider.GetMnemonic()
+		assert.Nil(t, err)
+		assert.Equal(t, testMnemonic, string(mnemonic))
+	})
+}
@@ -0,0 +1,125 @@
+package keyprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/multiversx/mx-sdk-go/data"
+)
+
+// KMSDecryptRequest is the body posted to the KMS in order to unwrap the mnemonic
+type KMSDecryptRequest struct {
+	KeyID      string `json:"keyId"`
+	CipherText string `json:"ciphertext"`
+}
+
+// KMSDecryptResponse is the body returned by the KMS, holding the base64 encoded plain text
+type KMSDecryptResponse struct {
+	PlainText string `json:"plaintext"`
+}
+
+// ArgsKMSKeyProvider is the DTO used to create a new instance of kmsKeyProvider
+type ArgsKMSKeyProvider struct {
+	URL                 string
+	KeyID               string
+	AuthToken           string
+	WrappedMnemonicFile string
+	RequestTimeout      time.Duration
+}
+
+type kmsKeyProvider struct {
+	url                 string
+	keyID               string
+	authToken           string
+	wrappedMnemonicFile string
+	httpClient          *http.Client
+}
+
+// NewKMSKeyProvider returns a new instance of kmsKeyProvider, which asks an HTTP KMS to unwrap the
+// mnemonic. The wrapped mnemonic file holds the base64 encoded cipher text, as returned by the KMS
+func NewKMSKeyProvider(args ArgsKMSKeyProvider) (*kmsKeyProvider, error) {
+	if len(args.URL) == 0 {
+		return nil, ErrEmptyURL
+	}
+	if len(args.KeyID) == 0 {
+		return nil, ErrEmptyKeyID
+	}
+	if len(args.WrappedMnemonicFile) == 0 {
+		return nil, ErrEmptyFilePath
+	}
+
+	return &kmsKeyProvider{
+		url:                 args.URL,
+		keyID:               args.KeyID,
+		authToken:           args.AuthToken,
+		wrappedMnemonicFile: args.WrappedMnemonicFile,
+		httpClient:          &http.Client{Timeout: args.RequestTimeout},
+	}, nil
+}
+
+// GetMnemonic reads the wrapped mnemonic and returns it as unwrapped by the KMS
+func (provider *kmsKeyProvider) GetMnemonic() (data.Mnemonic, error) {
+	wrappedMnemonic, err := os.ReadFile(provider.wrappedMnemonicFile)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := json.Marshal(&KMSDecryptRequest{
+		KeyID:      provider.keyID,
+		CipherText: strings.TrimSpace(string(wrappedMnemonic)),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, provider.url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(provider.authToken) > 0 {
+		req.Header.Set("Authorization", "Bearer "+provider.authToken)
+	}
+
+	resp, err := provider.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return "", fmt.Errorf("%w %d from KMS", ErrUnexpectedStatusCode, resp.StatusCode)
+	}
+
+	response := &KMSDecryptResponse{}
+	err = json.NewDecoder(resp.Body).Decode(response)
+	if err != nil {
+		return "", err
+	}
+
+	mnemonic, err := base64.StdEncoding.DecodeString(response.PlainText)
+	if err != nil {
+		return "", err
+	}
+	if len(mnemonic) == 0 {
+		return "", ErrEmptyMnemonic
+	}
+
+	return data.Mnemonic(mnemonic), nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (provider *kmsKeyProvider) IsInterfaceNil() bool {
+	return provider == nil
+}
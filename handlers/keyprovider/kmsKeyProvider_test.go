@@ -0,0 +1,161 @@
+package keyprovider
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testKeyID           = "key-id"
+	testAuthToken       = "token"
+	testWrappedMnemonic = "d3JhcHBlZCBtbmVtb25pYw=="
+)
+
+// createKMSStub emulates a KMS which unwraps only the test wrapped mnemonic, with the test key id
+func createKMSStub(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		if r.Header.Get("Authorization") != "Bearer "+testAuthToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		request := &KMSDecryptRequest{}
+		err := json.NewDecoder(r.Body).Decode(request)
+		require.Nil(t, err)
+		if request.KeyID != testKeyID || request.CipherText != testWrappedMnemonic {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		response := &KMSDecryptResponse{
+			PlainText: base64.StdEncoding.EncodeToString([]byte(testMnemonic)),
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+}
+
+func createMockArgsKMSKeyProvider(t *testing.T, url string) ArgsKMSKeyProvider {
+	wrappedMnemonicFile := filepath.Join(t.TempDir(), "mnemonic.wrapped")
+	require.Nil(t, os.WriteFile(wrappedMnemonicFile, []byte(testWrappedMnemonic+"\n"), 0600))
+
+	return ArgsKMSKeyProvider{
+		URL:                 url,
+		KeyID:               testKeyID,
+		AuthToken:           testAuthToken,
+		WrappedMnemonicFile: wrappedMnemonicFile,
+		RequestTimeout:      time.Second,
+	}
+}
+
+func TestNewKMSKeyProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty url should error", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := NewKMSKeyProvider(createMockArgsKMSKeyProvider(t, ""))
+		assert.Nil(t, provider)
+		assert.Equal(t, ErrEmptyURL, err)
+	})
+	t.Run("empty key id should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsKMSKeyProvider(t, "http://localhost")
+		args.KeyID = ""
+		provider, err := NewKMSKeyProvider(args)
+		assert.Nil(t, provider)
+		assert.Equal(t, ErrEmptyKeyID, err)
+	})
+	t.Run("empty wrapped mnemonic file should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsKMSKeyProvider(t, "http://localhost")
+		args.WrappedMnemonicFile = ""
+		provider, err := NewKMSKeyProvider(args)
+		assert.Nil(t, provider)
+		assert.Equal(t, ErrEmptyFilePath, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := NewKMSKeyProvider(createMockArgsKMSKeyProvider(t, "http://localhost"))
+		assert.Nil(t, err)
+		assert.False(t, provider.IsInterfaceNil())
+	})
+}
+
+func TestKMSKeyProvider_GetMnemonic(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing wrapped mnemonic file should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsKMSKeyProvider(t, "http://localhost")
+		args.WrappedMnemonicFile = filepath.Join(t.TempDir(), "missing")
+		provider, _ := NewKMSKeyProvider(args)
+		mnemonic, err := provider.GetMnemonic()
+		assert.Empty(t, mnemonic)
+		assert.NotNil(t, err)
+	})
+	t.Run("unauthorized request should error", func(t *testing.T) {
+		t.Parallel()
+
+		server := createKMSStub(t)
+		defer server.Close()
+
+		args := createMockArgsKMSKeyProvider(t, server.URL)
+		args.AuthToken = "wrong token"
+		provider, _ := NewKMSKeyProvider(args)
+		mnemonic, err := provider.GetMnemonic()
+		assert.Empty(t, mnemonic)
+		assert.True(t, errors.Is(err, ErrUnexpectedStatusCode))
+	})
+	t.Run("unknown key id should error", func(t *testing.T) {
+		t.Parallel()
+
+		server := createKMSStub(t)
+		defer server.Close()
+
+		args := createMockArgsKMSKeyProvider(t, server.URL)
+		args.KeyID = "other key id"
+		provider, _ := NewKMSKeyProvider(args)
+		mnemonic, err := provider.GetMnemonic()
+		assert.Empty(t, mnemonic)
+		assert.True(t, errors.Is(err, ErrUnexpectedStatusCode))
+	})
+	t.Run("empty plain text should error", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(&KMSDecryptResponse{})
+		}))
+		defer server.Close()
+
+		provider, _ := NewKMSKeyProvider(createMockArgsKMSKeyProvider(t, server.URL))
+		mnemonic, err := provider.GetMnemonic()
+		assert.Empty(t, mnemonic)
+		assert.Equal(t, ErrEmptyMnemonic, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		server := createKMSStub(t)
+		defer server.Close()
+
+		provider, _ := NewKMSKeyProvider(createMockArgsKMSKeyProvider(t, server.URL))
+		mnemonic, err := provider.GetMnemonic()
+		assert.Nil(t, err)
+		assert.Equal(t, testMnemonic, string(mnemonic))
+	})
+}
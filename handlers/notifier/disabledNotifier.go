@@ -0,0 +1,25 @@
+package notifier
+
+import "github.com/multiversx/mx-multi-factor-auth-go-service/core"
+
+type disabledNotifier struct {
+}
+
+// NewDisabledNotifier returns a new instance of disabledNotifier, used when no notifier is configured
+func NewDisabledNotifier() *disabledNotifier {
+	return &disabledNotifier{}
+}
+
+// Notify does nothing
+func (dn *disabledNotifier) Notify(_ core.SecurityEvent) {
+}
+
+// Close does nothing
+func (dn *disabledNotifier) Close() error {
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (dn *disabledNotifier) IsInterfaceNil() bool {
+	return dn == nil
+}
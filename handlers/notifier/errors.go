@@ -0,0 +1,18 @@
+package notifier
+
+import "errors"
+
+// ErrEmptyURL is returned when an empty webhook url is provided
+var ErrEmptyURL = errors.New("empty webhook url")
+
+// ErrEmptySecret is returned when an empty webhook secret is provided
+var ErrEmptySecret = errors.New("empty webhook secret")
+
+// ErrNilPublisher is returned when a nil publisher is provided
+var ErrNilPublisher = errors.New("nil publisher")
+
+// ErrEmptyChannel is returned when an empty pub/sub channel is provided
+var ErrEmptyChannel = errors.New("empty pub/sub channel")
+
+// ErrUnexpectedStatusCode is returned when the webhook endpoint does not accept the event
+var ErrUnexpectedStatusCode = errors.New("unexpected status code")
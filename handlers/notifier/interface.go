@@ -0,0 +1,10 @@
+package notifier
+
+import "context"
+
+// Publisher defines the component able to publish messages on a pub/sub channel
+type Publisher interface {
+	Publish(ctx context.Context, channel string, message []byte) error
+	Close() error
+	IsInterfaceNil() bool
+}
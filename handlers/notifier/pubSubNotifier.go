@@ -0,0 +1,72 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+)
+
+// ArgsPubSubNotifier is the DTO used to create a new instance of pubSubNotifier
+type ArgsPubSubNotifier struct {
+	Publisher        Publisher
+	Channel          string
+	OperationTimeout time.Duration
+}
+
+type pubSubNotifier struct {
+	publisher        Publisher
+	channel          string
+	operationTimeout time.Duration
+}
+
+// NewPubSubNotifier returns a new instance of pubSubNotifier, which publishes the security events as JSON messages
+// on the provided channel
+func NewPubSubNotifier(args ArgsPubSubNotifier) (*pubSubNotifier, error) {
+	if check.IfNil(args.Publisher) {
+		return nil, ErrNilPublisher
+	}
+	if len(args.Channel) == 0 {
+		return nil, ErrEmptyChannel
+	}
+
+	return &pubSubNotifier{
+		publisher:        args.Publisher,
+		channel:          args.Channel,
+		operationTimeout: args.OperationTimeout,
+	}, nil
+}
+
+// Notify publishes the event on the channel. Errors are only logged, as the event must not affect the request
+func (psn *pubSubNotifier) Notify(event core.SecurityEvent) {
+	message, err := json.Marshal(event)
+	if err != nil {
+		log.Error("could not marshal security event", "type", event.Type, "error", err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), psn.operationTimeout)
+	defer cancel()
+
+	err = psn.publisher.Publish(ctx, psn.channel, message)
+	if err != nil {
+		log.Error("could not publish security event",
+			"channel", psn.channel,
+			"type", event.Type,
+			"user address", event.UserAddress,
+			"error", err.Error())
+	}
+}
+
+// Close closes the underlying publisher
+func (psn *pubSubNotifier) Close() error {
+	return psn.publisher.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (psn *pubSubNotifier) IsInterfaceNil() bool {
+	return psn == nil
+}
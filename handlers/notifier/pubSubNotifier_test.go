@@ -0,0 +1,120 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+var expectedErr = errors.New("expected error")
+
+func createSecurityEvent() core.SecurityEvent {
+	return core.SecurityEvent{
+		Type:        core.UserFrozenEvent,
+		Timestamp:   1000,
+		UserAddress: "user",
+		UserIp:      "127.0.0.1",
+	}
+}
+
+func createMockArgsPubSubNotifier() ArgsPubSubNotifier {
+	return ArgsPubSubNotifier{
+		Publisher:        &testscommon.PublisherStub{},
+		Channel:          "channel",
+		OperationTimeout: time.Second,
+	}
+}
+
+func TestNewPubSubNotifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil publisher should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPubSubNotifier()
+		args.Publisher = nil
+		psn, err := NewPubSubNotifier(args)
+		assert.Nil(t, psn)
+		assert.Equal(t, ErrNilPublisher, err)
+	})
+	t.Run("empty channel should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPubSubNotifier()
+		args.Channel = ""
+		psn, err := NewPubSubNotifier(args)
+		assert.Nil(t, psn)
+		assert.Equal(t, ErrEmptyChannel, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		psn, err := NewPubSubNotifier(createMockArgsPubSubNotifier())
+		assert.Nil(t, err)
+		assert.False(t, psn.IsInterfaceNil())
+	})
+}
+
+func TestPubSubNotifier_Notify(t *testing.T) {
+	t.Parallel()
+
+	t.Run("publish error should not panic", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPubSubNotifier()
+		args.Publisher = &testscommon.PublisherStub{
+			PublishCalled: func(ctx context.Context, channel string, message []byte) error {
+				return expectedErr
+			},
+		}
+		psn, _ := NewPubSubNotifier(args)
+
+		psn.Notify(createSecurityEvent())
+	})
+	t.Run("should publish the event on the channel", func(t *testing.T) {
+		t.Parallel()
+
+		wasPublished := false
+		wasClosed := false
+		args := createMockArgsPubSubNotifier()
+		args.Publisher = &testscommon.PublisherStub{
+			PublishCalled: func(ctx context.Context, channel string, message []byte) error {
+				assert.Equal(t, "channel", channel)
+
+				event := core.SecurityEvent{}
+				require.Nil(t, json.Unmarshal(message, &event))
+				assert.Equal(t, createSecurityEvent(), event)
+				wasPublished = true
+				return nil
+			},
+			CloseCalled: func() error {
+				wasClosed = true
+				return nil
+			},
+		}
+		psn, _ := NewPubSubNotifier(args)
+
+		psn.Notify(createSecurityEvent())
+		assert.True(t, wasPublished)
+
+		assert.Nil(t, psn.Close())
+		assert.True(t, wasClosed)
+	})
+}
+
+func TestDisabledNotifier(t *testing.T) {
+	t.Parallel()
+
+	dn := NewDisabledNotifier()
+	assert.False(t, dn.IsInterfaceNil())
+	dn.Notify(createSecurityEvent())
+	assert.Nil(t, dn.Close())
+}
@@ -0,0 +1,188 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	logger "github.com/multiversx/mx-chain-logger-go"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+)
+
+const (
+	// SignatureHeader holds the hex encoded HMAC-SHA256 of the request body, prefixed with "sha256="
+	SignatureHeader = "X-Signature-256"
+
+	signaturePrefix = "sha256="
+	minQueueSize    = 1
+)
+
+var log = logger.GetOrCreate("notifier")
+
+// ArgsWebhookNotifier is the DTO used to create a new instance of webhookNotifier
+type ArgsWebhookNotifier struct {
+	URL            string
+	Secret         []byte
+	MaxRetries     uint32
+	RetryDelay     time.Duration
+	RequestTimeout time.Duration
+	QueueSize      uint32
+}
+
+type webhookNotifier struct {
+	url        string
+	secret     []byte
+	maxRetries uint32
+	retryDelay time.Duration
+	httpClient *http.Client
+	events     chan core.SecurityEvent
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// NewWebhookNotifier returns a new instance of webhookNotifier, which posts the security events to the provided url.
+// The events are sent in the background, so that a slow endpoint does not delay the requests of the users
+func NewWebhookNotifier(args ArgsWebhookNotifier) (*webhookNotifier, error) {
+	err := checkWebhookArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wn := &webhookNotifier{
+		url:        args.URL,
+		secret:     args.Secret,
+		maxRetries: args.MaxRetries,
+		retryDelay: args.RetryDelay,
+		httpClient: &http.Client{
+			Timeout: args.RequestTimeout,
+		},
+		events: make(chan core.SecurityEvent, args.QueueSize),
+		cancel: cancel,
+	}
+
+	wn.wg.Add(1)
+	go wn.processEvents(ctx)
+
+	return wn, nil
+}
+
+func checkWebhookArgs(args ArgsWebhookNotifier) error {
+	if len(args.URL) == 0 {
+		return ErrEmptyURL
+	}
+	if len(args.Secret) == 0 {
+		return ErrEmptySecret
+	}
+	if args.QueueSize < minQueueSize {
+		return fmt.Errorf("%w for QueueSize, received %d, min expected %d", core.ErrInvalidValue, args.QueueSize, minQueueSize)
+	}
+
+	return nil
+}
+
+// Notify queues the event to be sent. If the queue is full, the event is dropped
+func (wn *webhookNotifier) Notify(event core.SecurityEvent) {
+	select {
+	case wn.events <- event:
+	default:
+		log.Warn("security events queue is full, event dropped", "type", event.Type, "user address", event.UserAddress)
+	}
+}
+
+func (wn *webhookNotifier) processEvents(ctx context.Context) {
+	defer wn.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-wn.events:
+			wn.sendWithRetries(ctx, event)
+		}
+	}
+}
+
+func (wn *webhookNotifier) sendWithRetries(ctx context.Context, event core.SecurityEvent) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Error("could not marshal security event", "type", event.Type, "error", err.Error())
+		return
+	}
+
+	for attempt := uint32(0); attempt <= wn.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wn.retryDelay * time.Duration(attempt)):
+			}
+		}
+
+		err = wn.send(ctx, body)
+		if err == nil {
+			return
+		}
+
+		log.Debug("could not send security event", "type", event.Type, "attempt", attempt, "error", err.Error())
+	}
+
+	log.Error("could not send security event, giving up",
+		"type", event.Type,
+		"user address", event.UserAddress,
+		"error", err.Error())
+}
+
+func (wn *webhookNotifier) send(ctx context.Context, body []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, wn.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(SignatureHeader, signaturePrefix+ComputeSignature(wn.secret, body))
+
+	response, err := wn.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, response.Body)
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w %d", ErrUnexpectedStatusCode, response.StatusCode)
+	}
+
+	return nil
+}
+
+// ComputeSignature returns the hex encoded HMAC-SHA256 of the body, which the receivers use to authenticate the events
+func ComputeSignature(secret []byte, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Close stops sending the events, dropping the ones still queued
+func (wn *webhookNotifier) Close() error {
+	wn.cancel()
+	wn.wg.Wait()
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (wn *webhookNotifier) IsInterfaceNil() bool {
+	return wn == nil
+}
@@ -0,0 +1,165 @@
+package notifier
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+)
+
+const testTimeout = 5 * time.Second
+
+func createMockArgsWebhookNotifier(url string) ArgsWebhookNotifier {
+	return ArgsWebhookNotifier{
+		URL:            url,
+		Secret:         []byte("secret"),
+		MaxRetries:     2,
+		RetryDelay:     time.Millisecond,
+		RequestTimeout: time.Second,
+		QueueSize:      10,
+	}
+}
+
+func TestNewWebhookNotifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty url should error", func(t *testing.T) {
+		t.Parallel()
+
+		wn, err := NewWebhookNotifier(createMockArgsWebhookNotifier(""))
+		assert.Nil(t, wn)
+		assert.Equal(t, ErrEmptyURL, err)
+	})
+	t.Run("empty secret should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsWebhookNotifier("http://localhost")
+		args.Secret = nil
+		wn, err := NewWebhookNotifier(args)
+		assert.Nil(t, wn)
+		assert.Equal(t, ErrEmptySecret, err)
+	})
+	t.Run("invalid queue size should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsWebhookNotifier("http://localhost")
+		args.QueueSize = 0
+		wn, err := NewWebhookNotifier(args)
+		assert.Nil(t, wn)
+		assert.True(t, errors.Is(err, core.ErrInvalidValue))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		wn, err := NewWebhookNotifier(createMockArgsWebhookNotifier("http://localhost"))
+		assert.Nil(t, err)
+		assert.False(t, wn.IsInterfaceNil())
+		assert.Nil(t, wn.Close())
+	})
+}
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should send the signed event", func(t *testing.T) {
+		t.Parallel()
+
+		received := make(chan core.SecurityEvent, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.Nil(t, err)
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, signaturePrefix+ComputeSignature([]byte("secret"), body), r.Header.Get(SignatureHeader))
+
+			event := core.SecurityEvent{}
+			require.Nil(t, json.Unmarshal(body, &event))
+			received <- event
+		}))
+		defer server.Close()
+
+		wn, _ := NewWebhookNotifier(createMockArgsWebhookNotifier(server.URL))
+		defer func() {
+			_ = wn.Close()
+		}()
+
+		wn.Notify(createSecurityEvent())
+
+		select {
+		case event := <-received:
+			assert.Equal(t, createSecurityEvent(), event)
+		case <-time.After(testTimeout):
+			assert.Fail(t, "timeout waiting for the event")
+		}
+	})
+	t.Run("should retry until accepted", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := uint32(0)
+		accepted := make(chan struct{}, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddUint32(&numCalls, 1) < 3 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			accepted <- struct{}{}
+		}))
+		defer server.Close()
+
+		wn, _ := NewWebhookNotifier(createMockArgsWebhookNotifier(server.URL))
+		defer func() {
+			_ = wn.Close()
+		}()
+
+		wn.Notify(createSecurityEvent())
+
+		select {
+		case <-accepted:
+			assert.Equal(t, uint32(3), atomic.LoadUint32(&numCalls))
+		case <-time.After(testTimeout):
+			assert.Fail(t, "timeout waiting for the event")
+		}
+	})
+	t.Run("should give up after max retries", func(t *testing.T) {
+		t.Parallel()
+
+		numCalls := uint32(0)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddUint32(&numCalls, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		args := createMockArgsWebhookNotifier(server.URL)
+		args.MaxRetries = 1
+		wn, _ := NewWebhookNotifier(args)
+
+		wn.Notify(createSecurityEvent())
+		wn.Notify(createSecurityEvent())
+
+		assert.Eventually(t, func() bool {
+			return atomic.LoadUint32(&numCalls) == 4
+		}, testTimeout, time.Millisecond*10)
+		assert.Nil(t, wn.Close())
+		assert.Equal(t, uint32(4), atomic.LoadUint32(&numCalls))
+	})
+	t.Run("full queue should drop the event", func(t *testing.T) {
+		t.Parallel()
+
+		wn := &webhookNotifier{
+			events: make(chan core.SecurityEvent, 1),
+		}
+
+		wn.Notify(createSecurityEvent())
+		wn.Notify(createSecurityEvent())
+		assert.Equal(t, 1, len(wn.events))
+	})
+}
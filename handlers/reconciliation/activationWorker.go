@@ -0,0 +1,97 @@
+package reconciliation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	logger "github.com/multiversx/mx-chain-logger-go"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+)
+
+const minPeriod = time.Second
+
+var log = logger.GetOrCreate("reconciliation")
+
+// ArgsActivationWorker is the DTO used to create a new instance of activationWorker
+type ArgsActivationWorker struct {
+	Reconciler GuardianActivationReconciler
+	Period     time.Duration
+}
+
+type activationWorker struct {
+	reconciler GuardianActivationReconciler
+	period     time.Duration
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// NewActivationWorker returns a new instance of activationWorker, which periodically checks on chain the
+// activation of the guardians, in background, so that their state is updated even if the users do not sign
+func NewActivationWorker(args ArgsActivationWorker) (*activationWorker, error) {
+	err := checkArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	aw := &activationWorker{
+		reconciler: args.Reconciler,
+		period:     args.Period,
+		cancel:     cancel,
+	}
+
+	aw.wg.Add(1)
+	go aw.processLoop(ctx)
+
+	return aw, nil
+}
+
+func checkArgs(args ArgsActivationWorker) error {
+	if check.IfNil(args.Reconciler) {
+		return ErrNilReconciler
+	}
+	if args.Period < minPeriod {
+		return fmt.Errorf("%w for Period, received %v, min expected %v", core.ErrInvalidValue, args.Period, minPeriod)
+	}
+
+	return nil
+}
+
+func (aw *activationWorker) processLoop(ctx context.Context) {
+	defer aw.wg.Done()
+
+	timer := time.NewTimer(aw.period)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			err := aw.reconciler.ReconcileGuardianActivations(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Error("could not reconcile the guardian activations", "error", err.Error())
+			}
+
+			// the period is counted from the end of the previous check, so that slow checks do not overlap
+			timer.Reset(aw.period)
+		}
+	}
+}
+
+// Close stops the checks, waiting for the one in progress to be cancelled
+func (aw *activationWorker) Close() error {
+	aw.cancel()
+	aw.wg.Wait()
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (aw *activationWorker) IsInterfaceNil() bool {
+	return aw == nil
+}
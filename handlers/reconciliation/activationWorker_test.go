@@ -0,0 +1,113 @@
+package reconciliation
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+const testTimeout = 5 * time.Second
+
+func createMockArgsActivationWorker() ArgsActivationWorker {
+	return ArgsActivationWorker{
+		Reconciler: &testscommon.ServiceResolverStub{},
+		Period:     time.Second,
+	}
+}
+
+func TestNewActivationWorker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil reconciler should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsActivationWorker()
+		args.Reconciler = nil
+		aw, err := NewActivationWorker(args)
+		assert.Nil(t, aw)
+		assert.Equal(t, ErrNilReconciler, err)
+	})
+	t.Run("invalid period should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsActivationWorker()
+		args.Period = time.Millisecond
+		aw, err := NewActivationWorker(args)
+		assert.Nil(t, aw)
+		assert.True(t, errors.Is(err, core.ErrInvalidValue))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		aw, err := NewActivationWorker(createMockArgsActivationWorker())
+		assert.Nil(t, err)
+		assert.False(t, aw.IsInterfaceNil())
+		assert.Nil(t, aw.Close())
+	})
+}
+
+func TestActivationWorker_ShouldReconcilePeriodically(t *testing.T) {
+	t.Parallel()
+
+	numCalls := uint32(0)
+	called := make(chan struct{}, 10)
+	args := createMockArgsActivationWorker()
+	args.Reconciler = &testscommon.ServiceResolverStub{
+		ReconcileGuardianActivationsCalled: func(ctx context.Context) error {
+			atomic.AddUint32(&numCalls, 1)
+			called <- struct{}{}
+			return errors.New("should not stop the worker")
+		},
+	}
+	aw, _ := NewActivationWorker(args)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-called:
+		case <-time.After(testTimeout):
+			assert.Fail(t, "timeout waiting for the reconciliation")
+		}
+	}
+
+	assert.Nil(t, aw.Close())
+	numCallsAfterClose := atomic.LoadUint32(&numCalls)
+	time.Sleep(args.Period + 100*time.Millisecond)
+	assert.Equal(t, numCallsAfterClose, atomic.LoadUint32(&numCalls))
+}
+
+func TestActivationWorker_CloseShouldCancelTheCheckInProgress(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	args := createMockArgsActivationWorker()
+	args.Reconciler = &testscommon.ServiceResolverStub{
+		ReconcileGuardianActivationsCalled: func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	aw, _ := NewActivationWorker(args)
+
+	select {
+	case <-started:
+	case <-time.After(testTimeout):
+		assert.Fail(t, "timeout waiting for the reconciliation")
+	}
+
+	assert.Nil(t, aw.Close())
+}
+
+func TestDisabledActivationWorker(t *testing.T) {
+	t.Parallel()
+
+	daw := NewDisabledActivationWorker()
+	assert.False(t, daw.IsInterfaceNil())
+	assert.Nil(t, daw.Close())
+}
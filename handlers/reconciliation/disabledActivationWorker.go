@@ -0,0 +1,20 @@
+package reconciliation
+
+type disabledActivationWorker struct {
+}
+
+// NewDisabledActivationWorker returns a new instance of disabledActivationWorker, used when the background
+// checks are disabled and the guardians are reconciled only on demand
+func NewDisabledActivationWorker() *disabledActivationWorker {
+	return &disabledActivationWorker{}
+}
+
+// Close does nothing
+func (daw *disabledActivationWorker) Close() error {
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (daw *disabledActivationWorker) IsInterfaceNil() bool {
+	return daw == nil
+}
@@ -0,0 +1,6 @@
+package reconciliation
+
+import "errors"
+
+// ErrNilReconciler is returned when a nil guardian activation reconciler is provided
+var ErrNilReconciler = errors.New("nil guardian activation reconciler")
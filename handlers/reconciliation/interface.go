@@ -0,0 +1,9 @@
+package reconciliation
+
+import "context"
+
+// GuardianActivationReconciler defines the component able to update the activation state of the guardians
+type GuardianActivationReconciler interface {
+	ReconcileGuardianActivations(ctx context.Context) error
+	IsInterfaceNil() bool
+}
@@ -2,6 +2,7 @@ package secureOtp
 
 import (
 	"math"
+	"time"
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	logger "github.com/multiversx/mx-chain-logger-go"
@@ -17,10 +18,13 @@ var log = logger.GetOrCreate("SecureOtpHandler")
 // ArgsSecureOtpHandler is the DTO used to create a new instance of secureOtpHandler
 type ArgsSecureOtpHandler struct {
 	RateLimiter redis.RateLimiter
+	Notifier    core.Notifier
 }
 
 type secureOtpHandler struct {
-	rateLimiter redis.RateLimiter
+	rateLimiter    redis.RateLimiter
+	notifier       core.Notifier
+	getTimeHandler func() time.Time
 }
 
 // NewSecureOtpHandler returns a new instance of secureOtpHandler
@@ -31,7 +35,9 @@ func NewSecureOtpHandler(args ArgsSecureOtpHandler) (*secureOtpHandler, error) {
 	}
 
 	return &secureOtpHandler{
-		rateLimiter: args.RateLimiter,
+		rateLimiter:    args.RateLimiter,
+		notifier:       args.Notifier,
+		getTimeHandler: time.Now,
 	}, nil
 }
 
@@ -39,6 +45,9 @@ func checkArgs(args ArgsSecureOtpHandler) error {
 	if check.IfNil(args.RateLimiter) {
 		return handlers.ErrNilRateLimiter
 	}
+	if check.IfNil(args.Notifier) {
+		return handlers.ErrNilNotifier
+	}
 
 	return nil
 }
@@ -67,7 +76,8 @@ func (totp *secureOtpHandler) SecurityModeMaxFailures() uint64 {
 func (totp *secureOtpHandler) IsVerificationAllowedAndIncreaseTrials(account string, ip string) (*requests.OTPCodeVerifyData, error) {
 	key := computeVerificationKey(account, ip)
 
-	res, err := totp.rateLimiter.CheckAllowedAndIncreaseTrials(key, redis.NormalMode)
+	// the key is recorded in the account group, so the trials from all the ips can be removed together
+	res, err := totp.rateLimiter.CheckAllowedAndIncreaseTrialsInGroup(account, key, redis.NormalMode)
 	if err != nil {
 		return nil, err
 	}
@@ -90,6 +100,9 @@ func (totp *secureOtpHandler) IsVerificationAllowedAndIncreaseTrials(account str
 			"address", account,
 			"ip", ip,
 		)
+		if res.LimitReached {
+			totp.notify(core.UserFrozenEvent, account, ip)
+		}
 
 		err = core.ErrTooManyFailedAttempts
 	}
@@ -98,12 +111,45 @@ func (totp *secureOtpHandler) IsVerificationAllowedAndIncreaseTrials(account str
 		log.Debug("User is now in security mode",
 			"address", account,
 		)
+		if securityModeResult.LimitReached {
+			totp.notify(core.SecurityModeActivatedEvent, account, ip)
+		}
 		// no need to return error, as the second code needs to be verified first
 	}
 
 	return verifyCodeAllowData, err
 }
 
+// GetVerificationTrials returns the remaining trials of the account and ip and of the account security mode,
+// without consuming a trial
+func (totp *secureOtpHandler) GetVerificationTrials(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+	res, err := totp.rateLimiter.GetTrials(computeVerificationKey(account, ip), redis.NormalMode)
+	if err != nil {
+		return nil, err
+	}
+
+	securityModeResult, err := totp.rateLimiter.GetTrials(account, redis.SecurityMode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &requests.OTPCodeVerifyData{
+		RemainingTrials:             res.Remaining,
+		ResetAfter:                  int(math.Round(res.ResetAfter.Seconds())),
+		SecurityModeRemainingTrials: securityModeResult.Remaining,
+		SecurityModeResetAfter:      int(math.Round(securityModeResult.ResetAfter.Seconds())),
+	}, nil
+}
+
+func (totp *secureOtpHandler) notify(eventType core.SecurityEventType, account string, ip string) {
+	totp.notifier.Notify(core.SecurityEvent{
+		Type:        eventType,
+		Timestamp:   totp.getTimeHandler().Unix(),
+		UserAddress: account,
+		UserIp:      ip,
+	})
+}
+
 // SetSecurityModeNoExpire sets the security mode with no expire time
 func (totp *secureOtpHandler) SetSecurityModeNoExpire(key string) error {
 	return totp.rateLimiter.SetSecurityModeNoExpire(key)
@@ -116,12 +162,25 @@ func (totp *secureOtpHandler) UnsetSecurityModeNoExpire(key string) error {
 
 // Reset removes the account and ip from local cache
 func (totp *secureOtpHandler) Reset(account string, ip string) {
-	key := computeVerificationKey(account, ip)
+	err := totp.ResetFreeze(account, ip)
+	if err != nil {
+		log.Error("failed to reset limiter for key", "key", computeVerificationKey(account, ip), "error", err.Error())
+	}
+}
 
-	err := totp.rateLimiter.Reset(key)
+// ResetFreeze resets the failed trials of the account and ip, unfreezing them
+func (totp *secureOtpHandler) ResetFreeze(account string, ip string) error {
+	return totp.rateLimiter.Reset(computeVerificationKey(account, ip))
+}
+
+// ResetSecurityMode unsets the security mode of the account, even if it was set with no expire, and resets its failed trials
+func (totp *secureOtpHandler) ResetSecurityMode(account string) error {
+	err := totp.rateLimiter.UnsetSecurityModeNoExpire(account)
 	if err != nil {
-		log.Error("failed to reset limiter for key", "key", key, "error", err.Error())
+		return err
 	}
+
+	return totp.rateLimiter.Reset(account)
 }
 
 // DecrementSecurityModeFailedTrials decrements the security mode failed trials
@@ -134,6 +193,16 @@ func (totp *secureOtpHandler) ExtendSecurityMode(account string) error {
 	return totp.rateLimiter.ExtendSecurityMode(account)
 }
 
+// RemoveAccount removes all the limiter keys of the account: its security mode and its failed trials from every ip
+func (totp *secureOtpHandler) RemoveAccount(account string) error {
+	err := totp.rateLimiter.Remove(account)
+	if err != nil {
+		return err
+	}
+
+	return totp.rateLimiter.RemoveGroup(account)
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (totp *secureOtpHandler) IsInterfaceNil() bool {
 	return totp == nil
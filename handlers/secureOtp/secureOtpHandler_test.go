@@ -26,6 +26,7 @@ var expectedErr = errors.New("expected error")
 func createMockArgsSecureOtpHandler() secureOtp.ArgsSecureOtpHandler {
 	return secureOtp.ArgsSecureOtpHandler{
 		RateLimiter: &testscommon.RateLimiterStub{},
+		Notifier:    &testscommon.NotifierStub{},
 	}
 }
 
@@ -42,6 +43,16 @@ func TestNewSecureOtpHandler(t *testing.T) {
 		require.True(t, errors.Is(err, handlers.ErrNilRateLimiter))
 		require.Nil(t, totp)
 	})
+	t.Run("nil notifier should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsSecureOtpHandler()
+		args.Notifier = nil
+
+		totp, err := secureOtp.NewSecureOtpHandler(args)
+		require.Equal(t, handlers.ErrNilNotifier, err)
+		require.Nil(t, totp)
+	})
 
 	t.Run("should work", func(t *testing.T) {
 		t.Parallel()
@@ -63,7 +74,7 @@ func TestSecureOtpHandler_IsVerificationAllowedAndIncreaseTrials(t *testing.T) {
 		args := createMockArgsSecureOtpHandler()
 
 		args.RateLimiter = &testscommon.RateLimiterStub{
-			CheckAllowedAndIncreaseTrialsCalled: func(key string, _ redis.Mode) (*redis.RateLimiterResult, error) {
+			CheckAllowedAndIncreaseTrialsInGroupCalled: func(group string, key string, _ redis.Mode) (*redis.RateLimiterResult, error) {
 				return &redis.RateLimiterResult{}, expectedErr
 			},
 		}
@@ -76,19 +87,17 @@ func TestSecureOtpHandler_IsVerificationAllowedAndIncreaseTrials(t *testing.T) {
 		args := createMockArgsSecureOtpHandler()
 
 		args.RateLimiter = &testscommon.RateLimiterStub{
+			CheckAllowedAndIncreaseTrialsInGroupCalled: func(group string, key string, mode redis.Mode) (*redis.RateLimiterResult, error) {
+				require.Equal(t, redis.NormalMode, mode)
+				return &redis.RateLimiterResult{
+					Allowed:    true,
+					Remaining:  3,
+					ResetAfter: time.Second,
+				}, nil
+			},
 			CheckAllowedAndIncreaseTrialsCalled: func(key string, mode redis.Mode) (*redis.RateLimiterResult, error) {
-				switch mode {
-				case redis.NormalMode:
-					return &redis.RateLimiterResult{
-						Allowed:    true,
-						Remaining:  3,
-						ResetAfter: time.Second,
-					}, nil
-				case redis.SecurityMode:
-					return &redis.RateLimiterResult{}, expectedErr
-				default:
-					return nil, errors.New("unexpected mode")
-				}
+				require.Equal(t, redis.SecurityMode, mode)
+				return &redis.RateLimiterResult{}, expectedErr
 			},
 		}
 		totp, _ := secureOtp.NewSecureOtpHandler(args)
@@ -104,10 +113,15 @@ func TestSecureOtpHandler_IsVerificationAllowedAndIncreaseTrials(t *testing.T) {
 		args := createMockArgsSecureOtpHandler()
 
 		wasCalled := false
+		checkAllowedAndIncreaseTrials := func(key string, _ redis.Mode) (*redis.RateLimiterResult, error) {
+			wasCalled = true
+			return &redis.RateLimiterResult{Allowed: false}, nil
+		}
 		args.RateLimiter = &testscommon.RateLimiterStub{
-			CheckAllowedAndIncreaseTrialsCalled: func(key string, _ redis.Mode) (*redis.RateLimiterResult, error) {
-				wasCalled = true
-				return &redis.RateLimiterResult{Allowed: false}, nil
+			CheckAllowedAndIncreaseTrialsCalled: checkAllowedAndIncreaseTrials,
+			CheckAllowedAndIncreaseTrialsInGroupCalled: func(group string, key string, mode redis.Mode) (*redis.RateLimiterResult, error) {
+				require.Equal(t, account, group)
+				return checkAllowedAndIncreaseTrials(key, mode)
 			},
 		}
 		totp, _ := secureOtp.NewSecureOtpHandler(args)
@@ -124,10 +138,15 @@ func TestSecureOtpHandler_IsVerificationAllowedAndIncreaseTrials(t *testing.T) {
 		args := createMockArgsSecureOtpHandler()
 
 		wasCalled := false
+		checkAllowedAndIncreaseTrials := func(key string, _ redis.Mode) (*redis.RateLimiterResult, error) {
+			wasCalled = true
+			return &redis.RateLimiterResult{Allowed: true, Remaining: 1, ResetAfter: time.Duration(10) * time.Second}, nil
+		}
 		args.RateLimiter = &testscommon.RateLimiterStub{
-			CheckAllowedAndIncreaseTrialsCalled: func(key string, _ redis.Mode) (*redis.RateLimiterResult, error) {
-				wasCalled = true
-				return &redis.RateLimiterResult{Allowed: true, Remaining: 1, ResetAfter: time.Duration(10) * time.Second}, nil
+			CheckAllowedAndIncreaseTrialsCalled: checkAllowedAndIncreaseTrials,
+			CheckAllowedAndIncreaseTrialsInGroupCalled: func(group string, key string, mode redis.Mode) (*redis.RateLimiterResult, error) {
+				require.Equal(t, account, group)
+				return checkAllowedAndIncreaseTrials(key, mode)
 			},
 		}
 		totp, _ := secureOtp.NewSecureOtpHandler(args)
@@ -157,6 +176,38 @@ func TestSecureOtpHandler_IsVerificationAllowedAndIncreaseTrials(t *testing.T) {
 		_, err = totp.IsVerificationAllowedAndIncreaseTrials(account, ip)
 		require.Equal(t, core.ErrTooManyFailedAttempts, err)
 	})
+	t.Run("should notify only when the limits are reached", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsSecureOtpHandler()
+		args.RateLimiter = testscommon.NewRateLimiterMock(3, 10)
+		events := make([]core.SecurityEvent, 0)
+		args.Notifier = &testscommon.NotifierStub{
+			NotifyCalled: func(event core.SecurityEvent) {
+				events = append(events, event)
+			},
+		}
+		totp, _ := secureOtp.NewSecureOtpHandler(args)
+
+		for i := 0; i < 3; i++ {
+			_, err := totp.IsVerificationAllowedAndIncreaseTrials(account, ip)
+			require.Nil(t, err)
+		}
+		require.Equal(t, 0, len(events))
+
+		_, err := totp.IsVerificationAllowedAndIncreaseTrials(account, ip)
+		require.Equal(t, core.ErrTooManyFailedAttempts, err)
+		require.Equal(t, 2, len(events))
+		require.Equal(t, core.UserFrozenEvent, events[0].Type)
+		require.Equal(t, account, events[0].UserAddress)
+		require.Equal(t, ip, events[0].UserIp)
+		require.Equal(t, core.SecurityModeActivatedEvent, events[1].Type)
+		require.Equal(t, account, events[1].UserAddress)
+
+		_, err = totp.IsVerificationAllowedAndIncreaseTrials(account, ip)
+		require.Equal(t, core.ErrTooManyFailedAttempts, err)
+		require.Equal(t, 2, len(events))
+	})
 	t.Run("otp code verify data", func(t *testing.T) {
 		t.Parallel()
 
@@ -167,35 +218,40 @@ func TestSecureOtpHandler_IsVerificationAllowedAndIncreaseTrials(t *testing.T) {
 		resetAfterNormal := time.Duration(3) * time.Second
 		resetAfterSecurity := time.Duration(10) * time.Second
 
-		args.RateLimiter = &testscommon.RateLimiterStub{
-			CheckAllowedAndIncreaseTrialsCalled: func(key string, mode redis.Mode) (*redis.RateLimiterResult, error) {
-				switch mode {
-				case redis.NormalMode:
-					if _, ok := keyData[key]; !ok {
-						keyData[key] = &redis.RateLimiterResult{
-							Allowed:    true,
-							Remaining:  remainingNormal,
-							ResetAfter: resetAfterNormal,
-						}
+		checkAllowedAndIncreaseTrials := func(key string, mode redis.Mode) (*redis.RateLimiterResult, error) {
+			switch mode {
+			case redis.NormalMode:
+				if _, ok := keyData[key]; !ok {
+					keyData[key] = &redis.RateLimiterResult{
+						Allowed:    true,
+						Remaining:  remainingNormal,
+						ResetAfter: resetAfterNormal,
 					}
-				case redis.SecurityMode:
-					if _, ok := keyData[key]; !ok {
-						keyData[key] = &redis.RateLimiterResult{
-							Allowed:    true,
-							Remaining:  remainingSecurity,
-							ResetAfter: resetAfterSecurity,
-						}
+				}
+			case redis.SecurityMode:
+				if _, ok := keyData[key]; !ok {
+					keyData[key] = &redis.RateLimiterResult{
+						Allowed:    true,
+						Remaining:  remainingSecurity,
+						ResetAfter: resetAfterSecurity,
 					}
-				default:
-					return nil, errors.New("unexpected mode")
 				}
+			default:
+				return nil, errors.New("unexpected mode")
+			}
 
-				keyData[key].Allowed = keyData[key].Remaining > 0
-				if keyData[key].Remaining > 0 {
-					keyData[key].Remaining--
-				}
+			keyData[key].Allowed = keyData[key].Remaining > 0
+			if keyData[key].Remaining > 0 {
+				keyData[key].Remaining--
+			}
 
-				return keyData[key], nil
+			return keyData[key], nil
+		}
+		args.RateLimiter = &testscommon.RateLimiterStub{
+			CheckAllowedAndIncreaseTrialsCalled: checkAllowedAndIncreaseTrials,
+			CheckAllowedAndIncreaseTrialsInGroupCalled: func(group string, key string, mode redis.Mode) (*redis.RateLimiterResult, error) {
+				require.Equal(t, account, group)
+				return checkAllowedAndIncreaseTrials(key, mode)
 			},
 		}
 
@@ -238,6 +294,67 @@ func TestSecureOtpHandler_IsVerificationAllowedAndIncreaseTrials(t *testing.T) {
 	})
 }
 
+func TestSecureOtpHandler_GetVerificationTrials(t *testing.T) {
+	t.Parallel()
+
+	t.Run("on error should return err", func(t *testing.T) {
+		t.Parallel()
+
+		for _, failingMode := range []redis.Mode{redis.NormalMode, redis.SecurityMode} {
+			args := createMockArgsSecureOtpHandler()
+			mode := failingMode
+			args.RateLimiter = &testscommon.RateLimiterStub{
+				GetTrialsCalled: func(key string, currentMode redis.Mode) (*redis.RateLimiterResult, error) {
+					if currentMode == mode {
+						return nil, expectedErr
+					}
+					return &redis.RateLimiterResult{}, nil
+				},
+			}
+			totp, _ := secureOtp.NewSecureOtpHandler(args)
+
+			verifyCodeData, err := totp.GetVerificationTrials(account, ip)
+			require.Equal(t, expectedErr, err)
+			require.Nil(t, verifyCodeData)
+		}
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsSecureOtpHandler()
+		args.RateLimiter = &testscommon.RateLimiterStub{
+			CheckAllowedAndIncreaseTrialsCalled: func(key string, mode redis.Mode) (*redis.RateLimiterResult, error) {
+				require.Fail(t, "should have not been called")
+				return nil, nil
+			},
+			CheckAllowedAndIncreaseTrialsInGroupCalled: func(group string, key string, mode redis.Mode) (*redis.RateLimiterResult, error) {
+				require.Fail(t, "should have not been called")
+				return nil, nil
+			},
+			GetTrialsCalled: func(key string, mode redis.Mode) (*redis.RateLimiterResult, error) {
+				switch mode {
+				case redis.NormalMode:
+					require.Equal(t, account+":"+ip, key)
+					return &redis.RateLimiterResult{Allowed: true, Remaining: 2, ResetAfter: 10 * time.Second}, nil
+				default:
+					require.Equal(t, account, key)
+					return &redis.RateLimiterResult{ResetAfter: time.Duration(core.NoExpiryValue) * time.Second}, nil
+				}
+			},
+		}
+		totp, _ := secureOtp.NewSecureOtpHandler(args)
+
+		verifyCodeData, err := totp.GetVerificationTrials(account, ip)
+		require.Nil(t, err)
+		require.Equal(t, &requests.OTPCodeVerifyData{
+			RemainingTrials:             2,
+			ResetAfter:                  10,
+			SecurityModeRemainingTrials: 0,
+			SecurityModeResetAfter:      core.NoExpiryValue,
+		}, verifyCodeData)
+	})
+}
+
 func TestSecureOtpHandler_DecrementSecurityModeFailedTrials(t *testing.T) {
 	t.Parallel()
 
@@ -318,6 +435,134 @@ func TestSecureOtpHandler_Reset(t *testing.T) {
 	})
 }
 
+func TestSecureOtpHandler_ResetFreeze(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsSecureOtpHandler()
+	args.RateLimiter = &testscommon.RateLimiterStub{
+		ResetCalled: func(key string) error {
+			require.Equal(t, account+":"+ip, key)
+			return expectedErr
+		},
+	}
+	totp, _ := secureOtp.NewSecureOtpHandler(args)
+	require.NotNil(t, totp)
+
+	err := totp.ResetFreeze(account, ip)
+	require.Equal(t, expectedErr, err)
+}
+
+func TestSecureOtpHandler_ResetSecurityMode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unset security mode returns error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsSecureOtpHandler()
+		args.RateLimiter = &testscommon.RateLimiterStub{
+			UnsetSecurityModeNoExpireCalled: func(key string) error {
+				return expectedErr
+			},
+			ResetCalled: func(key string) error {
+				require.Fail(t, "should have not been called")
+				return nil
+			},
+		}
+		totp, _ := secureOtp.NewSecureOtpHandler(args)
+		require.NotNil(t, totp)
+
+		err := totp.ResetSecurityMode(account)
+		require.Equal(t, expectedErr, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		calls := make([]string, 0)
+		args := createMockArgsSecureOtpHandler()
+		args.RateLimiter = &testscommon.RateLimiterStub{
+			UnsetSecurityModeNoExpireCalled: func(key string) error {
+				require.Equal(t, account, key)
+				calls = append(calls, "unset")
+				return nil
+			},
+			ResetCalled: func(key string) error {
+				require.Equal(t, account, key)
+				calls = append(calls, "reset")
+				return nil
+			},
+		}
+		totp, _ := secureOtp.NewSecureOtpHandler(args)
+		require.NotNil(t, totp)
+
+		err := totp.ResetSecurityMode(account)
+		require.Nil(t, err)
+		require.Equal(t, []string{"unset", "reset"}, calls)
+	})
+}
+
+func TestSecureOtpHandler_RemoveAccount(t *testing.T) {
+	t.Parallel()
+
+	t.Run("remove returns error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsSecureOtpHandler()
+		args.RateLimiter = &testscommon.RateLimiterStub{
+			RemoveCalled: func(key string) error {
+				return expectedErr
+			},
+			RemoveGroupCalled: func(group string) error {
+				require.Fail(t, "should have not been called")
+				return nil
+			},
+		}
+		totp, _ := secureOtp.NewSecureOtpHandler(args)
+		require.NotNil(t, totp)
+
+		err := totp.RemoveAccount(account)
+		require.Equal(t, expectedErr, err)
+	})
+	t.Run("remove group returns error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsSecureOtpHandler()
+		args.RateLimiter = &testscommon.RateLimiterStub{
+			RemoveGroupCalled: func(group string) error {
+				return expectedErr
+			},
+		}
+		totp, _ := secureOtp.NewSecureOtpHandler(args)
+		require.NotNil(t, totp)
+
+		err := totp.RemoveAccount(account)
+		require.Equal(t, expectedErr, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		calls := make([]string, 0)
+		args := createMockArgsSecureOtpHandler()
+		args.RateLimiter = &testscommon.RateLimiterStub{
+			RemoveCalled: func(key string) error {
+				require.Equal(t, account, key)
+				calls = append(calls, "remove")
+				return nil
+			},
+			RemoveGroupCalled: func(group string) error {
+				require.Equal(t, account, group)
+				calls = append(calls, "remove group")
+				return nil
+			},
+		}
+		totp, _ := secureOtp.NewSecureOtpHandler(args)
+		require.NotNil(t, totp)
+
+		err := totp.RemoveAccount(account)
+		require.Nil(t, err)
+		require.Equal(t, []string{"remove", "remove group"}, calls)
+	})
+}
+
 func TestSecureOtpHandler_ExtendSecurityMode(t *testing.T) {
 	t.Parallel()
 
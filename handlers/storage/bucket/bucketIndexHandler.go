@@ -1,10 +1,14 @@
 package bucket
 
 import (
+	"bytes"
+	"context"
 	"encoding/binary"
+	"errors"
 	"sync"
 
 	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage"
 	"github.com/multiversx/mx-chain-core-go/core/check"
 )
 
@@ -13,9 +17,20 @@ const (
 	uint32Bytes  = 4
 )
 
+// pendingWrite is a write of a key which may still wait in the write batch of the bucket
+type pendingWrite struct {
+	nonce     uint64
+	isRemoved bool
+}
+
 type bucketIndexHandler struct {
-	bucket core.Storer
-	mut    sync.RWMutex
+	bucket    core.Storer
+	mut       sync.RWMutex
+	mutWrites sync.Mutex
+
+	mutPendingWrites sync.Mutex
+	pendingWrites    map[string]pendingWrite
+	writesNonce      uint64
 }
 
 // NewBucketIndexHandler returns a new instance of a bucket index handler
@@ -25,7 +40,8 @@ func NewBucketIndexHandler(bucket core.Storer) (*bucketIndexHandler, error) {
 	}
 
 	handler := &bucketIndexHandler{
-		bucket: bucket,
+		bucket:        bucket,
+		pendingWrites: make(map[string]pendingWrite),
 	}
 
 	err := bucket.Has([]byte(lastIndexKey))
@@ -56,11 +72,85 @@ func (handler *bucketIndexHandler) AllocateBucketIndex() (uint32, error) {
 	return index, handler.saveNewIndex(index)
 }
 
+// ReserveBucketIndex marks all the indexes up to the provided one as allocated
+func (handler *bucketIndexHandler) ReserveBucketIndex(index uint32) error {
+	handler.mut.Lock()
+	defer handler.mut.Unlock()
+
+	lastIndex, err := handler.getIndex()
+	if err != nil {
+		return err
+	}
+	if lastIndex >= index {
+		return nil
+	}
+
+	return handler.saveNewIndex(index)
+}
+
 // Put adds data to the bucket
 func (handler *bucketIndexHandler) Put(key, data []byte) error {
+	handler.mutWrites.Lock()
+	defer handler.mutWrites.Unlock()
+
+	return handler.put(key, data)
+}
+
+// Remove removes the key from the bucket, without releasing any index
+func (handler *bucketIndexHandler) Remove(key []byte) error {
+	handler.mutWrites.Lock()
+	defer handler.mutWrites.Unlock()
+
+	return handler.remove(key)
+}
+
+// PutIfNotExists adds data to the bucket only if the key does not exist yet. It returns true if the data was added
+func (handler *bucketIndexHandler) PutIfNotExists(key, data []byte) (bool, error) {
+	handler.mutWrites.Lock()
+	defer handler.mutWrites.Unlock()
+
+	_, err := handler.bucket.Get(key)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, storage.ErrKeyNotFound) {
+		return false, err
+	}
+
+	return true, handler.put(key, data)
+}
+
+// RemoveIfEqual removes the key from the bucket only if it still holds the provided data
+func (handler *bucketIndexHandler) RemoveIfEqual(key, data []byte) error {
+	handler.mutWrites.Lock()
+	defer handler.mutWrites.Unlock()
+
+	currentData, err := handler.bucket.Get(key)
+	if errors.Is(err, storage.ErrKeyNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(currentData, data) {
+		return nil
+	}
+
+	return handler.remove(key)
+}
+
+// must be called under mutWrites protection
+func (handler *bucketIndexHandler) put(key, data []byte) error {
+	handler.addPendingWrite(key, false)
 	return handler.bucket.Put(key, data)
 }
 
+// must be called under mutWrites protection
+func (handler *bucketIndexHandler) remove(key []byte) error {
+	handler.addPendingWrite(key, true)
+	return handler.bucket.Remove(key)
+}
+
 // Get returns the value for the key from the bucket
 func (handler *bucketIndexHandler) Get(key []byte) ([]byte, error) {
 	return handler.bucket.Get(key)
@@ -79,6 +169,86 @@ func (handler *bucketIndexHandler) GetLastIndex() (uint32, error) {
 	return handler.getIndex()
 }
 
+// RangeKeys calls the provided handler for each key-value pair of the bucket, until the handler returns false or
+// the context is done. The entry holding the last allocated index is skipped.
+// The persisted entries are ranged first. As the bucket persists the writes in batches, the keys written meanwhile
+// are read again, so the handler gets their latest values, the removed ones are skipped and the ones not persisted
+// yet are visited at the end
+func (handler *bucketIndexHandler) RangeKeys(ctx context.Context, rangeHandler func(key []byte, val []byte) bool) error {
+	if rangeHandler == nil {
+		return core.ErrNilRangeHandler
+	}
+
+	pendingWrites := handler.getPendingWrites()
+	visitedPendingKeys := make(map[string]struct{}, len(pendingWrites))
+	persistedKeys := make(map[string]pendingWrite, len(pendingWrites))
+
+	var err error
+	isStopped := false
+	handler.bucket.RangeKeys(func(key []byte, val []byte) bool {
+		err = ctx.Err()
+		if err != nil {
+			return false
+		}
+		if bytes.Equal(key, []byte(lastIndexKey)) {
+			return true
+		}
+
+		write, isPending := pendingWrites[string(key)]
+		if !isPending {
+			isStopped = !rangeHandler(key, val)
+			return !isStopped
+		}
+
+		visitedPendingKeys[string(key)] = struct{}{}
+		if write.isRemoved {
+			return true
+		}
+
+		latestVal, errGet := handler.bucket.Get(key)
+		if errGet != nil {
+			return true
+		}
+		if bytes.Equal(latestVal, val) {
+			persistedKeys[string(key)] = write
+		}
+
+		isStopped = !rangeHandler(key, latestVal)
+		return !isStopped
+	})
+	if err != nil || isStopped {
+		return err
+	}
+
+	for key, write := range pendingWrites {
+		_, wasVisited := visitedPendingKeys[key]
+		if wasVisited {
+			continue
+		}
+		if write.isRemoved {
+			persistedKeys[key] = write
+			continue
+		}
+
+		err = ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		val, errGet := handler.bucket.Get([]byte(key))
+		if errGet != nil {
+			continue
+		}
+		if !rangeHandler([]byte(key), val) {
+			return nil
+		}
+	}
+
+	handler.removePendingWrites(persistedKeys)
+
+	return nil
+}
+
 // Close closes the internal bucket
 func (handler *bucketIndexHandler) Close() error {
 	handler.mut.Lock()
@@ -104,6 +274,41 @@ func (handler *bucketIndexHandler) saveNewIndex(newIndex uint32) error {
 	return handler.bucket.Put([]byte(lastIndexKey), latestIndexBytes)
 }
 
+func (handler *bucketIndexHandler) addPendingWrite(key []byte, isRemoved bool) {
+	handler.mutPendingWrites.Lock()
+	defer handler.mutPendingWrites.Unlock()
+
+	handler.writesNonce++
+	handler.pendingWrites[string(key)] = pendingWrite{
+		nonce:     handler.writesNonce,
+		isRemoved: isRemoved,
+	}
+}
+
+func (handler *bucketIndexHandler) getPendingWrites() map[string]pendingWrite {
+	handler.mutPendingWrites.Lock()
+	defer handler.mutPendingWrites.Unlock()
+
+	pendingWrites := make(map[string]pendingWrite, len(handler.pendingWrites))
+	for key, write := range handler.pendingWrites {
+		pendingWrites[key] = write
+	}
+
+	return pendingWrites
+}
+
+// removePendingWrites forgets the writes found persisted, unless the keys were written again meanwhile
+func (handler *bucketIndexHandler) removePendingWrites(persistedWrites map[string]pendingWrite) {
+	handler.mutPendingWrites.Lock()
+	defer handler.mutPendingWrites.Unlock()
+
+	for key, write := range persistedWrites {
+		if handler.pendingWrites[key].nonce == write.nonce {
+			delete(handler.pendingWrites, key)
+		}
+	}
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (handler *bucketIndexHandler) IsInterfaceNil() bool {
 	return handler == nil
@@ -1,6 +1,7 @@
 package bucket
 
 import (
+	"context"
 	"encoding/binary"
 	"errors"
 	"sync"
@@ -108,6 +109,281 @@ func TestBucketIndexHandler_AllocateBucketIndex(t *testing.T) {
 	})
 }
 
+func TestBucketIndexHandler_ReserveBucketIndex(t *testing.T) {
+	t.Parallel()
+
+	t.Run("get returns error", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewBucketIndexHandler(&testscommon.StorerStub{
+			GetCalled: func(key []byte) ([]byte, error) {
+				return nil, expectedErr
+			},
+		})
+		assert.NotNil(t, handler)
+
+		err := handler.ReserveBucketIndex(5)
+		assert.Equal(t, expectedErr, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewBucketIndexHandler(testscommon.NewStorerMock())
+		assert.NotNil(t, handler)
+
+		err := handler.ReserveBucketIndex(5)
+		assert.Nil(t, err)
+		lastIndex, _ := handler.GetLastIndex()
+		assert.Equal(t, uint32(5), lastIndex)
+
+		err = handler.ReserveBucketIndex(3)
+		assert.Nil(t, err)
+		lastIndex, _ = handler.GetLastIndex()
+		assert.Equal(t, uint32(5), lastIndex)
+
+		index, _ := handler.AllocateBucketIndex()
+		assert.Equal(t, uint32(6), index)
+	})
+}
+
+func TestBucketIndexHandler_RangeKeys(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil handler should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewBucketIndexHandler(testscommon.NewStorerMock())
+		err := handler.RangeKeys(context.Background(), nil)
+		assert.Equal(t, core.ErrNilRangeHandler, err)
+	})
+	t.Run("done context should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewBucketIndexHandler(testscommon.NewStorerMock())
+		assert.Nil(t, handler.Put([]byte("key"), []byte("data")))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := handler.RangeKeys(ctx, func(key []byte, val []byte) bool {
+			assert.Fail(t, "should have not been called")
+			return true
+		})
+		assert.Equal(t, context.Canceled, err)
+	})
+	t.Run("should skip the last index key", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewBucketIndexHandler(testscommon.NewStorerMock())
+		assert.Nil(t, handler.Put([]byte("key1"), []byte("data1")))
+		assert.Nil(t, handler.Put([]byte("key2"), []byte("data2")))
+
+		entries := make(map[string]string)
+		err := handler.RangeKeys(context.Background(), func(key []byte, val []byte) bool {
+			entries[string(key)] = string(val)
+			return true
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"key1": "data1", "key2": "data2"}, entries)
+	})
+	t.Run("should stop when the handler returns false", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewBucketIndexHandler(testscommon.NewStorerMock())
+		assert.Nil(t, handler.Put([]byte("key1"), []byte("data1")))
+		assert.Nil(t, handler.Put([]byte("key2"), []byte("data2")))
+
+		numCalls := 0
+		err := handler.RangeKeys(context.Background(), func(key []byte, val []byte) bool {
+			numCalls++
+			return false
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, numCalls)
+	})
+	t.Run("should include the entries still in the write batch", func(t *testing.T) {
+		t.Parallel()
+
+		bucket := newBatchedStorerStub()
+		handler, _ := NewBucketIndexHandler(bucket)
+		assert.Nil(t, handler.Put([]byte("persisted"), []byte("data")))
+		assert.Nil(t, handler.Put([]byte("updated"), []byte("old data")))
+		assert.Nil(t, handler.Put([]byte("removed"), []byte("data")))
+		bucket.flush()
+
+		assert.Nil(t, handler.Put([]byte("updated"), []byte("new data")))
+		assert.Nil(t, handler.Remove([]byte("removed")))
+		assert.Nil(t, handler.Put([]byte("new"), []byte("data")))
+
+		entries := make(map[string]string)
+		err := handler.RangeKeys(context.Background(), func(key []byte, val []byte) bool {
+			entries[string(key)] = string(val)
+			return true
+		})
+		assert.Nil(t, err)
+		expectedEntries := map[string]string{
+			"persisted": "data",
+			"updated":   "new data",
+			"new":       "data",
+		}
+		assert.Equal(t, expectedEntries, entries)
+		assert.Equal(t, 3, len(handler.pendingWrites))
+
+		// the writes found persisted are forgotten
+		bucket.flush()
+		entries = make(map[string]string)
+		err = handler.RangeKeys(context.Background(), func(key []byte, val []byte) bool {
+			entries[string(key)] = string(val)
+			return true
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, expectedEntries, entries)
+		assert.Zero(t, len(handler.pendingWrites))
+	})
+}
+
+type batchedStorerStub struct {
+	*testscommon.StorerStub
+	mut       sync.Mutex
+	persisted map[string][]byte
+	batch     map[string][]byte
+}
+
+// newBatchedStorerStub returns a storer which, as the LevelDB persister, ranges only over the flushed entries
+func newBatchedStorerStub() *batchedStorerStub {
+	stub := &batchedStorerStub{
+		persisted: make(map[string][]byte),
+		batch:     make(map[string][]byte),
+	}
+	stub.StorerStub = &testscommon.StorerStub{
+		PutCalled: func(key, data []byte) error {
+			stub.mut.Lock()
+			defer stub.mut.Unlock()
+
+			stub.batch[string(key)] = data
+			return nil
+		},
+		RemoveCalled: func(key []byte) error {
+			stub.mut.Lock()
+			defer stub.mut.Unlock()
+
+			stub.batch[string(key)] = nil
+			return nil
+		},
+		GetCalled: func(key []byte) ([]byte, error) {
+			stub.mut.Lock()
+			defer stub.mut.Unlock()
+
+			val, isInBatch := stub.batch[string(key)]
+			if !isInBatch {
+				val = stub.persisted[string(key)]
+			}
+			if val == nil {
+				return nil, expectedErr
+			}
+
+			return val, nil
+		},
+		HasCalled: func(key []byte) error {
+			_, err := stub.Get(key)
+			return err
+		},
+		RangeKeysCalled: func(handler func(key []byte, val []byte) bool) {
+			stub.mut.Lock()
+			persisted := make(map[string][]byte, len(stub.persisted))
+			for key, val := range stub.persisted {
+				persisted[key] = val
+			}
+			stub.mut.Unlock()
+
+			for key, val := range persisted {
+				if !handler([]byte(key), val) {
+					return
+				}
+			}
+		},
+	}
+
+	return stub
+}
+
+func (stub *batchedStorerStub) flush() {
+	stub.mut.Lock()
+	defer stub.mut.Unlock()
+
+	for key, val := range stub.batch {
+		if val == nil {
+			delete(stub.persisted, key)
+			continue
+		}
+		stub.persisted[key] = val
+	}
+	stub.batch = make(map[string][]byte)
+}
+
+func TestBucketIndexHandler_Remove(t *testing.T) {
+	t.Parallel()
+
+	handler, _ := NewBucketIndexHandler(testscommon.NewStorerMock())
+	index, err := handler.AllocateBucketIndex()
+	assert.Nil(t, err)
+	assert.Nil(t, handler.Put([]byte("key"), []byte("data")))
+
+	assert.Nil(t, handler.Remove([]byte("key")))
+	assert.NotNil(t, handler.Has([]byte("key")))
+
+	lastIndex, err := handler.GetLastIndex()
+	assert.Nil(t, err)
+	assert.Equal(t, index, lastIndex)
+}
+
+func TestBucketIndexHandler_ConditionalWrites(t *testing.T) {
+	t.Parallel()
+
+	t.Run("bucket fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewBucketIndexHandler(&testscommon.StorerStub{
+			GetCalled: func(key []byte) ([]byte, error) {
+				return nil, expectedErr
+			},
+			PutCalled: func(key, data []byte) error {
+				assert.Fail(t, "should have not been called")
+				return nil
+			},
+			RemoveCalled: func(key []byte) error {
+				assert.Fail(t, "should have not been called")
+				return nil
+			},
+		})
+
+		wasPut, err := handler.PutIfNotExists([]byte("key"), []byte("data"))
+		assert.Equal(t, expectedErr, err)
+		assert.False(t, wasPut)
+		assert.Equal(t, expectedErr, handler.RemoveIfEqual([]byte("key"), []byte("data")))
+	})
+	t.Run("should write only if the key does not exist and remove only the same data", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewBucketIndexHandler(testscommon.NewStorerMock())
+
+		wasPut, err := handler.PutIfNotExists([]byte("key"), []byte("data"))
+		assert.Nil(t, err)
+		assert.True(t, wasPut)
+		wasPut, err = handler.PutIfNotExists([]byte("key"), []byte("other data"))
+		assert.Nil(t, err)
+		assert.False(t, wasPut)
+		data, err := handler.Get([]byte("key"))
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("data"), data)
+
+		assert.Nil(t, handler.RemoveIfEqual([]byte("key"), []byte("other data")))
+		assert.Nil(t, handler.Has([]byte("key")))
+		assert.Nil(t, handler.RemoveIfEqual([]byte("key"), []byte("data")))
+		assert.NotNil(t, handler.Has([]byte("key")))
+		assert.Nil(t, handler.RemoveIfEqual([]byte("key"), []byte("data")))
+	})
+}
+
 func TestBucketIndexHandler_ConcurrentCallsShouldWork(t *testing.T) {
 	t.Parallel()
 
@@ -131,7 +407,7 @@ func TestBucketIndexHandler_ConcurrentCallsShouldWork(t *testing.T) {
 	wg.Add(numCalls)
 	for i := 0; i < numCalls; i++ {
 		go func(idx int) {
-			switch idx % 6 {
+			switch idx % 7 {
 			case 0:
 				_, err := handler.AllocateBucketIndex()
 				assert.Nil(t, err)
@@ -147,6 +423,8 @@ func TestBucketIndexHandler_ConcurrentCallsShouldWork(t *testing.T) {
 			case 5:
 				_, err := handler.GetLastIndex()
 				assert.Nil(t, err)
+			case 6:
+				assert.Nil(t, handler.Remove([]byte("key")))
 			default:
 				assert.Fail(t, "should not hit default")
 			}
@@ -1,6 +1,7 @@
 package bucket
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
@@ -42,11 +43,31 @@ func (handler *mongodbIndexHandler) AllocateBucketIndex() (uint32, error) {
 	return handler.mongodbClient.IncrementIndex(handler.usersColl, []byte(lastIndexKey))
 }
 
+// ReserveBucketIndex marks all the indexes up to the provided one as allocated
+func (handler *mongodbIndexHandler) ReserveBucketIndex(index uint32) error {
+	return handler.mongodbClient.PutIndexIfGreater(handler.usersColl, []byte(lastIndexKey), index)
+}
+
 // Put adds data to storer
 func (handler *mongodbIndexHandler) Put(key, data []byte) error {
 	return handler.mongodbClient.Put(handler.usersColl, key, data)
 }
 
+// Remove removes the key from the collection, without releasing any index
+func (handler *mongodbIndexHandler) Remove(key []byte) error {
+	return handler.mongodbClient.Remove(handler.usersColl, key)
+}
+
+// PutIfNotExists adds data to storer only if the key does not exist yet. It returns true if the data was added
+func (handler *mongodbIndexHandler) PutIfNotExists(key, data []byte) (bool, error) {
+	return handler.mongodbClient.PutIfNotExists(handler.usersColl, key, data)
+}
+
+// RemoveIfEqual removes the key from the collection only if it still holds the provided data
+func (handler *mongodbIndexHandler) RemoveIfEqual(key, data []byte) error {
+	return handler.mongodbClient.RemoveIfEqual(handler.usersColl, key, data)
+}
+
 // Get returns the value for the key from storer
 func (handler *mongodbIndexHandler) Get(key []byte) ([]byte, error) {
 	return handler.mongodbClient.Get(handler.usersColl, key)
@@ -62,6 +83,22 @@ func (handler *mongodbIndexHandler) GetLastIndex() (uint32, error) {
 	return handler.mongodbClient.GetIndex(handler.usersColl, []byte(lastIndexKey))
 }
 
+// RangeKeys calls the provided handler for each key-value pair of the collection, until the handler
+// returns false or the context is done. The entry holding the last allocated index is skipped
+func (handler *mongodbIndexHandler) RangeKeys(ctx context.Context, rangeHandler func(key []byte, val []byte) bool) error {
+	if rangeHandler == nil {
+		return core.ErrNilRangeHandler
+	}
+
+	return handler.mongodbClient.RangeKeys(ctx, handler.usersColl, func(key []byte, val []byte) bool {
+		if string(key) == lastIndexKey {
+			return true
+		}
+
+		return rangeHandler(key, val)
+	})
+}
+
 // Close closes the internal bucket
 func (handler *mongodbIndexHandler) Close() error {
 	return handler.mongodbClient.Close()
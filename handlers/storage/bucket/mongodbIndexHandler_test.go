@@ -1,6 +1,7 @@
 package bucket
 
 import (
+	"context"
 	"errors"
 	"sync"
 	"testing"
@@ -61,6 +62,70 @@ func TestMongodbIndexHandler_IsInterfaceNil(t *testing.T) {
 	assert.False(t, mid.IsInterfaceNil())
 }
 
+func TestMongoDBIndexHandler_ReserveBucketIndex(t *testing.T) {
+	t.Parallel()
+
+	wasCalled := false
+	handler, _ := NewMongoDBIndexHandler(&testscommon.MongoDBClientStub{
+		PutIndexIfGreaterCalled: func(collID mongodb.CollectionID, key []byte, index uint32) error {
+			assert.Equal(t, mongodb.CollectionID("collName"), collID)
+			assert.Equal(t, []byte(lastIndexKey), key)
+			assert.Equal(t, uint32(5), index)
+			wasCalled = true
+			return nil
+		},
+	}, "collName")
+
+	err := handler.ReserveBucketIndex(5)
+	assert.Nil(t, err)
+	assert.True(t, wasCalled)
+}
+
+func TestMongoDBIndexHandler_RangeKeys(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil handler should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewMongoDBIndexHandler(&testscommon.MongoDBClientStub{
+			RangeKeysCalled: func(ctx context.Context, coll mongodb.CollectionID, handler func(key []byte, val []byte) bool) error {
+				assert.Fail(t, "should have not been called")
+				return nil
+			},
+		}, "collName")
+		err := handler.RangeKeys(context.Background(), nil)
+		assert.Equal(t, core.ErrNilRangeHandler, err)
+	})
+	t.Run("should skip the last index key", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewMongoDBIndexHandler(&testscommon.MongoDBClientStub{
+			RangeKeysCalled: func(ctx context.Context, coll mongodb.CollectionID, handler func(key []byte, val []byte) bool) error {
+				assert.Equal(t, mongodb.CollectionID("collName"), coll)
+				if !handler([]byte("key1"), []byte("data1")) {
+					return nil
+				}
+				if !handler([]byte(lastIndexKey), []byte("index")) {
+					return nil
+				}
+				if !handler([]byte("key2"), []byte("data2")) {
+					return nil
+				}
+				handler([]byte("key3"), []byte("data3"))
+				return expectedErr
+			},
+		}, "collName")
+
+		entries := make(map[string]string)
+		err := handler.RangeKeys(context.Background(), func(key []byte, val []byte) bool {
+			entries[string(key)] = string(val)
+			return len(entries) < 2
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"key1": "data1", "key2": "data2"}, entries)
+	})
+}
+
 func TestMongoDBIndexHandler_Operations(t *testing.T) {
 	t.Parallel()
 
@@ -82,7 +147,7 @@ func TestMongoDBIndexHandler_Operations(t *testing.T) {
 	wg.Add(numCalls)
 	for i := 0; i < numCalls; i++ {
 		go func(idx int) {
-			switch idx % 6 {
+			switch idx % 9 {
 			case 0:
 				_, err := handler.AllocateBucketIndex()
 				assert.Nil(t, err)
@@ -98,6 +163,13 @@ func TestMongoDBIndexHandler_Operations(t *testing.T) {
 			case 5:
 				_, err := handler.GetLastIndex()
 				assert.Nil(t, err)
+			case 6:
+				assert.Nil(t, handler.Remove([]byte("key")))
+			case 7:
+				_, err := handler.PutIfNotExists([]byte("key"), []byte("data"))
+				assert.Nil(t, err)
+			case 8:
+				assert.Nil(t, handler.RemoveIfEqual([]byte("key"), []byte("data")))
 			default:
 				assert.Fail(t, "should not hit default")
 			}
@@ -0,0 +1,250 @@
+package bucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage"
+)
+
+const logReshardingProgressInterval = 1000
+
+// ArgReshardingStorageWithIndex is the DTO used to create a new instance of resharding storage with index
+type ArgReshardingStorageWithIndex struct {
+	PreviousLayout ArgShardedStorageWithIndex
+	CurrentLayout  ArgShardedStorageWithIndex
+}
+
+type reshardingStorageWithIndex struct {
+	previousStorage *shardedStorageWithIndex
+	currentStorage  *shardedStorageWithIndex
+	cancel          func()
+	done            chan struct{}
+}
+
+// NewReshardingStorageWithIndex returns a new instance of resharding storage with index, which moves the
+// users from the previous bucket layout to the current one in background. While resharding, reads go
+// through both layouts, while writes and index allocations only use the current one.
+// Several instances may share the same layouts: the users are moved with conditional writes, so the moves
+// of an instance never overwrite nor restore the users saved or removed meanwhile by another one
+func NewReshardingStorageWithIndex(args ArgReshardingStorageWithIndex) (*reshardingStorageWithIndex, error) {
+	previousStorage, err := NewShardedStorageWithIndex(args.PreviousLayout)
+	if err != nil {
+		return nil, fmt.Errorf("%w for the previous layout", err)
+	}
+
+	currentStorage, err := NewShardedStorageWithIndex(args.CurrentLayout)
+	if err != nil {
+		return nil, fmt.Errorf("%w for the current layout", err)
+	}
+
+	// the indexes allocated in the previous layout, including those of the users not moved yet,
+	// should never be allocated again by the current layout
+	lastAllocatedIndex, err := previousStorage.GetLastAllocatedIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	err = currentStorage.ReserveIndexesUpTo(lastAllocatedIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rswi := &reshardingStorageWithIndex{
+		previousStorage: previousStorage,
+		currentStorage:  currentStorage,
+		cancel:          cancel,
+		done:            make(chan struct{}),
+	}
+
+	log.Info("resharding started",
+		"previous buckets", len(args.PreviousLayout.BucketHandlers),
+		"current buckets", len(args.CurrentLayout.BucketHandlers),
+		"reserved indexes up to", lastAllocatedIndex)
+
+	go rswi.moveUsers(ctx)
+
+	return rswi, nil
+}
+
+func (rswi *reshardingStorageWithIndex) moveUsers(ctx context.Context) {
+	defer close(rswi.done)
+
+	numMoved, numExisting := 0, 0
+	var moveErr error
+	err := rswi.previousStorage.RangeKeys(ctx, func(key []byte, val []byte) bool {
+		var wasMoved bool
+		wasMoved, moveErr = rswi.moveUser(key, val)
+		if moveErr != nil {
+			return false
+		}
+
+		if wasMoved {
+			numMoved++
+		} else {
+			numExisting++
+		}
+		if (numMoved+numExisting)%logReshardingProgressInterval == 0 {
+			log.Info("resharding in progress", "moved users", numMoved, "already moved users", numExisting)
+		}
+
+		return true
+	})
+	if err == nil {
+		err = moveErr
+	}
+	if err != nil {
+		log.Error("resharding stopped, it will be resumed on the next start", "error", err,
+			"moved users", numMoved, "already moved users", numExisting)
+		return
+	}
+
+	log.Info("resharding completed, the previous layout can be removed from the config",
+		"moved users", numMoved, "already moved users", numExisting)
+}
+
+// moveUser copies the user to the current layout, unless it was already saved there, by any instance. The copy
+// is written only if the key does not exist, so a newer version of the user is never overwritten. As the user
+// may have been removed after it was read, the previous layout is checked again after the copy: Remove deletes
+// the user from the previous layout first, so a copy written after the removal is always found here and undone,
+// unless it was overwritten meanwhile by a new registration
+func (rswi *reshardingStorageWithIndex) moveUser(key []byte, val []byte) (bool, error) {
+	wasCopied, err := rswi.currentStorage.PutIfNotExists(key, val)
+	if err != nil || !wasCopied {
+		return false, err
+	}
+
+	_, err = rswi.previousStorage.Get(key)
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, storage.ErrKeyNotFound) {
+		return false, err
+	}
+
+	return false, rswi.currentStorage.RemoveIfEqual(key, val)
+}
+
+// AllocateIndex returns a new index from the current layout, which was not used before in any of the layouts
+func (rswi *reshardingStorageWithIndex) AllocateIndex(address []byte) (uint32, error) {
+	return rswi.currentStorage.AllocateIndex(address)
+}
+
+// ReserveIndex marks the provided index as used in the current layout
+func (rswi *reshardingStorageWithIndex) ReserveIndex(index uint32) error {
+	return rswi.currentStorage.ReserveIndex(index)
+}
+
+// GetLastAllocatedIndex returns the greatest final index allocated by any of the layouts
+func (rswi *reshardingStorageWithIndex) GetLastAllocatedIndex() (uint32, error) {
+	previousLastIndex, err := rswi.previousStorage.GetLastAllocatedIndex()
+	if err != nil {
+		return 0, err
+	}
+
+	currentLastIndex, err := rswi.currentStorage.GetLastAllocatedIndex()
+	if err != nil {
+		return 0, err
+	}
+
+	if previousLastIndex > currentLastIndex {
+		return previousLastIndex, nil
+	}
+
+	return currentLastIndex, nil
+}
+
+// ReserveIndexesUpTo makes sure that the current layout will only allocate final indexes greater than the provided one
+func (rswi *reshardingStorageWithIndex) ReserveIndexesUpTo(finalIndex uint32) error {
+	return rswi.currentStorage.ReserveIndexesUpTo(finalIndex)
+}
+
+// Put adds data to the current layout
+func (rswi *reshardingStorageWithIndex) Put(key, data []byte) error {
+	return rswi.currentStorage.Put(key, data)
+}
+
+// Remove removes the key from both layouts. The previous layout goes first, so a concurrent move of the
+// user finds it missing after copying it and undoes the copy
+func (rswi *reshardingStorageWithIndex) Remove(key []byte) error {
+	err := rswi.previousStorage.Remove(key)
+	if err != nil {
+		return err
+	}
+
+	return rswi.currentStorage.Remove(key)
+}
+
+// Get returns the value for the key from the current layout, falling back to the previous one
+// if the key was not moved yet
+func (rswi *reshardingStorageWithIndex) Get(key []byte) ([]byte, error) {
+	data, err := rswi.currentStorage.Get(key)
+	if !errors.Is(err, storage.ErrKeyNotFound) {
+		return data, err
+	}
+
+	return rswi.previousStorage.Get(key)
+}
+
+// Has returns nil if the key exists in any of the layouts
+func (rswi *reshardingStorageWithIndex) Has(key []byte) error {
+	err := rswi.currentStorage.Has(key)
+	if err == nil {
+		return nil
+	}
+
+	return rswi.previousStorage.Has(key)
+}
+
+// Count returns the number of elements in the current layout, which includes the indexes reserved
+// for the previous layout
+func (rswi *reshardingStorageWithIndex) Count() (uint32, error) {
+	return rswi.currentStorage.Count()
+}
+
+// RangeKeys calls the provided handler for each key-value pair of the current layout, then for the
+// ones of the previous layout which were not moved yet, until the handler returns false or the context is done
+func (rswi *reshardingStorageWithIndex) RangeKeys(ctx context.Context, handler func(key []byte, val []byte) bool) error {
+	if handler == nil {
+		return core.ErrNilRangeHandler
+	}
+
+	shouldContinue := true
+	err := rswi.currentStorage.RangeKeys(ctx, func(key []byte, val []byte) bool {
+		shouldContinue = handler(key, val)
+		return shouldContinue
+	})
+	if err != nil || !shouldContinue {
+		return err
+	}
+
+	return rswi.previousStorage.RangeKeys(ctx, func(key []byte, val []byte) bool {
+		if rswi.currentStorage.Has(key) == nil {
+			return true
+		}
+
+		return handler(key, val)
+	})
+}
+
+// Close stops the resharding and closes both layouts
+func (rswi *reshardingStorageWithIndex) Close() error {
+	rswi.cancel()
+	<-rswi.done
+
+	errPrevious := rswi.previousStorage.Close()
+	errCurrent := rswi.currentStorage.Close()
+	if errCurrent != nil {
+		return errCurrent
+	}
+
+	return errPrevious
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (rswi *reshardingStorageWithIndex) IsInterfaceNil() bool {
+	return rswi == nil
+}
@@ -0,0 +1,471 @@
+package bucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+	"github.com/stretchr/testify/assert"
+)
+
+func createLayout(numBuckets uint32) ArgShardedStorageWithIndex {
+	bucketIDProvider, _ := NewBucketIDProvider(numBuckets)
+	bucketHandlers := make(map[uint32]core.IndexHandler, numBuckets)
+	for i := uint32(0); i < numBuckets; i++ {
+		bucketHandlers[i], _ = NewBucketIndexHandler(testscommon.NewStorerMock())
+	}
+
+	return ArgShardedStorageWithIndex{
+		BucketIDProvider: bucketIDProvider,
+		BucketHandlers:   bucketHandlers,
+	}
+}
+
+func registerUsersInLayout(t *testing.T, layout ArgShardedStorageWithIndex, numUsers int) map[string]uint32 {
+	sswi, err := NewShardedStorageWithIndex(layout)
+	assert.Nil(t, err)
+
+	indexes := make(map[string]uint32, numUsers)
+	for i := 0; i < numUsers; i++ {
+		address := fmt.Sprintf("address %d", i)
+		index, errAllocate := sswi.AllocateIndex([]byte(address))
+		assert.Nil(t, errAllocate)
+		assert.Nil(t, sswi.Put([]byte(address), []byte("data "+address)))
+
+		indexes[address] = index
+	}
+
+	return indexes
+}
+
+func TestNewReshardingStorageWithIndex(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid previous layout should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := ArgReshardingStorageWithIndex{
+			PreviousLayout: ArgShardedStorageWithIndex{
+				BucketIDProvider: &testscommon.BucketIDProviderStub{},
+			},
+			CurrentLayout: createLayout(2),
+		}
+		rswi, err := NewReshardingStorageWithIndex(args)
+		assert.True(t, errors.Is(err, core.ErrInvalidBucketHandlers))
+		assert.Nil(t, rswi)
+	})
+	t.Run("invalid current layout should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := ArgReshardingStorageWithIndex{
+			PreviousLayout: createLayout(2),
+			CurrentLayout: ArgShardedStorageWithIndex{
+				BucketIDProvider: nil,
+			},
+		}
+		rswi, err := NewReshardingStorageWithIndex(args)
+		assert.True(t, errors.Is(err, core.ErrNilBucketIDProvider))
+		assert.Nil(t, rswi)
+	})
+	t.Run("previous layout fails to return last index should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := ArgReshardingStorageWithIndex{
+			PreviousLayout: ArgShardedStorageWithIndex{
+				BucketIDProvider: &testscommon.BucketIDProviderStub{},
+				BucketHandlers: map[uint32]core.IndexHandler{
+					0: &testscommon.BucketIndexHandlerStub{
+						GetLastIndexCalled: func() (uint32, error) {
+							return 0, expectedErr
+						},
+					},
+				},
+			},
+			CurrentLayout: createLayout(2),
+		}
+		rswi, err := NewReshardingStorageWithIndex(args)
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, rswi)
+	})
+	t.Run("current layout fails to reserve indexes should error", func(t *testing.T) {
+		t.Parallel()
+
+		previousLayout := createLayout(2)
+		registerUsersInLayout(t, previousLayout, 10)
+		args := ArgReshardingStorageWithIndex{
+			PreviousLayout: previousLayout,
+			CurrentLayout: ArgShardedStorageWithIndex{
+				BucketIDProvider: &testscommon.BucketIDProviderStub{},
+				BucketHandlers: map[uint32]core.IndexHandler{
+					0: &testscommon.BucketIndexHandlerStub{
+						ReserveBucketIndexCalled: func(index uint32) error {
+							return expectedErr
+						},
+					},
+				},
+			},
+		}
+		rswi, err := NewReshardingStorageWithIndex(args)
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, rswi)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		rswi, err := NewReshardingStorageWithIndex(ArgReshardingStorageWithIndex{
+			PreviousLayout: createLayout(2),
+			CurrentLayout:  createLayout(4),
+		})
+		assert.Nil(t, err)
+		assert.False(t, rswi.IsInterfaceNil())
+		assert.Equal(t, core.ErrNilRangeHandler, rswi.RangeKeys(context.Background(), nil))
+		assert.Nil(t, rswi.Close())
+	})
+}
+
+func TestReshardingStorageWithIndex_ShouldNotReuseIndexes(t *testing.T) {
+	t.Parallel()
+
+	for _, numBuckets := range [][2]uint32{{4, 8}, {4, 3}, {1, 5}, {7, 2}} {
+		previousLayout := createLayout(numBuckets[0])
+		previousIndexes := registerUsersInLayout(t, previousLayout, 100)
+
+		rswi, err := NewReshardingStorageWithIndex(ArgReshardingStorageWithIndex{
+			PreviousLayout: previousLayout,
+			CurrentLayout:  createLayout(numBuckets[1]),
+		})
+		assert.Nil(t, err)
+
+		usedIndexes := make(map[uint32]struct{}, len(previousIndexes))
+		for _, index := range previousIndexes {
+			usedIndexes[index] = struct{}{}
+			usedIndexes[index+1] = struct{}{}
+		}
+
+		for i := 0; i < 100; i++ {
+			newIndex, errAllocate := rswi.AllocateIndex([]byte(fmt.Sprintf("new address %d", i)))
+			assert.Nil(t, errAllocate)
+			_, isUsed := usedIndexes[newIndex]
+			assert.False(t, isUsed, "layouts %v, index %d allocated again", numBuckets, newIndex)
+			usedIndexes[newIndex] = struct{}{}
+		}
+
+		assert.Nil(t, rswi.Close())
+	}
+}
+
+func TestReshardingStorageWithIndex_GetLastAllocatedIndex(t *testing.T) {
+	t.Parallel()
+
+	previousLayout := createLayout(2)
+	previousIndexes := registerUsersInLayout(t, previousLayout, 10)
+	rswi, err := NewReshardingStorageWithIndex(ArgReshardingStorageWithIndex{
+		PreviousLayout: previousLayout,
+		CurrentLayout:  createLayout(3),
+	})
+	assert.Nil(t, err)
+
+	maxPreviousIndex := uint32(0)
+	for _, index := range previousIndexes {
+		if index > maxPreviousIndex {
+			maxPreviousIndex = index
+		}
+	}
+	lastAllocatedIndex, err := rswi.GetLastAllocatedIndex()
+	assert.Nil(t, err)
+	assert.LessOrEqual(t, maxPreviousIndex, lastAllocatedIndex)
+
+	newIndex, err := rswi.AllocateIndex([]byte("new address"))
+	assert.Nil(t, err)
+	lastAllocatedIndex, err = rswi.GetLastAllocatedIndex()
+	assert.Nil(t, err)
+	assert.LessOrEqual(t, newIndex, lastAllocatedIndex)
+
+	assert.Nil(t, rswi.ReserveIndexesUpTo(lastAllocatedIndex+1000))
+	newIndex, err = rswi.AllocateIndex([]byte("other new address"))
+	assert.Nil(t, err)
+	assert.Greater(t, newIndex, lastAllocatedIndex+1000)
+
+	assert.Nil(t, rswi.Close())
+}
+
+func TestReshardingStorageWithIndex_MoveUsers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should move all users, keeping the newer versions", func(t *testing.T) {
+		t.Parallel()
+
+		numUsers := 50
+		previousLayout := createLayout(4)
+		previousIndexes := registerUsersInLayout(t, previousLayout, numUsers)
+
+		currentLayout := createLayout(8)
+		currentStorage, _ := NewShardedStorageWithIndex(currentLayout)
+		updatedAddress := []byte("address 7")
+		assert.Nil(t, currentStorage.Put(updatedAddress, []byte("newer data")))
+
+		rswi, err := NewReshardingStorageWithIndex(ArgReshardingStorageWithIndex{
+			PreviousLayout: previousLayout,
+			CurrentLayout:  currentLayout,
+		})
+		assert.Nil(t, err)
+		<-rswi.done
+
+		for address := range previousIndexes {
+			expectedData := []byte("data " + address)
+			if address == string(updatedAddress) {
+				expectedData = []byte("newer data")
+			}
+
+			data, errGet := currentStorage.Get([]byte(address))
+			assert.Nil(t, errGet)
+			assert.Equal(t, expectedData, data)
+
+			data, errGet = rswi.Get([]byte(address))
+			assert.Nil(t, errGet)
+			assert.Equal(t, expectedData, data)
+		}
+
+		assert.Nil(t, rswi.Close())
+	})
+	t.Run("current layout fails should stop", func(t *testing.T) {
+		t.Parallel()
+
+		previousLayout := createLayout(2)
+		registerUsersInLayout(t, previousLayout, 10)
+
+		numPutIfNotExistsCalls := 0
+		rswi, err := NewReshardingStorageWithIndex(ArgReshardingStorageWithIndex{
+			PreviousLayout: previousLayout,
+			CurrentLayout: ArgShardedStorageWithIndex{
+				BucketIDProvider: &testscommon.BucketIDProviderStub{},
+				BucketHandlers: map[uint32]core.IndexHandler{
+					0: &testscommon.BucketIndexHandlerStub{
+						PutIfNotExistsCalled: func(key, data []byte) (bool, error) {
+							numPutIfNotExistsCalls++
+							return false, expectedErr
+						},
+						PutCalled: func(key, data []byte) error {
+							assert.Fail(t, "should have not been called")
+							return nil
+						},
+					},
+				},
+			},
+		})
+		assert.Nil(t, err)
+		<-rswi.done
+		assert.Equal(t, 1, numPutIfNotExistsCalls)
+		assert.Nil(t, rswi.Close())
+	})
+}
+
+func TestReshardingStorageWithIndex_Remove(t *testing.T) {
+	t.Parallel()
+
+	t.Run("previous layout fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		rswi, _ := NewReshardingStorageWithIndex(ArgReshardingStorageWithIndex{
+			PreviousLayout: ArgShardedStorageWithIndex{
+				BucketIDProvider: &testscommon.BucketIDProviderStub{},
+				BucketHandlers: map[uint32]core.IndexHandler{
+					0: &testscommon.BucketIndexHandlerStub{
+						RemoveCalled: func(key []byte) error {
+							return expectedErr
+						},
+					},
+				},
+			},
+			CurrentLayout: createLayout(2),
+		})
+		assert.Equal(t, expectedErr, rswi.Remove([]byte("address")))
+		assert.Nil(t, rswi.Close())
+	})
+	t.Run("should remove from both layouts, without restoring the user", func(t *testing.T) {
+		t.Parallel()
+
+		previousLayout := createLayout(2)
+		previousIndexes := registerUsersInLayout(t, previousLayout, 10)
+		currentLayout := createLayout(4)
+		rswi, err := NewReshardingStorageWithIndex(ArgReshardingStorageWithIndex{
+			PreviousLayout: previousLayout,
+			CurrentLayout:  currentLayout,
+		})
+		assert.Nil(t, err)
+
+		removedAddress := []byte("address 3")
+		assert.Nil(t, rswi.Remove(removedAddress))
+		<-rswi.done
+
+		_, err = rswi.Get(removedAddress)
+		assert.True(t, errors.Is(err, storage.ErrKeyNotFound))
+		assert.NotNil(t, rswi.Has(removedAddress))
+
+		previousStorage, _ := NewShardedStorageWithIndex(previousLayout)
+		assert.NotNil(t, previousStorage.Has(removedAddress))
+		currentStorage, _ := NewShardedStorageWithIndex(currentLayout)
+		assert.NotNil(t, currentStorage.Has(removedAddress))
+
+		newIndex, err := rswi.AllocateIndex(removedAddress)
+		assert.Nil(t, err)
+		assert.Greater(t, newIndex, previousIndexes[string(removedAddress)])
+		assert.Nil(t, rswi.Close())
+	})
+}
+
+func TestReshardingStorageWithIndex_InstancesSharingTheLayouts(t *testing.T) {
+	t.Parallel()
+
+	previousLayout := createLayout(2)
+	registerUsersInLayout(t, previousLayout, 10)
+	currentLayout := createLayout(4)
+
+	firstInstance, err := NewReshardingStorageWithIndex(ArgReshardingStorageWithIndex{
+		PreviousLayout: previousLayout,
+		CurrentLayout:  currentLayout,
+	})
+	assert.Nil(t, err)
+	secondInstance, err := NewReshardingStorageWithIndex(ArgReshardingStorageWithIndex{
+		PreviousLayout: previousLayout,
+		CurrentLayout:  currentLayout,
+	})
+	assert.Nil(t, err)
+	<-firstInstance.done
+	<-secondInstance.done
+
+	// each case replays a move of the first instance, with the user read from the previous layout
+	// before the second instance saved or removed it
+	t.Run("should not overwrite a newer version", func(t *testing.T) {
+		address := []byte("address 1")
+		assert.Nil(t, secondInstance.Put(address, []byte("newer data")))
+
+		wasMoved, errMove := firstInstance.moveUser(address, []byte("data address 1"))
+		assert.Nil(t, errMove)
+		assert.False(t, wasMoved)
+
+		data, errGet := firstInstance.Get(address)
+		assert.Nil(t, errGet)
+		assert.Equal(t, []byte("newer data"), data)
+	})
+	t.Run("should not restore a removed user", func(t *testing.T) {
+		address := []byte("address 2")
+		assert.Nil(t, secondInstance.Remove(address))
+
+		wasMoved, errMove := firstInstance.moveUser(address, []byte("data address 2"))
+		assert.Nil(t, errMove)
+		assert.False(t, wasMoved)
+
+		_, errGet := firstInstance.Get(address)
+		assert.True(t, errors.Is(errGet, storage.ErrKeyNotFound))
+		_, errGet = secondInstance.Get(address)
+		assert.True(t, errors.Is(errGet, storage.ErrKeyNotFound))
+	})
+	t.Run("should not remove a user registered again", func(t *testing.T) {
+		address := []byte("address 3")
+		assert.Nil(t, secondInstance.Remove(address))
+		assert.Nil(t, secondInstance.Put(address, []byte("new registration")))
+
+		wasMoved, errMove := firstInstance.moveUser(address, []byte("data address 3"))
+		assert.Nil(t, errMove)
+		assert.False(t, wasMoved)
+
+		data, errGet := firstInstance.Get(address)
+		assert.Nil(t, errGet)
+		assert.Equal(t, []byte("new registration"), data)
+	})
+
+	assert.Nil(t, firstInstance.Close())
+	assert.Nil(t, secondInstance.Close())
+}
+
+func TestReshardingStorageWithIndex_ReadsThroughBothLayouts(t *testing.T) {
+	t.Parallel()
+
+	previousLayout := createLayout(2)
+	registerUsersInLayout(t, previousLayout, 5)
+	currentStorageKeys := map[string][]byte{
+		"address 1": []byte("newer data"),
+		"new user":  []byte("new data"),
+	}
+
+	moveCalls := make(chan struct{})
+	rswi, err := NewReshardingStorageWithIndex(ArgReshardingStorageWithIndex{
+		PreviousLayout: previousLayout,
+		CurrentLayout: ArgShardedStorageWithIndex{
+			BucketIDProvider: &testscommon.BucketIDProviderStub{},
+			BucketHandlers: map[uint32]core.IndexHandler{
+				0: &testscommon.BucketIndexHandlerStub{
+					GetCalled: func(key []byte) ([]byte, error) {
+						data, found := currentStorageKeys[string(key)]
+						if !found {
+							return nil, fmt.Errorf("%w for key %s", storage.ErrKeyNotFound, key)
+						}
+						return data, nil
+					},
+					HasCalled: func(key []byte) error {
+						_, found := currentStorageKeys[string(key)]
+						if !found {
+							return storage.ErrKeyNotFound
+						}
+						return nil
+					},
+					PutCalled: func(key, data []byte) error {
+						// block the background move, so the reads hit both layouts
+						<-moveCalls
+						return expectedErr
+					},
+					RangeKeysCalled: func(ctx context.Context, handler func(key []byte, val []byte) bool) error {
+						for key, val := range currentStorageKeys {
+							if !handler([]byte(key), val) {
+								return nil
+							}
+						}
+						return nil
+					},
+				},
+			},
+		},
+	})
+	assert.Nil(t, err)
+
+	data, err := rswi.Get([]byte("address 1"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("newer data"), data)
+
+	data, err = rswi.Get([]byte("address 2"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("data address 2"), data)
+
+	data, err = rswi.Get([]byte("new user"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("new data"), data)
+
+	assert.Nil(t, rswi.Has([]byte("address 3")))
+	assert.Nil(t, rswi.Has([]byte("new user")))
+	assert.NotNil(t, rswi.Has([]byte("missing user")))
+
+	entries := make(map[string]string)
+	err = rswi.RangeKeys(context.Background(), func(key []byte, val []byte) bool {
+		_, found := entries[string(key)]
+		assert.False(t, found, "key %s returned twice", key)
+		entries[string(key)] = string(val)
+		return true
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"address 0": "data address 0",
+		"address 1": "newer data",
+		"address 2": "data address 2",
+		"address 3": "data address 3",
+		"address 4": "data address 4",
+		"new user":  "new data",
+	}, entries)
+
+	close(moveCalls)
+	assert.Nil(t, rswi.Close())
+}
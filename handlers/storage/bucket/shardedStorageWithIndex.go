@@ -1,7 +1,9 @@
 package bucket
 
 import (
+	"context"
 	"fmt"
+	"sort"
 
 	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
 	"github.com/multiversx/mx-chain-core-go/core/check"
@@ -65,6 +67,19 @@ func (sswi *shardedStorageWithIndex) AllocateIndex(address []byte) (uint32, erro
 	return sswi.getNextFinalIndex(baseIndex, bucketID), nil
 }
 
+// ReserveIndex marks the provided index as used, so it will never be allocated again. It is needed when
+// users are copied from another storage, as their guardian keys are derived from the already allocated index.
+// The index is reserved in the bucket able to allocate it, which is not necessarily the bucket of the user
+func (sswi *shardedStorageWithIndex) ReserveIndex(index uint32) error {
+	bucketID, baseIndex := sswi.getBucketIDAndBaseIndexForFinalIndex(index)
+	bucket, found := sswi.bucketHandlers[bucketID]
+	if !found {
+		return fmt.Errorf("%w for index %d", core.ErrInvalidBucketID, index)
+	}
+
+	return bucket.ReserveBucketIndex(baseIndex)
+}
+
 // Put adds data to the bucket where the key should be
 func (sswi *shardedStorageWithIndex) Put(key, data []byte) error {
 	bucket, _, err := sswi.getBucketForKey(key)
@@ -75,6 +90,37 @@ func (sswi *shardedStorageWithIndex) Put(key, data []byte) error {
 	return bucket.Put(key, data)
 }
 
+// Remove removes the key from its bucket. The index allocated for it stays used, so it is never handed out again
+func (sswi *shardedStorageWithIndex) Remove(key []byte) error {
+	bucket, _, err := sswi.getBucketForKey(key)
+	if err != nil {
+		return err
+	}
+
+	return bucket.Remove(key)
+}
+
+// PutIfNotExists adds data to the bucket where the key should be, only if the key does not exist there yet.
+// It returns true if the data was added
+func (sswi *shardedStorageWithIndex) PutIfNotExists(key, data []byte) (bool, error) {
+	bucket, _, err := sswi.getBucketForKey(key)
+	if err != nil {
+		return false, err
+	}
+
+	return bucket.PutIfNotExists(key, data)
+}
+
+// RemoveIfEqual removes the key from its bucket only if it still holds the provided data
+func (sswi *shardedStorageWithIndex) RemoveIfEqual(key, data []byte) error {
+	bucket, _, err := sswi.getBucketForKey(key)
+	if err != nil {
+		return err
+	}
+
+	return bucket.RemoveIfEqual(key, data)
+}
+
 // Get returns the value for the key from the bucket where the key should be
 func (sswi *shardedStorageWithIndex) Get(key []byte) ([]byte, error) {
 	bucket, _, err := sswi.getBucketForKey(key)
@@ -110,6 +156,32 @@ func (sswi *shardedStorageWithIndex) Count() (uint32, error) {
 	return count, nil
 }
 
+// RangeKeys calls the provided handler for each key-value pair of all buckets, until the handler
+// returns false or the context is done
+func (sswi *shardedStorageWithIndex) RangeKeys(ctx context.Context, handler func(key []byte, val []byte) bool) error {
+	if handler == nil {
+		return core.ErrNilRangeHandler
+	}
+
+	shouldContinue := true
+	rangeHandler := func(key []byte, val []byte) bool {
+		shouldContinue = handler(key, val)
+		return shouldContinue
+	}
+
+	for _, bucketID := range sswi.getSortedBucketIDs() {
+		err := sswi.bucketHandlers[bucketID].RangeKeys(ctx, rangeHandler)
+		if err != nil {
+			return err
+		}
+		if !shouldContinue {
+			return nil
+		}
+	}
+
+	return nil
+}
+
 // Close closes the managed buckets
 func (sswi *shardedStorageWithIndex) Close() error {
 	var lastError error
@@ -149,6 +221,68 @@ func (sswi *shardedStorageWithIndex) getNextFinalIndex(newIndex, bucketID uint32
 	return indexMultiplier * (newIndex*numBuckets + bucketID)
 }
 
+// GetLastAllocatedIndex returns the greatest final index allocated by any of the buckets
+func (sswi *shardedStorageWithIndex) GetLastAllocatedIndex() (uint32, error) {
+	lastAllocatedIndex := uint32(0)
+	for bucketID, bucket := range sswi.bucketHandlers {
+		lastBaseIndex, err := bucket.GetLastIndex()
+		if err != nil {
+			return 0, err
+		}
+		if lastBaseIndex == 0 {
+			continue
+		}
+
+		finalIndex := sswi.getNextFinalIndex(lastBaseIndex, bucketID)
+		if finalIndex > lastAllocatedIndex {
+			lastAllocatedIndex = finalIndex
+		}
+	}
+
+	return lastAllocatedIndex, nil
+}
+
+// ReserveIndexesUpTo makes sure that all the buckets will only allocate final indexes greater than
+// both the provided final index and the one following it, used for the second guardian
+func (sswi *shardedStorageWithIndex) ReserveIndexesUpTo(finalIndex uint32) error {
+	numBuckets := uint32(len(sswi.bucketHandlers))
+	halfIndex := finalIndex / indexMultiplier
+	for bucketID, bucket := range sswi.bucketHandlers {
+		if halfIndex < bucketID {
+			continue
+		}
+
+		err := bucket.ReserveBucketIndex((halfIndex - bucketID) / numBuckets)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getBucketIDAndBaseIndexForFinalIndex is the reverse of getNextFinalIndex, returning the only bucket
+// and base index which can produce the provided final index
+func (sswi *shardedStorageWithIndex) getBucketIDAndBaseIndexForFinalIndex(finalIndex uint32) (uint32, uint32) {
+	numBuckets := uint32(len(sswi.bucketHandlers))
+	halfIndex := finalIndex / indexMultiplier
+
+	return halfIndex % numBuckets, halfIndex / numBuckets
+}
+
+func (sswi *shardedStorageWithIndex) getSortedBucketIDs() []uint32 {
+	bucketIDs := make([]uint32, 0, len(sswi.bucketHandlers))
+	for bucketID := range sswi.bucketHandlers {
+		bucketIDs = append(bucketIDs, bucketID)
+	}
+
+	sort.Slice(bucketIDs, func(i, j int) bool {
+		return bucketIDs[i] < bucketIDs[j]
+	})
+
+	return bucketIDs
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (sswi *shardedStorageWithIndex) IsInterfaceNil() bool {
 	return sswi == nil
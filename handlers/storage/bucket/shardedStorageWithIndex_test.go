@@ -2,6 +2,7 @@ package bucket
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -259,6 +260,13 @@ func TestShardedStorageWithIndex_Put(t *testing.T) {
 	t.Run("should work", testPut(true))
 }
 
+func TestShardedStorageWithIndex_Remove(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid address", testRemove(false))
+	t.Run("should work", testRemove(true))
+}
+
 func TestShardedStorageWithIndex_Close(t *testing.T) {
 	t.Parallel()
 
@@ -392,6 +400,185 @@ func TestShardedStorageWithIndex_Count(t *testing.T) {
 	})
 }
 
+func TestShardedStorageWithIndex_ReserveIndex(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing bucket should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := ArgShardedStorageWithIndex{
+			BucketIDProvider: &testscommon.BucketIDProviderStub{},
+			BucketHandlers: map[uint32]core.IndexHandler{
+				0: &testscommon.BucketIndexHandlerStub{},
+				2: &testscommon.BucketIndexHandlerStub{},
+			},
+		}
+		sswi, _ := NewShardedStorageWithIndex(args)
+		err := sswi.ReserveIndex(sswi.getNextFinalIndex(3, 1))
+		assert.True(t, errors.Is(err, core.ErrInvalidBucketID))
+	})
+	t.Run("should reserve the base index in the bucket producing the index", func(t *testing.T) {
+		t.Parallel()
+
+		providedBucketID := uint32(1)
+		reservedIndex := uint32(0)
+		bucketHandlers := map[uint32]core.IndexHandler{
+			0: &testscommon.BucketIndexHandlerStub{
+				ReserveBucketIndexCalled: func(index uint32) error {
+					assert.Fail(t, "should have not been called")
+					return nil
+				},
+			},
+			1: &testscommon.BucketIndexHandlerStub{
+				ReserveBucketIndexCalled: func(index uint32) error {
+					reservedIndex = index
+					return nil
+				},
+			},
+			2: &testscommon.BucketIndexHandlerStub{
+				ReserveBucketIndexCalled: func(index uint32) error {
+					assert.Fail(t, "should have not been called")
+					return nil
+				},
+			},
+		}
+		args := ArgShardedStorageWithIndex{
+			BucketIDProvider: &testscommon.BucketIDProviderStub{},
+			BucketHandlers:   bucketHandlers,
+		}
+		sswi, _ := NewShardedStorageWithIndex(args)
+
+		baseIndex := uint32(7)
+		err := sswi.ReserveIndex(sswi.getNextFinalIndex(baseIndex, providedBucketID))
+		assert.Nil(t, err)
+		assert.Equal(t, baseIndex, reservedIndex)
+	})
+	t.Run("indexes from another layout should not be allocated again", func(t *testing.T) {
+		t.Parallel()
+
+		for _, numBuckets := range []uint32{1, 2, 3, 4, 7} {
+			bucketIDProvider, _ := NewBucketIDProvider(numBuckets)
+			bucketHandlers := make(map[uint32]core.IndexHandler, numBuckets)
+			for i := uint32(0); i < numBuckets; i++ {
+				bucketHandlers[i], _ = NewBucketIndexHandler(testscommon.NewStorerMock())
+			}
+			sswi, _ := NewShardedStorageWithIndex(ArgShardedStorageWithIndex{
+				BucketIDProvider: bucketIDProvider,
+				BucketHandlers:   bucketHandlers,
+			})
+
+			usedIndexes := make(map[uint32]struct{})
+			for i := uint32(0); i < 50; i++ {
+				migratedIndex := indexMultiplier * (i*5 + 3)
+				assert.Nil(t, sswi.ReserveIndex(migratedIndex))
+				usedIndexes[migratedIndex] = struct{}{}
+			}
+
+			for i := 0; i < 200; i++ {
+				newIndex, err := sswi.AllocateIndex([]byte(fmt.Sprintf("address %d", i)))
+				assert.Nil(t, err)
+				_, isUsed := usedIndexes[newIndex]
+				assert.False(t, isUsed, "buckets %d, index %d allocated again", numBuckets, newIndex)
+			}
+		}
+	})
+}
+
+func TestShardedStorageWithIndex_RangeKeys(t *testing.T) {
+	t.Parallel()
+
+	createBucketHandler := func(keys ...string) core.IndexHandler {
+		return &testscommon.BucketIndexHandlerStub{
+			RangeKeysCalled: func(ctx context.Context, handler func(key []byte, val []byte) bool) error {
+				for _, key := range keys {
+					if !handler([]byte(key), []byte("data_"+key)) {
+						return nil
+					}
+				}
+				return nil
+			},
+		}
+	}
+
+	t.Run("nil handler should error", func(t *testing.T) {
+		t.Parallel()
+
+		sswi, _ := NewShardedStorageWithIndex(ArgShardedStorageWithIndex{
+			BucketIDProvider: &testscommon.BucketIDProviderStub{},
+			BucketHandlers: map[uint32]core.IndexHandler{
+				0: createBucketHandler("key0"),
+			},
+		})
+
+		err := sswi.RangeKeys(context.Background(), nil)
+		assert.Equal(t, core.ErrNilRangeHandler, err)
+	})
+	t.Run("bucket returns error should error", func(t *testing.T) {
+		t.Parallel()
+
+		bucketHandlers := map[uint32]core.IndexHandler{
+			0: createBucketHandler("key0"),
+			1: &testscommon.BucketIndexHandlerStub{
+				RangeKeysCalled: func(ctx context.Context, handler func(key []byte, val []byte) bool) error {
+					return expectedErr
+				},
+			},
+		}
+		sswi, _ := NewShardedStorageWithIndex(ArgShardedStorageWithIndex{
+			BucketIDProvider: &testscommon.BucketIDProviderStub{},
+			BucketHandlers:   bucketHandlers,
+		})
+
+		err := sswi.RangeKeys(context.Background(), func(key []byte, val []byte) bool {
+			return true
+		})
+		assert.Equal(t, expectedErr, err)
+	})
+	t.Run("should iterate the buckets in order", func(t *testing.T) {
+		t.Parallel()
+
+		bucketHandlers := map[uint32]core.IndexHandler{
+			2: createBucketHandler("key2"),
+			0: createBucketHandler("key0", "key00"),
+			1: createBucketHandler(),
+			3: createBucketHandler("key3"),
+		}
+		sswi, _ := NewShardedStorageWithIndex(ArgShardedStorageWithIndex{
+			BucketIDProvider: &testscommon.BucketIDProviderStub{},
+			BucketHandlers:   bucketHandlers,
+		})
+
+		keys := make([]string, 0)
+		err := sswi.RangeKeys(context.Background(), func(key []byte, val []byte) bool {
+			assert.Equal(t, "data_"+string(key), string(val))
+			keys = append(keys, string(key))
+			return true
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"key0", "key00", "key2", "key3"}, keys)
+	})
+	t.Run("should stop when the handler returns false", func(t *testing.T) {
+		t.Parallel()
+
+		bucketHandlers := map[uint32]core.IndexHandler{
+			0: createBucketHandler("key0", "key00"),
+			1: createBucketHandler("key1"),
+		}
+		sswi, _ := NewShardedStorageWithIndex(ArgShardedStorageWithIndex{
+			BucketIDProvider: &testscommon.BucketIDProviderStub{},
+			BucketHandlers:   bucketHandlers,
+		})
+
+		keys := make([]string, 0)
+		err := sswi.RangeKeys(context.Background(), func(key []byte, val []byte) bool {
+			keys = append(keys, string(key))
+			return len(keys) < 2
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"key0", "key00"}, keys)
+	})
+}
+
 func testGetBucketIDAndBaseIndex(shouldWork bool) func(t *testing.T) {
 	return func(t *testing.T) {
 		t.Parallel()
@@ -573,3 +760,46 @@ func testPut(shouldWork bool) func(t *testing.T) {
 		}
 	}
 }
+
+func testRemove(shouldWork bool) func(t *testing.T) {
+	return func(t *testing.T) {
+		t.Parallel()
+
+		providedAddr := []byte("addr")
+		providedIdx := uint32(1)
+		provider := &testscommon.BucketIDProviderStub{
+			GetBucketForAddressCalled: func(address []byte) uint32 {
+				assert.Equal(t, providedAddr, address)
+				return providedIdx
+			},
+		}
+		wasCalled := false
+		key := providedIdx
+		if !shouldWork {
+			key = providedIdx + 1
+		}
+		bucketHandlers := map[uint32]core.IndexHandler{
+			key: &testscommon.BucketIndexHandlerStub{
+				RemoveCalled: func(key []byte) error {
+					assert.Equal(t, providedAddr, key)
+					wasCalled = true
+					return nil
+				},
+			},
+		}
+		args := ArgShardedStorageWithIndex{
+			BucketIDProvider: provider,
+			BucketHandlers:   bucketHandlers,
+		}
+		sswi, _ := NewShardedStorageWithIndex(args)
+		assert.NotNil(t, sswi)
+
+		if shouldWork {
+			assert.Nil(t, sswi.Remove(providedAddr))
+			assert.True(t, wasCalled)
+		} else {
+			assert.True(t, errors.Is(sswi.Remove(providedAddr), core.ErrInvalidBucketID))
+			assert.False(t, wasCalled)
+		}
+	}
+}
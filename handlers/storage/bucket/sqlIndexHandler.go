@@ -0,0 +1,103 @@
+package bucket
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/sqldb"
+)
+
+type sqlIndexHandler struct {
+	usersTable  sqldb.TableID
+	sqldbClient sqldb.SQLDBClient
+}
+
+// NewSQLIndexHandler returns a new instance of a sql index handler
+func NewSQLIndexHandler(sqlClient sqldb.SQLDBClient, tableID sqldb.TableID) (*sqlIndexHandler, error) {
+	if check.IfNil(sqlClient) {
+		return nil, core.ErrNilSQLDBClient
+	}
+	if tableID == "" {
+		return nil, fmt.Errorf("%w: empty table name", core.ErrInvalidValue)
+	}
+
+	handler := &sqlIndexHandler{
+		sqldbClient: sqlClient,
+		usersTable:  tableID,
+	}
+
+	err := handler.sqldbClient.PutIndexIfNotExists(handler.usersTable, []byte(lastIndexKey), initialIndexValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler, nil
+}
+
+// AllocateBucketIndex allocates a new index and returns it
+func (handler *sqlIndexHandler) AllocateBucketIndex() (uint32, error) {
+	return handler.sqldbClient.IncrementIndex(handler.usersTable, []byte(lastIndexKey))
+}
+
+// ReserveBucketIndex marks all the indexes up to the provided one as allocated
+func (handler *sqlIndexHandler) ReserveBucketIndex(index uint32) error {
+	return handler.sqldbClient.PutIndexIfGreater(handler.usersTable, []byte(lastIndexKey), index)
+}
+
+// Put adds data to storer
+func (handler *sqlIndexHandler) Put(key, data []byte) error {
+	return handler.sqldbClient.Put(handler.usersTable, key, data)
+}
+
+// Remove removes the key from the table, without releasing any index
+func (handler *sqlIndexHandler) Remove(key []byte) error {
+	return handler.sqldbClient.Remove(handler.usersTable, key)
+}
+
+// PutIfNotExists adds data to storer only if the key does not exist yet. It returns true if the data was added
+func (handler *sqlIndexHandler) PutIfNotExists(key, data []byte) (bool, error) {
+	return handler.sqldbClient.PutIfNotExists(handler.usersTable, key, data)
+}
+
+// RemoveIfEqual removes the key from the table only if it still holds the provided data
+func (handler *sqlIndexHandler) RemoveIfEqual(key, data []byte) error {
+	return handler.sqldbClient.RemoveIfEqual(handler.usersTable, key, data)
+}
+
+// Get returns the value for the key from storer
+func (handler *sqlIndexHandler) Get(key []byte) ([]byte, error) {
+	return handler.sqldbClient.Get(handler.usersTable, key)
+}
+
+// Has returns true if the key exists in storer
+func (handler *sqlIndexHandler) Has(key []byte) error {
+	return handler.sqldbClient.Has(handler.usersTable, key)
+}
+
+// GetLastIndex returns the last index that was allocated
+func (handler *sqlIndexHandler) GetLastIndex() (uint32, error) {
+	return handler.sqldbClient.GetIndex(handler.usersTable, []byte(lastIndexKey))
+}
+
+// RangeKeys calls the provided handler for each key-value pair of the table, until the handler
+// returns false or the context is done. The last allocated index is not a key-value pair, so it is never visited
+func (handler *sqlIndexHandler) RangeKeys(ctx context.Context, rangeHandler func(key []byte, val []byte) bool) error {
+	if rangeHandler == nil {
+		return core.ErrNilRangeHandler
+	}
+
+	return handler.sqldbClient.RangeKeys(ctx, handler.usersTable, rangeHandler)
+}
+
+// Close closes the sql db client
+func (handler *sqlIndexHandler) Close() error {
+	return handler.sqldbClient.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (handler *sqlIndexHandler) IsInterfaceNil() bool {
+	return handler == nil
+}
@@ -0,0 +1,179 @@
+package bucket
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/sqldb"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+func TestNewSQLIndexHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil sql client should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler, err := NewSQLIndexHandler(nil, "tableName")
+		assert.Equal(t, core.ErrNilSQLDBClient, err)
+		assert.Nil(t, handler)
+	})
+	t.Run("empty table name string, should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler, err := NewSQLIndexHandler(&testscommon.SQLDBClientStub{}, "")
+		assert.True(t, errors.Is(err, core.ErrInvalidValue))
+		assert.Nil(t, handler)
+	})
+	t.Run("put lastIndexKey fails", func(t *testing.T) {
+		t.Parallel()
+
+		handler, err := NewSQLIndexHandler(&testscommon.SQLDBClientStub{
+			PutIndexIfNotExistsCalled: func(table sqldb.TableID, key []byte, index uint32) error {
+				return expectedErr
+			},
+		}, "tableName")
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, handler)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		wasCalled := false
+		handler, err := NewSQLIndexHandler(&testscommon.SQLDBClientStub{
+			PutIndexIfNotExistsCalled: func(table sqldb.TableID, key []byte, index uint32) error {
+				assert.Equal(t, sqldb.TableID("tableName"), table)
+				assert.Equal(t, []byte(lastIndexKey), key)
+				assert.Equal(t, uint32(initialIndexValue), index)
+				wasCalled = true
+				return nil
+			},
+		}, "tableName")
+		assert.Nil(t, err)
+		assert.False(t, handler.IsInterfaceNil())
+		assert.True(t, wasCalled)
+	})
+}
+
+func TestSQLIndexHandler_IsInterfaceNil(t *testing.T) {
+	t.Parallel()
+
+	var handler *sqlIndexHandler
+	assert.True(t, handler.IsInterfaceNil())
+}
+
+func TestSQLIndexHandler_ReserveBucketIndex(t *testing.T) {
+	t.Parallel()
+
+	wasCalled := false
+	handler, _ := NewSQLIndexHandler(&testscommon.SQLDBClientStub{
+		PutIndexIfGreaterCalled: func(table sqldb.TableID, key []byte, index uint32) error {
+			assert.Equal(t, sqldb.TableID("tableName"), table)
+			assert.Equal(t, []byte(lastIndexKey), key)
+			assert.Equal(t, uint32(5), index)
+			wasCalled = true
+			return nil
+		},
+	}, "tableName")
+
+	err := handler.ReserveBucketIndex(5)
+	assert.Nil(t, err)
+	assert.True(t, wasCalled)
+}
+
+func TestSQLIndexHandler_RangeKeys(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil handler should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewSQLIndexHandler(&testscommon.SQLDBClientStub{
+			RangeKeysCalled: func(ctx context.Context, table sqldb.TableID, handler func(key []byte, val []byte) bool) error {
+				assert.Fail(t, "should have not been called")
+				return nil
+			},
+		}, "tableName")
+		err := handler.RangeKeys(context.Background(), nil)
+		assert.Equal(t, core.ErrNilRangeHandler, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewSQLIndexHandler(&testscommon.SQLDBClientStub{
+			RangeKeysCalled: func(ctx context.Context, table sqldb.TableID, handler func(key []byte, val []byte) bool) error {
+				assert.Equal(t, sqldb.TableID("tableName"), table)
+				if !handler([]byte("key1"), []byte("data1")) {
+					return nil
+				}
+				handler([]byte("key2"), []byte("data2"))
+				return expectedErr
+			},
+		}, "tableName")
+
+		entries := make(map[string]string)
+		err := handler.RangeKeys(context.Background(), func(key []byte, val []byte) bool {
+			entries[string(key)] = string(val)
+			return true
+		})
+		assert.Equal(t, expectedErr, err)
+		assert.Equal(t, map[string]string{"key1": "data1", "key2": "data2"}, entries)
+	})
+}
+
+func TestSQLIndexHandler_Operations(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		r := recover()
+		if r != nil {
+			assert.Fail(t, "should have not panicked")
+		}
+	}()
+
+	handler, _ := NewSQLIndexHandler(&testscommon.SQLDBClientStub{
+		IncrementIndexCalled: func(table sqldb.TableID, key []byte) (uint32, error) {
+			return 1, nil
+		},
+	}, "tableName")
+
+	numCalls := 10000
+	var wg sync.WaitGroup
+	wg.Add(numCalls)
+	for i := 0; i < numCalls; i++ {
+		go func(idx int) {
+			switch idx % 9 {
+			case 0:
+				_, err := handler.AllocateBucketIndex()
+				assert.Nil(t, err)
+			case 1:
+				assert.Nil(t, handler.Put([]byte("key"), []byte("data")))
+			case 2:
+				_, err := handler.Get([]byte("key"))
+				assert.Nil(t, err)
+			case 3:
+				assert.Nil(t, handler.Has([]byte("key")))
+			case 4:
+				assert.Nil(t, handler.Close())
+			case 5:
+				_, err := handler.GetLastIndex()
+				assert.Nil(t, err)
+			case 6:
+				assert.Nil(t, handler.Remove([]byte("key")))
+			case 7:
+				_, err := handler.PutIfNotExists([]byte("key"), []byte("data"))
+				assert.Nil(t, err)
+			case 8:
+				assert.Nil(t, handler.RemoveIfEqual([]byte("key"), []byte("data")))
+			default:
+				assert.Fail(t, "should not hit default")
+			}
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+}
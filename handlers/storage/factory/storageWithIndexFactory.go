@@ -7,8 +7,10 @@ import (
 	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage/bucket"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/mongodb"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/sqldb"
 )
 
 type storageWithIndexFactory struct {
@@ -37,53 +39,196 @@ func (ssf *storageWithIndexFactory) Create() (core.StorageWithIndex, error) {
 		return ssf.createLocalDB()
 	case core.MongoDB:
 		return ssf.createMongoDB()
+	case core.PostgresDB:
+		return ssf.createSQLDB(sqldb.PostgresDialect, ssf.externalCfg.Postgres)
+	case core.SQLiteDB:
+		return ssf.createSQLDB(sqldb.SQLiteDialect, ssf.cfg.SQLite)
+	case core.MemoryDB:
+		return ssf.createMemoryDB()
 	default:
 		return nil, handlers.ErrInvalidConfig
 	}
 }
 
 func (ssf *storageWithIndexFactory) createMongoDB() (core.StorageWithIndex, error) {
-	client, err := mongodb.CreateMongoDBClient(ssf.externalCfg.MongoDB, ssf.metricsHandler)
+	mongoDBCfg := ssf.externalCfg.MongoDB
+	if !mongoDBCfg.Resharding.Enabled {
+		client, err := mongodb.CreateMongoDBClient(mongoDBCfg, ssf.metricsHandler)
+		if err != nil {
+			return nil, err
+		}
+
+		return createShardedMongoDB(client)
+	}
+
+	previousMongoDBCfg := mongoDBCfg
+	previousMongoDBCfg.UsersCollectionsPrefix = mongoDBCfg.Resharding.PreviousUsersCollectionsPrefix
+	previousMongoDBCfg.NumUsersCollections = mongoDBCfg.Resharding.PreviousNumUsersCollections
+	if getUsersCollectionsPrefix(previousMongoDBCfg) == getUsersCollectionsPrefix(mongoDBCfg) {
+		return nil, fmt.Errorf("%w, the previous users collections prefix should be different than the current one while resharding",
+			handlers.ErrInvalidConfig)
+	}
+
+	client, err := mongodb.CreateMongoDBClient(mongoDBCfg, ssf.metricsHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	currentLayout, err := createMongoDBLayout(client)
 	if err != nil {
 		return nil, err
 	}
 
-	return createShardedMongoDB(client)
+	previousClient, err := mongodb.CreateMongoDBClient(previousMongoDBCfg, ssf.metricsHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	previousLayout, err := createMongoDBLayout(previousClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return bucket.NewReshardingStorageWithIndex(bucket.ArgReshardingStorageWithIndex{
+		PreviousLayout: previousLayout,
+		CurrentLayout:  currentLayout,
+	})
+}
+
+func getUsersCollectionsPrefix(cfg config.MongoDBConfig) string {
+	if cfg.UsersCollectionsPrefix == "" {
+		return string(mongodb.UsersCollectionID)
+	}
+
+	return cfg.UsersCollectionsPrefix
 }
 
 func createShardedMongoDB(client mongodb.MongoDBClient) (core.StorageWithIndex, error) {
+	argsShardedStorageWithIndex, err := createMongoDBLayout(client)
+	if err != nil {
+		return nil, err
+	}
+
+	return bucket.NewShardedStorageWithIndex(argsShardedStorageWithIndex)
+}
+
+func createMongoDBLayout(client mongodb.MongoDBClient) (bucket.ArgShardedStorageWithIndex, error) {
 	collectionsIDs := client.GetAllCollectionsIDs()
 	numOfBuckets := uint32(len(collectionsIDs))
 
 	bucketIDProvider, err := bucket.NewBucketIDProvider(numOfBuckets)
 	if err != nil {
-		return nil, err
+		return bucket.ArgShardedStorageWithIndex{}, err
 	}
 
 	indexHandlers := make(map[uint32]core.IndexHandler, numOfBuckets)
 	for i, collName := range collectionsIDs {
 		indexHandlers[uint32(i)], err = bucket.NewMongoDBIndexHandler(client, collName)
 		if err != nil {
-			return nil, err
+			return bucket.ArgShardedStorageWithIndex{}, err
 		}
 	}
 
-	argsShardedStorageWithIndex := bucket.ArgShardedStorageWithIndex{
+	return bucket.ArgShardedStorageWithIndex{
 		BucketIDProvider: bucketIDProvider,
 		BucketHandlers:   indexHandlers,
+	}, nil
+}
+
+func (ssf *storageWithIndexFactory) createSQLDB(dialect sqldb.Dialect, sqlDBCfg config.SQLDBConfig) (core.StorageWithIndex, error) {
+	client, err := sqldb.CreateSQLDBClient(dialect, sqlDBCfg, ssf.metricsHandler)
+	if err != nil {
+		return nil, err
 	}
 
-	return bucket.NewShardedStorageWithIndex(argsShardedStorageWithIndex)
+	sqlDBLayout, err := createSQLDBLayout(client)
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	return bucket.NewShardedStorageWithIndex(sqlDBLayout)
+}
+
+func createSQLDBLayout(client sqldb.SQLDBClient) (bucket.ArgShardedStorageWithIndex, error) {
+	tablesIDs := client.GetAllTablesIDs()
+	numOfBuckets := uint32(len(tablesIDs))
+
+	bucketIDProvider, err := bucket.NewBucketIDProvider(numOfBuckets)
+	if err != nil {
+		return bucket.ArgShardedStorageWithIndex{}, err
+	}
+
+	indexHandlers := make(map[uint32]core.IndexHandler, numOfBuckets)
+	for i, tableName := range tablesIDs {
+		indexHandlers[uint32(i)], err = bucket.NewSQLIndexHandler(client, tableName)
+		if err != nil {
+			return bucket.ArgShardedStorageWithIndex{}, err
+		}
+	}
+
+	return bucket.ArgShardedStorageWithIndex{
+		BucketIDProvider: bucketIDProvider,
+		BucketHandlers:   indexHandlers,
+	}, nil
 }
 
 func (ssf *storageWithIndexFactory) createLocalDB() (core.StorageWithIndex, error) {
-	numbOfBuckets := ssf.cfg.ShardedStorage.NumberOfBuckets
-	bucketIDProvider, err := bucket.NewBucketIDProvider(numbOfBuckets)
+	shardedStorageCfg := ssf.cfg.ShardedStorage
+	reshardingCfg := shardedStorageCfg.Resharding
+	if reshardingCfg.Enabled && reshardingCfg.PreviousUsers.DB.FilePath == shardedStorageCfg.Users.DB.FilePath {
+		return nil, fmt.Errorf("%w, the previous users DB file path should be different than the current one while resharding",
+			handlers.ErrInvalidConfig)
+	}
+
+	currentLayout, err := createLocalDBLayout(shardedStorageCfg.NumberOfBuckets, shardedStorageCfg.Users)
 	if err != nil {
 		return nil, err
 	}
 
-	localDBCfg := ssf.cfg.ShardedStorage.Users
+	if !reshardingCfg.Enabled {
+		return bucket.NewShardedStorageWithIndex(currentLayout)
+	}
+
+	previousLayout, err := createLocalDBLayout(reshardingCfg.PreviousNumberOfBuckets, reshardingCfg.PreviousUsers)
+	if err != nil {
+		return nil, err
+	}
+
+	return bucket.NewReshardingStorageWithIndex(bucket.ArgReshardingStorageWithIndex{
+		PreviousLayout: previousLayout,
+		CurrentLayout:  currentLayout,
+	})
+}
+
+// createMemoryDB uses the local DB buckets layout, keeping the buckets in memory, so all the users are lost on restart
+func (ssf *storageWithIndexFactory) createMemoryDB() (core.StorageWithIndex, error) {
+	numOfBuckets := ssf.cfg.ShardedStorage.NumberOfBuckets
+	bucketIDProvider, err := bucket.NewBucketIDProvider(numOfBuckets)
+	if err != nil {
+		return nil, err
+	}
+
+	bucketIndexHandlers := make(map[uint32]core.IndexHandler, numOfBuckets)
+	for i := uint32(0); i < numOfBuckets; i++ {
+		bucketIndexHandlers[i], err = bucket.NewBucketIndexHandler(storage.NewMemoryStorer())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bucket.NewShardedStorageWithIndex(bucket.ArgShardedStorageWithIndex{
+		BucketIDProvider: bucketIDProvider,
+		BucketHandlers:   bucketIndexHandlers,
+	})
+}
+
+func createLocalDBLayout(numbOfBuckets uint32, localDBCfg config.StorageConfig) (bucket.ArgShardedStorageWithIndex, error) {
+	bucketIDProvider, err := bucket.NewBucketIDProvider(numbOfBuckets)
+	if err != nil {
+		return bucket.ArgShardedStorageWithIndex{}, err
+	}
+
 	bucketIndexHandlers := make(map[uint32]core.IndexHandler, numbOfBuckets)
 	var bucketStorer core.Storer
 	for i := uint32(0); i < numbOfBuckets; i++ {
@@ -94,21 +239,19 @@ func (ssf *storageWithIndexFactory) createLocalDB() (core.StorageWithIndex, erro
 
 		bucketStorer, err = storageFactory.NewStorageUnitFromConf(cacheCfg, dbCfg)
 		if err != nil {
-			return nil, err
+			return bucket.ArgShardedStorageWithIndex{}, err
 		}
 
 		bucketIndexHandlers[i], err = bucket.NewBucketIndexHandler(bucketStorer)
 		if err != nil {
-			return nil, err
+			return bucket.ArgShardedStorageWithIndex{}, err
 		}
 	}
 
-	argsShardedStorageWithIndex := bucket.ArgShardedStorageWithIndex{
+	return bucket.ArgShardedStorageWithIndex{
 		BucketIDProvider: bucketIDProvider,
 		BucketHandlers:   bucketIndexHandlers,
-	}
-
-	return bucket.NewShardedStorageWithIndex(argsShardedStorageWithIndex)
+	}, nil
 }
 
 // IsInterfaceNil returns true if there is no value under the interface
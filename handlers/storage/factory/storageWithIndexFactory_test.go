@@ -1,8 +1,10 @@
 package factory
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
@@ -119,6 +121,99 @@ func TestNewShardedStorageFactory_Create(t *testing.T) {
 		assert.Equal(t, "*bucket.shardedStorageWithIndex", fmt.Sprintf("%T", shardedStorageInstance))
 		removeDBs(t, cfg)
 	})
+	t.Run("resharding local storage with the same file path should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.Config{
+			General: config.GeneralConfig{
+				DBType: core.LevelDB,
+			},
+			ShardedStorage: config.ShardedStorageConfig{
+				NumberOfBuckets: 4,
+				Users:           createUsersStorageConfig("UsersDBSamePath"),
+				Resharding: config.ShardedStorageReshardingConfig{
+					Enabled:                 true,
+					PreviousNumberOfBuckets: 2,
+					PreviousUsers:           createUsersStorageConfig("UsersDBSamePath"),
+				},
+			},
+		}
+		ssf := NewStorageWithIndexFactory(cfg, config.ExternalConfig{}, &testscommon.StatusMetricsStub{})
+		shardedStorageInstance, err := ssf.Create()
+		assert.True(t, errors.Is(err, handlers.ErrInvalidConfig))
+		assert.Nil(t, shardedStorageInstance)
+	})
+	t.Run("resharding mongoDB with the same users collections prefix should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.Config{
+			General: config.GeneralConfig{
+				DBType: core.MongoDB,
+			},
+		}
+		extCfg := config.ExternalConfig{
+			MongoDB: config.MongoDBConfig{
+				URI:                 "mongodb://localhost:27017",
+				DBName:              "dbName",
+				NumUsersCollections: 8,
+				Resharding: config.MongoDBReshardingConfig{
+					Enabled:                        true,
+					PreviousUsersCollectionsPrefix: "users",
+					PreviousNumUsersCollections:    4,
+				},
+			},
+		}
+		ssf := NewStorageWithIndexFactory(cfg, extCfg, &testscommon.StatusMetricsStub{})
+		shardedStorageInstance, err := ssf.Create()
+		assert.True(t, errors.Is(err, handlers.ErrInvalidConfig))
+		assert.Nil(t, shardedStorageInstance)
+	})
+	t.Run("should create resharding local storage", func(t *testing.T) {
+		t.Parallel()
+
+		previousCfg := config.Config{
+			General: config.GeneralConfig{
+				DBType: core.LevelDB,
+			},
+			ShardedStorage: config.ShardedStorageConfig{
+				NumberOfBuckets: 2,
+				Users:           createUsersStorageConfig("UsersDBPrevious"),
+			},
+		}
+		ssf := NewStorageWithIndexFactory(previousCfg, config.ExternalConfig{}, &testscommon.StatusMetricsStub{})
+		previousStorage, err := ssf.Create()
+		require.Nil(t, err)
+		_, err = previousStorage.AllocateIndex([]byte("address"))
+		require.Nil(t, err)
+		require.Nil(t, previousStorage.Put([]byte("address"), []byte("data")))
+		require.Nil(t, previousStorage.Close())
+
+		cfg := config.Config{
+			General: config.GeneralConfig{
+				DBType: core.LevelDB,
+			},
+			ShardedStorage: config.ShardedStorageConfig{
+				NumberOfBuckets: 4,
+				Users:           createUsersStorageConfig("UsersDBResharded"),
+				Resharding: config.ShardedStorageReshardingConfig{
+					Enabled:                 true,
+					PreviousNumberOfBuckets: previousCfg.ShardedStorage.NumberOfBuckets,
+					PreviousUsers:           previousCfg.ShardedStorage.Users,
+				},
+			},
+		}
+		ssf = NewStorageWithIndexFactory(cfg, config.ExternalConfig{}, &testscommon.StatusMetricsStub{})
+		shardedStorageInstance, err := ssf.Create()
+		require.Nil(t, err)
+		assert.Equal(t, "*bucket.reshardingStorageWithIndex", fmt.Sprintf("%T", shardedStorageInstance))
+
+		data, err := shardedStorageInstance.Get([]byte("address"))
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("data"), data)
+		assert.Nil(t, shardedStorageInstance.Close())
+		removeDBs(t, previousCfg)
+		removeDBs(t, cfg)
+	})
 
 	t.Run("real storage LevelDB, returns ErrKeyNotFound on non existing key", func(t *testing.T) {
 		t.Parallel()
@@ -165,6 +260,107 @@ func TestNewShardedStorageFactory_Create(t *testing.T) {
 		removeDBs(t, cfg)
 	})
 
+	t.Run("postgres with empty dsn should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.Config{
+			General: config.GeneralConfig{
+				DBType: core.PostgresDB,
+			},
+		}
+		extCfg := config.ExternalConfig{
+			Postgres: config.SQLDBConfig{
+				OperationTimeoutInSec: 10,
+				NumUsersTables:        4,
+			},
+		}
+		ssf := NewStorageWithIndexFactory(cfg, extCfg, &testscommon.StatusMetricsStub{})
+		shardedStorageInstance, err := ssf.Create()
+		assert.NotNil(t, err)
+		assert.Nil(t, shardedStorageInstance)
+	})
+
+	t.Run("real storage SQLite, should allocate indexes and return ErrKeyNotFound on non existing key", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.Config{
+			General: config.GeneralConfig{
+				DBType: core.SQLiteDB,
+			},
+			SQLite: config.SQLDBConfig{
+				DSN:                   fmt.Sprintf("file:%s?_busy_timeout=5000", filepath.Join(t.TempDir(), "users.sqlite")),
+				OperationTimeoutInSec: 10,
+				MaxOpenConnections:    1,
+				NumUsersTables:        4,
+			},
+		}
+		ssf := NewStorageWithIndexFactory(cfg, config.ExternalConfig{}, &testscommon.StatusMetricsStub{})
+		shardedStorageInstance, err := ssf.Create()
+		require.Nil(t, err)
+		assert.Equal(t, "*bucket.shardedStorageWithIndex", fmt.Sprintf("%T", shardedStorageInstance))
+
+		_, err = shardedStorageInstance.Get([]byte("key"))
+		assert.Equal(t, storage.ErrKeyNotFound, err)
+
+		firstIndex, err := shardedStorageInstance.AllocateIndex([]byte("key"))
+		require.Nil(t, err)
+		require.Nil(t, shardedStorageInstance.Put([]byte("key"), []byte("data")))
+		require.Nil(t, shardedStorageInstance.Close())
+
+		// the indexes and the data are kept after restart
+		shardedStorageInstance, err = ssf.Create()
+		require.Nil(t, err)
+		data, err := shardedStorageInstance.Get([]byte("key"))
+		require.Nil(t, err)
+		assert.Equal(t, []byte("data"), data)
+		secondIndex, err := shardedStorageInstance.AllocateIndex([]byte("key"))
+		require.Nil(t, err)
+		assert.Greater(t, secondIndex, firstIndex)
+		require.Nil(t, shardedStorageInstance.Close())
+	})
+
+	t.Run("memory storage, should not touch the local DB files and lose the data on restart", func(t *testing.T) {
+		t.Parallel()
+
+		filePath := filepath.Join(t.TempDir(), "MemoryUsersDB")
+		cfg := config.Config{
+			General: config.GeneralConfig{
+				DBType: core.MemoryDB,
+			},
+			ShardedStorage: config.ShardedStorageConfig{
+				NumberOfBuckets: 4,
+				Users:           createUsersStorageConfig(filePath),
+			},
+		}
+		ssf := NewStorageWithIndexFactory(cfg, config.ExternalConfig{}, &testscommon.StatusMetricsStub{})
+		shardedStorageInstance, err := ssf.Create()
+		require.Nil(t, err)
+		assert.Equal(t, "*bucket.shardedStorageWithIndex", fmt.Sprintf("%T", shardedStorageInstance))
+
+		_, err = shardedStorageInstance.Get([]byte("key"))
+		assert.Equal(t, storage.ErrKeyNotFound, err)
+
+		firstIndex, err := shardedStorageInstance.AllocateIndex([]byte("key"))
+		require.Nil(t, err)
+		secondIndex, err := shardedStorageInstance.AllocateIndex([]byte("key"))
+		require.Nil(t, err)
+		assert.Greater(t, secondIndex, firstIndex)
+		require.Nil(t, shardedStorageInstance.Put([]byte("key"), []byte("data")))
+		data, err := shardedStorageInstance.Get([]byte("key"))
+		require.Nil(t, err)
+		assert.Equal(t, []byte("data"), data)
+		require.Nil(t, shardedStorageInstance.Close())
+
+		_, err = os.Stat(fmt.Sprintf("%s_%d", filePath, 0))
+		assert.True(t, os.IsNotExist(err))
+
+		shardedStorageInstance, err = ssf.Create()
+		require.Nil(t, err)
+		_, err = shardedStorageInstance.Get([]byte("key"))
+		assert.Equal(t, storage.ErrKeyNotFound, err)
+		require.Nil(t, shardedStorageInstance.Close())
+	})
+
 	t.Run("mocked MongoDB client, returns ErrKeyNotFound on non existing key", func(t *testing.T) {
 		t.Parallel()
 
@@ -232,3 +428,21 @@ func removeDBs(t *testing.T, cfg config.Config) {
 		assert.Nil(t, os.RemoveAll(dirName))
 	}
 }
+
+func createUsersStorageConfig(filePath string) config.StorageConfig {
+	return config.StorageConfig{
+		Cache: common.CacheConfig{
+			Name:        "UsersCache",
+			Type:        "SizeLRU",
+			SizeInBytes: 104857600,
+			Capacity:    100000,
+		},
+		DB: common.DBConfig{
+			FilePath:          filePath,
+			Type:              "LvlDB",
+			BatchDelaySeconds: 1,
+			MaxBatchSize:      1000,
+			MaxOpenFiles:      10,
+		},
+	}
+}
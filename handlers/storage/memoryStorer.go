@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"errors"
+	"sync"
+)
+
+type memoryStorer struct {
+	mut  sync.RWMutex
+	data map[string][]byte
+}
+
+// NewMemoryStorer returns a new storer keeping the data in memory, which is lost on close
+func NewMemoryStorer() *memoryStorer {
+	return &memoryStorer{
+		data: make(map[string][]byte),
+	}
+}
+
+// Put adds a copy of the data for the provided key
+func (ms *memoryStorer) Put(key, data []byte) error {
+	ms.mut.Lock()
+	defer ms.mut.Unlock()
+
+	ms.data[string(key)] = copyBytes(data)
+
+	return nil
+}
+
+// Get returns a copy of the data stored for the provided key
+func (ms *memoryStorer) Get(key []byte) ([]byte, error) {
+	ms.mut.RLock()
+	defer ms.mut.RUnlock()
+
+	data, found := ms.data[string(key)]
+	if !found {
+		return nil, ErrKeyNotFound
+	}
+
+	return copyBytes(data), nil
+}
+
+// Has returns nil if the provided key exists
+func (ms *memoryStorer) Has(key []byte) error {
+	ms.mut.RLock()
+	defer ms.mut.RUnlock()
+
+	_, found := ms.data[string(key)]
+	if !found {
+		return ErrKeyNotFound
+	}
+
+	return nil
+}
+
+// SearchFirst is not supported
+func (ms *memoryStorer) SearchFirst(_ []byte) ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
+// Remove removes the provided key
+func (ms *memoryStorer) Remove(key []byte) error {
+	ms.mut.Lock()
+	defer ms.mut.Unlock()
+
+	delete(ms.data, string(key))
+
+	return nil
+}
+
+// RangeKeys calls the handler for a snapshot of the stored key-value pairs, so the handler can use the storer
+func (ms *memoryStorer) RangeKeys(handler func(key []byte, val []byte) bool) {
+	if handler == nil {
+		return
+	}
+
+	ms.mut.RLock()
+	entries := make(map[string][]byte, len(ms.data))
+	for key, data := range ms.data {
+		entries[key] = data
+	}
+	ms.mut.RUnlock()
+
+	for key, data := range entries {
+		if !handler([]byte(key), copyBytes(data)) {
+			return
+		}
+	}
+}
+
+// ClearCache does nothing, as there is no cache
+func (ms *memoryStorer) ClearCache() {
+}
+
+// Close removes all the stored data
+func (ms *memoryStorer) Close() error {
+	ms.mut.Lock()
+	defer ms.mut.Unlock()
+
+	ms.data = make(map[string][]byte)
+
+	return nil
+}
+
+func copyBytes(data []byte) []byte {
+	if data == nil {
+		return nil
+	}
+
+	return append(make([]byte, 0, len(data)), data...)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ms *memoryStorer) IsInterfaceNil() bool {
+	return ms == nil
+}
@@ -0,0 +1,55 @@
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage"
+)
+
+func TestMemoryStorer(t *testing.T) {
+	t.Parallel()
+
+	storer := storage.NewMemoryStorer()
+	require.False(t, storer.IsInterfaceNil())
+
+	_, err := storer.Get([]byte("key"))
+	require.Equal(t, storage.ErrKeyNotFound, err)
+	require.Equal(t, storage.ErrKeyNotFound, storer.Has([]byte("key")))
+
+	data := []byte("data")
+	require.Nil(t, storer.Put([]byte("key"), data))
+	data[0] = 'D'
+	require.Nil(t, storer.Has([]byte("key")))
+	storedData, err := storer.Get([]byte("key"))
+	require.Nil(t, err)
+	require.Equal(t, []byte("data"), storedData)
+	storedData[0] = 'D'
+	storedData, err = storer.Get([]byte("key"))
+	require.Nil(t, err)
+	require.Equal(t, []byte("data"), storedData)
+
+	require.Nil(t, storer.Put([]byte("key2"), []byte("data2")))
+	entries := make(map[string]string)
+	storer.RangeKeys(func(key []byte, val []byte) bool {
+		entries[string(key)] = string(val)
+
+		// the handler can use the storer
+		assert.Nil(t, storer.Remove(key))
+		return true
+	})
+	require.Equal(t, map[string]string{"key": "data", "key2": "data2"}, entries)
+	require.Equal(t, storage.ErrKeyNotFound, storer.Has([]byte("key")))
+	storer.RangeKeys(nil)
+
+	_, err = storer.SearchFirst([]byte("key"))
+	require.NotNil(t, err)
+
+	require.Nil(t, storer.Put([]byte("key"), []byte("data")))
+	storer.ClearCache()
+	require.Nil(t, storer.Has([]byte("key")))
+	require.Nil(t, storer.Close())
+	require.Equal(t, storage.ErrKeyNotFound, storer.Has([]byte("key")))
+}
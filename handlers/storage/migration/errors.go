@@ -0,0 +1,18 @@
+package migration
+
+import "errors"
+
+// ErrNilSourceStorage signals that a nil source storage was provided
+var ErrNilSourceStorage = errors.New("nil source storage")
+
+// ErrNilDestinationStorage signals that a nil destination storage was provided
+var ErrNilDestinationStorage = errors.New("nil destination storage")
+
+// ErrNilMarshaller signals that a nil marshaller was provided
+var ErrNilMarshaller = errors.New("nil marshaller")
+
+// ErrConflictingEntry signals that the destination already holds a different value for a migrated key
+var ErrConflictingEntry = errors.New("conflicting entry in destination storage")
+
+// ErrVerificationFailed signals that the destination does not hold the same data as the source
+var ErrVerificationFailed = errors.New("migration verification failed")
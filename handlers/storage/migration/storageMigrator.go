@@ -0,0 +1,175 @@
+package migration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+)
+
+var log = logger.GetOrCreate("migration")
+
+const logProgressInterval = 1000
+
+// ArgsStorageMigrator is the DTO used to create a new instance of storageMigrator
+type ArgsStorageMigrator struct {
+	Source             core.StorageWithIndex
+	Destination        core.StorageWithIndex
+	UserDataMarshaller core.Marshaller
+}
+
+// MigrationResult holds the outcome of a migration
+type MigrationResult struct {
+	ReservedIndexesUpTo uint32
+	NumSourceEntries    uint32
+	NumMigratedEntries  uint32
+	NumExistingEntries  uint32
+	NumVerifiedEntries  uint32
+}
+
+type storageMigrator struct {
+	source             core.StorageWithIndex
+	destination        core.StorageWithIndex
+	userDataMarshaller core.Marshaller
+}
+
+// NewStorageMigrator returns a new instance of storageMigrator, which copies all the users from one storage to another
+func NewStorageMigrator(args ArgsStorageMigrator) (*storageMigrator, error) {
+	if check.IfNil(args.Source) {
+		return nil, ErrNilSourceStorage
+	}
+	if check.IfNil(args.Destination) {
+		return nil, ErrNilDestinationStorage
+	}
+	if check.IfNil(args.UserDataMarshaller) {
+		return nil, ErrNilMarshaller
+	}
+
+	return &storageMigrator{
+		source:             args.Source,
+		destination:        args.Destination,
+		userDataMarshaller: args.UserDataMarshaller,
+	}, nil
+}
+
+// Migrate copies all the entries of the source into the destination, keeping the index of each user reserved
+// in the destination, so the guardian keys derived from it stay the same and are never handed out again.
+// Before copying, the destination reserves all the indexes up to the last one allocated by the source, as the
+// indexes of the users removed from the source are never handed out again either.
+// Entries already present in the destination with the same value are skipped, so an interrupted migration
+// can be resumed. After copying, each source entry is checked against the destination
+func (sm *storageMigrator) Migrate(ctx context.Context) (*MigrationResult, error) {
+	lastAllocatedIndex, err := sm.source.GetLastAllocatedIndex()
+	if err != nil {
+		return nil, fmt.Errorf("%w while reading the last allocated index of the source", err)
+	}
+
+	err = sm.destination.ReserveIndexesUpTo(lastAllocatedIndex)
+	if err != nil {
+		return nil, fmt.Errorf("%w while reserving the indexes of the source in the destination", err)
+	}
+
+	log.Info("reserved the indexes of the source in the destination", "reserved indexes up to", lastAllocatedIndex)
+
+	result := &MigrationResult{
+		ReservedIndexesUpTo: lastAllocatedIndex,
+	}
+
+	var migrationErr error
+	err = sm.source.RangeKeys(ctx, func(key []byte, val []byte) bool {
+		result.NumSourceEntries++
+		migrationErr = sm.migrateEntry(key, val, result)
+		if migrationErr != nil {
+			return false
+		}
+
+		if result.NumSourceEntries%logProgressInterval == 0 {
+			log.Info("migration in progress", "processed entries", result.NumSourceEntries)
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if migrationErr != nil {
+		return nil, migrationErr
+	}
+
+	err = sm.verify(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (sm *storageMigrator) migrateEntry(key []byte, val []byte, result *MigrationResult) error {
+	userInfo := &core.UserInfo{}
+	err := sm.userDataMarshaller.Unmarshal(userInfo, val)
+	if err != nil {
+		return fmt.Errorf("%w while unmarshalling the entry for key %x", err, key)
+	}
+
+	err = sm.destination.Has(key)
+	if err == nil {
+		existingVal, errGet := sm.destination.Get(key)
+		if errGet != nil {
+			return errGet
+		}
+		if !bytes.Equal(existingVal, val) {
+			return fmt.Errorf("%w for key %x", ErrConflictingEntry, key)
+		}
+
+		result.NumExistingEntries++
+	} else {
+		err = sm.destination.Put(key, val)
+		if err != nil {
+			return err
+		}
+
+		result.NumMigratedEntries++
+	}
+
+	return sm.destination.ReserveIndex(userInfo.Index)
+}
+
+func (sm *storageMigrator) verify(ctx context.Context, result *MigrationResult) error {
+	var verificationErr error
+	err := sm.source.RangeKeys(ctx, func(key []byte, val []byte) bool {
+		migratedVal, errGet := sm.destination.Get(key)
+		if errGet != nil {
+			verificationErr = fmt.Errorf("%w, key %x: %s", ErrVerificationFailed, key, errGet.Error())
+			return false
+		}
+		if !bytes.Equal(migratedVal, val) {
+			verificationErr = fmt.Errorf("%w, different value for key %x", ErrVerificationFailed, key)
+			return false
+		}
+
+		result.NumVerifiedEntries++
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if verificationErr != nil {
+		return verificationErr
+	}
+
+	numCopiedEntries := result.NumMigratedEntries + result.NumExistingEntries
+	if result.NumVerifiedEntries != result.NumSourceEntries || numCopiedEntries != result.NumSourceEntries {
+		return fmt.Errorf("%w, source entries %d, copied entries %d, verified entries %d",
+			ErrVerificationFailed, result.NumSourceEntries, numCopiedEntries, result.NumVerifiedEntries)
+	}
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (sm *storageMigrator) IsInterfaceNil() bool {
+	return sm == nil
+}
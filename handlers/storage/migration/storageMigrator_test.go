@@ -0,0 +1,341 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage/bucket"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+var expectedErr = errors.New("expected error")
+
+func createStorage(t *testing.T, numBuckets uint32) core.StorageWithIndex {
+	bucketIDProvider, err := bucket.NewBucketIDProvider(numBuckets)
+	require.Nil(t, err)
+
+	bucketHandlers := make(map[uint32]core.IndexHandler, numBuckets)
+	for i := uint32(0); i < numBuckets; i++ {
+		bucketHandlers[i], err = bucket.NewBucketIndexHandler(testscommon.NewStorerMock())
+		require.Nil(t, err)
+	}
+
+	storage, err := bucket.NewShardedStorageWithIndex(bucket.ArgShardedStorageWithIndex{
+		BucketIDProvider: bucketIDProvider,
+		BucketHandlers:   bucketHandlers,
+	})
+	require.Nil(t, err)
+
+	return storage
+}
+
+func registerUsers(t *testing.T, storage core.StorageWithIndex, numUsers int) map[string]uint32 {
+	marshaller := &marshal.GogoProtoMarshalizer{}
+	indexes := make(map[string]uint32, numUsers)
+	for i := 0; i < numUsers; i++ {
+		address := []byte(fmt.Sprintf("user address %d", i))
+		index, err := storage.AllocateIndex(address)
+		require.Nil(t, err)
+
+		userInfoBytes, err := marshaller.Marshal(&core.UserInfo{Index: index})
+		require.Nil(t, err)
+		require.Nil(t, storage.Put(address, userInfoBytes))
+
+		indexes[string(address)] = index
+	}
+
+	return indexes
+}
+
+func createMockArgs(t *testing.T) ArgsStorageMigrator {
+	return ArgsStorageMigrator{
+		Source:             createStorage(t, 4),
+		Destination:        createStorage(t, 2),
+		UserDataMarshaller: &marshal.GogoProtoMarshalizer{},
+	}
+}
+
+func TestNewStorageMigrator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil source should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs(t)
+		args.Source = nil
+		sm, err := NewStorageMigrator(args)
+		require.Nil(t, sm)
+		require.Equal(t, ErrNilSourceStorage, err)
+	})
+	t.Run("nil destination should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs(t)
+		args.Destination = nil
+		sm, err := NewStorageMigrator(args)
+		require.Nil(t, sm)
+		require.Equal(t, ErrNilDestinationStorage, err)
+	})
+	t.Run("nil marshaller should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs(t)
+		args.UserDataMarshaller = nil
+		sm, err := NewStorageMigrator(args)
+		require.Nil(t, sm)
+		require.Equal(t, ErrNilMarshaller, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		sm, err := NewStorageMigrator(createMockArgs(t))
+		require.NotNil(t, sm)
+		require.Nil(t, err)
+	})
+}
+
+func TestStorageMigrator_Migrate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("source iteration fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs(t)
+		args.Source = &testscommon.ShardedStorageWithIndexStub{
+			RangeKeysCalled: func(ctx context.Context, handler func(key []byte, val []byte) bool) error {
+				return expectedErr
+			},
+		}
+		sm, _ := NewStorageMigrator(args)
+		result, err := sm.Migrate(context.Background())
+		require.Nil(t, result)
+		require.Equal(t, expectedErr, err)
+	})
+	t.Run("last allocated index of the source fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs(t)
+		args.Source = &testscommon.ShardedStorageWithIndexStub{
+			GetLastAllocatedIndexCalled: func() (uint32, error) {
+				return 0, expectedErr
+			},
+		}
+		sm, _ := NewStorageMigrator(args)
+		result, err := sm.Migrate(context.Background())
+		require.Nil(t, result)
+		require.True(t, errors.Is(err, expectedErr))
+	})
+	t.Run("reserve indexes in destination fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs(t)
+		registerUsers(t, args.Source, 3)
+		args.Destination = &testscommon.ShardedStorageWithIndexStub{
+			ReserveIndexesUpToCalled: func(finalIndex uint32) error {
+				return expectedErr
+			},
+			PutCalled: func(key, data []byte) error {
+				require.Fail(t, "should not copy any entry")
+				return nil
+			},
+		}
+		sm, _ := NewStorageMigrator(args)
+		result, err := sm.Migrate(context.Background())
+		require.Nil(t, result)
+		require.True(t, errors.Is(err, expectedErr))
+	})
+	t.Run("invalid user info should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs(t)
+		require.Nil(t, args.Source.Put([]byte("address"), []byte("invalid user info")))
+		sm, _ := NewStorageMigrator(args)
+		result, err := sm.Migrate(context.Background())
+		require.Nil(t, result)
+		require.NotNil(t, err)
+	})
+	t.Run("put in destination fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs(t)
+		registerUsers(t, args.Source, 3)
+		args.Destination = &testscommon.ShardedStorageWithIndexStub{
+			HasCalled: func(key []byte) error {
+				return expectedErr
+			},
+			PutCalled: func(key, data []byte) error {
+				return expectedErr
+			},
+		}
+		sm, _ := NewStorageMigrator(args)
+		result, err := sm.Migrate(context.Background())
+		require.Nil(t, result)
+		require.Equal(t, expectedErr, err)
+	})
+	t.Run("reserve index in destination fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs(t)
+		registerUsers(t, args.Source, 3)
+		args.Destination = &testscommon.ShardedStorageWithIndexStub{
+			HasCalled: func(key []byte) error {
+				return expectedErr
+			},
+			ReserveIndexCalled: func(index uint32) error {
+				return expectedErr
+			},
+		}
+		sm, _ := NewStorageMigrator(args)
+		result, err := sm.Migrate(context.Background())
+		require.Nil(t, result)
+		require.Equal(t, expectedErr, err)
+	})
+	t.Run("conflicting entry in destination should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs(t)
+		registerUsers(t, args.Source, 3)
+		registerUsers(t, args.Destination, 3)
+		sm, _ := NewStorageMigrator(args)
+		result, err := sm.Migrate(context.Background())
+		require.Nil(t, result)
+		require.True(t, errors.Is(err, ErrConflictingEntry))
+	})
+	t.Run("destination not holding the migrated data should fail verification", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs(t)
+		registerUsers(t, args.Source, 3)
+		args.Destination = &testscommon.ShardedStorageWithIndexStub{
+			HasCalled: func(key []byte) error {
+				return expectedErr
+			},
+			GetCalled: func(key []byte) ([]byte, error) {
+				return []byte("other data"), nil
+			},
+		}
+		sm, _ := NewStorageMigrator(args)
+		result, err := sm.Migrate(context.Background())
+		require.Nil(t, result)
+		require.True(t, errors.Is(err, ErrVerificationFailed))
+	})
+	t.Run("done context should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs(t)
+		registerUsers(t, args.Source, 3)
+		sm, _ := NewStorageMigrator(args)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		result, err := sm.Migrate(ctx)
+		require.Nil(t, result)
+		require.Equal(t, context.Canceled, err)
+	})
+	t.Run("should migrate to a different number of buckets, keeping the indexes", func(t *testing.T) {
+		t.Parallel()
+
+		numUsers := 50
+		args := createMockArgs(t)
+		sourceIndexes := registerUsers(t, args.Source, numUsers)
+		lastAllocatedIndex, err := args.Source.GetLastAllocatedIndex()
+		require.Nil(t, err)
+		sm, _ := NewStorageMigrator(args)
+
+		result, err := sm.Migrate(context.Background())
+		require.Nil(t, err)
+		require.Equal(t, &MigrationResult{
+			ReservedIndexesUpTo: lastAllocatedIndex,
+			NumSourceEntries:    uint32(numUsers),
+			NumMigratedEntries:  uint32(numUsers),
+			NumExistingEntries:  0,
+			NumVerifiedEntries:  uint32(numUsers),
+		}, result)
+
+		usedIndexes := make(map[uint32]struct{}, numUsers)
+		for address, index := range sourceIndexes {
+			userInfoBytes, errGet := args.Destination.Get([]byte(address))
+			require.Nil(t, errGet)
+
+			userInfo := &core.UserInfo{}
+			require.Nil(t, args.UserDataMarshaller.Unmarshal(userInfo, userInfoBytes))
+			require.Equal(t, index, userInfo.Index)
+
+			usedIndexes[index] = struct{}{}
+			usedIndexes[index+1] = struct{}{}
+		}
+
+		for i := 0; i < numUsers; i++ {
+			newIndex, errAllocate := args.Destination.AllocateIndex([]byte(fmt.Sprintf("new user address %d", i)))
+			require.Nil(t, errAllocate)
+			_, isUsed := usedIndexes[newIndex]
+			require.False(t, isUsed, "index %d allocated again", newIndex)
+		}
+	})
+	t.Run("should not allocate again the indexes of the users removed from the source", func(t *testing.T) {
+		t.Parallel()
+
+		numUsers := 20
+		args := createMockArgs(t)
+		sourceIndexes := registerUsers(t, args.Source, numUsers)
+		for address := range sourceIndexes {
+			require.Nil(t, args.Source.Remove([]byte(address)))
+		}
+		sm, _ := NewStorageMigrator(args)
+
+		result, err := sm.Migrate(context.Background())
+		require.Nil(t, err)
+		require.Zero(t, result.NumSourceEntries)
+
+		usedIndexes := make(map[uint32]struct{}, 2*numUsers)
+		for _, index := range sourceIndexes {
+			usedIndexes[index] = struct{}{}
+			usedIndexes[index+1] = struct{}{}
+		}
+
+		for i := 0; i < numUsers; i++ {
+			newIndex, errAllocate := args.Destination.AllocateIndex([]byte(fmt.Sprintf("new user address %d", i)))
+			require.Nil(t, errAllocate)
+			_, isUsed := usedIndexes[newIndex]
+			require.False(t, isUsed, "index %d of a removed user allocated again", newIndex)
+		}
+	})
+	t.Run("should resume an interrupted migration", func(t *testing.T) {
+		t.Parallel()
+
+		numUsers := 20
+		args := createMockArgs(t)
+		registerUsers(t, args.Source, numUsers)
+
+		numCopied := 0
+		err := args.Source.RangeKeys(context.Background(), func(key []byte, val []byte) bool {
+			require.Nil(t, args.Destination.Put(key, val))
+			numCopied++
+			return numCopied < numUsers/2
+		})
+		require.Nil(t, err)
+
+		sm, _ := NewStorageMigrator(args)
+		result, err := sm.Migrate(context.Background())
+		require.Nil(t, err)
+		require.Equal(t, uint32(numUsers), result.NumSourceEntries)
+		require.Equal(t, uint32(numUsers/2), result.NumExistingEntries)
+		require.Equal(t, uint32(numUsers/2), result.NumMigratedEntries)
+		require.Equal(t, uint32(numUsers), result.NumVerifiedEntries)
+	})
+}
+
+func TestStorageMigrator_IsInterfaceNil(t *testing.T) {
+	t.Parallel()
+
+	var sm *storageMigrator
+	require.True(t, sm.IsInterfaceNil())
+
+	sm, _ = NewStorageMigrator(createMockArgs(t))
+	require.False(t, sm.IsInterfaceNil())
+}
@@ -0,0 +1,33 @@
+package twofactor
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+)
+
+const (
+	// AlgorithmSHA1 is the name of the SHA1 algorithm, as used in otp urls
+	AlgorithmSHA1 = "SHA1"
+
+	// AlgorithmSHA256 is the name of the SHA256 algorithm, as used in otp urls
+	AlgorithmSHA256 = "SHA256"
+
+	// AlgorithmSHA512 is the name of the SHA512 algorithm, as used in otp urls
+	AlgorithmSHA512 = "SHA512"
+)
+
+// HashFromAlgorithm returns the hash function for the provided algorithm name
+func HashFromAlgorithm(algorithm string) (crypto.Hash, error) {
+	switch algorithm {
+	case AlgorithmSHA1:
+		return crypto.SHA1, nil
+	case AlgorithmSHA256:
+		return crypto.SHA256, nil
+	case AlgorithmSHA512:
+		return crypto.SHA512, nil
+	default:
+		return 0, fmt.Errorf("%w, unknown algorithm %s", handlers.ErrInvalidOTPParams, algorithm)
+	}
+}
@@ -0,0 +1,184 @@
+package rfc
+
+import (
+	"crypto"
+	"crypto/hmac"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	qr "github.com/sec51/qrcode"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+)
+
+const otpScheme = "otpauth"
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// otp generates and validates codes as defined by RFC 4226 (hotp) and RFC 6238 (totp)
+type otp struct {
+	secret             []byte
+	account            string
+	issuer             string
+	params             core.OTPParams
+	hash               crypto.Hash
+	acceptedDriftSteps uint32
+	getTimeHandler     func() time.Time
+}
+
+func (provider *otpProvider) newOTP(secret []byte, account string, params core.OTPParams, hash crypto.Hash) *otp {
+	return &otp{
+		secret:             secret,
+		account:            account,
+		issuer:             provider.issuer,
+		params:             params,
+		hash:               hash,
+		acceptedDriftSteps: provider.acceptedDriftSteps,
+		getTimeHandler:     provider.getTimeHandler,
+	}
+}
+
+// Validate checks the provided code against the codes in the skew window for hotp, respectively in the accepted
+// drift for totp, rejecting the codes already used. The counter is moved after the matched code, respectively
+// after the matched time step for totp, so a code older than the last accepted one is rejected as well
+func (o *otp) Validate(userCode string) error {
+	if len(userCode) == 0 {
+		return handlers.ErrEmptyCode
+	}
+
+	if o.params.Type == core.HOTPType {
+		return o.validateHOTP(userCode)
+	}
+
+	return o.validateTOTP(userCode)
+}
+
+func (o *otp) validateHOTP(userCode string) error {
+	for i := uint64(0); i <= uint64(o.params.Skew); i++ {
+		counter := o.params.Counter + i
+		if o.isCodeForCounter(userCode, counter) {
+			o.params.Counter = counter + 1
+			return nil
+		}
+	}
+
+	return handlers.ErrWrongCode
+}
+
+func (o *otp) validateTOTP(userCode string) error {
+	currentCounter := o.currentTimeCounter()
+	drift := uint64(o.acceptedDriftSteps)
+	firstCounter := uint64(0)
+	if currentCounter > drift {
+		firstCounter = currentCounter - drift
+	}
+	// the time steps before the last accepted one are rejected, so an observed code cannot be replayed
+	if firstCounter < o.params.Counter {
+		firstCounter = o.params.Counter
+	}
+	for counter := firstCounter; counter <= currentCounter+drift; counter++ {
+		if o.isCodeForCounter(userCode, counter) {
+			o.params.Counter = counter + 1
+			return nil
+		}
+	}
+
+	return handlers.ErrWrongCode
+}
+
+func (o *otp) isCodeForCounter(userCode string, counter uint64) bool {
+	return subtle.ConstantTimeCompare([]byte(userCode), []byte(o.computeCode(counter))) == 1
+}
+
+func (o *otp) currentTimeCounter() uint64 {
+	return uint64(o.getTimeHandler().Unix()) / uint64(o.params.Period)
+}
+
+// computeCode computes the code for the provided counter, as defined in RFC 4226, section 5.3
+func (o *otp) computeCode(counter uint64) string {
+	counterBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(counterBytes, counter)
+
+	mac := hmac.New(o.hash.New, o.secret)
+	_, _ = mac.Write(counterBytes)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0xf
+	truncated := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := uint32(0); i < o.params.Digits; i++ {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", o.params.Digits, truncated%modulo)
+}
+
+// OTP returns the current code, respectively the next expected code for hotp
+func (o *otp) OTP() (string, error) {
+	if o.params.Type == core.HOTPType {
+		return o.computeCode(o.params.Counter), nil
+	}
+
+	return o.computeCode(o.currentTimeCounter()), nil
+}
+
+// QR returns the otp url encoded as a png qr code
+func (o *otp) QR() ([]byte, error) {
+	otpUrl, err := o.Url()
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := qr.Encode(otpUrl, qr.Q)
+	if err != nil {
+		return nil, err
+	}
+
+	return code.PNG(), nil
+}
+
+// ToBytes returns the secret of the otp. The params are saved separately
+func (o *otp) ToBytes() ([]byte, error) {
+	secret := make([]byte, len(o.secret))
+	copy(secret, o.secret)
+
+	return secret, nil
+}
+
+// Url returns the otp url in the key uri format, eg: otpauth://totp/Example:alice@google.com?secret=JBSWY3DPEHPK3PXP&issuer=Example
+func (o *otp) Url() (string, error) {
+	values := url.Values{}
+	values.Add("secret", secretEncoding.EncodeToString(o.secret))
+	values.Add("issuer", o.issuer)
+	values.Add("algorithm", o.params.Algorithm)
+	values.Add("digits", strconv.Itoa(int(o.params.Digits)))
+	if o.params.Type == core.HOTPType {
+		values.Add("counter", strconv.FormatUint(o.params.Counter, 10))
+	} else {
+		values.Add("period", strconv.Itoa(int(o.params.Period)))
+	}
+
+	u := url.URL{
+		Scheme:   otpScheme,
+		Host:     o.params.Type,
+		Path:     fmt.Sprintf("%s:%s", url.QueryEscape(o.issuer), o.account),
+		RawQuery: values.Encode(),
+	}
+
+	return u.String(), nil
+}
+
+// Params returns the params of the otp, including the next accepted counter, respectively time step for totp
+func (o *otp) Params() core.OTPParams {
+	return o.params
+}
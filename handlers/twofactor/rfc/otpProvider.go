@@ -0,0 +1,79 @@
+package rfc
+
+import (
+	"crypto/rand"
+	"errors"
+	"time"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/twofactor"
+)
+
+var errEmptySecret = errors.New("empty secret")
+
+type otpProvider struct {
+	issuer             string
+	acceptedDriftSteps uint32
+	getTimeHandler     func() time.Time
+}
+
+// NewOTPProvider returns a new provider of otps as defined by RFC 4226 (hotp) and RFC 6238 (totp).
+// The totp codes are accepted for the provided number of time steps before and after the current one
+func NewOTPProvider(issuer string, acceptedDriftSteps uint32) (*otpProvider, error) {
+	err := twofactor.CheckAcceptedDriftSteps(acceptedDriftSteps)
+	if err != nil {
+		return nil, err
+	}
+
+	return &otpProvider{
+		issuer:             issuer,
+		acceptedDriftSteps: acceptedDriftSteps,
+		getTimeHandler:     time.Now,
+	}, nil
+}
+
+// GenerateOTP returns a new otp with a random secret, sized as the output of the hash function
+func (provider *otpProvider) GenerateOTP(account string, params core.OTPParams) (handlers.OTP, error) {
+	err := twofactor.CheckOTPParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := twofactor.HashFromAlgorithm(params.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, hash.Size())
+	_, err = rand.Read(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.newOTP(secret, account, params, hash), nil
+}
+
+// OTPFromBytes returns the otp for the provided secret and params
+func (provider *otpProvider) OTPFromBytes(otpBytes []byte, params core.OTPParams) (handlers.OTP, error) {
+	if len(otpBytes) == 0 {
+		return nil, errEmptySecret
+	}
+
+	err := twofactor.CheckOTPParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := twofactor.HashFromAlgorithm(params.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.newOTP(otpBytes, "", params, hash), nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (provider *otpProvider) IsInterfaceNil() bool {
+	return provider == nil
+}
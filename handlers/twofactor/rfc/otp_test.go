@@ -0,0 +1,318 @@
+package rfc
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+)
+
+var (
+	rfcSecretSHA1   = []byte("12345678901234567890")
+	rfcSecretSHA256 = []byte("12345678901234567890123456789012")
+	rfcSecretSHA512 = []byte("1234567890123456789012345678901234567890123456789012345678901234")
+)
+
+func createHOTPParams() core.OTPParams {
+	return core.OTPParams{
+		Type:      core.HOTPType,
+		Algorithm: "SHA1",
+		Digits:    6,
+		Skew:      2,
+	}
+}
+
+func createTOTPParams(algorithm string) core.OTPParams {
+	return core.OTPParams{
+		Type:      core.TOTPType,
+		Algorithm: algorithm,
+		Digits:    8,
+		Period:    30,
+		Skew:      1,
+	}
+}
+
+func createProvider() *otpProvider {
+	provider, _ := NewOTPProvider("MultiversX", 1)
+	return provider
+}
+
+func createProviderAt(unixTime int64) *otpProvider {
+	provider := createProvider()
+	provider.getTimeHandler = func() time.Time {
+		return time.Unix(unixTime, 0)
+	}
+
+	return provider
+}
+
+func TestOTP_HOTPTestVectors(t *testing.T) {
+	t.Parallel()
+
+	// test values from RFC 4226, appendix D
+	expectedCodes := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+
+	otpHandler, err := createProvider().OTPFromBytes(rfcSecretSHA1, createHOTPParams())
+	require.Nil(t, err)
+
+	for i, expectedCode := range expectedCodes {
+		code, errOTP := otpHandler.OTP()
+		assert.Nil(t, errOTP)
+		assert.Equal(t, expectedCode, code)
+
+		assert.Nil(t, otpHandler.Validate(expectedCode))
+		assert.Equal(t, uint64(i+1), otpHandler.Params().Counter)
+	}
+}
+
+func TestOTP_HOTPValidate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty code should error", func(t *testing.T) {
+		t.Parallel()
+
+		otpHandler, _ := createProvider().OTPFromBytes(rfcSecretSHA1, createHOTPParams())
+		assert.Equal(t, handlers.ErrEmptyCode, otpHandler.Validate(""))
+	})
+	t.Run("code inside the skew window should move the counter after it", func(t *testing.T) {
+		t.Parallel()
+
+		otpHandler, _ := createProvider().OTPFromBytes(rfcSecretSHA1, createHOTPParams())
+		assert.Nil(t, otpHandler.Validate("359152"))
+		assert.Equal(t, uint64(3), otpHandler.Params().Counter)
+	})
+	t.Run("code outside the skew window should error", func(t *testing.T) {
+		t.Parallel()
+
+		otpHandler, _ := createProvider().OTPFromBytes(rfcSecretSHA1, createHOTPParams())
+		assert.Equal(t, handlers.ErrWrongCode, otpHandler.Validate("969429"))
+		assert.Equal(t, uint64(0), otpHandler.Params().Counter)
+	})
+	t.Run("used code should error", func(t *testing.T) {
+		t.Parallel()
+
+		params := createHOTPParams()
+		params.Counter = 1
+		otpHandler, _ := createProvider().OTPFromBytes(rfcSecretSHA1, params)
+		assert.Equal(t, handlers.ErrWrongCode, otpHandler.Validate("755224"))
+		assert.Equal(t, uint64(1), otpHandler.Params().Counter)
+	})
+}
+
+func TestOTP_TOTPTestVectors(t *testing.T) {
+	t.Parallel()
+
+	// test values from RFC 6238, appendix B
+	testData := []struct {
+		unixTime     int64
+		algorithm    string
+		secret       []byte
+		expectedCode string
+	}{
+		{59, "SHA1", rfcSecretSHA1, "94287082"},
+		{59, "SHA256", rfcSecretSHA256, "46119246"},
+		{59, "SHA512", rfcSecretSHA512, "90693936"},
+		{1111111109, "SHA1", rfcSecretSHA1, "07081804"},
+		{1111111109, "SHA256", rfcSecretSHA256, "68084774"},
+		{1111111109, "SHA512", rfcSecretSHA512, "25091201"},
+		{2000000000, "SHA1", rfcSecretSHA1, "69279037"},
+		{2000000000, "SHA256", rfcSecretSHA256, "90698825"},
+		{2000000000, "SHA512", rfcSecretSHA512, "38618901"},
+	}
+
+	for _, td := range testData {
+		otpHandler, err := createProviderAt(td.unixTime).OTPFromBytes(td.secret, createTOTPParams(td.algorithm))
+		require.Nil(t, err)
+
+		code, err := otpHandler.OTP()
+		assert.Nil(t, err)
+		assert.Equal(t, td.expectedCode, code, "%s at %d", td.algorithm, td.unixTime)
+		assert.Nil(t, otpHandler.Validate(td.expectedCode))
+	}
+}
+
+func TestOTP_TOTPValidate(t *testing.T) {
+	t.Parallel()
+
+	// code 07081804 is valid for the interval [1111111080, 1111111110)
+	t.Run("code inside the skew window should work", func(t *testing.T) {
+		t.Parallel()
+
+		otpHandler, _ := createProviderAt(1111111139).OTPFromBytes(rfcSecretSHA1, createTOTPParams("SHA1"))
+		assert.Nil(t, otpHandler.Validate("07081804"))
+
+		otpHandler, _ = createProviderAt(1111111050).OTPFromBytes(rfcSecretSHA1, createTOTPParams("SHA1"))
+		assert.Nil(t, otpHandler.Validate("07081804"))
+	})
+	t.Run("the accepted drift should apply instead of the skew", func(t *testing.T) {
+		t.Parallel()
+
+		params := createTOTPParams("SHA1")
+		params.Skew = 0
+		otpHandler, _ := createProviderAt(1111111139).OTPFromBytes(rfcSecretSHA1, params)
+		assert.Nil(t, otpHandler.Validate("07081804"))
+
+		params.Skew = 5
+		provider := createProviderAt(1111111139)
+		provider.acceptedDriftSteps = 0
+		otpHandler, _ = provider.OTPFromBytes(rfcSecretSHA1, params)
+		assert.Equal(t, handlers.ErrWrongCode, otpHandler.Validate("07081804"))
+	})
+	t.Run("second code of a later time step should work", func(t *testing.T) {
+		t.Parallel()
+
+		otpHandler, _ := createProviderAt(1111111109).OTPFromBytes(rfcSecretSHA1, createTOTPParams("SHA1"))
+		assert.Nil(t, otpHandler.Validate("07081804"))
+
+		otpHandler, _ = createProviderAt(1111111110).OTPFromBytes(rfcSecretSHA1, otpHandler.Params())
+		secondCode, _ := otpHandler.OTP()
+		assert.Nil(t, otpHandler.Validate(secondCode))
+		assert.Equal(t, uint64(1111111110/30+1), otpHandler.Params().Counter)
+	})
+	t.Run("code outside the skew window should error", func(t *testing.T) {
+		t.Parallel()
+
+		otpHandler, _ := createProviderAt(1111111140).OTPFromBytes(rfcSecretSHA1, createTOTPParams("SHA1"))
+		assert.Equal(t, handlers.ErrWrongCode, otpHandler.Validate("07081804"))
+	})
+	t.Run("longer period should work", func(t *testing.T) {
+		t.Parallel()
+
+		params := createTOTPParams("SHA1")
+		params.Period = 60
+		provider := createProviderAt(1111111109)
+		provider.acceptedDriftSteps = 0
+		otpHandler, _ := provider.OTPFromBytes(rfcSecretSHA1, params)
+		code, _ := otpHandler.OTP()
+		assert.Nil(t, otpHandler.Validate(code))
+
+		// the 60 seconds interval is [1111111080, 1111111140)
+		provider = createProviderAt(1111111139)
+		provider.acceptedDriftSteps = 0
+		otpHandler, _ = provider.OTPFromBytes(rfcSecretSHA1, params)
+		assert.Nil(t, otpHandler.Validate(code))
+
+		provider = createProviderAt(1111111079)
+		provider.acceptedDriftSteps = 0
+		otpHandler, _ = provider.OTPFromBytes(rfcSecretSHA1, params)
+		assert.Equal(t, handlers.ErrWrongCode, otpHandler.Validate(code))
+	})
+	t.Run("code already used in the same time step should error", func(t *testing.T) {
+		t.Parallel()
+
+		otpHandler, _ := createProviderAt(1111111109).OTPFromBytes(rfcSecretSHA1, createTOTPParams("SHA1"))
+		assert.Nil(t, otpHandler.Validate("07081804"))
+		assert.Equal(t, uint64(1111111080/30+1), otpHandler.Params().Counter)
+		assert.Equal(t, handlers.ErrWrongCode, otpHandler.Validate("07081804"))
+
+		otpHandler, _ = createProviderAt(1111111109).OTPFromBytes(rfcSecretSHA1, otpHandler.Params())
+		assert.Equal(t, handlers.ErrWrongCode, otpHandler.Validate("07081804"))
+	})
+	t.Run("code of a time step before the last accepted one should error", func(t *testing.T) {
+		t.Parallel()
+
+		// the code of the next time step was already used, while the previous one is still inside the skew window
+		params := createTOTPParams("SHA1")
+		params.Counter = 1111111110/30 + 1
+		otpHandler, _ := createProviderAt(1111111110).OTPFromBytes(rfcSecretSHA1, params)
+		assert.Equal(t, handlers.ErrWrongCode, otpHandler.Validate("07081804"))
+		assert.Equal(t, params.Counter, otpHandler.Params().Counter)
+	})
+	t.Run("skew at the start of the time should not underflow", func(t *testing.T) {
+		t.Parallel()
+
+		otpHandler, _ := createProviderAt(0).OTPFromBytes(rfcSecretSHA1, createTOTPParams("SHA1"))
+		assert.Equal(t, handlers.ErrWrongCode, otpHandler.Validate("00000000"))
+	})
+}
+
+func TestOTPProvider_GenerateOTP(t *testing.T) {
+	t.Parallel()
+
+	provider := createProvider()
+	assert.False(t, provider.IsInterfaceNil())
+
+	t.Run("no accepted drift should error", func(t *testing.T) {
+		t.Parallel()
+
+		invalidProvider, err := NewOTPProvider("MultiversX", 0)
+		assert.ErrorIs(t, err, handlers.ErrInvalidConfig)
+		assert.Nil(t, invalidProvider)
+	})
+	t.Run("too large accepted drift should error", func(t *testing.T) {
+		t.Parallel()
+
+		invalidProvider, err := NewOTPProvider("MultiversX", 11)
+		assert.ErrorIs(t, err, handlers.ErrInvalidConfig)
+		assert.Nil(t, invalidProvider)
+	})
+
+	t.Run("invalid params should error", func(t *testing.T) {
+		t.Parallel()
+
+		params := createTOTPParams("MD5")
+		otpHandler, err := provider.GenerateOTP("account", params)
+		assert.ErrorIs(t, err, handlers.ErrInvalidOTPParams)
+		assert.Nil(t, otpHandler)
+	})
+	t.Run("totp should work", func(t *testing.T) {
+		t.Parallel()
+
+		params := createTOTPParams("SHA256")
+		otpHandler, err := provider.GenerateOTP("account", params)
+		require.Nil(t, err)
+		assert.Equal(t, params, otpHandler.Params())
+
+		secret, err := otpHandler.ToBytes()
+		assert.Nil(t, err)
+		assert.Equal(t, 32, len(secret))
+
+		otpUrl, err := otpHandler.Url()
+		assert.Nil(t, err)
+		u, err := url.Parse(otpUrl)
+		require.Nil(t, err)
+		assert.Equal(t, "otpauth", u.Scheme)
+		assert.Equal(t, core.TOTPType, u.Host)
+		assert.Equal(t, "/MultiversX:account", u.Path)
+		assert.Equal(t, "SHA256", u.Query().Get("algorithm"))
+		assert.Equal(t, "8", u.Query().Get("digits"))
+		assert.Equal(t, "30", u.Query().Get("period"))
+		assert.Equal(t, secretEncoding.EncodeToString(secret), u.Query().Get("secret"))
+
+		qr, err := otpHandler.QR()
+		assert.Nil(t, err)
+		assert.NotEmpty(t, qr)
+
+		otpFromBytes, err := provider.OTPFromBytes(secret, otpHandler.Params())
+		require.Nil(t, err)
+		code, _ := otpHandler.OTP()
+		assert.Nil(t, otpFromBytes.Validate(code))
+	})
+	t.Run("hotp should work", func(t *testing.T) {
+		t.Parallel()
+
+		params := createHOTPParams()
+		otpHandler, err := provider.GenerateOTP("account", params)
+		require.Nil(t, err)
+
+		otpUrl, err := otpHandler.Url()
+		assert.Nil(t, err)
+		u, err := url.Parse(otpUrl)
+		require.Nil(t, err)
+		assert.Equal(t, core.HOTPType, u.Host)
+		assert.Equal(t, "0", u.Query().Get("counter"))
+		assert.Empty(t, u.Query().Get("period"))
+	})
+	t.Run("empty secret should error", func(t *testing.T) {
+		t.Parallel()
+
+		otpHandler, err := provider.OTPFromBytes(nil, createHOTPParams())
+		assert.Equal(t, errEmptySecret, err)
+		assert.Nil(t, otpHandler)
+	})
+}
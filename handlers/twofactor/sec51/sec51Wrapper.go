@@ -1,41 +1,83 @@
 package sec51
 
 import (
-	"crypto"
 	"errors"
+	"fmt"
 
-	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
 	"github.com/multiversx/twofactor"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+	handlersTwoFactor "github.com/multiversx/mx-multi-factor-auth-go-service/handlers/twofactor"
+)
+
+const (
+	sec51Period = 30
+	sec51Skew   = 1
 )
 
 var emptyEncryptedMessage = errors.New("empty encrypted message")
 
 type sec51Wrapper struct {
-	digits int
 	issuer string
 }
 
+// sec51Totp wraps the sec51 totp, whose params are saved along with the secret
+type sec51Totp struct {
+	*twofactor.Totp
+}
+
+// Params returns empty params, as the sec51 totp holds them internally
+func (totp *sec51Totp) Params() core.OTPParams {
+	return core.OTPParams{}
+}
+
 // NewSec51Wrapper returns a new sec51 wrapper instance
-func NewSec51Wrapper(digits int, issuer string) *sec51Wrapper {
+func NewSec51Wrapper(issuer string) *sec51Wrapper {
 	return &sec51Wrapper{
-		digits: digits,
 		issuer: issuer,
 	}
 }
 
-// GenerateTOTP returns a new sec51 totp
-func (s *sec51Wrapper) GenerateTOTP(account string, hash crypto.Hash) (handlers.OTP, error) {
-	return twofactor.NewTOTP(account, s.issuer, hash, s.digits)
+// GenerateOTP returns a new sec51 totp. Only time based otps with the default period and skew are supported
+func (s *sec51Wrapper) GenerateOTP(account string, params core.OTPParams) (handlers.OTP, error) {
+	if params.Type != core.TOTPType || params.Period != sec51Period || params.Skew != sec51Skew {
+		return nil, fmt.Errorf("%w for sec51, only %s with period %d and skew %d is supported",
+			handlers.ErrInvalidOTPParams, core.TOTPType, sec51Period, sec51Skew)
+	}
+
+	hash, err := handlersTwoFactor.HashFromAlgorithm(params.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	totp, err := twofactor.NewTOTP(account, s.issuer, hash, int(params.Digits))
+	if err != nil {
+		return nil, err
+	}
+
+	return &sec51Totp{
+		Totp: totp,
+	}, nil
 }
 
-// TOTPFromBytes returns the totp for the provided bytes
-func (s *sec51Wrapper) TOTPFromBytes(encryptedMessage []byte) (handlers.OTP, error) {
-	if len(encryptedMessage) == 0 {
+// OTPFromBytes returns the totp for the provided bytes. The params are ignored, being part of the bytes
+func (s *sec51Wrapper) OTPFromBytes(otpBytes []byte, _ core.OTPParams) (handlers.OTP, error) {
+	if len(otpBytes) == 0 {
 		return nil, emptyEncryptedMessage
 	}
-	return twofactor.TOTPFromBytes(encryptedMessage)
+
+	totp, err := twofactor.TOTPFromBytes(otpBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sec51Totp{
+		Totp: totp,
+	}, nil
 }
 
+// IsInterfaceNil returns true if there is no value under the interface
 func (s *sec51Wrapper) IsInterfaceNil() bool {
 	return s == nil
 }
@@ -1,14 +1,25 @@
 package sec51_test
 
 import (
-	"crypto"
+	"errors"
 	"testing"
 
-	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/twofactor/sec51"
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/twofactor/sec51"
 )
 
+var providedParams = core.OTPParams{
+	Type:      core.TOTPType,
+	Algorithm: "SHA1",
+	Digits:    6,
+	Period:    30,
+	Skew:      1,
+}
+
 func TestSec51Wrapper_ShouldWork(t *testing.T) {
 	t.Parallel()
 
@@ -19,22 +30,51 @@ func TestSec51Wrapper_ShouldWork(t *testing.T) {
 		}
 	}()
 
-	s := sec51.NewSec51Wrapper(6, "MultiversX")
+	s := sec51.NewSec51Wrapper("MultiversX")
 	assert.NotNil(t, s)
 
-	totp, err := s.GenerateTOTP("account", crypto.SHA1)
+	totp, err := s.GenerateOTP("account", providedParams)
 	assert.Nil(t, err)
 	assert.NotNil(t, totp)
+	assert.Equal(t, core.OTPParams{}, totp.Params())
 
 	bytes, err := totp.ToBytes()
 	assert.Nil(t, err)
 
-	totpFromBytes, err := s.TOTPFromBytes(bytes)
+	totpFromBytes, err := s.OTPFromBytes(bytes, core.OTPParams{})
 	assert.Nil(t, err)
 	assert.NotNil(t, totpFromBytes)
 }
 
-func TestSec51Wrapper_TOTPFromBytes(t *testing.T) {
+func TestSec51Wrapper_GenerateOTPUnsupportedParams(t *testing.T) {
+	t.Parallel()
+
+	s := sec51.NewSec51Wrapper("MultiversX")
+
+	t.Run("hotp should error", func(t *testing.T) {
+		params := providedParams
+		params.Type = core.HOTPType
+		totp, err := s.GenerateOTP("account", params)
+		assert.Nil(t, totp)
+		assert.True(t, errors.Is(err, handlers.ErrInvalidOTPParams))
+	})
+	t.Run("custom period should error", func(t *testing.T) {
+		params := providedParams
+		params.Period = 60
+		totp, err := s.GenerateOTP("account", params)
+		assert.Nil(t, totp)
+		assert.True(t, errors.Is(err, handlers.ErrInvalidOTPParams))
+	})
+	t.Run("unknown algorithm should error", func(t *testing.T) {
+		params := providedParams
+		params.Algorithm = "MD5"
+		totp, err := s.GenerateOTP("account", params)
+		assert.Nil(t, totp)
+		assert.True(t, errors.Is(err, handlers.ErrInvalidOTPParams))
+	})
+}
+
+func TestSec51Wrapper_OTPFromBytes(t *testing.T) {
 	t.Parallel()
 
 	defer func() {
@@ -44,15 +84,15 @@ func TestSec51Wrapper_TOTPFromBytes(t *testing.T) {
 		}
 	}()
 
-	s := sec51.NewSec51Wrapper(6, "MultiversX")
+	s := sec51.NewSec51Wrapper("MultiversX")
 
 	t.Run("should return error if totp is nil", func(t *testing.T) {
-		totp, err := s.TOTPFromBytes(nil)
+		totp, err := s.OTPFromBytes(nil, core.OTPParams{})
 		assert.Nil(t, totp)
 		assert.NotNil(t, err)
 	})
 	t.Run("should return error if totp is empty", func(t *testing.T) {
-		totp, err := s.TOTPFromBytes([]byte{})
+		totp, err := s.OTPFromBytes([]byte{}, core.OTPParams{})
 		assert.Nil(t, totp)
 		assert.NotNil(t, err)
 	})
@@ -1,38 +1,161 @@
 package twofactor
 
 import (
-	"crypto"
+	"encoding/base32"
+	"fmt"
+	"net/url"
+	"strconv"
 
-	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
 	"github.com/multiversx/mx-chain-core-go/core/check"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
 )
 
-// twoFactorHandler is a wrapper over two factor totp implementation
+const (
+	minDigits = 6
+	maxDigits = 8
+	maxSkew   = 10
+
+	minAcceptedDriftSteps = 1
+	maxAcceptedDriftSteps = 10
+
+	secretKey    = "secret"
+	digitsKey    = "digits"
+	periodKey    = "period"
+	algorithmKey = "algorithm"
+)
+
+// ArgsTwoFactorHandler is the DTO used to create a new instance of twoFactorHandler
+type ArgsTwoFactorHandler struct {
+	OTPProvider       handlers.OTPProvider
+	LegacyOTPProvider handlers.OTPProvider
+	Params            core.OTPParams
+}
+
+// twoFactorHandler is a wrapper over the otp providers. The otps are generated with the configured params, while the
+// otps saved without params are loaded by the legacy provider and converted, using the configured skew
 type twoFactorHandler struct {
-	otpProvider handlers.OTPProvider
-	hashType    crypto.Hash
+	otpProvider       handlers.OTPProvider
+	legacyOTPProvider handlers.OTPProvider
+	params            core.OTPParams
 }
 
 // NewTwoFactorHandler returns a new instance of twoFactorHandler
-func NewTwoFactorHandler(otpProvider handlers.OTPProvider, hashType crypto.Hash) (*twoFactorHandler, error) {
-	if check.IfNil(otpProvider) {
+func NewTwoFactorHandler(args ArgsTwoFactorHandler) (*twoFactorHandler, error) {
+	if check.IfNil(args.OTPProvider) {
 		return nil, handlers.ErrNilOTPProvider
 	}
+	if check.IfNil(args.LegacyOTPProvider) {
+		return nil, fmt.Errorf("%w for legacy otps", handlers.ErrNilOTPProvider)
+	}
+	err := CheckOTPParams(args.Params)
+	if err != nil {
+		return nil, err
+	}
 
 	return &twoFactorHandler{
-		otpProvider: otpProvider,
-		hashType:    hashType,
+		otpProvider:       args.OTPProvider,
+		legacyOTPProvider: args.LegacyOTPProvider,
+		params:            args.Params,
 	}, nil
 }
 
-// CreateTOTP returns a new two factor totp
+// CheckOTPParams returns error if the provided otp params are not supported
+func CheckOTPParams(params core.OTPParams) error {
+	if params.Type != core.TOTPType && params.Type != core.HOTPType {
+		return fmt.Errorf("%w, unknown type %s", handlers.ErrInvalidOTPParams, params.Type)
+	}
+	_, err := HashFromAlgorithm(params.Algorithm)
+	if err != nil {
+		return err
+	}
+	if params.Digits < minDigits || params.Digits > maxDigits {
+		return fmt.Errorf("%w, digits should be between %d and %d, got %d",
+			handlers.ErrInvalidOTPParams, minDigits, maxDigits, params.Digits)
+	}
+	if params.Type == core.TOTPType && params.Period == 0 {
+		return fmt.Errorf("%w, period should be positive", handlers.ErrInvalidOTPParams)
+	}
+	if params.Skew > maxSkew {
+		return fmt.Errorf("%w, skew should be at most %d, got %d", handlers.ErrInvalidOTPParams, maxSkew, params.Skew)
+	}
+
+	return nil
+}
+
+// CheckAcceptedDriftSteps returns error if the provided totp drift is out of range. Without any drift a single
+// totp code is valid at a time, so the requests needing two codes could never pass
+func CheckAcceptedDriftSteps(acceptedDriftSteps uint32) error {
+	if acceptedDriftSteps < minAcceptedDriftSteps {
+		return fmt.Errorf("%w, accepted drift should be at least %d steps, got %d",
+			handlers.ErrInvalidConfig, minAcceptedDriftSteps, acceptedDriftSteps)
+	}
+	if acceptedDriftSteps > maxAcceptedDriftSteps {
+		return fmt.Errorf("%w, accepted drift should be at most %d steps, got %d",
+			handlers.ErrInvalidConfig, maxAcceptedDriftSteps, acceptedDriftSteps)
+	}
+
+	return nil
+}
+
+// CreateTOTP returns a new otp generated with the configured params
 func (handler *twoFactorHandler) CreateTOTP(account string) (handlers.OTP, error) {
-	return handler.otpProvider.GenerateTOTP(account, handler.hashType)
+	return handler.otpProvider.GenerateOTP(account, handler.params)
 }
 
-// TOTPFromBytes returns a two factor totp from bytes
-func (handler *twoFactorHandler) TOTPFromBytes(encryptedMessage []byte) (handlers.OTP, error) {
-	return handler.otpProvider.TOTPFromBytes(encryptedMessage)
+// TOTPFromBytes returns the otp from bytes, using the params it was generated with
+func (handler *twoFactorHandler) TOTPFromBytes(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
+	if len(params.Type) == 0 {
+		return handler.convertLegacyOTP(encryptedMessage)
+	}
+
+	return handler.otpProvider.OTPFromBytes(encryptedMessage, params)
+}
+
+// convertLegacyOTP extracts the secret and the params of the legacy otp from its url, so that it can be validated
+// as the other otps. The returned otp saves in the new format
+func (handler *twoFactorHandler) convertLegacyOTP(legacyOTPBytes []byte) (handlers.OTP, error) {
+	legacyOTP, err := handler.legacyOTPProvider.OTPFromBytes(legacyOTPBytes, core.OTPParams{})
+	if err != nil {
+		return nil, err
+	}
+
+	otpUrl, err := legacyOTP.Url()
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(otpUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	query := u.Query()
+	secret, err := base32.StdEncoding.DecodeString(query.Get(secretKey))
+	if err != nil {
+		return nil, fmt.Errorf("%w while decoding the legacy otp secret", err)
+	}
+
+	digits, err := strconv.ParseUint(query.Get(digitsKey), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%w while parsing the legacy otp digits", err)
+	}
+
+	period, err := strconv.ParseUint(query.Get(periodKey), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%w while parsing the legacy otp period", err)
+	}
+
+	params := core.OTPParams{
+		Type:      core.TOTPType,
+		Algorithm: query.Get(algorithmKey),
+		Digits:    uint32(digits),
+		Period:    uint32(period),
+		Skew:      handler.params.Skew,
+	}
+
+	return handler.otpProvider.OTPFromBytes(secret, params)
 }
 
 // IsInterfaceNil returns true if there is no value under the interface
@@ -1,52 +1,254 @@
 package twofactor_test
 
 import (
-	"crypto"
+	"errors"
 	"fmt"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/twofactor"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/twofactor/rfc"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/twofactor/sec51"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
-	"github.com/stretchr/testify/assert"
 )
 
-func TestTwoFactorHandler_ShouldWork(t *testing.T) {
+var expectedErr = errors.New("expected error")
+
+func createMockArgs() twofactor.ArgsTwoFactorHandler {
+	return twofactor.ArgsTwoFactorHandler{
+		OTPProvider:       &testscommon.OTPProviderStub{},
+		LegacyOTPProvider: &testscommon.OTPProviderStub{},
+		Params: core.OTPParams{
+			Type:      core.TOTPType,
+			Algorithm: twofactor.AlgorithmSHA1,
+			Digits:    6,
+			Period:    30,
+			Skew:      1,
+		},
+	}
+}
+
+func TestNewTwoFactorHandler(t *testing.T) {
 	t.Parallel()
 
 	t.Run("nil otp provider should error", func(t *testing.T) {
 		t.Parallel()
 
-		handler, err := twofactor.NewTwoFactorHandler(nil, crypto.SHA1)
+		args := createMockArgs()
+		args.OTPProvider = nil
+		handler, err := twofactor.NewTwoFactorHandler(args)
 		assert.Equal(t, handlers.ErrNilOTPProvider, err)
 		assert.Nil(t, handler)
 	})
+	t.Run("nil legacy otp provider should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.LegacyOTPProvider = nil
+		handler, err := twofactor.NewTwoFactorHandler(args)
+		assert.True(t, errors.Is(err, handlers.ErrNilOTPProvider))
+		assert.Nil(t, handler)
+	})
+	t.Run("invalid params should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Params.Type = "invalid"
+		handler, err := twofactor.NewTwoFactorHandler(args)
+		assert.True(t, errors.Is(err, handlers.ErrInvalidOTPParams))
+		assert.Nil(t, handler)
+	})
 	t.Run("should work", func(t *testing.T) {
 		t.Parallel()
 
-		wasGenerateTOTPCalled := false
-		wasTOTPFromBytesCalled := false
-		handler, err := twofactor.NewTwoFactorHandler(&testscommon.OTPProviderStub{
-			GenerateTOTPCalled: func(account string, hash crypto.Hash) (handlers.OTP, error) {
-				wasGenerateTOTPCalled = true
-				return &testscommon.TotpStub{}, nil
+		handler, err := twofactor.NewTwoFactorHandler(createMockArgs())
+		assert.Nil(t, err)
+		assert.False(t, handler.IsInterfaceNil())
+	})
+}
+
+func TestCheckOTPParams(t *testing.T) {
+	t.Parallel()
+
+	validParams := createMockArgs().Params
+	t.Run("unknown algorithm should error", func(t *testing.T) {
+		t.Parallel()
+
+		params := validParams
+		params.Algorithm = "MD5"
+		assert.True(t, errors.Is(twofactor.CheckOTPParams(params), handlers.ErrInvalidOTPParams))
+	})
+	t.Run("invalid digits should error", func(t *testing.T) {
+		t.Parallel()
+
+		params := validParams
+		params.Digits = 5
+		assert.True(t, errors.Is(twofactor.CheckOTPParams(params), handlers.ErrInvalidOTPParams))
+
+		params.Digits = 9
+		assert.True(t, errors.Is(twofactor.CheckOTPParams(params), handlers.ErrInvalidOTPParams))
+	})
+	t.Run("zero period for totp should error", func(t *testing.T) {
+		t.Parallel()
+
+		params := validParams
+		params.Period = 0
+		assert.True(t, errors.Is(twofactor.CheckOTPParams(params), handlers.ErrInvalidOTPParams))
+	})
+	t.Run("zero period for hotp should work", func(t *testing.T) {
+		t.Parallel()
+
+		params := validParams
+		params.Type = core.HOTPType
+		params.Period = 0
+		assert.Nil(t, twofactor.CheckOTPParams(params))
+	})
+	t.Run("too large skew should error", func(t *testing.T) {
+		t.Parallel()
+
+		params := validParams
+		params.Skew = 11
+		assert.True(t, errors.Is(twofactor.CheckOTPParams(params), handlers.ErrInvalidOTPParams))
+	})
+}
+
+func TestTwoFactorHandler_ShouldWork(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgs()
+	wasGenerateOTPCalled := false
+	wasOTPFromBytesCalled := false
+	args.OTPProvider = &testscommon.OTPProviderStub{
+		GenerateOTPCalled: func(account string, params core.OTPParams) (handlers.OTP, error) {
+			assert.Equal(t, "account", account)
+			assert.Equal(t, args.Params, params)
+			wasGenerateOTPCalled = true
+			return &testscommon.TotpStub{}, nil
+		},
+		OTPFromBytesCalled: func(otpBytes []byte, params core.OTPParams) (handlers.OTP, error) {
+			assert.Equal(t, []byte("secret"), otpBytes)
+			assert.Equal(t, args.Params, params)
+			wasOTPFromBytesCalled = true
+			return &testscommon.TotpStub{}, nil
+		},
+	}
+	handler, err := twofactor.NewTwoFactorHandler(args)
+	assert.Nil(t, err)
+
+	totp, err := handler.CreateTOTP("account")
+	assert.Nil(t, err)
+	assert.Equal(t, "*testscommon.TotpStub", fmt.Sprintf("%T", totp))
+	assert.True(t, wasGenerateOTPCalled)
+
+	_, err = handler.TOTPFromBytes([]byte("secret"), args.Params)
+	assert.Nil(t, err)
+	assert.True(t, wasOTPFromBytesCalled)
+}
+
+func TestTwoFactorHandler_TOTPFromBytesLegacyOTP(t *testing.T) {
+	t.Parallel()
+
+	t.Run("legacy provider error should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.LegacyOTPProvider = &testscommon.OTPProviderStub{
+			OTPFromBytesCalled: func(otpBytes []byte, params core.OTPParams) (handlers.OTP, error) {
+				return nil, expectedErr
+			},
+		}
+		handler, _ := twofactor.NewTwoFactorHandler(args)
+
+		otp, err := handler.TOTPFromBytes([]byte("legacy otp"), core.OTPParams{})
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, otp)
+	})
+	t.Run("invalid legacy url should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.LegacyOTPProvider = &testscommon.OTPProviderStub{
+			OTPFromBytesCalled: func(otpBytes []byte, params core.OTPParams) (handlers.OTP, error) {
+				return &testscommon.TotpStub{
+					UrlCalled: func() (string, error) {
+						return "otpauth://totp/MultiversX:account?secret=JBSWY3DPEHPK3PXP&digits=six", nil
+					},
+				}, nil
 			},
-			TOTPFromBytesCalled: func(encryptedMessage []byte) (handlers.OTP, error) {
-				wasTOTPFromBytesCalled = true
-				return &testscommon.TotpStub{}, nil
+		}
+		handler, _ := twofactor.NewTwoFactorHandler(args)
+
+		otp, err := handler.TOTPFromBytes([]byte("legacy otp"), core.OTPParams{})
+		assert.NotNil(t, err)
+		assert.Nil(t, otp)
+	})
+	t.Run("should convert the legacy otp", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Params.Skew = 2
+		args.LegacyOTPProvider = &testscommon.OTPProviderStub{
+			OTPFromBytesCalled: func(otpBytes []byte, params core.OTPParams) (handlers.OTP, error) {
+				assert.Equal(t, []byte("legacy otp"), otpBytes)
+				return &testscommon.TotpStub{
+					UrlCalled: func() (string, error) {
+						return "otpauth://totp/MultiversX:account?algorithm=SHA256&digits=7&issuer=MultiversX&period=30&secret=JBSWY3DPEHPK3PXP", nil
+					},
+				}, nil
 			},
-		}, crypto.SHA1)
-		assert.Nil(t, err)
-		assert.NotNil(t, handler)
+		}
+		expectedOTP := &testscommon.TotpStub{}
+		args.OTPProvider = &testscommon.OTPProviderStub{
+			OTPFromBytesCalled: func(otpBytes []byte, params core.OTPParams) (handlers.OTP, error) {
+				assert.Equal(t, []byte("Hello!\xde\xad\xbe\xef"), otpBytes)
+				assert.Equal(t, core.OTPParams{
+					Type:      core.TOTPType,
+					Algorithm: "SHA256",
+					Digits:    7,
+					Period:    30,
+					Skew:      2,
+				}, params)
+				return expectedOTP, nil
+			},
+		}
+		handler, _ := twofactor.NewTwoFactorHandler(args)
 
-		totp, err := handler.CreateTOTP("account")
+		otp, err := handler.TOTPFromBytes([]byte("legacy otp"), core.OTPParams{})
 		assert.Nil(t, err)
-		assert.Equal(t, "*testscommon.TotpStub", fmt.Sprintf("%T", totp))
-		assert.True(t, wasGenerateTOTPCalled)
+		assert.True(t, expectedOTP == otp)
+	})
+	t.Run("converted sec51 otp should validate its codes only once", func(t *testing.T) {
+		t.Parallel()
 
-		totpFromBytes, err := handler.TOTPFromBytes([]byte(""))
-		assert.Nil(t, err)
-		assert.Equal(t, "*testscommon.TotpStub", fmt.Sprintf("%T", totpFromBytes))
-		assert.True(t, wasTOTPFromBytesCalled)
+		args := createMockArgs()
+		args.OTPProvider, _ = rfc.NewOTPProvider("MultiversX", 1)
+		args.LegacyOTPProvider = sec51.NewSec51Wrapper("MultiversX")
+		handler, _ := twofactor.NewTwoFactorHandler(args)
+
+		legacyOTP, err := args.LegacyOTPProvider.GenerateOTP("account", args.Params)
+		require.Nil(t, err)
+		legacyOTPBytes, err := legacyOTP.ToBytes()
+		require.Nil(t, err)
+		code, err := legacyOTP.OTP()
+		require.Nil(t, err)
+
+		otp, err := handler.TOTPFromBytes(legacyOTPBytes, core.OTPParams{})
+		require.Nil(t, err)
+		assert.Nil(t, otp.Validate(code))
+		assert.Equal(t, handlers.ErrWrongCode, otp.Validate(code))
+
+		params := otp.Params()
+		assert.Equal(t, core.TOTPType, params.Type)
+		assert.NotZero(t, params.Counter)
+
+		newOTPBytes, err := otp.ToBytes()
+		require.Nil(t, err)
+		otp, err = handler.TOTPFromBytes(newOTPBytes, params)
+		require.Nil(t, err)
+		assert.Equal(t, handlers.ErrWrongCode, otp.Validate(code))
 	})
 }
@@ -0,0 +1,12 @@
+package txpolicy
+
+import "errors"
+
+// ErrNilRedisStorer signals that a nil redis storer was provided
+var ErrNilRedisStorer = errors.New("nil redis storer")
+
+// ErrInvalidSpentAmount signals that an invalid spent amount was found in storage
+var ErrInvalidSpentAmount = errors.New("invalid spent amount")
+
+// ErrInvalidOperationTimeout signals that an invalid operation timeout was provided
+var ErrInvalidOperationTimeout = errors.New("invalid operation timeout")
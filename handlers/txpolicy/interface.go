@@ -0,0 +1,12 @@
+package txpolicy
+
+import (
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/txdecoder"
+)
+
+type txDecoder interface {
+	Decode(tx transaction.FrontendTransaction) (*txdecoder.DecodedTransaction, error)
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,110 @@
+package txpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/redis"
+)
+
+const (
+	secondsInDay        = 24 * 60 * 60
+	dailySpendKeyPrefix = "txpolicy:daily:"
+	dailySpendTTL       = 2 * secondsInDay * time.Second
+)
+
+// spendTracker keeps, for each user, the amounts of each token co-signed during the current UTC day.
+// The amounts are kept in the redis storer, so they are persisted and shared between the instances of the service
+type spendTracker struct {
+	storer           redis.RedisStorer
+	operationTimeout time.Duration
+	getTimeHandler   func() time.Time
+}
+
+func newSpendTracker(storer redis.RedisStorer, operationTimeout time.Duration) *spendTracker {
+	return &spendTracker{
+		storer:           storer,
+		operationTimeout: operationTimeout,
+		getTimeHandler:   time.Now,
+	}
+}
+
+// spentToday returns the amounts co-signed today for the provided user
+func (tracker *spendTracker) spentToday(userAddress string) (map[string]*big.Int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), tracker.operationTimeout)
+	defer cancel()
+
+	value, err := tracker.storer.GetValue(ctx, tracker.createKey(userAddress))
+	if errors.Is(err, redis.ErrKeyNotExists) {
+		return make(map[string]*big.Int), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSpentAmounts(value)
+}
+
+// update passes the amounts co-signed today for the provided user to the update handler and saves them afterwards.
+// The whole update is atomic, so concurrent requests of the same user, even on different instances, cannot
+// exceed the limits together. Nothing is saved if the update handler returns an error
+func (tracker *spendTracker) update(userAddress string, updateHandler func(spent map[string]*big.Int) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), tracker.operationTimeout)
+	defer cancel()
+
+	return tracker.storer.UpdateValue(ctx, tracker.createKey(userAddress), dailySpendTTL, func(value []byte) ([]byte, error) {
+		spent, err := decodeSpentAmounts(value)
+		if err != nil {
+			return nil, err
+		}
+
+		err = updateHandler(spent)
+		if err != nil {
+			return nil, err
+		}
+
+		return encodeSpentAmounts(spent)
+	})
+}
+
+// createKey returns the key of the user for the current UTC day, so the amounts are reset once the day changes
+func (tracker *spendTracker) createKey(userAddress string) string {
+	day := tracker.getTimeHandler().Unix() / secondsInDay
+	return fmt.Sprintf("%s%d:%s", dailySpendKeyPrefix, day, userAddress)
+}
+
+func decodeSpentAmounts(value []byte) (map[string]*big.Int, error) {
+	spent := make(map[string]*big.Int)
+	if len(value) == 0 {
+		return spent, nil
+	}
+
+	encodedAmounts := make(map[string]string)
+	err := json.Unmarshal(value, &encodedAmounts)
+	if err != nil {
+		return nil, err
+	}
+
+	for token, encodedAmount := range encodedAmounts {
+		amount, ok := big.NewInt(0).SetString(encodedAmount, 10)
+		if !ok {
+			return nil, fmt.Errorf("%w, invalid daily spend %s of %s", ErrInvalidSpentAmount, encodedAmount, token)
+		}
+		spent[token] = amount
+	}
+
+	return spent, nil
+}
+
+func encodeSpentAmounts(spent map[string]*big.Int) ([]byte, error) {
+	encodedAmounts := make(map[string]string, len(spent))
+	for token, amount := range spent {
+		encodedAmounts[token] = amount.String()
+	}
+
+	return json.Marshal(encodedAmounts)
+}
@@ -0,0 +1,337 @@
+package txpolicy
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	logger "github.com/multiversx/mx-chain-logger-go"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/txdecoder"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/redis"
+)
+
+// multiTransferEGLDToken is the identifier used for the EGLD transfers made with MultiESDTNFTTransfer
+const multiTransferEGLDToken = "EGLD-000000"
+
+var log = logger.GetOrCreate("txpolicy")
+
+// ArgsTxPolicyHandler is the DTO used to create a new instance of txPolicyHandler
+type ArgsTxPolicyHandler struct {
+	Config                config.TxPolicyConfig
+	PubKeyConverter       core.PubkeyConverter
+	Storer                redis.RedisStorer
+	OperationTimeoutInSec uint64
+}
+
+// valueLimits holds the value rules applied on the transfers of one token
+type valueLimits struct {
+	maxTxValue             *big.Int
+	confirmationTxValue    *big.Int
+	maxDailySpend          *big.Int
+	confirmationDailySpend *big.Int
+}
+
+type txPolicyHandler struct {
+	enabled               bool
+	limits                map[string]*valueLimits
+	allowedReceivers      map[string]struct{}
+	deniedReceivers       map[string]struct{}
+	deniedFunctions       map[string]struct{}
+	confirmationFunctions map[string]struct{}
+	pubKeyConverter       core.PubkeyConverter
+	txDecoder             txDecoder
+	spendTracker          *spendTracker
+}
+
+// NewTxPolicyHandler returns a new instance of txPolicyHandler
+func NewTxPolicyHandler(args ArgsTxPolicyHandler) (*txPolicyHandler, error) {
+	if check.IfNil(args.PubKeyConverter) {
+		return nil, handlers.ErrNilPubKeyConverter
+	}
+	if check.IfNil(args.Storer) {
+		return nil, ErrNilRedisStorer
+	}
+	if args.OperationTimeoutInSec == 0 {
+		return nil, ErrInvalidOperationTimeout
+	}
+
+	handler := &txPolicyHandler{
+		enabled:               args.Config.Enabled,
+		limits:                make(map[string]*valueLimits, len(args.Config.TokenLimits)+1),
+		deniedFunctions:       sliceToSet(args.Config.DeniedFunctions),
+		confirmationFunctions: sliceToSet(args.Config.ConfirmationFunctions),
+		pubKeyConverter:       args.PubKeyConverter,
+		spendTracker:          newSpendTracker(args.Storer, time.Duration(args.OperationTimeoutInSec)*time.Second),
+	}
+
+	var err error
+	handler.txDecoder, err = txdecoder.NewTxDecoder(args.PubKeyConverter)
+	if err != nil {
+		return nil, err
+	}
+	handler.limits[txdecoder.EGLDToken], err = parseValueLimits(config.TokenTxPolicyConfig{
+		Token:                  txdecoder.EGLDToken,
+		MaxTxValue:             args.Config.MaxTxValue,
+		ConfirmationTxValue:    args.Config.ConfirmationTxValue,
+		MaxDailySpend:          args.Config.MaxDailySpend,
+		ConfirmationDailySpend: args.Config.ConfirmationDailySpend,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, tokenConfig := range args.Config.TokenLimits {
+		_, isDuplicate := handler.limits[tokenConfig.Token]
+		if len(tokenConfig.Token) == 0 || isDuplicate {
+			return nil, fmt.Errorf("%w for TokenLimits, invalid or duplicated token %s", handlers.ErrInvalidConfig, tokenConfig.Token)
+		}
+
+		handler.limits[tokenConfig.Token], err = parseValueLimits(tokenConfig)
+		if err != nil {
+			return nil, fmt.Errorf("%w of token %s", err, tokenConfig.Token)
+		}
+	}
+	handler.allowedReceivers, err = handler.decodeAddresses(args.Config.AllowedReceivers)
+	if err != nil {
+		return nil, fmt.Errorf("%w for AllowedReceivers", err)
+	}
+	handler.deniedReceivers, err = handler.decodeAddresses(args.Config.DeniedReceivers)
+	if err != nil {
+		return nil, fmt.Errorf("%w for DeniedReceivers", err)
+	}
+
+	return handler, nil
+}
+
+// CheckTransactions applies the configured policy on the provided transactions of the user, without recording them.
+// It returns an error if the transactions are rejected and true if they can only be signed
+// after an extra confirmation
+func (handler *txPolicyHandler) CheckTransactions(userAddress string, txs []transaction.FrontendTransaction) (bool, error) {
+	if !handler.enabled {
+		return false, nil
+	}
+
+	totalValues, confirmationRequired, err := handler.checkTransactions(txs)
+	if err != nil {
+		return false, err
+	}
+
+	spent, err := handler.spendTracker.spentToday(userAddress)
+	if err != nil {
+		return false, err
+	}
+
+	dailyNeedsConfirmation, err := handler.checkDailySpend(spent, totalValues)
+	if err != nil {
+		return false, err
+	}
+
+	return confirmationRequired || dailyNeedsConfirmation, nil
+}
+
+// CheckAndRecordTransactions applies the configured policy on the provided transactions of the user and adds them
+// to the user's daily spend, in one atomic update. It returns an error if the transactions are rejected, or if they
+// require an extra confirmation which was not provided, case in which nothing is recorded
+func (handler *txPolicyHandler) CheckAndRecordTransactions(userAddress string, txs []transaction.FrontendTransaction, isConfirmed bool) error {
+	if !handler.enabled {
+		return nil
+	}
+
+	totalValues, confirmationRequired, err := handler.checkTransactions(txs)
+	if err != nil {
+		return err
+	}
+	if confirmationRequired && !isConfirmed {
+		return handlers.ErrTxPolicyConfirmationRequired
+	}
+
+	return handler.spendTracker.update(userAddress, func(spent map[string]*big.Int) error {
+		dailyNeedsConfirmation, errCheck := handler.checkDailySpend(spent, totalValues)
+		if errCheck != nil {
+			return errCheck
+		}
+		if dailyNeedsConfirmation && !isConfirmed {
+			return handlers.ErrTxPolicyConfirmationRequired
+		}
+
+		for token, value := range totalValues {
+			spentAmount, found := spent[token]
+			if !found {
+				spentAmount = big.NewInt(0)
+				spent[token] = spentAmount
+			}
+			spentAmount.Add(spentAmount, value)
+		}
+
+		return nil
+	})
+}
+
+// checkTransactions checks each of the transactions, returning the total value of the tokens with limits
+// and whether any of the transactions requires confirmation
+func (handler *txPolicyHandler) checkTransactions(txs []transaction.FrontendTransaction) (map[string]*big.Int, bool, error) {
+	confirmationRequired := false
+	totalValues := make(map[string]*big.Int)
+	for index, tx := range txs {
+		values, txNeedsConfirmation, err := handler.checkTransaction(tx)
+		if err != nil {
+			return nil, false, fmt.Errorf("%w for transaction #%d", err, index)
+		}
+
+		confirmationRequired = confirmationRequired || txNeedsConfirmation
+		addValues(totalValues, values)
+	}
+
+	return totalValues, confirmationRequired, nil
+}
+
+func (handler *txPolicyHandler) checkTransaction(tx transaction.FrontendTransaction) (map[string]*big.Int, bool, error) {
+	decodedTx, err := handler.txDecoder.Decode(tx)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w, %s", handlers.ErrTxPolicyRejected, err.Error())
+	}
+
+	receiver := string(decodedTx.Receiver)
+	if _, isDenied := handler.deniedReceivers[receiver]; isDenied {
+		return nil, false, fmt.Errorf("%w, receiver %s is denied", handlers.ErrTxPolicyRejected, handler.pubKeyConverter.SilentEncode(decodedTx.Receiver, log))
+	}
+	if len(handler.allowedReceivers) > 0 {
+		if _, isAllowed := handler.allowedReceivers[receiver]; !isAllowed {
+			return nil, false, fmt.Errorf("%w, receiver %s is not allowed", handlers.ErrTxPolicyRejected, handler.pubKeyConverter.SilentEncode(decodedTx.Receiver, log))
+		}
+	}
+	if _, isDenied := handler.deniedFunctions[decodedTx.Function]; isDenied {
+		return nil, false, fmt.Errorf("%w, function %s is denied", handlers.ErrTxPolicyRejected, decodedTx.Function)
+	}
+
+	// the transfers of the same token are cumulated, so splitting a transfer cannot bypass the limits
+	values := make(map[string]*big.Int)
+	for _, transfer := range decodedTx.Transfers {
+		token := normalizeToken(transfer.Token)
+		if _, hasLimits := handler.limits[token]; hasLimits {
+			addValues(values, map[string]*big.Int{token: transfer.Amount})
+		}
+	}
+
+	_, confirmationRequired := handler.confirmationFunctions[decodedTx.Function]
+	for token, value := range values {
+		limits := handler.limits[token]
+		if limits.maxTxValue != nil && value.Cmp(limits.maxTxValue) > 0 {
+			return nil, false, fmt.Errorf("%w, value %s of %s exceeds the maximum allowed %s", handlers.ErrTxPolicyRejected, value.String(), token, limits.maxTxValue.String())
+		}
+
+		valueNeedsConfirmation := limits.confirmationTxValue != nil && value.Cmp(limits.confirmationTxValue) > 0
+		confirmationRequired = confirmationRequired || valueNeedsConfirmation
+	}
+
+	return values, confirmationRequired, nil
+}
+
+func (handler *txPolicyHandler) checkDailySpend(spent map[string]*big.Int, values map[string]*big.Int) (bool, error) {
+	confirmationRequired := false
+	for token, value := range values {
+		limits := handler.limits[token]
+		newDailySpend := big.NewInt(0).Set(value)
+		if spentAmount, found := spent[token]; found {
+			newDailySpend.Add(newDailySpend, spentAmount)
+		}
+
+		if limits.maxDailySpend != nil && newDailySpend.Cmp(limits.maxDailySpend) > 0 {
+			return false, fmt.Errorf("%w, daily spend %s of %s would exceed the maximum allowed %s", handlers.ErrTxPolicyRejected, newDailySpend.String(), token, limits.maxDailySpend.String())
+		}
+
+		dailyNeedsConfirmation := limits.confirmationDailySpend != nil && newDailySpend.Cmp(limits.confirmationDailySpend) > 0
+		confirmationRequired = confirmationRequired || dailyNeedsConfirmation
+	}
+
+	return confirmationRequired, nil
+}
+
+func (handler *txPolicyHandler) decodeAddresses(addresses []string) (map[string]struct{}, error) {
+	decodedAddresses := make(map[string]struct{}, len(addresses))
+	for _, address := range addresses {
+		addressBytes, err := handler.pubKeyConverter.Decode(address)
+		if err != nil {
+			return nil, fmt.Errorf("%w while decoding address %s", err, address)
+		}
+
+		decodedAddresses[string(addressBytes)] = struct{}{}
+	}
+
+	return decodedAddresses, nil
+}
+
+// normalizeToken returns the identifier of the native token for the EGLD transfers made with MultiESDTNFTTransfer
+func normalizeToken(token string) string {
+	if token == multiTransferEGLDToken {
+		return txdecoder.EGLDToken
+	}
+
+	return token
+}
+
+func addValues(destination map[string]*big.Int, values map[string]*big.Int) {
+	for token, value := range values {
+		total, found := destination[token]
+		if !found {
+			total = big.NewInt(0)
+			destination[token] = total
+		}
+		total.Add(total, value)
+	}
+}
+
+func parseValueLimits(tokenConfig config.TokenTxPolicyConfig) (*valueLimits, error) {
+	var err error
+	limits := &valueLimits{}
+	limits.maxTxValue, err = parseOptionalValue(tokenConfig.MaxTxValue, "MaxTxValue")
+	if err != nil {
+		return nil, err
+	}
+	limits.confirmationTxValue, err = parseOptionalValue(tokenConfig.ConfirmationTxValue, "ConfirmationTxValue")
+	if err != nil {
+		return nil, err
+	}
+	limits.maxDailySpend, err = parseOptionalValue(tokenConfig.MaxDailySpend, "MaxDailySpend")
+	if err != nil {
+		return nil, err
+	}
+	limits.confirmationDailySpend, err = parseOptionalValue(tokenConfig.ConfirmationDailySpend, "ConfirmationDailySpend")
+	if err != nil {
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+func parseOptionalValue(value string, name string) (*big.Int, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	parsedValue, ok := big.NewInt(0).SetString(value, 10)
+	if !ok || parsedValue.Sign() < 0 {
+		return nil, fmt.Errorf("%w for %s, received %s", handlers.ErrInvalidConfig, name, value)
+	}
+
+	return parsedValue, nil
+}
+
+func sliceToSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		set[value] = struct{}{}
+	}
+
+	return set
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (handler *txPolicyHandler) IsInterfaceNil() bool {
+	return handler == nil
+}
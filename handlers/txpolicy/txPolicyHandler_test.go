@@ -0,0 +1,435 @@
+package txpolicy
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/redis"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+const (
+	userAddress   = "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th"
+	receiver      = "erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx"
+	receiverHex   = "8049d639e5a6980d1cd2392abcce41029cda74a1563523a202f09641cc2618f8"
+	otherReceiver = "erd1k2s324ww2g0yj38qn2ch2jwctdy8mnfxep94q9arncc6xecg3xaq6mjse8"
+)
+
+func createMockArgs() ArgsTxPolicyHandler {
+	converter, _ := pubkeyConverter.NewBech32PubkeyConverter(32, "erd")
+	return ArgsTxPolicyHandler{
+		Config: config.TxPolicyConfig{
+			Enabled: true,
+		},
+		PubKeyConverter:       converter,
+		Storer:                redis.NewInMemoryStorer(),
+		OperationTimeoutInSec: 1,
+	}
+}
+
+func createTx(receiver string, value string, data string) transaction.FrontendTransaction {
+	return transaction.FrontendTransaction{
+		Sender:   userAddress,
+		Receiver: receiver,
+		Value:    value,
+		Data:     []byte(data),
+	}
+}
+
+func TestNewTxPolicyHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil pub key converter should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.PubKeyConverter = nil
+		handler, err := NewTxPolicyHandler(args)
+		require.Equal(t, handlers.ErrNilPubKeyConverter, err)
+		require.Nil(t, handler)
+	})
+	t.Run("nil storer should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Storer = nil
+		handler, err := NewTxPolicyHandler(args)
+		require.Equal(t, ErrNilRedisStorer, err)
+		require.Nil(t, handler)
+	})
+	t.Run("zero operation timeout should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.OperationTimeoutInSec = 0
+		handler, err := NewTxPolicyHandler(args)
+		require.Equal(t, ErrInvalidOperationTimeout, err)
+		require.Nil(t, handler)
+	})
+	t.Run("invalid token limit should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.TokenLimits = []config.TokenTxPolicyConfig{{Token: "TKN-123456", MaxDailySpend: "not a number"}}
+		handler, err := NewTxPolicyHandler(args)
+		require.True(t, errors.Is(err, handlers.ErrInvalidConfig))
+		require.True(t, strings.Contains(err.Error(), "TKN-123456"))
+		require.Nil(t, handler)
+	})
+	t.Run("duplicated token limit should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.TokenLimits = []config.TokenTxPolicyConfig{{Token: "EGLD", MaxTxValue: "1"}}
+		handler, err := NewTxPolicyHandler(args)
+		require.True(t, errors.Is(err, handlers.ErrInvalidConfig))
+		require.Nil(t, handler)
+	})
+	t.Run("invalid value should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.ConfirmationDailySpend = "not a number"
+		handler, err := NewTxPolicyHandler(args)
+		require.True(t, errors.Is(err, handlers.ErrInvalidConfig))
+		require.True(t, strings.Contains(err.Error(), "ConfirmationDailySpend"))
+		require.Nil(t, handler)
+	})
+	t.Run("negative value should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.MaxTxValue = "-1"
+		handler, err := NewTxPolicyHandler(args)
+		require.True(t, errors.Is(err, handlers.ErrInvalidConfig))
+		require.True(t, strings.Contains(err.Error(), "MaxTxValue"))
+		require.Nil(t, handler)
+	})
+	t.Run("invalid receiver should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.DeniedReceivers = []string{"invalid address"}
+		handler, err := NewTxPolicyHandler(args)
+		require.NotNil(t, err)
+		require.True(t, strings.Contains(err.Error(), "DeniedReceivers"))
+		require.Nil(t, handler)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		handler, err := NewTxPolicyHandler(createMockArgs())
+		require.Nil(t, err)
+		require.False(t, handler.IsInterfaceNil())
+	})
+}
+
+func TestTxPolicyHandler_CheckTransactions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("disabled policy should accept everything", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.Enabled = false
+		args.Config.MaxTxValue = "1"
+		handler, _ := NewTxPolicyHandler(args)
+
+		needsConfirmation, err := handler.CheckTransactions(userAddress, []transaction.FrontendTransaction{createTx(receiver, "100", "")})
+		require.Nil(t, err)
+		require.False(t, needsConfirmation)
+	})
+	t.Run("invalid tx value should reject", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewTxPolicyHandler(createMockArgs())
+
+		_, err := handler.CheckTransactions(userAddress, []transaction.FrontendTransaction{createTx(receiver, "-5", "")})
+		require.True(t, errors.Is(err, handlers.ErrTxPolicyRejected))
+	})
+	t.Run("value above max should reject", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.MaxTxValue = "100"
+		handler, _ := NewTxPolicyHandler(args)
+
+		txs := []transaction.FrontendTransaction{createTx(receiver, "100", ""), createTx(receiver, "101", "")}
+		_, err := handler.CheckTransactions(userAddress, txs)
+		require.True(t, errors.Is(err, handlers.ErrTxPolicyRejected))
+		require.True(t, strings.Contains(err.Error(), "transaction #1"))
+	})
+	t.Run("value above confirmation threshold should require confirmation", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.ConfirmationTxValue = "100"
+		handler, _ := NewTxPolicyHandler(args)
+
+		needsConfirmation, err := handler.CheckTransactions(userAddress, []transaction.FrontendTransaction{createTx(receiver, "100", "")})
+		require.Nil(t, err)
+		require.False(t, needsConfirmation)
+
+		needsConfirmation, err = handler.CheckTransactions(userAddress, []transaction.FrontendTransaction{createTx(receiver, "101", "")})
+		require.Nil(t, err)
+		require.True(t, needsConfirmation)
+	})
+	t.Run("denied receiver should reject", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.DeniedReceivers = []string{receiver}
+		handler, _ := NewTxPolicyHandler(args)
+
+		_, err := handler.CheckTransactions(userAddress, []transaction.FrontendTransaction{createTx(receiver, "1", "")})
+		require.True(t, errors.Is(err, handlers.ErrTxPolicyRejected))
+		require.True(t, strings.Contains(err.Error(), receiver))
+
+		_, err = handler.CheckTransactions(userAddress, []transaction.FrontendTransaction{createTx(otherReceiver, "1", "")})
+		require.Nil(t, err)
+	})
+	t.Run("denied receiver hidden in ESDTNFTTransfer should reject", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.DeniedReceivers = []string{receiver}
+		handler, _ := NewTxPolicyHandler(args)
+
+		data := "ESDTNFTTransfer@4e46542d313233343536@01@01@" + receiverHex
+		_, err := handler.CheckTransactions(userAddress, []transaction.FrontendTransaction{createTx(userAddress, "0", data)})
+		require.True(t, errors.Is(err, handlers.ErrTxPolicyRejected))
+	})
+	t.Run("receiver not in allow list should reject", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.AllowedReceivers = []string{receiver}
+		handler, _ := NewTxPolicyHandler(args)
+
+		_, err := handler.CheckTransactions(userAddress, []transaction.FrontendTransaction{createTx(receiver, "1", "")})
+		require.Nil(t, err)
+
+		_, err = handler.CheckTransactions(userAddress, []transaction.FrontendTransaction{createTx(otherReceiver, "1", "")})
+		require.True(t, errors.Is(err, handlers.ErrTxPolicyRejected))
+	})
+	t.Run("denied function should reject", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.DeniedFunctions = []string{"withdraw"}
+		handler, _ := NewTxPolicyHandler(args)
+
+		_, err := handler.CheckTransactions(userAddress, []transaction.FrontendTransaction{createTx(receiver, "0", "withdraw@01")})
+		require.True(t, errors.Is(err, handlers.ErrTxPolicyRejected))
+		require.True(t, strings.Contains(err.Error(), "withdraw"))
+	})
+	t.Run("denied function nested in MultiESDTNFTTransfer should reject", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.DeniedFunctions = []string{"withdraw"}
+		handler, _ := NewTxPolicyHandler(args)
+
+		data := "MultiESDTNFTTransfer@" + receiverHex + "@02@544b4e2d313233343536@@0a@4e46542d313233343536@01@01@" + hex.EncodeToString([]byte("withdraw"))
+		_, err := handler.CheckTransactions(userAddress, []transaction.FrontendTransaction{createTx(userAddress, "0", data)})
+		require.True(t, errors.Is(err, handlers.ErrTxPolicyRejected))
+	})
+	t.Run("malformed data field of built-in function should reject", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewTxPolicyHandler(createMockArgs())
+
+		_, err := handler.CheckTransactions(userAddress, []transaction.FrontendTransaction{createTx(receiver, "0", "ESDTTransfer@544b4e@0a@zz")})
+		require.True(t, errors.Is(err, handlers.ErrTxPolicyRejected))
+	})
+	t.Run("function nested in ESDTTransfer should require confirmation", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.ConfirmationFunctions = []string{"stake"}
+		handler, _ := NewTxPolicyHandler(args)
+
+		data := "ESDTTransfer@544b4e2d313233343536@0a@" + hex.EncodeToString([]byte("stake"))
+		needsConfirmation, err := handler.CheckTransactions(userAddress, []transaction.FrontendTransaction{createTx(receiver, "0", data)})
+		require.Nil(t, err)
+		require.True(t, needsConfirmation)
+	})
+	t.Run("token limits should apply on the ESDT transfers", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.MaxTxValue = "5"
+		args.Config.TokenLimits = []config.TokenTxPolicyConfig{{Token: "TKN-123456", MaxTxValue: "20", ConfirmationTxValue: "10"}}
+		handler, _ := NewTxPolicyHandler(args)
+
+		data := "ESDTTransfer@544b4e2d313233343536@0a"
+		needsConfirmation, err := handler.CheckTransactions(userAddress, []transaction.FrontendTransaction{createTx(receiver, "0", data)})
+		require.Nil(t, err)
+		require.False(t, needsConfirmation)
+
+		data = "MultiESDTNFTTransfer@" + receiverHex + "@02@544b4e2d313233343536@@0a@544b4e2d313233343536@@01"
+		needsConfirmation, err = handler.CheckTransactions(userAddress, []transaction.FrontendTransaction{createTx(userAddress, "0", data)})
+		require.Nil(t, err)
+		require.True(t, needsConfirmation)
+
+		data = "ESDTTransfer@544b4e2d313233343536@15"
+		_, err = handler.CheckTransactions(userAddress, []transaction.FrontendTransaction{createTx(receiver, "0", data)})
+		require.True(t, errors.Is(err, handlers.ErrTxPolicyRejected))
+		require.True(t, strings.Contains(err.Error(), "TKN-123456"))
+
+		data = "ESDTTransfer@4f544845522d313233343536@15"
+		_, err = handler.CheckTransactions(userAddress, []transaction.FrontendTransaction{createTx(receiver, "0", data)})
+		require.Nil(t, err)
+	})
+	t.Run("EGLD transferred with MultiESDTNFTTransfer should count as EGLD", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.MaxTxValue = "5"
+		handler, _ := NewTxPolicyHandler(args)
+
+		data := "MultiESDTNFTTransfer@" + receiverHex + "@01@" + hex.EncodeToString([]byte(multiTransferEGLDToken)) + "@@06"
+		_, err := handler.CheckTransactions(userAddress, []transaction.FrontendTransaction{createTx(userAddress, "0", data)})
+		require.True(t, errors.Is(err, handlers.ErrTxPolicyRejected))
+	})
+	t.Run("storer error should error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		args := createMockArgs()
+		args.Config.MaxDailySpend = "200"
+		args.Storer = &testscommon.RedisClientStub{
+			GetValueCalled: func(ctx context.Context, key string) ([]byte, error) {
+				return nil, expectedErr
+			},
+		}
+		handler, _ := NewTxPolicyHandler(args)
+
+		_, err := handler.CheckTransactions(userAddress, []transaction.FrontendTransaction{createTx(receiver, "1", "")})
+		require.Equal(t, expectedErr, err)
+	})
+}
+
+func TestTxPolicyHandler_CheckAndRecordTransactions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("disabled policy should not record", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.Enabled = false
+		args.Storer = &testscommon.RedisClientStub{
+			UpdateValueCalled: func(ctx context.Context, key string, ttl time.Duration, updateHandler func(value []byte) ([]byte, error)) error {
+				require.Fail(t, "should have not been called")
+				return nil
+			},
+		}
+		handler, _ := NewTxPolicyHandler(args)
+
+		err := handler.CheckAndRecordTransactions(userAddress, []transaction.FrontendTransaction{createTx(receiver, "100", "")}, false)
+		require.Nil(t, err)
+	})
+	t.Run("unconfirmed transaction requiring confirmation should not be recorded", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.ConfirmationTxValue = "100"
+		args.Config.MaxDailySpend = "1000"
+		handler, _ := NewTxPolicyHandler(args)
+
+		txs := []transaction.FrontendTransaction{createTx(receiver, "101", "")}
+		err := handler.CheckAndRecordTransactions(userAddress, txs, false)
+		require.Equal(t, handlers.ErrTxPolicyConfirmationRequired, err)
+
+		spent, err := handler.spendTracker.spentToday(userAddress)
+		require.Nil(t, err)
+		require.Empty(t, spent)
+
+		err = handler.CheckAndRecordTransactions(userAddress, txs, true)
+		require.Nil(t, err)
+
+		spent, err = handler.spendTracker.spentToday(userAddress)
+		require.Nil(t, err)
+		require.Equal(t, map[string]*big.Int{"EGLD": big.NewInt(101)}, spent)
+	})
+	t.Run("daily spend limits should apply on the cumulated value", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.ConfirmationDailySpend = "100"
+		args.Config.MaxDailySpend = "200"
+		handler, _ := NewTxPolicyHandler(args)
+		currentTime := time.Unix(1000*secondsInDay, 0)
+		handler.spendTracker.getTimeHandler = func() time.Time {
+			return currentTime
+		}
+
+		txs := []transaction.FrontendTransaction{createTx(receiver, "60", ""), createTx(receiver, "40", "")}
+		needsConfirmation, err := handler.CheckTransactions(userAddress, txs)
+		require.Nil(t, err)
+		require.False(t, needsConfirmation)
+		err = handler.CheckAndRecordTransactions(userAddress, txs, false)
+		require.Nil(t, err)
+
+		txs = []transaction.FrontendTransaction{createTx(receiver, "1", "")}
+		needsConfirmation, err = handler.CheckTransactions(userAddress, txs)
+		require.Nil(t, err)
+		require.True(t, needsConfirmation)
+		err = handler.CheckAndRecordTransactions(userAddress, txs, false)
+		require.Equal(t, handlers.ErrTxPolicyConfirmationRequired, err)
+
+		needsConfirmation, err = handler.CheckTransactions(otherReceiver, txs)
+		require.Nil(t, err)
+		require.False(t, needsConfirmation)
+
+		err = handler.CheckAndRecordTransactions(userAddress, []transaction.FrontendTransaction{createTx(receiver, "100", "")}, true)
+		require.Nil(t, err)
+		_, err = handler.CheckTransactions(userAddress, txs)
+		require.True(t, errors.Is(err, handlers.ErrTxPolicyRejected))
+		err = handler.CheckAndRecordTransactions(userAddress, txs, true)
+		require.True(t, errors.Is(err, handlers.ErrTxPolicyRejected))
+
+		currentTime = currentTime.Add(24 * time.Hour)
+		needsConfirmation, err = handler.CheckTransactions(userAddress, txs)
+		require.Nil(t, err)
+		require.False(t, needsConfirmation)
+	})
+	t.Run("concurrent requests should not exceed the daily spend together", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.MaxDailySpend = "100"
+		handler, _ := NewTxPolicyHandler(args)
+
+		numCalls := 20
+		numAccepted := uint32(0)
+		wg := sync.WaitGroup{}
+		wg.Add(numCalls)
+		for i := 0; i < numCalls; i++ {
+			go func() {
+				defer wg.Done()
+
+				err := handler.CheckAndRecordTransactions(userAddress, []transaction.FrontendTransaction{createTx(receiver, "10", "")}, false)
+				if err == nil {
+					atomic.AddUint32(&numAccepted, 1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		require.Equal(t, uint32(10), atomic.LoadUint32(&numAccepted))
+	})
+}
@@ -0,0 +1,9 @@
+package webauthn
+
+// ChallengeEncryptor defines the methods used to seal the challenges, so they can be checked without being stored
+type ChallengeEncryptor interface {
+	CurrentKeyID() uint32
+	EncryptData(data []byte, associatedData []byte) ([]byte, error)
+	DecryptData(keyID uint32, data []byte, associatedData []byte) ([]byte, error)
+	IsInterfaceNil() bool
+}
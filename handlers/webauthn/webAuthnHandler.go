@@ -0,0 +1,441 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+)
+
+const (
+	challengeKeyIDLength       = 4
+	challengeTimestampLength   = 8
+	challengeAssociatedDataTag = "webauthn challenge"
+	minChallengeTimeoutInSec   = 1
+
+	rpIDHashLength             = 32
+	flagsIndex                 = 32
+	signCountIndex             = 33
+	minAuthenticatorDataLength = 37
+	aaguidLength               = 16
+	credentialIDLengthSize     = 2
+	maxCredentialIDLength      = 1023
+
+	flagUserPresent            = 0x01
+	flagUserVerified           = 0x04
+	flagAttestedCredentialData = 0x40
+
+	clientDataTypeCreate  = "webauthn.create"
+	clientDataTypeGet     = "webauthn.get"
+	attestationFormatNone = "none"
+
+	coseKeyTypeEC2       = int64(2)
+	coseAlgorithmES256   = int64(-7)
+	coseCurveP256        = int64(1)
+	p256CoordinateLength = 32
+	uncompressedPointTag = 0x04
+)
+
+// ArgsWebAuthnHandler is the DTO used to create a new instance of webAuthnHandler
+type ArgsWebAuthnHandler struct {
+	Config    config.WebAuthnConfig
+	Encryptor ChallengeEncryptor
+}
+
+type collectedClientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+type attestationObject struct {
+	Format   string          `cbor:"fmt"`
+	AttStmt  cbor.RawMessage `cbor:"attStmt"`
+	AuthData []byte          `cbor:"authData"`
+}
+
+type coseKey struct {
+	KeyType     int64  `cbor:"1,keyasint"`
+	Algorithm   int64  `cbor:"3,keyasint"`
+	Curve       int64  `cbor:"-1,keyasint"`
+	XCoordinate []byte `cbor:"-2,keyasint"`
+	YCoordinate []byte `cbor:"-3,keyasint"`
+}
+
+type authenticatorData struct {
+	flags                  byte
+	signCount              uint32
+	attestedCredentialData []byte
+}
+
+type webAuthnHandler struct {
+	rpIDHash                []byte
+	origins                 map[string]struct{}
+	challengeTimeout        time.Duration
+	requireUserVerification bool
+	encryptor               ChallengeEncryptor
+	getTimeHandler          func() time.Time
+}
+
+// NewWebAuthnHandler returns a new instance of webAuthnHandler which accepts ES256 credentials
+// registered with the "none" attestation format
+func NewWebAuthnHandler(args ArgsWebAuthnHandler) (*webAuthnHandler, error) {
+	err := checkArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	origins := make(map[string]struct{}, len(args.Config.RPOrigins))
+	for _, origin := range args.Config.RPOrigins {
+		origins[origin] = struct{}{}
+	}
+	rpIDHash := sha256.Sum256([]byte(args.Config.RPID))
+
+	return &webAuthnHandler{
+		rpIDHash:                rpIDHash[:],
+		origins:                 origins,
+		challengeTimeout:        time.Duration(args.Config.ChallengeTimeoutInSec) * time.Second,
+		requireUserVerification: args.Config.RequireUserVerification,
+		encryptor:               args.Encryptor,
+		getTimeHandler:          time.Now,
+	}, nil
+}
+
+func checkArgs(args ArgsWebAuthnHandler) error {
+	if len(args.Config.RPID) == 0 {
+		return fmt.Errorf("%w, empty RPID", handlers.ErrInvalidConfig)
+	}
+	if len(args.Config.RPOrigins) == 0 {
+		return fmt.Errorf("%w, empty RPOrigins", handlers.ErrInvalidConfig)
+	}
+	if args.Config.ChallengeTimeoutInSec < minChallengeTimeoutInSec {
+		return fmt.Errorf("%w for ChallengeTimeoutInSec, got %d, min expected %d",
+			handlers.ErrInvalidConfig, args.Config.ChallengeTimeoutInSec, minChallengeTimeoutInSec)
+	}
+	if check.IfNil(args.Encryptor) {
+		return handlers.ErrNilEncryptor
+	}
+
+	return nil
+}
+
+// CreateChallenge returns a new challenge bound to the provided data. The challenge holds its sealed issue time,
+// so nothing needs to be stored until it is used and any number of challenges can be outstanding at once
+func (handler *webAuthnHandler) CreateChallenge(binding []byte) ([]byte, error) {
+	timestamp := make([]byte, challengeTimestampLength)
+	binary.BigEndian.PutUint64(timestamp, uint64(handler.getTimeHandler().UnixNano()))
+
+	sealedTimestamp, err := handler.encryptor.EncryptData(timestamp, createChallengeAssociatedData(binding))
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := make([]byte, challengeKeyIDLength, challengeKeyIDLength+len(sealedTimestamp))
+	binary.BigEndian.PutUint32(challenge, handler.encryptor.CurrentKeyID())
+
+	return append(challenge, sealedTimestamp...), nil
+}
+
+// VerifyRegistration verifies the attestation of a new credential against a challenge created for the provided
+// binding and returns the new credential
+func (handler *webAuthnHandler) VerifyRegistration(credential core.WebAuthnInfo, binding []byte, registration requests.WebAuthnRegistration) (*core.WebAuthnInfo, error) {
+	_, challengeTimestamp, err := handler.checkClientData(registration.ClientDataJSON, clientDataTypeCreate, credential, binding)
+	if err != nil {
+		return nil, err
+	}
+
+	attestationObjectBytes, err := decodeBase64URL(registration.AttestationObject, "attestation object")
+	if err != nil {
+		return nil, err
+	}
+
+	attestation, err := parseAttestationObject(attestationObjectBytes)
+	if err != nil {
+		return nil, err
+	}
+	// the guardian does not need to trust the authenticator model, so no attestation statement is verified
+	if attestation.Format != attestationFormatNone {
+		return nil, fmt.Errorf("%w, unsupported attestation format %s", handlers.ErrInvalidWebAuthnResponse, attestation.Format)
+	}
+
+	authData, err := handler.parseAuthenticatorData(attestation.AuthData)
+	if err != nil {
+		return nil, err
+	}
+	if authData.flags&flagAttestedCredentialData == 0 {
+		return nil, fmt.Errorf("%w, missing attested credential data", handlers.ErrInvalidWebAuthnResponse)
+	}
+
+	credentialID, publicKey, err := parseAttestedCredentialData(authData.attestedCredentialData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &core.WebAuthnInfo{
+		CredentialID:           credentialID,
+		PublicKey:              publicKey,
+		SignCount:              authData.signCount,
+		LastChallengeTimestamp: challengeTimestamp,
+	}, nil
+}
+
+// VerifyAssertion verifies the assertion against the credential and a challenge created for the provided binding,
+// returning the credential updated with the new signature counter and the consumed challenge
+func (handler *webAuthnHandler) VerifyAssertion(credential core.WebAuthnInfo, binding []byte, assertion requests.WebAuthnAssertion) (*core.WebAuthnInfo, error) {
+	credentialID, err := decodeBase64URL(assertion.CredentialID, "credential id")
+	if err != nil {
+		return nil, err
+	}
+	if len(credential.PublicKey) == 0 || !bytes.Equal(credentialID, credential.CredentialID) {
+		return nil, fmt.Errorf("%w, unknown credential", handlers.ErrInvalidWebAuthnResponse)
+	}
+
+	clientDataJSON, challengeTimestamp, err := handler.checkClientData(assertion.ClientDataJSON, clientDataTypeGet, credential, binding)
+	if err != nil {
+		return nil, err
+	}
+
+	authDataBytes, err := decodeBase64URL(assertion.AuthenticatorData, "authenticator data")
+	if err != nil {
+		return nil, err
+	}
+
+	authData, err := handler.parseAuthenticatorData(authDataBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// a counter which does not increase means that the authenticator might have been cloned.
+	// Authenticators which do not implement a counter always return 0
+	counterSupported := authData.signCount != 0 || credential.SignCount != 0
+	if counterSupported && authData.signCount <= credential.SignCount {
+		return nil, fmt.Errorf("%w, signature counter did not increase", handlers.ErrInvalidWebAuthnResponse)
+	}
+
+	signature, err := decodeBase64URL(assertion.Signature, "signature")
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := unmarshalPublicKey(credential.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signedData := make([]byte, 0, len(authDataBytes)+len(clientDataHash))
+	signedData = append(signedData, authDataBytes...)
+	signedData = append(signedData, clientDataHash[:]...)
+	signedDataHash := sha256.Sum256(signedData)
+	if !ecdsa.VerifyASN1(publicKey, signedDataHash[:], signature) {
+		return nil, fmt.Errorf("%w, invalid signature", handlers.ErrInvalidWebAuthnResponse)
+	}
+
+	credential.SignCount = authData.signCount
+	credential.LastChallengeTimestamp = challengeTimestamp
+
+	return &credential, nil
+}
+
+// checkClientData checks the client data and its challenge, returning the raw client data and the issue time of the challenge
+func (handler *webAuthnHandler) checkClientData(
+	encodedClientData string,
+	expectedType string,
+	credential core.WebAuthnInfo,
+	binding []byte,
+) ([]byte, int64, error) {
+	clientDataJSON, err := decodeBase64URL(encodedClientData, "client data")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	clientData := &collectedClientData{}
+	err = json.Unmarshal(clientDataJSON, clientData)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w, invalid client data: %s", handlers.ErrInvalidWebAuthnResponse, err.Error())
+	}
+
+	if clientData.Type != expectedType {
+		return nil, 0, fmt.Errorf("%w, unexpected client data type %s", handlers.ErrInvalidWebAuthnResponse, clientData.Type)
+	}
+
+	_, isAllowedOrigin := handler.origins[clientData.Origin]
+	if !isAllowedOrigin {
+		return nil, 0, fmt.Errorf("%w, origin %s not allowed", handlers.ErrInvalidWebAuthnResponse, clientData.Origin)
+	}
+
+	challenge, err := decodeBase64URL(clientData.Challenge, "challenge")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	challengeTimestamp, err := handler.openChallenge(challenge, binding)
+	if err != nil {
+		return nil, 0, err
+	}
+	// challenges are consumed in order, so a challenge older than the last consumed one cannot be replayed
+	if challengeTimestamp <= credential.LastChallengeTimestamp {
+		return nil, 0, fmt.Errorf("%w, challenge already used", handlers.ErrInvalidWebAuthnResponse)
+	}
+	challengeAge := handler.getTimeHandler().Sub(time.Unix(0, challengeTimestamp))
+	if challengeAge > handler.challengeTimeout {
+		return nil, 0, fmt.Errorf("%w, challenge expired", handlers.ErrInvalidWebAuthnResponse)
+	}
+
+	return clientDataJSON, challengeTimestamp, nil
+}
+
+// openChallenge returns the issue time of a challenge created for the provided binding
+func (handler *webAuthnHandler) openChallenge(challenge []byte, binding []byte) (int64, error) {
+	if len(challenge) <= challengeKeyIDLength {
+		return 0, fmt.Errorf("%w, challenge mismatch", handlers.ErrInvalidWebAuthnResponse)
+	}
+
+	keyID := binary.BigEndian.Uint32(challenge[:challengeKeyIDLength])
+	timestamp, err := handler.encryptor.DecryptData(keyID, challenge[challengeKeyIDLength:], createChallengeAssociatedData(binding))
+	if err != nil || len(timestamp) != challengeTimestampLength {
+		return 0, fmt.Errorf("%w, challenge mismatch", handlers.ErrInvalidWebAuthnResponse)
+	}
+
+	return int64(binary.BigEndian.Uint64(timestamp)), nil
+}
+
+func createChallengeAssociatedData(binding []byte) []byte {
+	associatedData := make([]byte, 0, len(challengeAssociatedDataTag)+len(binding))
+	associatedData = append(associatedData, challengeAssociatedDataTag...)
+	return append(associatedData, binding...)
+}
+
+func (handler *webAuthnHandler) parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < minAuthenticatorDataLength {
+		return nil, fmt.Errorf("%w, authenticator data too short", handlers.ErrInvalidWebAuthnResponse)
+	}
+	if !bytes.Equal(data[:rpIDHashLength], handler.rpIDHash) {
+		return nil, fmt.Errorf("%w, relying party mismatch", handlers.ErrInvalidWebAuthnResponse)
+	}
+
+	flags := data[flagsIndex]
+	if flags&flagUserPresent == 0 {
+		return nil, fmt.Errorf("%w, user not present", handlers.ErrInvalidWebAuthnResponse)
+	}
+	if handler.requireUserVerification && flags&flagUserVerified == 0 {
+		return nil, fmt.Errorf("%w, user not verified", handlers.ErrInvalidWebAuthnResponse)
+	}
+
+	return &authenticatorData{
+		flags:                  flags,
+		signCount:              binary.BigEndian.Uint32(data[signCountIndex:minAuthenticatorDataLength]),
+		attestedCredentialData: data[minAuthenticatorDataLength:],
+	}, nil
+}
+
+func parseAttestationObject(data []byte) (*attestationObject, error) {
+	attestation := &attestationObject{}
+	err := cbor.Unmarshal(data, attestation)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %s", handlers.ErrInvalidWebAuthnResponse, err.Error())
+	}
+	if len(attestation.Format) == 0 {
+		return nil, fmt.Errorf("%w, missing attestation format", handlers.ErrInvalidWebAuthnResponse)
+	}
+	if len(attestation.AuthData) == 0 {
+		return nil, fmt.Errorf("%w, missing authenticator data", handlers.ErrInvalidWebAuthnResponse)
+	}
+
+	return attestation, nil
+}
+
+func parseAttestedCredentialData(data []byte) ([]byte, []byte, error) {
+	credentialIDOffset := aaguidLength + credentialIDLengthSize
+	if len(data) < credentialIDOffset {
+		return nil, nil, fmt.Errorf("%w, attested credential data too short", handlers.ErrInvalidWebAuthnResponse)
+	}
+
+	credentialIDLength := int(binary.BigEndian.Uint16(data[aaguidLength:credentialIDOffset]))
+	publicKeyOffset := credentialIDOffset + credentialIDLength
+	if credentialIDLength == 0 || credentialIDLength > maxCredentialIDLength || len(data) < publicKeyOffset {
+		return nil, nil, fmt.Errorf("%w, invalid credential id length %d", handlers.ErrInvalidWebAuthnResponse, credentialIDLength)
+	}
+
+	// the COSE key might be followed by the extensions, which are ignored
+	key := &coseKey{}
+	err := cbor.NewDecoder(bytes.NewReader(data[publicKeyOffset:])).Decode(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w, %s", handlers.ErrInvalidWebAuthnResponse, err.Error())
+	}
+
+	publicKey, err := parseCOSEKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	credentialID := make([]byte, credentialIDLength)
+	copy(credentialID, data[credentialIDOffset:publicKeyOffset])
+
+	return credentialID, publicKey, nil
+}
+
+// parseCOSEKey returns the uncompressed P-256 point of an ES256 COSE key
+func parseCOSEKey(key *coseKey) ([]byte, error) {
+	if key.KeyType != coseKeyTypeEC2 ||
+		key.Algorithm != coseAlgorithmES256 ||
+		key.Curve != coseCurveP256 {
+		return nil, fmt.Errorf("%w, unsupported public key, only ES256 is accepted", handlers.ErrInvalidWebAuthnResponse)
+	}
+	if len(key.XCoordinate) != p256CoordinateLength || len(key.YCoordinate) != p256CoordinateLength {
+		return nil, fmt.Errorf("%w, invalid public key coordinates", handlers.ErrInvalidWebAuthnResponse)
+	}
+
+	publicKey := make([]byte, 0, 1+2*p256CoordinateLength)
+	publicKey = append(publicKey, uncompressedPointTag)
+	publicKey = append(publicKey, key.XCoordinate...)
+	publicKey = append(publicKey, key.YCoordinate...)
+
+	_, err := unmarshalPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return publicKey, nil
+}
+
+func unmarshalPublicKey(publicKey []byte) (*ecdsa.PublicKey, error) {
+	x, y := elliptic.Unmarshal(elliptic.P256(), publicKey)
+	if x == nil {
+		return nil, fmt.Errorf("%w, public key is not on the P-256 curve", handlers.ErrInvalidWebAuthnResponse)
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     x,
+		Y:     y,
+	}, nil
+}
+
+// decodeBase64URL decodes base64url values, with or without padding, as sent by the browsers
+func decodeBase64URL(value string, name string) ([]byte, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, fmt.Errorf("%w, invalid %s encoding: %s", handlers.ErrInvalidWebAuthnResponse, name, err.Error())
+	}
+
+	return decoded, nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (handler *webAuthnHandler) IsInterfaceNil() bool {
+	return handler == nil
+}
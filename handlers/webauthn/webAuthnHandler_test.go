@@ -0,0 +1,659 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	factoryMarshaller "github.com/multiversx/mx-chain-core-go/marshal/factory"
+	crypto "github.com/multiversx/mx-chain-crypto-go"
+	"github.com/multiversx/mx-chain-crypto-go/signing"
+	"github.com/multiversx/mx-chain-crypto-go/signing/ed25519"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/encryption"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+const (
+	testRPID   = "multiversx.com"
+	testOrigin = "https://wallet.multiversx.com"
+)
+
+var (
+	currentTimestamp = time.Unix(1700000000, 0)
+	testBinding      = []byte("user, guardian and payload digest")
+)
+
+// softwareAuthenticator mimics a WebAuthn authenticator, producing "none" attestations and ES256 assertions
+type softwareAuthenticator struct {
+	privateKey   *ecdsa.PrivateKey
+	credentialID []byte
+	rpID         string
+	origin       string
+	flags        byte
+	signCount    uint32
+}
+
+func newSoftwareAuthenticator(t *testing.T) *softwareAuthenticator {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+
+	return &softwareAuthenticator{
+		privateKey:   privateKey,
+		credentialID: []byte("software credential id"),
+		rpID:         testRPID,
+		origin:       testOrigin,
+		flags:        flagUserPresent | flagUserVerified,
+	}
+}
+
+func (authenticator *softwareAuthenticator) clientData(clientDataType string, challenge []byte) []byte {
+	clientData, _ := json.Marshal(&collectedClientData{
+		Type:      clientDataType,
+		Challenge: base64.RawURLEncoding.EncodeToString(challenge),
+		Origin:    authenticator.origin,
+	})
+
+	return clientData
+}
+
+func (authenticator *softwareAuthenticator) authenticatorData(flags byte, attestedCredentialData []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(authenticator.rpID))
+	authData := append([]byte{}, rpIDHash[:]...)
+	authData = append(authData, flags)
+	authData = appendUint32(authData, authenticator.signCount)
+
+	return append(authData, attestedCredentialData...)
+}
+
+func (authenticator *softwareAuthenticator) coseKey() *coseKey {
+	x := make([]byte, p256CoordinateLength)
+	y := make([]byte, p256CoordinateLength)
+	authenticator.privateKey.X.FillBytes(x)
+	authenticator.privateKey.Y.FillBytes(y)
+
+	return &coseKey{
+		KeyType:     coseKeyTypeEC2,
+		Algorithm:   coseAlgorithmES256,
+		Curve:       coseCurveP256,
+		XCoordinate: x,
+		YCoordinate: y,
+	}
+}
+
+func (authenticator *softwareAuthenticator) register(t *testing.T, challenge []byte, key *coseKey) requests.WebAuthnRegistration {
+	encodedKey, err := cbor.Marshal(key)
+	require.Nil(t, err)
+
+	attestedCredentialData := make([]byte, aaguidLength)
+	attestedCredentialData = appendUint16(attestedCredentialData, uint16(len(authenticator.credentialID)))
+	attestedCredentialData = append(attestedCredentialData, authenticator.credentialID...)
+	attestedCredentialData = append(attestedCredentialData, encodedKey...)
+
+	attestation, err := cbor.Marshal(map[string]interface{}{
+		"fmt":      attestationFormatNone,
+		"attStmt":  map[string]interface{}{},
+		"authData": authenticator.authenticatorData(authenticator.flags|flagAttestedCredentialData, attestedCredentialData),
+	})
+	require.Nil(t, err)
+
+	return requests.WebAuthnRegistration{
+		ClientDataJSON:    base64.RawURLEncoding.EncodeToString(authenticator.clientData(clientDataTypeCreate, challenge)),
+		AttestationObject: base64.RawURLEncoding.EncodeToString(attestation),
+	}
+}
+
+func (authenticator *softwareAuthenticator) assert(t *testing.T, challenge []byte) requests.WebAuthnAssertion {
+	clientData := authenticator.clientData(clientDataTypeGet, challenge)
+	authData := authenticator.authenticatorData(authenticator.flags, nil)
+
+	clientDataHash := sha256.Sum256(clientData)
+	signedDataHash := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, authenticator.privateKey, signedDataHash[:])
+	require.Nil(t, err)
+
+	return requests.WebAuthnAssertion{
+		CredentialID:      base64.RawURLEncoding.EncodeToString(authenticator.credentialID),
+		ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientData),
+		AuthenticatorData: base64.RawURLEncoding.EncodeToString(authData),
+		Signature:         base64.RawURLEncoding.EncodeToString(signature),
+	}
+}
+
+func appendUint16(data []byte, value uint16) []byte {
+	encoded := make([]byte, 2)
+	binary.BigEndian.PutUint16(encoded, value)
+	return append(data, encoded...)
+}
+
+func appendUint32(data []byte, value uint32) []byte {
+	encoded := make([]byte, 4)
+	binary.BigEndian.PutUint32(encoded, value)
+	return append(data, encoded...)
+}
+
+func createMockArgs() ArgsWebAuthnHandler {
+	keyGen := signing.NewKeyGenerator(ed25519.NewEd25519())
+	managedKey, _ := keyGen.GeneratePair()
+	marshaller, _ := factoryMarshaller.NewMarshalizer(factoryMarshaller.JsonMarshalizer)
+	keyRing, _ := encryption.NewKeyRing(encryption.ArgsKeyRing{
+		Marshaller:   marshaller,
+		KeyGen:       keyGen,
+		ManagedKeys:  map[uint32]crypto.PrivateKey{1: managedKey},
+		CurrentKeyID: 1,
+	})
+
+	return ArgsWebAuthnHandler{
+		Config: config.WebAuthnConfig{
+			RPID:                    testRPID,
+			RPOrigins:               []string{testOrigin},
+			ChallengeTimeoutInSec:   120,
+			RequireUserVerification: true,
+		},
+		Encryptor: keyRing,
+	}
+}
+
+// createHandler returns a handler whose clock advances by one millisecond on each read,
+// so each challenge is newer than the previous ones
+func createHandler(t *testing.T) *webAuthnHandler {
+	handler, err := NewWebAuthnHandler(createMockArgs())
+	require.Nil(t, err)
+	now := currentTimestamp
+	handler.getTimeHandler = func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	}
+
+	return handler
+}
+
+func createChallenge(t *testing.T, handler *webAuthnHandler, binding []byte) []byte {
+	challenge, err := handler.CreateChallenge(binding)
+	require.Nil(t, err)
+
+	return challenge
+}
+
+func registerAuthenticator(t *testing.T, handler *webAuthnHandler, authenticator *softwareAuthenticator) core.WebAuthnInfo {
+	challenge := createChallenge(t, handler, testBinding)
+	credential, err := handler.VerifyRegistration(core.WebAuthnInfo{}, testBinding, authenticator.register(t, challenge, authenticator.coseKey()))
+	require.Nil(t, err)
+
+	return *credential
+}
+
+func TestNewWebAuthnHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty RPID should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.RPID = ""
+		handler, err := NewWebAuthnHandler(args)
+		assert.True(t, errors.Is(err, handlers.ErrInvalidConfig))
+		assert.True(t, strings.Contains(err.Error(), "RPID"))
+		assert.True(t, handler.IsInterfaceNil())
+	})
+	t.Run("empty RPOrigins should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.RPOrigins = nil
+		handler, err := NewWebAuthnHandler(args)
+		assert.True(t, errors.Is(err, handlers.ErrInvalidConfig))
+		assert.True(t, strings.Contains(err.Error(), "RPOrigins"))
+		assert.Nil(t, handler)
+	})
+	t.Run("invalid ChallengeTimeoutInSec should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Config.ChallengeTimeoutInSec = 0
+		handler, err := NewWebAuthnHandler(args)
+		assert.True(t, errors.Is(err, handlers.ErrInvalidConfig))
+		assert.True(t, strings.Contains(err.Error(), "ChallengeTimeoutInSec"))
+		assert.Nil(t, handler)
+	})
+	t.Run("nil encryptor should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Encryptor = nil
+		handler, err := NewWebAuthnHandler(args)
+		assert.Equal(t, handlers.ErrNilEncryptor, err)
+		assert.Nil(t, handler)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		handler, err := NewWebAuthnHandler(createMockArgs())
+		assert.Nil(t, err)
+		assert.False(t, handler.IsInterfaceNil())
+	})
+}
+
+func TestWebAuthnHandler_CreateChallenge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("encryption error should error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		args := createMockArgs()
+		args.Encryptor = &testscommon.EncryptorStub{
+			EncryptDataCalled: func(data []byte, associatedData []byte) ([]byte, error) {
+				return nil, expectedErr
+			},
+		}
+		handler, err := NewWebAuthnHandler(args)
+		require.Nil(t, err)
+
+		challenge, err := handler.CreateChallenge(testBinding)
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, challenge)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		firstChallenge := createChallenge(t, handler, testBinding)
+		secondChallenge := createChallenge(t, handler, testBinding)
+
+		assert.NotEqual(t, firstChallenge, secondChallenge)
+		assert.Equal(t, uint32(1), binary.BigEndian.Uint32(firstChallenge[:challengeKeyIDLength]))
+		assert.NotContains(t, string(firstChallenge), string(testBinding))
+	})
+}
+
+func TestWebAuthnHandler_VerifyRegistration(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid challenge should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+
+		credential, err := handler.VerifyRegistration(core.WebAuthnInfo{}, testBinding, authenticator.register(t, []byte("challenge"), authenticator.coseKey()))
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "challenge mismatch"))
+		assert.Nil(t, credential)
+	})
+	t.Run("challenge of another binding should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		challenge := createChallenge(t, handler, []byte("other binding"))
+
+		credential, err := handler.VerifyRegistration(core.WebAuthnInfo{}, testBinding, authenticator.register(t, challenge, authenticator.coseKey()))
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "challenge mismatch"))
+		assert.Nil(t, credential)
+	})
+	t.Run("expired challenge should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		challenge := createChallenge(t, handler, testBinding)
+		handler.getTimeHandler = func() time.Time {
+			return currentTimestamp.Add(handler.challengeTimeout + time.Second)
+		}
+
+		credential, err := handler.VerifyRegistration(core.WebAuthnInfo{}, testBinding, authenticator.register(t, challenge, authenticator.coseKey()))
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "challenge expired"))
+		assert.Nil(t, credential)
+	})
+	t.Run("challenge older than the last consumed one should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		challenge := createChallenge(t, handler, testBinding)
+		previousCredential := core.WebAuthnInfo{
+			LastChallengeTimestamp: currentTimestamp.Add(time.Minute).UnixNano(),
+		}
+
+		credential, err := handler.VerifyRegistration(previousCredential, testBinding, authenticator.register(t, challenge, authenticator.coseKey()))
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "challenge already used"))
+		assert.Nil(t, credential)
+	})
+	t.Run("other origin should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		authenticator.origin = "https://phishing.com"
+		challenge := createChallenge(t, handler, testBinding)
+
+		credential, err := handler.VerifyRegistration(core.WebAuthnInfo{}, testBinding, authenticator.register(t, challenge, authenticator.coseKey()))
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "origin"))
+		assert.Nil(t, credential)
+	})
+	t.Run("other relying party should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		authenticator.rpID = "phishing.com"
+		challenge := createChallenge(t, handler, testBinding)
+
+		credential, err := handler.VerifyRegistration(core.WebAuthnInfo{}, testBinding, authenticator.register(t, challenge, authenticator.coseKey()))
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "relying party mismatch"))
+		assert.Nil(t, credential)
+	})
+	t.Run("user not verified should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		authenticator.flags = flagUserPresent
+		challenge := createChallenge(t, handler, testBinding)
+
+		credential, err := handler.VerifyRegistration(core.WebAuthnInfo{}, testBinding, authenticator.register(t, challenge, authenticator.coseKey()))
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "user not verified"))
+		assert.Nil(t, credential)
+	})
+	t.Run("assertion client data should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		challenge := createChallenge(t, handler, testBinding)
+		registration := authenticator.register(t, challenge, authenticator.coseKey())
+		registration.ClientDataJSON = base64.RawURLEncoding.EncodeToString(authenticator.clientData(clientDataTypeGet, challenge))
+
+		credential, err := handler.VerifyRegistration(core.WebAuthnInfo{}, testBinding, registration)
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "unexpected client data type"))
+		assert.Nil(t, credential)
+	})
+	t.Run("unsupported key should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		challenge := createChallenge(t, handler, testBinding)
+		key := authenticator.coseKey()
+		key.Algorithm = -8
+
+		credential, err := handler.VerifyRegistration(core.WebAuthnInfo{}, testBinding, authenticator.register(t, challenge, key))
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "only ES256 is accepted"))
+		assert.Nil(t, credential)
+	})
+	t.Run("point not on curve should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		challenge := createChallenge(t, handler, testBinding)
+		key := authenticator.coseKey()
+		key.YCoordinate = make([]byte, p256CoordinateLength)
+
+		credential, err := handler.VerifyRegistration(core.WebAuthnInfo{}, testBinding, authenticator.register(t, challenge, key))
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "not on the P-256 curve"))
+		assert.Nil(t, credential)
+	})
+	t.Run("invalid attestation object should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		challenge := createChallenge(t, handler, testBinding)
+		registration := authenticator.register(t, challenge, authenticator.coseKey())
+
+		invalidAttestationObjects := map[string][]byte{
+			"truncated map": {0xa1, 0x63},
+			"not a map":     {0x01},
+			"trailing data": append(mustDecodeBase64URL(t, registration.AttestationObject), 0x01),
+		}
+		for name, attestationObject := range invalidAttestationObjects {
+			registration.AttestationObject = base64.RawURLEncoding.EncodeToString(attestationObject)
+
+			credential, err := handler.VerifyRegistration(core.WebAuthnInfo{}, testBinding, registration)
+			assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse), name)
+			assert.Nil(t, credential, name)
+		}
+	})
+	t.Run("other attestation format should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		challenge := createChallenge(t, handler, testBinding)
+		registration := authenticator.register(t, challenge, authenticator.coseKey())
+		attestation, err := cbor.Marshal(map[string]interface{}{
+			"fmt":      "packed",
+			"authData": authenticator.authenticatorData(authenticator.flags, nil),
+		})
+		require.Nil(t, err)
+		registration.AttestationObject = base64.RawURLEncoding.EncodeToString(attestation)
+
+		credential, err := handler.VerifyRegistration(core.WebAuthnInfo{}, testBinding, registration)
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "unsupported attestation format packed"))
+		assert.Nil(t, credential)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		authenticator.signCount = 7
+		challenge := createChallenge(t, handler, testBinding)
+
+		credential, err := handler.VerifyRegistration(core.WebAuthnInfo{}, testBinding, authenticator.register(t, challenge, authenticator.coseKey()))
+		require.Nil(t, err)
+		assert.Equal(t, authenticator.credentialID, credential.CredentialID)
+		assert.Equal(t, elliptic.Marshal(elliptic.P256(), authenticator.privateKey.X, authenticator.privateKey.Y), credential.PublicKey)
+		assert.Equal(t, uint32(7), credential.SignCount)
+		assert.Equal(t, currentTimestamp.Add(time.Millisecond).UnixNano(), credential.LastChallengeTimestamp)
+	})
+}
+
+func TestWebAuthnHandler_VerifyAssertion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown credential should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		credential := registerAuthenticator(t, handler, authenticator)
+		assertion := authenticator.assert(t, createChallenge(t, handler, testBinding))
+		assertion.CredentialID = base64.RawURLEncoding.EncodeToString([]byte("other credential"))
+
+		updatedCredential, err := handler.VerifyAssertion(credential, testBinding, assertion)
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "unknown credential"))
+		assert.Nil(t, updatedCredential)
+	})
+	t.Run("challenge of other transactions should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		credential := registerAuthenticator(t, handler, authenticator)
+		assertion := authenticator.assert(t, createChallenge(t, handler, []byte("binding of other transactions")))
+
+		updatedCredential, err := handler.VerifyAssertion(credential, testBinding, assertion)
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "challenge mismatch"))
+		assert.Nil(t, updatedCredential)
+	})
+	t.Run("replayed challenge should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		credential := registerAuthenticator(t, handler, authenticator)
+		challenge := createChallenge(t, handler, testBinding)
+
+		updatedCredential, err := handler.VerifyAssertion(credential, testBinding, authenticator.assert(t, challenge))
+		require.Nil(t, err)
+
+		replayedCredential, err := handler.VerifyAssertion(*updatedCredential, testBinding, authenticator.assert(t, challenge))
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "challenge already used"))
+		assert.Nil(t, replayedCredential)
+	})
+	t.Run("signature of another key should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		credential := registerAuthenticator(t, handler, authenticator)
+		otherAuthenticator := newSoftwareAuthenticator(t)
+
+		updatedCredential, err := handler.VerifyAssertion(credential, testBinding, otherAuthenticator.assert(t, createChallenge(t, handler, testBinding)))
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "invalid signature"))
+		assert.Nil(t, updatedCredential)
+	})
+	t.Run("tampered authenticator data should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		credential := registerAuthenticator(t, handler, authenticator)
+		assertion := authenticator.assert(t, createChallenge(t, handler, testBinding))
+		authenticator.signCount = 100
+		assertion.AuthenticatorData = base64.RawURLEncoding.EncodeToString(authenticator.authenticatorData(authenticator.flags, nil))
+
+		updatedCredential, err := handler.VerifyAssertion(credential, testBinding, assertion)
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "invalid signature"))
+		assert.Nil(t, updatedCredential)
+	})
+	t.Run("registration client data should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		credential := registerAuthenticator(t, handler, authenticator)
+		challenge := createChallenge(t, handler, testBinding)
+		assertion := authenticator.assert(t, challenge)
+		assertion.ClientDataJSON = base64.RawURLEncoding.EncodeToString(authenticator.clientData(clientDataTypeCreate, challenge))
+
+		updatedCredential, err := handler.VerifyAssertion(credential, testBinding, assertion)
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "unexpected client data type"))
+		assert.Nil(t, updatedCredential)
+	})
+	t.Run("user not present should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		handler.requireUserVerification = false
+		authenticator := newSoftwareAuthenticator(t)
+		credential := registerAuthenticator(t, handler, authenticator)
+		authenticator.flags = 0
+
+		updatedCredential, err := handler.VerifyAssertion(credential, testBinding, authenticator.assert(t, createChallenge(t, handler, testBinding)))
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "user not present"))
+		assert.Nil(t, updatedCredential)
+	})
+	t.Run("counter not increased should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		authenticator.signCount = 5
+		credential := registerAuthenticator(t, handler, authenticator)
+
+		updatedCredential, err := handler.VerifyAssertion(credential, testBinding, authenticator.assert(t, createChallenge(t, handler, testBinding)))
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "signature counter did not increase"))
+		assert.Nil(t, updatedCredential)
+	})
+	t.Run("invalid signature encoding should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		credential := registerAuthenticator(t, handler, authenticator)
+		assertion := authenticator.assert(t, createChallenge(t, handler, testBinding))
+		assertion.Signature = "not base64 !"
+
+		updatedCredential, err := handler.VerifyAssertion(credential, testBinding, assertion)
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.True(t, strings.Contains(err.Error(), "invalid signature encoding"))
+		assert.Nil(t, updatedCredential)
+	})
+	t.Run("should work with counter", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		authenticator.signCount = 5
+		credential := registerAuthenticator(t, handler, authenticator)
+		authenticator.signCount = 6
+
+		updatedCredential, err := handler.VerifyAssertion(credential, testBinding, authenticator.assert(t, createChallenge(t, handler, testBinding)))
+		require.Nil(t, err)
+		assert.Equal(t, uint32(6), updatedCredential.SignCount)
+		assert.Greater(t, updatedCredential.LastChallengeTimestamp, credential.LastChallengeTimestamp)
+		assert.Equal(t, credential.PublicKey, updatedCredential.PublicKey)
+	})
+	t.Run("should work without counter and padded encoding", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		credential := registerAuthenticator(t, handler, authenticator)
+		assertion := authenticator.assert(t, createChallenge(t, handler, testBinding))
+		signature, _ := base64.RawURLEncoding.DecodeString(assertion.Signature)
+		assertion.Signature = base64.URLEncoding.EncodeToString(signature)
+
+		updatedCredential, err := handler.VerifyAssertion(credential, testBinding, assertion)
+		require.Nil(t, err)
+		assert.Zero(t, updatedCredential.SignCount)
+	})
+	t.Run("newer outstanding challenges should not invalidate the used one", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createHandler(t)
+		authenticator := newSoftwareAuthenticator(t)
+		credential := registerAuthenticator(t, handler, authenticator)
+		challenge := createChallenge(t, handler, testBinding)
+		_ = createChallenge(t, handler, testBinding)
+		_ = createChallenge(t, handler, testBinding)
+
+		updatedCredential, err := handler.VerifyAssertion(credential, testBinding, authenticator.assert(t, challenge))
+		require.Nil(t, err)
+		assert.NotNil(t, updatedCredential)
+	})
+}
+
+func mustDecodeBase64URL(t *testing.T, value string) []byte {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	require.Nil(t, err)
+
+	return decoded
+}
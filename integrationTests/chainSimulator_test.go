@@ -0,0 +1,323 @@
+package integrationtests
+
+import (
+	"context"
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	chainCore "github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	"github.com/multiversx/mx-chain-crypto-go/signing"
+	"github.com/multiversx/mx-chain-crypto-go/signing/ed25519"
+	"github.com/multiversx/mx-sdk-go/blockchain/cryptoProvider"
+	"github.com/multiversx/mx-sdk-go/builders"
+	sdkCore "github.com/multiversx/mx-sdk-go/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/chainSimulator"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/factory"
+	storageFactory "github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage/factory"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/twofactor/rfc"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/metrics"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/redis"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/resolver"
+)
+
+const (
+	configDirectory          = "../cmd/multi-factor-auth/config/"
+	guardianActivationEpochs = 20
+	userIp                   = "127.0.0.1"
+	serviceUID               = "MultiversXTCSService"
+)
+
+type offlineService struct {
+	resolver  core.ServiceResolver
+	simulator chainSimulatorHandler
+	issuer    string
+}
+
+type chainSimulatorHandler interface {
+	SetBalance(address string, balance string) error
+	SetGuardian(address string, guardian string, serviceUID string) error
+	SetActiveGuardian(address string, guardian string, serviceUID string) error
+	SetGuarded(address string, guarded bool) error
+	AdvanceEpochs(numEpochs uint32)
+}
+
+// createOfflineService wires the service as the tcs runner does, with the chain simulator instead of the API
+// and everything else kept in memory
+func createOfflineService(t *testing.T) *offlineService {
+	var generalConfig config.Config
+	require.Nil(t, chainCore.LoadTomlFile(&generalConfig, configDirectory+"config.toml"))
+	var externalConfig config.ExternalConfig
+	require.Nil(t, chainCore.LoadTomlFile(&externalConfig, configDirectory+"external.toml"))
+
+	generalConfig.Guardian.MnemonicFile = "../factory/testdata/multiversx.mnemonic"
+	generalConfig.KeyProvider.Type = string(core.FileKeyProvider)
+	generalConfig.Audit.SinkType = ""
+	generalConfig.Notifier.Type = ""
+	generalConfig.TwoFactor.Type = core.HOTPType
+	generalConfig.ServiceResolver.SkipTxUserSigVerify = false
+	generalConfig.General.DBType = core.MemoryDB
+	externalConfig.Redis.ConnectionType = string(core.RedisMemoryConnType)
+	externalConfig.ChainSimulator.EpochDurationInSec = 0
+	externalConfig.ChainSimulator.GuardianActivationEpochs = guardianActivationEpochs
+
+	configs := &config.Configs{
+		GeneralConfig:  generalConfig,
+		ExternalConfig: externalConfig,
+		FlagsConfig: config.ContextFlagsConfig{
+			SimulateChain: true,
+		},
+	}
+
+	simulator, err := chainSimulator.CreateChainSimulator(externalConfig.ChainSimulator)
+	require.Nil(t, err)
+	networkAddress, err := simulator.Start(externalConfig.ChainSimulator.ListenAddress)
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		assert.Nil(t, simulator.Close())
+	})
+
+	cryptoComponents, err := factory.CreateCoreCryptoComponents(generalConfig.PubKey)
+	require.Nil(t, err)
+
+	statusMetricsHandler := metrics.NewStatusMetrics()
+	registeredUsersDB, err := storageFactory.NewStorageWithIndexFactory(generalConfig, externalConfig, statusMetricsHandler).Create()
+	require.Nil(t, err)
+
+	auditSink, err := factory.CreateAuditSink(configs, statusMetricsHandler)
+	require.Nil(t, err)
+	notifier, err := factory.CreateNotifier(configs)
+	require.Nil(t, err)
+
+	externalConfig.Api.NetworkAddress = networkAddress
+	externalConfig.Api.FallbackNetworkAddresses = nil
+	_, httpClientWrapper, err := factory.CreateChainApiClients(externalConfig.Api, statusMetricsHandler)
+	require.Nil(t, err)
+	twoFactorHandler, err := factory.CreateOTPHandler(configs)
+	require.Nil(t, err)
+	redisStorer, err := redis.CreateRedisStorer(externalConfig.Redis)
+	require.Nil(t, err)
+	secureOtpHandler, err := factory.CreateSecureOTPHandler(configs, redisStorer, notifier)
+	require.Nil(t, err)
+
+	serviceResolver, err := factory.CreateServiceResolver(configs, cryptoComponents, httpClientWrapper, registeredUsersDB, twoFactorHandler, secureOtpHandler, redisStorer, auditSink, notifier)
+	require.Nil(t, err)
+
+	return &offlineService{
+		resolver:  serviceResolver,
+		simulator: simulator,
+		issuer:    generalConfig.TwoFactor.Issuer,
+	}
+}
+
+type testUser struct {
+	cryptoHolder  sdkCore.CryptoComponentsHolder
+	counters      map[string]uint64
+	secrets       map[string][]byte
+	recoveryCodes []string
+}
+
+func TestChainSimulator_RegisterVerifySignOffline(t *testing.T) {
+	service := createOfflineService(t)
+	user := createTestUser(t)
+	userAddress := user.cryptoHolder.GetBech32()
+
+	t.Run("an account without balance cannot register", func(t *testing.T) {
+		require.Nil(t, service.simulator.SetBalance(userAddress, "0"))
+		_, _, err := service.resolver.RegisterUser(user.cryptoHolder.GetAddressHandler(), userIp, requests.RegistrationPayload{})
+		assert.True(t, errors.Is(err, resolver.ErrNoBalance))
+		require.Nil(t, service.simulator.SetBalance(userAddress, "1000000000000000000"))
+	})
+
+	firstGuardian := user.register(t, service, "")
+	user.verify(t, service, firstGuardian)
+	require.NotEmpty(t, user.recoveryCodes)
+	user.checkGuardianStatus(t, service, firstGuardian, core.Verified, false)
+	user.checkStatusDoesNotConsumeTrials(t, service)
+
+	// the user sets the guardian on chain, then guards the account
+	require.Nil(t, service.simulator.SetActiveGuardian(userAddress, firstGuardian, serviceUID))
+	require.Nil(t, service.simulator.SetGuarded(userAddress, true))
+	user.previewTransaction(t, service, firstGuardian)
+	user.signTransaction(t, service, firstGuardian)
+	user.checkGuardianStatus(t, service, firstGuardian, core.Active, true)
+
+	// a new device gets the second guardian, which is not yet on chain
+	secondGuardian := user.register(t, service, user.popRecoveryCode(t))
+	assert.NotEqual(t, firstGuardian, secondGuardian)
+	user.verify(t, service, secondGuardian)
+
+	// while the second guardian is pending, a new registration replaces it
+	require.Nil(t, service.simulator.SetGuardian(userAddress, secondGuardian, serviceUID))
+	require.Nil(t, service.resolver.ReconcileGuardianActivations(context.Background()))
+	user.checkGuardianStatus(t, service, secondGuardian, core.PendingOnChain, false)
+	guardian := user.register(t, service, user.popRecoveryCode(t))
+	assert.Equal(t, secondGuardian, guardian)
+	user.checkGuardianStatus(t, service, secondGuardian, core.Untracked, false)
+	user.verify(t, service, secondGuardian)
+
+	// once the second guardian becomes active, the first one is replaced and a new registration overwrites it
+	service.simulator.AdvanceEpochs(guardianActivationEpochs)
+	require.Nil(t, service.resolver.ReconcileGuardianActivations(context.Background()))
+	user.checkGuardianStatus(t, service, secondGuardian, core.Active, true)
+	user.checkGuardianStatus(t, service, firstGuardian, core.Replaced, false)
+	user.signTransaction(t, service, secondGuardian)
+	guardian = user.register(t, service, user.popRecoveryCode(t))
+	assert.Equal(t, firstGuardian, guardian)
+}
+
+func (user *testUser) checkStatusDoesNotConsumeTrials(t *testing.T, service *offlineService) {
+	status, err := service.resolver.GetGuardianStatus(user.cryptoHolder.GetAddressHandler(), userIp)
+	require.Nil(t, err)
+	assert.False(t, status.SecurityModeActive)
+
+	statusAfter, err := service.resolver.GetGuardianStatus(user.cryptoHolder.GetAddressHandler(), userIp)
+	require.Nil(t, err)
+	assert.Equal(t, status.VerifyData.RemainingTrials, statusAfter.VerifyData.RemainingTrials)
+	assert.Equal(t, status.VerifyData.SecurityModeRemainingTrials, statusAfter.VerifyData.SecurityModeRemainingTrials)
+}
+
+func (user *testUser) checkGuardianStatus(t *testing.T, service *offlineService, guardian string, activationState core.ActivationState, usable bool) {
+	status, err := service.resolver.GetGuardianStatus(user.cryptoHolder.GetAddressHandler(), userIp)
+	require.Nil(t, err)
+
+	for _, guardianStatus := range status.Guardians {
+		if guardianStatus.Address == guardian {
+			assert.Equal(t, activationState.String(), guardianStatus.ActivationState)
+			assert.Equal(t, usable, guardianStatus.Usable)
+			return
+		}
+	}
+
+	assert.Fail(t, "guardian not found", guardian)
+}
+
+func createTestUser(t *testing.T) *testUser {
+	keyGen := signing.NewKeyGenerator(ed25519.NewEd25519())
+	sk, _ := keyGen.GeneratePair()
+	skBytes, err := sk.ToByteArray()
+	require.Nil(t, err)
+
+	holder, err := cryptoProvider.NewCryptoComponentsHolder(keyGen, skBytes)
+	require.Nil(t, err)
+
+	return &testUser{
+		cryptoHolder: holder,
+		counters:     make(map[string]uint64),
+		secrets:      make(map[string][]byte),
+	}
+}
+
+// register registers the user, using the recovery code, if any, to skip the delay between otp writes.
+// Returns the guardian for which the otp was registered
+func (user *testUser) register(t *testing.T, service *offlineService, recoveryCode string) string {
+	otp, guardian, err := service.resolver.RegisterUser(user.cryptoHolder.GetAddressHandler(), userIp, requests.RegistrationPayload{
+		RecoveryCode: recoveryCode,
+	})
+	require.Nil(t, err)
+
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(otp.Secret)
+	require.Nil(t, err)
+	user.secrets[guardian] = secret
+	user.counters[guardian] = uint64(otp.Counter)
+
+	return guardian
+}
+
+func (user *testUser) popRecoveryCode(t *testing.T) string {
+	require.NotEmpty(t, user.recoveryCodes)
+	recoveryCode := user.recoveryCodes[0]
+	user.recoveryCodes = user.recoveryCodes[1:]
+
+	return recoveryCode
+}
+
+func (user *testUser) verify(t *testing.T, service *offlineService, guardian string) {
+	recoveryCodes, _, err := service.resolver.VerifyCode(user.cryptoHolder.GetAddressHandler(), userIp, requests.VerificationPayload{
+		Code:     user.nextCode(t, service, guardian),
+		Guardian: guardian,
+	})
+	require.Nil(t, err)
+	if recoveryCodes != nil {
+		user.recoveryCodes = recoveryCodes.RecoveryCodes
+	}
+}
+
+func (user *testUser) nextCode(t *testing.T, service *offlineService, guardian string) string {
+	params := core.OTPParams{
+		Type:      core.HOTPType,
+		Algorithm: "SHA1",
+		Digits:    6,
+		Counter:   user.counters[guardian],
+	}
+	otpProvider, err := rfc.NewOTPProvider(service.issuer, 1)
+	require.Nil(t, err)
+	otp, err := otpProvider.OTPFromBytes(user.secrets[guardian], params)
+	require.Nil(t, err)
+	code, err := otp.OTP()
+	require.Nil(t, err)
+
+	user.counters[guardian]++
+
+	return code
+}
+
+func (user *testUser) createSignedTransaction(t *testing.T, guardian string) transaction.FrontendTransaction {
+	tx := transaction.FrontendTransaction{
+		Nonce:        1,
+		Value:        "1",
+		Receiver:     user.cryptoHolder.GetBech32(),
+		Sender:       user.cryptoHolder.GetBech32(),
+		GasPrice:     1000000000,
+		GasLimit:     150000,
+		ChainID:      "T",
+		Version:      2,
+		Options:      transaction.MaskGuardedTransaction,
+		GuardianAddr: guardian,
+	}
+	txBuilder, err := builders.NewTxBuilder(cryptoProvider.NewSigner())
+	require.Nil(t, err)
+	require.Nil(t, txBuilder.ApplyUserSignature(user.cryptoHolder, &tx))
+
+	return tx
+}
+
+// previewTransaction checks that the transaction is previewed without a code, before its guardian is reconciled
+func (user *testUser) previewTransaction(t *testing.T, service *offlineService, guardian string) {
+	preview, err := service.resolver.PreviewTransactions(requests.SignMultipleTransactions{
+		Txs: []transaction.FrontendTransaction{user.createSignedTransaction(t, guardian)},
+	})
+	require.Nil(t, err)
+
+	assert.Equal(t, guardian, preview.Guardian.Address)
+	assert.Equal(t, core.Verified.String(), preview.Guardian.ActivationState)
+	require.Equal(t, 1, len(preview.Transactions))
+	assert.Equal(t, string(core.EGLDTransferTransaction), preview.Transactions[0].Type)
+	assert.Equal(t, user.cryptoHolder.GetBech32(), preview.Transactions[0].Receiver)
+	assert.Equal(t, []requests.TokenTransfer{{Token: "EGLD", Amount: "1"}}, preview.Transactions[0].Transfers)
+	assert.Equal(t, "150000000000000", preview.Transactions[0].MaxFee)
+}
+
+func (user *testUser) signTransaction(t *testing.T, service *offlineService, guardian string) {
+	txBytes, _, err := service.resolver.SignTransaction(userIp, requests.SignTransaction{
+		Code: user.nextCode(t, service, guardian),
+		Tx:   user.createSignedTransaction(t, guardian),
+	})
+	require.Nil(t, err)
+
+	signedTx := transaction.FrontendTransaction{}
+	require.Nil(t, json.Unmarshal(txBytes, &signedTx))
+	assert.Equal(t, guardian, signedTx.GuardianAddr)
+	_, err = hex.DecodeString(signedTx.GuardianSignature)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, signedTx.GuardianSignature)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/secureOtp"
 	redisLocal "github.com/multiversx/mx-multi-factor-auth-go-service/redis"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
 )
 
 type miniRedisHandler interface {
@@ -50,6 +51,7 @@ func createRateLimiter(t *testing.T, maxFailures, periodLimit, securityModeMaxFa
 
 	secureOtpArgs := secureOtp.ArgsSecureOtpHandler{
 		RateLimiter: rl,
+		Notifier:    &testscommon.NotifierStub{},
 	}
 	secureOtpHandler, err := secureOtp.NewSecureOtpHandler(secureOtpArgs)
 	require.Nil(t, err)
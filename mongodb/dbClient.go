@@ -24,12 +24,19 @@ const (
 	// UsersCollectionID specifies mongodb collection for users
 	UsersCollectionID CollectionID = "users"
 
+	// AuditCollectionID specifies mongodb collection for the audit log
+	AuditCollectionID CollectionID = "audit"
+
 	metricPrefix        = "MongoDB"
 	getIndexMetricLabel = "GetIndex"
 	delMetricLabel      = "DeleteOne"
 	findMetricLabel     = "FindOne"
 	updateMetricLabel   = "UpdateOne"
 	incMetricLabel      = "Increment"
+	insertMetricLabel   = "InsertOne"
+	findManyMetricLabel = "Find"
+	rangeMetricLabel    = "RangeKeys"
+	maxMetricLabel      = "Max"
 )
 
 const incrementIndexStep = 1
@@ -40,6 +47,11 @@ type mongoEntry struct {
 	Value []byte `bson:"value"`
 }
 
+type rawMongoEntry struct {
+	Key   string        `bson:"_id"`
+	Value bson.RawValue `bson:"value"`
+}
+
 type counterMongoEntry struct {
 	Key   string `bson:"_id"`
 	Value uint32 `bson:"value"`
@@ -54,14 +66,28 @@ type mongodbClient struct {
 	metricsHandler core.StatusMetricsHandler
 }
 
-// NewClient will create a new mongodb client instance
+// NewClient will create a new mongodb client instance, using the default users collections
 func NewClient(client *mongo.Client, dbName string, numUsersColls uint32, metricsHandler core.StatusMetricsHandler) (*mongodbClient, error) {
+	return NewClientWithUsersCollections(client, dbName, string(UsersCollectionID), numUsersColls, metricsHandler)
+}
+
+// NewClientWithUsersCollections will create a new mongodb client instance, using the users collections with the provided prefix
+func NewClientWithUsersCollections(
+	client *mongo.Client,
+	dbName string,
+	usersCollsPrefix string,
+	numUsersColls uint32,
+	metricsHandler core.StatusMetricsHandler,
+) (*mongodbClient, error) {
 	if client == nil {
 		return nil, ErrNilMongoDBClient
 	}
 	if dbName == "" {
 		return nil, ErrEmptyMongoDBName
 	}
+	if usersCollsPrefix == "" {
+		return nil, ErrEmptyUsersCollectionsPrefix
+	}
 	if numUsersColls < minNumUsersColls {
 		return nil, fmt.Errorf("%w for number of users collections: provided %d, minimum %d",
 			core.ErrInvalidValue, numUsersColls, minNumUsersColls)
@@ -85,20 +111,21 @@ func NewClient(client *mongo.Client, dbName string, numUsersColls uint32, metric
 		metricsHandler: metricsHandler,
 	}
 
-	mongoClient.createCollections(numUsersColls)
+	mongoClient.createCollections(usersCollsPrefix, numUsersColls)
 
 	return mongoClient, nil
 }
 
-func (mdc *mongodbClient) createCollections(numUsersColls uint32) {
+func (mdc *mongodbClient) createCollections(usersCollsPrefix string, numUsersColls uint32) {
 	collections := make(map[CollectionID]*mongo.Collection)
 	collectionIDs := make([]CollectionID, 0, len(mdc.collections))
 
 	for i := uint32(0); i < numUsersColls; i++ {
-		collName := fmt.Sprintf("%s_%d", string(UsersCollectionID), i)
+		collName := fmt.Sprintf("%s_%d", usersCollsPrefix, i)
 		collections[CollectionID(collName)] = mdc.db.Collection(collName)
 		collectionIDs = append(collectionIDs, CollectionID(collName))
 	}
+	collections[AuditCollectionID] = mdc.db.Collection(string(AuditCollectionID))
 
 	mdc.collections = collections
 	mdc.collectionsIDs = collectionIDs
@@ -137,6 +164,120 @@ func (mdc *mongodbClient) Put(collID CollectionID, key []byte, data []byte) erro
 	return nil
 }
 
+// PutIfNotExists will set the key value pair into specified collection only if the key does not exist yet.
+// It returns true if the pair was written
+func (mdc *mongodbClient) PutIfNotExists(collID CollectionID, key []byte, data []byte) (bool, error) {
+	coll, ok := mdc.collections[collID]
+	if !ok {
+		return false, ErrCollectionNotFound
+	}
+
+	filter := bson.D{{Key: "_id", Value: string(key)}}
+	update := bson.D{{Key: "$setOnInsert",
+		Value: bson.D{
+			{Key: "_id", Value: string(key)},
+			{Key: "value", Value: data},
+		},
+	}}
+
+	opts := options.Update().SetUpsert(true)
+
+	t := time.Now()
+	res, err := coll.UpdateOne(mdc.ctx, filter, update, opts)
+	duration := time.Since(t)
+	if err != nil {
+		return false, err
+	}
+	mdc.metricsHandler.AddRequestData(getOpID(updateMetricLabel), duration, metrics.NonErrorCode)
+
+	return res.UpsertedCount == 1, nil
+}
+
+// InsertOne will add a new document into specified collection
+func (mdc *mongodbClient) InsertOne(collID CollectionID, document interface{}) error {
+	coll, ok := mdc.collections[collID]
+	if !ok {
+		return ErrCollectionNotFound
+	}
+
+	t := time.Now()
+	_, err := coll.InsertOne(mdc.ctx, document)
+	duration := time.Since(t)
+	if err != nil {
+		return err
+	}
+	mdc.metricsHandler.AddRequestData(getOpID(insertMetricLabel), duration, metrics.NonErrorCode)
+
+	return nil
+}
+
+// Find will decode into results all the documents of the specified collection matching the filter
+func (mdc *mongodbClient) Find(collID CollectionID, filter interface{}, opts *options.FindOptions, results interface{}) error {
+	coll, ok := mdc.collections[collID]
+	if !ok {
+		return ErrCollectionNotFound
+	}
+
+	t := time.Now()
+	cursor, err := coll.Find(mdc.ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+
+	err = cursor.All(mdc.ctx, results)
+	duration := time.Since(t)
+	if err != nil {
+		return err
+	}
+	mdc.metricsHandler.AddRequestData(getOpID(findManyMetricLabel), duration, metrics.NonErrorCode)
+
+	return nil
+}
+
+// RangeKeys will call the handler for each key-value entry of the specified collection, until the handler returns false.
+// Entries which do not hold a binary value, such as the index counters, are skipped
+func (mdc *mongodbClient) RangeKeys(ctx context.Context, collID CollectionID, handler func(key []byte, val []byte) bool) error {
+	coll, ok := mdc.collections[collID]
+	if !ok {
+		return ErrCollectionNotFound
+	}
+
+	t := time.Now()
+	cursor, err := coll.Find(ctx, bson.D{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		log.LogIfError(cursor.Close(mdc.ctx))
+	}()
+
+	for cursor.Next(ctx) {
+		entry := &rawMongoEntry{}
+		err = cursor.Decode(entry)
+		if err != nil {
+			return err
+		}
+
+		_, value, isBinary := entry.Value.BinaryOK()
+		if !isBinary {
+			continue
+		}
+
+		if !handler([]byte(entry.Key), value) {
+			break
+		}
+	}
+
+	err = cursor.Err()
+	duration := time.Since(t)
+	if err != nil {
+		return err
+	}
+	mdc.metricsHandler.AddRequestData(getOpID(rangeMetricLabel), duration, metrics.NonErrorCode)
+
+	return nil
+}
+
 func (mdc *mongodbClient) findOne(collID CollectionID, key []byte) (*mongoEntry, error) {
 	coll, ok := mdc.collections[collID]
 	if !ok {
@@ -197,6 +338,29 @@ func (mdc *mongodbClient) Remove(collID CollectionID, key []byte) error {
 	return nil
 }
 
+// RemoveIfEqual will remove the provided key from the collection only if it still holds the provided value
+func (mdc *mongodbClient) RemoveIfEqual(collID CollectionID, key []byte, data []byte) error {
+	coll, ok := mdc.collections[collID]
+	if !ok {
+		return ErrCollectionNotFound
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: string(key)},
+		{Key: "value", Value: data},
+	}
+
+	t := time.Now()
+	_, err := coll.DeleteOne(mdc.ctx, filter)
+	duration := time.Since(t)
+	if err != nil {
+		return err
+	}
+	mdc.metricsHandler.AddRequestData(getOpID(delMetricLabel), duration, metrics.NonErrorCode)
+
+	return nil
+}
+
 // GetIndex will return the index value for the provided key and collection
 func (mdc *mongodbClient) GetIndex(collID CollectionID, key []byte) (uint32, error) {
 	coll, ok := mdc.collections[collID]
@@ -249,6 +413,35 @@ func (mdc *mongodbClient) PutIndexIfNotExists(collID CollectionID, key []byte, i
 	return nil
 }
 
+// PutIndexIfGreater will set the index value to the specified key if it is greater than the existing one
+func (mdc *mongodbClient) PutIndexIfGreater(collID CollectionID, key []byte, index uint32) error {
+	coll, ok := mdc.collections[collID]
+	if !ok {
+		return ErrCollectionNotFound
+	}
+
+	filter := bson.D{{Key: "_id", Value: string(key)}}
+	update := bson.D{{Key: "$max",
+		Value: bson.D{
+			{Key: "value", Value: index},
+		},
+	}}
+
+	opts := options.Update().SetUpsert(true)
+
+	t := time.Now()
+	res, err := coll.UpdateOne(mdc.ctx, filter, update, opts)
+	duration := time.Since(t)
+	if err != nil {
+		return err
+	}
+	mdc.metricsHandler.AddRequestData(getOpID(maxMetricLabel), duration, metrics.NonErrorCode)
+
+	log.Trace("PutIndexIfGreater", "collID", coll.Name(), "key", string(key), "value", index, "modifiedCount", res.ModifiedCount, "upsertedCount", res.UpsertedCount)
+
+	return nil
+}
+
 // IncrementIndex will increment the value for the provided key
 func (mdc *mongodbClient) IncrementIndex(collID CollectionID, key []byte) (uint32, error) {
 	coll, ok := mdc.collections[collID]
@@ -1,6 +1,7 @@
 package mongodb_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -13,6 +14,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var expectedErr = errors.New("expected error")
@@ -41,6 +43,14 @@ func TestNewMongoDBClient(t *testing.T) {
 		require.Equal(mt, mongodb.ErrEmptyMongoDBName, err)
 	})
 
+	mt.Run("empty users collections prefix, should fail", func(mt *mtest.T) {
+		mt.Parallel()
+
+		client, err := mongodb.NewClientWithUsersCollections(mt.Client, "dbName", "", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, client)
+		require.Equal(mt, mongodb.ErrEmptyUsersCollectionsPrefix, err)
+	})
+
 	mt.Run("invalid num of users collections, should fail", func(mt *mtest.T) {
 		mt.Parallel()
 
@@ -69,6 +79,14 @@ func TestNewMongoDBClient(t *testing.T) {
 		require.Nil(mt, err)
 		require.False(mt, client.IsInterfaceNil())
 	})
+
+	mt.Run("should work with users collections prefix", func(mt *mtest.T) {
+		mt.Parallel()
+
+		client, err := mongodb.NewClientWithUsersCollections(mt.Client, "dbName", "users_v2", 3, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+		require.Equal(mt, []mongodb.CollectionID{"users_v2_0", "users_v2_1", "users_v2_2"}, client.GetAllCollectionsIDs())
+	})
 }
 
 func TestMongoDBClient_Put(t *testing.T) {
@@ -121,6 +139,109 @@ func TestMongoDBClient_Put(t *testing.T) {
 	})
 }
 
+func TestMongoDBClient_InsertOne(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("collection not found", func(mt *mtest.T) {
+		mt.Parallel()
+
+		client, err := mongodb.NewClient(mt.Client, "dbName", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+
+		err = client.InsertOne("another coll", bson.D{{Key: "key", Value: "value"}})
+		require.Equal(mt, mongodb.ErrCollectionNotFound, err)
+	})
+
+	mt.Run("should fail", func(mt *mtest.T) {
+		mt.Parallel()
+
+		mt.AddMockResponses(
+			mtest.CreateCommandErrorResponse(mtest.CommandError{
+				Code:    1,
+				Message: expectedErr.Error(),
+			}),
+		)
+
+		client, err := mongodb.NewClient(mt.Client, "dbName", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+
+		err = client.InsertOne(mongodb.AuditCollectionID, bson.D{{Key: "key", Value: "value"}})
+		require.Equal(mt, expectedErr.Error(), err.Error())
+	})
+
+	mt.Run("should work", func(mt *mtest.T) {
+		mt.Parallel()
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		client, err := mongodb.NewClient(mt.Client, "dbName", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+
+		err = client.InsertOne(mongodb.AuditCollectionID, bson.D{{Key: "key", Value: "value"}})
+		require.Nil(mt, err)
+	})
+}
+
+func TestMongoDBClient_Find(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("collection not found", func(mt *mtest.T) {
+		mt.Parallel()
+
+		client, err := mongodb.NewClient(mt.Client, "dbName", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+
+		results := make([]core.AuditEntry, 0)
+		err = client.Find("another coll", bson.D{}, options.Find(), &results)
+		require.Equal(mt, mongodb.ErrCollectionNotFound, err)
+	})
+
+	mt.Run("should fail", func(mt *mtest.T) {
+		mt.Parallel()
+
+		mt.AddMockResponses(
+			mtest.CreateCommandErrorResponse(mtest.CommandError{
+				Code:    1,
+				Message: expectedErr.Error(),
+			}),
+		)
+
+		client, err := mongodb.NewClient(mt.Client, "dbName", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+
+		results := make([]core.AuditEntry, 0)
+		err = client.Find(mongodb.AuditCollectionID, bson.D{}, options.Find(), &results)
+		require.Equal(mt, expectedErr.Error(), err.Error())
+	})
+
+	mt.Run("should work", func(mt *mtest.T) {
+		mt.Parallel()
+
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch,
+				bson.D{{Key: "route", Value: "/second"}, {Key: "user_address", Value: "user"}},
+				bson.D{{Key: "route", Value: "/first"}, {Key: "user_address", Value: "user"}},
+			),
+		)
+
+		client, err := mongodb.NewClient(mt.Client, "dbName", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+
+		results := make([]core.AuditEntry, 0)
+		err = client.Find(mongodb.AuditCollectionID, bson.D{{Key: "user_address", Value: "user"}}, options.Find(), &results)
+		require.Nil(mt, err)
+		require.Equal(mt, 2, len(results))
+		require.Equal(mt, "/second", results[0].Route)
+		require.Equal(mt, "/first", results[1].Route)
+	})
+}
+
 func TestMongoDBClient_PutIndexIfNotExists(t *testing.T) {
 	t.Parallel()
 
@@ -172,6 +293,114 @@ func TestMongoDBClient_PutIndexIfNotExists(t *testing.T) {
 	})
 }
 
+func TestMongoDBClient_PutIndexIfGreater(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("collection not found", func(mt *mtest.T) {
+		mt.Parallel()
+
+		client, err := mongodb.NewClient(mt.Client, "dbName", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+
+		err = client.PutIndexIfGreater("another coll", []byte("key1"), 1)
+		require.Equal(mt, mongodb.ErrCollectionNotFound, err)
+	})
+
+	mt.Run("should fail", func(mt *mtest.T) {
+		mt.Parallel()
+
+		mt.AddMockResponses(
+			mtest.CreateCommandErrorResponse(mtest.CommandError{
+				Code:    1,
+				Message: expectedErr.Error(),
+			}),
+		)
+
+		client, err := mongodb.NewClient(mt.Client, "dbName", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+
+		err = client.PutIndexIfGreater(usersCollID, []byte("key1"), 1)
+		require.Equal(mt, expectedErr.Error(), err.Error())
+	})
+
+	mt.Run("should work", func(mt *mtest.T) {
+		mt.Parallel()
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		client, err := mongodb.NewClient(mt.Client, "dbName", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+
+		err = client.PutIndexIfGreater(usersCollID, []byte("key1"), 1)
+		require.Nil(mt, err)
+	})
+}
+
+func TestMongoDBClient_RangeKeys(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("collection not found", func(mt *mtest.T) {
+		mt.Parallel()
+
+		client, err := mongodb.NewClient(mt.Client, "dbName", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+
+		err = client.RangeKeys(context.Background(), "another coll", func(key []byte, val []byte) bool {
+			return true
+		})
+		require.Equal(mt, mongodb.ErrCollectionNotFound, err)
+	})
+
+	mt.Run("should fail", func(mt *mtest.T) {
+		mt.Parallel()
+
+		mt.AddMockResponses(
+			mtest.CreateCommandErrorResponse(mtest.CommandError{
+				Code:    1,
+				Message: expectedErr.Error(),
+			}),
+		)
+
+		client, err := mongodb.NewClient(mt.Client, "dbName", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+
+		err = client.RangeKeys(context.Background(), usersCollID, func(key []byte, val []byte) bool {
+			return true
+		})
+		require.Equal(mt, expectedErr.Error(), err.Error())
+	})
+
+	mt.Run("should skip the counters and stop when the handler returns false", func(mt *mtest.T) {
+		mt.Parallel()
+
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: "key1"}, {Key: "value", Value: []byte("value1")}},
+				bson.D{{Key: "_id", Value: "counter"}, {Key: "value", Value: 5}},
+				bson.D{{Key: "_id", Value: "key2"}, {Key: "value", Value: []byte("value2")}},
+				bson.D{{Key: "_id", Value: "key3"}, {Key: "value", Value: []byte("value3")}},
+			),
+		)
+
+		client, err := mongodb.NewClient(mt.Client, "dbName", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+
+		entries := make(map[string]string)
+		err = client.RangeKeys(context.Background(), usersCollID, func(key []byte, val []byte) bool {
+			entries[string(key)] = string(val)
+			return len(entries) < 2
+		})
+		require.Nil(mt, err)
+		require.Equal(mt, map[string]string{"key1": "value1", "key2": "value2"}, entries)
+	})
+}
+
 func TestMongoDBClient_Get(t *testing.T) {
 	t.Parallel()
 
@@ -312,6 +541,123 @@ func TestMongoDBClient_Remove(t *testing.T) {
 	})
 }
 
+func TestMongoDBClient_PutIfNotExists(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("collection not found", func(mt *mtest.T) {
+		mt.Parallel()
+
+		client, err := mongodb.NewClient(mt.Client, "dbName", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+
+		wasPut, err := client.PutIfNotExists("another coll", []byte("key1"), []byte("data"))
+		require.Equal(mt, mongodb.ErrCollectionNotFound, err)
+		require.False(mt, wasPut)
+	})
+
+	mt.Run("should fail", func(mt *mtest.T) {
+		mt.Parallel()
+
+		mt.AddMockResponses(
+			mtest.CreateCommandErrorResponse(mtest.CommandError{
+				Code:    1,
+				Message: expectedErr.Error(),
+			}),
+		)
+
+		client, err := mongodb.NewClient(mt.Client, "dbName", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+
+		wasPut, err := client.PutIfNotExists(usersCollID, []byte("key1"), []byte("data"))
+		require.Equal(mt, expectedErr.Error(), err.Error())
+		require.False(mt, wasPut)
+	})
+
+	mt.Run("existing key should not be put", func(mt *mtest.T) {
+		mt.Parallel()
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(
+			bson.E{Key: "n", Value: 1},
+			bson.E{Key: "nModified", Value: 0},
+		))
+
+		client, err := mongodb.NewClient(mt.Client, "dbName", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+
+		wasPut, err := client.PutIfNotExists(usersCollID, []byte("key1"), []byte("data"))
+		require.Nil(mt, err)
+		require.False(mt, wasPut)
+	})
+
+	mt.Run("missing key should be put", func(mt *mtest.T) {
+		mt.Parallel()
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(
+			bson.E{Key: "n", Value: 1},
+			bson.E{Key: "nModified", Value: 0},
+			bson.E{Key: "upserted", Value: bson.A{
+				bson.D{{Key: "index", Value: 0}, {Key: "_id", Value: "key1"}},
+			}},
+		))
+
+		client, err := mongodb.NewClient(mt.Client, "dbName", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+
+		wasPut, err := client.PutIfNotExists(usersCollID, []byte("key1"), []byte("data"))
+		require.Nil(mt, err)
+		require.True(mt, wasPut)
+	})
+}
+
+func TestMongoDBClient_RemoveIfEqual(t *testing.T) {
+	t.Parallel()
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("collection not found", func(mt *mtest.T) {
+		mt.Parallel()
+
+		client, err := mongodb.NewClient(mt.Client, "dbName", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+
+		err = client.RemoveIfEqual("another coll", []byte("key1"), []byte("data"))
+		require.Equal(mt, mongodb.ErrCollectionNotFound, err)
+	})
+
+	mt.Run("should fail", func(mt *mtest.T) {
+		mt.Parallel()
+
+		mt.AddMockResponses(
+			mtest.CreateCommandErrorResponse(mtest.CommandError{
+				Code:    1,
+				Message: expectedErr.Error(),
+			}),
+		)
+
+		client, err := mongodb.NewClient(mt.Client, "dbName", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+
+		err = client.RemoveIfEqual(usersCollID, []byte("key1"), []byte("data"))
+		require.Equal(mt, expectedErr.Error(), err.Error())
+	})
+
+	mt.Run("should work", func(mt *mtest.T) {
+		mt.Parallel()
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+
+		client, err := mongodb.NewClient(mt.Client, "dbName", 4, &testscommon.StatusMetricsStub{})
+		require.Nil(mt, err)
+
+		err = client.RemoveIfEqual(usersCollID, []byte("key1"), []byte("data"))
+		require.Nil(mt, err)
+	})
+}
+
 func TestMongoDBClient_IncrementIndex(t *testing.T) {
 	t.Parallel()
 
@@ -10,3 +10,6 @@ var ErrEmptyMongoDBName = errors.New("empty db name")
 
 // ErrCollectionNotFound signals that provided mongodb collection is not available
 var ErrCollectionNotFound = errors.New("mongodb collection not found")
+
+// ErrEmptyUsersCollectionsPrefix signals that an empty prefix for the users collections has been provided
+var ErrEmptyUsersCollectionsPrefix = errors.New("empty users collections prefix")
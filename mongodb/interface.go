@@ -1,13 +1,25 @@
 package mongodb
 
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
 // MongoDBClient defines what a mongodb client should do
 type MongoDBClient interface {
 	Put(coll CollectionID, key []byte, data []byte) error
+	InsertOne(coll CollectionID, document interface{}) error
+	Find(coll CollectionID, filter interface{}, opts *options.FindOptions, results interface{}) error
 	Get(coll CollectionID, key []byte) ([]byte, error)
 	Has(coll CollectionID, key []byte) error
 	Remove(coll CollectionID, key []byte) error
+	PutIfNotExists(coll CollectionID, key []byte, data []byte) (bool, error)
+	RemoveIfEqual(coll CollectionID, key []byte, data []byte) error
+	RangeKeys(ctx context.Context, coll CollectionID, handler func(key []byte, val []byte) bool) error
 	GetIndex(collID CollectionID, key []byte) (uint32, error)
 	PutIndexIfNotExists(collID CollectionID, key []byte, index uint32) error
+	PutIndexIfGreater(collID CollectionID, key []byte, index uint32) error
 	IncrementIndex(collID CollectionID, key []byte) (uint32, error)
 	GetAllCollectionsIDs() []CollectionID
 	Close() error
@@ -43,7 +43,12 @@ func CreateMongoDBClient(cfg config.MongoDBConfig, metricsHandler core.StatusMet
 		return nil, err
 	}
 
-	return NewClient(client, cfg.DBName, cfg.NumUsersCollections, metricsHandler)
+	usersCollsPrefix := cfg.UsersCollectionsPrefix
+	if usersCollsPrefix == "" {
+		usersCollsPrefix = string(UsersCollectionID)
+	}
+
+	return NewClientWithUsersCollections(client, cfg.DBName, usersCollsPrefix, cfg.NumUsersCollections, metricsHandler)
 }
 
 func checkMongoDBConfig(cfg config.MongoDBConfig) error {
@@ -19,3 +19,6 @@ var ErrNilRedisClientWrapper = errors.New("nil redis client wrapper")
 
 // ErrRedisConnectionFailed signals that connection to redis failed
 var ErrRedisConnectionFailed = errors.New("error connecting to redis")
+
+// ErrUpdateConflict signals that a value could not be updated because it was changed concurrently too many times
+var ErrUpdateConflict = errors.New("value changed concurrently too many times")
@@ -0,0 +1,290 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+)
+
+type inMemoryEntry struct {
+	value    int64
+	data     []byte
+	members  map[string]struct{}
+	expireAt time.Time
+}
+
+func (entry *inMemoryEntry) hasExpiry() bool {
+	return !entry.expireAt.IsZero()
+}
+
+// inMemoryStorer is an in-process replacement of the redis client wrapper, meant for local development and
+// testing, when a single instance of the service is running. The expired keys are removed when accessed
+type inMemoryStorer struct {
+	entries map[string]*inMemoryEntry
+	mut     sync.Mutex
+}
+
+// NewInMemoryStorer will create a new in-memory storer component
+func NewInMemoryStorer() *inMemoryStorer {
+	return &inMemoryStorer{
+		entries: make(map[string]*inMemoryEntry),
+	}
+}
+
+// Increment will increment the value corresponding to the specified key, creating it if needed
+func (ims *inMemoryStorer) Increment(_ context.Context, key string) (int64, error) {
+	return ims.add(key, 1), nil
+}
+
+// Decrement will decrement the value corresponding to the specified key, creating it if needed
+func (ims *inMemoryStorer) Decrement(_ context.Context, key string) (int64, error) {
+	return ims.add(key, -1), nil
+}
+
+func (ims *inMemoryStorer) add(key string, delta int64) int64 {
+	ims.mut.Lock()
+	defer ims.mut.Unlock()
+
+	entry, found := ims.getEntry(key)
+	if !found {
+		entry = &inMemoryEntry{}
+		ims.entries[key] = entry
+	}
+	entry.value += delta
+
+	return entry.value
+}
+
+// SetExpire will set the specified ttl for the key, if it exists
+func (ims *inMemoryStorer) SetExpire(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	ims.mut.Lock()
+	defer ims.mut.Unlock()
+
+	entry, found := ims.getEntry(key)
+	if !found {
+		return false, nil
+	}
+
+	ims.setTTL(key, entry, ttl)
+
+	return true, nil
+}
+
+// SetExpireIfNotExists will set the specified ttl for the key, only if the key exists and has no ttl set yet
+func (ims *inMemoryStorer) SetExpireIfNotExists(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	ims.mut.Lock()
+	defer ims.mut.Unlock()
+
+	entry, found := ims.getEntry(key)
+	if !found || entry.hasExpiry() {
+		return false, nil
+	}
+
+	ims.setTTL(key, entry, ttl)
+
+	return true, nil
+}
+
+// SetPersist will remove the ttl of the specified key
+func (ims *inMemoryStorer) SetPersist(_ context.Context, key string) (bool, error) {
+	ims.mut.Lock()
+	defer ims.mut.Unlock()
+
+	entry, found := ims.getEntry(key)
+	if !found || !entry.hasExpiry() {
+		return false, nil
+	}
+
+	entry.expireAt = time.Time{}
+
+	return true, nil
+}
+
+// SetGreaterExpireTTL will set the specified ttl for the key, only if the new ttl is greater than the current one.
+// As for redis, a key without ttl is considered to have an infinite one
+func (ims *inMemoryStorer) SetGreaterExpireTTL(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	ims.mut.Lock()
+	defer ims.mut.Unlock()
+
+	entry, found := ims.getEntry(key)
+	if !found || !entry.hasExpiry() {
+		return false, nil
+	}
+	if !time.Now().Add(ttl).After(entry.expireAt) {
+		return false, nil
+	}
+
+	ims.setTTL(key, entry, ttl)
+
+	return true, nil
+}
+
+// must be called under mutex protection
+func (ims *inMemoryStorer) setTTL(key string, entry *inMemoryEntry, ttl time.Duration) {
+	if ttl <= 0 {
+		delete(ims.entries, key)
+		return
+	}
+
+	entry.expireAt = time.Now().Add(ttl)
+}
+
+// ResetCounterAndKeepTTL will reset the counter for the specified key, creating it if needed, but will keep its ttl
+func (ims *inMemoryStorer) ResetCounterAndKeepTTL(_ context.Context, key string) error {
+	ims.mut.Lock()
+	defer ims.mut.Unlock()
+
+	entry, found := ims.getEntry(key)
+	if !found {
+		ims.entries[key] = &inMemoryEntry{}
+		return nil
+	}
+
+	entry.value = 0
+
+	return nil
+}
+
+// ExpireTime will return the remaining ttl for the specified key, or core.NoExpiryValue if it has none
+func (ims *inMemoryStorer) ExpireTime(_ context.Context, key string) (time.Duration, error) {
+	ims.mut.Lock()
+	defer ims.mut.Unlock()
+
+	entry, found := ims.getEntry(key)
+	if !found {
+		return 0, ErrKeyNotExists
+	}
+	if !entry.hasExpiry() {
+		return time.Duration(core.NoExpiryValue), nil
+	}
+
+	return time.Until(entry.expireAt), nil
+}
+
+// GetCounter will return the value corresponding to the specified key, without changing it
+func (ims *inMemoryStorer) GetCounter(_ context.Context, key string) (int64, error) {
+	ims.mut.Lock()
+	defer ims.mut.Unlock()
+
+	entry, found := ims.getEntry(key)
+	if !found {
+		return 0, ErrKeyNotExists
+	}
+
+	return entry.value, nil
+}
+
+// GetValue will return the raw value corresponding to the specified key
+func (ims *inMemoryStorer) GetValue(_ context.Context, key string) ([]byte, error) {
+	ims.mut.Lock()
+	defer ims.mut.Unlock()
+
+	entry, found := ims.getEntry(key)
+	if !found {
+		return nil, ErrKeyNotExists
+	}
+
+	return entry.data, nil
+}
+
+// UpdateValue will replace the value of the specified key with the one returned by the update handler, setting the specified ttl.
+// The whole update runs under mutex protection, so it is atomic
+func (ims *inMemoryStorer) UpdateValue(_ context.Context, key string, ttl time.Duration, updateHandler func(value []byte) ([]byte, error)) error {
+	ims.mut.Lock()
+	defer ims.mut.Unlock()
+
+	var currentValue []byte
+	entry, found := ims.getEntry(key)
+	if found {
+		currentValue = entry.data
+	}
+
+	newValue, err := updateHandler(currentValue)
+	if err != nil {
+		return err
+	}
+
+	entry = &inMemoryEntry{
+		data: newValue,
+	}
+	ims.entries[key] = entry
+	if ttl > 0 {
+		ims.setTTL(key, entry, ttl)
+	}
+
+	return nil
+}
+
+// Delete will remove the specified keys
+func (ims *inMemoryStorer) Delete(_ context.Context, keys ...string) error {
+	ims.mut.Lock()
+	defer ims.mut.Unlock()
+
+	for _, key := range keys {
+		delete(ims.entries, key)
+	}
+
+	return nil
+}
+
+// AddToSet will add the member to the set stored at the specified key, refreshing the ttl of the set
+func (ims *inMemoryStorer) AddToSet(_ context.Context, key string, member string, ttl time.Duration) error {
+	ims.mut.Lock()
+	defer ims.mut.Unlock()
+
+	entry, found := ims.getEntry(key)
+	if !found {
+		entry = &inMemoryEntry{}
+		ims.entries[key] = entry
+	}
+	if entry.members == nil {
+		entry.members = make(map[string]struct{})
+	}
+	entry.members[member] = struct{}{}
+	ims.setTTL(key, entry, ttl)
+
+	return nil
+}
+
+// GetSetMembers will return the members of the set stored at the specified key, or an empty slice if the key does not exist
+func (ims *inMemoryStorer) GetSetMembers(_ context.Context, key string) ([]string, error) {
+	ims.mut.Lock()
+	defer ims.mut.Unlock()
+
+	members := make([]string, 0)
+	entry, found := ims.getEntry(key)
+	if !found {
+		return members, nil
+	}
+	for member := range entry.members {
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+// must be called under mutex protection
+func (ims *inMemoryStorer) getEntry(key string) (*inMemoryEntry, bool) {
+	entry, found := ims.entries[key]
+	if !found {
+		return nil, false
+	}
+	if entry.hasExpiry() && !time.Now().Before(entry.expireAt) {
+		delete(ims.entries, key)
+		return nil, false
+	}
+
+	return entry, true
+}
+
+// IsConnected returns true, as there is no connection to lose
+func (ims *inMemoryStorer) IsConnected(_ context.Context) bool {
+	return true
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ims *inMemoryStorer) IsInterfaceNil() bool {
+	return ims == nil
+}
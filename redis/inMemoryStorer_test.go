@@ -0,0 +1,347 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	redisClient "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/redis"
+)
+
+func createMiniredisStorer(t *testing.T) redis.RedisStorer {
+	server := miniredis.RunT(t)
+	rc := redisClient.NewClient(&redisClient.Options{
+		Addr: server.Addr(),
+	})
+
+	rcw, err := redis.NewRedisClientWrapper(rc)
+	require.Nil(t, err)
+
+	return rcw
+}
+
+func TestInMemoryStorer_ShouldBehaveAsRedis(t *testing.T) {
+	t.Parallel()
+
+	type result struct {
+		value int64
+		ok    bool
+		err   error
+	}
+	operations := []struct {
+		name string
+		run  func(storer redis.RedisStorer) result
+	}{
+		{"increment missing key", func(storer redis.RedisStorer) result {
+			value, err := storer.Increment(context.TODO(), "key1")
+			return result{value: value, err: err}
+		}},
+		{"decrement", func(storer redis.RedisStorer) result {
+			value, err := storer.Decrement(context.TODO(), "key1")
+			return result{value: value, err: err}
+		}},
+		{"decrement missing key", func(storer redis.RedisStorer) result {
+			value, err := storer.Decrement(context.TODO(), "key2")
+			return result{value: value, err: err}
+		}},
+		{"expire missing key", func(storer redis.RedisStorer) result {
+			ok, err := storer.SetExpire(context.TODO(), "missing", time.Minute)
+			return result{ok: ok, err: err}
+		}},
+		{"expire greater without ttl", func(storer redis.RedisStorer) result {
+			ok, err := storer.SetGreaterExpireTTL(context.TODO(), "key1", time.Minute)
+			return result{ok: ok, err: err}
+		}},
+		{"expire if not exists without ttl", func(storer redis.RedisStorer) result {
+			ok, err := storer.SetExpireIfNotExists(context.TODO(), "key1", time.Minute)
+			return result{ok: ok, err: err}
+		}},
+		{"expire if not exists with ttl", func(storer redis.RedisStorer) result {
+			ok, err := storer.SetExpireIfNotExists(context.TODO(), "key1", time.Hour)
+			return result{ok: ok, err: err}
+		}},
+		{"expire greater with smaller ttl", func(storer redis.RedisStorer) result {
+			ok, err := storer.SetGreaterExpireTTL(context.TODO(), "key1", time.Second)
+			return result{ok: ok, err: err}
+		}},
+		{"expire greater with greater ttl", func(storer redis.RedisStorer) result {
+			ok, err := storer.SetGreaterExpireTTL(context.TODO(), "key1", time.Hour)
+			return result{ok: ok, err: err}
+		}},
+		{"reset counter", func(storer redis.RedisStorer) result {
+			return result{err: storer.ResetCounterAndKeepTTL(context.TODO(), "key1")}
+		}},
+		{"increment after reset", func(storer redis.RedisStorer) result {
+			value, err := storer.Increment(context.TODO(), "key1")
+			return result{value: value, err: err}
+		}},
+		{"get counter", func(storer redis.RedisStorer) result {
+			value, err := storer.GetCounter(context.TODO(), "key1")
+			return result{value: value, err: err}
+		}},
+		{"get counter of missing key", func(storer redis.RedisStorer) result {
+			value, err := storer.GetCounter(context.TODO(), "missing")
+			return result{value: value, err: err}
+		}},
+		{"ttl kept after reset", func(storer redis.RedisStorer) result {
+			ttl, err := storer.ExpireTime(context.TODO(), "key1")
+			return result{ok: ttl > 59*time.Minute && ttl <= time.Hour, err: err}
+		}},
+		{"persist", func(storer redis.RedisStorer) result {
+			ok, err := storer.SetPersist(context.TODO(), "key1")
+			return result{ok: ok, err: err}
+		}},
+		{"persist again", func(storer redis.RedisStorer) result {
+			ok, err := storer.SetPersist(context.TODO(), "key1")
+			return result{ok: ok, err: err}
+		}},
+		{"persist missing key", func(storer redis.RedisStorer) result {
+			ok, err := storer.SetPersist(context.TODO(), "missing")
+			return result{ok: ok, err: err}
+		}},
+		{"ttl of persistent key", func(storer redis.RedisStorer) result {
+			ttl, err := storer.ExpireTime(context.TODO(), "key1")
+			return result{value: int64(ttl), err: err}
+		}},
+		{"ttl of missing key", func(storer redis.RedisStorer) result {
+			ttl, err := storer.ExpireTime(context.TODO(), "missing")
+			return result{value: int64(ttl), err: err}
+		}},
+		{"reset missing key", func(storer redis.RedisStorer) result {
+			return result{err: storer.ResetCounterAndKeepTTL(context.TODO(), "key3")}
+		}},
+		{"increment after reset of missing key", func(storer redis.RedisStorer) result {
+			value, err := storer.Increment(context.TODO(), "key3")
+			return result{value: value, err: err}
+		}},
+		{"delete", func(storer redis.RedisStorer) result {
+			return result{err: storer.Delete(context.TODO(), "key1", "missing")}
+		}},
+		{"increment after delete", func(storer redis.RedisStorer) result {
+			value, err := storer.Increment(context.TODO(), "key1")
+			return result{value: value, err: err}
+		}},
+		{"expire with negative ttl", func(storer redis.RedisStorer) result {
+			ok, err := storer.SetExpire(context.TODO(), "key3", -time.Second)
+			return result{ok: ok, err: err}
+		}},
+		{"ttl of key expired with negative ttl", func(storer redis.RedisStorer) result {
+			ttl, err := storer.ExpireTime(context.TODO(), "key3")
+			return result{value: int64(ttl), err: err}
+		}},
+	}
+
+	redisStorer := createMiniredisStorer(t)
+	inMemoryStorer := redis.NewInMemoryStorer()
+	for _, operation := range operations {
+		expectedResult := operation.run(redisStorer)
+		actualResult := operation.run(inMemoryStorer)
+		assert.Equal(t, expectedResult, actualResult, operation.name)
+	}
+}
+
+func TestInMemoryStorer_SetsShouldMatchAsRedis(t *testing.T) {
+	t.Parallel()
+
+	redisStorer := createMiniredisStorer(t)
+	inMemoryStorer := redis.NewInMemoryStorer()
+	for _, storer := range []redis.RedisStorer{redisStorer, inMemoryStorer} {
+		for _, member := range []string{"account:ip1", "account:ip2", "account:ip1"} {
+			err := storer.AddToSet(context.TODO(), "group", member, time.Minute)
+			require.Nil(t, err)
+		}
+	}
+
+	for _, key := range []string{"group", "missing"} {
+		expectedMembers, err := redisStorer.GetSetMembers(context.TODO(), key)
+		require.Nil(t, err)
+		actualMembers, err := inMemoryStorer.GetSetMembers(context.TODO(), key)
+		require.Nil(t, err)
+		assert.ElementsMatch(t, expectedMembers, actualMembers, key)
+
+		expectedTTL, expectedErr := redisStorer.ExpireTime(context.TODO(), key)
+		actualTTL, actualErr := inMemoryStorer.ExpireTime(context.TODO(), key)
+		assert.Equal(t, expectedErr, actualErr, key)
+		assert.Equal(t, expectedTTL > 59*time.Second, actualTTL > 59*time.Second, key)
+	}
+}
+
+func TestInMemoryStorer_ExpiredKeys(t *testing.T) {
+	t.Parallel()
+
+	storer := redis.NewInMemoryStorer()
+	require.False(t, storer.IsInterfaceNil())
+	require.True(t, storer.IsConnected(context.TODO()))
+
+	_, err := storer.Increment(context.TODO(), "key1")
+	require.Nil(t, err)
+	_, err = storer.Increment(context.TODO(), "key2")
+	require.Nil(t, err)
+	wasSet, err := storer.SetExpire(context.TODO(), "key1", 10*time.Millisecond)
+	require.Nil(t, err)
+	require.True(t, wasSet)
+
+	ttl, err := storer.ExpireTime(context.TODO(), "key1")
+	require.Nil(t, err)
+	require.True(t, ttl > 0 && ttl <= 10*time.Millisecond)
+	ttl, err = storer.ExpireTime(context.TODO(), "key2")
+	require.Nil(t, err)
+	require.Equal(t, time.Duration(core.NoExpiryValue), ttl)
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = storer.ExpireTime(context.TODO(), "key1")
+	require.Equal(t, redis.ErrKeyNotExists, err)
+	_, err = storer.ExpireTime(context.TODO(), "key2")
+	require.Nil(t, err)
+	value, err := storer.Increment(context.TODO(), "key1")
+	require.Nil(t, err)
+	require.Equal(t, int64(1), value)
+}
+
+func TestInMemoryStorer_ConcurrentOperations(t *testing.T) {
+	t.Parallel()
+
+	storer := redis.NewInMemoryStorer()
+
+	numCalls := 500
+	wg := sync.WaitGroup{}
+	wg.Add(numCalls)
+	for i := 0; i < numCalls; i++ {
+		go func(idx int) {
+			defer wg.Done()
+
+			var err error
+			switch idx % 6 {
+			case 0:
+				_, err = storer.Increment(context.TODO(), "key1")
+			case 1:
+				_, err = storer.ExpireTime(context.TODO(), "key1")
+				if errors.Is(err, redis.ErrKeyNotExists) {
+					err = nil
+				}
+			case 2:
+				_, err = storer.SetExpire(context.TODO(), "key1", time.Millisecond)
+			case 3:
+				_, err = storer.SetPersist(context.TODO(), "key1")
+			case 4:
+				err = storer.AddToSet(context.TODO(), "key2", "key1", time.Millisecond)
+			case 5:
+				err = storer.Delete(context.TODO(), "key1")
+			}
+			assert.Nil(t, err)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestStorers_GetAndUpdateValue(t *testing.T) {
+	t.Parallel()
+
+	storers := map[string]redis.RedisStorer{
+		"miniredis": createMiniredisStorer(t),
+		"in-memory": redis.NewInMemoryStorer(),
+	}
+	for name, storer := range storers {
+		_, err := storer.GetValue(context.TODO(), "key1")
+		require.Equal(t, redis.ErrKeyNotExists, err, name)
+
+		err = storer.UpdateValue(context.TODO(), "key1", time.Minute, func(value []byte) ([]byte, error) {
+			require.Empty(t, value, name)
+			return []byte("value1"), nil
+		})
+		require.Nil(t, err, name)
+
+		expectedErr := errors.New("expected error")
+		err = storer.UpdateValue(context.TODO(), "key1", time.Minute, func(value []byte) ([]byte, error) {
+			require.Equal(t, []byte("value1"), value, name)
+			return []byte("value2"), expectedErr
+		})
+		require.Equal(t, expectedErr, err, name)
+
+		value, err := storer.GetValue(context.TODO(), "key1")
+		require.Nil(t, err, name)
+		require.Equal(t, []byte("value1"), value, name)
+		ttl, err := storer.ExpireTime(context.TODO(), "key1")
+		require.Nil(t, err, name)
+		require.True(t, ttl > 0 && ttl <= time.Minute, name)
+	}
+}
+
+func TestStorers_ConcurrentUpdateValue(t *testing.T) {
+	t.Parallel()
+
+	storers := map[string]redis.RedisStorer{
+		"miniredis": createMiniredisStorer(t),
+		"in-memory": redis.NewInMemoryStorer(),
+	}
+	for name, storer := range storers {
+		numCalls := 5
+		wg := sync.WaitGroup{}
+		wg.Add(numCalls)
+		for i := 0; i < numCalls; i++ {
+			go func() {
+				defer wg.Done()
+
+				err := storer.UpdateValue(context.TODO(), "key1", time.Minute, func(value []byte) ([]byte, error) {
+					return append(value, 'a'), nil
+				})
+				assert.Nil(t, err, name)
+			}()
+		}
+		wg.Wait()
+
+		value, err := storer.GetValue(context.TODO(), "key1")
+		require.Nil(t, err, name)
+		require.Equal(t, []byte("aaaaa"), value, name)
+	}
+}
+
+func TestCreateRedisRateLimiter_InMemory(t *testing.T) {
+	t.Parallel()
+
+	redisCfg := config.RedisConfig{
+		ConnectionType:        string(core.RedisMemoryConnType),
+		OperationTimeoutInSec: 1,
+	}
+	twoFactorCfg := config.TwoFactorConfig{
+		MaxFailures:                      2,
+		BackoffTimeInSeconds:             60,
+		SecurityModeMaxFailures:          10,
+		SecurityModeBackoffTimeInSeconds: 3600,
+	}
+	redisStorer, err := redis.CreateRedisStorer(redisCfg)
+	require.Nil(t, err)
+	rateLimiter, err := redis.CreateRedisRateLimiter(redisStorer, redisCfg, twoFactorCfg)
+	require.Nil(t, err)
+
+	res, err := rateLimiter.CheckAllowedAndIncreaseTrials("key", redis.NormalMode)
+	require.Nil(t, err)
+	require.Equal(t, &redis.RateLimiterResult{Allowed: true, Remaining: 1, ResetAfter: time.Minute}, res)
+	res, err = rateLimiter.CheckAllowedAndIncreaseTrials("key", redis.NormalMode)
+	require.Nil(t, err)
+	require.True(t, res.Allowed)
+	require.Equal(t, 0, res.Remaining)
+	res, err = rateLimiter.CheckAllowedAndIncreaseTrials("key", redis.NormalMode)
+	require.Nil(t, err)
+	require.False(t, res.Allowed)
+	require.True(t, res.LimitReached)
+
+	require.Nil(t, rateLimiter.Remove("key"))
+	res, err = rateLimiter.CheckAllowedAndIncreaseTrials("key", redis.NormalMode)
+	require.Nil(t, err)
+	require.True(t, res.Allowed)
+
+	publisher, err := redis.CreateRedisPublisher(redisCfg)
+	require.Nil(t, publisher)
+	require.True(t, errors.Is(err, core.ErrInvalidRedisConnType))
+}
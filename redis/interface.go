@@ -17,7 +17,11 @@ const (
 // RateLimiter defines the behaviour of a rate limiter component
 type RateLimiter interface {
 	CheckAllowedAndIncreaseTrials(key string, mode Mode) (*RateLimiterResult, error)
+	CheckAllowedAndIncreaseTrialsInGroup(group string, key string, mode Mode) (*RateLimiterResult, error)
+	GetTrials(key string, mode Mode) (*RateLimiterResult, error)
 	Reset(key string) error
+	Remove(key string) error
+	RemoveGroup(group string) error
 	SetSecurityModeNoExpire(key string) error
 	UnsetSecurityModeNoExpire(key string) error
 	DecrementSecurityFailedTrials(key string) error
@@ -37,6 +41,12 @@ type RedisStorer interface {
 	SetGreaterExpireTTL(ctx context.Context, key string, ttl time.Duration) (bool, error)
 	ResetCounterAndKeepTTL(ctx context.Context, key string) error
 	ExpireTime(ctx context.Context, key string) (time.Duration, error)
+	GetCounter(ctx context.Context, key string) (int64, error)
+	GetValue(ctx context.Context, key string) ([]byte, error)
+	UpdateValue(ctx context.Context, key string, ttl time.Duration, updateHandler func(value []byte) ([]byte, error)) error
+	Delete(ctx context.Context, keys ...string) error
+	AddToSet(ctx context.Context, key string, member string, ttl time.Duration) error
+	GetSetMembers(ctx context.Context, key string) ([]string, error)
 	IsConnected(ctx context.Context) bool
 	IsInterfaceNil() bool
 }
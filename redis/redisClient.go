@@ -8,7 +8,8 @@ import (
 )
 
 const (
-	pongValue = "PONG"
+	pongValue        = "PONG"
+	maxUpdateRetries = 10
 )
 
 // redisClientWrapper defines a wrapper over redis client
@@ -66,6 +67,58 @@ func (r *redisClientWrapper) ResetCounterAndKeepTTL(ctx context.Context, key str
 	return err
 }
 
+// GetCounter will return the value corresponding to the specified key, without changing it
+func (r *redisClientWrapper) GetCounter(ctx context.Context, key string) (int64, error) {
+	value, err := r.client.Get(ctx, key).Int64()
+	if err == redis.Nil {
+		return 0, ErrKeyNotExists
+	}
+
+	return value, err
+}
+
+// GetValue will return the raw value corresponding to the specified key
+func (r *redisClientWrapper) GetValue(ctx context.Context, key string) ([]byte, error) {
+	value, err := r.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, ErrKeyNotExists
+	}
+
+	return value, err
+}
+
+// UpdateValue will replace the value of the specified key with the one returned by the update handler, setting the specified ttl.
+// The key is watched, so the update is retried if another client changes it meanwhile. A nil value is passed to the
+// handler if the key does not exist, and nothing is saved if the handler returns an error
+func (r *redisClientWrapper) UpdateValue(ctx context.Context, key string, ttl time.Duration, updateHandler func(value []byte) ([]byte, error)) error {
+	txHandler := func(tx *redis.Tx) error {
+		value, err := tx.Get(ctx, key).Bytes()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+
+		newValue, err := updateHandler(value)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, newValue, ttl)
+			return nil
+		})
+		return err
+	}
+
+	for i := 0; i < maxUpdateRetries; i++ {
+		err := r.client.Watch(ctx, txHandler, key)
+		if err != redis.TxFailedErr {
+			return err
+		}
+	}
+
+	return ErrUpdateConflict
+}
+
 // ExpireTime will return expire time for the specified key
 func (r *redisClientWrapper) ExpireTime(ctx context.Context, key string) (time.Duration, error) {
 	expTime, err := r.client.TTL(ctx, key).Result()
@@ -79,6 +132,41 @@ func (r *redisClientWrapper) ExpireTime(ctx context.Context, key string) (time.D
 	return expTime, nil
 }
 
+// Delete will remove the specified keys
+func (r *redisClientWrapper) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return r.client.Del(ctx, keys...).Err()
+}
+
+// AddToSet will add the member to the set stored at the specified key, refreshing the ttl of the set
+func (r *redisClientWrapper) AddToSet(ctx context.Context, key string, member string, ttl time.Duration) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, key, member)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+
+	return err
+}
+
+// GetSetMembers will return the members of the set stored at the specified key, or an empty slice if the key does not exist
+func (r *redisClientWrapper) GetSetMembers(ctx context.Context, key string) ([]string, error) {
+	return r.client.SMembers(ctx, key).Result()
+}
+
+// Publish will post the message on the specified pub/sub channel
+func (r *redisClientWrapper) Publish(ctx context.Context, channel string, message []byte) error {
+	return r.client.Publish(ctx, channel, message).Err()
+}
+
+// Close will close the underlying redis client
+func (r *redisClientWrapper) Close() error {
+	return r.client.Close()
+}
+
 // IsConnected will check if redis client is connected
 func (r *redisClientWrapper) IsConnected(ctx context.Context) bool {
 	pong, err := r.client.Ping(ctx).Result()
@@ -86,6 +86,13 @@ func TestOperations(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, int64(2), retries)
 
+	retries, err = rcw.GetCounter(context.TODO(), "key1")
+	require.Nil(t, err)
+	require.Equal(t, int64(2), retries)
+
+	_, err = rcw.GetCounter(context.TODO(), "invalidKey")
+	require.Equal(t, redis.ErrKeyNotExists, err)
+
 	wasSet, err = rcw.SetPersist(context.TODO(), "key1")
 	require.Nil(t, err)
 	require.True(t, wasSet)
@@ -102,6 +109,73 @@ func TestOperations(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestPublish(t *testing.T) {
+	t.Parallel()
+
+	server := miniredis.RunT(t)
+	rc := redisClient.NewClient(&redisClient.Options{
+		Addr: server.Addr(),
+	})
+
+	rcw, err := redis.NewRedisClientWrapper(rc)
+	require.Nil(t, err)
+
+	subscriber := rc.Subscribe(context.TODO(), "channel")
+	defer func() {
+		_ = subscriber.Close()
+	}()
+	_, err = subscriber.Receive(context.TODO())
+	require.Nil(t, err)
+
+	err = rcw.Publish(context.TODO(), "channel", []byte("message"))
+	require.Nil(t, err)
+
+	msg, err := subscriber.ReceiveMessage(context.TODO())
+	require.Nil(t, err)
+	assert.Equal(t, "channel", msg.Channel)
+	assert.Equal(t, "message", msg.Payload)
+
+	require.Nil(t, rcw.Close())
+	require.NotNil(t, rcw.Publish(context.TODO(), "channel", []byte("message")))
+}
+
+func TestDeleteAndSetMembers(t *testing.T) {
+	t.Parallel()
+
+	server := miniredis.RunT(t)
+	rc := redisClient.NewClient(&redisClient.Options{
+		Addr: server.Addr(),
+	})
+
+	rcw, err := redis.NewRedisClientWrapper(rc)
+	require.Nil(t, err)
+
+	members, err := rcw.GetSetMembers(context.TODO(), "group")
+	require.Nil(t, err)
+	assert.Empty(t, members)
+
+	for _, key := range []string{"account:ip1", "account:ip2", "account:ip1"} {
+		err = rcw.AddToSet(context.TODO(), "group", key, time.Minute)
+		require.Nil(t, err)
+	}
+	require.Equal(t, time.Minute, server.TTL("group"))
+
+	members, err = rcw.GetSetMembers(context.TODO(), "group")
+	require.Nil(t, err)
+	assert.ElementsMatch(t, []string{"account:ip1", "account:ip2"}, members)
+
+	_, err = rcw.Increment(context.TODO(), "account")
+	require.Nil(t, err)
+
+	err = rcw.Delete(context.TODO())
+	require.Nil(t, err)
+
+	err = rcw.Delete(context.TODO(), "group", "missing")
+	require.Nil(t, err)
+	require.False(t, server.Exists("group"))
+	require.True(t, server.Exists("account"))
+}
+
 func TestConcurrentOperations(t *testing.T) {
 	t.Parallel()
 
@@ -2,6 +2,7 @@ package redis
 
 import (
 	"context"
+	"fmt"
 
 	logger "github.com/multiversx/mx-chain-logger-go"
 	"github.com/redis/go-redis/v9"
@@ -12,22 +13,8 @@ import (
 
 var log = logger.GetOrCreate("redis")
 
-// CreateRedisRateLimiter will create a new redis rate limiter component
-func CreateRedisRateLimiter(cfg config.RedisConfig, twoFactorCfg config.TwoFactorConfig) (RateLimiter, error) {
-	client, err := createRedisClient(cfg)
-	if err != nil {
-		return nil, err
-	}
-	redisStorer, err := NewRedisClientWrapper(client)
-	if err != nil {
-		return nil, err
-	}
-
-	ok := redisStorer.IsConnected(context.Background())
-	if !ok {
-		return nil, ErrRedisConnectionFailed
-	}
-
+// CreateRedisRateLimiter will create a new redis rate limiter component over the provided redis storer
+func CreateRedisRateLimiter(redisStorer RedisStorer, cfg config.RedisConfig, twoFactorCfg config.TwoFactorConfig) (RateLimiter, error) {
 	rateLimiterArgs := ArgsRateLimiter{
 		OperationTimeoutInSec: cfg.OperationTimeoutInSec,
 		FreezeFailureConfig: FailureConfig{
@@ -43,6 +30,43 @@ func CreateRedisRateLimiter(cfg config.RedisConfig, twoFactorCfg config.TwoFacto
 	return NewRateLimiter(rateLimiterArgs)
 }
 
+// CreateRedisStorer will create a new redis storer component, or an in-memory one for the memory connection type
+func CreateRedisStorer(cfg config.RedisConfig) (RedisStorer, error) {
+	if core.RedisConnType(cfg.ConnectionType) == core.RedisMemoryConnType {
+		log.Warn("using the in-memory redis storer, the stored values are neither persisted nor shared between instances")
+		return NewInMemoryStorer(), nil
+	}
+
+	return createConnectedClientWrapper(cfg)
+}
+
+// CreateRedisPublisher will create a new redis client wrapper, used to publish on pub/sub channels
+func CreateRedisPublisher(cfg config.RedisConfig) (*redisClientWrapper, error) {
+	if core.RedisConnType(cfg.ConnectionType) == core.RedisMemoryConnType {
+		return nil, fmt.Errorf("%w, publishing requires a redis server", core.ErrInvalidRedisConnType)
+	}
+
+	return createConnectedClientWrapper(cfg)
+}
+
+func createConnectedClientWrapper(cfg config.RedisConfig) (*redisClientWrapper, error) {
+	client, err := createRedisClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	clientWrapper, err := NewRedisClientWrapper(client)
+	if err != nil {
+		return nil, err
+	}
+
+	ok := clientWrapper.IsConnected(context.Background())
+	if !ok {
+		return nil, ErrRedisConnectionFailed
+	}
+
+	return clientWrapper, nil
+}
+
 func createRedisClient(cfg config.RedisConfig) (*redis.Client, error) {
 	switch core.RedisConnType(cfg.ConnectionType) {
 	case core.RedisInstanceConnType:
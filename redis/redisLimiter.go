@@ -15,6 +15,7 @@ const (
 	minLimitPeriodInSec      = 1
 	minMaxFailures           = 1
 	minOperationTimeoutInSec = 1
+	groupKeyPrefix           = "group:"
 )
 
 // RateLimiterResult defines rate limiter result
@@ -35,6 +36,10 @@ type RateLimiterResult struct {
 	// manages requests per minute and received one request 20s ago,
 	// reset after would return 40s
 	ResetAfter time.Duration
+
+	// LimitReached specifies if this request was the first one not allowed
+	// in the current period, so the freeze or the security mode has just been triggered
+	LimitReached bool
 }
 
 // FailureConfig defines the configuration for the rate limiter failure configuration
@@ -116,7 +121,7 @@ func (rl *rateLimiter) CheckAllowedAndIncreaseTrials(key string, mode Mode) (*Ra
 	ctx, cancel := context.WithTimeout(context.Background(), rl.operationTimeout)
 	defer cancel()
 
-	res, err := rl.rateLimit(ctx, key, mode)
+	res, err := rl.rateLimit(ctx, "", key, mode)
 	if err != nil {
 		return nil, err
 	}
@@ -124,7 +129,21 @@ func (rl *rateLimiter) CheckAllowedAndIncreaseTrials(key string, mode Mode) (*Ra
 	return res, nil
 }
 
-func (rl *rateLimiter) rateLimit(ctx context.Context, key string, mode Mode) (*RateLimiterResult, error) {
+// CheckAllowedAndIncreaseTrialsInGroup works as CheckAllowedAndIncreaseTrials, also recording the key in the provided group
+// when its trials start, so all the keys of the group can be removed at once with RemoveGroup
+func (rl *rateLimiter) CheckAllowedAndIncreaseTrialsInGroup(group string, key string, mode Mode) (*RateLimiterResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rl.operationTimeout)
+	defer cancel()
+
+	res, err := rl.rateLimit(ctx, group, key, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (rl *rateLimiter) rateLimit(ctx context.Context, group string, key string, mode Mode) (*RateLimiterResult, error) {
 	rl.mutStorer.Lock()
 	defer rl.mutStorer.Unlock()
 
@@ -145,12 +164,62 @@ func (rl *rateLimiter) rateLimit(ctx context.Context, key string, mode Mode) (*R
 
 	firstTry := totalRetries == 1
 	if firstTry {
-		return rl.setAndGetLimiterFirstTimeResult(ctx, key, mode)
+		return rl.setAndGetLimiterFirstTimeResult(ctx, group, key, mode)
 	}
 
 	return rl.getLimiterResult(ctx, key, totalRetries, mode)
 }
 
+// GetTrials will return the rate limits for the specified key, without increasing the number of trials. A key
+// without failed trials is allowed, with all the trials remaining and nothing to reset
+func (rl *rateLimiter) GetTrials(key string, mode Mode) (*RateLimiterResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rl.operationTimeout)
+	defer cancel()
+
+	rl.mutStorer.Lock()
+	defer rl.mutStorer.Unlock()
+
+	_, maxFailures := rl.getFailConfig(mode)
+	notLimitedResult := &RateLimiterResult{
+		Allowed:   true,
+		Remaining: int(maxFailures),
+	}
+
+	expTime, err := rl.storer.ExpireTime(ctx, key)
+	if err == ErrKeyNotExists {
+		return notLimitedResult, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if expTime == core.NoExpiryValue {
+		return &RateLimiterResult{
+			Allowed:    false,
+			Remaining:  0,
+			ResetAfter: time.Duration(core.NoExpiryValue) * time.Second,
+		}, nil
+	}
+
+	totalRetries, err := rl.storer.GetCounter(ctx, key)
+	if err == ErrKeyNotExists {
+		return notLimitedResult, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := maxFailures - totalRetries
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return &RateLimiterResult{
+		Allowed:    totalRetries <= maxFailures,
+		Remaining:  int(remaining),
+		ResetAfter: expTime,
+	}, nil
+}
+
 func (rl *rateLimiter) getLimiterResult(ctx context.Context, key string, totalRetries int64, mode Mode) (*RateLimiterResult, error) {
 	allowed := true
 	_, maxFailures := rl.getFailConfig(mode)
@@ -159,6 +228,7 @@ func (rl *rateLimiter) getLimiterResult(ctx context.Context, key string, totalRe
 		remaining = 0
 		allowed = false
 	}
+	limitReached := totalRetries == maxFailures+1
 
 	expTime, err := rl.storer.ExpireTime(ctx, key)
 	if err != nil {
@@ -166,13 +236,14 @@ func (rl *rateLimiter) getLimiterResult(ctx context.Context, key string, totalRe
 	}
 
 	return &RateLimiterResult{
-		Allowed:    allowed,
-		Remaining:  int(remaining),
-		ResetAfter: expTime,
+		Allowed:      allowed,
+		Remaining:    int(remaining),
+		ResetAfter:   expTime,
+		LimitReached: limitReached,
 	}, nil
 }
 
-func (rl *rateLimiter) setAndGetLimiterFirstTimeResult(ctx context.Context, key string, mode Mode) (*RateLimiterResult, error) {
+func (rl *rateLimiter) setAndGetLimiterFirstTimeResult(ctx context.Context, group string, key string, mode Mode) (*RateLimiterResult, error) {
 	limitPeriod, maxFailures := rl.getFailConfig(mode)
 
 	_, err := rl.storer.SetExpire(ctx, key, limitPeriod)
@@ -180,6 +251,14 @@ func (rl *rateLimiter) setAndGetLimiterFirstTimeResult(ctx context.Context, key
 		return nil, err
 	}
 
+	if len(group) > 0 {
+		// the group gets the same ttl as the key, so it does not expire before any of its keys
+		err = rl.storer.AddToSet(ctx, computeGroupKey(group), key, limitPeriod)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &RateLimiterResult{
 		Allowed:    true,
 		Remaining:  int(maxFailures - 1),
@@ -244,6 +323,38 @@ func (rl *rateLimiter) Reset(key string) error {
 	return err
 }
 
+// Remove will remove the provided key, along with its ttl
+func (rl *rateLimiter) Remove(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), rl.operationTimeout)
+	defer cancel()
+
+	rl.mutStorer.Lock()
+	defer rl.mutStorer.Unlock()
+
+	return rl.storer.Delete(ctx, key)
+}
+
+// RemoveGroup will remove all the keys recorded in the provided group, along with the group itself
+func (rl *rateLimiter) RemoveGroup(group string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), rl.operationTimeout)
+	defer cancel()
+
+	rl.mutStorer.Lock()
+	defer rl.mutStorer.Unlock()
+
+	groupKey := computeGroupKey(group)
+	keys, err := rl.storer.GetSetMembers(ctx, groupKey)
+	if err != nil {
+		return err
+	}
+
+	return rl.storer.Delete(ctx, append(keys, groupKey)...)
+}
+
+func computeGroupKey(group string) string {
+	return groupKeyPrefix + group
+}
+
 // DecrementSecurityFailedTrials will decrement the number of security retrials for the specified key
 func (rl *rateLimiter) DecrementSecurityFailedTrials(key string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), rl.operationTimeout)
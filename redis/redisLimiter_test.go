@@ -271,6 +271,7 @@ func TestCheckAllowed(t *testing.T) {
 
 		require.Equal(t, true, res.Allowed)
 		require.Equal(t, expRemaining, res.Remaining)
+		require.False(t, res.LimitReached)
 		require.Equal(t, expRetryAfter, res.ResetAfter)
 		require.True(t, wasExpireTimeCalled)
 		require.False(t, wasSetExpireCalled)
@@ -337,10 +338,172 @@ func TestCheckAllowed(t *testing.T) {
 			require.Equal(t, false, res.Allowed)
 			require.Equal(t, data.expectedRemaining, res.Remaining)
 			require.Equal(t, data.expectedResetAfter, res.ResetAfter)
+			require.True(t, res.LimitReached)
 		}
 	})
 }
 
+func TestCheckAllowedInGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns err on storer add to set fail", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockRateLimiterArgs()
+		args.Storer = &testscommon.RedisClientStub{
+			IncrementCalled: func(ctx context.Context, key string) (int64, error) {
+				return 1, nil // first try
+			},
+			AddToSetCalled: func(ctx context.Context, key string, member string, ttl time.Duration) error {
+				return expectedErr
+			},
+		}
+
+		rl, err := redis.NewRateLimiter(args)
+		require.Nil(t, err)
+
+		res, err := rl.CheckAllowedAndIncreaseTrialsInGroup("group", "key", redis.NormalMode)
+		require.Equal(t, expectedErr, err)
+		require.Nil(t, res)
+	})
+	t.Run("should add the key to the group only on first try", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockRateLimiterArgs()
+		args.FreezeFailureConfig.LimitPeriodInSec = 10
+
+		totalRetries := int64(0)
+		addedMembers := make([]string, 0)
+		args.Storer = &testscommon.RedisClientStub{
+			IncrementCalled: func(ctx context.Context, key string) (int64, error) {
+				totalRetries++
+				return totalRetries, nil
+			},
+			AddToSetCalled: func(ctx context.Context, key string, member string, ttl time.Duration) error {
+				require.Equal(t, "group:account", key)
+				require.Equal(t, 10*time.Second, ttl)
+				addedMembers = append(addedMembers, member)
+				return nil
+			},
+		}
+
+		rl, err := redis.NewRateLimiter(args)
+		require.Nil(t, err)
+
+		for i := 0; i < 2; i++ {
+			res, errCheck := rl.CheckAllowedAndIncreaseTrialsInGroup("account", "account:ip", redis.NormalMode)
+			require.Nil(t, errCheck)
+			require.True(t, res.Allowed)
+		}
+		require.Equal(t, []string{"account:ip"}, addedMembers)
+	})
+	t.Run("should not add the key to any group without group", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockRateLimiterArgs()
+		args.Storer = &testscommon.RedisClientStub{
+			IncrementCalled: func(ctx context.Context, key string) (int64, error) {
+				return 1, nil
+			},
+			AddToSetCalled: func(ctx context.Context, key string, member string, ttl time.Duration) error {
+				require.Fail(t, "should have not been called")
+				return nil
+			},
+		}
+
+		rl, err := redis.NewRateLimiter(args)
+		require.Nil(t, err)
+
+		_, err = rl.CheckAllowedAndIncreaseTrials("key", redis.NormalMode)
+		require.Nil(t, err)
+	})
+}
+
+func TestGetTrials(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns err on storer get expire fail", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockRateLimiterArgs()
+		args.Storer = &testscommon.RedisClientStub{
+			ExpireTimeCalled: func(ctx context.Context, key string) (time.Duration, error) {
+				return 0, expectedErr
+			},
+		}
+		rl, _ := redis.NewRateLimiter(args)
+
+		res, err := rl.GetTrials("key", redis.NormalMode)
+		require.Equal(t, expectedErr, err)
+		require.Nil(t, res)
+	})
+	t.Run("returns err on storer get counter fail", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockRateLimiterArgs()
+		args.Storer = &testscommon.RedisClientStub{
+			ExpireTimeCalled: func(ctx context.Context, key string) (time.Duration, error) {
+				return time.Second, nil
+			},
+			GetCounterCalled: func(ctx context.Context, key string) (int64, error) {
+				return 0, expectedErr
+			},
+		}
+		rl, _ := redis.NewRateLimiter(args)
+
+		res, err := rl.GetTrials("key", redis.NormalMode)
+		require.Equal(t, expectedErr, err)
+		require.Nil(t, res)
+	})
+	t.Run("should not increase the trials", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockRateLimiterArgs()
+		args.Storer = redis.NewInMemoryStorer()
+		rl, _ := redis.NewRateLimiter(args)
+
+		res, err := rl.GetTrials("key", redis.NormalMode)
+		require.Nil(t, err)
+		assert.Equal(t, &redis.RateLimiterResult{Allowed: true, Remaining: 3}, res)
+
+		_, _ = rl.CheckAllowedAndIncreaseTrials("key", redis.NormalMode)
+		for i := 0; i < 2; i++ {
+			res, err = rl.GetTrials("key", redis.NormalMode)
+			require.Nil(t, err)
+			assert.True(t, res.Allowed)
+			assert.Equal(t, 2, res.Remaining)
+			assert.True(t, res.ResetAfter > 0 && res.ResetAfter <= time.Minute)
+			assert.False(t, res.LimitReached)
+		}
+
+		for i := 0; i < 3; i++ {
+			_, _ = rl.CheckAllowedAndIncreaseTrials("key", redis.NormalMode)
+		}
+		res, err = rl.GetTrials("key", redis.NormalMode)
+		require.Nil(t, err)
+		assert.False(t, res.Allowed)
+		assert.Equal(t, 0, res.Remaining)
+	})
+	t.Run("persistent key should not be allowed", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockRateLimiterArgs()
+		args.Storer = redis.NewInMemoryStorer()
+		rl, _ := redis.NewRateLimiter(args)
+
+		_, _ = rl.CheckAllowedAndIncreaseTrials("key", redis.SecurityMode)
+		require.Nil(t, rl.SetSecurityModeNoExpire("key"))
+
+		res, err := rl.GetTrials("key", redis.SecurityMode)
+		require.Nil(t, err)
+		assert.Equal(t, &redis.RateLimiterResult{
+			Allowed:    false,
+			Remaining:  0,
+			ResetAfter: time.Duration(core.NoExpiryValue) * time.Second,
+		}, res)
+	})
+}
+
 func TestReset(t *testing.T) {
 	t.Parallel()
 
@@ -385,6 +548,142 @@ func TestReset(t *testing.T) {
 	})
 }
 
+func TestRemove(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockRateLimiterArgs()
+		args.Storer = &testscommon.RedisClientStub{
+			DeleteCalled: func(ctx context.Context, keys ...string) error {
+				return expectedErr
+			},
+		}
+
+		rl, err := redis.NewRateLimiter(args)
+		require.Nil(t, err)
+
+		err = rl.Remove("key")
+		require.Equal(t, expectedErr, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockRateLimiterArgs()
+
+		deletedKeys := make([]string, 0)
+		args.Storer = &testscommon.RedisClientStub{
+			DeleteCalled: func(ctx context.Context, keys ...string) error {
+				deletedKeys = append(deletedKeys, keys...)
+				return nil
+			},
+		}
+
+		rl, err := redis.NewRateLimiter(args)
+		require.Nil(t, err)
+
+		err = rl.Remove("key")
+		require.Nil(t, err)
+		require.Equal(t, []string{"key"}, deletedKeys)
+	})
+}
+
+func TestRemoveGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("get set members fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockRateLimiterArgs()
+		args.Storer = &testscommon.RedisClientStub{
+			GetSetMembersCalled: func(ctx context.Context, key string) ([]string, error) {
+				return nil, expectedErr
+			},
+			DeleteCalled: func(ctx context.Context, keys ...string) error {
+				require.Fail(t, "should have not been called")
+				return nil
+			},
+		}
+
+		rl, err := redis.NewRateLimiter(args)
+		require.Nil(t, err)
+
+		err = rl.RemoveGroup("key")
+		require.Equal(t, expectedErr, err)
+	})
+	t.Run("delete fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockRateLimiterArgs()
+		args.Storer = &testscommon.RedisClientStub{
+			GetSetMembersCalled: func(ctx context.Context, key string) ([]string, error) {
+				return []string{"key:ip"}, nil
+			},
+			DeleteCalled: func(ctx context.Context, keys ...string) error {
+				return expectedErr
+			},
+		}
+
+		rl, err := redis.NewRateLimiter(args)
+		require.Nil(t, err)
+
+		err = rl.RemoveGroup("key")
+		require.Equal(t, expectedErr, err)
+	})
+	t.Run("should delete the keys of the group and the group", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockRateLimiterArgs()
+
+		deletedKeys := make([]string, 0)
+		args.Storer = &testscommon.RedisClientStub{
+			GetSetMembersCalled: func(ctx context.Context, key string) ([]string, error) {
+				require.Equal(t, "group:key", key)
+				return []string{"key:ip1", "key:ip2"}, nil
+			},
+			DeleteCalled: func(ctx context.Context, keys ...string) error {
+				deletedKeys = append(deletedKeys, keys...)
+				return nil
+			},
+		}
+
+		rl, err := redis.NewRateLimiter(args)
+		require.Nil(t, err)
+
+		err = rl.RemoveGroup("key")
+		require.Nil(t, err)
+		require.Equal(t, []string{"key:ip1", "key:ip2", "group:key"}, deletedKeys)
+	})
+	t.Run("should remove only the keys of the group", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockRateLimiterArgs()
+		args.Storer = redis.NewInMemoryStorer()
+
+		rl, err := redis.NewRateLimiter(args)
+		require.Nil(t, err)
+
+		for _, key := range []string{"key:ip1", "key:ip2"} {
+			_, err = rl.CheckAllowedAndIncreaseTrialsInGroup("key", key, redis.NormalMode)
+			require.Nil(t, err)
+		}
+		_, err = rl.CheckAllowedAndIncreaseTrialsInGroup("other", "other:ip1", redis.NormalMode)
+		require.Nil(t, err)
+		_, err = rl.CheckAllowedAndIncreaseTrials("key:ip3", redis.NormalMode)
+		require.Nil(t, err)
+
+		err = rl.RemoveGroup("key")
+		require.Nil(t, err)
+
+		for key, expectedRemaining := range map[string]int{"key:ip1": 3, "key:ip2": 3, "other:ip1": 2, "key:ip3": 2} {
+			res, errGet := rl.GetTrials(key, redis.NormalMode)
+			require.Nil(t, errGet)
+			require.Equal(t, expectedRemaining, res.Remaining, key)
+		}
+	})
+}
+
 func TestSetSecurityModeNoExpire(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,206 @@
+package resolver
+
+import (
+	"bytes"
+	"fmt"
+
+	sdkCore "github.com/multiversx/mx-sdk-go/core"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+)
+
+const legacyOTPType = "legacy"
+
+// GetUserState returns the state of the user guardians, without any of their secrets
+func (resolver *serviceResolver) GetUserState(userAddress sdkCore.AddressHandler) (*requests.UserStateResponse, error) {
+	addressBytes := userAddress.AddressBytes()
+	resolver.userCritSection.RLock(string(addressBytes))
+	userInfo, err := resolver.getUserInfo(addressBytes)
+	resolver.userCritSection.RUnlock(string(addressBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	return resolver.createUserStateResponse(userAddress, userInfo)
+}
+
+// ForceUnsetSecurityMode unsets the security mode of the user and resets its failed trials, without requiring any code
+func (resolver *serviceResolver) ForceUnsetSecurityMode(userAddress sdkCore.AddressHandler) error {
+	bech32Addr, err := resolver.getRegisteredUserAddress(userAddress)
+	if err != nil {
+		return err
+	}
+
+	err = resolver.secureOtpHandler.ResetSecurityMode(bech32Addr)
+	if err != nil {
+		return err
+	}
+
+	log.Info("security mode force unset", "userAddress", bech32Addr)
+
+	return nil
+}
+
+// ResetRateLimiter resets the failed trials of the user from the provided ip
+func (resolver *serviceResolver) ResetRateLimiter(userAddress sdkCore.AddressHandler, userIp string) error {
+	if len(userIp) == 0 {
+		return ErrEmptyUserIp
+	}
+
+	bech32Addr, err := resolver.getRegisteredUserAddress(userAddress)
+	if err != nil {
+		return err
+	}
+
+	err = resolver.secureOtpHandler.ResetFreeze(bech32Addr, userIp)
+	if err != nil {
+		return err
+	}
+
+	log.Info("rate limiter reset", "userAddress", bech32Addr, "ip", userIp)
+
+	return nil
+}
+
+// MarkGuardianNotUsable marks the provided guardian of the user as not usable, so that it cannot co-sign
+// until its otp is verified again
+func (resolver *serviceResolver) MarkGuardianNotUsable(userAddress sdkCore.AddressHandler, guardian string) error {
+	guardianAddr, err := resolver.pubKeyConverter.Decode(guardian)
+	if err != nil {
+		return err
+	}
+
+	addressBytes := userAddress.AddressBytes()
+	resolver.userCritSection.Lock(string(addressBytes))
+	defer resolver.userCritSection.Unlock(string(addressBytes))
+
+	userInfo, err := resolver.getUserInfo(addressBytes)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case bytes.Equal(guardianAddr, userInfo.FirstGuardian.PublicKey):
+		resetGuardian(&userInfo.FirstGuardian)
+	case bytes.Equal(guardianAddr, userInfo.SecondGuardian.PublicKey):
+		resetGuardian(&userInfo.SecondGuardian)
+	default:
+		return fmt.Errorf("%w, guardian %s", ErrInvalidGuardian, guardian)
+	}
+
+	err = resolver.marshalAndSaveEncrypted(addressBytes, userInfo)
+	if err != nil {
+		return err
+	}
+
+	log.Info("guardian marked as not usable", "userAddress", resolver.pubKeyConverter.SilentEncode(addressBytes, log), "guardian", guardian)
+
+	return nil
+}
+
+// ReEncryptUser re-encrypts the secrets of the user with the current encryption key
+func (resolver *serviceResolver) ReEncryptUser(userAddress sdkCore.AddressHandler) error {
+	addressBytes := userAddress.AddressBytes()
+	resolver.userCritSection.Lock(string(addressBytes))
+	defer resolver.userCritSection.Unlock(string(addressBytes))
+
+	userInfo, err := resolver.getUserInfo(addressBytes)
+	if err != nil {
+		return err
+	}
+
+	oldKeyID := userInfo.EncryptionKeyID
+	err = resolver.marshalAndSaveEncrypted(addressBytes, userInfo)
+	if err != nil {
+		return err
+	}
+
+	log.Info("user re-encrypted", "userAddress", resolver.pubKeyConverter.SilentEncode(addressBytes, log), "old key id", oldKeyID)
+
+	return nil
+}
+
+// ExportUserData returns all the data held for the user which can be inspected by an operator
+func (resolver *serviceResolver) ExportUserData(userAddress sdkCore.AddressHandler) (*requests.UserAuditDataResponse, error) {
+	addressBytes := userAddress.AddressBytes()
+	resolver.userCritSection.RLock(string(addressBytes))
+	userInfo, err := resolver.getUserInfo(addressBytes)
+	resolver.userCritSection.RUnlock(string(addressBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	userState, err := resolver.createUserStateResponse(userAddress, userInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	spendingPolicy, err := resolver.createSpendingPolicyResponse(userInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &requests.UserAuditDataResponse{
+		State:           *userState,
+		SpendingPolicy:  *spendingPolicy,
+		ExportTimestamp: resolver.getTimeHandler().Unix(),
+	}, nil
+}
+
+func (resolver *serviceResolver) getRegisteredUserAddress(userAddress sdkCore.AddressHandler) (string, error) {
+	err := resolver.registeredUsersDB.Has(userAddress.AddressBytes())
+	if err != nil {
+		return "", err
+	}
+
+	return userAddress.AddressAsBech32String()
+}
+
+func (resolver *serviceResolver) createUserStateResponse(userAddress sdkCore.AddressHandler, userInfo *core.UserInfo) (*requests.UserStateResponse, error) {
+	bech32Addr, err := userAddress.AddressAsBech32String()
+	if err != nil {
+		return nil, err
+	}
+
+	firstGuardianState, err := resolver.createGuardianStateResponse(userInfo.FirstGuardian)
+	if err != nil {
+		return nil, err
+	}
+
+	secondGuardianState, err := resolver.createGuardianStateResponse(userInfo.SecondGuardian)
+	if err != nil {
+		return nil, err
+	}
+
+	return &requests.UserStateResponse{
+		Address:                bech32Addr,
+		Index:                  userInfo.Index,
+		Guardians:              []requests.GuardianStateResponse{firstGuardianState, secondGuardianState},
+		RecoveryCodesRemaining: len(userInfo.RecoveryCodes),
+		EncryptionKeyID:        userInfo.EncryptionKeyID,
+		EncryptionVersion:      userInfo.EncryptionVersion,
+	}, nil
+}
+
+func (resolver *serviceResolver) createGuardianStateResponse(guardian core.GuardianInfo) (requests.GuardianStateResponse, error) {
+	guardianAddr, err := resolver.pubKeyConverter.Encode(guardian.PublicKey)
+	if err != nil {
+		return requests.GuardianStateResponse{}, err
+	}
+
+	guardianState := requests.GuardianStateResponse{
+		Address:            guardianAddr,
+		State:              guardian.State.String(),
+		WebAuthnRegistered: len(guardian.WebAuthnData.CredentialID) > 0,
+	}
+	if len(guardian.OTPData.OTP) > 0 {
+		guardianState.OTPType = guardian.OTPData.Params.Type
+		if len(guardianState.OTPType) == 0 {
+			guardianState.OTPType = legacyOTPType
+		}
+		guardianState.LastOTPChangeTimestamp = guardian.OTPData.LastTOTPChangeTimestamp
+	}
+
+	return guardianState, nil
+}
@@ -0,0 +1,207 @@
+package resolver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+func TestServiceResolver_GetUserState(t *testing.T) {
+	t.Parallel()
+
+	ctx := createRecoveryCodesTestContext(t, "code1", "code2")
+
+	userState, err := ctx.resolver.GetUserState(ctx.userAddress)
+	require.Nil(t, err)
+	assert.Equal(t, usrAddr, userState.Address)
+	assert.Equal(t, providedUserInfo.Index, userState.Index)
+	assert.Equal(t, 2, userState.RecoveryCodesRemaining)
+	require.Equal(t, 2, len(userState.Guardians))
+
+	firstGuardian := userState.Guardians[0]
+	assert.Equal(t, string(providedUserInfo.FirstGuardian.PublicKey), firstGuardian.Address)
+	assert.Equal(t, core.Usable.String(), firstGuardian.State)
+	assert.Equal(t, legacyOTPType, firstGuardian.OTPType)
+	assert.True(t, firstGuardian.WebAuthnRegistered)
+
+	secondGuardian := userState.Guardians[1]
+	assert.Equal(t, string(providedUserInfo.SecondGuardian.PublicKey), secondGuardian.Address)
+	assert.False(t, secondGuardian.WebAuthnRegistered)
+}
+
+func TestServiceResolver_ForceUnsetSecurityMode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unregistered user should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+		ctx.resolver.registeredUsersDB = &testscommon.ShardedStorageWithIndexStub{
+			HasCalled: func(key []byte) error {
+				return expectedErr
+			},
+		}
+
+		err := ctx.resolver.ForceUnsetSecurityMode(ctx.userAddress)
+		assert.Equal(t, expectedErr, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+		wasCalled := false
+		ctx.resolver.secureOtpHandler = &testscommon.SecureOtpHandlerStub{
+			ResetSecurityModeCalled: func(account string) error {
+				assert.Equal(t, usrAddr, account)
+				wasCalled = true
+				return nil
+			},
+		}
+
+		err := ctx.resolver.ForceUnsetSecurityMode(ctx.userAddress)
+		assert.Nil(t, err)
+		assert.True(t, wasCalled)
+	})
+}
+
+func TestServiceResolver_ResetRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty ip should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+
+		err := ctx.resolver.ResetRateLimiter(ctx.userAddress, "")
+		assert.Equal(t, ErrEmptyUserIp, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+		wasCalled := false
+		ctx.resolver.secureOtpHandler = &testscommon.SecureOtpHandlerStub{
+			ResetFreezeCalled: func(account string, ip string) error {
+				assert.Equal(t, usrAddr, account)
+				assert.Equal(t, "127.0.0.1", ip)
+				wasCalled = true
+				return nil
+			},
+		}
+
+		err := ctx.resolver.ResetRateLimiter(ctx.userAddress, "127.0.0.1")
+		assert.Nil(t, err)
+		assert.True(t, wasCalled)
+	})
+}
+
+func TestServiceResolver_MarkGuardianNotUsable(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown guardian should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+
+		err := ctx.resolver.MarkGuardianNotUsable(ctx.userAddress, "unknown guardian")
+		assert.True(t, errors.Is(err, ErrInvalidGuardian))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+		secondGuardian := string(providedUserInfo.SecondGuardian.PublicKey)
+
+		err := ctx.resolver.MarkGuardianNotUsable(ctx.userAddress, secondGuardian)
+		require.Nil(t, err)
+
+		userInfo := ctx.getUserInfo(t)
+		assert.Equal(t, core.Usable, userInfo.FirstGuardian.State)
+		assert.Equal(t, core.NotUsable, userInfo.SecondGuardian.State)
+
+		_, err = ctx.resolver.getGuardianInfoFromAddress(ctx.userAddress.AddressBytes(), secondGuardian, userInfo)
+		assert.True(t, errors.Is(err, ErrGuardianNotUsable))
+	})
+}
+
+func TestServiceResolver_ReEncryptUser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unregistered user should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+		ctx.resolver.registeredUsersDB = &testscommon.ShardedStorageWithIndexStub{
+			GetCalled: func(key []byte) ([]byte, error) {
+				return nil, expectedErr
+			},
+		}
+
+		err := ctx.resolver.ReEncryptUser(ctx.userAddress)
+		assert.Equal(t, expectedErr, err)
+	})
+	t.Run("encryption error should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+		ctx.resolver.userEncryptor = &testscommon.UserEncryptorStub{
+			EncryptUserInfoCalled: func(userAddress []byte, userInfo *core.UserInfo) (*core.UserInfo, error) {
+				return nil, expectedErr
+			},
+			DecryptUserInfoCalled: func(userAddress []byte, userInfo *core.UserInfo) (*core.UserInfo, error) {
+				return userInfo, nil
+			},
+		}
+
+		err := ctx.resolver.ReEncryptUser(ctx.userAddress)
+		assert.Equal(t, expectedErr, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+		assert.Equal(t, uint32(0), ctx.getUserInfo(t).EncryptionKeyID)
+
+		ctx.resolver.userEncryptor = &testscommon.UserEncryptorStub{
+			EncryptUserInfoCalled: func(userAddress []byte, userInfo *core.UserInfo) (*core.UserInfo, error) {
+				userCopy := *userInfo
+				userCopy.EncryptionKeyID = 1
+				return &userCopy, nil
+			},
+			DecryptUserInfoCalled: func(userAddress []byte, userInfo *core.UserInfo) (*core.UserInfo, error) {
+				userCopy := *userInfo
+				return &userCopy, nil
+			},
+		}
+
+		err := ctx.resolver.ReEncryptUser(ctx.userAddress)
+		require.Nil(t, err)
+
+		userInfo := ctx.getUserInfo(t)
+		assert.Equal(t, uint32(1), userInfo.EncryptionKeyID)
+		assert.Equal(t, providedUserInfo.FirstGuardian.PublicKey, userInfo.FirstGuardian.PublicKey)
+
+		userState, err := ctx.resolver.GetUserState(ctx.userAddress)
+		require.Nil(t, err)
+		assert.Equal(t, uint32(1), userState.EncryptionKeyID)
+		assert.Equal(t, userInfo.EncryptionVersion, userState.EncryptionVersion)
+	})
+}
+
+func TestServiceResolver_ExportUserData(t *testing.T) {
+	t.Parallel()
+
+	ctx := createRecoveryCodesTestContext(t, "code1")
+
+	auditData, err := ctx.resolver.ExportUserData(ctx.userAddress)
+	require.Nil(t, err)
+	assert.Equal(t, usrAddr, auditData.State.Address)
+	assert.Equal(t, 1, auditData.State.RecoveryCodesRemaining)
+	assert.Nil(t, auditData.SpendingPolicy.Pending)
+	assert.Equal(t, int64(1000), auditData.ExportTimestamp)
+}
@@ -0,0 +1,82 @@
+package resolver
+
+import (
+	"context"
+
+	sdkCore "github.com/multiversx/mx-sdk-go/core"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+)
+
+// DeregisterUser verifies the code and then removes the user, if none of its guardians is active or pending on chain.
+// The guardian data is not read from the cache, as a guardian set on chain in the last seconds must be seen.
+// The index of the user stays reserved, so a new registration will get new guardians
+func (resolver *serviceResolver) DeregisterUser(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error) {
+	guardianAddr, err := resolver.pubKeyConverter.Decode(request.Guardian)
+	if err != nil {
+		return nil, err
+	}
+
+	bech32Addr, err := userAddress.AddressAsBech32String()
+	if err != nil {
+		return nil, err
+	}
+
+	ctxGetGuardianData, cancelGetGuardianData := context.WithTimeout(context.Background(), resolver.requestTime)
+	defer cancelGetGuardianData()
+	guardianData, err := resolver.httpClientWrapper.GetFreshGuardianData(ctxGetGuardianData, bech32Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	addressBytes := userAddress.AddressBytes()
+	resolver.userCritSection.Lock(string(addressBytes))
+	defer resolver.userCritSection.Unlock(string(addressBytes))
+
+	userInfo, err := resolver.getUserInfo(addressBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, guardian := range []core.GuardianInfo{userInfo.FirstGuardian, userInfo.SecondGuardian} {
+		if resolver.getOnChainGuardianState(guardianData, guardian) != core.MissingGuardian {
+			return nil, ErrGuardianSetOnChain
+		}
+	}
+
+	verifyCodeData, err := resolver.verifyCodeOrAssertion(userInfo, addressBytes, bech32Addr, userIp, guardianAddr, request)
+	if err != nil {
+		return verifyCodeData, err
+	}
+
+	err = resolver.registeredUsersDB.Remove(addressBytes)
+	if err != nil {
+		// save the verified code as used, so it can not be replayed
+		errSave := resolver.marshalAndSaveEncrypted(addressBytes, userInfo)
+		if errSave != nil {
+			log.Warn("could not save user info after failed removal", "userAddress", bech32Addr, "error", errSave.Error())
+		}
+
+		return verifyCodeData, err
+	}
+
+	err = resolver.secureOtpHandler.RemoveAccount(bech32Addr)
+	if err != nil {
+		log.Warn("could not remove the rate limiter keys of the deregistered user", "userAddress", bech32Addr, "error", err.Error())
+	}
+
+	resolver.notifier.Notify(core.SecurityEvent{
+		Type:        core.UserDeregisteredEvent,
+		Timestamp:   resolver.getTimeHandler().Unix(),
+		UserAddress: bech32Addr,
+		UserIp:      userIp,
+		Guardian:    request.Guardian,
+	})
+
+	log.Info("user deregistered",
+		"userAddress", bech32Addr,
+		"guardian", request.Guardian)
+
+	return verifyCodeData, nil
+}
@@ -0,0 +1,179 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/data/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+type deregistrationTestContext struct {
+	*webAuthnTestContext
+	removeErr           error
+	removedAccounts     []string
+	notifiedEvents      []core.SecurityEvent
+	requestedAddresses  []string
+	guardianDataOnChain *api.GuardianData
+}
+
+func createDeregistrationTestContext(t *testing.T) *deregistrationTestContext {
+	ctx := &deregistrationTestContext{
+		webAuthnTestContext: createRecoveryCodesTestContext(t, providedRecoveryCode),
+	}
+
+	ctx.resolver.httpClientWrapper = &testscommon.HttpClientWrapperStub{
+		GetGuardianDataCalled: func(_ context.Context, _ string) (*api.GuardianData, error) {
+			assert.Fail(t, "the cached guardian data should have not been used")
+			return nil, nil
+		},
+		GetFreshGuardianDataCalled: func(_ context.Context, address string) (*api.GuardianData, error) {
+			ctx.requestedAddresses = append(ctx.requestedAddresses, address)
+			return ctx.guardianDataOnChain, nil
+		},
+	}
+	usersDB := ctx.resolver.registeredUsersDB.(*testscommon.ShardedStorageWithIndexStub)
+	usersDB.RemoveCalled = func(key []byte) error {
+		if ctx.removeErr != nil {
+			return ctx.removeErr
+		}
+
+		ctx.mutDB.Lock()
+		delete(ctx.db, string(key))
+		ctx.mutDB.Unlock()
+		return nil
+	}
+	secureOtpHandler := ctx.resolver.secureOtpHandler.(*testscommon.SecureOtpHandlerStub)
+	secureOtpHandler.RemoveAccountCalled = func(account string) error {
+		ctx.removedAccounts = append(ctx.removedAccounts, account)
+		return nil
+	}
+	ctx.resolver.notifier = &testscommon.NotifierStub{
+		NotifyCalled: func(event core.SecurityEvent) {
+			ctx.notifiedEvents = append(ctx.notifiedEvents, event)
+		},
+	}
+
+	return ctx
+}
+
+func (ctx *deregistrationTestContext) isUserStored() bool {
+	ctx.mutDB.Lock()
+	defer ctx.mutDB.Unlock()
+
+	_, found := ctx.db[string(ctx.userAddress.AddressBytes())]
+	return found
+}
+
+func TestServiceResolver_DeregisterUser(t *testing.T) {
+	t.Parallel()
+
+	providedRequest := requests.VerificationPayload{
+		Assertion: &providedAssertion,
+		Guardian:  string(providedUserInfo.FirstGuardian.PublicKey),
+	}
+	t.Run("get guardian data fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createDeregistrationTestContext(t)
+		ctx.resolver.httpClientWrapper = &testscommon.HttpClientWrapperStub{
+			GetFreshGuardianDataCalled: func(_ context.Context, address string) (*api.GuardianData, error) {
+				return nil, expectedErr
+			},
+		}
+
+		_, err := ctx.resolver.DeregisterUser(ctx.userAddress, "userIp", providedRequest)
+		assert.Equal(t, expectedErr, err)
+		assert.True(t, ctx.isUserStored())
+	})
+	t.Run("active guardian should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createDeregistrationTestContext(t)
+		ctx.guardianDataOnChain = &api.GuardianData{
+			ActiveGuardian: &api.Guardian{
+				Address: string(providedUserInfo.SecondGuardian.PublicKey),
+			},
+		}
+
+		_, err := ctx.resolver.DeregisterUser(ctx.userAddress, "userIp", providedRequest)
+		assert.Equal(t, ErrGuardianSetOnChain, err)
+		assert.True(t, ctx.isUserStored())
+		assert.Equal(t, []string{usrAddr}, ctx.requestedAddresses)
+	})
+	t.Run("pending guardian should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createDeregistrationTestContext(t)
+		ctx.guardianDataOnChain = &api.GuardianData{
+			ActiveGuardian: &api.Guardian{
+				Address: "other guardian",
+			},
+			PendingGuardian: &api.Guardian{
+				Address: string(providedUserInfo.FirstGuardian.PublicKey),
+			},
+		}
+
+		_, err := ctx.resolver.DeregisterUser(ctx.userAddress, "userIp", providedRequest)
+		assert.Equal(t, ErrGuardianSetOnChain, err)
+		assert.True(t, ctx.isUserStored())
+	})
+	t.Run("invalid assertion should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createDeregistrationTestContext(t)
+		ctx.resolver.webAuthnHandler = &testscommon.WebAuthnHandlerStub{
+			VerifyAssertionCalled: func(credential core.WebAuthnInfo, binding []byte, assertion requests.WebAuthnAssertion) (*core.WebAuthnInfo, error) {
+				return nil, expectedErr
+			},
+		}
+
+		_, err := ctx.resolver.DeregisterUser(ctx.userAddress, "userIp", providedRequest)
+		assert.True(t, errors.Is(err, expectedErr))
+		assert.True(t, ctx.isUserStored())
+		assert.Empty(t, ctx.removedAccounts)
+		assert.Empty(t, ctx.notifiedEvents)
+	})
+	t.Run("remove fails should error and keep the assertion counter", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createDeregistrationTestContext(t)
+		ctx.removeErr = expectedErr
+
+		_, err := ctx.resolver.DeregisterUser(ctx.userAddress, "userIp", providedRequest)
+		assert.Equal(t, expectedErr, err)
+		assert.True(t, ctx.isUserStored())
+		assert.Equal(t, uint32(6), ctx.getWebAuthnData(t).SignCount)
+		assert.Empty(t, ctx.removedAccounts)
+		assert.Empty(t, ctx.notifiedEvents)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createDeregistrationTestContext(t)
+		ctx.guardianDataOnChain = &api.GuardianData{
+			ActiveGuardian: &api.Guardian{
+				Address: "other guardian",
+			},
+		}
+
+		_, err := ctx.resolver.DeregisterUser(ctx.userAddress, "userIp", providedRequest)
+		require.Nil(t, err)
+		assert.False(t, ctx.isUserStored())
+		assert.Equal(t, []string{usrAddr}, ctx.removedAccounts)
+		require.Equal(t, 1, len(ctx.notifiedEvents))
+		assert.Equal(t, core.SecurityEvent{
+			Type:        core.UserDeregisteredEvent,
+			Timestamp:   1000,
+			UserAddress: usrAddr,
+			UserIp:      "userIp",
+			Guardian:    providedRequest.Guardian,
+		}, ctx.notifiedEvents[0])
+	})
+}
@@ -56,6 +56,12 @@ var ErrNilTOTPHandler = errors.New("nil TOTP handler")
 // ErrNilSecureOtpHandler signals that a nil secure TOTP handler was provided
 var ErrNilSecureOtpHandler = errors.New("nil secure TOTP handler")
 
+// ErrNilTxPolicyHandler signals that a nil tx policy handler was provided
+var ErrNilTxPolicyHandler = errors.New("nil tx policy handler")
+
+// ErrNilTxDecoder signals that a nil tx decoder was provided
+var ErrNilTxDecoder = errors.New("nil tx decoder")
+
 // ErrNilUserInfo signals that a nil user info was provided
 var ErrNilUserInfo = errors.New("nil user info")
 
@@ -82,3 +88,48 @@ var ErrSameCode = errors.New("same code")
 
 // ErrAccountHasNoActiveGuardian signals that there is no active guardian for the user
 var ErrAccountHasNoActiveGuardian = errors.New("no active guardian for the account")
+
+// ErrInvalidSpendingPolicy signals that an invalid spending policy was provided
+var ErrInvalidSpendingPolicy = errors.New("invalid spending policy")
+
+// ErrSpendingLimitExceeded signals that the transactions would exceed the spending limits of the user
+var ErrSpendingLimitExceeded = errors.New("spending limit exceeded")
+
+// ErrNilWebAuthnHandler signals that a nil WebAuthn handler was provided
+var ErrNilWebAuthnHandler = errors.New("nil webauthn handler")
+
+// ErrWebAuthnNotRegistered signals that the guardian has no WebAuthn credential registered
+var ErrWebAuthnNotRegistered = errors.New("no webauthn credential registered")
+
+// ErrInvalidRecoveryCode signals that the provided recovery code is not valid
+var ErrInvalidRecoveryCode = errors.New("invalid recovery code")
+
+// ErrEmptyUserIp signals that an empty user ip was provided
+var ErrEmptyUserIp = errors.New("empty user ip")
+
+// ErrNilAuditSink signals that a nil audit sink was provided
+var ErrNilAuditSink = errors.New("nil audit sink")
+
+// ErrInvalidHistoryLimit signals that the requested number of history entries is not valid
+var ErrInvalidHistoryLimit = errors.New("invalid history limit")
+
+// ErrNilNotifier signals that a nil notifier was provided
+var ErrNilNotifier = errors.New("nil notifier")
+
+// ErrUnknownEncryptionVersion signals that the user info was encrypted with an unknown scheme
+var ErrUnknownEncryptionVersion = errors.New("unknown encryption version")
+
+// ErrGuardianSetOnChain signals that one of the guardians of the user is still active or pending on chain
+var ErrGuardianSetOnChain = errors.New("guardian is active or pending on chain")
+
+// ErrAmbiguousWebAuthnChallenge signals that a WebAuthn challenge was requested for both transactions and a message
+var ErrAmbiguousWebAuthnChallenge = errors.New("webauthn challenge requested for both transactions and a message")
+
+// ErrReEncryptionInProgress signals that a re-encryption of all the users is already running
+var ErrReEncryptionInProgress = errors.New("re-encryption already in progress")
+
+// ErrInvalidRecordTag signals that the stored user record was modified outside the service
+var ErrInvalidRecordTag = errors.New("invalid user record tag")
+
+// ErrLegacyRecordRejected signals that a user record of a previous encryption format was found while they are rejected
+var ErrLegacyRecordRejected = errors.New("user record of a previous encryption format rejected")
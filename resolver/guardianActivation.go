@@ -0,0 +1,214 @@
+package resolver
+
+import (
+	"context"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/data/api"
+	sdkCore "github.com/multiversx/mx-sdk-go/core"
+	sdkData "github.com/multiversx/mx-sdk-go/data"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+)
+
+// GetGuardianStatus returns the usability and the on chain activation progress of the user guardians, along with
+// the remaining verification trials of the user and its security mode. No verification trial is consumed
+func (resolver *serviceResolver) GetGuardianStatus(userAddress sdkCore.AddressHandler, userIp string) (*requests.GuardianStatusResponse, error) {
+	bech32Addr, err := userAddress.AddressAsBech32String()
+	if err != nil {
+		return nil, err
+	}
+
+	addressBytes := userAddress.AddressBytes()
+	resolver.userCritSection.RLock(string(addressBytes))
+	userInfo, err := resolver.getUserInfo(addressBytes)
+	resolver.userCritSection.RUnlock(string(addressBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	firstGuardianStatus, err := resolver.createGuardianStatus(userInfo.FirstGuardian)
+	if err != nil {
+		return nil, err
+	}
+
+	secondGuardianStatus, err := resolver.createGuardianStatus(userInfo.SecondGuardian)
+	if err != nil {
+		return nil, err
+	}
+
+	verifyCodeData, err := resolver.secureOtpHandler.GetVerificationTrials(bech32Addr, userIp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &requests.GuardianStatusResponse{
+		Guardians:            []requests.GuardianStatus{firstGuardianStatus, secondGuardianStatus},
+		SecurityModeActive:   verifyCodeData.SecurityModeRemainingTrials <= 0,
+		SecurityModeNoExpire: verifyCodeData.SecurityModeResetAfter == core.NoExpiryValue,
+		VerifyData:           verifyCodeData,
+	}, nil
+}
+
+func (resolver *serviceResolver) createGuardianStatus(guardian core.GuardianInfo) (requests.GuardianStatus, error) {
+	guardianAddr, err := resolver.pubKeyConverter.Encode(guardian.PublicKey)
+	if err != nil {
+		return requests.GuardianStatus{}, err
+	}
+
+	guardianStatus := requests.GuardianStatus{
+		Address:           guardianAddr,
+		Usable:            guardian.State == core.Usable,
+		ActivationState:   guardian.ActivationState.String(),
+		VerifiedTimestamp: guardian.VerifiedTimestamp,
+	}
+	if len(guardian.OTPData.OTP) == 0 {
+		return guardianStatus, nil
+	}
+
+	// same as for the registration, the otp can be written again only after DelayBetweenOTPWritesInSec
+	guardianStatus.LastOTPChangeTimestamp = guardian.OTPData.LastTOTPChangeTimestamp
+	nextAllowedOTPChangeTimestamp := guardian.OTPData.LastTOTPChangeTimestamp + int64(resolver.config.DelayBetweenOTPWritesInSec)
+	registrationAllowedAfter := nextAllowedOTPChangeTimestamp - resolver.getTimeHandler().Unix()
+	if registrationAllowedAfter > 0 {
+		guardianStatus.RegistrationAllowedAfter = registrationAllowedAfter
+	}
+
+	return guardianStatus, nil
+}
+
+// ReconcileGuardianActivations checks on chain the guardians verified recently and the pending ones, updating their
+// activation state. The users are only collected while iterating the storage and updated afterwards, one by one
+func (resolver *serviceResolver) ReconcileGuardianActivations(ctx context.Context) error {
+	userAddresses := make([][]byte, 0)
+	err := resolver.registeredUsersDB.RangeKeys(ctx, func(key []byte, val []byte) bool {
+		// the activation state is not encrypted, so the user does not need to be decrypted to be checked
+		userInfo := &core.UserInfo{}
+		errUnmarshal := resolver.userDataMarshaller.Unmarshal(userInfo, val)
+		if errUnmarshal != nil {
+			log.Debug("could not unmarshal user while reconciling guardian activations", "error", errUnmarshal)
+			return true
+		}
+
+		if resolver.isActivationCheckNeeded(userInfo) {
+			userAddresses = append(userAddresses, key)
+		}
+
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, userAddress := range userAddresses {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err = resolver.reconcileUserGuardians(sdkData.NewAddressFromBytes(userAddress))
+		if err != nil {
+			log.Warn("could not reconcile guardian activation",
+				"userAddress", resolver.pubKeyConverter.SilentEncode(userAddress, log),
+				"error", err)
+		}
+	}
+
+	log.Debug("guardian activations reconciled", "num users", len(userAddresses))
+
+	return nil
+}
+
+func (resolver *serviceResolver) isActivationCheckNeeded(userInfo *core.UserInfo) bool {
+	return resolver.isGuardianActivationCheckNeeded(userInfo.FirstGuardian) ||
+		resolver.isGuardianActivationCheckNeeded(userInfo.SecondGuardian)
+}
+
+// isGuardianActivationCheckNeeded returns true for the pending guardians and for the guardians verified
+// recently, a verified guardian which is not set on chain in GuardianActivationWindowInSec being no longer checked
+func (resolver *serviceResolver) isGuardianActivationCheckNeeded(guardian core.GuardianInfo) bool {
+	switch guardian.ActivationState {
+	case core.PendingOnChain:
+		return true
+	case core.Verified:
+		activationWindow := time.Duration(resolver.config.GuardianActivationWindowInSec) * time.Second
+		verifiedTime := time.Unix(guardian.VerifiedTimestamp, 0)
+		return resolver.getTimeHandler().Before(verifiedTime.Add(activationWindow))
+	default:
+		return false
+	}
+}
+
+func (resolver *serviceResolver) reconcileUserGuardians(userAddress sdkCore.AddressHandler) error {
+	addressBytes := userAddress.AddressBytes()
+	resolver.userCritSection.Lock(string(addressBytes))
+	defer resolver.userCritSection.Unlock(string(addressBytes))
+
+	userInfo, err := resolver.getUserInfo(addressBytes)
+	if err != nil {
+		return err
+	}
+
+	return resolver.reconcileGuardians(addressBytes, userInfo)
+}
+
+// reconcileGuardians fetches the guardian data of the user and updates the activation state of its tracked
+// guardians, saving the user if any of them changed. The guardian data is not read from the cache, as a stale one
+// could mark a guardian replaced or keep a replaced one usable. It should be called under the user lock
+func (resolver *serviceResolver) reconcileGuardians(userAddress []byte, userInfo *core.UserInfo) error {
+	bech32Addr, err := resolver.pubKeyConverter.Encode(userAddress)
+	if err != nil {
+		return err
+	}
+
+	ctxGetGuardianData, cancelGetGuardianData := context.WithTimeout(context.Background(), resolver.requestTime)
+	defer cancelGetGuardianData()
+	guardianData, err := resolver.httpClientWrapper.GetFreshGuardianData(ctxGetGuardianData, bech32Addr)
+	if err != nil {
+		return err
+	}
+
+	firstChanged := resolver.updateGuardianActivation(bech32Addr, guardianData, &userInfo.FirstGuardian)
+	secondChanged := resolver.updateGuardianActivation(bech32Addr, guardianData, &userInfo.SecondGuardian)
+	if !firstChanged && !secondChanged {
+		return nil
+	}
+
+	return resolver.marshalAndSaveEncrypted(userAddress, userInfo)
+}
+
+// updateGuardianActivation moves the guardian through Verified -> PendingOnChain -> Active -> Replaced, according
+// to the guardian data of the user. A guardian becomes usable only once it is active on chain, and stops being
+// usable when replaced. The guardians which are not tracked are left unchanged
+func (resolver *serviceResolver) updateGuardianActivation(userAddress string, guardianData *api.GuardianData, guardian *core.GuardianInfo) bool {
+	if guardian.ActivationState == core.Untracked {
+		return false
+	}
+
+	oldActivationState, oldState := guardian.ActivationState, guardian.State
+	switch resolver.getOnChainGuardianState(guardianData, *guardian) {
+	case core.ActiveGuardian:
+		guardian.ActivationState = core.Active
+		guardian.State = core.Usable
+	case core.PendingGuardian:
+		guardian.ActivationState = core.PendingOnChain
+	case core.MissingGuardian:
+		if guardian.ActivationState == core.PendingOnChain || guardian.ActivationState == core.Active {
+			guardian.ActivationState = core.Replaced
+			guardian.State = core.NotUsable
+		}
+	}
+
+	if guardian.ActivationState == oldActivationState && guardian.State == oldState {
+		return false
+	}
+
+	log.Debug("guardian activation updated",
+		"userAddress", userAddress,
+		"guardian", resolver.pubKeyConverter.SilentEncode(guardian.PublicKey, log),
+		"old activation state", oldActivationState.String(),
+		"new activation state", guardian.ActivationState.String(),
+		"usable", guardian.State == core.Usable)
+
+	return true
+}
@@ -0,0 +1,366 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/data/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+const providedVerifiedTimestamp = int64(900)
+
+func createActivationTestContext(t *testing.T, firstActivationState core.ActivationState, secondActivationState core.ActivationState, guardianData *api.GuardianData) *webAuthnTestContext {
+	ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+	ctx.resolver.config.GuardianActivationWindowInSec = 3600
+	ctx.resolver.httpClientWrapper = &testscommon.HttpClientWrapperStub{
+		GetFreshGuardianDataCalled: func(_ context.Context, address string) (*api.GuardianData, error) {
+			assert.Equal(t, string(ctx.userAddress.AddressBytes()), address)
+			return guardianData, nil
+		},
+	}
+	registeredUsersDB := ctx.resolver.registeredUsersDB.(*testscommon.ShardedStorageWithIndexStub)
+	registeredUsersDB.RangeKeysCalled = func(_ context.Context, handler func(key []byte, val []byte) bool) error {
+		ctx.mutDB.Lock()
+		dbCopy := make(map[string][]byte, len(ctx.db))
+		for key, val := range ctx.db {
+			dbCopy[key] = val
+		}
+		ctx.mutDB.Unlock()
+
+		for key, val := range dbCopy {
+			if !handler([]byte(key), val) {
+				return nil
+			}
+		}
+		return nil
+	}
+
+	userInfo := *providedUserInfo
+	userInfo.FirstGuardian = createTrackedGuardian(userInfo.FirstGuardian, firstActivationState)
+	userInfo.SecondGuardian = createTrackedGuardian(userInfo.SecondGuardian, secondActivationState)
+	err := ctx.resolver.marshalAndSaveEncrypted(ctx.userAddress.AddressBytes(), &userInfo)
+	require.Nil(t, err)
+
+	return ctx
+}
+
+func createTrackedGuardian(guardian core.GuardianInfo, activationState core.ActivationState) core.GuardianInfo {
+	guardian.ActivationState = activationState
+	guardian.State = core.NotUsable
+	if activationState == core.Active {
+		guardian.State = core.Usable
+	}
+	if activationState != core.Untracked {
+		guardian.VerifiedTimestamp = providedVerifiedTimestamp
+	}
+
+	return guardian
+}
+
+func getActivationTestUserInfo(t *testing.T, ctx *webAuthnTestContext) *core.UserInfo {
+	userInfo, err := ctx.resolver.getUserInfo(ctx.userAddress.AddressBytes())
+	require.Nil(t, err)
+
+	return userInfo
+}
+
+func TestServiceResolver_GetGuardianStatus(t *testing.T) {
+	t.Parallel()
+
+	providedIp := "127.0.0.1"
+	t.Run("get user info fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createActivationTestContext(t, core.Active, core.Verified, nil)
+		ctx.resolver.registeredUsersDB = &testscommon.ShardedStorageWithIndexStub{
+			GetCalled: func(key []byte) ([]byte, error) {
+				return nil, expectedErr
+			},
+		}
+
+		status, err := ctx.resolver.GetGuardianStatus(ctx.userAddress, providedIp)
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, status)
+	})
+	t.Run("get verification trials fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createActivationTestContext(t, core.Active, core.Verified, nil)
+		ctx.resolver.secureOtpHandler = &testscommon.SecureOtpHandlerStub{
+			GetVerificationTrialsCalled: func(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+				return nil, expectedErr
+			},
+		}
+
+		status, err := ctx.resolver.GetGuardianStatus(ctx.userAddress, providedIp)
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, status)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createActivationTestContext(t, core.Active, core.Verified, nil)
+		userInfo := getActivationTestUserInfo(t, ctx)
+		// the current time of the test context is 1000
+		userInfo.FirstGuardian.OTPData.LastTOTPChangeTimestamp = 1000 - int64(minDelayBetweenOTPUpdates) + 10
+		userInfo.SecondGuardian.OTPData.LastTOTPChangeTimestamp = 1000 - int64(minDelayBetweenOTPUpdates) - 10
+		require.Nil(t, ctx.resolver.marshalAndSaveEncrypted(ctx.userAddress.AddressBytes(), userInfo))
+
+		providedVerifyData := &requests.OTPCodeVerifyData{
+			RemainingTrials:             2,
+			ResetAfter:                  50,
+			SecurityModeRemainingTrials: 0,
+			SecurityModeResetAfter:      core.NoExpiryValue,
+		}
+		ctx.resolver.secureOtpHandler = &testscommon.SecureOtpHandlerStub{
+			IsVerificationAllowedAndIncreaseTrialsCalled: func(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+				assert.Fail(t, "should have not been called")
+				return nil, nil
+			},
+			GetVerificationTrialsCalled: func(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+				assert.Equal(t, usrAddr, account)
+				assert.Equal(t, providedIp, ip)
+				return providedVerifyData, nil
+			},
+		}
+
+		status, err := ctx.resolver.GetGuardianStatus(ctx.userAddress, providedIp)
+		require.Nil(t, err)
+		expectedStatus := &requests.GuardianStatusResponse{
+			Guardians: []requests.GuardianStatus{
+				{
+					Address:                  string(providedUserInfo.FirstGuardian.PublicKey),
+					Usable:                   true,
+					ActivationState:          "Active",
+					VerifiedTimestamp:        providedVerifiedTimestamp,
+					LastOTPChangeTimestamp:   userInfo.FirstGuardian.OTPData.LastTOTPChangeTimestamp,
+					RegistrationAllowedAfter: 10,
+				},
+				{
+					Address:                  string(providedUserInfo.SecondGuardian.PublicKey),
+					Usable:                   false,
+					ActivationState:          "Verified",
+					VerifiedTimestamp:        providedVerifiedTimestamp,
+					LastOTPChangeTimestamp:   userInfo.SecondGuardian.OTPData.LastTOTPChangeTimestamp,
+					RegistrationAllowedAfter: 0,
+				},
+			},
+			SecurityModeActive:   true,
+			SecurityModeNoExpire: true,
+			VerifyData:           providedVerifyData,
+		}
+		assert.Equal(t, expectedStatus, status)
+	})
+	t.Run("guardian without otp should not have registration delay", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createActivationTestContext(t, core.Untracked, core.Untracked, nil)
+		userInfo := getActivationTestUserInfo(t, ctx)
+		userInfo.SecondGuardian.OTPData = core.OTPInfo{}
+		require.Nil(t, ctx.resolver.marshalAndSaveEncrypted(ctx.userAddress.AddressBytes(), userInfo))
+		ctx.resolver.secureOtpHandler = &testscommon.SecureOtpHandlerStub{
+			GetVerificationTrialsCalled: func(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+				return &requests.OTPCodeVerifyData{RemainingTrials: 3, SecurityModeRemainingTrials: 100, SecurityModeResetAfter: 10}, nil
+			},
+		}
+
+		status, err := ctx.resolver.GetGuardianStatus(ctx.userAddress, providedIp)
+		require.Nil(t, err)
+		assert.Zero(t, status.Guardians[1].LastOTPChangeTimestamp)
+		assert.Zero(t, status.Guardians[1].RegistrationAllowedAfter)
+		assert.False(t, status.SecurityModeActive)
+		assert.False(t, status.SecurityModeNoExpire)
+	})
+}
+
+func TestServiceResolver_ReconcileGuardianActivations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("range keys fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createActivationTestContext(t, core.Verified, core.Untracked, nil)
+		ctx.resolver.registeredUsersDB.(*testscommon.ShardedStorageWithIndexStub).RangeKeysCalled = func(_ context.Context, _ func(key []byte, val []byte) bool) error {
+			return expectedErr
+		}
+
+		err := ctx.resolver.ReconcileGuardianActivations(context.Background())
+		assert.Equal(t, expectedErr, err)
+	})
+	t.Run("cancelled context should stop", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createActivationTestContext(t, core.Verified, core.Untracked, &api.GuardianData{
+			ActiveGuardian: &api.Guardian{Address: string(providedUserInfo.FirstGuardian.PublicKey)},
+		})
+		cancelledCtx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := ctx.resolver.ReconcileGuardianActivations(cancelledCtx)
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, core.Verified, getActivationTestUserInfo(t, ctx).FirstGuardian.ActivationState)
+	})
+	t.Run("guardian data error should not stop the reconciliation", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createActivationTestContext(t, core.Verified, core.Untracked, nil)
+		ctx.resolver.httpClientWrapper = &testscommon.HttpClientWrapperStub{
+			GetFreshGuardianDataCalled: func(_ context.Context, _ string) (*api.GuardianData, error) {
+				return nil, expectedErr
+			},
+		}
+
+		err := ctx.resolver.ReconcileGuardianActivations(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, core.Verified, getActivationTestUserInfo(t, ctx).FirstGuardian.ActivationState)
+	})
+	t.Run("guardians not needing a check should not be fetched", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createActivationTestContext(t, core.Active, core.Replaced, nil)
+		ctx.resolver.httpClientWrapper = &testscommon.HttpClientWrapperStub{
+			GetFreshGuardianDataCalled: func(_ context.Context, _ string) (*api.GuardianData, error) {
+				assert.Fail(t, "should have not been called")
+				return nil, nil
+			},
+		}
+
+		err := ctx.resolver.ReconcileGuardianActivations(context.Background())
+		assert.Nil(t, err)
+	})
+	t.Run("verified guardian outside the activation window should not be fetched", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createActivationTestContext(t, core.Verified, core.Untracked, nil)
+		ctx.resolver.config.GuardianActivationWindowInSec = 10
+		ctx.resolver.httpClientWrapper = &testscommon.HttpClientWrapperStub{
+			GetFreshGuardianDataCalled: func(_ context.Context, _ string) (*api.GuardianData, error) {
+				assert.Fail(t, "should have not been called")
+				return nil, nil
+			},
+		}
+
+		err := ctx.resolver.ReconcileGuardianActivations(context.Background())
+		assert.Nil(t, err)
+	})
+	t.Run("verified guardian set on chain should become pending", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createActivationTestContext(t, core.Active, core.Verified, &api.GuardianData{
+			ActiveGuardian:  &api.Guardian{Address: string(providedUserInfo.FirstGuardian.PublicKey)},
+			PendingGuardian: &api.Guardian{Address: string(providedUserInfo.SecondGuardian.PublicKey)},
+		})
+
+		err := ctx.resolver.ReconcileGuardianActivations(context.Background())
+		assert.Nil(t, err)
+		userInfo := getActivationTestUserInfo(t, ctx)
+		assert.Equal(t, core.Active, userInfo.FirstGuardian.ActivationState)
+		assert.Equal(t, core.Usable, userInfo.FirstGuardian.State)
+		assert.Equal(t, core.PendingOnChain, userInfo.SecondGuardian.ActivationState)
+		assert.Equal(t, core.NotUsable, userInfo.SecondGuardian.State)
+	})
+	t.Run("pending guardian activated should replace the previous one", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createActivationTestContext(t, core.Active, core.PendingOnChain, &api.GuardianData{
+			ActiveGuardian: &api.Guardian{Address: string(providedUserInfo.SecondGuardian.PublicKey)},
+		})
+
+		err := ctx.resolver.ReconcileGuardianActivations(context.Background())
+		assert.Nil(t, err)
+		userInfo := getActivationTestUserInfo(t, ctx)
+		assert.Equal(t, core.Replaced, userInfo.FirstGuardian.ActivationState)
+		assert.Equal(t, core.NotUsable, userInfo.FirstGuardian.State)
+		assert.Equal(t, core.Active, userInfo.SecondGuardian.ActivationState)
+		assert.Equal(t, core.Usable, userInfo.SecondGuardian.State)
+	})
+	t.Run("verified guardian not yet on chain should be unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createActivationTestContext(t, core.Verified, core.Untracked, &api.GuardianData{})
+		numPuts := 0
+		registeredUsersDB := ctx.resolver.registeredUsersDB.(*testscommon.ShardedStorageWithIndexStub)
+		putCalled := registeredUsersDB.PutCalled
+		registeredUsersDB.PutCalled = func(key, data []byte) error {
+			numPuts++
+			return putCalled(key, data)
+		}
+
+		err := ctx.resolver.ReconcileGuardianActivations(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 0, numPuts)
+		assert.Equal(t, core.Verified, getActivationTestUserInfo(t, ctx).FirstGuardian.ActivationState)
+	})
+}
+
+func TestServiceResolver_getGuardianInfoFromAddressShouldReconcileOnDemand(t *testing.T) {
+	t.Parallel()
+
+	firstGuardian := string(providedUserInfo.FirstGuardian.PublicKey)
+	t.Run("guardian activated on chain should become usable", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createActivationTestContext(t, core.Verified, core.Untracked, &api.GuardianData{
+			ActiveGuardian: &api.Guardian{Address: firstGuardian},
+		})
+		userInfo := getActivationTestUserInfo(t, ctx)
+
+		guardian, err := ctx.resolver.getGuardianInfoFromAddress(ctx.userAddress.AddressBytes(), firstGuardian, userInfo)
+		assert.Nil(t, err)
+		assert.Equal(t, core.Usable, guardian.State)
+		assert.Equal(t, core.Active, getActivationTestUserInfo(t, ctx).FirstGuardian.ActivationState)
+	})
+	t.Run("guardian not yet active should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createActivationTestContext(t, core.Verified, core.Untracked, &api.GuardianData{
+			PendingGuardian: &api.Guardian{Address: firstGuardian},
+		})
+		userInfo := getActivationTestUserInfo(t, ctx)
+
+		_, err := ctx.resolver.getGuardianInfoFromAddress(ctx.userAddress.AddressBytes(), firstGuardian, userInfo)
+		assert.True(t, errors.Is(err, ErrGuardianNotUsable))
+		assert.Equal(t, core.PendingOnChain, getActivationTestUserInfo(t, ctx).FirstGuardian.ActivationState)
+	})
+	t.Run("untracked guardian should not be checked on chain", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createActivationTestContext(t, core.Untracked, core.Untracked, nil)
+		ctx.resolver.httpClientWrapper = &testscommon.HttpClientWrapperStub{
+			GetFreshGuardianDataCalled: func(_ context.Context, _ string) (*api.GuardianData, error) {
+				assert.Fail(t, "should have not been called")
+				return nil, nil
+			},
+		}
+		userInfo := getActivationTestUserInfo(t, ctx)
+
+		_, err := ctx.resolver.getGuardianInfoFromAddress(ctx.userAddress.AddressBytes(), firstGuardian, userInfo)
+		assert.True(t, errors.Is(err, ErrGuardianNotUsable))
+	})
+}
+
+func TestServiceResolver_isGuardianActivationCheckNeeded(t *testing.T) {
+	t.Parallel()
+
+	ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+	ctx.resolver.config.GuardianActivationWindowInSec = 100
+	currentTime := time.Unix(1000, 0)
+
+	verifiedAt := func(timestamp int64) core.GuardianInfo {
+		return core.GuardianInfo{ActivationState: core.Verified, VerifiedTimestamp: timestamp}
+	}
+	assert.True(t, ctx.resolver.isGuardianActivationCheckNeeded(verifiedAt(currentTime.Unix()-99)))
+	assert.False(t, ctx.resolver.isGuardianActivationCheckNeeded(verifiedAt(currentTime.Unix()-100)))
+	assert.True(t, ctx.resolver.isGuardianActivationCheckNeeded(core.GuardianInfo{ActivationState: core.PendingOnChain}))
+	assert.False(t, ctx.resolver.isGuardianActivationCheckNeeded(core.GuardianInfo{ActivationState: core.Untracked}))
+	assert.False(t, ctx.resolver.isGuardianActivationCheckNeeded(core.GuardianInfo{ActivationState: core.Active}))
+	assert.False(t, ctx.resolver.isGuardianActivationCheckNeeded(core.GuardianInfo{ActivationState: core.Replaced}))
+}
@@ -0,0 +1,55 @@
+package resolver
+
+import (
+	"fmt"
+
+	sdkCore "github.com/multiversx/mx-sdk-go/core"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+)
+
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+)
+
+// GetHistory returns the co-signed transactions and messages of the user, along with the failed attempts,
+// newest first. A zero limit returns the default number of entries
+func (resolver *serviceResolver) GetHistory(userAddress sdkCore.AddressHandler, offset uint32, limit uint32) (*requests.UserHistoryResponse, error) {
+	if limit == 0 {
+		limit = defaultHistoryLimit
+	}
+	if limit > maxHistoryLimit {
+		return nil, fmt.Errorf("%w, provided %d, max %d", ErrInvalidHistoryLimit, limit, maxHistoryLimit)
+	}
+
+	bech32Addr, err := resolver.getRegisteredUserAddress(userAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	auditEntries, err := resolver.auditSink.GetUserEntries(bech32Addr, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]requests.HistoryEntry, 0, len(auditEntries))
+	for _, auditEntry := range auditEntries {
+		entries = append(entries, requests.HistoryEntry{
+			Timestamp:  auditEntry.Timestamp,
+			Route:      auditEntry.Route,
+			Guardian:   auditEntry.Guardian,
+			UserIp:     auditEntry.UserIp,
+			UserAgent:  auditEntry.UserAgent,
+			TxHashes:   auditEntry.TxHashes,
+			Outcome:    auditEntry.Outcome,
+			ErrorClass: auditEntry.ErrorClass,
+		})
+	}
+
+	return &requests.UserHistoryResponse{
+		Entries: entries,
+		Offset:  offset,
+		Limit:   limit,
+	}, nil
+}
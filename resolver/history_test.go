@@ -0,0 +1,105 @@
+package resolver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+func TestServiceResolver_GetHistory(t *testing.T) {
+	t.Parallel()
+
+	t.Run("limit too high should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+		ctx.resolver.auditSink = &testscommon.AuditSinkStub{
+			GetUserEntriesCalled: func(userAddress string, offset uint32, limit uint32) ([]core.AuditEntry, error) {
+				assert.Fail(t, "should have not been called")
+				return nil, nil
+			},
+		}
+
+		history, err := ctx.resolver.GetHistory(ctx.userAddress, 0, maxHistoryLimit+1)
+		assert.True(t, errors.Is(err, ErrInvalidHistoryLimit))
+		assert.Nil(t, history)
+	})
+	t.Run("unregistered user should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+		ctx.resolver.registeredUsersDB = &testscommon.ShardedStorageWithIndexStub{
+			HasCalled: func(key []byte) error {
+				return expectedErr
+			},
+		}
+
+		history, err := ctx.resolver.GetHistory(ctx.userAddress, 0, 0)
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, history)
+	})
+	t.Run("audit sink error should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+		ctx.resolver.auditSink = &testscommon.AuditSinkStub{
+			GetUserEntriesCalled: func(userAddress string, offset uint32, limit uint32) ([]core.AuditEntry, error) {
+				return nil, expectedErr
+			},
+		}
+
+		history, err := ctx.resolver.GetHistory(ctx.userAddress, 0, 0)
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, history)
+	})
+	t.Run("should work with default limit", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+		providedEntries := []core.AuditEntry{
+			{
+				Timestamp:   200,
+				Route:       "/guardian/sign-transaction",
+				UserAddress: usrAddr,
+				UserIp:      "127.0.0.1",
+				UserAgent:   "agent",
+				TxHashes:    []string{"hash"},
+				Outcome:     core.AuditOutcomeSuccess,
+			},
+			{
+				Timestamp:   100,
+				Route:       "/guardian/sign-message",
+				UserAddress: usrAddr,
+				Guardian:    "guardian",
+				UserIp:      "10.0.0.1",
+				Outcome:     core.AuditOutcomeFailure,
+				ErrorClass:  "wrong code",
+			},
+		}
+		ctx.resolver.auditSink = &testscommon.AuditSinkStub{
+			GetUserEntriesCalled: func(userAddress string, offset uint32, limit uint32) ([]core.AuditEntry, error) {
+				assert.Equal(t, usrAddr, userAddress)
+				assert.Equal(t, uint32(5), offset)
+				assert.Equal(t, uint32(defaultHistoryLimit), limit)
+				return providedEntries, nil
+			},
+		}
+
+		history, err := ctx.resolver.GetHistory(ctx.userAddress, 5, 0)
+		require.Nil(t, err)
+		assert.Equal(t, uint32(5), history.Offset)
+		assert.Equal(t, uint32(defaultHistoryLimit), history.Limit)
+		require.Equal(t, 2, len(history.Entries))
+		assert.Equal(t, int64(200), history.Entries[0].Timestamp)
+		assert.Equal(t, []string{"hash"}, history.Entries[0].TxHashes)
+		assert.Equal(t, core.AuditOutcomeSuccess, history.Entries[0].Outcome)
+		assert.Equal(t, "10.0.0.1", history.Entries[1].UserIp)
+		assert.Equal(t, "guardian", history.Entries[1].Guardian)
+		assert.Equal(t, "wrong code", history.Entries[1].ErrorClass)
+	})
+}
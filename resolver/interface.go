@@ -1,7 +1,9 @@
 package resolver
 
 import (
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/txdecoder"
 	sdkCore "github.com/multiversx/mx-sdk-go/core"
 )
 
@@ -12,16 +14,26 @@ type CryptoComponentsHolderFactory interface {
 	IsInterfaceNil() bool
 }
 
-// Encryptor is the interface that defines the methods that can be used to encrypt and decrypt data
+// Encryptor is the interface that defines the methods that can be used to encrypt and decrypt data.
+// Data is always encrypted with the current key, while it can be decrypted with any of the known keys
 type Encryptor interface {
-	EncryptData(data []byte) ([]byte, error)
-	DecryptData(data []byte) ([]byte, error)
+	CurrentKeyID() uint32
+	EncryptData(data []byte, associatedData []byte) ([]byte, error)
+	DecryptData(keyID uint32, data []byte, associatedData []byte) ([]byte, error)
+	DecryptLegacyData(keyID uint32, data []byte) ([]byte, error)
 	IsInterfaceNil() bool
 }
 
 // UserEncryptor is the interface that defines the methods that can be used to encrypt and decrypt user info
 type UserEncryptor interface {
-	EncryptUserInfo(userInfo *core.UserInfo) (*core.UserInfo, error)
-	DecryptUserInfo(userInfo *core.UserInfo) (*core.UserInfo, error)
+	EncryptUserInfo(userAddress []byte, userInfo *core.UserInfo) (*core.UserInfo, error)
+	DecryptUserInfo(userAddress []byte, userInfo *core.UserInfo) (*core.UserInfo, error)
+	IsEncryptedWithCurrentKey(userInfo *core.UserInfo) bool
+	IsInterfaceNil() bool
+}
+
+// TxDecoder is the interface that defines the methods that can be used to decode the transfers of a transaction
+type TxDecoder interface {
+	Decode(tx transaction.FrontendTransaction) (*txdecoder.DecodedTransaction, error)
 	IsInterfaceNil() bool
 }
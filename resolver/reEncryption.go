@@ -0,0 +1,164 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	sdkData "github.com/multiversx/mx-sdk-go/data"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage"
+)
+
+const logReEncryptionProgressInterval = 1000
+
+// reEncryptionStatus holds the progress of the last re-encryption job
+type reEncryptionStatus struct {
+	mut      sync.RWMutex
+	response requests.ReEncryptionStatusResponse
+}
+
+// StartReEncryption starts re-encrypting in background all the users which are not encrypted with the current key
+// and scheme. Its progress is returned by GetReEncryptionStatus
+func (resolver *serviceResolver) StartReEncryption() error {
+	status := resolver.reEncryptionStatus
+	status.mut.Lock()
+	defer status.mut.Unlock()
+
+	if status.response.Running {
+		return ErrReEncryptionInProgress
+	}
+
+	status.response = requests.ReEncryptionStatusResponse{
+		Running:            true,
+		StartTimestamp:     resolver.getTimeHandler().Unix(),
+		RemainingOnOldKeys: make(map[uint32]int),
+	}
+
+	go resolver.reEncryptUsers(context.Background())
+
+	return nil
+}
+
+// GetReEncryptionStatus returns the progress of the last re-encryption job
+func (resolver *serviceResolver) GetReEncryptionStatus() *requests.ReEncryptionStatusResponse {
+	status := resolver.reEncryptionStatus
+	status.mut.RLock()
+	defer status.mut.RUnlock()
+
+	response := status.response
+	response.RemainingOnOldKeys = make(map[uint32]int, len(status.response.RemainingOnOldKeys))
+	for keyID, numRemaining := range status.response.RemainingOnOldKeys {
+		response.RemainingOnOldKeys[keyID] = numRemaining
+	}
+
+	return &response
+}
+
+// reEncryptUsers first counts the users on old keys, as the key id is not encrypted, then re-encrypts them one by one.
+// A user which cannot be re-encrypted is left on its old key and counted as failed
+func (resolver *serviceResolver) reEncryptUsers(ctx context.Context) {
+	oldKeyIDs := make(map[string]uint32)
+	numUsers := 0
+	err := resolver.registeredUsersDB.RangeKeys(ctx, func(key []byte, val []byte) bool {
+		userInfo := &core.UserInfo{}
+		errUnmarshal := resolver.userDataMarshaller.Unmarshal(userInfo, val)
+		if errUnmarshal != nil {
+			log.Debug("could not unmarshal user while counting the users on old keys", "error", errUnmarshal)
+			return true
+		}
+
+		numUsers++
+		if !resolver.userEncryptor.IsEncryptedWithCurrentKey(userInfo) {
+			oldKeyIDs[string(key)] = userInfo.EncryptionKeyID
+		}
+
+		return true
+	})
+	if err != nil {
+		resolver.finishReEncryption(err)
+		return
+	}
+
+	resolver.updateReEncryptionStatus(func(response *requests.ReEncryptionStatusResponse) {
+		response.NumUsers = numUsers
+		response.NumOnOldKeys = len(oldKeyIDs)
+		for _, keyID := range oldKeyIDs {
+			response.RemainingOnOldKeys[keyID]++
+		}
+	})
+	log.Info("re-encryption started", "num users", numUsers, "num users on old keys", len(oldKeyIDs))
+
+	numProcessed := 0
+	for userAddress, oldKeyID := range oldKeyIDs {
+		if ctx.Err() != nil {
+			resolver.finishReEncryption(ctx.Err())
+			return
+		}
+
+		errReEncrypt := resolver.ReEncryptUser(sdkData.NewAddressFromBytes([]byte(userAddress)))
+		// a user removed meanwhile is no longer on the old key, without being re-encrypted
+		wasRemoved := errors.Is(errReEncrypt, storage.ErrKeyNotFound)
+		if wasRemoved {
+			errReEncrypt = nil
+		}
+		resolver.updateReEncryptionStatus(func(response *requests.ReEncryptionStatusResponse) {
+			if errReEncrypt != nil {
+				response.NumFailed++
+				return
+			}
+
+			if !wasRemoved {
+				response.NumReEncrypted++
+			}
+			response.RemainingOnOldKeys[oldKeyID]--
+			if response.RemainingOnOldKeys[oldKeyID] == 0 {
+				delete(response.RemainingOnOldKeys, oldKeyID)
+			}
+		})
+		if errReEncrypt != nil {
+			log.Warn("could not re-encrypt user",
+				"userAddress", resolver.pubKeyConverter.SilentEncode([]byte(userAddress), log),
+				"error", errReEncrypt)
+		}
+
+		numProcessed++
+		if numProcessed%logReEncryptionProgressInterval == 0 {
+			response := resolver.GetReEncryptionStatus()
+			log.Info("re-encryption in progress", "re-encrypted users", response.NumReEncrypted,
+				"failed users", response.NumFailed, "remaining users", len(oldKeyIDs)-numProcessed)
+		}
+	}
+
+	resolver.finishReEncryption(nil)
+}
+
+func (resolver *serviceResolver) updateReEncryptionStatus(handler func(response *requests.ReEncryptionStatusResponse)) {
+	status := resolver.reEncryptionStatus
+	status.mut.Lock()
+	handler(&status.response)
+	status.mut.Unlock()
+}
+
+func (resolver *serviceResolver) finishReEncryption(err error) {
+	resolver.updateReEncryptionStatus(func(response *requests.ReEncryptionStatusResponse) {
+		response.Running = false
+		response.FinishTimestamp = resolver.getTimeHandler().Unix()
+		if err != nil {
+			response.Error = err.Error()
+		}
+	})
+
+	response := resolver.GetReEncryptionStatus()
+	if err != nil {
+		log.Error("re-encryption stopped, it can be started again", "error", err,
+			"re-encrypted users", response.NumReEncrypted, "failed users", response.NumFailed,
+			"remaining on old keys", response.RemainingOnOldKeys)
+		return
+	}
+
+	log.Info("re-encryption completed", "re-encrypted users", response.NumReEncrypted,
+		"failed users", response.NumFailed, "remaining on old keys", response.RemainingOnOldKeys)
+}
@@ -0,0 +1,175 @@
+package resolver
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+const currentTestKeyID = uint32(2)
+
+func createReEncryptionTestResolver(t *testing.T, users map[string]uint32) (*serviceResolver, map[string][]byte, *sync.Mutex) {
+	args := createMockArgs()
+	db := make(map[string][]byte)
+	mutDB := &sync.Mutex{}
+	for userAddress, keyID := range users {
+		userInfo := *providedUserInfo
+		userInfo.EncryptionKeyID = keyID
+		userInfo.EncryptionVersion = uint32(core.AEADEncryption)
+		db[userAddress], _ = args.UserDataMarshaller.Marshal(&userInfo)
+	}
+
+	args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
+		PutCalled: func(key, data []byte) error {
+			mutDB.Lock()
+			db[string(key)] = data
+			mutDB.Unlock()
+			return nil
+		},
+		GetCalled: func(key []byte) ([]byte, error) {
+			if string(key) == "removed" {
+				return nil, storage.ErrKeyNotFound
+			}
+
+			mutDB.Lock()
+			defer mutDB.Unlock()
+			return db[string(key)], nil
+		},
+		RangeKeysCalled: func(ctx context.Context, handler func(key []byte, val []byte) bool) error {
+			mutDB.Lock()
+			entries := make(map[string][]byte, len(db))
+			for key, val := range db {
+				entries[key] = val
+			}
+			mutDB.Unlock()
+
+			for key, val := range entries {
+				if !handler([]byte(key), val) {
+					return nil
+				}
+			}
+			return nil
+		},
+	}
+	args.UserEncryptor = &testscommon.UserEncryptorStub{
+		EncryptUserInfoCalled: func(userAddress []byte, userInfo *core.UserInfo) (*core.UserInfo, error) {
+			if string(userAddress) == "failing" {
+				return nil, expectedErr
+			}
+
+			userCopy := *userInfo
+			userCopy.EncryptionKeyID = currentTestKeyID
+			return &userCopy, nil
+		},
+		DecryptUserInfoCalled: func(userAddress []byte, userInfo *core.UserInfo) (*core.UserInfo, error) {
+			userCopy := *userInfo
+			return &userCopy, nil
+		},
+		IsEncryptedWithCurrentKeyCalled: func(userInfo *core.UserInfo) bool {
+			return userInfo.EncryptionKeyID == currentTestKeyID
+		},
+	}
+
+	resolver, err := NewServiceResolver(args)
+	require.Nil(t, err)
+	resolver.getTimeHandler = func() time.Time {
+		return time.Unix(1000, 0)
+	}
+
+	return resolver, db, mutDB
+}
+
+func waitReEncryption(t *testing.T, resolver *serviceResolver) *requests.ReEncryptionStatusResponse {
+	require.Eventually(t, func() bool {
+		return !resolver.GetReEncryptionStatus().Running
+	}, time.Second, time.Millisecond)
+
+	return resolver.GetReEncryptionStatus()
+}
+
+func TestServiceResolver_StartReEncryption(t *testing.T) {
+	t.Parallel()
+
+	t.Run("already running should error", func(t *testing.T) {
+		t.Parallel()
+
+		resolver, _, _ := createReEncryptionTestResolver(t, nil)
+		resolver.reEncryptionStatus.response.Running = true
+
+		err := resolver.StartReEncryption()
+		assert.Equal(t, ErrReEncryptionInProgress, err)
+	})
+	t.Run("range error should stop", func(t *testing.T) {
+		t.Parallel()
+
+		resolver, _, _ := createReEncryptionTestResolver(t, nil)
+		resolver.registeredUsersDB = &testscommon.ShardedStorageWithIndexStub{
+			RangeKeysCalled: func(ctx context.Context, handler func(key []byte, val []byte) bool) error {
+				return expectedErr
+			},
+		}
+
+		err := resolver.StartReEncryption()
+		require.Nil(t, err)
+
+		status := waitReEncryption(t, resolver)
+		assert.Equal(t, expectedErr.Error(), status.Error)
+		assert.Equal(t, int64(1000), status.FinishTimestamp)
+		assert.Zero(t, status.NumReEncrypted)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		users := map[string]uint32{
+			"user0a":  0,
+			"user0b":  0,
+			"user1":   1,
+			"user2":   currentTestKeyID,
+			"failing": 1,
+			"removed": 0,
+		}
+		resolver, db, mutDB := createReEncryptionTestResolver(t, users)
+
+		err := resolver.StartReEncryption()
+		require.Nil(t, err)
+
+		status := waitReEncryption(t, resolver)
+		expectedStatus := &requests.ReEncryptionStatusResponse{
+			StartTimestamp:     1000,
+			FinishTimestamp:    1000,
+			NumUsers:           6,
+			NumOnOldKeys:       5,
+			NumReEncrypted:     3,
+			NumFailed:          1,
+			RemainingOnOldKeys: map[uint32]int{1: 1},
+		}
+		assert.Equal(t, expectedStatus, status)
+
+		for _, userAddress := range []string{"user0a", "user0b", "user1"} {
+			mutDB.Lock()
+			userInfo := &core.UserInfo{}
+			err = resolver.userDataMarshaller.Unmarshal(userInfo, db[userAddress])
+			mutDB.Unlock()
+			require.Nil(t, err)
+			assert.Equal(t, currentTestKeyID, userInfo.EncryptionKeyID)
+		}
+
+		// a new job only finds the users which were not re-encrypted before
+		err = resolver.StartReEncryption()
+		require.Nil(t, err)
+
+		status = waitReEncryption(t, resolver)
+		assert.Equal(t, 2, status.NumOnOldKeys)
+		assert.Zero(t, status.NumReEncrypted)
+		assert.Equal(t, 1, status.NumFailed)
+	})
+}
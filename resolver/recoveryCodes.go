@@ -0,0 +1,180 @@
+package resolver
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"strings"
+
+	sdkCore "github.com/multiversx/mx-sdk-go/core"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+)
+
+const (
+	numRecoveryCodes        = 10
+	recoveryCodeBytesLength = 10
+	recoveryCodeGroupLength = 4
+	recoveryCodeSeparator   = "-"
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// RegenerateRecoveryCodes verifies the codes and then replaces the recovery codes of the user with a new set
+func (resolver *serviceResolver) RegenerateRecoveryCodes(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error) {
+	guardianAddr, err := resolver.pubKeyConverter.Decode(request.Guardian)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	addressBytes := userAddress.AddressBytes()
+	resolver.userCritSection.Lock(string(addressBytes))
+	defer resolver.userCritSection.Unlock(string(addressBytes))
+
+	userInfo, err := resolver.getUserInfo(addressBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bech32Addr, err := userAddress.AddressAsBech32String()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	verifyCodeData, err := resolver.verifyCodeOrAssertion(userInfo, addressBytes, bech32Addr, userIp, guardianAddr, request)
+	if err != nil {
+		return nil, verifyCodeData, err
+	}
+
+	_, err = resolver.getGuardianInfoFromAddress(addressBytes, request.Guardian, userInfo)
+	if err != nil {
+		return nil, verifyCodeData, err
+	}
+
+	recoveryCodes, err := resolver.generateRecoveryCodesAndSave(addressBytes, userInfo)
+	if err != nil {
+		return nil, verifyCodeData, err
+	}
+
+	log.Debug("recovery codes regenerated",
+		"userAddress", bech32Addr,
+		"guardian", request.Guardian)
+
+	return &requests.RecoveryCodesResponse{
+		RecoveryCodes: recoveryCodes,
+	}, verifyCodeData, nil
+}
+
+// generateRecoveryCodesIfMissing generates a new set of recovery codes only if the user has none left
+func (resolver *serviceResolver) generateRecoveryCodesIfMissing(userAddress []byte, userInfo *core.UserInfo) (*requests.RecoveryCodesResponse, error) {
+	if len(userInfo.RecoveryCodes) > 0 {
+		return nil, nil
+	}
+
+	recoveryCodes, err := resolver.generateRecoveryCodesAndSave(userAddress, userInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &requests.RecoveryCodesResponse{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// generateRecoveryCodesAndSave replaces the recovery codes of the user, storing only their hashes.
+// The user lock must be held by the caller
+func (resolver *serviceResolver) generateRecoveryCodesAndSave(userAddress []byte, userInfo *core.UserInfo) ([]string, error) {
+	recoveryCodes := make([]string, 0, numRecoveryCodes)
+	hashedRecoveryCodes := make([][]byte, 0, numRecoveryCodes)
+	for i := 0; i < numRecoveryCodes; i++ {
+		recoveryCode, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		recoveryCodes = append(recoveryCodes, recoveryCode)
+		hashedRecoveryCodes = append(hashedRecoveryCodes, hashRecoveryCode(userAddress, recoveryCode))
+	}
+
+	userInfo.RecoveryCodes = hashedRecoveryCodes
+	err := resolver.marshalAndSaveEncrypted(userAddress, userInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// consumeRecoveryCode verifies the recovery code and removes it from the user info, counting as a verification trial.
+// The caller must save the user info while holding the user lock
+func (resolver *serviceResolver) consumeRecoveryCode(
+	userInfo *core.UserInfo,
+	userAddress []byte,
+	bech32Addr string,
+	userIp string,
+	recoveryCode string,
+) (*requests.OTPCodeVerifyData, error) {
+	verifyCodeData, err := resolver.secureOtpHandler.IsVerificationAllowedAndIncreaseTrials(bech32Addr, userIp)
+	if err != nil {
+		resolver.extendSecurityMode(verifyCodeData, bech32Addr)
+		return verifyCodeData, err
+	}
+
+	hashedRecoveryCode := hashRecoveryCode(userAddress, recoveryCode)
+	for i, storedRecoveryCode := range userInfo.RecoveryCodes {
+		if subtle.ConstantTimeCompare(storedRecoveryCode, hashedRecoveryCode) != 1 {
+			continue
+		}
+
+		remainingRecoveryCodes := make([][]byte, 0, len(userInfo.RecoveryCodes)-1)
+		remainingRecoveryCodes = append(remainingRecoveryCodes, userInfo.RecoveryCodes[:i]...)
+		userInfo.RecoveryCodes = append(remainingRecoveryCodes, userInfo.RecoveryCodes[i+1:]...)
+		resolver.secureOtpHandler.Reset(bech32Addr, userIp)
+
+		log.Debug("recovery code used",
+			"userAddress", bech32Addr,
+			"remaining recovery codes", len(userInfo.RecoveryCodes))
+
+		return &requests.OTPCodeVerifyData{
+			RemainingTrials:             int(resolver.secureOtpHandler.FreezeMaxFailures()),
+			SecurityModeRemainingTrials: verifyCodeData.SecurityModeRemainingTrials,
+			SecurityModeResetAfter:      verifyCodeData.SecurityModeResetAfter,
+		}, nil
+	}
+
+	resolver.extendSecurityMode(verifyCodeData, bech32Addr)
+
+	return verifyCodeData, ErrInvalidRecoveryCode
+}
+
+// generateRecoveryCode returns a random code formatted as groups of base32 characters, eg: ABCD-EFGH-IJKL-MNOP
+func generateRecoveryCode() (string, error) {
+	buff := make([]byte, recoveryCodeBytesLength)
+	_, err := rand.Read(buff)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := recoveryCodeEncoding.EncodeToString(buff)
+	groups := make([]string, 0, len(encoded)/recoveryCodeGroupLength+1)
+	for len(encoded) > recoveryCodeGroupLength {
+		groups = append(groups, encoded[:recoveryCodeGroupLength])
+		encoded = encoded[recoveryCodeGroupLength:]
+	}
+	groups = append(groups, encoded)
+
+	return strings.Join(groups, recoveryCodeSeparator), nil
+}
+
+// hashRecoveryCode hashes the recovery code bound to the user address, ignoring the separators and the case
+func hashRecoveryCode(userAddress []byte, recoveryCode string) []byte {
+	normalizedCode := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(recoveryCode), recoveryCodeSeparator, ""))
+
+	hasher := sha256.New()
+	_, _ = hasher.Write(userAddress)
+	_, _ = hasher.Write([]byte(normalizedCode))
+
+	return hasher.Sum(nil)
+}
@@ -0,0 +1,188 @@
+package resolver
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+var providedRecoveryCode = "ABCD-EFGH-IJKL-MNOP"
+
+func createRecoveryCodesTestContext(t *testing.T, recoveryCodes ...string) *webAuthnTestContext {
+	ctx := createWebAuthnTestContext(t, createRegisteredWebAuthnInfo())
+	ctx.resolver.webAuthnHandler = acceptAssertion(t, ctx.createBinding(nil))
+
+	if len(recoveryCodes) == 0 {
+		return ctx
+	}
+
+	userInfo := ctx.getUserInfo(t)
+	for _, recoveryCode := range recoveryCodes {
+		userInfo.RecoveryCodes = append(userInfo.RecoveryCodes, hashRecoveryCode(ctx.userAddress.AddressBytes(), recoveryCode))
+	}
+	err := ctx.resolver.marshalAndSaveEncrypted(ctx.userAddress.AddressBytes(), userInfo)
+	require.Nil(t, err)
+
+	return ctx
+}
+
+func (ctx *webAuthnTestContext) getUserInfo(t *testing.T) *core.UserInfo {
+	userInfo, err := ctx.resolver.getUserInfo(ctx.userAddress.AddressBytes())
+	require.Nil(t, err)
+
+	return userInfo
+}
+
+func TestServiceResolver_RegenerateRecoveryCodes(t *testing.T) {
+	t.Parallel()
+
+	providedRequest := requests.VerificationPayload{
+		Assertion: &providedAssertion,
+		Guardian:  string(providedUserInfo.FirstGuardian.PublicKey),
+	}
+	t.Run("invalid assertion should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createRecoveryCodesTestContext(t, providedRecoveryCode)
+		ctx.resolver.webAuthnHandler = &testscommon.WebAuthnHandlerStub{
+			VerifyAssertionCalled: func(credential core.WebAuthnInfo, binding []byte, assertion requests.WebAuthnAssertion) (*core.WebAuthnInfo, error) {
+				return nil, expectedErr
+			},
+		}
+
+		response, _, err := ctx.resolver.RegenerateRecoveryCodes(ctx.userAddress, "userIp", providedRequest)
+		assert.True(t, errors.Is(err, expectedErr))
+		assert.Nil(t, response)
+		assert.Equal(t, 1, len(ctx.getUserInfo(t).RecoveryCodes))
+	})
+	t.Run("unknown guardian should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createRecoveryCodesTestContext(t, providedRecoveryCode)
+		request := providedRequest
+		request.Guardian = "unknown guardian"
+
+		response, _, err := ctx.resolver.RegenerateRecoveryCodes(ctx.userAddress, "userIp", request)
+		assert.NotNil(t, err)
+		assert.Nil(t, response)
+		assert.Equal(t, 1, len(ctx.getUserInfo(t).RecoveryCodes))
+	})
+	t.Run("should work and invalidate the previous codes", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createRecoveryCodesTestContext(t, providedRecoveryCode)
+
+		response, _, err := ctx.resolver.RegenerateRecoveryCodes(ctx.userAddress, "userIp", providedRequest)
+		require.Nil(t, err)
+		require.Equal(t, numRecoveryCodes, len(response.RecoveryCodes))
+
+		userInfo := ctx.getUserInfo(t)
+		require.Equal(t, numRecoveryCodes, len(userInfo.RecoveryCodes))
+		oldHash := hashRecoveryCode(ctx.userAddress.AddressBytes(), providedRecoveryCode)
+		for i, recoveryCode := range response.RecoveryCodes {
+			assert.Equal(t, hashRecoveryCode(ctx.userAddress.AddressBytes(), recoveryCode), userInfo.RecoveryCodes[i])
+			assert.NotEqual(t, oldHash, userInfo.RecoveryCodes[i])
+		}
+	})
+}
+
+func TestServiceResolver_VerifyCodeGeneratesRecoveryCodes(t *testing.T) {
+	t.Parallel()
+
+	providedRequest := requests.VerificationPayload{
+		Assertion: &providedAssertion,
+		Guardian:  string(providedUserInfo.FirstGuardian.PublicKey),
+	}
+	t.Run("missing recovery codes should generate them", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createRecoveryCodesTestContext(t)
+
+		response, _, err := ctx.resolver.VerifyCode(ctx.userAddress, "userIp", providedRequest)
+		require.Nil(t, err)
+		require.NotNil(t, response)
+		assert.Equal(t, numRecoveryCodes, len(response.RecoveryCodes))
+		assert.Equal(t, numRecoveryCodes, len(ctx.getUserInfo(t).RecoveryCodes))
+	})
+	t.Run("existing recovery codes should not be returned again", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createRecoveryCodesTestContext(t, providedRecoveryCode)
+
+		response, _, err := ctx.resolver.VerifyCode(ctx.userAddress, "userIp", providedRequest)
+		require.Nil(t, err)
+		assert.Nil(t, response)
+		assert.Equal(t, 1, len(ctx.getUserInfo(t).RecoveryCodes))
+	})
+}
+
+func TestServiceResolver_UnsetSecurityModeNoExpireWithRecoveryCode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid recovery code should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createRecoveryCodesTestContext(t, providedRecoveryCode)
+		request := requests.SecurityModeNoExpire{
+			UserAddr:     usrAddr,
+			RecoveryCode: "AAAA-BBBB-CCCC-DDDD",
+		}
+
+		_, err := ctx.resolver.UnsetSecurityModeNoExpire("userIp", request)
+		assert.Equal(t, ErrInvalidRecoveryCode, err)
+		assert.Equal(t, 1, ctx.numExtendCalled)
+		assert.Equal(t, 0, ctx.numResets)
+		assert.Equal(t, 1, len(ctx.getUserInfo(t).RecoveryCodes))
+	})
+	t.Run("should work only once", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createRecoveryCodesTestContext(t, "AAAA-BBBB-CCCC-DDDD", providedRecoveryCode)
+		wasUnsetCalled := false
+		ctx.resolver.secureOtpHandler.(*testscommon.SecureOtpHandlerStub).UnsetSecurityModeNoExpireCalled = func(account string) error {
+			assert.Equal(t, usrAddr, account)
+			wasUnsetCalled = true
+			return nil
+		}
+		request := requests.SecurityModeNoExpire{
+			UserAddr:     usrAddr,
+			RecoveryCode: " abcdefgh-ijklmnop ",
+		}
+
+		_, err := ctx.resolver.UnsetSecurityModeNoExpire("userIp", request)
+		require.Nil(t, err)
+		assert.True(t, wasUnsetCalled)
+		assert.Equal(t, 1, ctx.numResets)
+
+		userInfo := ctx.getUserInfo(t)
+		require.Equal(t, 1, len(userInfo.RecoveryCodes))
+		assert.Equal(t, hashRecoveryCode(ctx.userAddress.AddressBytes(), "AAAA-BBBB-CCCC-DDDD"), userInfo.RecoveryCodes[0])
+
+		_, err = ctx.resolver.UnsetSecurityModeNoExpire("userIp", request)
+		assert.Equal(t, ErrInvalidRecoveryCode, err)
+	})
+}
+
+func TestGenerateRecoveryCode(t *testing.T) {
+	t.Parallel()
+
+	recoveryCode, err := generateRecoveryCode()
+	require.Nil(t, err)
+
+	groups := strings.Split(recoveryCode, recoveryCodeSeparator)
+	assert.Equal(t, 4, len(groups))
+	for _, group := range groups {
+		assert.Equal(t, recoveryCodeGroupLength, len(group))
+	}
+
+	otherRecoveryCode, err := generateRecoveryCode()
+	require.Nil(t, err)
+	assert.NotEqual(t, recoveryCode, otherRecoveryCode)
+}
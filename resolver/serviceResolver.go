@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/gorilla/schema"
+	chainCore "github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-core-go/data"
 	"github.com/multiversx/mx-chain-core-go/data/api"
@@ -48,6 +49,9 @@ type ArgServiceResolver struct {
 	UserEncryptor                 UserEncryptor
 	TOTPHandler                   handlers.TOTPHandler
 	SecureOtpHandler              handlers.SecureOtpHandler
+	TxPolicyHandler               handlers.TxPolicyHandler
+	TxDecoder                     TxDecoder
+	WebAuthnHandler               handlers.WebAuthnHandler
 	HttpClientWrapper             core.HttpClientWrapper
 	KeysGenerator                 core.KeysGenerator
 	PubKeyConverter               core.PubkeyConverter
@@ -57,6 +61,8 @@ type ArgServiceResolver struct {
 	SignatureVerifier             builders.Signer
 	GuardedTxBuilder              core.GuardedTxBuilder
 	RegisteredUsersDB             core.StorageWithIndex
+	AuditSink                     core.AuditSink
+	Notifier                      core.Notifier
 	KeyGen                        crypto.KeyGenerator
 	CryptoComponentsHolderFactory CryptoComponentsHolderFactory
 	Config                        config.ServiceResolverConfig
@@ -66,6 +72,9 @@ type serviceResolver struct {
 	userEncryptor                 UserEncryptor
 	totpHandler                   handlers.TOTPHandler
 	secureOtpHandler              handlers.SecureOtpHandler
+	txPolicyHandler               handlers.TxPolicyHandler
+	txDecoder                     TxDecoder
+	webAuthnHandler               handlers.WebAuthnHandler
 	httpClientWrapper             core.HttpClientWrapper
 	keysGenerator                 core.KeysGenerator
 	pubKeyConverter               core.PubkeyConverter
@@ -76,11 +85,15 @@ type serviceResolver struct {
 	signatureVerifier             builders.Signer
 	guardedTxBuilder              core.GuardedTxBuilder
 	registeredUsersDB             core.StorageWithIndex
+	auditSink                     core.AuditSink
+	notifier                      core.Notifier
 	keyGen                        crypto.KeyGenerator
 	cryptoComponentsHolderFactory CryptoComponentsHolderFactory
 	config                        config.ServiceResolverConfig
+	getTimeHandler                func() time.Time
 
-	userCritSection sync.KeyRWMutexHandler
+	userCritSection    sync.KeyRWMutexHandler
+	reEncryptionStatus *reEncryptionStatus
 }
 
 // NewServiceResolver returns a new instance of service resolver
@@ -94,6 +107,9 @@ func NewServiceResolver(args ArgServiceResolver) (*serviceResolver, error) {
 		userEncryptor:                 args.UserEncryptor,
 		totpHandler:                   args.TOTPHandler,
 		secureOtpHandler:              args.SecureOtpHandler,
+		txPolicyHandler:               args.TxPolicyHandler,
+		txDecoder:                     args.TxDecoder,
+		webAuthnHandler:               args.WebAuthnHandler,
 		httpClientWrapper:             args.HttpClientWrapper,
 		keysGenerator:                 args.KeysGenerator,
 		pubKeyConverter:               args.PubKeyConverter,
@@ -104,10 +120,14 @@ func NewServiceResolver(args ArgServiceResolver) (*serviceResolver, error) {
 		signatureVerifier:             args.SignatureVerifier,
 		guardedTxBuilder:              args.GuardedTxBuilder,
 		registeredUsersDB:             args.RegisteredUsersDB,
+		auditSink:                     args.AuditSink,
+		notifier:                      args.Notifier,
 		keyGen:                        args.KeyGen,
 		cryptoComponentsHolderFactory: args.CryptoComponentsHolderFactory,
 		config:                        args.Config,
+		getTimeHandler:                time.Now,
 		userCritSection:               sync.NewKeyRWMutex(),
+		reEncryptionStatus:            &reEncryptionStatus{},
 	}
 
 	return resolver, nil
@@ -123,6 +143,15 @@ func checkArgs(args ArgServiceResolver) error {
 	if check.IfNil(args.SecureOtpHandler) {
 		return ErrNilSecureOtpHandler
 	}
+	if check.IfNil(args.TxPolicyHandler) {
+		return ErrNilTxPolicyHandler
+	}
+	if check.IfNil(args.TxDecoder) {
+		return ErrNilTxDecoder
+	}
+	if check.IfNil(args.WebAuthnHandler) {
+		return ErrNilWebAuthnHandler
+	}
 	if check.IfNil(args.HttpClientWrapper) {
 		return ErrNilHTTPClientWrapper
 	}
@@ -153,6 +182,12 @@ func checkArgs(args ArgServiceResolver) error {
 	if check.IfNil(args.RegisteredUsersDB) {
 		return fmt.Errorf("%w for registered users", ErrNilDB)
 	}
+	if check.IfNil(args.AuditSink) {
+		return ErrNilAuditSink
+	}
+	if check.IfNil(args.Notifier) {
+		return ErrNilNotifier
+	}
 	if check.IfNil(args.KeyGen) {
 		return ErrNilKeyGenerator
 	}
@@ -173,7 +208,8 @@ func checkArgs(args ArgServiceResolver) error {
 
 // RegisterUser creates a new OTP for the given provider
 // and (optionally) returns some information required for the user to set up the OTP on his end (eg: QR code).
-func (resolver *serviceResolver) RegisterUser(userAddress sdkCore.AddressHandler, request requests.RegistrationPayload) (*requests.OTP, string, error) {
+// A recovery code can be provided in order to replace the OTP of an already registered user without waiting for the delay
+func (resolver *serviceResolver) RegisterUser(userAddress sdkCore.AddressHandler, userIp string, request requests.RegistrationPayload) (*requests.OTP, string, error) {
 	tag := resolver.extractUserTagForSecretGeneration(request.Tag, userAddress.Pretty())
 	otp, err := resolver.totpHandler.CreateTOTP(tag)
 	if err != nil {
@@ -190,7 +226,7 @@ func (resolver *serviceResolver) RegisterUser(userAddress sdkCore.AddressHandler
 		return &requests.OTP{}, "", err
 	}
 
-	guardianAddress, otpAge, err := resolver.registerUser(userAddress, otp)
+	guardianAddress, otpAge, err := resolver.registerUser(userAddress, userIp, request.RecoveryCode, otp)
 	if err != nil {
 		return &requests.OTP{
 			TimeSinceGeneration: otpAge,
@@ -204,14 +240,22 @@ func (resolver *serviceResolver) RegisterUser(userAddress sdkCore.AddressHandler
 		}, "", err
 	}
 
+	resolver.notifier.Notify(core.SecurityEvent{
+		Type:        core.GuardianRegisteredEvent,
+		Timestamp:   resolver.getTimeHandler().Unix(),
+		UserAddress: resolver.pubKeyConverter.SilentEncode(userAddress.AddressBytes(), log),
+		UserIp:      userIp,
+		Guardian:    encodedAddr,
+	})
+
 	return otpInfo, encodedAddr, nil
 }
 
-// VerifyCode validates the code received
-func (resolver *serviceResolver) VerifyCode(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error) {
+// VerifyCode validates the code received. If the user has no recovery codes left, a new set is generated and returned
+func (resolver *serviceResolver) VerifyCode(userAddress sdkCore.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error) {
 	guardianAddr, err := resolver.pubKeyConverter.Decode(request.Guardian)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	addressBytes := userAddress.AddressBytes()
@@ -220,29 +264,42 @@ func (resolver *serviceResolver) VerifyCode(userAddress sdkCore.AddressHandler,
 
 	userInfo, err := resolver.getUserInfo(addressBytes)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	bech32Addr, err := userAddress.AddressAsBech32String()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	verifyCodeData, err := resolver.checkAllowanceAndVerifyCode(userInfo, bech32Addr, userIp, request.Code, request.SecondCode, guardianAddr)
+	verifyCodeData, err := resolver.verifyCodeOrAssertion(userInfo, addressBytes, bech32Addr, userIp, guardianAddr, request)
 	if err != nil {
-		return verifyCodeData, err
+		return nil, verifyCodeData, err
+	}
+
+	err = resolver.marshalAndSaveEncrypted(addressBytes, userInfo)
+	if err != nil {
+		return nil, verifyCodeData, err
+	}
+
+	recoveryCodes, err := resolver.generateRecoveryCodesIfMissing(addressBytes, userInfo)
+	if err != nil {
+		return nil, verifyCodeData, err
 	}
 
 	err = resolver.updateGuardianStateIfNeeded(userAddress.AddressBytes(), userInfo, guardianAddr)
 	if err != nil {
-		return verifyCodeData, err
+		return nil, verifyCodeData, err
 	}
 
+	// the user is about to set the verified guardian on chain
+	resolver.httpClientWrapper.InvalidateGuardianData(bech32Addr)
+
 	log.Debug("code ok",
 		"userAddress", bech32Addr,
 		"guardian", request.Guardian)
 
-	return verifyCodeData, nil
+	return recoveryCodes, verifyCodeData, nil
 }
 
 // SignMessage validates user's message, then adds guardian signature and returns the message.
@@ -251,8 +308,13 @@ func (resolver *serviceResolver) SignMessage(userIp string, request requests.Sig
 	if err != nil {
 		return nil, nil, err
 	}
+	var payloadDigest []byte
+	if request.Assertion != nil {
+		payloadDigest = resolver.computeMessageDigest(request.Message)
+	}
+
 	guardian, otpCodeVerifyData, err := resolver.verifyCodesReturningGuardian(userAddress, request.GuardianAddr,
-		userIp, request.Code, request.SecondCode)
+		userIp, request.Code, request.SecondCode, request.Assertion, payloadDigest, false)
 	if err != nil {
 		return nil, otpCodeVerifyData, err
 	}
@@ -280,9 +342,15 @@ func (resolver *serviceResolver) SetSecurityModeNoExpire(userIp string, request
 	return verifyCodeData, resolver.secureOtpHandler.SetSecurityModeNoExpire(request.UserAddr)
 }
 
-// UnsetSecurityModeNoExpire gets the user's guardian, verifies the codes and then unsets the SecurityMode
+// UnsetSecurityModeNoExpire gets the user's guardian, verifies the codes or the recovery code and then unsets the SecurityMode
 func (resolver *serviceResolver) UnsetSecurityModeNoExpire(userIp string, request requests.SecurityModeNoExpire) (*requests.OTPCodeVerifyData, error) {
-	verifyCodeData, err := resolver.checkGuardianAndVerifyCode(userIp, request)
+	var verifyCodeData *requests.OTPCodeVerifyData
+	var err error
+	if len(request.RecoveryCode) > 0 {
+		verifyCodeData, err = resolver.useRecoveryCode(userIp, request.UserAddr, request.RecoveryCode)
+	} else {
+		verifyCodeData, err = resolver.checkGuardianAndVerifyCode(userIp, request)
+	}
 	if err != nil {
 		return verifyCodeData, err
 	}
@@ -291,7 +359,7 @@ func (resolver *serviceResolver) UnsetSecurityModeNoExpire(userIp string, reques
 
 // SignTransaction validates user's transaction, then adds guardian signature and returns the transaction
 func (resolver *serviceResolver) SignTransaction(userIp string, request requests.SignTransaction) ([]byte, *requests.OTPCodeVerifyData, error) {
-	guardian, otpCodeVerifyData, err := resolver.validateTxRequestReturningGuardian(userIp, request.Code, request.SecondCode, []transaction.FrontendTransaction{request.Tx})
+	guardian, isConfirmed, otpCodeVerifyData, err := resolver.validateTxRequestReturningGuardian(userIp, request.Code, request.SecondCode, request.Assertion, []transaction.FrontendTransaction{request.Tx})
 	if err != nil {
 		return nil, otpCodeVerifyData, err
 	}
@@ -307,12 +375,23 @@ func (resolver *serviceResolver) SignTransaction(userIp string, request requests
 	}
 
 	txBytes, err := resolver.txMarshaller.Marshal(&request.Tx)
-	return txBytes, otpCodeVerifyData, err
+	if err != nil {
+		return nil, otpCodeVerifyData, err
+	}
+
+	signedTxs := []transaction.FrontendTransaction{request.Tx}
+	err = resolver.recordSignedTransactions(signedTxs, isConfirmed)
+	if err != nil {
+		return nil, otpCodeVerifyData, err
+	}
+	resolver.invalidateGuardianDataIfChanged(signedTxs)
+
+	return txBytes, otpCodeVerifyData, nil
 }
 
 // SignMultipleTransactions validates user's transactions, then adds guardian signature and returns the transaction
 func (resolver *serviceResolver) SignMultipleTransactions(userIp string, request requests.SignMultipleTransactions) ([][]byte, *requests.OTPCodeVerifyData, error) {
-	guardian, otpCodeVerifyData, err := resolver.validateTxRequestReturningGuardian(userIp, request.Code, request.SecondCode, request.Txs)
+	guardian, isConfirmed, otpCodeVerifyData, err := resolver.validateTxRequestReturningGuardian(userIp, request.Code, request.SecondCode, request.Assertion, request.Txs)
 	if err != nil {
 		return nil, otpCodeVerifyData, err
 	}
@@ -337,9 +416,61 @@ func (resolver *serviceResolver) SignMultipleTransactions(userIp string, request
 		txsSlice = append(txsSlice, txBuff)
 	}
 
+	err = resolver.recordSignedTransactions(request.Txs, isConfirmed)
+	if err != nil {
+		return nil, otpCodeVerifyData, err
+	}
+	resolver.invalidateGuardianDataIfChanged(request.Txs)
+
 	return txsSlice, otpCodeVerifyData, nil
 }
 
+// recordSignedTransactions checks the signed transactions against the spending limits of the user and the daily limits
+// of the transaction policy and records them, under the user lock. The signatures are not returned if the check fails,
+// so concurrent requests of the user cannot exceed the limits together
+func (resolver *serviceResolver) recordSignedTransactions(txs []transaction.FrontendTransaction, isConfirmed bool) error {
+	userAddress, err := sdkData.NewAddressFromBech32String(txs[0].Sender)
+	if err != nil {
+		return err
+	}
+
+	addressBytes := userAddress.AddressBytes()
+	resolver.userCritSection.Lock(string(addressBytes))
+	defer resolver.userCritSection.Unlock(string(addressBytes))
+
+	userInfo, spending, err := resolver.computeSpending(addressBytes, txs)
+	if err != nil {
+		return err
+	}
+
+	err = resolver.txPolicyHandler.CheckAndRecordTransactions(txs[0].Sender, txs, isConfirmed)
+	if err != nil {
+		return err
+	}
+	if spending == nil {
+		return nil
+	}
+
+	return resolver.saveUserSpending(addressBytes, userInfo, spending)
+}
+
+// invalidateGuardianDataIfChanged drops the cached guardian data of the sender if any of the signed transactions
+// changes its guardians or its guarded state
+func (resolver *serviceResolver) invalidateGuardianDataIfChanged(txs []transaction.FrontendTransaction) {
+	for _, tx := range txs {
+		decodedTx, err := resolver.txDecoder.Decode(tx)
+		if err != nil {
+			continue
+		}
+
+		switch decodedTx.Function {
+		case chainCore.BuiltInFunctionSetGuardian, chainCore.BuiltInFunctionGuardAccount, chainCore.BuiltInFunctionUnGuardAccount:
+			resolver.httpClientWrapper.InvalidateGuardianData(tx.Sender)
+			return
+		}
+	}
+}
+
 // RegisteredUsers returns the number of registered users
 func (resolver *serviceResolver) RegisteredUsers() (uint32, error) {
 	return resolver.registeredUsersDB.Count()
@@ -376,14 +507,38 @@ func (resolver *serviceResolver) checkGuardianAndVerifyCode(userIp string, reque
 	}
 
 	addressBytes := userAddress.AddressBytes()
-	resolver.userCritSection.RLock(string(addressBytes))
+	resolver.userCritSection.Lock(string(addressBytes))
+	defer resolver.userCritSection.Unlock(string(addressBytes))
+
 	userInfo, err := resolver.getUserInfo(addressBytes)
-	resolver.userCritSection.RUnlock(string(addressBytes))
 	if err != nil {
 		return nil, err
 	}
 
-	return resolver.checkAllowanceAndVerifyCode(userInfo, request.UserAddr, userIp, request.Code, request.SecondCode, guardianAddrBytes)
+	return resolver.checkAllowanceAndVerifyCode(userInfo, addressBytes, request.UserAddr, userIp, request.Code, request.SecondCode, guardianAddrBytes, false)
+}
+
+func (resolver *serviceResolver) useRecoveryCode(userIp string, bech32Addr string, recoveryCode string) (*requests.OTPCodeVerifyData, error) {
+	userAddress, err := sdkData.NewAddressFromBech32String(bech32Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	addressBytes := userAddress.AddressBytes()
+	resolver.userCritSection.Lock(string(addressBytes))
+	defer resolver.userCritSection.Unlock(string(addressBytes))
+
+	userInfo, err := resolver.getUserInfo(addressBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	verifyCodeData, err := resolver.consumeRecoveryCode(userInfo, addressBytes, bech32Addr, userIp, recoveryCode)
+	if err != nil {
+		return verifyCodeData, err
+	}
+
+	return verifyCodeData, resolver.marshalAndSaveEncrypted(addressBytes, userInfo)
 }
 
 func (resolver *serviceResolver) validateUserAddress(userAddress string) error {
@@ -401,22 +556,35 @@ func (resolver *serviceResolver) validateUserAddress(userAddress string) error {
 	return nil
 }
 
+// verifyCode validates the code and updates the otp params of the guardian, as the accepted counter moves on each use.
+// The caller must save the user info, so that the code cannot be used again
 func (resolver *serviceResolver) verifyCode(userInfo *core.UserInfo, userCode string, guardianAddr []byte) error {
-	otpHandler, err := resolver.getUserOTPHandler(userInfo, guardianAddr)
+	otpInfo, err := extractOtpForGuardian(userInfo, guardianAddr)
 	if err != nil {
 		return err
 	}
 
-	return otpHandler.Validate(userCode)
-}
+	otpHandler, err := resolver.totpHandler.TOTPFromBytes(otpInfo.OTP, otpInfo.Params)
+	if err != nil {
+		return err
+	}
 
-func (resolver *serviceResolver) getUserOTPHandler(userInfo *core.UserInfo, guardianAddr []byte) (handlers.OTP, error) {
-	otpInfo, err := extractOtpForGuardian(userInfo, guardianAddr)
+	err = otpHandler.Validate(userCode)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return resolver.totpHandler.TOTPFromBytes(otpInfo.OTP)
+	newParams := otpHandler.Params()
+	if otpInfo.Params.Type != newParams.Type {
+		// the otps saved before the params became configurable are migrated on their first use
+		otpInfo.OTP, err = otpHandler.ToBytes()
+		if err != nil {
+			return err
+		}
+	}
+	otpInfo.Params = newParams
+
+	return nil
 }
 
 func extractOtpForGuardian(userInfo *core.UserInfo, guardian []byte) (*core.OTPInfo, error) {
@@ -442,7 +610,7 @@ func hasBalance(balance string) bool {
 }
 
 // registerUser tries to register the user, returning the address of a unique guardian and the time of qr generation in case this registration was a subsequent one made too early
-func (resolver *serviceResolver) registerUser(userAddress sdkCore.AddressHandler, otp handlers.OTP) ([]byte, int64, error) {
+func (resolver *serviceResolver) registerUser(userAddress sdkCore.AddressHandler, userIp string, recoveryCode string, otp handlers.OTP) ([]byte, int64, error) {
 	addressBytes := userAddress.AddressBytes()
 
 	resolver.userCritSection.Lock(string(addressBytes))
@@ -450,6 +618,10 @@ func (resolver *serviceResolver) registerUser(userAddress sdkCore.AddressHandler
 
 	userInfo, err := resolver.getUserInfo(addressBytes)
 	if errors.Is(err, storage.ErrKeyNotFound) {
+		if len(recoveryCode) > 0 {
+			return nil, zeroQRAge, ErrInvalidRecoveryCode
+		}
+
 		guardianData, errNewAccount := resolver.handleNewAccount(userAddress, otp)
 		return guardianData, zeroQRAge, errNewAccount
 	}
@@ -457,32 +629,79 @@ func (resolver *serviceResolver) registerUser(userAddress sdkCore.AddressHandler
 		return nil, zeroQRAge, err
 	}
 
-	return resolver.handleRegisteredAccount(userAddress, userInfo, otp)
+	return resolver.handleRegisteredAccount(userAddress, userIp, recoveryCode, userInfo, otp)
 }
 
+// validateTxRequestReturningGuardian validates the transactions and verifies the codes, returning the guardian
+// and whether the request was confirmed, as required by the transaction policy
 func (resolver *serviceResolver) validateTxRequestReturningGuardian(
-	userIp, code string, secondCode string, txs []transaction.FrontendTransaction,
-) (core.GuardianInfo, *requests.OTPCodeVerifyData, error) {
+	userIp, code string, secondCode string, assertion *requests.WebAuthnAssertion, txs []transaction.FrontendTransaction,
+) (core.GuardianInfo, bool, *requests.OTPCodeVerifyData, error) {
+	userAddress, confirmationRequired, err := resolver.checkTransactionsReturningUser(txs)
+	if err != nil {
+		return core.GuardianInfo{}, false, nil, err
+	}
+
+	var payloadDigest []byte
+	if assertion != nil {
+		payloadDigest, err = resolver.computeTransactionsDigest(txs)
+		if err != nil {
+			return core.GuardianInfo{}, false, nil, err
+		}
+	}
+
+	guardian, otpCodeVerifyData, err := resolver.verifyCodesReturningGuardian(userAddress, txs[0].GuardianAddr, userIp, code, secondCode, assertion, payloadDigest, confirmationRequired)
+	if err != nil {
+		return core.GuardianInfo{}, false, otpCodeVerifyData, err
+	}
+
+	return guardian, confirmationRequired, otpCodeVerifyData, nil
+}
+
+// checkTransactionsReturningUser validates the transactions and checks them against the transaction policy and the
+// spending limits of the user, returning the user sending them and whether a confirmation code is required. Nothing is recorded
+func (resolver *serviceResolver) checkTransactionsReturningUser(txs []transaction.FrontendTransaction) (sdkCore.AddressHandler, bool, error) {
+	userAddress, confirmationRequired, err := resolver.checkTransactionsPolicy(txs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = resolver.checkSpending(userAddress.AddressBytes(), txs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return userAddress, confirmationRequired, nil
+}
+
+// checkTransactionsPolicy validates the transactions and checks them against the transaction policy, returning the
+// user sending them and whether a confirmation code is required. Nothing is recorded
+func (resolver *serviceResolver) checkTransactionsPolicy(txs []transaction.FrontendTransaction) (sdkCore.AddressHandler, bool, error) {
 	if len(txs) > resolver.config.MaxTransactionsAllowedForSigning {
-		return core.GuardianInfo{}, nil, fmt.Errorf("%w, got %d, max allowed %d",
+		return nil, false, fmt.Errorf("%w, got %d, max allowed %d",
 			ErrTooManyTransactionsToSign, len(txs), resolver.config.MaxTransactionsAllowedForSigning)
 	}
 
 	if len(txs) == 0 {
-		return core.GuardianInfo{}, nil, ErrNoTransactionToSign
+		return nil, false, ErrNoTransactionToSign
 	}
 
 	userAddress, err := sdkData.NewAddressFromBech32String(txs[0].Sender)
 	if err != nil {
-		return core.GuardianInfo{}, nil, err
+		return nil, false, err
 	}
 
 	err = resolver.validateTransactions(txs, userAddress)
 	if err != nil {
-		return core.GuardianInfo{}, nil, err
+		return nil, false, err
 	}
 
-	return resolver.verifyCodesReturningGuardian(userAddress, txs[0].GuardianAddr, userIp, code, secondCode)
+	confirmationRequired, err := resolver.txPolicyHandler.CheckTransactions(txs[0].Sender, txs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return userAddress, confirmationRequired, nil
 }
 
 func (resolver *serviceResolver) verifyCodesReturningGuardian(
@@ -491,39 +710,48 @@ func (resolver *serviceResolver) verifyCodesReturningGuardian(
 	userIp,
 	code,
 	secondCode string,
+	assertion *requests.WebAuthnAssertion,
+	payloadDigest []byte,
+	confirmationRequired bool,
 ) (core.GuardianInfo, *requests.OTPCodeVerifyData, error) {
 	guardianAddrBytes, err := resolver.pubKeyConverter.Decode(guardianAddr)
 	if err != nil {
 		return core.GuardianInfo{}, nil, err
 	}
 
-	addressBytes := userAddress.AddressBytes()
-	resolver.userCritSection.RLock(string(addressBytes))
-	userInfo, err := resolver.getUserInfo(addressBytes)
-	resolver.userCritSection.RUnlock(string(addressBytes))
-
+	bech32Addr, err := userAddress.AddressAsBech32String()
 	if err != nil {
 		return core.GuardianInfo{}, nil, err
 	}
 
-	bech32Addr, err := userAddress.AddressAsBech32String()
+	addressBytes := userAddress.AddressBytes()
+	if assertion != nil {
+		return resolver.verifyAssertionReturningGuardian(addressBytes, bech32Addr, userIp, guardianAddr, guardianAddrBytes, payloadDigest, code, *assertion, confirmationRequired)
+	}
+
+	resolver.userCritSection.Lock(string(addressBytes))
+	defer resolver.userCritSection.Unlock(string(addressBytes))
+
+	userInfo, err := resolver.getUserInfo(addressBytes)
 	if err != nil {
 		return core.GuardianInfo{}, nil, err
 	}
 
 	otpVerifyCodeData, err := resolver.checkAllowanceAndVerifyCode(
 		userInfo,
+		addressBytes,
 		bech32Addr,
 		userIp,
 		code,
 		secondCode,
 		guardianAddrBytes,
+		confirmationRequired,
 	)
 	if err != nil {
 		return core.GuardianInfo{}, otpVerifyCodeData, err
 	}
 
-	guardianInfo, err := resolver.getGuardianInfoFromAddress(guardianAddr, userInfo)
+	guardianInfo, err := resolver.getGuardianInfoFromAddress(addressBytes, guardianAddr, userInfo)
 	if err != nil {
 		return core.GuardianInfo{}, otpVerifyCodeData, err
 	}
@@ -538,13 +766,18 @@ func (resolver *serviceResolver) extendSecurityMode(verifyCodeData *requests.OTP
 	}
 }
 
+// checkAllowanceAndVerifyCode verifies the codes of the request. The codes are checked against the same starting
+// counter, so they can be sent in any order, and the moved counter is saved as soon as a code is accepted, so that
+// the code cannot be replayed even if the rest of the request fails. The user lock must be held by the caller
 func (resolver *serviceResolver) checkAllowanceAndVerifyCode(
 	userInfo *core.UserInfo,
+	addressBytes []byte,
 	userAddress string,
 	userIp string,
 	code string,
 	secondCode string,
 	guardianAddr []byte,
+	confirmationRequired bool,
 ) (*requests.OTPCodeVerifyData, error) {
 	verifyCodeData, err := resolver.secureOtpHandler.IsVerificationAllowedAndIncreaseTrials(userAddress, userIp)
 	if err != nil {
@@ -552,11 +785,26 @@ func (resolver *serviceResolver) checkAllowanceAndVerifyCode(
 		return verifyCodeData, err
 	}
 
+	otpInfo, err := extractOtpForGuardian(userInfo, guardianAddr)
+	if err != nil {
+		resolver.extendSecurityMode(verifyCodeData, userAddress)
+		return verifyCodeData, err
+	}
+	startCounter := otpInfo.Params.Counter
+
 	err = resolver.verifyCode(userInfo, code, guardianAddr)
 	if err != nil {
 		resolver.extendSecurityMode(verifyCodeData, userAddress)
 		return verifyCodeData, err
 	}
+
+	// the trial is consumed on a failed confirmation, so the second code cannot be brute-forced with a single valid code
+	if confirmationRequired {
+		err = resolver.verifyConfirmationCode(userInfo, code, secondCode, guardianAddr, startCounter)
+		if err != nil {
+			return verifyCodeData, resolver.saveAcceptedCodes(addressBytes, userInfo, err)
+		}
+	}
 	resolver.secureOtpHandler.Reset(userAddress, userIp)
 
 	securityModeExtended, err := resolver.verifySecurityModeCode(
@@ -565,6 +813,7 @@ func (resolver *serviceResolver) checkAllowanceAndVerifyCode(
 		code,
 		secondCode,
 		guardianAddr,
+		startCounter,
 		verifyCodeData.SecurityModeRemainingTrials)
 	remainingSecurityTrials := verifyCodeData.SecurityModeRemainingTrials
 	if err != nil {
@@ -583,7 +832,21 @@ func (resolver *serviceResolver) checkAllowanceAndVerifyCode(
 		ResetAfter:                  0,
 		SecurityModeRemainingTrials: remainingSecurityTrials, // decrementing failed trials increases remaining trials
 		SecurityModeResetAfter:      securityModeResetAfter,
-	}, err
+	}, resolver.saveAcceptedCodes(addressBytes, userInfo, err)
+}
+
+// saveAcceptedCodes saves the user info holding the counters moved by the accepted codes. The verification error,
+// if any, is returned instead of the saving one
+func (resolver *serviceResolver) saveAcceptedCodes(addressBytes []byte, userInfo *core.UserInfo, verifyErr error) error {
+	errSave := resolver.marshalAndSaveEncrypted(addressBytes, userInfo)
+	if verifyErr == nil {
+		return errSave
+	}
+	if errSave != nil {
+		log.Warn("failed to save the accepted codes", "error", errSave.Error())
+	}
+
+	return verifyErr
 }
 
 func (resolver *serviceResolver) verifySecurityModeCode(
@@ -592,6 +855,7 @@ func (resolver *serviceResolver) verifySecurityModeCode(
 	firstCode string,
 	secondCode string,
 	guardianAddr []byte,
+	startCounter uint64,
 	securityModeRemainingTrials int,
 ) (bool, error) {
 	if securityModeRemainingTrials <= 0 {
@@ -611,7 +875,7 @@ func (resolver *serviceResolver) verifySecurityModeCode(
 			return securityModeExtended, fmt.Errorf("%w with codeError %s, security mode %s", ErrSecondCodeInvalidInSecurityMode, ErrSameCode, securityModeExtendedStr)
 		}
 
-		err := resolver.verifyCode(userInfo, secondCode, guardianAddr)
+		err := resolver.verifySecondCode(userInfo, secondCode, guardianAddr, startCounter)
 		if err != nil {
 			// if the second code is not correct, extend the ttl for security mode
 			errExtendSecurityMode := resolver.secureOtpHandler.ExtendSecurityMode(userAddress)
@@ -634,17 +898,53 @@ func (resolver *serviceResolver) verifySecurityModeCode(
 	return false, nil
 }
 
+// verifyConfirmationCode verifies the second code of a request which requires a confirmation
+func (resolver *serviceResolver) verifyConfirmationCode(userInfo *core.UserInfo, firstCode string, secondCode string, guardianAddr []byte, startCounter uint64) error {
+	if len(secondCode) == 0 {
+		return handlers.ErrTxPolicyConfirmationRequired
+	}
+	if secondCode == firstCode {
+		return fmt.Errorf("%w with codeError %s", handlers.ErrTxPolicyConfirmationRequired, ErrSameCode)
+	}
+
+	err := resolver.verifySecondCode(userInfo, secondCode, guardianAddr, startCounter)
+	if err != nil {
+		return fmt.Errorf("%w with codeError %s", handlers.ErrTxPolicyConfirmationRequired, err)
+	}
+
+	return nil
+}
+
+// verifySecondCode verifies the second code of a request against the counter the request started from, as the
+// first code already moved it, and keeps the highest accepted counter. The caller must check that the codes differ
+func (resolver *serviceResolver) verifySecondCode(userInfo *core.UserInfo, secondCode string, guardianAddr []byte, startCounter uint64) error {
+	otpInfo, err := extractOtpForGuardian(userInfo, guardianAddr)
+	if err != nil {
+		return err
+	}
+
+	firstCodeCounter := otpInfo.Params.Counter
+	otpInfo.Params.Counter = startCounter
+	err = resolver.verifyCode(userInfo, secondCode, guardianAddr)
+	if otpInfo.Params.Counter < firstCodeCounter {
+		otpInfo.Params.Counter = firstCodeCounter
+	}
+
+	return err
+}
+
+// updateGuardianStateIfNeeded marks the guardian as verified. It becomes usable only once it is seen active on chain
 func (resolver *serviceResolver) updateGuardianStateIfNeeded(userAddress []byte, userInfo *core.UserInfo, guardianAddress []byte) error {
 	userInfoCopy := *userInfo
 	if bytes.Equal(guardianAddress, userInfoCopy.FirstGuardian.PublicKey) {
-		if userInfoCopy.FirstGuardian.State == core.NotUsable {
-			userInfoCopy.FirstGuardian.State = core.Usable
+		if !isGuardianVerified(userInfoCopy.FirstGuardian) {
+			resolver.markGuardianVerified(&userInfoCopy.FirstGuardian)
 			return resolver.marshalAndSaveEncrypted(userAddress, &userInfoCopy)
 		}
 	}
 	if bytes.Equal(guardianAddress, userInfoCopy.SecondGuardian.PublicKey) {
-		if userInfoCopy.SecondGuardian.State == core.NotUsable {
-			userInfoCopy.SecondGuardian.State = core.Usable
+		if !isGuardianVerified(userInfoCopy.SecondGuardian) {
+			resolver.markGuardianVerified(&userInfoCopy.SecondGuardian)
 			return resolver.marshalAndSaveEncrypted(userAddress, &userInfoCopy)
 		}
 	}
@@ -652,6 +952,29 @@ func (resolver *serviceResolver) updateGuardianStateIfNeeded(userAddress []byte,
 	return nil
 }
 
+func (resolver *serviceResolver) markGuardianVerified(guardian *core.GuardianInfo) {
+	guardian.ActivationState = core.Verified
+	guardian.VerifiedTimestamp = resolver.getTimeHandler().Unix()
+}
+
+// isGuardianVerified returns true if the otp of the guardian was verified and the guardian was not replaced on chain
+// meanwhile. The guardians verified before the activation was tracked are only known by their usable state
+func isGuardianVerified(guardian core.GuardianInfo) bool {
+	switch guardian.ActivationState {
+	case core.Verified, core.PendingOnChain, core.Active:
+		return true
+	default:
+		return guardian.State == core.Usable
+	}
+}
+
+// resetGuardian marks the guardian as not usable, its otp having to be verified again
+func resetGuardian(guardian *core.GuardianInfo) {
+	guardian.State = core.NotUsable
+	guardian.ActivationState = core.Untracked
+	guardian.VerifiedTimestamp = 0
+}
+
 func (resolver *serviceResolver) validateTransactions(txs []transaction.FrontendTransaction, userAddress sdkCore.AddressHandler) error {
 	expectedGuardian := txs[0].GuardianAddr
 	for _, tx := range txs {
@@ -701,35 +1024,52 @@ func (resolver *serviceResolver) validateOneTransaction(tx transaction.FrontendT
 	)
 }
 
-func (resolver *serviceResolver) getGuardianInfoFromAddress(guardianAddr string, userInfo *core.UserInfo) (core.GuardianInfo, error) {
-	guardianForTx := core.GuardianInfo{}
-	unknownGuardian := true
-	firstGuardianAddr, err := resolver.pubKeyConverter.Encode(userInfo.FirstGuardian.PublicKey)
+// getGuardianInfoFromAddress returns the provided guardian of the user, if usable. A verified guardian which is
+// not usable yet is checked on chain first, as it might have been activated since the last check. It should be
+// called under the user lock
+func (resolver *serviceResolver) getGuardianInfoFromAddress(userAddress []byte, guardianAddr string, userInfo *core.UserInfo) (core.GuardianInfo, error) {
+	guardianForTx, err := resolver.getUserGuardian(guardianAddr, userInfo)
 	if err != nil {
 		return core.GuardianInfo{}, err
 	}
+
+	if guardianForTx.State == core.NotUsable && guardianForTx.ActivationState != core.Untracked {
+		err = resolver.reconcileGuardians(userAddress, userInfo)
+		if err != nil {
+			return core.GuardianInfo{}, err
+		}
+	}
+
+	if guardianForTx.State == core.NotUsable {
+		return core.GuardianInfo{}, fmt.Errorf("%w, guardian %s", ErrGuardianNotUsable, guardianAddr)
+	}
+
+	return *guardianForTx, nil
+}
+
+// getUserGuardian returns the guardian of the user with the provided address, regardless of its state
+func (resolver *serviceResolver) getUserGuardian(guardianAddr string, userInfo *core.UserInfo) (*core.GuardianInfo, error) {
+	var guardian *core.GuardianInfo
+	firstGuardianAddr, err := resolver.pubKeyConverter.Encode(userInfo.FirstGuardian.PublicKey)
+	if err != nil {
+		return nil, err
+	}
 	if guardianAddr == firstGuardianAddr {
-		guardianForTx = userInfo.FirstGuardian
-		unknownGuardian = false
+		guardian = &userInfo.FirstGuardian
 	}
 	secondGuardianAddr, err := resolver.pubKeyConverter.Encode(userInfo.SecondGuardian.PublicKey)
 	if err != nil {
-		return core.GuardianInfo{}, err
+		return nil, err
 	}
 	if guardianAddr == secondGuardianAddr {
-		guardianForTx = userInfo.SecondGuardian
-		unknownGuardian = false
+		guardian = &userInfo.SecondGuardian
 	}
 
-	if unknownGuardian {
-		return core.GuardianInfo{}, fmt.Errorf("%w, guardian %s", ErrInvalidGuardian, guardianAddr)
+	if guardian == nil {
+		return nil, fmt.Errorf("%w, guardian %s", ErrInvalidGuardian, guardianAddr)
 	}
 
-	if guardianForTx.State == core.NotUsable {
-		return core.GuardianInfo{}, fmt.Errorf("%w, guardian %s", ErrGuardianNotUsable, guardianAddr)
-	}
-
-	return guardianForTx, nil
+	return guardian, nil
 }
 
 func (resolver *serviceResolver) handleNewAccount(userAddress sdkCore.AddressHandler, otp handlers.OTP) ([]byte, error) {
@@ -767,17 +1107,33 @@ func (resolver *serviceResolver) handleNewAccount(userAddress sdkCore.AddressHan
 	return userInfo.FirstGuardian.PublicKey, nil
 }
 
-func (resolver *serviceResolver) handleRegisteredAccount(userAddress sdkCore.AddressHandler, userInfo *core.UserInfo, otp handlers.OTP) ([]byte, int64, error) {
+func (resolver *serviceResolver) handleRegisteredAccount(
+	userAddress sdkCore.AddressHandler,
+	userIp string,
+	recoveryCode string,
+	userInfo *core.UserInfo,
+	otp handlers.OTP,
+) ([]byte, int64, error) {
 	bech32Addr, err := userAddress.AddressAsBech32String()
 	if err != nil {
 		return nil, zeroQRAge, err
 	}
+
+	// a valid recovery code allows the user to replace a lost OTP right away
+	skipOTPWriteDelay := len(recoveryCode) > 0
+	if skipOTPWriteDelay {
+		_, err = resolver.consumeRecoveryCode(userInfo, userAddress.AddressBytes(), bech32Addr, userIp, recoveryCode)
+		if err != nil {
+			return nil, zeroQRAge, err
+		}
+	}
+
 	nextGuardian, err := resolver.getNextGuardianAddress(bech32Addr, userInfo)
 	if err != nil {
 		return nil, zeroQRAge, err
 	}
 
-	otpAge, err := resolver.saveOTPForUserGuardian(userAddress, userInfo, otp, nextGuardian)
+	otpAge, err := resolver.saveOTPForUserGuardian(userAddress, userInfo, otp, nextGuardian, skipOTPWriteDelay)
 	if err != nil {
 		return nil, otpAge, err
 	}
@@ -786,14 +1142,14 @@ func (resolver *serviceResolver) handleRegisteredAccount(userAddress sdkCore.Add
 }
 
 func (resolver *serviceResolver) getNextGuardianAddress(userAddress string, userInfo *core.UserInfo) ([]byte, error) {
-	if userInfo.FirstGuardian.State == core.NotUsable {
+	if !isGuardianVerified(userInfo.FirstGuardian) {
 		log.Debug("registering old user",
 			"userAddress", userAddress,
 			"newGuardian", resolver.pubKeyConverter.SilentEncode(userInfo.FirstGuardian.PublicKey, log))
 		return userInfo.FirstGuardian.PublicKey, nil
 	}
 
-	if userInfo.SecondGuardian.State == core.NotUsable {
+	if !isGuardianVerified(userInfo.SecondGuardian) {
 		log.Debug("registering old user",
 			"userAddress", userAddress,
 			"newGuardian", resolver.pubKeyConverter.SilentEncode(userInfo.SecondGuardian.PublicKey, log))
@@ -823,8 +1179,8 @@ func (resolver *serviceResolver) getNextGuardianAddress(userAddress string, user
 	return nextGuardian, nil
 }
 
-func (resolver *serviceResolver) saveOTPForUserGuardian(userAddress sdkCore.AddressHandler, userInfo *core.UserInfo, otp handlers.OTP, guardian []byte) (int64, error) {
-	otpAge, err := resolver.addOTPToUserGuardian(userInfo, guardian, otp)
+func (resolver *serviceResolver) saveOTPForUserGuardian(userAddress sdkCore.AddressHandler, userInfo *core.UserInfo, otp handlers.OTP, guardian []byte, skipOTPWriteDelay bool) (int64, error) {
+	otpAge, err := resolver.addOTPToUserGuardian(userInfo, guardian, otp, skipOTPWriteDelay)
 	if err != nil {
 		return otpAge, err
 	}
@@ -833,7 +1189,7 @@ func (resolver *serviceResolver) saveOTPForUserGuardian(userAddress sdkCore.Addr
 	return otpAge, resolver.marshalAndSaveEncrypted(addressBytes, userInfo)
 }
 
-func (resolver *serviceResolver) addOTPToUserGuardian(userInfo *core.UserInfo, guardian []byte, otp handlers.OTP) (int64, error) {
+func (resolver *serviceResolver) addOTPToUserGuardian(userInfo *core.UserInfo, guardian []byte, otp handlers.OTP, skipOTPWriteDelay bool) (int64, error) {
 	if userInfo == nil {
 		return zeroQRAge, ErrNilUserInfo
 	}
@@ -856,7 +1212,7 @@ func (resolver *serviceResolver) addOTPToUserGuardian(userInfo *core.UserInfo, g
 	oldOTPInfo := &selectedGuardianInfo.OTPData
 	otpAge := currentTimestamp - oldOTPInfo.LastTOTPChangeTimestamp
 	nextAllowedOTPChangeTimestamp := oldOTPInfo.LastTOTPChangeTimestamp + int64(resolver.config.DelayBetweenOTPWritesInSec)
-	allowedToChangeOTP := nextAllowedOTPChangeTimestamp < currentTimestamp
+	allowedToChangeOTP := nextAllowedOTPChangeTimestamp < currentTimestamp || skipOTPWriteDelay
 	if !allowedToChangeOTP {
 		return otpAge, fmt.Errorf("%w, last update was %d seconds ago, retry in %d seconds",
 			handlers.ErrRegistrationFailed,
@@ -872,6 +1228,7 @@ func (resolver *serviceResolver) addOTPToUserGuardian(userInfo *core.UserInfo, g
 
 	selectedGuardianInfo.OTPData.OTP = otpBytes
 	selectedGuardianInfo.OTPData.LastTOTPChangeTimestamp = currentTimestamp
+	selectedGuardianInfo.OTPData.Params = otp.Params()
 
 	return zeroQRAge, nil
 }
@@ -882,11 +1239,11 @@ func (resolver *serviceResolver) getUserInfo(userAddress []byte) (*core.UserInfo
 		return nil, err
 	}
 
-	return resolver.unmarshalAndDecryptUserInfo(encryptedDataMarshalled)
+	return resolver.unmarshalAndDecryptUserInfo(userAddress, encryptedDataMarshalled)
 }
 
-func (resolver *serviceResolver) encryptAndMarshalUserInfo(userInfo *core.UserInfo) ([]byte, error) {
-	encryptedUserInfo, err := resolver.userEncryptor.EncryptUserInfo(userInfo)
+func (resolver *serviceResolver) encryptAndMarshalUserInfo(userAddress []byte, userInfo *core.UserInfo) ([]byte, error) {
+	encryptedUserInfo, err := resolver.userEncryptor.EncryptUserInfo(userAddress, userInfo)
 	if err != nil {
 		return nil, err
 	}
@@ -894,14 +1251,14 @@ func (resolver *serviceResolver) encryptAndMarshalUserInfo(userInfo *core.UserIn
 	return resolver.userDataMarshaller.Marshal(encryptedUserInfo)
 }
 
-func (resolver *serviceResolver) unmarshalAndDecryptUserInfo(encryptedDataMarshalled []byte) (*core.UserInfo, error) {
+func (resolver *serviceResolver) unmarshalAndDecryptUserInfo(userAddress []byte, encryptedDataMarshalled []byte) (*core.UserInfo, error) {
 	userInfo := &core.UserInfo{}
 	err := resolver.userDataMarshaller.Unmarshal(userInfo, encryptedDataMarshalled)
 	if err != nil {
 		return nil, err
 	}
 
-	return resolver.userEncryptor.DecryptUserInfo(userInfo)
+	return resolver.userEncryptor.DecryptUserInfo(userAddress, userInfo)
 }
 
 func (resolver *serviceResolver) computeNewUserDataAndSave(index uint32, userAddress []byte, privateKeys []crypto.PrivateKey, otp handlers.OTP) (*core.UserInfo, error) {
@@ -915,6 +1272,7 @@ func (resolver *serviceResolver) computeNewUserDataAndSave(index uint32, userAdd
 		return nil, err
 	}
 	firstGuardian.OTPData.LastTOTPChangeTimestamp = time.Now().Unix()
+	firstGuardian.OTPData.Params = otp.Params()
 
 	secondGuardian, err := getGuardianInfoForKey(privateKeys[1])
 	if err != nil {
@@ -936,7 +1294,7 @@ func (resolver *serviceResolver) computeNewUserDataAndSave(index uint32, userAdd
 }
 
 func (resolver *serviceResolver) marshalAndSaveEncrypted(userAddress []byte, userInfo *core.UserInfo) error {
-	encryptedDataBytes, err := resolver.encryptAndMarshalUserInfo(userInfo)
+	encryptedDataBytes, err := resolver.encryptAndMarshalUserInfo(userAddress, userInfo)
 	if err != nil {
 		return err
 	}
@@ -955,27 +1313,27 @@ func (resolver *serviceResolver) prepareNextGuardian(guardianData *api.GuardianD
 	isFirstOnChain := firstGuardianOnChainState != core.MissingGuardian
 	isSecondOnChain := secondGuardianOnChainState != core.MissingGuardian
 	if !isFirstOnChain && !isSecondOnChain {
-		userInfo.FirstGuardian.State = core.NotUsable
-		userInfo.SecondGuardian.State = core.NotUsable
+		resetGuardian(&userInfo.FirstGuardian)
+		resetGuardian(&userInfo.SecondGuardian)
 		return userInfo.FirstGuardian.PublicKey
 	}
 
 	if isFirstOnChain && isSecondOnChain {
 		if firstGuardianOnChainState == core.PendingGuardian {
-			userInfo.FirstGuardian.State = core.NotUsable
+			resetGuardian(&userInfo.FirstGuardian)
 			return userInfo.FirstGuardian.PublicKey
 		}
 
-		userInfo.SecondGuardian.State = core.NotUsable
+		resetGuardian(&userInfo.SecondGuardian)
 		return userInfo.SecondGuardian.PublicKey
 	}
 
 	if isFirstOnChain {
-		userInfo.SecondGuardian.State = core.NotUsable
+		resetGuardian(&userInfo.SecondGuardian)
 		return userInfo.SecondGuardian.PublicKey
 	}
 
-	userInfo.FirstGuardian.State = core.NotUsable
+	resetGuardian(&userInfo.FirstGuardian)
 	return userInfo.FirstGuardian.PublicKey
 }
 
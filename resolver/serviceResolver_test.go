@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/btcsuite/btcd/btcutil/bech32"
+	chainCore "github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-core-go/data/api"
 	"github.com/multiversx/mx-chain-core-go/data/mock"
@@ -26,9 +27,13 @@ import (
 	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/txdecoder"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/secureOtp"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/twofactor"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/twofactor/rfc"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/twofactor/sec51"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
 )
 
@@ -58,6 +63,13 @@ var (
 			},
 		},
 	}
+	providedOTPParams = core.OTPParams{
+		Type:      core.TOTPType,
+		Algorithm: "SHA1",
+		Digits:    6,
+		Period:    30,
+		Skew:      1,
+	}
 	testKeygen      = signing.NewKeyGenerator(ed25519.NewEd25519())
 	testSk, _       = testKeygen.GeneratePair()
 	providedOTPInfo = &requests.OTP{
@@ -79,11 +91,11 @@ var (
 func createMockArgs() ArgServiceResolver {
 	return ArgServiceResolver{
 		UserEncryptor: &testscommon.UserEncryptorStub{
-			EncryptUserInfoCalled: func(user *core.UserInfo) (*core.UserInfo, error) {
+			EncryptUserInfoCalled: func(userAddress []byte, user *core.UserInfo) (*core.UserInfo, error) {
 				userCopy := *user
 				return &userCopy, nil
 			},
-			DecryptUserInfoCalled: func(encryptedUserInfo *core.UserInfo) (*core.UserInfo, error) {
+			DecryptUserInfoCalled: func(userAddress []byte, encryptedUserInfo *core.UserInfo) (*core.UserInfo, error) {
 				encryptedUserInfoCopy := *encryptedUserInfo
 				return &encryptedUserInfoCopy, nil
 			},
@@ -96,7 +108,7 @@ func createMockArgs() ArgServiceResolver {
 					},
 				}, nil
 			},
-			TOTPFromBytesCalled: func(encryptedMessage []byte) (handlers.OTP, error) {
+			TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
 				return &testscommon.TotpStub{
 					UrlCalled: func() (string, error) {
 						return providedUrl, nil
@@ -114,6 +126,11 @@ func createMockArgs() ArgServiceResolver {
 				}, nil
 			},
 		},
+		TxPolicyHandler: &testscommon.TxPolicyHandlerStub{},
+		TxDecoder:       &testscommon.TxDecoderStub{},
+		WebAuthnHandler: &testscommon.WebAuthnHandlerStub{},
+		AuditSink:       &testscommon.AuditSinkStub{},
+		Notifier:        &testscommon.NotifierStub{},
 		HttpClientWrapper: &testscommon.HttpClientWrapperStub{
 			GetGuardianDataCalled: func(ctx context.Context, address string) (*api.GuardianData, error) {
 				return &api.GuardianData{
@@ -127,7 +144,7 @@ func createMockArgs() ArgServiceResolver {
 			},
 		},
 		KeysGenerator: &testscommon.KeysGeneratorStub{
-			GenerateManagedKeyCalled: func() (crypto.PrivateKey, error) {
+			GenerateManagedKeyCalled: func(keyID uint32) (crypto.PrivateKey, error) {
 				return testSk, nil
 			},
 			GenerateKeysCalled: func(index uint32) ([]crypto.PrivateKey, error) {
@@ -236,6 +253,24 @@ func TestNewServiceResolver(t *testing.T) {
 		assert.True(t, errors.Is(err, ErrNilDB))
 		assert.Nil(t, resolver)
 	})
+	t.Run("nil AuditSink should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.AuditSink = nil
+		resolver, err := NewServiceResolver(args)
+		assert.Equal(t, ErrNilAuditSink, err)
+		assert.Nil(t, resolver)
+	})
+	t.Run("nil Notifier should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.Notifier = nil
+		resolver, err := NewServiceResolver(args)
+		assert.Equal(t, ErrNilNotifier, err)
+		assert.Nil(t, resolver)
+	})
 	t.Run("nil totp should error", func(t *testing.T) {
 		t.Parallel()
 
@@ -254,6 +289,33 @@ func TestNewServiceResolver(t *testing.T) {
 		assert.Equal(t, ErrNilSecureOtpHandler, err)
 		assert.Nil(t, resolver)
 	})
+	t.Run("nil TxPolicyHandler should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.TxPolicyHandler = nil
+		resolver, err := NewServiceResolver(args)
+		assert.Equal(t, ErrNilTxPolicyHandler, err)
+		assert.Nil(t, resolver)
+	})
+	t.Run("nil WebAuthnHandler should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.WebAuthnHandler = nil
+		resolver, err := NewServiceResolver(args)
+		assert.Equal(t, ErrNilWebAuthnHandler, err)
+		assert.Nil(t, resolver)
+	})
+	t.Run("nil TxDecoder should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.TxDecoder = nil
+		resolver, err := NewServiceResolver(args)
+		assert.Equal(t, ErrNilTxDecoder, err)
+		assert.Nil(t, resolver)
+	})
 	t.Run("nil userDataMarshaller should error", func(t *testing.T) {
 		t.Parallel()
 
@@ -552,7 +614,7 @@ func TestServiceResolver_GetGuardianAddress(t *testing.T) {
 
 		cnt := 0
 		args.UserEncryptor = &testscommon.UserEncryptorStub{
-			EncryptUserInfoCalled: func(userInfo *core.UserInfo) (*core.UserInfo, error) {
+			EncryptUserInfoCalled: func(userAddress []byte, userInfo *core.UserInfo) (*core.UserInfo, error) {
 				if cnt == 0 {
 					cnt++
 					return userInfo, nil
@@ -616,13 +678,13 @@ func TestServiceResolver_GetGuardianAddress(t *testing.T) {
 			},
 		}
 		args.UserEncryptor = &testscommon.UserEncryptorStub{
-			DecryptUserInfoCalled: func(encryptedUserInfo *core.UserInfo) (*core.UserInfo, error) {
+			DecryptUserInfoCalled: func(userAddress []byte, encryptedUserInfo *core.UserInfo) (*core.UserInfo, error) {
 				return nil, expectedErr
 			},
 		}
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -639,7 +701,7 @@ func TestServiceResolver_GetGuardianAddress(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -662,7 +724,7 @@ func TestServiceResolver_GetGuardianAddress(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -684,7 +746,7 @@ func TestServiceResolver_GetGuardianAddress(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -705,7 +767,7 @@ func TestServiceResolver_GetGuardianAddress(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -726,7 +788,7 @@ func TestServiceResolver_GetGuardianAddress(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -755,7 +817,7 @@ func TestServiceResolver_GetGuardianAddress(t *testing.T) {
 
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -783,7 +845,7 @@ func TestServiceResolver_GetGuardianAddress(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -809,7 +871,7 @@ func TestServiceResolver_GetGuardianAddress(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -835,7 +897,7 @@ func TestServiceResolver_GetGuardianAddress(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -980,13 +1042,13 @@ func TestServiceResolver_RegisterUser(t *testing.T) {
 
 		args := createMockArgs()
 		args.UserEncryptor = &testscommon.UserEncryptorStub{
-			DecryptUserInfoCalled: func(encryptedUserInfo *core.UserInfo) (*core.UserInfo, error) {
+			DecryptUserInfoCalled: func(userAddress []byte, encryptedUserInfo *core.UserInfo) (*core.UserInfo, error) {
 				return nil, expectedErr
 			},
 		}
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -1003,7 +1065,7 @@ func TestServiceResolver_RegisterUser(t *testing.T) {
 		providedUserInfoCopy.FirstGuardian.State = core.NotUsable
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -1046,13 +1108,32 @@ func TestServiceResolver_RegisterUser(t *testing.T) {
 					UrlCalled: func() (string, error) {
 						return providedUrl, nil
 					},
+					ParamsCalled: func() core.OTPParams {
+						return providedOTPParams
+					},
 				}, nil
 			},
 		}
+		events := make([]core.SecurityEvent, 0)
+		args.Notifier = &testscommon.NotifierStub{
+			NotifyCalled: func(event core.SecurityEvent) {
+				events = append(events, event)
+			},
+		}
 		args.Config.DelayBetweenOTPWritesInSec = 2
 		expectedOTPInfo := *providedOTPInfo
 		expectedOTPInfo.TimeSinceGeneration = 0
 		checkRegisterUserResults(t, args, addr, req, nil, &expectedOTPInfo, string(providedUserInfo.FirstGuardian.PublicKey))
+		require.Equal(t, 1, len(events))
+		assert.Equal(t, core.GuardianRegisteredEvent, events[0].Type)
+		assert.Equal(t, string(providedUserInfo.FirstGuardian.PublicKey), events[0].Guardian)
+
+		encryptedUser, err := args.RegisteredUsersDB.Get(addr.AddressBytes())
+		require.Nil(t, err)
+		savedUserInfo := &core.UserInfo{}
+		err = args.UserDataMarshaller.Unmarshal(savedUserInfo, encryptedUser)
+		require.Nil(t, err)
+		assert.Equal(t, providedOTPParams, savedUserInfo.FirstGuardian.OTPData.Params)
 
 		// register again should fail, too early
 		time.Sleep(time.Second)
@@ -1060,10 +1141,12 @@ func TestServiceResolver_RegisterUser(t *testing.T) {
 			TimeSinceGeneration: 1,
 		}
 		checkRegisterUserResults(t, args, addr, req, handlers.ErrRegistrationFailed, expectedFailureOTPInfo, "")
+		require.Equal(t, 1, len(events))
 
 		// wait until next otp generation allowed
 		time.Sleep(time.Duration(args.Config.DelayBetweenOTPWritesInSec) * time.Second)
 		checkRegisterUserResults(t, args, addr, req, nil, &expectedOTPInfo, string(providedUserInfo.FirstGuardian.PublicKey))
+		require.Equal(t, 2, len(events))
 	})
 	t.Run("registerUser returns error", func(t *testing.T) {
 		t.Parallel()
@@ -1103,7 +1186,7 @@ func TestServiceResolver_RegisterUser(t *testing.T) {
 		providedUserInfoCopy.SecondGuardian.State = core.NotUsable
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -1132,7 +1215,7 @@ func TestServiceResolver_RegisterUser(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -1175,7 +1258,7 @@ func TestServiceResolver_verifySecurityModeCode(t *testing.T) {
 	wrongCode := "wrong code"
 	expectedWrongCodeErr := errors.New("wrong code expected error")
 	totpHandler := &testscommon.TOTPHandlerStub{
-		TOTPFromBytesCalled: func(encryptedMessage []byte) (handlers.OTP, error) {
+		TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
 			return &testscommon.TotpStub{
 				ValidateCalled: func(userCode string) error {
 					if userCode == wrongCode {
@@ -1192,7 +1275,7 @@ func TestServiceResolver_verifySecurityModeCode(t *testing.T) {
 
 		args := createMockArgs()
 		args.TOTPHandler = &testscommon.TOTPHandlerStub{
-			TOTPFromBytesCalled: func(encryptedMessage []byte) (handlers.OTP, error) {
+			TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
 				return &testscommon.TotpStub{
 					ValidateCalled: func(userCode string) error {
 						require.Fail(t, "should not be called")
@@ -1209,7 +1292,7 @@ func TestServiceResolver_verifySecurityModeCode(t *testing.T) {
 		secondCode := "second code"
 		guardianAddr := []byte(providedRequest.Guardian)
 		remainingTrials := 3
-		securityModeExtended, err := resolver.verifySecurityModeCode(providedUserInfo, usrAddr, firstCode, secondCode, guardianAddr, remainingTrials)
+		securityModeExtended, err := resolver.verifySecurityModeCode(providedUserInfo, usrAddr, firstCode, secondCode, guardianAddr, 0, remainingTrials)
 		require.Nil(t, err)
 		require.False(t, securityModeExtended)
 	})
@@ -1225,7 +1308,7 @@ func TestServiceResolver_verifySecurityModeCode(t *testing.T) {
 		firstCode := "123456"
 		guardianAddr := []byte(providedRequest.Guardian)
 		remainingTrials := 0
-		securityModeExtended, err := resolver.verifySecurityModeCode(providedUserInfo, usrAddr, firstCode, wrongCode, guardianAddr, remainingTrials)
+		securityModeExtended, err := resolver.verifySecurityModeCode(providedUserInfo, usrAddr, firstCode, wrongCode, guardianAddr, 0, remainingTrials)
 		require.ErrorIs(t, err, ErrSecondCodeInvalidInSecurityMode)
 		require.True(t, securityModeExtended)
 	})
@@ -1245,7 +1328,7 @@ func TestServiceResolver_verifySecurityModeCode(t *testing.T) {
 
 		guardianAddr := []byte(providedRequest.Guardian)
 		remainingTrials := 0
-		securityModeExtended, err := resolver.verifySecurityModeCode(providedUserInfo, usrAddr, "code1", "code2", guardianAddr, remainingTrials)
+		securityModeExtended, err := resolver.verifySecurityModeCode(providedUserInfo, usrAddr, "code1", "code2", guardianAddr, 0, remainingTrials)
 		require.Nil(t, err)
 		require.False(t, securityModeExtended)
 	})
@@ -1268,7 +1351,7 @@ func TestServiceResolver_verifySecurityModeCode(t *testing.T) {
 
 		guardianAddr := []byte(providedRequest.Guardian)
 		remainingTrials := 0
-		securityModeExtended, err := resolver.verifySecurityModeCode(providedUserInfo, usrAddr, "code1", "code2", guardianAddr, remainingTrials)
+		securityModeExtended, err := resolver.verifySecurityModeCode(providedUserInfo, usrAddr, "code1", "code2", guardianAddr, 0, remainingTrials)
 		require.Nil(t, err)
 		require.Equal(t, 1, decrementCalled)
 		require.False(t, securityModeExtended)
@@ -1279,7 +1362,7 @@ func TestServiceResolver_verifySecurityModeCode(t *testing.T) {
 
 		args := createMockArgs()
 		args.TOTPHandler = &testscommon.TOTPHandlerStub{
-			TOTPFromBytesCalled: func(encryptedMessage []byte) (handlers.OTP, error) {
+			TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
 				return &testscommon.TotpStub{
 					ValidateCalled: func(userCode string) error {
 						require.Fail(t, "should not be called")
@@ -1297,7 +1380,7 @@ func TestServiceResolver_verifySecurityModeCode(t *testing.T) {
 		guardianAddr := []byte(providedRequest.Guardian)
 		remainingTrials := 0
 
-		securityModeExtended, err := resolver.verifySecurityModeCode(providedUserInfo, usrAddr, firstCode, secondCode, guardianAddr, remainingTrials)
+		securityModeExtended, err := resolver.verifySecurityModeCode(providedUserInfo, usrAddr, firstCode, secondCode, guardianAddr, 0, remainingTrials)
 		require.True(t, errors.Is(err, ErrSecondCodeInvalidInSecurityMode))
 		require.True(t, securityModeExtended)
 	})
@@ -1315,7 +1398,7 @@ func TestServiceResolver_checkAllowanceAndVerifyCode(t *testing.T) {
 	wrongCodeExpectedErr := errors.New("wrong code expected error")
 	wrongCode := "wrong code"
 	totp := &testscommon.TOTPHandlerStub{
-		TOTPFromBytesCalled: func(encryptedMessage []byte) (handlers.OTP, error) {
+		TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
 			return &testscommon.TotpStub{
 				ValidateCalled: func(userCode string) error {
 					if userCode == wrongCode {
@@ -1369,11 +1452,13 @@ func TestServiceResolver_checkAllowanceAndVerifyCode(t *testing.T) {
 		providedUserInfoCopy := *providedUserInfo
 		otpVerifyData, err := resolver.checkAllowanceAndVerifyCode(
 			&providedUserInfoCopy,
+			[]byte(usrAddr),
 			usrAddr,
 			"userIP",
 			providedRequest.Code,
 			providedRequest.SecondCode,
-			[]byte(providedRequest.Guardian))
+			[]byte(providedRequest.Guardian),
+			false)
 
 		require.Equal(t, expectedErr, err)
 		require.Nil(t, otpVerifyData)
@@ -1418,11 +1503,13 @@ func TestServiceResolver_checkAllowanceAndVerifyCode(t *testing.T) {
 		providedUserInfoCopy := *providedUserInfo
 		otpVerifyData, err := resolver.checkAllowanceAndVerifyCode(
 			&providedUserInfoCopy,
+			[]byte(usrAddr),
 			usrAddr,
 			"userIP",
 			wrongCode,
 			providedRequest.SecondCode,
-			[]byte(providedRequest.Guardian))
+			[]byte(providedRequest.Guardian),
+			false)
 
 		require.Equal(t, wrongCodeExpectedErr, err)
 		require.Equal(t, isVerificationAllowedOtpData, *otpVerifyData)
@@ -1435,7 +1522,7 @@ func TestServiceResolver_checkAllowanceAndVerifyCode(t *testing.T) {
 		validateCalled := 0
 		args := createMockArgs()
 		args.TOTPHandler = &testscommon.TOTPHandlerStub{
-			TOTPFromBytesCalled: func(encryptedMessage []byte) (handlers.OTP, error) {
+			TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
 				return &testscommon.TotpStub{
 					ValidateCalled: func(userCode string) error {
 						validateCalled++
@@ -1467,11 +1554,13 @@ func TestServiceResolver_checkAllowanceAndVerifyCode(t *testing.T) {
 		providedUserInfoCopy := *providedUserInfo
 		otpVerifyData, err := resolver.checkAllowanceAndVerifyCode(
 			&providedUserInfoCopy,
+			[]byte(usrAddr),
 			usrAddr,
 			"userIP",
 			providedRequest.Code,
 			wrongCode,
-			[]byte(providedRequest.Guardian))
+			[]byte(providedRequest.Guardian),
+			false)
 
 		isVerificationAllowedOtpData := requests.OTPCodeVerifyData{
 			RemainingTrials:             3,
@@ -1533,11 +1622,13 @@ func TestServiceResolver_checkAllowanceAndVerifyCode(t *testing.T) {
 		providedUserInfoCopy := *providedUserInfo
 		otpVerifyData, err := resolver.checkAllowanceAndVerifyCode(
 			&providedUserInfoCopy,
+			[]byte(usrAddr),
 			usrAddr,
 			"userIP",
 			providedRequest.Code,
 			wrongCode,
-			[]byte(providedRequest.Guardian))
+			[]byte(providedRequest.Guardian),
+			false)
 
 		expectedData := requests.OTPCodeVerifyData{
 			RemainingTrials:             int(maxNormalModeFailures),
@@ -1566,14 +1657,14 @@ func TestServiceResolver_VerifyCode(t *testing.T) {
 		providedUserInfoCopy := *providedUserInfo
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
 		}
 
 		args.TOTPHandler = &testscommon.TOTPHandlerStub{
-			TOTPFromBytesCalled: func(encryptedMessage []byte) (handlers.OTP, error) {
+			TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
 				return &testscommon.TotpStub{
 					ValidateCalled: func(userCode string) error {
 						return expectedErr
@@ -1621,14 +1712,14 @@ func TestServiceResolver_VerifyCode(t *testing.T) {
 			},
 		}
 		args.TOTPHandler = &testscommon.TOTPHandlerStub{
-			TOTPFromBytesCalled: func(encryptedMessage []byte) (handlers.OTP, error) {
+			TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
 				assert.Fail(t, "should not have called this")
 				return nil, nil
 			},
 		}
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -1640,7 +1731,7 @@ func TestServiceResolver_VerifyCode(t *testing.T) {
 		userAddress, _ := sdkData.NewAddressFromBech32String(usrAddr)
 
 		resolver, _ := NewServiceResolver(args)
-		otpVerifyCodeData, err := resolver.VerifyCode(userAddress, "userIp", providedRequest)
+		_, otpVerifyCodeData, err := resolver.VerifyCode(userAddress, "userIp", providedRequest)
 		assert.Equal(t, expectedErr, err)
 		require.Nil(t, otpVerifyCodeData)
 	})
@@ -1659,14 +1750,14 @@ func TestServiceResolver_VerifyCode(t *testing.T) {
 			},
 		}
 		args.TOTPHandler = &testscommon.TOTPHandlerStub{
-			TOTPFromBytesCalled: func(encryptedMessage []byte) (handlers.OTP, error) {
+			TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
 				assert.Fail(t, "should not have called this")
 				return nil, nil
 			},
 		}
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -1678,7 +1769,7 @@ func TestServiceResolver_VerifyCode(t *testing.T) {
 		userAddress, _ := sdkData.NewAddressFromBech32String(usrAddr)
 
 		resolver, _ := NewServiceResolver(args)
-		otpVerifyCodeData, err := resolver.VerifyCode(userAddress, "userIp", providedRequest)
+		_, otpVerifyCodeData, err := resolver.VerifyCode(userAddress, "userIp", providedRequest)
 		assert.True(t, errors.Is(err, core.ErrTooManyFailedAttempts))
 		assert.Equal(t, 2, otpVerifyCodeData.RemainingTrials)
 		assert.Equal(t, 10, otpVerifyCodeData.ResetAfter)
@@ -1703,7 +1794,7 @@ func TestServiceResolver_VerifyCode(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -1735,7 +1826,7 @@ func TestServiceResolver_VerifyCode(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -1760,7 +1851,7 @@ func TestServiceResolver_VerifyCode(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -1781,7 +1872,7 @@ func TestServiceResolver_VerifyCode(t *testing.T) {
 		putCalled := false
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -1797,7 +1888,7 @@ func TestServiceResolver_VerifyCode(t *testing.T) {
 		}
 		numCalled := 0
 		args.TOTPHandler = &testscommon.TOTPHandlerStub{
-			TOTPFromBytesCalled: func(encryptedMessage []byte) (handlers.OTP, error) {
+			TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
 				return &testscommon.TotpStub{
 					ValidateCalled: func(userCode string) error {
 						switch numCalled {
@@ -1812,10 +1903,17 @@ func TestServiceResolver_VerifyCode(t *testing.T) {
 				}, nil
 			},
 		}
+		invalidatedAddresses := make([]string, 0)
+		args.HttpClientWrapper = &testscommon.HttpClientWrapperStub{
+			InvalidateGuardianDataCalled: func(address string) {
+				invalidatedAddresses = append(invalidatedAddresses, address)
+			},
+		}
 		userAddress, _ := sdkData.NewAddressFromBech32String(usrAddr)
 		checkVerifyCodeResults(t, args, userAddress, providedRequest, nil)
 		require.Equal(t, 2, numCalled)
 		require.True(t, putCalled)
+		require.Equal(t, []string{usrAddr}, invalidatedAddresses)
 	})
 	t.Run("should work for second guardian", func(t *testing.T) {
 		t.Parallel()
@@ -1824,15 +1922,16 @@ func TestServiceResolver_VerifyCode(t *testing.T) {
 		providedUserInfoCopy.SecondGuardian.State = core.NotUsable
 		args := createMockArgs()
 		putCalled := false
+		savedUserInfo := &core.UserInfo{}
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
 			PutCalled: func(key, data []byte) error {
 				putCalled = true
-				return nil
+				return args.UserDataMarshaller.Unmarshal(savedUserInfo, data)
 			},
 		}
 		args.PubKeyConverter = &mock.PubkeyConverterStub{
@@ -1842,7 +1941,7 @@ func TestServiceResolver_VerifyCode(t *testing.T) {
 		}
 		numCalls := 0
 		args.TOTPHandler = &testscommon.TOTPHandlerStub{
-			TOTPFromBytesCalled: func(encryptedMessage []byte) (handlers.OTP, error) {
+			TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
 				return &testscommon.TotpStub{
 					ValidateCalled: func(userCode string) error {
 						switch numCalls {
@@ -1861,6 +1960,10 @@ func TestServiceResolver_VerifyCode(t *testing.T) {
 		checkVerifyCodeResults(t, args, userAddress, providedRequest, nil)
 		require.Equal(t, 2, numCalls)
 		require.True(t, putCalled)
+		// the guardian becomes usable only once it is active on chain
+		assert.Equal(t, core.NotUsable, savedUserInfo.SecondGuardian.State)
+		assert.Equal(t, core.Verified, savedUserInfo.SecondGuardian.ActivationState)
+		assert.NotZero(t, savedUserInfo.SecondGuardian.VerifiedTimestamp)
 	})
 }
 
@@ -1917,13 +2020,13 @@ func TestServiceResolver_SignTransaction(t *testing.T) {
 		providedUserInfoCopy := *providedUserInfo
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
 		}
 		args.TOTPHandler = &testscommon.TOTPHandlerStub{
-			TOTPFromBytesCalled: func(encryptedMessage []byte) (handlers.OTP, error) {
+			TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
 				return &testscommon.TotpStub{
 					ValidateCalled: func(userCode string) error {
 						return expectedErr
@@ -1969,7 +2072,7 @@ func TestServiceResolver_SignTransaction(t *testing.T) {
 
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2001,7 +2104,7 @@ func TestServiceResolver_SignTransaction(t *testing.T) {
 		}
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2014,7 +2117,7 @@ func TestServiceResolver_SignTransaction(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2041,7 +2144,7 @@ func TestServiceResolver_SignTransaction(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2074,7 +2177,7 @@ func TestServiceResolver_SignTransaction(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2094,7 +2197,7 @@ func TestServiceResolver_SignTransaction(t *testing.T) {
 		}
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2106,6 +2209,177 @@ func TestServiceResolver_SignTransaction(t *testing.T) {
 		assert.True(t, errors.Is(err, expectedErr))
 		assert.Nil(t, txHash)
 	})
+	t.Run("tx policy rejects the transaction", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.TxPolicyHandler = &testscommon.TxPolicyHandlerStub{
+			CheckTransactionsCalled: func(userAddress string, txs []transaction.FrontendTransaction) (bool, error) {
+				return false, handlers.ErrTxPolicyRejected
+			},
+		}
+		args.SecureOtpHandler = &testscommon.SecureOtpHandlerStub{
+			IsVerificationAllowedAndIncreaseTrialsCalled: func(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+				require.Fail(t, "should not have been called")
+				return nil, nil
+			},
+		}
+		signTransactionAndCheckResults(t, args, providedRequest, nil, handlers.ErrTxPolicyRejected)
+	})
+	t.Run("tx policy requires confirmation, missing second code", func(t *testing.T) {
+		t.Parallel()
+
+		request := requests.SignTransaction{
+			Code: defaultFirstCode,
+			Tx: transaction.FrontendTransaction{
+				Sender:       providedSender,
+				Signature:    hex.EncodeToString([]byte("signature")),
+				GuardianAddr: string(providedUserInfo.SecondGuardian.PublicKey),
+			},
+		}
+		args := createMockArgs()
+		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
+			GetCalled: func(key []byte) ([]byte, error) {
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
+				require.Nil(t, err)
+				return args.UserDataMarshaller.Marshal(encryptedUser)
+			},
+		}
+		args.TxPolicyHandler = &testscommon.TxPolicyHandlerStub{
+			CheckTransactionsCalled: func(userAddress string, txs []transaction.FrontendTransaction) (bool, error) {
+				return true, nil
+			},
+			CheckAndRecordTransactionsCalled: func(userAddress string, txs []transaction.FrontendTransaction, isConfirmed bool) error {
+				require.Fail(t, "should not have been called")
+				return nil
+			},
+		}
+		args.SecureOtpHandler = &testscommon.SecureOtpHandlerStub{
+			ResetCalled: func(account string, ip string) {
+				require.Fail(t, "should not have been called")
+			},
+		}
+
+		resolver, _ := NewServiceResolver(args)
+		txHash, _, err := resolver.SignTransaction("userIp", request)
+		assert.True(t, errors.Is(err, handlers.ErrTxPolicyConfirmationRequired))
+		assert.Nil(t, txHash)
+	})
+	t.Run("tx policy requires confirmation, same code provided twice", func(t *testing.T) {
+		t.Parallel()
+
+		request := requests.SignTransaction{
+			Code:       defaultFirstCode,
+			SecondCode: defaultFirstCode,
+			Tx: transaction.FrontendTransaction{
+				Sender:       providedSender,
+				Signature:    hex.EncodeToString([]byte("signature")),
+				GuardianAddr: string(providedUserInfo.SecondGuardian.PublicKey),
+			},
+		}
+		args := createMockArgs()
+		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
+			GetCalled: func(key []byte) ([]byte, error) {
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
+				require.Nil(t, err)
+				return args.UserDataMarshaller.Marshal(encryptedUser)
+			},
+		}
+		args.TxPolicyHandler = &testscommon.TxPolicyHandlerStub{
+			CheckTransactionsCalled: func(userAddress string, txs []transaction.FrontendTransaction) (bool, error) {
+				return true, nil
+			},
+		}
+
+		resolver, _ := NewServiceResolver(args)
+		txHash, _, err := resolver.SignTransaction("userIp", request)
+		assert.True(t, errors.Is(err, handlers.ErrTxPolicyConfirmationRequired))
+		assert.True(t, strings.Contains(err.Error(), ErrSameCode.Error()))
+		assert.Nil(t, txHash)
+	})
+	t.Run("tx policy requires confirmation, should work and record the signed transaction", func(t *testing.T) {
+		t.Parallel()
+
+		request := requests.SignTransaction{
+			Code:       defaultFirstCode,
+			SecondCode: defaultSecondCode,
+			Tx: transaction.FrontendTransaction{
+				Sender:       providedSender,
+				Signature:    hex.EncodeToString([]byte("signature")),
+				GuardianAddr: string(providedUserInfo.SecondGuardian.PublicKey),
+			},
+		}
+		args := createMockArgs()
+		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
+			GetCalled: func(key []byte) ([]byte, error) {
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
+				require.Nil(t, err)
+				return args.UserDataMarshaller.Marshal(encryptedUser)
+			},
+		}
+		validatedCodes := make([]string, 0)
+		args.TOTPHandler = &testscommon.TOTPHandlerStub{
+			TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
+				return &testscommon.TotpStub{
+					ValidateCalled: func(userCode string) error {
+						validatedCodes = append(validatedCodes, userCode)
+						return nil
+					},
+				}, nil
+			},
+		}
+		args.SecureOtpHandler = &testscommon.SecureOtpHandlerStub{
+			IsVerificationAllowedAndIncreaseTrialsCalled: func(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+				return &requests.OTPCodeVerifyData{
+					RemainingTrials:             2,
+					SecurityModeRemainingTrials: 10,
+				}, nil
+			},
+		}
+		recordCalled := false
+		args.TxPolicyHandler = &testscommon.TxPolicyHandlerStub{
+			CheckTransactionsCalled: func(userAddress string, txs []transaction.FrontendTransaction) (bool, error) {
+				return true, nil
+			},
+			CheckAndRecordTransactionsCalled: func(userAddress string, txs []transaction.FrontendTransaction, isConfirmed bool) error {
+				assert.Equal(t, providedSender, userAddress)
+				assert.Equal(t, 1, len(txs))
+				assert.True(t, isConfirmed)
+				recordCalled = true
+				return nil
+			},
+		}
+
+		resolver, _ := NewServiceResolver(args)
+		txHash, _, err := resolver.SignTransaction("userIp", request)
+		assert.Nil(t, err)
+		assert.NotNil(t, txHash)
+		assert.Equal(t, []string{defaultFirstCode, defaultSecondCode}, validatedCodes)
+		assert.True(t, recordCalled)
+	})
+	t.Run("tx policy rejects the signed transaction on record should not return it", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
+			GetCalled: func(key []byte) ([]byte, error) {
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
+				require.Nil(t, err)
+				return args.UserDataMarshaller.Marshal(encryptedUser)
+			},
+		}
+		args.TxPolicyHandler = &testscommon.TxPolicyHandlerStub{
+			CheckAndRecordTransactionsCalled: func(userAddress string, txs []transaction.FrontendTransaction, isConfirmed bool) error {
+				assert.False(t, isConfirmed)
+				return handlers.ErrTxPolicyRejected
+			},
+		}
+
+		resolver, _ := NewServiceResolver(args)
+		txHash, _, err := resolver.SignTransaction("userIp", providedRequest)
+		assert.True(t, errors.Is(err, handlers.ErrTxPolicyRejected))
+		assert.Nil(t, txHash)
+	})
 	t.Run("should work with sig verification", func(t *testing.T) {
 		t.Parallel()
 
@@ -2121,7 +2395,7 @@ func TestServiceResolver_SignTransaction(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2160,7 +2434,7 @@ func TestServiceResolver_SignTransaction(t *testing.T) {
 		args.Config.SkipTxUserSigVerify = true
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2183,6 +2457,52 @@ func TestServiceResolver_SignTransaction(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, finalTxBuff, txHash)
 	})
+	t.Run("guardian related transaction should invalidate the guardian data", func(t *testing.T) {
+		t.Parallel()
+
+		request := requests.SignTransaction{
+			Code:       defaultFirstCode,
+			SecondCode: defaultSecondCode,
+			Tx: transaction.FrontendTransaction{
+				Sender:       providedSender,
+				Receiver:     providedSender,
+				Data:         []byte(chainCore.BuiltInFunctionGuardAccount),
+				GuardianAddr: string(providedUserInfo.SecondGuardian.PublicKey),
+			},
+		}
+		args := createMockArgs()
+		args.Config.SkipTxUserSigVerify = true
+		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
+			GetCalled: func(key []byte) ([]byte, error) {
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
+				require.Nil(t, err)
+				return args.UserDataMarshaller.Marshal(encryptedUser)
+			},
+		}
+		args.TxDecoder = &testscommon.TxDecoderStub{
+			DecodeCalled: func(tx transaction.FrontendTransaction) (*txdecoder.DecodedTransaction, error) {
+				return &txdecoder.DecodedTransaction{
+					Function: string(tx.Data),
+				}, nil
+			},
+		}
+		invalidatedAddresses := make([]string, 0)
+		args.HttpClientWrapper = &testscommon.HttpClientWrapperStub{
+			InvalidateGuardianDataCalled: func(address string) {
+				invalidatedAddresses = append(invalidatedAddresses, address)
+			},
+		}
+
+		resolver, _ := NewServiceResolver(args)
+		_, _, err := resolver.SignTransaction("userIp", request)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{providedSender}, invalidatedAddresses)
+
+		request.Tx.Data = []byte("transfer")
+		_, _, err = resolver.SignTransaction("userIp", request)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{providedSender}, invalidatedAddresses)
+	})
 }
 
 func TestServiceResolver_SignMessage(t *testing.T) {
@@ -2214,13 +2534,13 @@ func TestServiceResolver_SignMessage(t *testing.T) {
 		providedUserInfoCopy := *providedUserInfo
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
 		}
 		args.TOTPHandler = &testscommon.TOTPHandlerStub{
-			TOTPFromBytesCalled: func(encryptedMessage []byte) (handlers.OTP, error) {
+			TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
 				return &testscommon.TotpStub{
 					ValidateCalled: func(userCode string) error {
 						return expectedErr
@@ -2264,7 +2584,7 @@ func TestServiceResolver_SignMessage(t *testing.T) {
 
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2294,7 +2614,7 @@ func TestServiceResolver_SignMessage(t *testing.T) {
 		}
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2310,7 +2630,7 @@ func TestServiceResolver_SignMessage(t *testing.T) {
 		providedRequestCopy.GuardianAddr = string(providedUserInfoCopy.FirstGuardian.PublicKey)
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2336,7 +2656,7 @@ func TestServiceResolver_SignMessage(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2378,7 +2698,7 @@ func TestServiceResolver_SignMessage(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2424,7 +2744,7 @@ func TestServiceResolver_CheckGuardianAndVerifyCode(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2446,7 +2766,7 @@ func TestServiceResolver_CheckGuardianAndVerifyCode(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2475,7 +2795,7 @@ func TestServiceResolver_CheckGuardianAndVerifyCode(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2577,7 +2897,7 @@ func TestServiceResolver_SetSecurityModeNoExpire(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2619,7 +2939,7 @@ func TestServiceResolver_SetSecurityModeNoExpire(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2665,7 +2985,7 @@ func TestServiceResolver_UnsetSecurityModeNoExpire(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2707,7 +3027,7 @@ func TestServiceResolver_UnsetSecurityModeNoExpire(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(&providedUserInfoCopy)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, &providedUserInfoCopy)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2853,7 +3173,7 @@ func TestServiceResolver_SignMultipleTransactions(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2881,7 +3201,7 @@ func TestServiceResolver_SignMultipleTransactions(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2904,7 +3224,7 @@ func TestServiceResolver_SignMultipleTransactions(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2934,7 +3254,7 @@ func TestServiceResolver_SignMultipleTransactions(t *testing.T) {
 		args := createMockArgs()
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -2982,7 +3302,7 @@ func TestServiceResolver_SignMultipleTransactions(t *testing.T) {
 		args.Config.SkipTxUserSigVerify = true
 		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
 			GetCalled: func(key []byte) ([]byte, error) {
-				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(providedUserInfo)
+				encryptedUser, err := args.UserEncryptor.EncryptUserInfo(key, providedUserInfo)
 				require.Nil(t, err)
 				return args.UserDataMarshaller.Marshal(encryptedUser)
 			},
@@ -3038,6 +3358,7 @@ func TestServiceResolver_TcsConfig(t *testing.T) {
 
 	secureOtpArgs := secureOtp.ArgsSecureOtpHandler{
 		RateLimiter: testscommon.NewRateLimiterMock(3, backoffTimeInSeconds),
+		Notifier:    &testscommon.NotifierStub{},
 	}
 	args.SecureOtpHandler, _ = secureOtp.NewSecureOtpHandler(secureOtpArgs)
 	resolver, _ := NewServiceResolver(args)
@@ -3142,7 +3463,7 @@ func TestServiceResolver_parseUrl(t *testing.T) {
 func checkGetGuardianAddressResults(t *testing.T, args ArgServiceResolver, userAddress sdkCore.AddressHandler, expectedErr error, expectedAddress []byte, otp handlers.OTP, expectedAge int64) {
 	resolver, _ := NewServiceResolver(args)
 	assert.NotNil(t, resolver)
-	addr, otpAge, err := resolver.registerUser(userAddress, otp)
+	addr, otpAge, err := resolver.registerUser(userAddress, "userIp", "", otp)
 	assert.True(t, errors.Is(err, expectedErr))
 	assert.Equal(t, expectedAddress, addr)
 	assert.LessOrEqual(t, otpAge, expectedAge)
@@ -3151,7 +3472,7 @@ func checkGetGuardianAddressResults(t *testing.T, args ArgServiceResolver, userA
 func checkRegisterUserResults(t *testing.T, args ArgServiceResolver, userAddress sdkCore.AddressHandler, request requests.RegistrationPayload, expectedErr error, expectedOTPInfo *requests.OTP, expectedGuardian string) {
 	resolver, _ := NewServiceResolver(args)
 	assert.NotNil(t, resolver)
-	otpInfo, guardian, err := resolver.RegisterUser(userAddress, request)
+	otpInfo, guardian, err := resolver.RegisterUser(userAddress, "userIp", request)
 	assert.True(t, errors.Is(err, expectedErr))
 	assert.Equal(t, expectedOTPInfo, otpInfo)
 	assert.Equal(t, expectedGuardian, guardian)
@@ -3160,7 +3481,7 @@ func checkRegisterUserResults(t *testing.T, args ArgServiceResolver, userAddress
 func checkVerifyCodeResults(t *testing.T, args ArgServiceResolver, userAddress sdkCore.AddressHandler, providedRequest requests.VerificationPayload, expectedErr error) {
 	resolver, _ := NewServiceResolver(args)
 	assert.NotNil(t, resolver)
-	_, err := resolver.VerifyCode(userAddress, "userIp", providedRequest)
+	_, _, err := resolver.VerifyCode(userAddress, "userIp", providedRequest)
 	assert.True(t, errors.Is(err, expectedErr))
 }
 
@@ -3194,3 +3515,182 @@ func signMultipleTransactionsAndCheckResults(t *testing.T, args ArgServiceResolv
 	assert.True(t, errors.Is(err, expectedErr))
 	assert.Equal(t, expectedHashes, txHashes)
 }
+
+func TestServiceResolver_VerifyCodeWithCounterBasedOTP(t *testing.T) {
+	t.Parallel()
+
+	hotpParams := core.OTPParams{
+		Type:      core.HOTPType,
+		Algorithm: twofactor.AlgorithmSHA1,
+		Digits:    6,
+		Skew:      1,
+	}
+	otpProvider, err := rfc.NewOTPProvider("MultiversX", 1)
+	require.Nil(t, err)
+	totpHandler, err := twofactor.NewTwoFactorHandler(twofactor.ArgsTwoFactorHandler{
+		OTPProvider:       otpProvider,
+		LegacyOTPProvider: sec51.NewSec51Wrapper("MultiversX"),
+		Params:            hotpParams,
+	})
+	require.Nil(t, err)
+
+	ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+	ctx.resolver.totpHandler = totpHandler
+	ctx.resolver.secureOtpHandler.(*testscommon.SecureOtpHandlerStub).IsVerificationAllowedAndIncreaseTrialsCalled = func(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+		return &requests.OTPCodeVerifyData{
+			RemainingTrials:             2,
+			SecurityModeRemainingTrials: 10,
+		}, nil
+	}
+	userInfo := ctx.getUserInfo(t)
+	userInfo.FirstGuardian.OTPData.OTP = []byte("12345678901234567890")
+	userInfo.FirstGuardian.OTPData.Params = hotpParams
+	err = ctx.resolver.marshalAndSaveEncrypted(ctx.userAddress.AddressBytes(), userInfo)
+	require.Nil(t, err)
+
+	// codes for the counters 0 and 1, from RFC 4226, appendix D
+	providedRequest := requests.VerificationPayload{
+		Code:     "755224",
+		Guardian: string(providedUserInfo.FirstGuardian.PublicKey),
+	}
+	_, _, err = ctx.resolver.VerifyCode(ctx.userAddress, "userIp", providedRequest)
+	require.Nil(t, err)
+	assert.Equal(t, uint64(1), ctx.getUserInfo(t).FirstGuardian.OTPData.Params.Counter)
+
+	_, _, err = ctx.resolver.VerifyCode(ctx.userAddress, "userIp", providedRequest)
+	assert.Equal(t, handlers.ErrWrongCode, err)
+
+	providedRequest.Code = "287082"
+	_, _, err = ctx.resolver.VerifyCode(ctx.userAddress, "userIp", providedRequest)
+	require.Nil(t, err)
+	assert.Equal(t, uint64(2), ctx.getUserInfo(t).FirstGuardian.OTPData.Params.Counter)
+}
+
+func TestServiceResolver_VerifyCodesOfOneRequest(t *testing.T) {
+	t.Parallel()
+
+	hotpParams := core.OTPParams{
+		Type:      core.HOTPType,
+		Algorithm: twofactor.AlgorithmSHA1,
+		Digits:    6,
+		Skew:      1,
+	}
+	otpProvider, err := rfc.NewOTPProvider("MultiversX", 1)
+	require.Nil(t, err)
+	totpHandler, err := twofactor.NewTwoFactorHandler(twofactor.ArgsTwoFactorHandler{
+		OTPProvider:       otpProvider,
+		LegacyOTPProvider: sec51.NewSec51Wrapper("MultiversX"),
+		Params:            hotpParams,
+	})
+	require.Nil(t, err)
+
+	createContext := func(t *testing.T) *webAuthnTestContext {
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+		ctx.resolver.totpHandler = totpHandler
+		ctx.resolver.secureOtpHandler.(*testscommon.SecureOtpHandlerStub).IsVerificationAllowedAndIncreaseTrialsCalled = func(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+			return &requests.OTPCodeVerifyData{
+				RemainingTrials:             2,
+				SecurityModeRemainingTrials: 0,
+			}, nil
+		}
+		userInfo := ctx.getUserInfo(t)
+		userInfo.FirstGuardian.OTPData.OTP = []byte("12345678901234567890")
+		userInfo.FirstGuardian.OTPData.Params = hotpParams
+		err := ctx.resolver.marshalAndSaveEncrypted(ctx.userAddress.AddressBytes(), userInfo)
+		require.Nil(t, err)
+
+		return ctx
+	}
+
+	// codes for the counters 0 and 1, from RFC 4226, appendix D
+	t.Run("codes in any order should work and keep the highest counter", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createContext(t)
+		providedRequest := requests.VerificationPayload{
+			Code:       "287082",
+			SecondCode: "755224",
+			Guardian:   string(providedUserInfo.FirstGuardian.PublicKey),
+		}
+		_, _, err := ctx.resolver.VerifyCode(ctx.userAddress, "userIp", providedRequest)
+		require.Nil(t, err)
+		assert.Equal(t, uint64(2), ctx.getUserInfo(t).FirstGuardian.OTPData.Params.Counter)
+	})
+	t.Run("accepted code should not be replayed after a failed request", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createContext(t)
+		providedRequest := requests.VerificationPayload{
+			Code:       "755224",
+			SecondCode: "000000",
+			Guardian:   string(providedUserInfo.FirstGuardian.PublicKey),
+		}
+		_, _, err := ctx.resolver.VerifyCode(ctx.userAddress, "userIp", providedRequest)
+		assert.True(t, errors.Is(err, ErrSecondCodeInvalidInSecurityMode))
+		assert.Equal(t, uint64(1), ctx.getUserInfo(t).FirstGuardian.OTPData.Params.Counter)
+
+		providedRequest.SecondCode = "287082"
+		_, _, err = ctx.resolver.VerifyCode(ctx.userAddress, "userIp", providedRequest)
+		assert.Equal(t, handlers.ErrWrongCode, err)
+	})
+}
+
+func TestServiceResolver_VerifyCodeWithLegacyTOTP(t *testing.T) {
+	t.Parallel()
+
+	totpParams := core.OTPParams{
+		Type:      core.TOTPType,
+		Algorithm: twofactor.AlgorithmSHA1,
+		Digits:    6,
+		Period:    30,
+		Skew:      1,
+	}
+	legacyOTPProvider := sec51.NewSec51Wrapper("MultiversX")
+	otpProvider, err := rfc.NewOTPProvider("MultiversX", 1)
+	require.Nil(t, err)
+	totpHandler, err := twofactor.NewTwoFactorHandler(twofactor.ArgsTwoFactorHandler{
+		OTPProvider:       otpProvider,
+		LegacyOTPProvider: legacyOTPProvider,
+		Params:            totpParams,
+	})
+	require.Nil(t, err)
+
+	ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+	ctx.resolver.totpHandler = totpHandler
+	ctx.resolver.secureOtpHandler.(*testscommon.SecureOtpHandlerStub).IsVerificationAllowedAndIncreaseTrialsCalled = func(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+		return &requests.OTPCodeVerifyData{
+			RemainingTrials:             2,
+			SecurityModeRemainingTrials: 10,
+		}, nil
+	}
+
+	legacyOTP, err := legacyOTPProvider.GenerateOTP("account", totpParams)
+	require.Nil(t, err)
+	legacyOTPBytes, err := legacyOTP.ToBytes()
+	require.Nil(t, err)
+	code, err := legacyOTP.OTP()
+	require.Nil(t, err)
+
+	userInfo := ctx.getUserInfo(t)
+	userInfo.FirstGuardian.OTPData.OTP = legacyOTPBytes
+	userInfo.FirstGuardian.OTPData.Params = core.OTPParams{}
+	err = ctx.resolver.marshalAndSaveEncrypted(ctx.userAddress.AddressBytes(), userInfo)
+	require.Nil(t, err)
+
+	providedRequest := requests.VerificationPayload{
+		Code:     code,
+		Guardian: string(providedUserInfo.FirstGuardian.PublicKey),
+	}
+	_, _, err = ctx.resolver.VerifyCode(ctx.userAddress, "userIp", providedRequest)
+	require.Nil(t, err)
+
+	// the legacy otp should be migrated on the first use
+	otpInfo := ctx.getUserInfo(t).FirstGuardian.OTPData
+	assert.Equal(t, core.TOTPType, otpInfo.Params.Type)
+	assert.NotZero(t, otpInfo.Params.Counter)
+	assert.NotEqual(t, legacyOTPBytes, otpInfo.OTP)
+
+	// the same code should not be accepted twice
+	_, _, err = ctx.resolver.VerifyCode(ctx.userAddress, "userIp", providedRequest)
+	assert.Equal(t, handlers.ErrWrongCode, err)
+}
@@ -0,0 +1,477 @@
+package resolver
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	sdkCore "github.com/multiversx/mx-sdk-go/core"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+)
+
+const (
+	maxSpendingLimits   = 50
+	maxTrustedReceivers = 100
+	secondsInDay        = 24 * 60 * 60
+	secondsInWeek       = 7 * secondsInDay
+)
+
+// SetSpendingPolicy verifies the code and then sets the spending limits and the trusted receivers of the user.
+// A policy which is not stricter than the active one only becomes active after the configured delay,
+// so that a leaked code cannot be used to raise the limits right away
+func (resolver *serviceResolver) SetSpendingPolicy(userAddress sdkCore.AddressHandler, userIp string, request requests.SetSpendingPolicy) (*requests.OTPCodeVerifyData, error) {
+	newPolicy, err := resolver.parseSpendingPolicy(request)
+	if err != nil {
+		return nil, err
+	}
+
+	guardianAddr, err := resolver.pubKeyConverter.Decode(request.Guardian)
+	if err != nil {
+		return nil, err
+	}
+
+	addressBytes := userAddress.AddressBytes()
+	resolver.userCritSection.Lock(string(addressBytes))
+	defer resolver.userCritSection.Unlock(string(addressBytes))
+
+	userInfo, err := resolver.getUserInfo(addressBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	bech32Addr, err := userAddress.AddressAsBech32String()
+	if err != nil {
+		return nil, err
+	}
+
+	verifyCodeData, err := resolver.checkAllowanceAndVerifyCode(userInfo, addressBytes, bech32Addr, userIp, request.Code, request.SecondCode, guardianAddr, false)
+	if err != nil {
+		return verifyCodeData, err
+	}
+
+	_, err = resolver.getGuardianInfoFromAddress(addressBytes, request.Guardian, userInfo)
+	if err != nil {
+		return verifyCodeData, err
+	}
+
+	spending, err := resolver.getUserSpending(userInfo)
+	if err != nil {
+		return verifyCodeData, err
+	}
+
+	currentTimestamp := resolver.getTimeHandler().Unix()
+	activatePendingSpendingPolicy(spending, currentTimestamp)
+	if isStricterSpendingPolicy(&spending.Active, newPolicy) {
+		spending.Active = *newPolicy
+		spending.Pending = nil
+	} else {
+		newPolicy.ActivationTimestamp = currentTimestamp + int64(resolver.config.SpendingPolicyActivationDelayInSec)
+		spending.Pending = newPolicy
+	}
+
+	err = resolver.saveUserSpending(addressBytes, userInfo, spending)
+	if err != nil {
+		return verifyCodeData, err
+	}
+
+	log.Debug("spending policy set",
+		"userAddress", bech32Addr,
+		"num limits", len(newPolicy.Limits),
+		"num trusted receivers", len(newPolicy.TrustedReceivers),
+		"activation timestamp", newPolicy.ActivationTimestamp)
+
+	return verifyCodeData, nil
+}
+
+// GetSpendingPolicy returns the spending policies of the user along with the amounts spent in the current day and week
+func (resolver *serviceResolver) GetSpendingPolicy(userAddress sdkCore.AddressHandler) (*requests.SpendingPolicyResponse, error) {
+	addressBytes := userAddress.AddressBytes()
+	resolver.userCritSection.RLock(string(addressBytes))
+	userInfo, err := resolver.getUserInfo(addressBytes)
+	resolver.userCritSection.RUnlock(string(addressBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	return resolver.createSpendingPolicyResponse(userInfo)
+}
+
+func (resolver *serviceResolver) createSpendingPolicyResponse(userInfo *core.UserInfo) (*requests.SpendingPolicyResponse, error) {
+	spending, err := resolver.getUserSpending(userInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	currentTimestamp := resolver.getTimeHandler().Unix()
+	activatePendingSpendingPolicy(spending, currentTimestamp)
+
+	response := &requests.SpendingPolicyResponse{
+		Active: resolver.convertSpendingPolicy(&spending.Active),
+		Spent:  make([]requests.SpentAmount, 0, len(spending.Spent)),
+	}
+	if spending.Pending != nil {
+		pendingPolicy := resolver.convertSpendingPolicy(spending.Pending)
+		response.Pending = &pendingPolicy
+	}
+	for _, spentAmount := range spending.Spent {
+		resetExpiredSpentAmount(&spentAmount, currentTimestamp)
+		response.Spent = append(response.Spent, requests.SpentAmount{
+			Token:       spentAmount.Token,
+			DailySpent:  spentAmount.DailySpent,
+			WeeklySpent: spentAmount.WeeklySpent,
+		})
+	}
+
+	return response, nil
+}
+
+// checkSpending checks the transactions against the spending limits of the user, without recording them,
+// so that requests exceeding the limits are rejected before the codes are verified
+func (resolver *serviceResolver) checkSpending(userAddress []byte, txs []transaction.FrontendTransaction) error {
+	resolver.userCritSection.RLock(string(userAddress))
+	defer resolver.userCritSection.RUnlock(string(userAddress))
+
+	_, _, err := resolver.computeSpending(userAddress, txs)
+
+	return err
+}
+
+// computeSpending checks the transactions against the spending limits of the user and returns the user info along with
+// the spending updated with the transactions. The returned spending is nil if the user has no limits. Nothing is saved,
+// so the caller must hold the user lock until the spending is saved
+func (resolver *serviceResolver) computeSpending(userAddress []byte, txs []transaction.FrontendTransaction) (*core.UserInfo, *core.UserSpending, error) {
+	userInfo, err := resolver.getUserInfo(userAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	spending, err := resolver.getUserSpending(userInfo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	currentTimestamp := resolver.getTimeHandler().Unix()
+	activatePendingSpendingPolicy(spending, currentTimestamp)
+	if len(spending.Active.Limits) == 0 {
+		return userInfo, nil, nil
+	}
+
+	err = resolver.addTransactionsToSpending(spending, txs, currentTimestamp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return userInfo, spending, nil
+}
+
+func (resolver *serviceResolver) addTransactionsToSpending(spending *core.UserSpending, txs []transaction.FrontendTransaction, currentTimestamp int64) error {
+	limits := make(map[string]core.SpendingLimit, len(spending.Active.Limits))
+	for _, limit := range spending.Active.Limits {
+		limits[limit.Token] = limit
+	}
+
+	trustedReceivers := make(map[string]struct{}, len(spending.Active.TrustedReceivers))
+	for _, receiver := range spending.Active.TrustedReceivers {
+		trustedReceivers[string(receiver)] = struct{}{}
+	}
+
+	spentAmounts := make(map[string]*core.SpentAmount, len(limits))
+	for _, spentAmount := range spending.Spent {
+		_, hasLimit := limits[spentAmount.Token]
+		if !hasLimit {
+			continue
+		}
+
+		spentAmountCopy := spentAmount
+		resetExpiredSpentAmount(&spentAmountCopy, currentTimestamp)
+		spentAmounts[spentAmount.Token] = &spentAmountCopy
+	}
+
+	for index, tx := range txs {
+		decodedTx, err := resolver.txDecoder.Decode(tx)
+		if err != nil {
+			return fmt.Errorf("%w for transaction #%d", err, index)
+		}
+
+		_, isTrusted := trustedReceivers[string(decodedTx.Receiver)]
+		if isTrusted {
+			continue
+		}
+
+		for _, transfer := range decodedTx.Transfers {
+			limit, hasLimit := limits[transfer.Token]
+			if !hasLimit {
+				continue
+			}
+
+			spentAmount, found := spentAmounts[transfer.Token]
+			if !found {
+				spentAmount = &core.SpentAmount{
+					Token: transfer.Token,
+					Day:   currentTimestamp / secondsInDay,
+					Week:  currentTimestamp / secondsInWeek,
+				}
+				spentAmounts[transfer.Token] = spentAmount
+			}
+
+			err = addToSpentAmount(spentAmount, limit, transfer.Amount)
+			if err != nil {
+				return fmt.Errorf("%w for transaction #%d", err, index)
+			}
+		}
+	}
+
+	spending.Spent = make([]core.SpentAmount, 0, len(spentAmounts))
+	for _, spentAmount := range spentAmounts {
+		spending.Spent = append(spending.Spent, *spentAmount)
+	}
+	sort.Slice(spending.Spent, func(i, j int) bool {
+		return spending.Spent[i].Token < spending.Spent[j].Token
+	})
+
+	return nil
+}
+
+func addToSpentAmount(spentAmount *core.SpentAmount, limit core.SpendingLimit, amount *big.Int) error {
+	dailySpent, err := addAmount(spentAmount.DailySpent, amount)
+	if err != nil {
+		return err
+	}
+	weeklySpent, err := addAmount(spentAmount.WeeklySpent, amount)
+	if err != nil {
+		return err
+	}
+
+	err = checkLimit(dailySpent, limit.Daily)
+	if err != nil {
+		return fmt.Errorf("%w, daily limit %s of %s", err, limit.Daily, limit.Token)
+	}
+	err = checkLimit(weeklySpent, limit.Weekly)
+	if err != nil {
+		return fmt.Errorf("%w, weekly limit %s of %s", err, limit.Weekly, limit.Token)
+	}
+
+	spentAmount.DailySpent = dailySpent.String()
+	spentAmount.WeeklySpent = weeklySpent.String()
+
+	return nil
+}
+
+func addAmount(spent string, amount *big.Int) (*big.Int, error) {
+	spentValue, err := parseAmount(spent)
+	if err != nil {
+		return nil, err
+	}
+
+	return spentValue.Add(spentValue, amount), nil
+}
+
+func checkLimit(spent *big.Int, limit string) error {
+	if len(limit) == 0 {
+		return nil
+	}
+
+	limitValue, err := parseAmount(limit)
+	if err != nil {
+		return err
+	}
+	if spent.Cmp(limitValue) > 0 {
+		return ErrSpendingLimitExceeded
+	}
+
+	return nil
+}
+
+func resetExpiredSpentAmount(spentAmount *core.SpentAmount, currentTimestamp int64) {
+	currentDay := currentTimestamp / secondsInDay
+	if spentAmount.Day != currentDay {
+		spentAmount.Day = currentDay
+		spentAmount.DailySpent = ""
+	}
+
+	currentWeek := currentTimestamp / secondsInWeek
+	if spentAmount.Week != currentWeek {
+		spentAmount.Week = currentWeek
+		spentAmount.WeeklySpent = ""
+	}
+}
+
+func activatePendingSpendingPolicy(spending *core.UserSpending, currentTimestamp int64) {
+	if spending.Pending == nil || spending.Pending.ActivationTimestamp > currentTimestamp {
+		return
+	}
+
+	spending.Active = *spending.Pending
+	spending.Pending = nil
+}
+
+// isStricterSpendingPolicy returns true if the new policy keeps a limit at least as low for every limit
+// of the active policy and does not add any trusted receiver
+func isStricterSpendingPolicy(activePolicy *core.SpendingPolicy, newPolicy *core.SpendingPolicy) bool {
+	// trusted receivers only bypass existing limits, so nothing can be looser than no limits at all
+	if len(activePolicy.Limits) == 0 {
+		return true
+	}
+
+	newLimits := make(map[string]core.SpendingLimit, len(newPolicy.Limits))
+	for _, limit := range newPolicy.Limits {
+		newLimits[limit.Token] = limit
+	}
+
+	for _, activeLimit := range activePolicy.Limits {
+		newLimit, found := newLimits[activeLimit.Token]
+		if !found {
+			return false
+		}
+		if !isStricterLimit(activeLimit.Daily, newLimit.Daily) || !isStricterLimit(activeLimit.Weekly, newLimit.Weekly) {
+			return false
+		}
+	}
+
+	activeReceivers := make(map[string]struct{}, len(activePolicy.TrustedReceivers))
+	for _, receiver := range activePolicy.TrustedReceivers {
+		activeReceivers[string(receiver)] = struct{}{}
+	}
+	for _, receiver := range newPolicy.TrustedReceivers {
+		_, found := activeReceivers[string(receiver)]
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isStricterLimit(activeLimit string, newLimit string) bool {
+	if len(activeLimit) == 0 {
+		return true
+	}
+	if len(newLimit) == 0 {
+		return false
+	}
+
+	activeValue, errActive := parseAmount(activeLimit)
+	newValue, errNew := parseAmount(newLimit)
+	if errActive != nil || errNew != nil {
+		return false
+	}
+
+	return newValue.Cmp(activeValue) <= 0
+}
+
+func (resolver *serviceResolver) parseSpendingPolicy(request requests.SetSpendingPolicy) (*core.SpendingPolicy, error) {
+	if len(request.Limits) > maxSpendingLimits {
+		return nil, fmt.Errorf("%w, too many limits, got %d, max allowed %d", ErrInvalidSpendingPolicy, len(request.Limits), maxSpendingLimits)
+	}
+	if len(request.TrustedReceivers) > maxTrustedReceivers {
+		return nil, fmt.Errorf("%w, too many trusted receivers, got %d, max allowed %d", ErrInvalidSpendingPolicy, len(request.TrustedReceivers), maxTrustedReceivers)
+	}
+
+	policy := &core.SpendingPolicy{
+		Limits:           make([]core.SpendingLimit, 0, len(request.Limits)),
+		TrustedReceivers: make([][]byte, 0, len(request.TrustedReceivers)),
+	}
+
+	tokens := make(map[string]struct{}, len(request.Limits))
+	for _, limit := range request.Limits {
+		if len(limit.Token) == 0 {
+			return nil, fmt.Errorf("%w, empty token", ErrInvalidSpendingPolicy)
+		}
+		_, isDuplicate := tokens[limit.Token]
+		if isDuplicate {
+			return nil, fmt.Errorf("%w, duplicated token %s", ErrInvalidSpendingPolicy, limit.Token)
+		}
+		tokens[limit.Token] = struct{}{}
+
+		if len(limit.Daily) == 0 && len(limit.Weekly) == 0 {
+			return nil, fmt.Errorf("%w, no limit for token %s", ErrInvalidSpendingPolicy, limit.Token)
+		}
+		_, err := parseAmount(limit.Daily)
+		if err != nil {
+			return nil, fmt.Errorf("%w, daily limit of token %s: %s", ErrInvalidSpendingPolicy, limit.Token, err.Error())
+		}
+		_, err = parseAmount(limit.Weekly)
+		if err != nil {
+			return nil, fmt.Errorf("%w, weekly limit of token %s: %s", ErrInvalidSpendingPolicy, limit.Token, err.Error())
+		}
+
+		policy.Limits = append(policy.Limits, core.SpendingLimit{
+			Token:  limit.Token,
+			Daily:  limit.Daily,
+			Weekly: limit.Weekly,
+		})
+	}
+
+	for _, receiver := range request.TrustedReceivers {
+		receiverBytes, err := resolver.pubKeyConverter.Decode(receiver)
+		if err != nil {
+			return nil, fmt.Errorf("%w, trusted receiver %s: %s", ErrInvalidSpendingPolicy, receiver, err.Error())
+		}
+
+		policy.TrustedReceivers = append(policy.TrustedReceivers, receiverBytes)
+	}
+
+	return policy, nil
+}
+
+func (resolver *serviceResolver) convertSpendingPolicy(policy *core.SpendingPolicy) requests.SpendingPolicy {
+	convertedPolicy := requests.SpendingPolicy{
+		Limits:              make([]requests.SpendingLimit, 0, len(policy.Limits)),
+		TrustedReceivers:    make([]string, 0, len(policy.TrustedReceivers)),
+		ActivationTimestamp: policy.ActivationTimestamp,
+	}
+	for _, limit := range policy.Limits {
+		convertedPolicy.Limits = append(convertedPolicy.Limits, requests.SpendingLimit{
+			Token:  limit.Token,
+			Daily:  limit.Daily,
+			Weekly: limit.Weekly,
+		})
+	}
+	for _, receiver := range policy.TrustedReceivers {
+		convertedPolicy.TrustedReceivers = append(convertedPolicy.TrustedReceivers, resolver.pubKeyConverter.SilentEncode(receiver, log))
+	}
+
+	return convertedPolicy
+}
+
+func (resolver *serviceResolver) getUserSpending(userInfo *core.UserInfo) (*core.UserSpending, error) {
+	spending := &core.UserSpending{}
+	if len(userInfo.SpendingData) == 0 {
+		return spending, nil
+	}
+
+	err := resolver.userDataMarshaller.Unmarshal(spending, userInfo.SpendingData)
+	if err != nil {
+		return nil, err
+	}
+
+	return spending, nil
+}
+
+func (resolver *serviceResolver) saveUserSpending(userAddress []byte, userInfo *core.UserInfo, spending *core.UserSpending) error {
+	spendingData, err := resolver.userDataMarshaller.Marshal(spending)
+	if err != nil {
+		return err
+	}
+
+	userInfoCopy := *userInfo
+	userInfoCopy.SpendingData = spendingData
+
+	return resolver.marshalAndSaveEncrypted(userAddress, &userInfoCopy)
+}
+
+func parseAmount(amount string) (*big.Int, error) {
+	if len(amount) == 0 {
+		return big.NewInt(0), nil
+	}
+
+	value, ok := big.NewInt(0).SetString(amount, 10)
+	if !ok || value.Sign() < 0 {
+		return nil, fmt.Errorf("%w %s", ErrInvalidValue, amount)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,511 @@
+package resolver
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	chainCore "github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/data/mock"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	sdkCore "github.com/multiversx/mx-sdk-go/core"
+	sdkData "github.com/multiversx/mx-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/txdecoder"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+const (
+	trustedReceiver   = "trusted receiver"
+	untrustedReceiver = "untrusted receiver"
+)
+
+type spendingPolicyTestContext struct {
+	resolver    *serviceResolver
+	userAddress sdkCore.AddressHandler
+	mutDB       sync.Mutex
+	db          map[string][]byte
+	numPuts     int
+	currentTime time.Time
+}
+
+func createSpendingPolicyTestContext(t *testing.T) *spendingPolicyTestContext {
+	ctx := &spendingPolicyTestContext{
+		db:          make(map[string][]byte),
+		currentTime: time.Unix(1000*secondsInWeek, 0),
+	}
+
+	args := createMockArgs()
+	args.Config.SpendingPolicyActivationDelayInSec = secondsInDay
+	args.SecureOtpHandler = &testscommon.SecureOtpHandlerStub{
+		IsVerificationAllowedAndIncreaseTrialsCalled: func(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+			return &requests.OTPCodeVerifyData{
+				SecurityModeRemainingTrials: 10,
+			}, nil
+		},
+	}
+	args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
+		PutCalled: func(key, data []byte) error {
+			ctx.mutDB.Lock()
+			ctx.db[string(key)] = data
+			ctx.numPuts++
+			ctx.mutDB.Unlock()
+			return nil
+		},
+		GetCalled: func(key []byte) ([]byte, error) {
+			ctx.mutDB.Lock()
+			defer ctx.mutDB.Unlock()
+			return ctx.db[string(key)], nil
+		},
+	}
+	args.PubKeyConverter = &mock.PubkeyConverterStub{
+		DecodeCalled: func(humanReadable string) ([]byte, error) {
+			return []byte(humanReadable), nil
+		},
+		EncodeCalled: func(pkBytes []byte) (string, error) {
+			return string(pkBytes), nil
+		},
+		SilentEncodeCalled: func(pkBytes []byte, log chainCore.Logger) string {
+			return string(pkBytes)
+		},
+	}
+	args.TxDecoder, _ = txdecoder.NewTxDecoder(args.PubKeyConverter)
+
+	var err error
+	ctx.resolver, err = NewServiceResolver(args)
+	require.Nil(t, err)
+	ctx.resolver.getTimeHandler = func() time.Time {
+		return ctx.currentTime
+	}
+
+	ctx.userAddress, err = sdkData.NewAddressFromBech32String(usrAddr)
+	require.Nil(t, err)
+
+	userInfo := *providedUserInfo
+	err = ctx.resolver.marshalAndSaveEncrypted(ctx.userAddress.AddressBytes(), &userInfo)
+	require.Nil(t, err)
+	ctx.numPuts = 0
+
+	return ctx
+}
+
+func (ctx *spendingPolicyTestContext) setPolicy(limits []requests.SpendingLimit, trustedReceivers []string) error {
+	request := requests.SetSpendingPolicy{
+		Code:             defaultFirstCode,
+		Guardian:         string(providedUserInfo.FirstGuardian.PublicKey),
+		Limits:           limits,
+		TrustedReceivers: trustedReceivers,
+	}
+	_, err := ctx.resolver.SetSpendingPolicy(ctx.userAddress, "userIp", request)
+
+	return err
+}
+
+func (ctx *spendingPolicyTestContext) getPolicy(t *testing.T) *requests.SpendingPolicyResponse {
+	response, err := ctx.resolver.GetSpendingPolicy(ctx.userAddress)
+	require.Nil(t, err)
+
+	return response
+}
+
+func createEGLDTx(receiver string, value string) transaction.FrontendTransaction {
+	return transaction.FrontendTransaction{
+		Sender:   usrAddr,
+		Receiver: receiver,
+		Value:    value,
+	}
+}
+
+func createESDTTx(receiver string, token string, amount string) transaction.FrontendTransaction {
+	return transaction.FrontendTransaction{
+		Sender:   usrAddr,
+		Receiver: receiver,
+		Value:    "0",
+		Data:     []byte("ESDTTransfer@" + hex.EncodeToString([]byte(token)) + "@" + amount),
+	}
+}
+
+func TestServiceResolver_SetSpendingPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid policy should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createSpendingPolicyTestContext(t)
+
+		err := ctx.setPolicy([]requests.SpendingLimit{{Token: "", Daily: "1"}}, nil)
+		assert.True(t, errors.Is(err, ErrInvalidSpendingPolicy))
+		assert.True(t, strings.Contains(err.Error(), "empty token"))
+
+		err = ctx.setPolicy([]requests.SpendingLimit{{Token: "EGLD", Daily: "1"}, {Token: "EGLD", Weekly: "1"}}, nil)
+		assert.True(t, errors.Is(err, ErrInvalidSpendingPolicy))
+		assert.True(t, strings.Contains(err.Error(), "duplicated token"))
+
+		err = ctx.setPolicy([]requests.SpendingLimit{{Token: "EGLD"}}, nil)
+		assert.True(t, errors.Is(err, ErrInvalidSpendingPolicy))
+		assert.True(t, strings.Contains(err.Error(), "no limit"))
+
+		err = ctx.setPolicy([]requests.SpendingLimit{{Token: "EGLD", Weekly: "-1"}}, nil)
+		assert.True(t, errors.Is(err, ErrInvalidSpendingPolicy))
+		assert.True(t, strings.Contains(err.Error(), "weekly limit"))
+
+		err = ctx.setPolicy(nil, make([]string, maxTrustedReceivers+1))
+		assert.True(t, errors.Is(err, ErrInvalidSpendingPolicy))
+		assert.True(t, strings.Contains(err.Error(), "too many trusted receivers"))
+
+		assert.Equal(t, 0, ctx.numPuts)
+	})
+	t.Run("wrong code should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createSpendingPolicyTestContext(t)
+		ctx.resolver.totpHandler = &testscommon.TOTPHandlerStub{
+			TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
+				return &testscommon.TotpStub{
+					ValidateCalled: func(userCode string) error {
+						return expectedErr
+					},
+				}, nil
+			},
+		}
+
+		err := ctx.setPolicy([]requests.SpendingLimit{{Token: "EGLD", Daily: "1"}}, nil)
+		assert.Equal(t, expectedErr, err)
+		assert.Equal(t, 0, ctx.numPuts)
+	})
+	t.Run("guardian not usable should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createSpendingPolicyTestContext(t)
+		userInfo := *providedUserInfo
+		userInfo.FirstGuardian.State = core.NotUsable
+		err := ctx.resolver.marshalAndSaveEncrypted(ctx.userAddress.AddressBytes(), &userInfo)
+		require.Nil(t, err)
+		ctx.numPuts = 0
+
+		err = ctx.setPolicy([]requests.SpendingLimit{{Token: "EGLD", Daily: "1"}}, nil)
+		assert.True(t, errors.Is(err, ErrGuardianNotUsable))
+		// only the accepted code is saved
+		assert.Equal(t, 1, ctx.numPuts)
+		savedUserInfo, err := ctx.resolver.getUserInfo(ctx.userAddress.AddressBytes())
+		require.Nil(t, err)
+		assert.Empty(t, savedUserInfo.SpendingData)
+	})
+	t.Run("first policy should apply right away", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createSpendingPolicyTestContext(t)
+
+		limits := []requests.SpendingLimit{{Token: "EGLD", Daily: "100", Weekly: "500"}}
+		err := ctx.setPolicy(limits, []string{trustedReceiver})
+		require.Nil(t, err)
+
+		response := ctx.getPolicy(t)
+		assert.Equal(t, limits, response.Active.Limits)
+		assert.Equal(t, []string{trustedReceiver}, response.Active.TrustedReceivers)
+		assert.Nil(t, response.Pending)
+	})
+	t.Run("looser policy should apply after the delay", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createSpendingPolicyTestContext(t)
+
+		strictLimits := []requests.SpendingLimit{{Token: "EGLD", Daily: "100"}}
+		err := ctx.setPolicy(strictLimits, nil)
+		require.Nil(t, err)
+
+		looserLimits := []requests.SpendingLimit{{Token: "EGLD", Daily: "1000"}}
+		err = ctx.setPolicy(looserLimits, []string{trustedReceiver})
+		require.Nil(t, err)
+
+		response := ctx.getPolicy(t)
+		assert.Equal(t, strictLimits, response.Active.Limits)
+		require.NotNil(t, response.Pending)
+		assert.Equal(t, looserLimits, response.Pending.Limits)
+		assert.Equal(t, ctx.currentTime.Unix()+secondsInDay, response.Pending.ActivationTimestamp)
+
+		ctx.currentTime = ctx.currentTime.Add(secondsInDay * time.Second)
+		response = ctx.getPolicy(t)
+		assert.Equal(t, looserLimits, response.Active.Limits)
+		assert.Equal(t, []string{trustedReceiver}, response.Active.TrustedReceivers)
+		assert.Nil(t, response.Pending)
+	})
+	t.Run("stricter policy should apply right away and drop the pending one", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createSpendingPolicyTestContext(t)
+
+		err := ctx.setPolicy([]requests.SpendingLimit{{Token: "EGLD", Daily: "100"}}, []string{trustedReceiver})
+		require.Nil(t, err)
+		err = ctx.setPolicy(nil, nil)
+		require.Nil(t, err)
+		require.NotNil(t, ctx.getPolicy(t).Pending)
+
+		stricterLimits := []requests.SpendingLimit{{Token: "EGLD", Daily: "50"}, {Token: "TKN-123456", Weekly: "10"}}
+		err = ctx.setPolicy(stricterLimits, nil)
+		require.Nil(t, err)
+
+		response := ctx.getPolicy(t)
+		assert.Equal(t, stricterLimits, response.Active.Limits)
+		assert.Empty(t, response.Active.TrustedReceivers)
+		assert.Nil(t, response.Pending)
+	})
+}
+
+func TestServiceResolver_GetSpendingPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("get user info error should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
+			GetCalled: func(key []byte) ([]byte, error) {
+				return nil, expectedErr
+			},
+		}
+		resolver, _ := NewServiceResolver(args)
+		userAddress, _ := sdkData.NewAddressFromBech32String(usrAddr)
+
+		response, err := resolver.GetSpendingPolicy(userAddress)
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, response)
+	})
+	t.Run("no policy should return empty response", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createSpendingPolicyTestContext(t)
+
+		response := ctx.getPolicy(t)
+		assert.Empty(t, response.Active.Limits)
+		assert.Empty(t, response.Active.TrustedReceivers)
+		assert.Nil(t, response.Pending)
+		assert.Empty(t, response.Spent)
+	})
+}
+
+func TestServiceResolver_RecordSignedTransactions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no policy should not save anything", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createSpendingPolicyTestContext(t)
+
+		err := ctx.resolver.recordSignedTransactions([]transaction.FrontendTransaction{createEGLDTx(untrustedReceiver, "1000")}, false)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, ctx.numPuts)
+	})
+	t.Run("invalid transaction should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createSpendingPolicyTestContext(t)
+		err := ctx.setPolicy([]requests.SpendingLimit{{Token: "EGLD", Daily: "100"}}, nil)
+		require.Nil(t, err)
+
+		tx := createEGLDTx(untrustedReceiver, "0")
+		tx.Data = []byte("ESDTTransfer@zz@01")
+		err = ctx.resolver.recordSignedTransactions([]transaction.FrontendTransaction{tx}, false)
+		assert.True(t, errors.Is(err, txdecoder.ErrInvalidDataField))
+	})
+	t.Run("daily limit should apply on the cumulated value", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createSpendingPolicyTestContext(t)
+		err := ctx.setPolicy([]requests.SpendingLimit{{Token: "EGLD", Daily: "100", Weekly: "250"}}, []string{trustedReceiver})
+		require.Nil(t, err)
+
+		txs := []transaction.FrontendTransaction{createEGLDTx(untrustedReceiver, "60"), createEGLDTx(trustedReceiver, "1000")}
+		err = ctx.resolver.recordSignedTransactions(txs, false)
+		require.Nil(t, err)
+
+		err = ctx.resolver.recordSignedTransactions([]transaction.FrontendTransaction{createEGLDTx(untrustedReceiver, "41")}, false)
+		assert.True(t, errors.Is(err, ErrSpendingLimitExceeded))
+		assert.True(t, strings.Contains(err.Error(), "daily limit"))
+
+		err = ctx.resolver.recordSignedTransactions([]transaction.FrontendTransaction{createEGLDTx(untrustedReceiver, "40")}, false)
+		require.Nil(t, err)
+
+		expectedSpent := []requests.SpentAmount{{Token: txdecoder.EGLDToken, DailySpent: "100", WeeklySpent: "100"}}
+		assert.Equal(t, expectedSpent, ctx.getPolicy(t).Spent)
+
+		ctx.currentTime = ctx.currentTime.Add(secondsInDay * time.Second)
+		expectedSpent = []requests.SpentAmount{{Token: txdecoder.EGLDToken, DailySpent: "", WeeklySpent: "100"}}
+		assert.Equal(t, expectedSpent, ctx.getPolicy(t).Spent)
+
+		err = ctx.resolver.recordSignedTransactions([]transaction.FrontendTransaction{createEGLDTx(untrustedReceiver, "100")}, false)
+		require.Nil(t, err)
+
+		ctx.currentTime = ctx.currentTime.Add(secondsInDay * time.Second)
+		err = ctx.resolver.recordSignedTransactions([]transaction.FrontendTransaction{createEGLDTx(untrustedReceiver, "51")}, false)
+		assert.True(t, errors.Is(err, ErrSpendingLimitExceeded))
+		assert.True(t, strings.Contains(err.Error(), "weekly limit"))
+
+		ctx.currentTime = ctx.currentTime.Add(secondsInWeek * time.Second)
+		err = ctx.resolver.recordSignedTransactions([]transaction.FrontendTransaction{createEGLDTx(untrustedReceiver, "100")}, false)
+		require.Nil(t, err)
+	})
+	t.Run("ESDT limits should apply per token", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createSpendingPolicyTestContext(t)
+		err := ctx.setPolicy([]requests.SpendingLimit{{Token: "TKN-123456", Daily: "16"}}, nil)
+		require.Nil(t, err)
+
+		txs := []transaction.FrontendTransaction{
+			createESDTTx(untrustedReceiver, "TKN-123456", "10"),
+			createESDTTx(untrustedReceiver, "OTHER-123456", "ff"),
+			createEGLDTx(untrustedReceiver, "1000"),
+		}
+		err = ctx.resolver.recordSignedTransactions(txs, false)
+		require.Nil(t, err)
+
+		err = ctx.resolver.recordSignedTransactions([]transaction.FrontendTransaction{createESDTTx(untrustedReceiver, "TKN-123456", "01")}, false)
+		assert.True(t, errors.Is(err, ErrSpendingLimitExceeded))
+
+		expectedSpent := []requests.SpentAmount{{Token: "TKN-123456", DailySpent: "16", WeeklySpent: "16"}}
+		assert.Equal(t, expectedSpent, ctx.getPolicy(t).Spent)
+	})
+}
+
+func TestServiceResolver_CheckSpending(t *testing.T) {
+	t.Parallel()
+
+	t.Run("limit exceeded should not save anything", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createSpendingPolicyTestContext(t)
+		err := ctx.setPolicy([]requests.SpendingLimit{{Token: "EGLD", Daily: "100"}}, nil)
+		require.Nil(t, err)
+		ctx.numPuts = 0
+
+		err = ctx.resolver.checkSpending(ctx.userAddress.AddressBytes(), []transaction.FrontendTransaction{createEGLDTx(untrustedReceiver, "100")})
+		assert.Nil(t, err)
+		err = ctx.resolver.checkSpending(ctx.userAddress.AddressBytes(), []transaction.FrontendTransaction{createEGLDTx(untrustedReceiver, "101")})
+		assert.True(t, errors.Is(err, ErrSpendingLimitExceeded))
+		assert.Equal(t, 0, ctx.numPuts)
+		assert.Empty(t, ctx.getPolicy(t).Spent)
+	})
+	t.Run("limit exceeded should reject the request before verifying the code", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createSpendingPolicyTestContext(t)
+		err := ctx.setPolicy([]requests.SpendingLimit{{Token: "EGLD", Daily: "100"}}, nil)
+		require.Nil(t, err)
+		ctx.resolver.secureOtpHandler = &testscommon.SecureOtpHandlerStub{
+			IsVerificationAllowedAndIncreaseTrialsCalled: func(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+				assert.Fail(t, "should have not been called")
+				return nil, nil
+			},
+		}
+
+		tx := createEGLDTx(untrustedReceiver, "101")
+		tx.Signature = hex.EncodeToString([]byte("signature"))
+		tx.GuardianAddr = string(providedUserInfo.FirstGuardian.PublicKey)
+		request := requests.SignTransaction{
+			Code: defaultFirstCode,
+			Tx:   tx,
+		}
+		txBytes, _, err := ctx.resolver.SignTransaction("userIp", request)
+		assert.True(t, errors.Is(err, ErrSpendingLimitExceeded))
+		assert.Nil(t, txBytes)
+	})
+}
+
+func TestIsStricterSpendingPolicy(t *testing.T) {
+	t.Parallel()
+
+	activePolicy := &core.SpendingPolicy{
+		Limits: []core.SpendingLimit{
+			{Token: "EGLD", Daily: "100"},
+			{Token: "TKN-123456", Daily: "10", Weekly: "50"},
+		},
+		TrustedReceivers: [][]byte{[]byte("receiver1"), []byte("receiver2")},
+	}
+
+	tests := []struct {
+		name      string
+		newPolicy *core.SpendingPolicy
+		expected  bool
+	}{
+		{
+			name: "same policy",
+			newPolicy: &core.SpendingPolicy{
+				Limits:           activePolicy.Limits,
+				TrustedReceivers: activePolicy.TrustedReceivers,
+			},
+			expected: true,
+		},
+		{
+			name: "lower limits, new token, fewer receivers",
+			newPolicy: &core.SpendingPolicy{
+				Limits: []core.SpendingLimit{
+					{Token: "EGLD", Daily: "99", Weekly: "1000"},
+					{Token: "TKN-123456", Daily: "10", Weekly: "1"},
+					{Token: "NEW-123456", Daily: "1"},
+				},
+				TrustedReceivers: [][]byte{[]byte("receiver2")},
+			},
+			expected: true,
+		},
+		{
+			name: "removed token",
+			newPolicy: &core.SpendingPolicy{
+				Limits: []core.SpendingLimit{{Token: "EGLD", Daily: "100"}},
+			},
+			expected: false,
+		},
+		{
+			name: "removed weekly limit",
+			newPolicy: &core.SpendingPolicy{
+				Limits: []core.SpendingLimit{
+					{Token: "EGLD", Daily: "100"},
+					{Token: "TKN-123456", Daily: "10"},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "higher daily limit",
+			newPolicy: &core.SpendingPolicy{
+				Limits: []core.SpendingLimit{
+					{Token: "EGLD", Daily: "101"},
+					{Token: "TKN-123456", Daily: "10", Weekly: "50"},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "new trusted receiver",
+			newPolicy: &core.SpendingPolicy{
+				Limits:           activePolicy.Limits,
+				TrustedReceivers: [][]byte{[]byte("receiver3")},
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, isStricterSpendingPolicy(activePolicy, tt.newPolicy))
+		})
+	}
+
+	t.Run("any policy is stricter than no limits", func(t *testing.T) {
+		noLimitsPolicy := &core.SpendingPolicy{
+			TrustedReceivers: [][]byte{[]byte("receiver1")},
+		}
+		newPolicy := &core.SpendingPolicy{
+			TrustedReceivers: [][]byte{[]byte("receiver3")},
+		}
+		require.True(t, isStricterSpendingPolicy(noLimitsPolicy, newPolicy))
+	})
+}
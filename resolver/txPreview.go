@@ -0,0 +1,193 @@
+package resolver
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	chainCore "github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/txdecoder"
+)
+
+const setGuardianNumArgs = 2
+
+// PreviewTransactions validates the transactions and checks them against the spending limits of the user the same
+// way as for signing, returning a human-readable summary of each of them, along with the guardian which would sign
+// them and the amounts spent once signed. No code is required and no verification trial is consumed
+func (resolver *serviceResolver) PreviewTransactions(request requests.SignMultipleTransactions) (*requests.PreviewTransactionsResponse, error) {
+	userAddress, confirmationRequired, err := resolver.checkTransactionsPolicy(request.Txs)
+	if err != nil {
+		return nil, err
+	}
+
+	addressBytes := userAddress.AddressBytes()
+	resolver.userCritSection.RLock(string(addressBytes))
+	userInfo, err := resolver.getUserInfo(addressBytes)
+	resolver.userCritSection.RUnlock(string(addressBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	// the activation state is not reconciled here, as it would write the user
+	guardian, err := resolver.getUserGuardian(request.Txs[0].GuardianAddr, userInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	guardianStatus, err := resolver.createGuardianStatus(*guardian)
+	if err != nil {
+		return nil, err
+	}
+
+	spending, err := resolver.previewSpending(userInfo, request.Txs)
+	if err != nil {
+		return nil, err
+	}
+
+	trustedReceivers := make(map[string]struct{}, len(spending.Active.TrustedReceivers))
+	for _, receiver := range spending.Active.TrustedReceivers {
+		trustedReceivers[string(receiver)] = struct{}{}
+	}
+
+	summaries := make([]requests.TransactionSummary, 0, len(request.Txs))
+	for index, tx := range request.Txs {
+		summary, errSummary := resolver.createTransactionSummary(tx, trustedReceivers)
+		if errSummary != nil {
+			return nil, fmt.Errorf("%w for transaction #%d", errSummary, index)
+		}
+
+		summaries = append(summaries, *summary)
+	}
+
+	spentAfterSigning := make([]requests.SpentAmount, 0, len(spending.Spent))
+	for _, spentAmount := range spending.Spent {
+		spentAfterSigning = append(spentAfterSigning, requests.SpentAmount{
+			Token:       spentAmount.Token,
+			DailySpent:  spentAmount.DailySpent,
+			WeeklySpent: spentAmount.WeeklySpent,
+		})
+	}
+
+	return &requests.PreviewTransactionsResponse{
+		Guardian:             guardianStatus,
+		ConfirmationRequired: confirmationRequired,
+		Transactions:         summaries,
+		SpendingLimits:       resolver.convertSpendingPolicy(&spending.Active).Limits,
+		SpentAfterSigning:    spentAfterSigning,
+	}, nil
+}
+
+// previewSpending checks the transactions against the spending limits of the user, the same as when signing, and
+// returns the spending of the user updated with the transactions. Nothing is saved
+func (resolver *serviceResolver) previewSpending(userInfo *core.UserInfo, txs []transaction.FrontendTransaction) (*core.UserSpending, error) {
+	spending, err := resolver.getUserSpending(userInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	currentTimestamp := resolver.getTimeHandler().Unix()
+	activatePendingSpendingPolicy(spending, currentTimestamp)
+	if len(spending.Active.Limits) == 0 {
+		spending.Spent = nil
+		return spending, nil
+	}
+
+	err = resolver.addTransactionsToSpending(spending, txs, currentTimestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	return spending, nil
+}
+
+func (resolver *serviceResolver) createTransactionSummary(tx transaction.FrontendTransaction, trustedReceivers map[string]struct{}) (*requests.TransactionSummary, error) {
+	decodedTx, err := resolver.txDecoder.Decode(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	receiver, err := resolver.pubKeyConverter.Encode(decodedTx.Receiver)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &requests.TransactionSummary{
+		Nonce:           tx.Nonce,
+		Sender:          tx.Sender,
+		Receiver:        receiver,
+		Type:            string(getTransactionType(tx, decodedTx)),
+		BuiltInFunction: decodedTx.BuiltInFunction,
+		Transfers:       make([]requests.TokenTransfer, 0, len(decodedTx.Transfers)),
+		MaxFee:          big.NewInt(0).Mul(big.NewInt(0).SetUint64(tx.GasLimit), big.NewInt(0).SetUint64(tx.GasPrice)).String(),
+	}
+	_, summary.TrustedReceiver = trustedReceivers[string(decodedTx.Receiver)]
+	for _, transfer := range decodedTx.Transfers {
+		summary.Transfers = append(summary.Transfers, requests.TokenTransfer{
+			Token:  transfer.Token,
+			Nonce:  transfer.Nonce,
+			Amount: transfer.Amount.String(),
+		})
+	}
+
+	// the data field of a simple transfer is only a note, so it is not reported as a function call
+	hasFunctionCall := len(decodedTx.Function) > 0 && summary.Type != string(core.EGLDTransferTransaction)
+	if hasFunctionCall {
+		summary.Function = decodedTx.Function
+		summary.Arguments = decodedTx.Arguments
+	}
+
+	if decodedTx.Function == chainCore.BuiltInFunctionSetGuardian {
+		resolver.addNewGuardianToSummary(summary, decodedTx.Arguments)
+	}
+
+	return summary, nil
+}
+
+// addNewGuardianToSummary decodes the guardian and the service uid set by SetGuardian. The arguments which
+// cannot be decoded are only reported in their hex form, as the transaction would fail on chain anyway
+func (resolver *serviceResolver) addNewGuardianToSummary(summary *requests.TransactionSummary, args []string) {
+	if len(args) < setGuardianNumArgs {
+		return
+	}
+
+	guardian, err := hex.DecodeString(args[0])
+	if err == nil {
+		summary.NewGuardian, _ = resolver.pubKeyConverter.Encode(guardian)
+	}
+
+	serviceUID, err := hex.DecodeString(args[1])
+	if err == nil {
+		summary.NewGuardianServiceUID = string(serviceUID)
+	}
+}
+
+func getTransactionType(tx transaction.FrontendTransaction, decodedTx *txdecoder.DecodedTransaction) core.TransactionType {
+	switch decodedTx.BuiltInFunction {
+	case chainCore.BuiltInFunctionESDTTransfer:
+		return core.ESDTTransferTransaction
+	case chainCore.BuiltInFunctionESDTNFTTransfer:
+		return core.NFTTransferTransaction
+	case chainCore.BuiltInFunctionMultiESDTNFTTransfer:
+		return core.MultiTokenTransferTransaction
+	}
+
+	switch decodedTx.Function {
+	case "":
+		return core.EGLDTransferTransaction
+	case chainCore.BuiltInFunctionSetGuardian, chainCore.BuiltInFunctionGuardAccount, chainCore.BuiltInFunctionUnGuardAccount:
+		return core.GuardianOperationTransaction
+	}
+
+	if chainCore.IsSmartContractAddress(decodedTx.Receiver) {
+		return core.SmartContractCallTransaction
+	}
+	if tx.Receiver == tx.Sender {
+		return core.BuiltInFunctionCallTransaction
+	}
+
+	return core.EGLDTransferTransaction
+}
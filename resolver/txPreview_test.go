@@ -0,0 +1,321 @@
+package resolver
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/data/api"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/txdecoder"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+func createPreviewTestContext(t *testing.T) *webAuthnTestContext {
+	ctx := createActivationTestContext(t, core.Active, core.Verified, nil)
+	ctx.resolver.txDecoder, _ = txdecoder.NewTxDecoder(ctx.resolver.pubKeyConverter)
+	ctx.resolver.httpClientWrapper = &testscommon.HttpClientWrapperStub{
+		GetFreshGuardianDataCalled: func(_ context.Context, address string) (*api.GuardianData, error) {
+			assert.Fail(t, "should have not been called")
+			return nil, nil
+		},
+	}
+	ctx.resolver.secureOtpHandler = &testscommon.SecureOtpHandlerStub{
+		IsVerificationAllowedAndIncreaseTrialsCalled: func(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+			assert.Fail(t, "should have not been called")
+			return nil, nil
+		},
+	}
+
+	return ctx
+}
+
+func setPreviewSpendingPolicy(t *testing.T, ctx *webAuthnTestContext, policy core.SpendingPolicy) {
+	userInfo := getActivationTestUserInfo(t, ctx)
+	var err error
+	userInfo.SpendingData, err = ctx.resolver.userDataMarshaller.Marshal(&core.UserSpending{Active: policy})
+	require.Nil(t, err)
+
+	err = ctx.resolver.marshalAndSaveEncrypted(ctx.userAddress.AddressBytes(), userInfo)
+	require.Nil(t, err)
+}
+
+func createPreviewTx(guardian []byte, receiver string, value string, data string) transaction.FrontendTransaction {
+	return transaction.FrontendTransaction{
+		Nonce:        1,
+		Value:        value,
+		Receiver:     receiver,
+		Sender:       usrAddr,
+		GasPrice:     1000000000,
+		GasLimit:     50000,
+		Data:         []byte(data),
+		Signature:    hex.EncodeToString([]byte("signature")),
+		GuardianAddr: string(guardian),
+	}
+}
+
+func TestServiceResolver_PreviewTransactions(t *testing.T) {
+	t.Parallel()
+
+	firstGuardian := providedUserInfo.FirstGuardian.PublicKey
+	secondGuardian := providedUserInfo.SecondGuardian.PublicKey
+	providedReceiver := "receiver"
+	t.Run("no transaction should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createPreviewTestContext(t)
+		response, err := ctx.resolver.PreviewTransactions(requests.SignMultipleTransactions{})
+		assert.Equal(t, ErrNoTransactionToSign, err)
+		assert.Nil(t, response)
+	})
+	t.Run("guardian mismatch should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createPreviewTestContext(t)
+		response, err := ctx.resolver.PreviewTransactions(requests.SignMultipleTransactions{
+			Txs: []transaction.FrontendTransaction{
+				createPreviewTx(firstGuardian, providedReceiver, "1", ""),
+				createPreviewTx(secondGuardian, providedReceiver, "1", ""),
+			},
+		})
+		assert.Equal(t, ErrGuardianMismatch, err)
+		assert.Nil(t, response)
+	})
+	t.Run("transaction policy fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createPreviewTestContext(t)
+		ctx.resolver.txPolicyHandler = &testscommon.TxPolicyHandlerStub{
+			CheckTransactionsCalled: func(userAddress string, txs []transaction.FrontendTransaction) (bool, error) {
+				return false, expectedErr
+			},
+		}
+		response, err := ctx.resolver.PreviewTransactions(requests.SignMultipleTransactions{
+			Txs: []transaction.FrontendTransaction{createPreviewTx(firstGuardian, providedReceiver, "1", "")},
+		})
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, response)
+	})
+	t.Run("get user info fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createPreviewTestContext(t)
+		ctx.resolver.registeredUsersDB = &testscommon.ShardedStorageWithIndexStub{
+			GetCalled: func(key []byte) ([]byte, error) {
+				return nil, expectedErr
+			},
+		}
+		response, err := ctx.resolver.PreviewTransactions(requests.SignMultipleTransactions{
+			Txs: []transaction.FrontendTransaction{createPreviewTx(firstGuardian, providedReceiver, "1", "")},
+		})
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, response)
+	})
+	t.Run("unknown guardian should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createPreviewTestContext(t)
+		response, err := ctx.resolver.PreviewTransactions(requests.SignMultipleTransactions{
+			Txs: []transaction.FrontendTransaction{createPreviewTx([]byte("unknown guardian"), providedReceiver, "1", "")},
+		})
+		assert.True(t, errors.Is(err, ErrInvalidGuardian))
+		assert.Nil(t, response)
+	})
+	t.Run("invalid data field should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createPreviewTestContext(t)
+		response, err := ctx.resolver.PreviewTransactions(requests.SignMultipleTransactions{
+			Txs: []transaction.FrontendTransaction{
+				createPreviewTx(firstGuardian, providedReceiver, "1", ""),
+				createPreviewTx(firstGuardian, providedReceiver, "0", "ESDTTransfer@zz@01"),
+			},
+		})
+		assert.True(t, errors.Is(err, txdecoder.ErrInvalidDataField))
+		assert.Contains(t, err.Error(), "for transaction #1")
+		assert.Nil(t, response)
+	})
+	t.Run("spending limit exceeded should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createPreviewTestContext(t)
+		setPreviewSpendingPolicy(t, ctx, core.SpendingPolicy{
+			Limits: []core.SpendingLimit{{Token: txdecoder.EGLDToken, Daily: "100"}},
+		})
+		response, err := ctx.resolver.PreviewTransactions(requests.SignMultipleTransactions{
+			Txs: []transaction.FrontendTransaction{
+				createPreviewTx(firstGuardian, providedReceiver, "60", ""),
+				createPreviewTx(firstGuardian, providedReceiver, "41", ""),
+			},
+		})
+		assert.True(t, errors.Is(err, ErrSpendingLimitExceeded))
+		assert.Contains(t, err.Error(), "for transaction #1")
+		assert.Nil(t, response)
+	})
+	t.Run("should report the spending limits and the trusted receivers", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createPreviewTestContext(t)
+		trustedReceiver := "trusted receiver"
+		setPreviewSpendingPolicy(t, ctx, core.SpendingPolicy{
+			Limits: []core.SpendingLimit{
+				{Token: txdecoder.EGLDToken, Daily: "100", Weekly: "500"},
+				{Token: "TKN-123456", Daily: "10"},
+			},
+			TrustedReceivers: [][]byte{[]byte(trustedReceiver)},
+		})
+		dbBefore := make(map[string][]byte, len(ctx.db))
+		for key, val := range ctx.db {
+			dbBefore[key] = val
+		}
+
+		response, err := ctx.resolver.PreviewTransactions(requests.SignMultipleTransactions{
+			Txs: []transaction.FrontendTransaction{
+				createPreviewTx(firstGuardian, providedReceiver, "60", ""),
+				createPreviewTx(firstGuardian, trustedReceiver, "1000", ""),
+				createPreviewTx(firstGuardian, providedReceiver, "0", "ESDTTransfer@"+hex.EncodeToString([]byte("OTHER-123456"))+"@0a"),
+			},
+		})
+		require.Nil(t, err)
+
+		require.Equal(t, 3, len(response.Transactions))
+		assert.False(t, response.Transactions[0].TrustedReceiver)
+		assert.True(t, response.Transactions[1].TrustedReceiver)
+		assert.False(t, response.Transactions[2].TrustedReceiver)
+		expectedLimits := []requests.SpendingLimit{
+			{Token: txdecoder.EGLDToken, Daily: "100", Weekly: "500"},
+			{Token: "TKN-123456", Daily: "10"},
+		}
+		assert.Equal(t, expectedLimits, response.SpendingLimits)
+		expectedSpent := []requests.SpentAmount{{Token: txdecoder.EGLDToken, DailySpent: "60", WeeklySpent: "60"}}
+		assert.Equal(t, expectedSpent, response.SpentAfterSigning)
+
+		// the previewed transactions are not recorded
+		assert.Equal(t, dbBefore, ctx.db)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createPreviewTestContext(t)
+		ctx.resolver.txPolicyHandler = &testscommon.TxPolicyHandlerStub{
+			CheckTransactionsCalled: func(userAddress string, txs []transaction.FrontendTransaction) (bool, error) {
+				return true, nil
+			},
+			CheckAndRecordTransactionsCalled: func(userAddress string, txs []transaction.FrontendTransaction, isConfirmed bool) error {
+				assert.Fail(t, "should have not been called")
+				return nil
+			},
+		}
+		dbBefore := make(map[string][]byte, len(ctx.db))
+		for key, val := range ctx.db {
+			dbBefore[key] = val
+		}
+
+		token := hex.EncodeToString([]byte("TKN-123456"))
+		nft := hex.EncodeToString([]byte("NFT-123456"))
+		finalReceiver := hex.EncodeToString([]byte("final receiver"))
+		scAddress := string(append(make([]byte, 31), 1))
+		request := requests.SignMultipleTransactions{
+			Txs: []transaction.FrontendTransaction{
+				createPreviewTx(secondGuardian, providedReceiver, "100", "a note"),
+				createPreviewTx(secondGuardian, providedReceiver, "0", "ESDTTransfer@"+token+"@0a"),
+				createPreviewTx(secondGuardian, usrAddr, "0", "ESDTNFTTransfer@"+nft+"@02@01@"+finalReceiver),
+				createPreviewTx(secondGuardian, usrAddr, "0", "MultiESDTNFTTransfer@"+finalReceiver+"@02@"+token+"@@05@"+nft+"@03@01@"+hex.EncodeToString([]byte("buy"))+"@0b"),
+				createPreviewTx(secondGuardian, scAddress, "7", "swap@0a"),
+				createPreviewTx(secondGuardian, usrAddr, "0", "ESDTLocalMint@"+token+"@0a"),
+				createPreviewTx(secondGuardian, usrAddr, "0", "SetGuardian@"+hex.EncodeToString(firstGuardian)+"@"+hex.EncodeToString([]byte("MultiversXTCSService"))),
+				createPreviewTx(secondGuardian, usrAddr, "0", "GuardAccount"),
+			},
+		}
+
+		response, err := ctx.resolver.PreviewTransactions(request)
+		require.Nil(t, err)
+
+		assert.Equal(t, string(secondGuardian), response.Guardian.Address)
+		assert.False(t, response.Guardian.Usable)
+		assert.Equal(t, core.Verified.String(), response.Guardian.ActivationState)
+		assert.Equal(t, providedVerifiedTimestamp, response.Guardian.VerifiedTimestamp)
+		assert.True(t, response.ConfirmationRequired)
+
+		expectedTransactions := []requests.TransactionSummary{
+			{
+				Receiver:  providedReceiver,
+				Type:      string(core.EGLDTransferTransaction),
+				Transfers: []requests.TokenTransfer{{Token: txdecoder.EGLDToken, Amount: "100"}},
+			},
+			{
+				Receiver:        providedReceiver,
+				Type:            string(core.ESDTTransferTransaction),
+				BuiltInFunction: "ESDTTransfer",
+				Transfers:       []requests.TokenTransfer{{Token: "TKN-123456", Amount: "10"}},
+			},
+			{
+				Receiver:        "final receiver",
+				Type:            string(core.NFTTransferTransaction),
+				BuiltInFunction: "ESDTNFTTransfer",
+				Transfers:       []requests.TokenTransfer{{Token: "NFT-123456", Nonce: 2, Amount: "1"}},
+			},
+			{
+				Receiver:        "final receiver",
+				Type:            string(core.MultiTokenTransferTransaction),
+				BuiltInFunction: "MultiESDTNFTTransfer",
+				Function:        "buy",
+				Arguments:       []string{"0b"},
+				Transfers: []requests.TokenTransfer{
+					{Token: "TKN-123456", Amount: "5"},
+					{Token: "NFT-123456", Nonce: 3, Amount: "1"},
+				},
+			},
+			{
+				Receiver:  scAddress,
+				Type:      string(core.SmartContractCallTransaction),
+				Function:  "swap",
+				Arguments: []string{"0a"},
+				Transfers: []requests.TokenTransfer{{Token: txdecoder.EGLDToken, Amount: "7"}},
+			},
+			{
+				Receiver:  usrAddr,
+				Type:      string(core.BuiltInFunctionCallTransaction),
+				Function:  "ESDTLocalMint",
+				Arguments: []string{token, "0a"},
+				Transfers: []requests.TokenTransfer{},
+			},
+			{
+				Receiver:              usrAddr,
+				Type:                  string(core.GuardianOperationTransaction),
+				Function:              "SetGuardian",
+				Arguments:             []string{hex.EncodeToString(firstGuardian), hex.EncodeToString([]byte("MultiversXTCSService"))},
+				Transfers:             []requests.TokenTransfer{},
+				NewGuardian:           string(firstGuardian),
+				NewGuardianServiceUID: "MultiversXTCSService",
+			},
+			{
+				Receiver:  usrAddr,
+				Type:      string(core.GuardianOperationTransaction),
+				Function:  "GuardAccount",
+				Arguments: []string{},
+				Transfers: []requests.TokenTransfer{},
+			},
+		}
+		require.Equal(t, len(expectedTransactions), len(response.Transactions))
+		for i := range expectedTransactions {
+			expectedTransactions[i].Nonce = 1
+			expectedTransactions[i].Sender = usrAddr
+			expectedTransactions[i].MaxFee = "50000000000000"
+			assert.Equal(t, expectedTransactions[i], response.Transactions[i], "transaction #%d", i)
+		}
+
+		// a user without spending limits has nothing spent
+		assert.Empty(t, response.SpendingLimits)
+		assert.Empty(t, response.SpentAfterSigning)
+
+		// nothing is consumed, recorded or reconciled
+		assert.Equal(t, dbBefore, ctx.db)
+	})
+}
@@ -1,93 +1,187 @@
 package resolver
 
 import (
+	"encoding/binary"
+	"fmt"
+
 	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
 	"github.com/multiversx/mx-chain-core-go/core/check"
 )
 
+const (
+	firstGuardianPrivateKeyField  = "first-guardian-private-key"
+	firstGuardianOTPField         = "first-guardian-otp"
+	secondGuardianPrivateKeyField = "second-guardian-private-key"
+	secondGuardianOTPField        = "second-guardian-otp"
+	spendingDataField             = "spending-data"
+	recordField                   = "record"
+)
+
+type encryptedField struct {
+	name  string
+	value *[]byte
+}
+
 type userEncryptor struct {
-	encryptor Encryptor
+	encryptor           Encryptor
+	rejectLegacyRecords bool
 }
 
-// NewUserEncryptor creates a new instance of userEncryptor
-func NewUserEncryptor(encryptor Encryptor) (*userEncryptor, error) {
+// NewUserEncryptor creates a new instance of userEncryptor. When rejectLegacyRecords is set, only the records
+// of the current format, bound by their record tag, are decrypted, so a stored record cannot be downgraded
+func NewUserEncryptor(encryptor Encryptor, rejectLegacyRecords bool) (*userEncryptor, error) {
 	if check.IfNil(encryptor) {
 		return nil, ErrNilEncryptor
 	}
 
 	return &userEncryptor{
-		encryptor: encryptor,
+		encryptor:           encryptor,
+		rejectLegacyRecords: rejectLegacyRecords,
 	}, nil
 }
 
-// EncryptUserInfo encrypts the provided user info
-func (ue *userEncryptor) EncryptUserInfo(userInfo *core.UserInfo) (*core.UserInfo, error) {
+// EncryptUserInfo encrypts the provided user info with the current key, recording its id.
+// Each secret is bound to the user address and to its field, so it cannot be moved to another user or field,
+// while the record tag binds all the other fields of the record to the user address
+func (ue *userEncryptor) EncryptUserInfo(userAddress []byte, userInfo *core.UserInfo) (*core.UserInfo, error) {
 	if userInfo == nil {
 		return nil, ErrNilUserInfo
 	}
 
-	firstGuardianSk, err := ue.encryptor.EncryptData(userInfo.FirstGuardian.PrivateKey)
-	if err != nil {
-		return nil, err
-	}
-
-	otpFirstGuardian, err := ue.encryptor.EncryptData(userInfo.FirstGuardian.OTPData.OTP)
-	if err != nil {
-		return nil, err
+	encryptedUserInfo := *userInfo
+	encryptedUserInfo.EncryptionKeyID = ue.encryptor.CurrentKeyID()
+	encryptedUserInfo.EncryptionVersion = uint32(core.RecordAEADEncryption)
+	for _, field := range getEncryptedFields(&encryptedUserInfo) {
+		associatedData := createAssociatedData(userAddress, field.name, core.RecordAEADEncryption)
+		encryptedValue, err := ue.encryptor.EncryptData(*field.value, associatedData)
+		if err != nil {
+			return nil, err
+		}
+
+		*field.value = encryptedValue
 	}
 
-	secondGuardianSk, err := ue.encryptor.EncryptData(userInfo.SecondGuardian.PrivateKey)
+	recordAssociatedData, err := createRecordAssociatedData(userAddress, &encryptedUserInfo)
 	if err != nil {
 		return nil, err
 	}
 
-	otpSecondGuardian, err := ue.encryptor.EncryptData(userInfo.SecondGuardian.OTPData.OTP)
+	encryptedUserInfo.RecordTag, err = ue.encryptor.EncryptData(nil, recordAssociatedData)
 	if err != nil {
 		return nil, err
 	}
 
-	encryptedUserInfo := *userInfo
-	encryptedUserInfo.FirstGuardian.PrivateKey = firstGuardianSk
-	encryptedUserInfo.FirstGuardian.OTPData.OTP = otpFirstGuardian
-	encryptedUserInfo.SecondGuardian.PrivateKey = secondGuardianSk
-	encryptedUserInfo.SecondGuardian.OTPData.OTP = otpSecondGuardian
-
 	return &encryptedUserInfo, nil
 }
 
-// DecryptUserInfo decrypts the provided user info
-func (ue *userEncryptor) DecryptUserInfo(userInfo *core.UserInfo) (*core.UserInfo, error) {
+// DecryptUserInfo decrypts the provided user info with the key and the scheme it was encrypted with
+func (ue *userEncryptor) DecryptUserInfo(userAddress []byte, userInfo *core.UserInfo) (*core.UserInfo, error) {
 	if userInfo == nil {
 		return nil, ErrNilUserInfo
 	}
 
-	decryptedFirstGuardianSk, err := ue.encryptor.DecryptData(userInfo.FirstGuardian.PrivateKey)
-	if err != nil {
-		return nil, err
+	isRecordAEAD := core.EncryptionVersion(userInfo.EncryptionVersion) == core.RecordAEADEncryption
+	if !isRecordAEAD && ue.rejectLegacyRecords {
+		return nil, fmt.Errorf("%w, encryption version %d", ErrLegacyRecordRejected, userInfo.EncryptionVersion)
+	}
+	if isRecordAEAD {
+		err := ue.checkRecordTag(userAddress, userInfo)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	decryptedFirstGuardianOTP, err := ue.encryptor.DecryptData(userInfo.FirstGuardian.OTPData.OTP)
+	decryptedUserInfo := *userInfo
+	decryptedUserInfo.RecordTag = nil
+	for _, field := range getEncryptedFields(&decryptedUserInfo) {
+		decryptedValue, err := ue.decryptField(userAddress, userInfo, field)
+		if err != nil {
+			return nil, err
+		}
+
+		*field.value = decryptedValue
+	}
+
+	return &decryptedUserInfo, nil
+}
+
+// IsEncryptedWithCurrentKey returns true if the provided user info was encrypted with the current key and scheme
+func (ue *userEncryptor) IsEncryptedWithCurrentKey(userInfo *core.UserInfo) bool {
+	return userInfo.EncryptionKeyID == ue.encryptor.CurrentKeyID() &&
+		userInfo.EncryptionVersion == uint32(core.RecordAEADEncryption)
+}
+
+func (ue *userEncryptor) checkRecordTag(userAddress []byte, userInfo *core.UserInfo) error {
+	recordAssociatedData, err := createRecordAssociatedData(userAddress, userInfo)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	decryptedSecondGuardianSk, err := ue.encryptor.DecryptData(userInfo.SecondGuardian.PrivateKey)
+	_, err = ue.encryptor.DecryptData(userInfo.EncryptionKeyID, userInfo.RecordTag, recordAssociatedData)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("%w: %s", ErrInvalidRecordTag, err.Error())
 	}
 
-	decryptedSecondGuardianSkOTP, err := ue.encryptor.DecryptData(userInfo.SecondGuardian.OTPData.OTP)
+	return nil
+}
+
+func (ue *userEncryptor) decryptField(userAddress []byte, userInfo *core.UserInfo, field encryptedField) ([]byte, error) {
+	version := core.EncryptionVersion(userInfo.EncryptionVersion)
+	switch version {
+	case core.LegacyEncryption:
+		return ue.encryptor.DecryptLegacyData(userInfo.EncryptionKeyID, *field.value)
+	case core.AEADEncryption:
+		// the empty secrets were saved as they were in this version
+		if len(*field.value) == 0 {
+			return *field.value, nil
+		}
+		fallthrough
+	case core.RecordAEADEncryption:
+		return ue.encryptor.DecryptData(userInfo.EncryptionKeyID, *field.value, createAssociatedData(userAddress, field.name, version))
+	default:
+		return nil, fmt.Errorf("%w %d", ErrUnknownEncryptionVersion, userInfo.EncryptionVersion)
+	}
+}
+
+func getEncryptedFields(userInfo *core.UserInfo) []encryptedField {
+	return []encryptedField{
+		{name: firstGuardianPrivateKeyField, value: &userInfo.FirstGuardian.PrivateKey},
+		{name: firstGuardianOTPField, value: &userInfo.FirstGuardian.OTPData.OTP},
+		{name: secondGuardianPrivateKeyField, value: &userInfo.SecondGuardian.PrivateKey},
+		{name: secondGuardianOTPField, value: &userInfo.SecondGuardian.OTPData.OTP},
+		{name: spendingDataField, value: &userInfo.SpendingData},
+	}
+}
+
+// createAssociatedData returns the field name and the user address, separated by a zero byte. From RecordAEADEncryption
+// on, the version is appended as well, so a secret cannot be decrypted as part of a record of an older version
+func createAssociatedData(userAddress []byte, fieldName string, version core.EncryptionVersion) []byte {
+	associatedData := make([]byte, 0, len(fieldName)+1+len(userAddress)+4)
+	associatedData = append(associatedData, fieldName...)
+	associatedData = append(associatedData, 0)
+	associatedData = append(associatedData, userAddress...)
+	if version == core.AEADEncryption {
+		return associatedData
+	}
+
+	versionBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(versionBytes, uint32(version))
+
+	return append(associatedData, versionBytes...)
+}
+
+// createRecordAssociatedData returns the associated data of the record tag, which is the marshalled record,
+// without its tag, bound to the user address
+func createRecordAssociatedData(userAddress []byte, userInfo *core.UserInfo) ([]byte, error) {
+	record := *userInfo
+	record.RecordTag = nil
+	recordBytes, err := record.Marshal()
 	if err != nil {
 		return nil, err
 	}
 
-	decryptedUserInfo := *userInfo
-	decryptedUserInfo.FirstGuardian.PrivateKey = decryptedFirstGuardianSk
-	decryptedUserInfo.FirstGuardian.OTPData.OTP = decryptedFirstGuardianOTP
-	decryptedUserInfo.SecondGuardian.PrivateKey = decryptedSecondGuardianSk
-	decryptedUserInfo.SecondGuardian.OTPData.OTP = decryptedSecondGuardianSkOTP
-
-	return &decryptedUserInfo, nil
+	associatedData := createAssociatedData(userAddress, recordField, core.RecordAEADEncryption)
+	return append(associatedData, recordBytes...), nil
 }
 
 // IsInterfaceNil returns true if there is no value under the interface
@@ -3,22 +3,26 @@ package resolver
 import (
 	"bytes"
 	"errors"
+	"strings"
 	"testing"
 
+	factoryMarshaller "github.com/multiversx/mx-chain-core-go/marshal/factory"
+	crypto "github.com/multiversx/mx-chain-crypto-go"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/encryption"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
-	factoryMarshaller "github.com/multiversx/mx-chain-core-go/marshal/factory"
 	"github.com/stretchr/testify/require"
 )
 
+var testUserAddress = []byte("user address")
+
 func TestNewUserEncryptor(t *testing.T) {
 	t.Parallel()
 
 	t.Run("should return error when encryptor is nil", func(t *testing.T) {
 		t.Parallel()
 
-		ue, err := NewUserEncryptor(nil)
+		ue, err := NewUserEncryptor(nil, false)
 		require.Nil(t, ue)
 		require.Equal(t, ErrNilEncryptor, err)
 	})
@@ -26,7 +30,7 @@ func TestNewUserEncryptor(t *testing.T) {
 		t.Parallel()
 
 		encryptor := &testscommon.EncryptorStub{}
-		ue, err := NewUserEncryptor(encryptor)
+		ue, err := NewUserEncryptor(encryptor, false)
 		require.NotNil(t, ue)
 		require.Nil(t, err)
 	})
@@ -40,6 +44,7 @@ func TestUserEncryptor_EncryptUserInfo(t *testing.T) {
 	secondGuardianSk := []byte("secondGuardianSk")
 	firstGuardianOTP := []byte("firstGuardianOtp")
 	secondGuardianOTP := []byte("secondGuardianOtp")
+	spendingData := []byte("spendingData")
 	userInfo := &core.UserInfo{
 		FirstGuardian: core.GuardianInfo{
 			PublicKey:  []byte("firstGuardianPk"),
@@ -59,14 +64,15 @@ func TestUserEncryptor_EncryptUserInfo(t *testing.T) {
 				LastTOTPChangeTimestamp: 100,
 			},
 		},
+		SpendingData: spendingData,
 	}
 
 	t.Run("should return error when userInfo is nil", func(t *testing.T) {
 		t.Parallel()
 
-		encryptor, _ := encryption.NewEncryptor(testMarshaller, testKeygen, testSk)
-		ue, _ := NewUserEncryptor(encryptor)
-		encryptedUserInfo, err := ue.EncryptUserInfo(nil)
+		encryptor, _ := createTestKeyRing(testMarshaller, 0)
+		ue, _ := NewUserEncryptor(encryptor, false)
+		encryptedUserInfo, err := ue.EncryptUserInfo(testUserAddress, nil)
 		require.Nil(t, encryptedUserInfo)
 		require.Equal(t, ErrNilUserInfo, err)
 	})
@@ -75,16 +81,16 @@ func TestUserEncryptor_EncryptUserInfo(t *testing.T) {
 
 		expectedError := errors.New("expected error")
 		encryptor := &testscommon.EncryptorStub{
-			EncryptDataCalled: func(data []byte) ([]byte, error) {
+			EncryptDataCalled: func(data []byte, associatedData []byte) ([]byte, error) {
 				if bytes.Equal(data, firstGuardianSk) {
 					return nil, expectedError
 				}
 				return data, nil
 			},
 		}
-		ue, _ := NewUserEncryptor(encryptor)
+		ue, _ := NewUserEncryptor(encryptor, false)
 
-		encryptedUserInfo, err := ue.EncryptUserInfo(userInfo)
+		encryptedUserInfo, err := ue.EncryptUserInfo(testUserAddress, userInfo)
 		require.Nil(t, encryptedUserInfo)
 		require.Equal(t, expectedError, err)
 	})
@@ -93,16 +99,16 @@ func TestUserEncryptor_EncryptUserInfo(t *testing.T) {
 
 		expectedError := errors.New("expected error")
 		encryptor := &testscommon.EncryptorStub{
-			EncryptDataCalled: func(data []byte) ([]byte, error) {
+			EncryptDataCalled: func(data []byte, associatedData []byte) ([]byte, error) {
 				if bytes.Equal(data, secondGuardianSk) {
 					return nil, expectedError
 				}
 				return data, nil
 			},
 		}
-		ue, _ := NewUserEncryptor(encryptor)
+		ue, _ := NewUserEncryptor(encryptor, false)
 
-		encryptedUserInfo, err := ue.EncryptUserInfo(userInfo)
+		encryptedUserInfo, err := ue.EncryptUserInfo(testUserAddress, userInfo)
 		require.Nil(t, encryptedUserInfo)
 		require.Equal(t, expectedError, err)
 	})
@@ -111,16 +117,16 @@ func TestUserEncryptor_EncryptUserInfo(t *testing.T) {
 
 		expectedError := errors.New("expected error")
 		encryptor := &testscommon.EncryptorStub{
-			EncryptDataCalled: func(data []byte) ([]byte, error) {
+			EncryptDataCalled: func(data []byte, associatedData []byte) ([]byte, error) {
 				if bytes.Equal(data, firstGuardianOTP) {
 					return nil, expectedError
 				}
 				return data, nil
 			},
 		}
-		ue, _ := NewUserEncryptor(encryptor)
+		ue, _ := NewUserEncryptor(encryptor, false)
 
-		encryptedUserInfo, err := ue.EncryptUserInfo(userInfo)
+		encryptedUserInfo, err := ue.EncryptUserInfo(testUserAddress, userInfo)
 		require.Nil(t, encryptedUserInfo)
 		require.Equal(t, expectedError, err)
 	})
@@ -129,24 +135,42 @@ func TestUserEncryptor_EncryptUserInfo(t *testing.T) {
 
 		expectedError := errors.New("expected error")
 		encryptor := &testscommon.EncryptorStub{
-			EncryptDataCalled: func(data []byte) ([]byte, error) {
+			EncryptDataCalled: func(data []byte, associatedData []byte) ([]byte, error) {
 				if bytes.Equal(data, secondGuardianOTP) {
 					return nil, expectedError
 				}
 				return data, nil
 			},
 		}
-		ue, _ := NewUserEncryptor(encryptor)
+		ue, _ := NewUserEncryptor(encryptor, false)
 
-		encryptedUserInfo, err := ue.EncryptUserInfo(userInfo)
+		encryptedUserInfo, err := ue.EncryptUserInfo(testUserAddress, userInfo)
+		require.Nil(t, encryptedUserInfo)
+		require.Equal(t, expectedError, err)
+	})
+	t.Run("spending data encryption error should return error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedError := errors.New("expected error")
+		encryptor := &testscommon.EncryptorStub{
+			EncryptDataCalled: func(data []byte, associatedData []byte) ([]byte, error) {
+				if bytes.Equal(data, spendingData) {
+					return nil, expectedError
+				}
+				return data, nil
+			},
+		}
+		ue, _ := NewUserEncryptor(encryptor, false)
+
+		encryptedUserInfo, err := ue.EncryptUserInfo(testUserAddress, userInfo)
 		require.Nil(t, encryptedUserInfo)
 		require.Equal(t, expectedError, err)
 	})
 	t.Run("should work", func(t *testing.T) {
 		t.Parallel()
 
-		encryptor, _ := encryption.NewEncryptor(testMarshaller, testKeygen, testSk)
-		ue, _ := NewUserEncryptor(encryptor)
+		encryptor, _ := createTestKeyRing(testMarshaller, 0)
+		ue, _ := NewUserEncryptor(encryptor, false)
 		userInfo := &core.UserInfo{
 			FirstGuardian: core.GuardianInfo{
 				PublicKey:  []byte("firstGuardianPk"),
@@ -166,9 +190,10 @@ func TestUserEncryptor_EncryptUserInfo(t *testing.T) {
 					LastTOTPChangeTimestamp: 200,
 				},
 			},
-			Index: 1,
+			Index:        1,
+			SpendingData: []byte("spendingData"),
 		}
-		encryptedUserInfo, err := ue.EncryptUserInfo(userInfo)
+		encryptedUserInfo, err := ue.EncryptUserInfo(testUserAddress, userInfo)
 		require.NotNil(t, encryptedUserInfo)
 		require.Nil(t, err)
 		checkEncryptedUserInfo(t, userInfo, encryptedUserInfo)
@@ -183,6 +208,7 @@ func TestUserEncryptor_DecryptUserInfo(t *testing.T) {
 	secondGuardianSk := []byte("secondGuardianSk")
 	firstGuardianOTP := []byte("firstGuardianOtp")
 	secondGuardianOTP := []byte("secondGuardianOtp")
+	spendingData := []byte("spendingData")
 	userInfo := &core.UserInfo{
 		FirstGuardian: core.GuardianInfo{
 			PublicKey:  []byte("firstGuardianPk"),
@@ -202,91 +228,109 @@ func TestUserEncryptor_DecryptUserInfo(t *testing.T) {
 				LastTOTPChangeTimestamp: 100,
 			},
 		},
+		SpendingData: spendingData,
 	}
 
-	encryptor, err := encryption.NewEncryptor(testMarshaller, testKeygen, testSk)
+	encryptor, err := createTestKeyRing(testMarshaller, 0)
 	require.Nil(t, err)
 
 	t.Run("should return error when userInfo is nil", func(t *testing.T) {
 		t.Parallel()
 
-		ue, _ := NewUserEncryptor(&testscommon.EncryptorStub{})
-		decryptedUserInfo, err := ue.DecryptUserInfo(nil)
+		ue, _ := NewUserEncryptor(&testscommon.EncryptorStub{}, false)
+		decryptedUserInfo, err := ue.DecryptUserInfo(testUserAddress, nil)
 		require.Nil(t, decryptedUserInfo)
 		require.Equal(t, ErrNilUserInfo, err)
 	})
-	t.Run("should return error when first guardian private key decryption error", func(t *testing.T) {
+	t.Run("should return error when first guardian private key legacy decryption error", func(t *testing.T) {
 		t.Parallel()
 
 		expectedError := errors.New("expected error")
 		encryptor := &testscommon.EncryptorStub{
-			DecryptDataCalled: func(data []byte) ([]byte, error) {
+			DecryptLegacyDataCalled: func(keyID uint32, data []byte) ([]byte, error) {
 				if bytes.Equal(data, firstGuardianSk) {
 					return nil, expectedError
 				}
 				return data, nil
 			},
 		}
-		ue, _ := NewUserEncryptor(encryptor)
-		decryptedUserInfo, err := ue.DecryptUserInfo(userInfo)
+		ue, _ := NewUserEncryptor(encryptor, false)
+		decryptedUserInfo, err := ue.DecryptUserInfo(testUserAddress, userInfo)
 		require.Nil(t, decryptedUserInfo)
 		require.Equal(t, expectedError, err)
 	})
-	t.Run("should return error when second guardian private key decryption error", func(t *testing.T) {
+	t.Run("should return error when second guardian private key legacy decryption error", func(t *testing.T) {
 		t.Parallel()
 
 		expectedError := errors.New("expected error")
 		encryptor := &testscommon.EncryptorStub{
-			DecryptDataCalled: func(data []byte) ([]byte, error) {
+			DecryptLegacyDataCalled: func(keyID uint32, data []byte) ([]byte, error) {
 				if bytes.Equal(data, secondGuardianSk) {
 					return nil, expectedError
 				}
 				return data, nil
 			},
 		}
-		ue, _ := NewUserEncryptor(encryptor)
-		decryptedUserInfo, err := ue.DecryptUserInfo(userInfo)
+		ue, _ := NewUserEncryptor(encryptor, false)
+		decryptedUserInfo, err := ue.DecryptUserInfo(testUserAddress, userInfo)
 		require.Nil(t, decryptedUserInfo)
 		require.Equal(t, expectedError, err)
 	})
-	t.Run("should return error when first guardian otp decryption error", func(t *testing.T) {
+	t.Run("should return error when first guardian otp legacy decryption error", func(t *testing.T) {
 		t.Parallel()
 
 		expectedError := errors.New("expected error")
 		encryptor := &testscommon.EncryptorStub{
-			DecryptDataCalled: func(data []byte) ([]byte, error) {
+			DecryptLegacyDataCalled: func(keyID uint32, data []byte) ([]byte, error) {
 				if bytes.Equal(data, firstGuardianOTP) {
 					return nil, expectedError
 				}
 				return data, nil
 			},
 		}
-		ue, _ := NewUserEncryptor(encryptor)
-		decryptedUserInfo, err := ue.DecryptUserInfo(userInfo)
+		ue, _ := NewUserEncryptor(encryptor, false)
+		decryptedUserInfo, err := ue.DecryptUserInfo(testUserAddress, userInfo)
 		require.Nil(t, decryptedUserInfo)
 		require.Equal(t, expectedError, err)
 	})
-	t.Run("should return error when second guardian otp decryption error", func(t *testing.T) {
+	t.Run("should return error when second guardian otp legacy decryption error", func(t *testing.T) {
 		t.Parallel()
 
 		expectedError := errors.New("expected error")
 		encryptor := &testscommon.EncryptorStub{
-			DecryptDataCalled: func(data []byte) ([]byte, error) {
+			DecryptLegacyDataCalled: func(keyID uint32, data []byte) ([]byte, error) {
 				if bytes.Equal(data, secondGuardianOTP) {
 					return nil, expectedError
 				}
 				return data, nil
 			},
 		}
-		ue, _ := NewUserEncryptor(encryptor)
-		decryptedUserInfo, err := ue.DecryptUserInfo(userInfo)
+		ue, _ := NewUserEncryptor(encryptor, false)
+		decryptedUserInfo, err := ue.DecryptUserInfo(testUserAddress, userInfo)
+		require.Nil(t, decryptedUserInfo)
+		require.Equal(t, expectedError, err)
+	})
+	t.Run("should return error when spending data legacy decryption error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedError := errors.New("expected error")
+		encryptor := &testscommon.EncryptorStub{
+			DecryptLegacyDataCalled: func(keyID uint32, data []byte) ([]byte, error) {
+				if bytes.Equal(data, spendingData) {
+					return nil, expectedError
+				}
+				return data, nil
+			},
+		}
+		ue, _ := NewUserEncryptor(encryptor, false)
+		decryptedUserInfo, err := ue.DecryptUserInfo(testUserAddress, userInfo)
 		require.Nil(t, decryptedUserInfo)
 		require.Equal(t, expectedError, err)
 	})
 	t.Run("should work", func(t *testing.T) {
 		t.Parallel()
 
-		ue, _ := NewUserEncryptor(encryptor)
+		ue, _ := NewUserEncryptor(encryptor, false)
 		userInfo := &core.UserInfo{
 			FirstGuardian: core.GuardianInfo{
 				PublicKey:  []byte("firstGuardianPk"),
@@ -300,17 +344,270 @@ func TestUserEncryptor_DecryptUserInfo(t *testing.T) {
 				State:      0,
 				OTPData:    core.OTPInfo{},
 			},
-			Index: 1,
+			Index:             1,
+			SpendingData:      []byte("spendingData"),
+			EncryptionVersion: uint32(core.RecordAEADEncryption),
 		}
-		encryptedUserInfo, err := ue.EncryptUserInfo(userInfo)
+		encryptedUserInfo, err := ue.EncryptUserInfo(testUserAddress, userInfo)
 		require.Nil(t, err)
 		require.NotNil(t, encryptedUserInfo)
 
-		decryptedUserInfo, err := ue.DecryptUserInfo(encryptedUserInfo)
+		decryptedUserInfo, err := ue.DecryptUserInfo(testUserAddress, encryptedUserInfo)
 		require.NotNil(t, decryptedUserInfo)
 		require.Nil(t, err)
 		require.Equal(t, userInfo, decryptedUserInfo)
 	})
+	t.Run("should decrypt with the key the user info was encrypted with", func(t *testing.T) {
+		t.Parallel()
+
+		userInfo := &core.UserInfo{
+			FirstGuardian: core.GuardianInfo{
+				PublicKey:  []byte("firstGuardianPk"),
+				PrivateKey: []byte("firstGuardianSk"),
+			},
+			SecondGuardian: core.GuardianInfo{
+				PublicKey:  []byte("secondGuardianPk"),
+				PrivateKey: []byte("secondGuardianSk"),
+			},
+			Index:             1,
+			EncryptionVersion: uint32(core.RecordAEADEncryption),
+		}
+		oldEncryptor, _ := createTestKeyRing(testMarshaller, 0)
+		oldUe, _ := NewUserEncryptor(oldEncryptor, false)
+		encryptedUserInfo, err := oldUe.EncryptUserInfo(testUserAddress, userInfo)
+		require.Nil(t, err)
+		require.Equal(t, uint32(0), encryptedUserInfo.EncryptionKeyID)
+
+		newEncryptor, _ := createTestKeyRing(testMarshaller, 1)
+		ue, _ := NewUserEncryptor(newEncryptor, false)
+		decryptedUserInfo, err := ue.DecryptUserInfo(testUserAddress, encryptedUserInfo)
+		require.Nil(t, err)
+		require.Equal(t, userInfo, decryptedUserInfo)
+
+		reEncryptedUserInfo, err := ue.EncryptUserInfo(testUserAddress, decryptedUserInfo)
+		require.Nil(t, err)
+		require.Equal(t, uint32(1), reEncryptedUserInfo.EncryptionKeyID)
+
+		_, err = oldUe.DecryptUserInfo(testUserAddress, reEncryptedUserInfo)
+		require.True(t, errors.Is(err, ErrInvalidRecordTag))
+		require.True(t, strings.Contains(err.Error(), encryption.ErrUnknownKeyID.Error()))
+	})
+	t.Run("should decrypt each field with its associated data", func(t *testing.T) {
+		t.Parallel()
+
+		expectedError := errors.New("expected error")
+		encryptor := &testscommon.EncryptorStub{
+			DecryptDataCalled: func(keyID uint32, data []byte, associatedData []byte) ([]byte, error) {
+				if bytes.Equal(data, spendingData) {
+					require.Equal(t, append([]byte("spending-data\x00"), testUserAddress...), associatedData)
+					return nil, expectedError
+				}
+				return data, nil
+			},
+		}
+		ue, _ := NewUserEncryptor(encryptor, false)
+		userInfoCopy := *userInfo
+		userInfoCopy.EncryptionVersion = uint32(core.AEADEncryption)
+		decryptedUserInfo, err := ue.DecryptUserInfo(testUserAddress, &userInfoCopy)
+		require.Nil(t, decryptedUserInfo)
+		require.Equal(t, expectedError, err)
+	})
+	t.Run("should return error for an unknown encryption version", func(t *testing.T) {
+		t.Parallel()
+
+		ue, _ := NewUserEncryptor(encryptor, false)
+		userInfoCopy := *userInfo
+		userInfoCopy.EncryptionVersion = 3
+		decryptedUserInfo, err := ue.DecryptUserInfo(testUserAddress, &userInfoCopy)
+		require.Nil(t, decryptedUserInfo)
+		require.True(t, errors.Is(err, ErrUnknownEncryptionVersion))
+	})
+	t.Run("should not decrypt the secrets of another user", func(t *testing.T) {
+		t.Parallel()
+
+		ue, _ := NewUserEncryptor(encryptor, false)
+		encryptedUserInfo, err := ue.EncryptUserInfo(testUserAddress, userInfo)
+		require.Nil(t, err)
+
+		decryptedUserInfo, err := ue.DecryptUserInfo([]byte("another user address"), encryptedUserInfo)
+		require.Nil(t, decryptedUserInfo)
+		require.NotNil(t, err)
+	})
+	t.Run("should not decrypt secrets swapped between fields", func(t *testing.T) {
+		t.Parallel()
+
+		ue, _ := NewUserEncryptor(encryptor, false)
+		encryptedUserInfo, err := ue.EncryptUserInfo(testUserAddress, userInfo)
+		require.Nil(t, err)
+
+		encryptedUserInfo.FirstGuardian.PrivateKey, encryptedUserInfo.SecondGuardian.PrivateKey =
+			encryptedUserInfo.SecondGuardian.PrivateKey, encryptedUserInfo.FirstGuardian.PrivateKey
+		decryptedUserInfo, err := ue.DecryptUserInfo(testUserAddress, encryptedUserInfo)
+		require.Nil(t, decryptedUserInfo)
+		require.NotNil(t, err)
+	})
+	t.Run("should not decrypt a record with changed plain fields", func(t *testing.T) {
+		t.Parallel()
+
+		ue, _ := NewUserEncryptor(encryptor, false)
+		encryptedUserInfo, err := ue.EncryptUserInfo(testUserAddress, userInfo)
+		require.Nil(t, err)
+
+		changeHandlers := []func(userInfo *core.UserInfo){
+			func(userInfo *core.UserInfo) { userInfo.Index++ },
+			func(userInfo *core.UserInfo) { userInfo.FirstGuardian.WebAuthnData.PublicKey = []byte("attacker key") },
+			func(userInfo *core.UserInfo) { userInfo.SecondGuardian.State = core.Usable },
+			func(userInfo *core.UserInfo) { userInfo.FirstGuardian.OTPData.Params.Counter++ },
+			func(userInfo *core.UserInfo) { userInfo.RecoveryCodes = [][]byte{[]byte("code hash")} },
+			func(userInfo *core.UserInfo) { userInfo.SpendingData = nil },
+			func(userInfo *core.UserInfo) { userInfo.RecordTag = nil },
+		}
+		for _, changeHandler := range changeHandlers {
+			changedUserInfo := *encryptedUserInfo
+			changeHandler(&changedUserInfo)
+
+			decryptedUserInfo, errDecrypt := ue.DecryptUserInfo(testUserAddress, &changedUserInfo)
+			require.Nil(t, decryptedUserInfo)
+			require.True(t, errors.Is(errDecrypt, ErrInvalidRecordTag))
+		}
+	})
+	t.Run("should not decrypt a record moved to another user", func(t *testing.T) {
+		t.Parallel()
+
+		ue, _ := NewUserEncryptor(encryptor, false)
+		encryptedUserInfo, err := ue.EncryptUserInfo(testUserAddress, userInfo)
+		require.Nil(t, err)
+
+		decryptedUserInfo, err := ue.DecryptUserInfo([]byte("another user address"), encryptedUserInfo)
+		require.Nil(t, decryptedUserInfo)
+		require.True(t, errors.Is(err, ErrInvalidRecordTag))
+	})
+	t.Run("should not decrypt a record downgraded to the previous version", func(t *testing.T) {
+		t.Parallel()
+
+		ue, _ := NewUserEncryptor(encryptor, false)
+		encryptedUserInfo, err := ue.EncryptUserInfo(testUserAddress, &core.UserInfo{
+			FirstGuardian:  userInfo.FirstGuardian,
+			SecondGuardian: userInfo.SecondGuardian,
+		})
+		require.Nil(t, err)
+		require.NotEmpty(t, encryptedUserInfo.SpendingData, "empty secrets should be sealed as well")
+
+		encryptedUserInfo.EncryptionVersion = uint32(core.AEADEncryption)
+		encryptedUserInfo.RecordTag = nil
+		decryptedUserInfo, err := ue.DecryptUserInfo(testUserAddress, encryptedUserInfo)
+		require.Nil(t, decryptedUserInfo)
+		require.NotNil(t, err)
+	})
+	t.Run("should reject a record downgraded to the previous version when the legacy records are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		aeadUserInfo := *userInfo
+		aeadUserInfo.EncryptionVersion = uint32(core.AEADEncryption)
+		for _, field := range getEncryptedFields(&aeadUserInfo) {
+			*field.value, err = encryptor.EncryptData(*field.value, createAssociatedData(testUserAddress, field.name, core.AEADEncryption))
+			require.Nil(t, err)
+		}
+
+		ue, _ := NewUserEncryptor(encryptor, false)
+		decryptedUserInfo, err := ue.DecryptUserInfo(testUserAddress, &aeadUserInfo)
+		require.Nil(t, err)
+		require.Equal(t, userInfo.FirstGuardian, decryptedUserInfo.FirstGuardian)
+
+		ue, _ = NewUserEncryptor(encryptor, true)
+		decryptedUserInfo, err = ue.DecryptUserInfo(testUserAddress, &aeadUserInfo)
+		require.Nil(t, decryptedUserInfo)
+		require.True(t, errors.Is(err, ErrLegacyRecordRejected))
+
+		legacyEncryptor, err := encryption.NewEncryptor(testMarshaller, testKeygen, testSk)
+		require.Nil(t, err)
+		legacyUserInfo := *userInfo
+		for _, field := range getEncryptedFields(&legacyUserInfo) {
+			*field.value, err = legacyEncryptor.EncryptData(*field.value)
+			require.Nil(t, err)
+		}
+		decryptedUserInfo, err = ue.DecryptUserInfo(testUserAddress, &legacyUserInfo)
+		require.Nil(t, decryptedUserInfo)
+		require.True(t, errors.Is(err, ErrLegacyRecordRejected))
+
+		encryptedUserInfo, err := ue.EncryptUserInfo(testUserAddress, userInfo)
+		require.Nil(t, err)
+		decryptedUserInfo, err = ue.DecryptUserInfo(testUserAddress, encryptedUserInfo)
+		require.Nil(t, err)
+		require.Equal(t, userInfo.FirstGuardian, decryptedUserInfo.FirstGuardian)
+	})
+	t.Run("should decrypt the empty secrets of the previous version", func(t *testing.T) {
+		t.Parallel()
+
+		aeadUserInfo := *userInfo
+		aeadUserInfo.EncryptionVersion = uint32(core.AEADEncryption)
+		aeadUserInfo.SpendingData = nil
+		for _, field := range getEncryptedFields(&aeadUserInfo) {
+			if len(*field.value) == 0 {
+				continue
+			}
+			*field.value, err = encryptor.EncryptData(*field.value, createAssociatedData(testUserAddress, field.name, core.AEADEncryption))
+			require.Nil(t, err)
+		}
+
+		ue, _ := NewUserEncryptor(encryptor, false)
+		decryptedUserInfo, err := ue.DecryptUserInfo(testUserAddress, &aeadUserInfo)
+		require.Nil(t, err)
+		require.Equal(t, userInfo.FirstGuardian, decryptedUserInfo.FirstGuardian)
+		require.Equal(t, userInfo.SecondGuardian, decryptedUserInfo.SecondGuardian)
+		require.Empty(t, decryptedUserInfo.SpendingData)
+	})
+	t.Run("should decrypt legacy user info and migrate it on encryption", func(t *testing.T) {
+		t.Parallel()
+
+		legacyEncryptor, err := encryption.NewEncryptor(testMarshaller, testKeygen, testSk)
+		require.Nil(t, err)
+		legacyUserInfo := *userInfo
+		for _, field := range getEncryptedFields(&legacyUserInfo) {
+			*field.value, err = legacyEncryptor.EncryptData(*field.value)
+			require.Nil(t, err)
+		}
+
+		ue, _ := NewUserEncryptor(encryptor, false)
+		decryptedUserInfo, err := ue.DecryptUserInfo(testUserAddress, &legacyUserInfo)
+		require.Nil(t, err)
+		require.Equal(t, userInfo, decryptedUserInfo)
+
+		encryptedUserInfo, err := ue.EncryptUserInfo(testUserAddress, decryptedUserInfo)
+		require.Nil(t, err)
+		require.Equal(t, uint32(core.RecordAEADEncryption), encryptedUserInfo.EncryptionVersion)
+		checkEncryptedUserInfo(t, userInfo, encryptedUserInfo)
+
+		decryptedUserInfo, err = ue.DecryptUserInfo(testUserAddress, encryptedUserInfo)
+		require.Nil(t, err)
+		require.Equal(t, userInfo.FirstGuardian, decryptedUserInfo.FirstGuardian)
+		require.Equal(t, userInfo.SecondGuardian, decryptedUserInfo.SecondGuardian)
+		require.Equal(t, userInfo.SpendingData, decryptedUserInfo.SpendingData)
+	})
+}
+
+func TestUserEncryptor_IsEncryptedWithCurrentKey(t *testing.T) {
+	t.Parallel()
+
+	testMarshaller, _ := factoryMarshaller.NewMarshalizer(factoryMarshaller.JsonMarshalizer)
+	encryptor, _ := createTestKeyRing(testMarshaller, 1)
+	ue, _ := NewUserEncryptor(encryptor, false)
+
+	encryptedUserInfo, err := ue.EncryptUserInfo(testUserAddress, &core.UserInfo{})
+	require.Nil(t, err)
+	require.True(t, ue.IsEncryptedWithCurrentKey(encryptedUserInfo))
+
+	oldKeyUserInfo := *encryptedUserInfo
+	oldKeyUserInfo.EncryptionKeyID = 0
+	require.False(t, ue.IsEncryptedWithCurrentKey(&oldKeyUserInfo))
+
+	legacyUserInfo := *encryptedUserInfo
+	legacyUserInfo.EncryptionVersion = uint32(core.LegacyEncryption)
+	require.False(t, ue.IsEncryptedWithCurrentKey(&legacyUserInfo))
+
+	aeadUserInfo := *encryptedUserInfo
+	aeadUserInfo.EncryptionVersion = uint32(core.AEADEncryption)
+	require.False(t, ue.IsEncryptedWithCurrentKey(&aeadUserInfo))
 }
 
 func TestUserEncryptor_IsInterfaceNil(t *testing.T) {
@@ -328,6 +625,7 @@ func checkEncryptedUserInfo(t *testing.T, userInfo *core.UserInfo, encryptedUser
 	require.NotEqual(t, userInfo.SecondGuardian.PrivateKey, encryptedUserInfo.SecondGuardian.PrivateKey, "secondGuardianSk should be encrypted")
 	require.NotEqual(t, userInfo.FirstGuardian.OTPData.OTP, encryptedUserInfo.FirstGuardian.OTPData.OTP, "firstGuardianOtp should be encrypted")
 	require.NotEqual(t, userInfo.SecondGuardian.OTPData.OTP, encryptedUserInfo.SecondGuardian.OTPData.OTP, "secondGuardianOtp should be encrypted")
+	require.NotEqual(t, userInfo.SpendingData, encryptedUserInfo.SpendingData, "spending data should be encrypted")
 	require.Equal(t, userInfo.FirstGuardian.PublicKey, encryptedUserInfo.FirstGuardian.PublicKey, "firstGuardianPk should not be encrypted")
 	require.Equal(t, userInfo.SecondGuardian.PublicKey, encryptedUserInfo.SecondGuardian.PublicKey, "secondGuardianPk should not be encrypted")
 	require.Equal(t, userInfo.Index, encryptedUserInfo.Index, "index should not be encrypted")
@@ -336,3 +634,19 @@ func checkEncryptedUserInfo(t *testing.T, userInfo *core.UserInfo, encryptedUser
 	require.Equal(t, userInfo.FirstGuardian.OTPData.LastTOTPChangeTimestamp, encryptedUserInfo.FirstGuardian.OTPData.GetLastTOTPChangeTimestamp(), "firstGuardian last OTP change should not be encrypted")
 	require.Equal(t, userInfo.SecondGuardian.OTPData.LastTOTPChangeTimestamp, encryptedUserInfo.SecondGuardian.OTPData.GetLastTOTPChangeTimestamp(), "secondGuardian last OTP change should not be encrypted")
 }
+
+func createTestKeyRing(marshaller core.Marshaller, currentKeyID uint32) (Encryptor, error) {
+	managedKeys := map[uint32]crypto.PrivateKey{
+		0: testSk,
+	}
+	if currentKeyID > 0 {
+		managedKeys[currentKeyID], _ = testKeygen.GeneratePair()
+	}
+
+	return encryption.NewKeyRing(encryption.ArgsKeyRing{
+		Marshaller:   marshaller,
+		KeyGen:       testKeygen,
+		ManagedKeys:  managedKeys,
+		CurrentKeyID: currentKeyID,
+	})
+}
@@ -0,0 +1,339 @@
+package resolver
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	sdkCore "github.com/multiversx/mx-sdk-go/core"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+)
+
+const (
+	webAuthnTransactionsTag = "webauthn transactions"
+	webAuthnMessageTag      = "webauthn message"
+)
+
+// WebAuthnChallenge issues a new challenge for the WebAuthn credential of the guardian, bound to the transactions
+// or to the message of the request, if any. The challenges are not stored, so any number of them can be outstanding
+// and issuing one does not count as a verification trial, but none is issued while the verifications of the user
+// are blocked
+func (resolver *serviceResolver) WebAuthnChallenge(userAddress sdkCore.AddressHandler, userIp string, request requests.WebAuthnChallenge) (*requests.WebAuthnChallengeResponse, *requests.OTPCodeVerifyData, error) {
+	bech32Addr, err := userAddress.AddressAsBech32String()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	verifyCodeData, err := resolver.secureOtpHandler.GetVerificationTrials(bech32Addr, userIp)
+	if err != nil {
+		return nil, nil, err
+	}
+	if verifyCodeData.RemainingTrials <= 0 {
+		return nil, verifyCodeData, core.ErrTooManyFailedAttempts
+	}
+
+	guardianAddr, err := resolver.pubKeyConverter.Decode(request.Guardian)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payloadDigest, err := resolver.computeChallengePayloadDigest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	addressBytes := userAddress.AddressBytes()
+	credentialID, err := resolver.getWebAuthnCredentialID(addressBytes, guardianAddr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	challenge, err := resolver.webAuthnHandler.CreateChallenge(computeWebAuthnBinding(addressBytes, guardianAddr, payloadDigest))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response := &requests.WebAuthnChallengeResponse{
+		Challenge: base64.RawURLEncoding.EncodeToString(challenge),
+	}
+	if len(credentialID) > 0 {
+		response.CredentialID = base64.RawURLEncoding.EncodeToString(credentialID)
+	}
+
+	return response, nil, nil
+}
+
+func (resolver *serviceResolver) getWebAuthnCredentialID(userAddress []byte, guardianAddr []byte) ([]byte, error) {
+	resolver.userCritSection.RLock(string(userAddress))
+	defer resolver.userCritSection.RUnlock(string(userAddress))
+
+	userInfo, err := resolver.getUserInfo(userAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	webAuthnInfo, err := extractWebAuthnForGuardian(userInfo, guardianAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	return webAuthnInfo.CredentialID, nil
+}
+
+func (resolver *serviceResolver) computeChallengePayloadDigest(request requests.WebAuthnChallenge) ([]byte, error) {
+	hasTransactions := len(request.Transactions) > 0
+	hasMessage := len(request.Message) > 0
+	switch {
+	case hasTransactions && hasMessage:
+		return nil, ErrAmbiguousWebAuthnChallenge
+	case hasTransactions:
+		return resolver.computeTransactionsDigest(request.Transactions)
+	case hasMessage:
+		return resolver.computeMessageDigest(request.Message), nil
+	default:
+		return nil, nil
+	}
+}
+
+// computeTransactionsDigest returns the digest of the transactions an assertion authorizes. The signatures are
+// left out, so the challenge can be requested before the user signs the transactions
+func (resolver *serviceResolver) computeTransactionsDigest(txs []transaction.FrontendTransaction) ([]byte, error) {
+	txHashes := make([]byte, 0)
+	for index, tx := range txs {
+		tx.Signature = ""
+		tx.GuardianSignature = ""
+		txBytes, err := resolver.txMarshaller.Marshal(&tx)
+		if err != nil {
+			return nil, fmt.Errorf("%w for transaction #%d", err, index)
+		}
+
+		txHashes = append(txHashes, resolver.txHasher.Compute(string(txBytes))...)
+	}
+
+	return resolver.txHasher.Compute(webAuthnTransactionsTag + string(txHashes)), nil
+}
+
+// computeMessageDigest returns the digest of the message an assertion authorizes
+func (resolver *serviceResolver) computeMessageDigest(message string) []byte {
+	return resolver.txHasher.Compute(webAuthnMessageTag + message)
+}
+
+// computeWebAuthnBinding returns the data a challenge is bound to: the user, the guardian and the digest of the
+// payload authorized by the assertion, empty when the assertion only stands for a code
+func computeWebAuthnBinding(userAddress []byte, guardianAddr []byte, payloadDigest []byte) []byte {
+	binding := make([]byte, 0, len(userAddress)+len(guardianAddr)+len(payloadDigest))
+	binding = append(binding, userAddress...)
+	binding = append(binding, guardianAddr...)
+	return append(binding, payloadDigest...)
+}
+
+// RegisterWebAuthn verifies the codes and then registers the WebAuthn credential for the guardian,
+// replacing the previous one
+func (resolver *serviceResolver) RegisterWebAuthn(userAddress sdkCore.AddressHandler, userIp string, request requests.RegisterWebAuthn) (*requests.OTPCodeVerifyData, error) {
+	guardianAddr, err := resolver.pubKeyConverter.Decode(request.Guardian)
+	if err != nil {
+		return nil, err
+	}
+
+	addressBytes := userAddress.AddressBytes()
+	resolver.userCritSection.Lock(string(addressBytes))
+	defer resolver.userCritSection.Unlock(string(addressBytes))
+
+	userInfo, err := resolver.getUserInfo(addressBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	bech32Addr, err := userAddress.AddressAsBech32String()
+	if err != nil {
+		return nil, err
+	}
+
+	verifyCodeData, err := resolver.checkAllowanceAndVerifyCode(userInfo, addressBytes, bech32Addr, userIp, request.Code, request.SecondCode, guardianAddr, false)
+	if err != nil {
+		return verifyCodeData, err
+	}
+
+	_, err = resolver.getGuardianInfoFromAddress(addressBytes, request.Guardian, userInfo)
+	if err != nil {
+		return verifyCodeData, err
+	}
+
+	webAuthnInfo, err := extractWebAuthnForGuardian(userInfo, guardianAddr)
+	if err != nil {
+		return verifyCodeData, err
+	}
+
+	binding := computeWebAuthnBinding(addressBytes, guardianAddr, nil)
+	credential, err := resolver.webAuthnHandler.VerifyRegistration(*webAuthnInfo, binding, request.Credential)
+	if err != nil {
+		return verifyCodeData, err
+	}
+
+	*webAuthnInfo = *credential
+	err = resolver.marshalAndSaveEncrypted(addressBytes, userInfo)
+	if err != nil {
+		return verifyCodeData, err
+	}
+
+	log.Debug("webauthn credential registered",
+		"userAddress", bech32Addr,
+		"guardian", request.Guardian)
+
+	return verifyCodeData, nil
+}
+
+// verifyCodeOrAssertion verifies either the assertion or the codes of the request. The user lock must be held by the caller
+func (resolver *serviceResolver) verifyCodeOrAssertion(
+	userInfo *core.UserInfo,
+	userAddress []byte,
+	bech32Addr string,
+	userIp string,
+	guardianAddr []byte,
+	request requests.VerificationPayload,
+) (*requests.OTPCodeVerifyData, error) {
+	if request.Assertion != nil {
+		return resolver.verifyAssertion(userInfo, userAddress, bech32Addr, userIp, guardianAddr, nil, request.Code, *request.Assertion, false)
+	}
+
+	return resolver.checkAllowanceAndVerifyCode(userInfo, userAddress, bech32Addr, userIp, request.Code, request.SecondCode, guardianAddr, false)
+}
+
+func (resolver *serviceResolver) verifyAssertionReturningGuardian(
+	userAddress []byte,
+	bech32Addr string,
+	userIp string,
+	guardianAddr string,
+	guardianAddrBytes []byte,
+	payloadDigest []byte,
+	code string,
+	assertion requests.WebAuthnAssertion,
+	confirmationRequired bool,
+) (core.GuardianInfo, *requests.OTPCodeVerifyData, error) {
+	resolver.userCritSection.Lock(string(userAddress))
+	defer resolver.userCritSection.Unlock(string(userAddress))
+
+	userInfo, err := resolver.getUserInfo(userAddress)
+	if err != nil {
+		return core.GuardianInfo{}, nil, err
+	}
+
+	verifyCodeData, err := resolver.verifyAssertion(userInfo, userAddress, bech32Addr, userIp, guardianAddrBytes, payloadDigest, code, assertion, confirmationRequired)
+	if err != nil {
+		return core.GuardianInfo{}, verifyCodeData, err
+	}
+
+	guardianInfo, err := resolver.getGuardianInfoFromAddress(userAddress, guardianAddr, userInfo)
+	if err != nil {
+		return core.GuardianInfo{}, verifyCodeData, err
+	}
+
+	return guardianInfo, verifyCodeData, nil
+}
+
+// verifyAssertion verifies the assertion against a challenge bound to the provided payload digest and saves the
+// consumed challenge along with the new signature counter. An assertion stands for a single code, so the code of
+// the request is verified as well when a confirmation is required or when the security mode is active.
+// The user lock must be held by the caller
+func (resolver *serviceResolver) verifyAssertion(
+	userInfo *core.UserInfo,
+	userAddress []byte,
+	bech32Addr string,
+	userIp string,
+	guardianAddr []byte,
+	payloadDigest []byte,
+	code string,
+	assertion requests.WebAuthnAssertion,
+	confirmationRequired bool,
+) (*requests.OTPCodeVerifyData, error) {
+	webAuthnInfo, err := extractWebAuthnForGuardian(userInfo, guardianAddr)
+	if err != nil {
+		return nil, err
+	}
+	if len(webAuthnInfo.PublicKey) == 0 {
+		return nil, ErrWebAuthnNotRegistered
+	}
+
+	verifyCodeData, err := resolver.secureOtpHandler.IsVerificationAllowedAndIncreaseTrials(bech32Addr, userIp)
+	if err != nil {
+		resolver.extendSecurityMode(verifyCodeData, bech32Addr)
+		return verifyCodeData, err
+	}
+
+	binding := computeWebAuthnBinding(userAddress, guardianAddr, payloadDigest)
+	credential, err := resolver.webAuthnHandler.VerifyAssertion(*webAuthnInfo, binding, assertion)
+	if err != nil {
+		resolver.extendSecurityMode(verifyCodeData, bech32Addr)
+		return verifyCodeData, err
+	}
+
+	// the challenge is consumed even if the code is wrong, so the assertion cannot be reused to guess the code
+	*webAuthnInfo = *credential
+	isSecurityModeActive := verifyCodeData.SecurityModeRemainingTrials <= 0
+	errCode := resolver.verifyAssertionCode(userInfo, code, guardianAddr, confirmationRequired, isSecurityModeActive)
+	err = resolver.marshalAndSaveEncrypted(userAddress, userInfo)
+	if errCode != nil {
+		if isSecurityModeActive {
+			resolver.extendSecurityMode(verifyCodeData, bech32Addr)
+		}
+		return verifyCodeData, errCode
+	}
+	if err != nil {
+		return verifyCodeData, err
+	}
+
+	resolver.secureOtpHandler.Reset(bech32Addr, userIp)
+	errDec := resolver.secureOtpHandler.DecrementSecurityModeFailedTrials(bech32Addr)
+	if errDec != nil {
+		log.Warn("failed to decrement security mode failed trials", "user", bech32Addr, "error", errDec.Error())
+	}
+
+	return &requests.OTPCodeVerifyData{
+		RemainingTrials:             int(resolver.secureOtpHandler.FreezeMaxFailures()),
+		SecurityModeRemainingTrials: verifyCodeData.SecurityModeRemainingTrials,
+		SecurityModeResetAfter:      verifyCodeData.SecurityModeResetAfter,
+	}, nil
+}
+
+// verifyAssertionCode verifies the code sent along with an assertion, if the assertion alone is not enough
+func (resolver *serviceResolver) verifyAssertionCode(
+	userInfo *core.UserInfo,
+	code string,
+	guardianAddr []byte,
+	confirmationRequired bool,
+	isSecurityModeActive bool,
+) error {
+	if !confirmationRequired && !isSecurityModeActive {
+		return nil
+	}
+
+	err := resolver.verifyCode(userInfo, code, guardianAddr)
+	if err == nil {
+		return nil
+	}
+	if confirmationRequired {
+		return fmt.Errorf("%w with codeError %s", handlers.ErrTxPolicyConfirmationRequired, err)
+	}
+
+	return fmt.Errorf("%w with codeError %s", ErrSecondCodeInvalidInSecurityMode, err)
+}
+
+func extractWebAuthnForGuardian(userInfo *core.UserInfo, guardian []byte) (*core.WebAuthnInfo, error) {
+	if userInfo == nil {
+		return nil, ErrNilUserInfo
+	}
+
+	if bytes.Equal(userInfo.FirstGuardian.PublicKey, guardian) {
+		return &userInfo.FirstGuardian.WebAuthnData, nil
+	}
+
+	if bytes.Equal(userInfo.SecondGuardian.PublicKey, guardian) {
+		return &userInfo.SecondGuardian.WebAuthnData, nil
+	}
+
+	return nil, ErrInvalidGuardian
+}
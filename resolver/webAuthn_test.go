@@ -0,0 +1,597 @@
+package resolver
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	chainCore "github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/data/mock"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	crypto "github.com/multiversx/mx-chain-crypto-go"
+	sdkCore "github.com/multiversx/mx-sdk-go/core"
+	sdkData "github.com/multiversx/mx-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+var (
+	providedChallenge    = []byte("challenge")
+	providedCredentialID = []byte("credential id")
+	providedAssertion    = requests.WebAuthnAssertion{
+		CredentialID: base64.RawURLEncoding.EncodeToString(providedCredentialID),
+	}
+)
+
+type webAuthnTestContext struct {
+	resolver        *serviceResolver
+	userAddress     sdkCore.AddressHandler
+	mutDB           sync.Mutex
+	db              map[string][]byte
+	numResets       int
+	numExtendCalled int
+
+	securityModeRemainingTrials int
+}
+
+func createWebAuthnTestContext(t *testing.T, webAuthnData core.WebAuthnInfo) *webAuthnTestContext {
+	ctx := &webAuthnTestContext{
+		db:                          make(map[string][]byte),
+		securityModeRemainingTrials: 10,
+	}
+
+	args := createMockArgs()
+	args.SecureOtpHandler = &testscommon.SecureOtpHandlerStub{
+		IsVerificationAllowedAndIncreaseTrialsCalled: func(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+			return &requests.OTPCodeVerifyData{
+				RemainingTrials:             2,
+				SecurityModeRemainingTrials: ctx.securityModeRemainingTrials,
+			}, nil
+		},
+		GetVerificationTrialsCalled: func(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+			return &requests.OTPCodeVerifyData{
+				RemainingTrials:             2,
+				SecurityModeRemainingTrials: ctx.securityModeRemainingTrials,
+			}, nil
+		},
+		ResetCalled: func(account string, ip string) {
+			ctx.numResets++
+		},
+		ExtendSecurityModeCalled: func(account string) error {
+			ctx.numExtendCalled++
+			return nil
+		},
+	}
+	args.RegisteredUsersDB = &testscommon.ShardedStorageWithIndexStub{
+		PutCalled: func(key, data []byte) error {
+			ctx.mutDB.Lock()
+			ctx.db[string(key)] = data
+			ctx.mutDB.Unlock()
+			return nil
+		},
+		GetCalled: func(key []byte) ([]byte, error) {
+			ctx.mutDB.Lock()
+			defer ctx.mutDB.Unlock()
+			return ctx.db[string(key)], nil
+		},
+	}
+	args.PubKeyConverter = &mock.PubkeyConverterStub{
+		DecodeCalled: func(humanReadable string) ([]byte, error) {
+			return []byte(humanReadable), nil
+		},
+		EncodeCalled: func(pkBytes []byte) (string, error) {
+			return string(pkBytes), nil
+		},
+		SilentEncodeCalled: func(pkBytes []byte, log chainCore.Logger) string {
+			return string(pkBytes)
+		},
+	}
+
+	var err error
+	ctx.resolver, err = NewServiceResolver(args)
+	require.Nil(t, err)
+	ctx.resolver.getTimeHandler = func() time.Time {
+		return time.Unix(1000, 0)
+	}
+
+	ctx.userAddress, err = sdkData.NewAddressFromBech32String(usrAddr)
+	require.Nil(t, err)
+
+	userInfo := *providedUserInfo
+	userInfo.FirstGuardian.WebAuthnData = webAuthnData
+	err = ctx.resolver.marshalAndSaveEncrypted(ctx.userAddress.AddressBytes(), &userInfo)
+	require.Nil(t, err)
+
+	return ctx
+}
+
+func (ctx *webAuthnTestContext) getWebAuthnData(t *testing.T) core.WebAuthnInfo {
+	userInfo, err := ctx.resolver.getUserInfo(ctx.userAddress.AddressBytes())
+	require.Nil(t, err)
+
+	return userInfo.FirstGuardian.WebAuthnData
+}
+
+func createRegisteredWebAuthnInfo() core.WebAuthnInfo {
+	return core.WebAuthnInfo{
+		CredentialID:           providedCredentialID,
+		PublicKey:              []byte("public key"),
+		SignCount:              5,
+		LastChallengeTimestamp: 990,
+	}
+}
+
+// acceptAssertion returns a WebAuthn handler accepting the assertions bound to the provided binding
+func acceptAssertion(t *testing.T, expectedBinding []byte) *testscommon.WebAuthnHandlerStub {
+	return &testscommon.WebAuthnHandlerStub{
+		VerifyAssertionCalled: func(credential core.WebAuthnInfo, binding []byte, assertion requests.WebAuthnAssertion) (*core.WebAuthnInfo, error) {
+			assert.Equal(t, expectedBinding, binding)
+			assert.Equal(t, providedAssertion, assertion)
+			credential.SignCount++
+			credential.LastChallengeTimestamp++
+			return &credential, nil
+		},
+	}
+}
+
+func (ctx *webAuthnTestContext) createBinding(payloadDigest []byte) []byte {
+	return computeWebAuthnBinding(ctx.userAddress.AddressBytes(), providedUserInfo.FirstGuardian.PublicKey, payloadDigest)
+}
+
+func TestServiceResolver_WebAuthnChallenge(t *testing.T) {
+	t.Parallel()
+
+	providedRequest := requests.WebAuthnChallenge{
+		Guardian: string(providedUserInfo.FirstGuardian.PublicKey),
+	}
+	t.Run("get verification trials fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+		ctx.resolver.secureOtpHandler = &testscommon.SecureOtpHandlerStub{
+			GetVerificationTrialsCalled: func(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+				return nil, expectedErr
+			},
+		}
+
+		response, _, err := ctx.resolver.WebAuthnChallenge(ctx.userAddress, "userIp", providedRequest)
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, response)
+	})
+	t.Run("no remaining trials should error without revealing the credential", func(t *testing.T) {
+		t.Parallel()
+
+		expectedVerifyCodeData := &requests.OTPCodeVerifyData{
+			RemainingTrials: 0,
+			ResetAfter:      60,
+		}
+		ctx := createWebAuthnTestContext(t, createRegisteredWebAuthnInfo())
+		ctx.resolver.secureOtpHandler = &testscommon.SecureOtpHandlerStub{
+			GetVerificationTrialsCalled: func(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+				assert.Equal(t, usrAddr, account)
+				assert.Equal(t, "userIp", ip)
+				return expectedVerifyCodeData, nil
+			},
+		}
+		ctx.resolver.webAuthnHandler = &testscommon.WebAuthnHandlerStub{
+			CreateChallengeCalled: func(binding []byte) ([]byte, error) {
+				assert.Fail(t, "should have not been called")
+				return nil, nil
+			},
+		}
+
+		response, verifyCodeData, err := ctx.resolver.WebAuthnChallenge(ctx.userAddress, "userIp", providedRequest)
+		assert.Equal(t, core.ErrTooManyFailedAttempts, err)
+		assert.Equal(t, expectedVerifyCodeData, verifyCodeData)
+		assert.Nil(t, response)
+	})
+	t.Run("both transactions and message should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+		request := providedRequest
+		request.Transactions = []transaction.FrontendTransaction{{Nonce: 1}}
+		request.Message = "message"
+
+		response, _, err := ctx.resolver.WebAuthnChallenge(ctx.userAddress, "userIp", request)
+		assert.Equal(t, ErrAmbiguousWebAuthnChallenge, err)
+		assert.Nil(t, response)
+	})
+	t.Run("unknown guardian should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+		request := providedRequest
+		request.Guardian = "unknown guardian"
+
+		response, _, err := ctx.resolver.WebAuthnChallenge(ctx.userAddress, "userIp", request)
+		assert.Equal(t, ErrInvalidGuardian, err)
+		assert.Nil(t, response)
+	})
+	t.Run("create challenge fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+		ctx.resolver.webAuthnHandler = &testscommon.WebAuthnHandlerStub{
+			CreateChallengeCalled: func(binding []byte) ([]byte, error) {
+				return nil, expectedErr
+			},
+		}
+
+		response, _, err := ctx.resolver.WebAuthnChallenge(ctx.userAddress, "userIp", providedRequest)
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, response)
+	})
+	t.Run("should work for a new credential without charging a trial", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+		ctx.resolver.secureOtpHandler = &testscommon.SecureOtpHandlerStub{
+			IsVerificationAllowedAndIncreaseTrialsCalled: func(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+				assert.Fail(t, "should have not been called")
+				return nil, nil
+			},
+			GetVerificationTrialsCalled: func(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+				return &requests.OTPCodeVerifyData{RemainingTrials: 1}, nil
+			},
+		}
+		ctx.resolver.webAuthnHandler = &testscommon.WebAuthnHandlerStub{
+			CreateChallengeCalled: func(binding []byte) ([]byte, error) {
+				assert.Equal(t, ctx.createBinding(nil), binding)
+				return providedChallenge, nil
+			},
+		}
+		ctx.resolver.registeredUsersDB = &testscommon.ShardedStorageWithIndexStub{
+			GetCalled: ctx.resolver.registeredUsersDB.Get,
+			PutCalled: func(key, data []byte) error {
+				assert.Fail(t, "should have not been called")
+				return nil
+			},
+		}
+
+		response, verifyCodeData, err := ctx.resolver.WebAuthnChallenge(ctx.userAddress, "userIp", providedRequest)
+		require.Nil(t, err)
+		assert.Nil(t, verifyCodeData)
+		assert.Equal(t, base64.RawURLEncoding.EncodeToString(providedChallenge), response.Challenge)
+		assert.Empty(t, response.CredentialID)
+	})
+	t.Run("should work for a registered credential and transactions", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, createRegisteredWebAuthnInfo())
+		request := providedRequest
+		request.Transactions = []transaction.FrontendTransaction{{Nonce: 1, Signature: "user signature"}}
+		unsignedTxs := []transaction.FrontendTransaction{{Nonce: 1}}
+		expectedDigest, err := ctx.resolver.computeTransactionsDigest(unsignedTxs)
+		require.Nil(t, err)
+		otherDigest, err := ctx.resolver.computeTransactionsDigest([]transaction.FrontendTransaction{{Nonce: 2}})
+		require.Nil(t, err)
+		assert.NotEqual(t, expectedDigest, otherDigest)
+
+		newChallenge := []byte("new challenge")
+		ctx.resolver.webAuthnHandler = &testscommon.WebAuthnHandlerStub{
+			CreateChallengeCalled: func(binding []byte) ([]byte, error) {
+				assert.Equal(t, ctx.createBinding(expectedDigest), binding)
+				return newChallenge, nil
+			},
+		}
+
+		response, _, err := ctx.resolver.WebAuthnChallenge(ctx.userAddress, "userIp", request)
+		require.Nil(t, err)
+		assert.Equal(t, base64.RawURLEncoding.EncodeToString(newChallenge), response.Challenge)
+		assert.Equal(t, providedAssertion.CredentialID, response.CredentialID)
+		assert.Equal(t, createRegisteredWebAuthnInfo(), ctx.getWebAuthnData(t))
+	})
+	t.Run("should work for a message", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, createRegisteredWebAuthnInfo())
+		request := providedRequest
+		request.Message = "message"
+		ctx.resolver.webAuthnHandler = &testscommon.WebAuthnHandlerStub{
+			CreateChallengeCalled: func(binding []byte) ([]byte, error) {
+				assert.Equal(t, ctx.createBinding(ctx.resolver.computeMessageDigest("message")), binding)
+				return providedChallenge, nil
+			},
+		}
+
+		_, _, err := ctx.resolver.WebAuthnChallenge(ctx.userAddress, "userIp", request)
+		require.Nil(t, err)
+	})
+}
+
+func TestServiceResolver_RegisterWebAuthn(t *testing.T) {
+	t.Parallel()
+
+	providedRequest := requests.RegisterWebAuthn{
+		Code:     defaultFirstCode,
+		Guardian: string(providedUserInfo.FirstGuardian.PublicKey),
+	}
+	t.Run("wrong code should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+		ctx.resolver.totpHandler = &testscommon.TOTPHandlerStub{
+			TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
+				return &testscommon.TotpStub{
+					ValidateCalled: func(userCode string) error {
+						return expectedErr
+					},
+				}, nil
+			},
+		}
+		ctx.resolver.webAuthnHandler = &testscommon.WebAuthnHandlerStub{
+			VerifyRegistrationCalled: func(credential core.WebAuthnInfo, binding []byte, registration requests.WebAuthnRegistration) (*core.WebAuthnInfo, error) {
+				assert.Fail(t, "should have not been called")
+				return nil, nil
+			},
+		}
+
+		_, err := ctx.resolver.RegisterWebAuthn(ctx.userAddress, "userIp", providedRequest)
+		assert.Equal(t, expectedErr, err)
+	})
+	t.Run("invalid registration should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, createRegisteredWebAuthnInfo())
+		ctx.resolver.webAuthnHandler = &testscommon.WebAuthnHandlerStub{
+			VerifyRegistrationCalled: func(credential core.WebAuthnInfo, binding []byte, registration requests.WebAuthnRegistration) (*core.WebAuthnInfo, error) {
+				return nil, handlers.ErrInvalidWebAuthnResponse
+			},
+		}
+
+		_, err := ctx.resolver.RegisterWebAuthn(ctx.userAddress, "userIp", providedRequest)
+		assert.Equal(t, handlers.ErrInvalidWebAuthnResponse, err)
+		assert.Equal(t, createRegisteredWebAuthnInfo(), ctx.getWebAuthnData(t))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, createRegisteredWebAuthnInfo())
+		ctx.resolver.webAuthnHandler = &testscommon.WebAuthnHandlerStub{
+			VerifyRegistrationCalled: func(credential core.WebAuthnInfo, binding []byte, registration requests.WebAuthnRegistration) (*core.WebAuthnInfo, error) {
+				assert.Equal(t, createRegisteredWebAuthnInfo(), credential)
+				assert.Equal(t, ctx.createBinding(nil), binding)
+				return &core.WebAuthnInfo{
+					CredentialID:           []byte("new credential id"),
+					PublicKey:              []byte("new public key"),
+					SignCount:              1,
+					LastChallengeTimestamp: 995,
+				}, nil
+			},
+		}
+
+		_, err := ctx.resolver.RegisterWebAuthn(ctx.userAddress, "userIp", providedRequest)
+		require.Nil(t, err)
+
+		webAuthnData := ctx.getWebAuthnData(t)
+		assert.Equal(t, []byte("new credential id"), webAuthnData.CredentialID)
+		assert.Equal(t, []byte("new public key"), webAuthnData.PublicKey)
+		assert.Equal(t, uint32(1), webAuthnData.SignCount)
+		assert.Equal(t, int64(995), webAuthnData.LastChallengeTimestamp)
+	})
+}
+
+func TestServiceResolver_VerifyCodeWithWebAuthnAssertion(t *testing.T) {
+	t.Parallel()
+
+	providedRequest := requests.VerificationPayload{
+		Assertion: &providedAssertion,
+		Guardian:  string(providedUserInfo.FirstGuardian.PublicKey),
+	}
+	t.Run("no credential registered should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, core.WebAuthnInfo{})
+
+		_, _, err := ctx.resolver.VerifyCode(ctx.userAddress, "userIp", providedRequest)
+		assert.Equal(t, ErrWebAuthnNotRegistered, err)
+	})
+	t.Run("verification not allowed should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, createRegisteredWebAuthnInfo())
+		ctx.resolver.secureOtpHandler = &testscommon.SecureOtpHandlerStub{
+			IsVerificationAllowedAndIncreaseTrialsCalled: func(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+				return &requests.OTPCodeVerifyData{}, core.ErrTooManyFailedAttempts
+			},
+		}
+		ctx.resolver.webAuthnHandler = &testscommon.WebAuthnHandlerStub{
+			VerifyAssertionCalled: func(credential core.WebAuthnInfo, binding []byte, assertion requests.WebAuthnAssertion) (*core.WebAuthnInfo, error) {
+				assert.Fail(t, "should have not been called")
+				return nil, nil
+			},
+		}
+
+		_, verifyCodeData, err := ctx.resolver.VerifyCode(ctx.userAddress, "userIp", providedRequest)
+		assert.Equal(t, core.ErrTooManyFailedAttempts, err)
+		assert.NotNil(t, verifyCodeData)
+	})
+	t.Run("invalid assertion should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, createRegisteredWebAuthnInfo())
+		ctx.resolver.webAuthnHandler = &testscommon.WebAuthnHandlerStub{
+			VerifyAssertionCalled: func(credential core.WebAuthnInfo, binding []byte, assertion requests.WebAuthnAssertion) (*core.WebAuthnInfo, error) {
+				return nil, handlers.ErrInvalidWebAuthnResponse
+			},
+		}
+
+		_, _, err := ctx.resolver.VerifyCode(ctx.userAddress, "userIp", providedRequest)
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.Equal(t, 1, ctx.numExtendCalled)
+		assert.Equal(t, 0, ctx.numResets)
+		assert.Equal(t, createRegisteredWebAuthnInfo(), ctx.getWebAuthnData(t))
+	})
+	t.Run("security mode without code should error and consume the challenge", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, createRegisteredWebAuthnInfo())
+		ctx.securityModeRemainingTrials = 0
+		ctx.resolver.webAuthnHandler = acceptAssertion(t, ctx.createBinding(nil))
+		ctx.resolver.totpHandler = &testscommon.TOTPHandlerStub{
+			TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
+				return &testscommon.TotpStub{
+					ValidateCalled: func(userCode string) error {
+						return handlers.ErrEmptyCode
+					},
+				}, nil
+			},
+		}
+
+		_, _, err := ctx.resolver.VerifyCode(ctx.userAddress, "userIp", providedRequest)
+		assert.True(t, errors.Is(err, ErrSecondCodeInvalidInSecurityMode))
+		assert.Equal(t, 1, ctx.numExtendCalled)
+		assert.Equal(t, 0, ctx.numResets)
+		assert.Equal(t, int64(991), ctx.getWebAuthnData(t).LastChallengeTimestamp)
+	})
+	t.Run("security mode with code should work", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, createRegisteredWebAuthnInfo())
+		ctx.securityModeRemainingTrials = 0
+		ctx.resolver.webAuthnHandler = acceptAssertion(t, ctx.createBinding(nil))
+		ctx.resolver.totpHandler = &testscommon.TOTPHandlerStub{
+			TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
+				return &testscommon.TotpStub{
+					ValidateCalled: func(userCode string) error {
+						assert.Equal(t, defaultFirstCode, userCode)
+						return nil
+					},
+				}, nil
+			},
+		}
+		request := providedRequest
+		request.Code = defaultFirstCode
+
+		_, _, err := ctx.resolver.VerifyCode(ctx.userAddress, "userIp", request)
+		require.Nil(t, err)
+		assert.Equal(t, 1, ctx.numResets)
+		assert.Equal(t, uint32(6), ctx.getWebAuthnData(t).SignCount)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, createRegisteredWebAuthnInfo())
+		ctx.resolver.totpHandler = &testscommon.TOTPHandlerStub{
+			TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
+				assert.Fail(t, "should have not been called")
+				return nil, nil
+			},
+		}
+		ctx.resolver.webAuthnHandler = acceptAssertion(t, ctx.createBinding(nil))
+
+		_, _, err := ctx.resolver.VerifyCode(ctx.userAddress, "userIp", providedRequest)
+		require.Nil(t, err)
+		assert.Equal(t, 1, ctx.numResets)
+		assert.Equal(t, 0, ctx.numExtendCalled)
+
+		webAuthnData := ctx.getWebAuthnData(t)
+		assert.Equal(t, uint32(6), webAuthnData.SignCount)
+		assert.Equal(t, int64(991), webAuthnData.LastChallengeTimestamp)
+	})
+}
+
+func TestServiceResolver_SignMessageWithWebAuthnAssertion(t *testing.T) {
+	t.Parallel()
+
+	ctx := createWebAuthnTestContext(t, createRegisteredWebAuthnInfo())
+	ctx.resolver.webAuthnHandler = acceptAssertion(t, ctx.createBinding(ctx.resolver.computeMessageDigest("message")))
+	ctx.resolver.cryptoComponentsHolderFactory = &testscommon.CryptoComponentsHolderFactoryStub{
+		CreateCalled: func(privateKeyBytes []byte) (sdkCore.CryptoComponentsHolder, error) {
+			assert.Equal(t, providedUserInfo.FirstGuardian.PrivateKey, privateKeyBytes)
+			return &testscommon.CryptoComponentsHolderStub{}, nil
+		},
+	}
+	ctx.resolver.signatureVerifier = &testscommon.SignerStub{
+		SignMessageCalled: func(msg []byte, _ crypto.PrivateKey) ([]byte, error) {
+			return []byte("signature"), nil
+		},
+	}
+
+	request := requests.SignMessage{
+		Assertion:    &providedAssertion,
+		Message:      "message",
+		UserAddr:     usrAddr,
+		GuardianAddr: string(providedUserInfo.FirstGuardian.PublicKey),
+	}
+	signature, _, err := ctx.resolver.SignMessage("userIp", request)
+	require.Nil(t, err)
+	assert.Equal(t, []byte("signature"), signature)
+
+	webAuthnData := ctx.getWebAuthnData(t)
+	assert.Equal(t, uint32(6), webAuthnData.SignCount)
+	assert.Equal(t, int64(991), webAuthnData.LastChallengeTimestamp)
+}
+
+func TestServiceResolver_SignTransactionWithWebAuthnAssertion(t *testing.T) {
+	t.Parallel()
+
+	tx := transaction.FrontendTransaction{
+		Sender:       usrAddr,
+		Signature:    hex.EncodeToString([]byte("signature")),
+		GuardianAddr: string(providedUserInfo.FirstGuardian.PublicKey),
+	}
+	t.Run("confirmation without code should error and consume the challenge", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, createRegisteredWebAuthnInfo())
+		ctx.resolver.txPolicyHandler = &testscommon.TxPolicyHandlerStub{
+			CheckTransactionsCalled: func(userAddress string, txs []transaction.FrontendTransaction) (bool, error) {
+				return true, nil
+			},
+		}
+		expectedDigest, err := ctx.resolver.computeTransactionsDigest([]transaction.FrontendTransaction{tx})
+		require.Nil(t, err)
+		ctx.resolver.webAuthnHandler = acceptAssertion(t, ctx.createBinding(expectedDigest))
+		ctx.resolver.totpHandler = &testscommon.TOTPHandlerStub{
+			TOTPFromBytesCalled: func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
+				return &testscommon.TotpStub{
+					ValidateCalled: func(userCode string) error {
+						return handlers.ErrEmptyCode
+					},
+				}, nil
+			},
+		}
+
+		request := requests.SignTransaction{
+			Assertion: &providedAssertion,
+			Tx:        tx,
+		}
+		_, _, err = ctx.resolver.SignTransaction("userIp", request)
+		assert.True(t, errors.Is(err, handlers.ErrTxPolicyConfirmationRequired))
+		assert.Equal(t, int64(991), ctx.getWebAuthnData(t).LastChallengeTimestamp)
+	})
+	t.Run("assertion of other transactions should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := createWebAuthnTestContext(t, createRegisteredWebAuthnInfo())
+		otherDigest, err := ctx.resolver.computeTransactionsDigest([]transaction.FrontendTransaction{{Nonce: 7}})
+		require.Nil(t, err)
+		ctx.resolver.webAuthnHandler = &testscommon.WebAuthnHandlerStub{
+			VerifyAssertionCalled: func(credential core.WebAuthnInfo, binding []byte, assertion requests.WebAuthnAssertion) (*core.WebAuthnInfo, error) {
+				if !bytes.Equal(ctx.createBinding(otherDigest), binding) {
+					return nil, handlers.ErrInvalidWebAuthnResponse
+				}
+				return &credential, nil
+			},
+		}
+
+		request := requests.SignTransaction{
+			Assertion: &providedAssertion,
+			Tx:        tx,
+		}
+		_, _, err = ctx.resolver.SignTransaction("userIp", request)
+		assert.True(t, errors.Is(err, handlers.ErrInvalidWebAuthnResponse))
+		assert.Equal(t, 1, ctx.numExtendCalled)
+	})
+}
@@ -0,0 +1,517 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	logger "github.com/multiversx/mx-chain-logger-go"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/metrics"
+)
+
+var log = logger.GetOrCreate("sqldb")
+
+// TableID defines sql table type
+type TableID string
+
+// Dialect defines the sql database engine, being also the name of the registered driver
+type Dialect string
+
+const (
+	// PostgresDialect is used for PostgreSQL databases
+	PostgresDialect Dialect = "postgres"
+
+	// SQLiteDialect is used for SQLite databases
+	SQLiteDialect Dialect = "sqlite3"
+)
+
+const (
+	// UsersTableID specifies the default prefix of the users tables
+	UsersTableID TableID = "users"
+
+	getIndexMetricLabel = "GetIndex"
+	delMetricLabel      = "Delete"
+	selectMetricLabel   = "Select"
+	upsertMetricLabel   = "Upsert"
+	incMetricLabel      = "Increment"
+	rangeMetricLabel    = "RangeKeys"
+	maxMetricLabel      = "Max"
+)
+
+const incrementIndexStep = 1
+const minNumUsersTables = 1
+const rangeKeysBatchSize = 100
+
+var tableNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+type dialectInfo struct {
+	metricPrefix string
+	binaryType   string
+}
+
+var dialects = map[Dialect]dialectInfo{
+	PostgresDialect: {
+		metricPrefix: "Postgres",
+		binaryType:   "BYTEA",
+	},
+	SQLiteDialect: {
+		metricPrefix: "SQLite",
+		binaryType:   "BLOB",
+	},
+}
+
+// tableQueries holds the statements of a table. The entries holding a value are the key-value pairs, while the
+// ones holding an index are the counters, so the key-value operations never touch the counters and vice versa
+type tableQueries struct {
+	create              string
+	put                 string
+	get                 string
+	has                 string
+	remove              string
+	putIfNotExists      string
+	removeIfEqual       string
+	rangeKeys           string
+	getIndex            string
+	putIndexIfNotExists string
+	putIndexIfGreater   string
+	incrementIndex      string
+}
+
+// ArgsSQLDBClient is the DTO used to create a new sql db client instance
+type ArgsSQLDBClient struct {
+	DB                *sql.DB
+	Dialect           Dialect
+	UsersTablesPrefix string
+	NumUsersTables    uint32
+	OperationTimeout  time.Duration
+	MetricsHandler    core.StatusMetricsHandler
+}
+
+type sqldbClient struct {
+	db               *sql.DB
+	tables           map[TableID]*tableQueries
+	tablesIDs        []TableID
+	operationTimeout time.Duration
+	metricPrefix     string
+	metricsHandler   core.StatusMetricsHandler
+}
+
+// NewClient will create a new sql db client instance, creating the users tables if they do not exist
+func NewClient(args ArgsSQLDBClient) (*sqldbClient, error) {
+	err := checkArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	dialect := dialects[args.Dialect]
+	client := &sqldbClient{
+		db:               args.DB,
+		operationTimeout: args.OperationTimeout,
+		metricPrefix:     dialect.metricPrefix,
+		metricsHandler:   args.MetricsHandler,
+	}
+
+	err = client.createTables(dialect, args.UsersTablesPrefix, args.NumUsersTables)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func checkArgs(args ArgsSQLDBClient) error {
+	if args.DB == nil {
+		return ErrNilSQLDB
+	}
+	_, found := dialects[args.Dialect]
+	if !found {
+		return fmt.Errorf("%w: %s", ErrUnknownDialect, args.Dialect)
+	}
+	if !tableNameRegex.MatchString(args.UsersTablesPrefix) {
+		return fmt.Errorf("%w: %s", ErrInvalidUsersTablesPrefix, args.UsersTablesPrefix)
+	}
+	if args.NumUsersTables < minNumUsersTables {
+		return fmt.Errorf("%w for number of users tables: provided %d, minimum %d",
+			core.ErrInvalidValue, args.NumUsersTables, minNumUsersTables)
+	}
+	if args.OperationTimeout <= 0 {
+		return fmt.Errorf("%w for operation timeout: provided %v", core.ErrInvalidValue, args.OperationTimeout)
+	}
+	if check.IfNil(args.MetricsHandler) {
+		return core.ErrNilMetricsHandler
+	}
+
+	return nil
+}
+
+func (sdc *sqldbClient) createTables(dialect dialectInfo, usersTablesPrefix string, numUsersTables uint32) error {
+	tables := make(map[TableID]*tableQueries, numUsersTables)
+	tablesIDs := make([]TableID, 0, numUsersTables)
+
+	for i := uint32(0); i < numUsersTables; i++ {
+		tableName := fmt.Sprintf("%s_%d", usersTablesPrefix, i)
+		queries := createTableQueries(dialect, tableName)
+
+		ctx, cancel := context.WithTimeout(context.Background(), sdc.operationTimeout)
+		_, err := sdc.db.ExecContext(ctx, queries.create)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("%w while creating table %s", err, tableName)
+		}
+
+		tables[TableID(tableName)] = queries
+		tablesIDs = append(tablesIDs, TableID(tableName))
+	}
+
+	sdc.tables = tables
+	sdc.tablesIDs = tablesIDs
+
+	return nil
+}
+
+func createTableQueries(dialect dialectInfo, tableName string) *tableQueries {
+	table := fmt.Sprintf(`"%s"`, tableName)
+
+	return &tableQueries{
+		create: fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (id %s PRIMARY KEY, value %s, idx BIGINT)`,
+			table, dialect.binaryType, dialect.binaryType),
+		put: fmt.Sprintf(`INSERT INTO %s (id, value) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET value = excluded.value`,
+			table),
+		get:    fmt.Sprintf(`SELECT value FROM %s WHERE id = $1 AND value IS NOT NULL`, table),
+		has:    fmt.Sprintf(`SELECT 1 FROM %s WHERE id = $1 AND value IS NOT NULL`, table),
+		remove: fmt.Sprintf(`DELETE FROM %s WHERE id = $1 AND value IS NOT NULL`, table),
+		putIfNotExists: fmt.Sprintf(`INSERT INTO %s (id, value) VALUES ($1, $2) ON CONFLICT (id) DO NOTHING`,
+			table),
+		removeIfEqual: fmt.Sprintf(`DELETE FROM %s WHERE id = $1 AND value = $2`, table),
+		rangeKeys: fmt.Sprintf(`SELECT id, value FROM %s WHERE value IS NOT NULL AND id > $1 ORDER BY id LIMIT $2`,
+			table),
+		getIndex: fmt.Sprintf(`SELECT idx FROM %s WHERE id = $1 AND idx IS NOT NULL`, table),
+		putIndexIfNotExists: fmt.Sprintf(`INSERT INTO %s (id, idx) VALUES ($1, $2) ON CONFLICT (id) DO NOTHING`,
+			table),
+		putIndexIfGreater: fmt.Sprintf(`INSERT INTO %[1]s (id, idx) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET idx = excluded.idx `+
+			`WHERE %[1]s.idx IS NULL OR %[1]s.idx < excluded.idx`, table),
+		incrementIndex: fmt.Sprintf(`INSERT INTO %[1]s (id, idx) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET idx = COALESCE(%[1]s.idx, 0) + $2 `+
+			`RETURNING idx`, table),
+	}
+}
+
+// GetAllTablesIDs returns the users tables names as array of table ids
+func (sdc *sqldbClient) GetAllTablesIDs() []TableID {
+	return sdc.tablesIDs
+}
+
+// Put will set key value pair into specified table
+func (sdc *sqldbClient) Put(table TableID, key []byte, data []byte) error {
+	queries, ok := sdc.tables[table]
+	if !ok {
+		return ErrTableNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sdc.operationTimeout)
+	defer cancel()
+
+	t := time.Now()
+	_, err := sdc.db.ExecContext(ctx, queries.put, key, data)
+	duration := time.Since(t)
+	if err != nil {
+		return err
+	}
+	sdc.metricsHandler.AddRequestData(sdc.getOpID(upsertMetricLabel), duration, metrics.NonErrorCode)
+
+	return nil
+}
+
+// Get will return the value for the provided key and table
+func (sdc *sqldbClient) Get(table TableID, key []byte) ([]byte, error) {
+	queries, ok := sdc.tables[table]
+	if !ok {
+		return nil, ErrTableNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sdc.operationTimeout)
+	defer cancel()
+
+	var value []byte
+	t := time.Now()
+	err := sdc.db.QueryRowContext(ctx, queries.get, key).Scan(&value)
+	duration := time.Since(t)
+	if err != nil {
+		return nil, convertNoRowsError(err)
+	}
+	sdc.metricsHandler.AddRequestData(sdc.getOpID(selectMetricLabel), duration, metrics.NonErrorCode)
+
+	return value, nil
+}
+
+// Has will return nil if the provided key exists in the table
+func (sdc *sqldbClient) Has(table TableID, key []byte) error {
+	queries, ok := sdc.tables[table]
+	if !ok {
+		return ErrTableNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sdc.operationTimeout)
+	defer cancel()
+
+	var found int
+	t := time.Now()
+	err := sdc.db.QueryRowContext(ctx, queries.has, key).Scan(&found)
+	duration := time.Since(t)
+	if err != nil {
+		return convertNoRowsError(err)
+	}
+	sdc.metricsHandler.AddRequestData(sdc.getOpID(selectMetricLabel), duration, metrics.NonErrorCode)
+
+	return nil
+}
+
+// Remove will remove the provided key from the table. The counters are never removed
+func (sdc *sqldbClient) Remove(table TableID, key []byte) error {
+	queries, ok := sdc.tables[table]
+	if !ok {
+		return ErrTableNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sdc.operationTimeout)
+	defer cancel()
+
+	t := time.Now()
+	_, err := sdc.db.ExecContext(ctx, queries.remove, key)
+	duration := time.Since(t)
+	if err != nil {
+		return err
+	}
+	sdc.metricsHandler.AddRequestData(sdc.getOpID(delMetricLabel), duration, metrics.NonErrorCode)
+
+	return nil
+}
+
+// PutIfNotExists will set the key value pair into specified table only if the key does not exist yet.
+// It returns true if the pair was written
+func (sdc *sqldbClient) PutIfNotExists(table TableID, key []byte, data []byte) (bool, error) {
+	queries, ok := sdc.tables[table]
+	if !ok {
+		return false, ErrTableNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sdc.operationTimeout)
+	defer cancel()
+
+	t := time.Now()
+	res, err := sdc.db.ExecContext(ctx, queries.putIfNotExists, key, data)
+	duration := time.Since(t)
+	if err != nil {
+		return false, err
+	}
+	sdc.metricsHandler.AddRequestData(sdc.getOpID(upsertMetricLabel), duration, metrics.NonErrorCode)
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// RemoveIfEqual will remove the provided key from the table only if it still holds the provided value
+func (sdc *sqldbClient) RemoveIfEqual(table TableID, key []byte, data []byte) error {
+	queries, ok := sdc.tables[table]
+	if !ok {
+		return ErrTableNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sdc.operationTimeout)
+	defer cancel()
+
+	t := time.Now()
+	_, err := sdc.db.ExecContext(ctx, queries.removeIfEqual, key, data)
+	duration := time.Since(t)
+	if err != nil {
+		return err
+	}
+	sdc.metricsHandler.AddRequestData(sdc.getOpID(delMetricLabel), duration, metrics.NonErrorCode)
+
+	return nil
+}
+
+// RangeKeys will call the handler for each key-value entry of the specified table, ordered by key, until the handler
+// returns false or the context is done. The counters are skipped. The entries are read in batches, so no connection
+// is held while the handler is called
+func (sdc *sqldbClient) RangeKeys(ctx context.Context, table TableID, handler func(key []byte, val []byte) bool) error {
+	queries, ok := sdc.tables[table]
+	if !ok {
+		return ErrTableNotFound
+	}
+
+	t := time.Now()
+	lastKey := make([]byte, 0)
+	for {
+		keys, values, err := sdc.readBatch(ctx, queries.rangeKeys, lastKey)
+		if err != nil {
+			return err
+		}
+
+		for i := range keys {
+			if !handler(keys[i], values[i]) {
+				sdc.metricsHandler.AddRequestData(sdc.getOpID(rangeMetricLabel), time.Since(t), metrics.NonErrorCode)
+				return nil
+			}
+		}
+
+		if len(keys) < rangeKeysBatchSize {
+			break
+		}
+		lastKey = keys[len(keys)-1]
+	}
+	sdc.metricsHandler.AddRequestData(sdc.getOpID(rangeMetricLabel), time.Since(t), metrics.NonErrorCode)
+
+	return nil
+}
+
+func (sdc *sqldbClient) readBatch(ctx context.Context, query string, lastKey []byte) ([][]byte, [][]byte, error) {
+	ctxBatch, cancel := context.WithTimeout(ctx, sdc.operationTimeout)
+	defer cancel()
+
+	rows, err := sdc.db.QueryContext(ctxBatch, query, lastKey, rangeKeysBatchSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		log.LogIfError(rows.Close())
+	}()
+
+	keys := make([][]byte, 0, rangeKeysBatchSize)
+	values := make([][]byte, 0, rangeKeysBatchSize)
+	for rows.Next() {
+		var key, value []byte
+		err = rows.Scan(&key, &value)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+
+	return keys, values, rows.Err()
+}
+
+// GetIndex will return the index value for the provided key and table
+func (sdc *sqldbClient) GetIndex(table TableID, key []byte) (uint32, error) {
+	queries, ok := sdc.tables[table]
+	if !ok {
+		return 0, ErrTableNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sdc.operationTimeout)
+	defer cancel()
+
+	var index uint32
+	t := time.Now()
+	err := sdc.db.QueryRowContext(ctx, queries.getIndex, key).Scan(&index)
+	duration := time.Since(t)
+	if err != nil {
+		return 0, convertNoRowsError(err)
+	}
+	sdc.metricsHandler.AddRequestData(sdc.getOpID(getIndexMetricLabel), duration, metrics.NonErrorCode)
+
+	return index, nil
+}
+
+// PutIndexIfNotExists will set an index value to the specified key if not already exists
+func (sdc *sqldbClient) PutIndexIfNotExists(table TableID, key []byte, index uint32) error {
+	queries, ok := sdc.tables[table]
+	if !ok {
+		return ErrTableNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sdc.operationTimeout)
+	defer cancel()
+
+	res, err := sdc.db.ExecContext(ctx, queries.putIndexIfNotExists, key, index)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+	log.Trace("PutIndexIfNotExists", "table", table, "key", string(key), "value", index, "rowsAffected", rowsAffected)
+
+	return nil
+}
+
+// PutIndexIfGreater will set the index value to the specified key if it is greater than the existing one
+func (sdc *sqldbClient) PutIndexIfGreater(table TableID, key []byte, index uint32) error {
+	queries, ok := sdc.tables[table]
+	if !ok {
+		return ErrTableNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sdc.operationTimeout)
+	defer cancel()
+
+	t := time.Now()
+	res, err := sdc.db.ExecContext(ctx, queries.putIndexIfGreater, key, index)
+	duration := time.Since(t)
+	if err != nil {
+		return err
+	}
+	sdc.metricsHandler.AddRequestData(sdc.getOpID(maxMetricLabel), duration, metrics.NonErrorCode)
+
+	rowsAffected, _ := res.RowsAffected()
+	log.Trace("PutIndexIfGreater", "table", table, "key", string(key), "value", index, "rowsAffected", rowsAffected)
+
+	return nil
+}
+
+// IncrementIndex will atomically increment the value for the provided key, within a single upsert statement
+func (sdc *sqldbClient) IncrementIndex(table TableID, key []byte) (uint32, error) {
+	queries, ok := sdc.tables[table]
+	if !ok {
+		return 0, ErrTableNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sdc.operationTimeout)
+	defer cancel()
+
+	var index uint32
+	t := time.Now()
+	err := sdc.db.QueryRowContext(ctx, queries.incrementIndex, key, incrementIndexStep).Scan(&index)
+	duration := time.Since(t)
+	if err != nil {
+		return 0, err
+	}
+	sdc.metricsHandler.AddRequestData(sdc.getOpID(incMetricLabel), duration, metrics.NonErrorCode)
+
+	log.Trace("IncrementIndex", "table", table, "key", string(key), "value", index)
+
+	return index, nil
+}
+
+func (sdc *sqldbClient) getOpID(operation string) string {
+	return fmt.Sprintf("%s-%s", sdc.metricPrefix, operation)
+}
+
+func convertNoRowsError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.ErrKeyNotFound
+	}
+
+	return err
+}
+
+// Close will close the sql db. It can be called multiple times
+func (sdc *sqldbClient) Close() error {
+	return sdc.db.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (sdc *sqldbClient) IsInterfaceNil() bool {
+	return sdc == nil
+}
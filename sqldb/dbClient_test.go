@@ -0,0 +1,398 @@
+package sqldb_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/sqldb"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/testscommon"
+)
+
+var usersTableID = sqldb.TableID(fmt.Sprintf("%s_%d", string(sqldb.UsersTableID), 0))
+
+func createSQLiteConfig(t *testing.T) config.SQLDBConfig {
+	return config.SQLDBConfig{
+		DSN:                   fmt.Sprintf("file:%s?_busy_timeout=5000", filepath.Join(t.TempDir(), "users.sqlite")),
+		OperationTimeoutInSec: 10,
+		MaxOpenConnections:    1,
+		NumUsersTables:        2,
+	}
+}
+
+func createSQLiteClient(t *testing.T) sqldb.SQLDBClient {
+	client, err := sqldb.CreateSQLDBClient(sqldb.SQLiteDialect, createSQLiteConfig(t), &testscommon.StatusMetricsStub{})
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	return client
+}
+
+func createMockArgsSQLDBClient(t *testing.T) sqldb.ArgsSQLDBClient {
+	db, err := sql.Open(string(sqldb.SQLiteDialect), createSQLiteConfig(t).DSN)
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return sqldb.ArgsSQLDBClient{
+		DB:                db,
+		Dialect:           sqldb.SQLiteDialect,
+		UsersTablesPrefix: string(sqldb.UsersTableID),
+		NumUsersTables:    2,
+		OperationTimeout:  time.Second,
+		MetricsHandler:    &testscommon.StatusMetricsStub{},
+	}
+}
+
+func TestNewSQLDBClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil db, should fail", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsSQLDBClient(t)
+		args.DB = nil
+		client, err := sqldb.NewClient(args)
+		require.Nil(t, client)
+		require.Equal(t, sqldb.ErrNilSQLDB, err)
+	})
+	t.Run("unknown dialect, should fail", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsSQLDBClient(t)
+		args.Dialect = "mysql"
+		client, err := sqldb.NewClient(args)
+		require.Nil(t, client)
+		require.True(t, errors.Is(err, sqldb.ErrUnknownDialect))
+	})
+	t.Run("invalid users tables prefix, should fail", func(t *testing.T) {
+		t.Parallel()
+
+		for _, prefix := range []string{"", "0users", `users"; DROP TABLE users_0; --`, "users-new"} {
+			args := createMockArgsSQLDBClient(t)
+			args.UsersTablesPrefix = prefix
+			client, err := sqldb.NewClient(args)
+			require.Nil(t, client)
+			require.True(t, errors.Is(err, sqldb.ErrInvalidUsersTablesPrefix), prefix)
+		}
+	})
+	t.Run("invalid num of users tables, should fail", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsSQLDBClient(t)
+		args.NumUsersTables = 0
+		client, err := sqldb.NewClient(args)
+		require.Nil(t, client)
+		require.True(t, errors.Is(err, core.ErrInvalidValue))
+	})
+	t.Run("invalid operation timeout, should fail", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsSQLDBClient(t)
+		args.OperationTimeout = 0
+		client, err := sqldb.NewClient(args)
+		require.Nil(t, client)
+		require.True(t, errors.Is(err, core.ErrInvalidValue))
+	})
+	t.Run("nil metrics handler, should fail", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsSQLDBClient(t)
+		args.MetricsHandler = nil
+		client, err := sqldb.NewClient(args)
+		require.Nil(t, client)
+		require.Equal(t, core.ErrNilMetricsHandler, err)
+	})
+	t.Run("closed db, should fail", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsSQLDBClient(t)
+		require.Nil(t, args.DB.Close())
+		client, err := sqldb.NewClient(args)
+		require.Nil(t, client)
+		require.NotNil(t, err)
+	})
+	t.Run("should work and keep the existing tables", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsSQLDBClient(t)
+		client, err := sqldb.NewClient(args)
+		require.Nil(t, err)
+		require.False(t, client.IsInterfaceNil())
+		require.Equal(t, []sqldb.TableID{"users_0", "users_1"}, client.GetAllTablesIDs())
+
+		require.Nil(t, client.Put(usersTableID, []byte("key"), []byte("data")))
+
+		client, err = sqldb.NewClient(args)
+		require.Nil(t, err)
+		data, err := client.Get(usersTableID, []byte("key"))
+		require.Nil(t, err)
+		require.Equal(t, []byte("data"), data)
+	})
+}
+
+func TestCreateSQLDBClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty dsn, should fail", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createSQLiteConfig(t)
+		cfg.DSN = ""
+		client, err := sqldb.CreateSQLDBClient(sqldb.SQLiteDialect, cfg, &testscommon.StatusMetricsStub{})
+		require.Nil(t, client)
+		require.NotNil(t, err)
+	})
+	t.Run("invalid operation timeout, should fail", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createSQLiteConfig(t)
+		cfg.OperationTimeoutInSec = 0
+		client, err := sqldb.CreateSQLDBClient(sqldb.SQLiteDialect, cfg, &testscommon.StatusMetricsStub{})
+		require.Nil(t, client)
+		require.True(t, errors.Is(err, core.ErrInvalidValue))
+	})
+	t.Run("unknown dialect, should fail", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := sqldb.CreateSQLDBClient("mysql", createSQLiteConfig(t), &testscommon.StatusMetricsStub{})
+		require.Nil(t, client)
+		require.True(t, errors.Is(err, sqldb.ErrUnknownDialect))
+	})
+	t.Run("should use the provided users tables prefix", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createSQLiteConfig(t)
+		cfg.UsersTablesPrefix = "users_v2"
+		client, err := sqldb.CreateSQLDBClient(sqldb.SQLiteDialect, cfg, &testscommon.StatusMetricsStub{})
+		require.Nil(t, err)
+		require.Equal(t, []sqldb.TableID{"users_v2_0", "users_v2_1"}, client.GetAllTablesIDs())
+		require.Nil(t, client.Close())
+		require.Nil(t, client.Close())
+	})
+}
+
+func TestSQLDBClient_UnknownTable(t *testing.T) {
+	t.Parallel()
+
+	client := createSQLiteClient(t)
+	table := sqldb.TableID("users_2")
+
+	require.Equal(t, sqldb.ErrTableNotFound, client.Put(table, []byte("key"), []byte("data")))
+	_, err := client.Get(table, []byte("key"))
+	require.Equal(t, sqldb.ErrTableNotFound, err)
+	require.Equal(t, sqldb.ErrTableNotFound, client.Has(table, []byte("key")))
+	require.Equal(t, sqldb.ErrTableNotFound, client.Remove(table, []byte("key")))
+	require.Equal(t, sqldb.ErrTableNotFound, client.RangeKeys(context.Background(), table, func(key []byte, val []byte) bool {
+		return true
+	}))
+	_, err = client.GetIndex(table, []byte("key"))
+	require.Equal(t, sqldb.ErrTableNotFound, err)
+	require.Equal(t, sqldb.ErrTableNotFound, client.PutIndexIfNotExists(table, []byte("key"), 0))
+	require.Equal(t, sqldb.ErrTableNotFound, client.PutIndexIfGreater(table, []byte("key"), 0))
+	_, err = client.IncrementIndex(table, []byte("key"))
+	require.Equal(t, sqldb.ErrTableNotFound, err)
+}
+
+func TestSQLDBClient_KeyValueOperations(t *testing.T) {
+	t.Parallel()
+
+	client := createSQLiteClient(t)
+	key := []byte{0, 1, 255, 'k'}
+
+	_, err := client.Get(usersTableID, key)
+	require.Equal(t, storage.ErrKeyNotFound, err)
+	require.Equal(t, storage.ErrKeyNotFound, client.Has(usersTableID, key))
+
+	require.Nil(t, client.Put(usersTableID, key, []byte("data")))
+	require.Nil(t, client.Put(usersTableID, key, []byte("new data")))
+	require.Nil(t, client.Has(usersTableID, key))
+	data, err := client.Get(usersTableID, key)
+	require.Nil(t, err)
+	require.Equal(t, []byte("new data"), data)
+
+	_, err = client.Get(sqldb.TableID("users_1"), key)
+	require.Equal(t, storage.ErrKeyNotFound, err)
+
+	require.Nil(t, client.Remove(usersTableID, key))
+	require.Nil(t, client.Remove(usersTableID, key))
+	_, err = client.Get(usersTableID, key)
+	require.Equal(t, storage.ErrKeyNotFound, err)
+}
+
+func TestSQLDBClient_ConditionalOperations(t *testing.T) {
+	t.Parallel()
+
+	client := createSQLiteClient(t)
+	key := []byte("key")
+
+	wasPut, err := client.PutIfNotExists(usersTableID, key, []byte("data"))
+	require.Nil(t, err)
+	require.True(t, wasPut)
+	wasPut, err = client.PutIfNotExists(usersTableID, key, []byte("other data"))
+	require.Nil(t, err)
+	require.False(t, wasPut)
+	data, err := client.Get(usersTableID, key)
+	require.Nil(t, err)
+	require.Equal(t, []byte("data"), data)
+
+	require.Nil(t, client.RemoveIfEqual(usersTableID, key, []byte("other data")))
+	require.Nil(t, client.Has(usersTableID, key))
+	require.Nil(t, client.RemoveIfEqual(usersTableID, key, []byte("data")))
+	require.Equal(t, storage.ErrKeyNotFound, client.Has(usersTableID, key))
+	require.Nil(t, client.RemoveIfEqual(usersTableID, key, []byte("data")))
+
+	_, err = client.PutIfNotExists(sqldb.TableID("missing"), key, []byte("data"))
+	require.Equal(t, sqldb.ErrTableNotFound, err)
+	require.Equal(t, sqldb.ErrTableNotFound, client.RemoveIfEqual(sqldb.TableID("missing"), key, []byte("data")))
+}
+
+func TestSQLDBClient_IndexOperations(t *testing.T) {
+	t.Parallel()
+
+	client := createSQLiteClient(t)
+	key := []byte("lastIndex")
+
+	_, err := client.GetIndex(usersTableID, key)
+	require.Equal(t, storage.ErrKeyNotFound, err)
+
+	require.Nil(t, client.PutIndexIfNotExists(usersTableID, key, 0))
+	require.Nil(t, client.PutIndexIfNotExists(usersTableID, key, 7))
+	index, err := client.GetIndex(usersTableID, key)
+	require.Nil(t, err)
+	require.Equal(t, uint32(0), index)
+
+	index, err = client.IncrementIndex(usersTableID, key)
+	require.Nil(t, err)
+	require.Equal(t, uint32(1), index)
+
+	require.Nil(t, client.PutIndexIfGreater(usersTableID, key, 5))
+	require.Nil(t, client.PutIndexIfGreater(usersTableID, key, 3))
+	index, err = client.GetIndex(usersTableID, key)
+	require.Nil(t, err)
+	require.Equal(t, uint32(5), index)
+
+	index, err = client.IncrementIndex(usersTableID, key)
+	require.Nil(t, err)
+	require.Equal(t, uint32(6), index)
+
+	// the counters are not key-value entries
+	require.Equal(t, storage.ErrKeyNotFound, client.Has(usersTableID, key))
+	require.Nil(t, client.Remove(usersTableID, key))
+	index, err = client.GetIndex(usersTableID, key)
+	require.Nil(t, err)
+	require.Equal(t, uint32(6), index)
+
+	otherKey := []byte("otherIndex")
+	index, err = client.IncrementIndex(usersTableID, otherKey)
+	require.Nil(t, err)
+	require.Equal(t, uint32(1), index)
+	require.Nil(t, client.PutIndexIfGreater(usersTableID, []byte("newIndex"), 4))
+	index, err = client.GetIndex(usersTableID, []byte("newIndex"))
+	require.Nil(t, err)
+	require.Equal(t, uint32(4), index)
+}
+
+func TestSQLDBClient_IncrementIndexConcurrently(t *testing.T) {
+	t.Parallel()
+
+	cfg := createSQLiteConfig(t)
+	cfg.MaxOpenConnections = 4
+	client, err := sqldb.CreateSQLDBClient(sqldb.SQLiteDialect, cfg, &testscommon.StatusMetricsStub{})
+	require.Nil(t, err)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	numCalls := 100
+	indexes := make(map[uint32]struct{})
+	var mutIndexes sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(numCalls)
+	for i := 0; i < numCalls; i++ {
+		go func() {
+			defer wg.Done()
+
+			index, errIncrement := client.IncrementIndex(usersTableID, []byte("lastIndex"))
+			assert.Nil(t, errIncrement)
+
+			mutIndexes.Lock()
+			indexes[index] = struct{}{}
+			mutIndexes.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, numCalls, len(indexes))
+	index, err := client.GetIndex(usersTableID, []byte("lastIndex"))
+	require.Nil(t, err)
+	assert.Equal(t, uint32(numCalls), index)
+}
+
+func TestSQLDBClient_RangeKeys(t *testing.T) {
+	t.Parallel()
+
+	client := createSQLiteClient(t)
+	numEntries := 250
+	expectedEntries := make(map[string]string)
+	for i := 0; i < numEntries; i++ {
+		key := fmt.Sprintf("key%03d", i)
+		value := fmt.Sprintf("value%d", i)
+		require.Nil(t, client.Put(usersTableID, []byte(key), []byte(value)))
+		expectedEntries[key] = value
+	}
+	_, err := client.IncrementIndex(usersTableID, []byte("lastIndex"))
+	require.Nil(t, err)
+	require.Nil(t, client.Put(sqldb.TableID("users_1"), []byte("other"), []byte("other")))
+
+	t.Run("should visit all the entries, skipping the counters", func(t *testing.T) {
+		t.Parallel()
+
+		entries := make(map[string]string)
+		err := client.RangeKeys(context.Background(), usersTableID, func(key []byte, val []byte) bool {
+			entries[string(key)] = string(val)
+
+			// the handler can use the client, as no connection is held while it is called
+			_, errGet := client.Get(usersTableID, key)
+			assert.Nil(t, errGet)
+			return true
+		})
+		require.Nil(t, err)
+		require.Equal(t, expectedEntries, entries)
+	})
+	t.Run("should stop when the handler returns false", func(t *testing.T) {
+		t.Parallel()
+
+		numVisited := 0
+		err := client.RangeKeys(context.Background(), usersTableID, func(key []byte, val []byte) bool {
+			numVisited++
+			return numVisited < 150
+		})
+		require.Nil(t, err)
+		require.Equal(t, 150, numVisited)
+	})
+	t.Run("done context should error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := client.RangeKeys(ctx, usersTableID, func(key []byte, val []byte) bool {
+			return true
+		})
+		require.Equal(t, context.Canceled, err)
+	})
+}
@@ -0,0 +1,15 @@
+package sqldb
+
+import "errors"
+
+// ErrNilSQLDB signals that a nil sql db has been provided
+var ErrNilSQLDB = errors.New("nil sql db")
+
+// ErrUnknownDialect signals that an unknown sql dialect has been provided
+var ErrUnknownDialect = errors.New("unknown sql dialect")
+
+// ErrTableNotFound signals that provided sql table is not available
+var ErrTableNotFound = errors.New("sql table not found")
+
+// ErrInvalidUsersTablesPrefix signals that an invalid prefix for the users tables has been provided
+var ErrInvalidUsersTablesPrefix = errors.New("invalid users tables prefix")
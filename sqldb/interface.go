@@ -0,0 +1,21 @@
+package sqldb
+
+import "context"
+
+// SQLDBClient defines what a sql db client should do
+type SQLDBClient interface {
+	Put(table TableID, key []byte, data []byte) error
+	Get(table TableID, key []byte) ([]byte, error)
+	Has(table TableID, key []byte) error
+	Remove(table TableID, key []byte) error
+	PutIfNotExists(table TableID, key []byte, data []byte) (bool, error)
+	RemoveIfEqual(table TableID, key []byte, data []byte) error
+	RangeKeys(ctx context.Context, table TableID, handler func(key []byte, val []byte) bool) error
+	GetIndex(table TableID, key []byte) (uint32, error)
+	PutIndexIfNotExists(table TableID, key []byte, index uint32) error
+	PutIndexIfGreater(table TableID, key []byte, index uint32) error
+	IncrementIndex(table TableID, key []byte) (uint32, error)
+	GetAllTablesIDs() []TableID
+	Close() error
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,70 @@
+package sqldb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	// registers the postgres driver
+	_ "github.com/lib/pq"
+	// registers the sqlite3 driver
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+)
+
+const minTimeoutInSec = 1
+
+var errEmptyDSN = errors.New("empty sql data source name")
+
+// CreateSQLDBClient will open the database with the driver of the provided dialect and create a new sql db client instance
+func CreateSQLDBClient(dialect Dialect, cfg config.SQLDBConfig, metricsHandler core.StatusMetricsHandler) (SQLDBClient, error) {
+	err := checkSQLDBConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	_, found := dialects[dialect]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDialect, dialect)
+	}
+
+	db, err := sql.Open(string(dialect), cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(int(cfg.MaxOpenConnections))
+
+	usersTablesPrefix := cfg.UsersTablesPrefix
+	if usersTablesPrefix == "" {
+		usersTablesPrefix = string(UsersTableID)
+	}
+
+	client, err := NewClient(ArgsSQLDBClient{
+		DB:                db,
+		Dialect:           dialect,
+		UsersTablesPrefix: usersTablesPrefix,
+		NumUsersTables:    cfg.NumUsersTables,
+		OperationTimeout:  time.Duration(cfg.OperationTimeoutInSec) * time.Second,
+		MetricsHandler:    metricsHandler,
+	})
+	if err != nil {
+		log.LogIfError(db.Close())
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func checkSQLDBConfig(cfg config.SQLDBConfig) error {
+	if cfg.DSN == "" {
+		return errEmptyDSN
+	}
+	if cfg.OperationTimeoutInSec < minTimeoutInSec {
+		return fmt.Errorf("%w for sql operation timeout: provided %d, minimum %d",
+			core.ErrInvalidValue, cfg.OperationTimeoutInSec, minTimeoutInSec)
+	}
+
+	return nil
+}
@@ -8,14 +8,14 @@ import (
 	logger "github.com/multiversx/mx-chain-logger-go"
 	storageGoFactory "github.com/multiversx/mx-chain-storage-go/factory"
 	"github.com/multiversx/mx-sdk-go/authentication/native"
-	"github.com/multiversx/mx-sdk-go/core/http"
 
 	"github.com/multiversx/mx-multi-factor-auth-go-service/api/middleware"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/chainSimulator"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/config"
-	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/factory"
 	storageFactory "github.com/multiversx/mx-multi-factor-auth-go-service/handlers/storage/factory"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/metrics"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/redis"
 )
 
 var log = logger.GetOrCreate("tcsRunner")
@@ -53,8 +53,46 @@ func (tr *tcsRunner) Start() error {
 		log.LogIfError(registeredUsersDB.Close())
 	}()
 
-	httpClient := http.NewHttpClientWrapper(nil, tr.configs.ExternalConfig.Api.NetworkAddress)
-	httpClientWrapper, err := core.NewHttpClientWrapper(httpClient)
+	auditSink, err := factory.CreateAuditSink(tr.configs, statusMetricsHandler)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		log.LogIfError(auditSink.Close())
+	}()
+
+	notifier, err := factory.CreateNotifier(tr.configs)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		log.LogIfError(notifier.Close())
+	}()
+
+	apiConfig := tr.configs.ExternalConfig.Api
+	if tr.configs.FlagsConfig.SimulateChain {
+		simulatorCfg := tr.configs.ExternalConfig.ChainSimulator
+		simulator, errCreate := chainSimulator.CreateChainSimulator(simulatorCfg)
+		if errCreate != nil {
+			return errCreate
+		}
+
+		apiConfig.NetworkAddress, err = simulator.Start(simulatorCfg.ListenAddress)
+		if err != nil {
+			return err
+		}
+		apiConfig.FallbackNetworkAddresses = nil
+
+		defer func() {
+			log.LogIfError(simulator.Close())
+		}()
+
+		log.Warn("the chain simulator is used instead of the API, for local testing only", "network address", apiConfig.NetworkAddress)
+	}
+
+	httpClient, httpClientWrapper, err := factory.CreateChainApiClients(apiConfig, statusMetricsHandler)
 	if err != nil {
 		return err
 	}
@@ -64,12 +102,32 @@ func (tr *tcsRunner) Start() error {
 		return err
 	}
 
-	secureOtpHandler, err := factory.CreateSecureOTPHandler(tr.configs)
+	// the rate limiter and the transaction policy share the same redis connection
+	redisStorer, err := redis.CreateRedisStorer(tr.configs.ExternalConfig.Redis)
 	if err != nil {
 		return err
 	}
 
-	serviceResolver, err := factory.CreateServiceResolver(tr.configs, cryptoComponents, httpClientWrapper, registeredUsersDB, twoFactorHandler, secureOtpHandler)
+	secureOtpHandler, err := factory.CreateSecureOTPHandler(tr.configs, redisStorer, notifier)
+	if err != nil {
+		return err
+	}
+
+	serviceResolver, err := factory.CreateServiceResolver(tr.configs, cryptoComponents, httpClientWrapper, registeredUsersDB, twoFactorHandler, secureOtpHandler, redisStorer, auditSink, notifier)
+	if err != nil {
+		return err
+	}
+
+	activationWorker, err := factory.CreateGuardianActivationWorker(tr.configs, serviceResolver)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		log.LogIfError(activationWorker.Close())
+	}()
+
+	auditLogger, err := factory.CreateAuditLogger(auditSink, cryptoComponents)
 	if err != nil {
 		return err
 	}
@@ -96,7 +154,7 @@ func (tr *tcsRunner) Start() error {
 
 	nativeAuthWhitelistHandler := middleware.NewNativeAuthWhitelistHandler(tr.configs.ApiRoutesConfig.APIPackages)
 
-	webServer, err := factory.StartWebServer(*tr.configs, serviceResolver, nativeAuthServer, tokenHandler, nativeAuthWhitelistHandler, statusMetricsHandler)
+	webServer, err := factory.StartWebServer(*tr.configs, serviceResolver, nativeAuthServer, tokenHandler, nativeAuthWhitelistHandler, statusMetricsHandler, auditLogger)
 	if err != nil {
 		return err
 	}
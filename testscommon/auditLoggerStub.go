@@ -0,0 +1,24 @@
+package testscommon
+
+import (
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+)
+
+// AuditLoggerStub -
+type AuditLoggerStub struct {
+	RecordCalled func(entry core.AuditEntry, txs []transaction.FrontendTransaction)
+}
+
+// Record -
+func (stub *AuditLoggerStub) Record(entry core.AuditEntry, txs []transaction.FrontendTransaction) {
+	if stub.RecordCalled != nil {
+		stub.RecordCalled(entry, txs)
+	}
+}
+
+// IsInterfaceNil -
+func (stub *AuditLoggerStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -0,0 +1,41 @@
+package testscommon
+
+import (
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+)
+
+// AuditSinkStub -
+type AuditSinkStub struct {
+	AppendCalled         func(entry core.AuditEntry) error
+	GetUserEntriesCalled func(userAddress string, offset uint32, limit uint32) ([]core.AuditEntry, error)
+	CloseCalled          func() error
+}
+
+// Append -
+func (stub *AuditSinkStub) Append(entry core.AuditEntry) error {
+	if stub.AppendCalled != nil {
+		return stub.AppendCalled(entry)
+	}
+	return nil
+}
+
+// GetUserEntries -
+func (stub *AuditSinkStub) GetUserEntries(userAddress string, offset uint32, limit uint32) ([]core.AuditEntry, error) {
+	if stub.GetUserEntriesCalled != nil {
+		return stub.GetUserEntriesCalled(userAddress, offset, limit)
+	}
+	return make([]core.AuditEntry, 0), nil
+}
+
+// Close -
+func (stub *AuditSinkStub) Close() error {
+	if stub.CloseCalled != nil {
+		return stub.CloseCalled()
+	}
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *AuditSinkStub) IsInterfaceNil() bool {
+	return stub == nil
+}
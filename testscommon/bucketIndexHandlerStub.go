@@ -1,13 +1,20 @@
 package testscommon
 
+import "context"
+
 // BucketIndexHandlerStub -
 type BucketIndexHandlerStub struct {
 	PutCalled                 func(key, data []byte) error
 	GetCalled                 func(key []byte) ([]byte, error)
 	HasCalled                 func(key []byte) error
+	RemoveCalled              func(key []byte) error
+	PutIfNotExistsCalled      func(key, data []byte) (bool, error)
+	RemoveIfEqualCalled       func(key, data []byte) error
 	CloseCalled               func() error
 	AllocateBucketIndexCalled func() (uint32, error)
+	ReserveBucketIndexCalled  func(index uint32) error
 	GetLastIndexCalled        func() (uint32, error)
+	RangeKeysCalled           func(ctx context.Context, handler func(key []byte, val []byte) bool) error
 }
 
 // Put -
@@ -34,6 +41,30 @@ func (stub *BucketIndexHandlerStub) Has(key []byte) error {
 	return nil
 }
 
+// Remove -
+func (stub *BucketIndexHandlerStub) Remove(key []byte) error {
+	if stub.RemoveCalled != nil {
+		return stub.RemoveCalled(key)
+	}
+	return nil
+}
+
+// PutIfNotExists -
+func (stub *BucketIndexHandlerStub) PutIfNotExists(key, data []byte) (bool, error) {
+	if stub.PutIfNotExistsCalled != nil {
+		return stub.PutIfNotExistsCalled(key, data)
+	}
+	return false, nil
+}
+
+// RemoveIfEqual -
+func (stub *BucketIndexHandlerStub) RemoveIfEqual(key, data []byte) error {
+	if stub.RemoveIfEqualCalled != nil {
+		return stub.RemoveIfEqualCalled(key, data)
+	}
+	return nil
+}
+
 // Close -
 func (stub *BucketIndexHandlerStub) Close() error {
 	if stub.CloseCalled != nil {
@@ -50,6 +81,14 @@ func (stub *BucketIndexHandlerStub) AllocateBucketIndex() (uint32, error) {
 	return 0, nil
 }
 
+// ReserveBucketIndex -
+func (stub *BucketIndexHandlerStub) ReserveBucketIndex(index uint32) error {
+	if stub.ReserveBucketIndexCalled != nil {
+		return stub.ReserveBucketIndexCalled(index)
+	}
+	return nil
+}
+
 // GetLastIndex -
 func (stub *BucketIndexHandlerStub) GetLastIndex() (uint32, error) {
 	if stub.GetLastIndexCalled != nil {
@@ -58,6 +97,14 @@ func (stub *BucketIndexHandlerStub) GetLastIndex() (uint32, error) {
 	return 0, nil
 }
 
+// RangeKeys -
+func (stub *BucketIndexHandlerStub) RangeKeys(ctx context.Context, handler func(key []byte, val []byte) bool) error {
+	if stub.RangeKeysCalled != nil {
+		return stub.RangeKeysCalled(ctx, handler)
+	}
+	return nil
+}
+
 // IsInterfaceNil -
 func (stub *BucketIndexHandlerStub) IsInterfaceNil() bool {
 	return stub == nil
@@ -2,22 +2,40 @@ package testscommon
 
 // EncryptorStub is a stub implementation of Encryptor
 type EncryptorStub struct {
-	EncryptDataCalled func(data []byte) ([]byte, error)
-	DecryptDataCalled func(data []byte) ([]byte, error)
+	CurrentKeyIDCalled      func() uint32
+	EncryptDataCalled       func(data []byte, associatedData []byte) ([]byte, error)
+	DecryptDataCalled       func(keyID uint32, data []byte, associatedData []byte) ([]byte, error)
+	DecryptLegacyDataCalled func(keyID uint32, data []byte) ([]byte, error)
+}
+
+// CurrentKeyID returns the id of the key used for encryption
+func (es *EncryptorStub) CurrentKeyID() uint32 {
+	if es.CurrentKeyIDCalled != nil {
+		return es.CurrentKeyIDCalled()
+	}
+	return 0
 }
 
 // EncryptData encrypts the provided data
-func (es *EncryptorStub) EncryptData(data []byte) ([]byte, error) {
+func (es *EncryptorStub) EncryptData(data []byte, associatedData []byte) ([]byte, error) {
 	if es.EncryptDataCalled != nil {
-		return es.EncryptDataCalled(data)
+		return es.EncryptDataCalled(data, associatedData)
 	}
 	return data, nil
 }
 
 // DecryptData decrypts the provided data
-func (es *EncryptorStub) DecryptData(data []byte) ([]byte, error) {
+func (es *EncryptorStub) DecryptData(keyID uint32, data []byte, associatedData []byte) ([]byte, error) {
 	if es.DecryptDataCalled != nil {
-		return es.DecryptDataCalled(data)
+		return es.DecryptDataCalled(keyID, data, associatedData)
+	}
+	return data, nil
+}
+
+// DecryptLegacyData decrypts the provided data, encrypted in the legacy format
+func (es *EncryptorStub) DecryptLegacyData(keyID uint32, data []byte) ([]byte, error) {
+	if es.DecryptLegacyDataCalled != nil {
+		return es.DecryptLegacyDataCalled(keyID, data)
 	}
 	return data, nil
 }
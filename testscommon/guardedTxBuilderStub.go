@@ -8,6 +8,7 @@ import (
 // GuardedTxBuilderStub -
 type GuardedTxBuilderStub struct {
 	ApplyGuardianSignatureCalled func(cryptoHolderGuardian core.CryptoComponentsHolder, tx *transaction.FrontendTransaction) error
+	ComputeTxHashCalled          func(tx *transaction.FrontendTransaction) ([]byte, error)
 }
 
 // ApplyGuardianSignature -
@@ -18,6 +19,14 @@ func (stub *GuardedTxBuilderStub) ApplyGuardianSignature(cryptoHolderGuardian co
 	return nil
 }
 
+// ComputeTxHash -
+func (stub *GuardedTxBuilderStub) ComputeTxHash(tx *transaction.FrontendTransaction) ([]byte, error) {
+	if stub.ComputeTxHashCalled != nil {
+		return stub.ComputeTxHashCalled(tx)
+	}
+	return nil, nil
+}
+
 // IsInterfaceNil -
 func (stub *GuardedTxBuilderStub) IsInterfaceNil() bool {
 	return stub == nil
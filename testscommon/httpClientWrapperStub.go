@@ -9,8 +9,10 @@ import (
 
 // HttpClientWrapperStub -
 type HttpClientWrapperStub struct {
-	GetAccountCalled      func(ctx context.Context, address string) (*data.Account, error)
-	GetGuardianDataCalled func(ctx context.Context, address string) (*api.GuardianData, error)
+	GetAccountCalled             func(ctx context.Context, address string) (*data.Account, error)
+	GetGuardianDataCalled        func(ctx context.Context, address string) (*api.GuardianData, error)
+	GetFreshGuardianDataCalled   func(ctx context.Context, address string) (*api.GuardianData, error)
+	InvalidateGuardianDataCalled func(address string)
 }
 
 // GetAccount -
@@ -29,6 +31,21 @@ func (stub *HttpClientWrapperStub) GetGuardianData(ctx context.Context, address
 	return &api.GuardianData{}, nil
 }
 
+// GetFreshGuardianData -
+func (stub *HttpClientWrapperStub) GetFreshGuardianData(ctx context.Context, address string) (*api.GuardianData, error) {
+	if stub.GetFreshGuardianDataCalled != nil {
+		return stub.GetFreshGuardianDataCalled(ctx, address)
+	}
+	return &api.GuardianData{}, nil
+}
+
+// InvalidateGuardianData -
+func (stub *HttpClientWrapperStub) InvalidateGuardianData(address string) {
+	if stub.InvalidateGuardianDataCalled != nil {
+		stub.InvalidateGuardianDataCalled(address)
+	}
+}
+
 // IsInterfaceNil -
 func (stub *HttpClientWrapperStub) IsInterfaceNil() bool {
 	return stub == nil
@@ -4,14 +4,14 @@ import crypto "github.com/multiversx/mx-chain-crypto-go"
 
 // KeysGeneratorStub -
 type KeysGeneratorStub struct {
-	GenerateManagedKeyCalled func() (crypto.PrivateKey, error)
+	GenerateManagedKeyCalled func(keyID uint32) (crypto.PrivateKey, error)
 	GenerateKeysCalled       func(index uint32) ([]crypto.PrivateKey, error)
 }
 
 // GenerateManagedKey -
-func (stub *KeysGeneratorStub) GenerateManagedKey() (crypto.PrivateKey, error) {
+func (stub *KeysGeneratorStub) GenerateManagedKey(keyID uint32) (crypto.PrivateKey, error) {
 	if stub.GenerateManagedKeyCalled != nil {
-		return stub.GenerateManagedKeyCalled()
+		return stub.GenerateManagedKeyCalled(keyID)
 	}
 	return nil, nil
 }
@@ -1,11 +1,13 @@
 package testscommon
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
 	"github.com/multiversx/mx-chain-storage-go/common"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/mongodb"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type collCache struct {
@@ -88,11 +90,73 @@ func (m *mongoDBClientMock) Has(coll mongodb.CollectionID, key []byte) error {
 	return nil
 }
 
+// InsertOne -
+func (m *mongoDBClientMock) InsertOne(coll mongodb.CollectionID, document interface{}) error {
+	return nil
+}
+
+// Find -
+func (m *mongoDBClientMock) Find(coll mongodb.CollectionID, filter interface{}, opts *options.FindOptions, results interface{}) error {
+	return nil
+}
+
 // Remove -
 func (m *mongoDBClientMock) Remove(coll mongodb.CollectionID, key []byte) error {
 	return nil
 }
 
+// PutIfNotExists -
+func (m *mongoDBClientMock) PutIfNotExists(coll mongodb.CollectionID, key []byte, data []byte) (bool, error) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	collection, ok := m.collections[coll]
+	if !ok {
+		return false, mongodb.ErrCollectionNotFound
+	}
+
+	_, exists := collection.cache[string(key)]
+	if exists {
+		return false, nil
+	}
+	collection.cache[string(key)] = data
+
+	return true, nil
+}
+
+// RemoveIfEqual -
+func (m *mongoDBClientMock) RemoveIfEqual(coll mongodb.CollectionID, key []byte, data []byte) error {
+	return nil
+}
+
+// RangeKeys -
+func (m *mongoDBClientMock) RangeKeys(ctx context.Context, coll mongodb.CollectionID, handler func(key []byte, val []byte) bool) error {
+	m.mut.RLock()
+	collection, ok := m.collections[coll]
+	if !ok {
+		m.mut.RUnlock()
+		return mongodb.ErrCollectionNotFound
+	}
+
+	entries := make(map[string][]byte, len(collection.cache))
+	for key, val := range collection.cache {
+		entries[key] = val
+	}
+	m.mut.RUnlock()
+
+	for key, val := range entries {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+		if !handler([]byte(key), val) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
 // GetIndex -
 func (m *mongoDBClientMock) GetIndex(coll mongodb.CollectionID, key []byte) (uint32, error) {
 	return 0, nil
@@ -108,6 +172,11 @@ func (m *mongoDBClientMock) PutIndexIfNotExists(collID mongodb.CollectionID, key
 	return nil
 }
 
+// PutIndexIfGreater -
+func (m *mongoDBClientMock) PutIndexIfGreater(collID mongodb.CollectionID, key []byte, index uint32) error {
+	return nil
+}
+
 // GetAllCollectionsIDs -
 func (m *mongoDBClientMock) GetAllCollectionsIDs() []mongodb.CollectionID {
 	return m.collectionsIDs
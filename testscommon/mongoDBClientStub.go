@@ -1,18 +1,28 @@
 package testscommon
 
 import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+
 	"github.com/multiversx/mx-multi-factor-auth-go-service/mongodb"
 )
 
 // MongoDBClientStub implemented mongodb client wraper interface
 type MongoDBClientStub struct {
 	PutCalled                  func(coll mongodb.CollectionID, key []byte, data []byte) error
+	InsertOneCalled            func(coll mongodb.CollectionID, document interface{}) error
+	FindCalled                 func(coll mongodb.CollectionID, filter interface{}, opts *options.FindOptions, results interface{}) error
 	GetCalled                  func(coll mongodb.CollectionID, key []byte) ([]byte, error)
 	HasCalled                  func(coll mongodb.CollectionID, key []byte) error
 	RemoveCalled               func(coll mongodb.CollectionID, key []byte) error
+	PutIfNotExistsCalled       func(coll mongodb.CollectionID, key []byte, data []byte) (bool, error)
+	RemoveIfEqualCalled        func(coll mongodb.CollectionID, key []byte, data []byte) error
+	RangeKeysCalled            func(ctx context.Context, coll mongodb.CollectionID, handler func(key []byte, val []byte) bool) error
 	GetIndexCalled             func(collID mongodb.CollectionID, key []byte) (uint32, error)
 	IncrementIndexCalled       func(collID mongodb.CollectionID, key []byte) (uint32, error)
 	PutIndexIfNotExistsCalled  func(collID mongodb.CollectionID, key []byte, index uint32) error
+	PutIndexIfGreaterCalled    func(collID mongodb.CollectionID, key []byte, index uint32) error
 	GetAllCollectionsIDsCalled func() []mongodb.CollectionID
 	CloseCalled                func() error
 }
@@ -26,6 +36,24 @@ func (m *MongoDBClientStub) Put(coll mongodb.CollectionID, key []byte, data []by
 	return nil
 }
 
+// InsertOne -
+func (m *MongoDBClientStub) InsertOne(coll mongodb.CollectionID, document interface{}) error {
+	if m.InsertOneCalled != nil {
+		return m.InsertOneCalled(coll, document)
+	}
+
+	return nil
+}
+
+// Find -
+func (m *MongoDBClientStub) Find(coll mongodb.CollectionID, filter interface{}, opts *options.FindOptions, results interface{}) error {
+	if m.FindCalled != nil {
+		return m.FindCalled(coll, filter, opts, results)
+	}
+
+	return nil
+}
+
 // Get -
 func (m *MongoDBClientStub) Get(coll mongodb.CollectionID, key []byte) ([]byte, error) {
 	if m.GetCalled != nil {
@@ -53,6 +81,33 @@ func (m *MongoDBClientStub) Remove(coll mongodb.CollectionID, key []byte) error
 	return nil
 }
 
+// PutIfNotExists -
+func (m *MongoDBClientStub) PutIfNotExists(coll mongodb.CollectionID, key []byte, data []byte) (bool, error) {
+	if m.PutIfNotExistsCalled != nil {
+		return m.PutIfNotExistsCalled(coll, key, data)
+	}
+
+	return false, nil
+}
+
+// RemoveIfEqual -
+func (m *MongoDBClientStub) RemoveIfEqual(coll mongodb.CollectionID, key []byte, data []byte) error {
+	if m.RemoveIfEqualCalled != nil {
+		return m.RemoveIfEqualCalled(coll, key, data)
+	}
+
+	return nil
+}
+
+// RangeKeys -
+func (m *MongoDBClientStub) RangeKeys(ctx context.Context, coll mongodb.CollectionID, handler func(key []byte, val []byte) bool) error {
+	if m.RangeKeysCalled != nil {
+		return m.RangeKeysCalled(ctx, coll, handler)
+	}
+
+	return nil
+}
+
 // GetIndex -
 func (m *MongoDBClientStub) GetIndex(coll mongodb.CollectionID, key []byte) (uint32, error) {
 	if m.GetIndexCalled != nil {
@@ -80,6 +135,15 @@ func (m *MongoDBClientStub) PutIndexIfNotExists(collID mongodb.CollectionID, key
 	return nil
 }
 
+// PutIndexIfGreater -
+func (m *MongoDBClientStub) PutIndexIfGreater(collID mongodb.CollectionID, key []byte, index uint32) error {
+	if m.PutIndexIfGreaterCalled != nil {
+		return m.PutIndexIfGreaterCalled(collID, key, index)
+	}
+
+	return nil
+}
+
 // GetAllCollectionsIDs -
 func (m *MongoDBClientStub) GetAllCollectionsIDs() []mongodb.CollectionID {
 	if m.GetAllCollectionsIDsCalled != nil {
@@ -0,0 +1,29 @@
+package testscommon
+
+import "github.com/multiversx/mx-multi-factor-auth-go-service/core"
+
+// NotifierStub -
+type NotifierStub struct {
+	NotifyCalled func(event core.SecurityEvent)
+	CloseCalled  func() error
+}
+
+// Notify -
+func (stub *NotifierStub) Notify(event core.SecurityEvent) {
+	if stub.NotifyCalled != nil {
+		stub.NotifyCalled(event)
+	}
+}
+
+// Close -
+func (stub *NotifierStub) Close() error {
+	if stub.CloseCalled != nil {
+		return stub.CloseCalled()
+	}
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *NotifierStub) IsInterfaceNil() bool {
+	return stub == nil
+}
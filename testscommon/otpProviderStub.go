@@ -1,29 +1,28 @@
 package testscommon
 
 import (
-	"crypto"
-
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
 )
 
 // OTPProviderStub -
 type OTPProviderStub struct {
-	GenerateTOTPCalled  func(account string, hash crypto.Hash) (handlers.OTP, error)
-	TOTPFromBytesCalled func(encryptedMessage []byte) (handlers.OTP, error)
+	GenerateOTPCalled  func(account string, params core.OTPParams) (handlers.OTP, error)
+	OTPFromBytesCalled func(otpBytes []byte, params core.OTPParams) (handlers.OTP, error)
 }
 
-// GenerateTOTP -
-func (stub *OTPProviderStub) GenerateTOTP(account string, hash crypto.Hash) (handlers.OTP, error) {
-	if stub.GenerateTOTPCalled != nil {
-		return stub.GenerateTOTPCalled(account, hash)
+// GenerateOTP -
+func (stub *OTPProviderStub) GenerateOTP(account string, params core.OTPParams) (handlers.OTP, error) {
+	if stub.GenerateOTPCalled != nil {
+		return stub.GenerateOTPCalled(account, params)
 	}
 	return nil, nil
 }
 
-// TOTPFromBytes -
-func (stub *OTPProviderStub) TOTPFromBytes(encryptedMessage []byte) (handlers.OTP, error) {
-	if stub.TOTPFromBytesCalled != nil {
-		return stub.TOTPFromBytesCalled(encryptedMessage)
+// OTPFromBytes -
+func (stub *OTPProviderStub) OTPFromBytes(otpBytes []byte, params core.OTPParams) (handlers.OTP, error) {
+	if stub.OTPFromBytesCalled != nil {
+		return stub.OTPFromBytesCalled(otpBytes, params)
 	}
 	return nil, nil
 }
@@ -0,0 +1,30 @@
+package testscommon
+
+import "context"
+
+// PublisherStub -
+type PublisherStub struct {
+	PublishCalled func(ctx context.Context, channel string, message []byte) error
+	CloseCalled   func() error
+}
+
+// Publish -
+func (stub *PublisherStub) Publish(ctx context.Context, channel string, message []byte) error {
+	if stub.PublishCalled != nil {
+		return stub.PublishCalled(ctx, channel, message)
+	}
+	return nil
+}
+
+// Close -
+func (stub *PublisherStub) Close() error {
+	if stub.CloseCalled != nil {
+		return stub.CloseCalled()
+	}
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *PublisherStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -10,6 +10,7 @@ import (
 // RateLimiterMock -
 type RateLimiterMock struct {
 	trials      map[string]int
+	groups      map[string]map[string]struct{}
 	mutTrials   sync.RWMutex
 	maxFailures int
 	periodLimit time.Duration
@@ -19,6 +20,7 @@ type RateLimiterMock struct {
 func NewRateLimiterMock(maxFailures int, periodLimit int) *RateLimiterMock {
 	return &RateLimiterMock{
 		trials:      make(map[string]int),
+		groups:      make(map[string]map[string]struct{}),
 		maxFailures: maxFailures,
 		periodLimit: time.Duration(periodLimit) * time.Second,
 	}
@@ -35,8 +37,10 @@ func (r *RateLimiterMock) CheckAllowedAndIncreaseTrials(key string, _ redis.Mode
 		return &redis.RateLimiterResult{Allowed: true, Remaining: r.maxFailures}, nil
 	}
 
+	limitReached := false
 	if r.trials[key] < r.maxFailures {
 		r.trials[key]++
+		limitReached = r.trials[key] == r.maxFailures
 	}
 
 	remaining := r.maxFailures - r.trials[key]
@@ -46,7 +50,30 @@ func (r *RateLimiterMock) CheckAllowedAndIncreaseTrials(key string, _ redis.Mode
 		allowed = false
 	}
 
-	return &redis.RateLimiterResult{Allowed: allowed, Remaining: remaining}, nil
+	return &redis.RateLimiterResult{Allowed: allowed, Remaining: remaining, LimitReached: limitReached}, nil
+}
+
+// CheckAllowedAndIncreaseTrialsInGroup -
+func (r *RateLimiterMock) CheckAllowedAndIncreaseTrialsInGroup(group string, key string, mode redis.Mode) (*redis.RateLimiterResult, error) {
+	r.mutTrials.Lock()
+	_, exists := r.groups[group]
+	if !exists {
+		r.groups[group] = make(map[string]struct{})
+	}
+	r.groups[group][key] = struct{}{}
+	r.mutTrials.Unlock()
+
+	return r.CheckAllowedAndIncreaseTrials(key, mode)
+}
+
+// GetTrials -
+func (r *RateLimiterMock) GetTrials(key string, _ redis.Mode) (*redis.RateLimiterResult, error) {
+	r.mutTrials.RLock()
+	defer r.mutTrials.RUnlock()
+
+	remaining := r.maxFailures - r.trials[key]
+
+	return &redis.RateLimiterResult{Allowed: remaining > 0, Remaining: remaining}, nil
 }
 
 // SetSecurityModeNoExpire -
@@ -69,6 +96,24 @@ func (r *RateLimiterMock) Reset(key string) error {
 	return nil
 }
 
+// Remove -
+func (r *RateLimiterMock) Remove(key string) error {
+	return r.Reset(key)
+}
+
+// RemoveGroup -
+func (r *RateLimiterMock) RemoveGroup(group string) error {
+	r.mutTrials.Lock()
+	defer r.mutTrials.Unlock()
+
+	for key := range r.groups[group] {
+		delete(r.trials, key)
+	}
+	delete(r.groups, group)
+
+	return nil
+}
+
 // DecrementSecurityFailedTrials -
 func (r *RateLimiterMock) DecrementSecurityFailedTrials(key string) error {
 	r.mutTrials.Lock()
@@ -8,14 +8,18 @@ import (
 
 // RateLimiterStub -
 type RateLimiterStub struct {
-	CheckAllowedAndIncreaseTrialsCalled func(key string, mode redis.Mode) (*redis.RateLimiterResult, error)
-	DecrementSecurityFailuresCalled     func(key string) error
-	ResetCalled                         func(key string) error
-	PeriodCalled                        func(mode redis.Mode) time.Duration
-	RateCalled                          func(mode redis.Mode) int
-	SetSecurityModeNoExpireCalled       func(key string) error
-	UnsetSecurityModeNoExpireCalled     func(key string) error
-	ExtendSecurityModeCalled            func(key string) error
+	CheckAllowedAndIncreaseTrialsCalled        func(key string, mode redis.Mode) (*redis.RateLimiterResult, error)
+	CheckAllowedAndIncreaseTrialsInGroupCalled func(group string, key string, mode redis.Mode) (*redis.RateLimiterResult, error)
+	GetTrialsCalled                            func(key string, mode redis.Mode) (*redis.RateLimiterResult, error)
+	DecrementSecurityFailuresCalled            func(key string) error
+	ResetCalled                                func(key string) error
+	RemoveCalled                               func(key string) error
+	RemoveGroupCalled                          func(group string) error
+	PeriodCalled                               func(mode redis.Mode) time.Duration
+	RateCalled                                 func(mode redis.Mode) int
+	SetSecurityModeNoExpireCalled              func(key string) error
+	UnsetSecurityModeNoExpireCalled            func(key string) error
+	ExtendSecurityModeCalled                   func(key string) error
 }
 
 // CheckAllowedAndIncreaseTrials -
@@ -27,6 +31,24 @@ func (r *RateLimiterStub) CheckAllowedAndIncreaseTrials(key string, mode redis.M
 	return nil, nil
 }
 
+// CheckAllowedAndIncreaseTrialsInGroup -
+func (r *RateLimiterStub) CheckAllowedAndIncreaseTrialsInGroup(group string, key string, mode redis.Mode) (*redis.RateLimiterResult, error) {
+	if r.CheckAllowedAndIncreaseTrialsInGroupCalled != nil {
+		return r.CheckAllowedAndIncreaseTrialsInGroupCalled(group, key, mode)
+	}
+
+	return nil, nil
+}
+
+// GetTrials -
+func (r *RateLimiterStub) GetTrials(key string, mode redis.Mode) (*redis.RateLimiterResult, error) {
+	if r.GetTrialsCalled != nil {
+		return r.GetTrialsCalled(key, mode)
+	}
+
+	return nil, nil
+}
+
 // DecrementSecurityFailedTrials -
 func (r *RateLimiterStub) DecrementSecurityFailedTrials(key string) error {
 	if r.DecrementSecurityFailuresCalled != nil {
@@ -88,6 +110,24 @@ func (r *RateLimiterStub) ExtendSecurityMode(key string) error {
 	return nil
 }
 
+// Remove -
+func (r *RateLimiterStub) Remove(key string) error {
+	if r.RemoveCalled != nil {
+		return r.RemoveCalled(key)
+	}
+
+	return nil
+}
+
+// RemoveGroup -
+func (r *RateLimiterStub) RemoveGroup(group string) error {
+	if r.RemoveGroupCalled != nil {
+		return r.RemoveGroupCalled(group)
+	}
+
+	return nil
+}
+
 // IsInterfaceNil -
 func (r *RateLimiterStub) IsInterfaceNil() bool {
 	return r == nil
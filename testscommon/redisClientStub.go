@@ -15,6 +15,12 @@ type RedisClientStub struct {
 	SetGreaterExpireTTLCalled    func(ctx context.Context, key string, ttl time.Duration) (bool, error)
 	ResetCounterAndKeepTTLCalled func(ctx context.Context, key string) error
 	ExpireTimeCalled             func(ctx context.Context, key string) (time.Duration, error)
+	GetCounterCalled             func(ctx context.Context, key string) (int64, error)
+	GetValueCalled               func(ctx context.Context, key string) ([]byte, error)
+	UpdateValueCalled            func(ctx context.Context, key string, ttl time.Duration, updateHandler func(value []byte) ([]byte, error)) error
+	DeleteCalled                 func(ctx context.Context, keys ...string) error
+	AddToSetCalled               func(ctx context.Context, key string, member string, ttl time.Duration) error
+	GetSetMembersCalled          func(ctx context.Context, key string) ([]string, error)
 	IsConnectedCalled            func(ctx context.Context) bool
 }
 
@@ -88,6 +94,60 @@ func (r *RedisClientStub) ExpireTime(ctx context.Context, key string) (time.Dura
 	return 0, nil
 }
 
+// GetCounter -
+func (r *RedisClientStub) GetCounter(ctx context.Context, key string) (int64, error) {
+	if r.GetCounterCalled != nil {
+		return r.GetCounterCalled(ctx, key)
+	}
+
+	return 0, nil
+}
+
+// GetValue -
+func (r *RedisClientStub) GetValue(ctx context.Context, key string) ([]byte, error) {
+	if r.GetValueCalled != nil {
+		return r.GetValueCalled(ctx, key)
+	}
+
+	return nil, nil
+}
+
+// UpdateValue -
+func (r *RedisClientStub) UpdateValue(ctx context.Context, key string, ttl time.Duration, updateHandler func(value []byte) ([]byte, error)) error {
+	if r.UpdateValueCalled != nil {
+		return r.UpdateValueCalled(ctx, key, ttl, updateHandler)
+	}
+
+	return nil
+}
+
+// Delete -
+func (r *RedisClientStub) Delete(ctx context.Context, keys ...string) error {
+	if r.DeleteCalled != nil {
+		return r.DeleteCalled(ctx, keys...)
+	}
+
+	return nil
+}
+
+// AddToSet -
+func (r *RedisClientStub) AddToSet(ctx context.Context, key string, member string, ttl time.Duration) error {
+	if r.AddToSetCalled != nil {
+		return r.AddToSetCalled(ctx, key, member, ttl)
+	}
+
+	return nil
+}
+
+// GetSetMembers -
+func (r *RedisClientStub) GetSetMembers(ctx context.Context, key string) ([]string, error) {
+	if r.GetSetMembersCalled != nil {
+		return r.GetSetMembersCalled(ctx, key)
+	}
+
+	return nil, nil
+}
+
 // IsConnected -
 func (r *RedisClientStub) IsConnected(ctx context.Context) bool {
 	if r.IsConnectedCalled != nil {
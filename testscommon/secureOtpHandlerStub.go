@@ -5,7 +5,10 @@ import "github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
 // SecureOtpHandlerStub is a stub implementation of the SecureOtpHandler interface
 type SecureOtpHandlerStub struct {
 	IsVerificationAllowedAndIncreaseTrialsCalled func(account string, ip string) (*requests.OTPCodeVerifyData, error)
+	GetVerificationTrialsCalled                  func(account string, ip string) (*requests.OTPCodeVerifyData, error)
 	ResetCalled                                  func(account string, ip string)
+	ResetFreezeCalled                            func(account string, ip string) error
+	ResetSecurityModeCalled                      func(account string) error
 	DecrementSecurityModeFailedTrialsCalled      func(account string) error
 	SetSecurityModeNoExpireCalled                func(key string) error
 	UnsetSecurityModeNoExpireCalled              func(key string) error
@@ -14,6 +17,7 @@ type SecureOtpHandlerStub struct {
 	SecurityModeBackOffTimeCalled                func() uint64
 	SecurityModeMaxFailuresCalled                func() uint64
 	ExtendSecurityModeCalled                     func(account string) error
+	RemoveAccountCalled                          func(account string) error
 }
 
 // IsVerificationAllowedAndIncreaseTrials returns true if the verification is allowed for the given account and ip
@@ -25,6 +29,15 @@ func (stub *SecureOtpHandlerStub) IsVerificationAllowedAndIncreaseTrials(account
 	return &requests.OTPCodeVerifyData{}, nil
 }
 
+// GetVerificationTrials -
+func (stub *SecureOtpHandlerStub) GetVerificationTrials(account string, ip string) (*requests.OTPCodeVerifyData, error) {
+	if stub.GetVerificationTrialsCalled != nil {
+		return stub.GetVerificationTrialsCalled(account, ip)
+	}
+
+	return &requests.OTPCodeVerifyData{}, nil
+}
+
 // SetSecurityModeNoExpire -
 func (stub *SecureOtpHandlerStub) SetSecurityModeNoExpire(key string) error {
 	if stub.SetSecurityModeNoExpireCalled != nil {
@@ -48,6 +61,24 @@ func (stub *SecureOtpHandlerStub) Reset(account string, ip string) {
 	}
 }
 
+// ResetFreeze -
+func (stub *SecureOtpHandlerStub) ResetFreeze(account string, ip string) error {
+	if stub.ResetFreezeCalled != nil {
+		return stub.ResetFreezeCalled(account, ip)
+	}
+
+	return nil
+}
+
+// ResetSecurityMode -
+func (stub *SecureOtpHandlerStub) ResetSecurityMode(account string) error {
+	if stub.ResetSecurityModeCalled != nil {
+		return stub.ResetSecurityModeCalled(account)
+	}
+
+	return nil
+}
+
 // DecrementSecurityModeFailedTrials decrements the security mode failed trials
 func (stub *SecureOtpHandlerStub) DecrementSecurityModeFailedTrials(account string) error {
 	if stub.DecrementSecurityModeFailedTrialsCalled != nil {
@@ -100,6 +131,15 @@ func (stub *SecureOtpHandlerStub) ExtendSecurityMode(account string) error {
 	return nil
 }
 
+// RemoveAccount -
+func (stub *SecureOtpHandlerStub) RemoveAccount(account string) error {
+	if stub.RemoveAccountCalled != nil {
+		return stub.RemoveAccountCalled(account)
+	}
+
+	return nil
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (stub *SecureOtpHandlerStub) IsInterfaceNil() bool {
 	return stub == nil
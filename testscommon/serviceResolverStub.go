@@ -1,6 +1,8 @@
 package testscommon
 
 import (
+	"context"
+
 	"github.com/multiversx/mx-sdk-go/core"
 
 	tcsCore "github.com/multiversx/mx-multi-factor-auth-go-service/core"
@@ -9,32 +11,50 @@ import (
 
 // ServiceResolverStub -
 type ServiceResolverStub struct {
-	GetGuardianAddressCalled        func(userAddress core.AddressHandler) (string, error)
-	RegisterUserCalled              func(userAddress core.AddressHandler, request requests.RegistrationPayload) (*requests.OTP, string, error)
-	VerifyCodeCalled                func(userAddress core.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error)
-	SetSecurityModeNoExpireCalled   func(userIp string, request requests.SecurityModeNoExpire) (*requests.OTPCodeVerifyData, error)
-	UnsetSecurityModeNoExpireCalled func(userIp string, request requests.SecurityModeNoExpire) (*requests.OTPCodeVerifyData, error)
-	SignMessageCalled               func(userIp string, request requests.SignMessage) ([]byte, *requests.OTPCodeVerifyData, error)
-	SignTransactionCalled           func(userIp string, request requests.SignTransaction) ([]byte, *requests.OTPCodeVerifyData, error)
-	SignMultipleTransactionsCalled  func(userIp string, request requests.SignMultipleTransactions) ([][]byte, *requests.OTPCodeVerifyData, error)
-	RegisteredUsersCalled           func() (uint32, error)
-	TcsConfigCalled                 func() *tcsCore.TcsConfig
+	GetGuardianAddressCalled           func(userAddress core.AddressHandler) (string, error)
+	RegisterUserCalled                 func(userAddress core.AddressHandler, userIp string, request requests.RegistrationPayload) (*requests.OTP, string, error)
+	VerifyCodeCalled                   func(userAddress core.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error)
+	SetSecurityModeNoExpireCalled      func(userIp string, request requests.SecurityModeNoExpire) (*requests.OTPCodeVerifyData, error)
+	UnsetSecurityModeNoExpireCalled    func(userIp string, request requests.SecurityModeNoExpire) (*requests.OTPCodeVerifyData, error)
+	SignMessageCalled                  func(userIp string, request requests.SignMessage) ([]byte, *requests.OTPCodeVerifyData, error)
+	SignTransactionCalled              func(userIp string, request requests.SignTransaction) ([]byte, *requests.OTPCodeVerifyData, error)
+	SignMultipleTransactionsCalled     func(userIp string, request requests.SignMultipleTransactions) ([][]byte, *requests.OTPCodeVerifyData, error)
+	SetSpendingPolicyCalled            func(userAddress core.AddressHandler, userIp string, request requests.SetSpendingPolicy) (*requests.OTPCodeVerifyData, error)
+	GetSpendingPolicyCalled            func(userAddress core.AddressHandler) (*requests.SpendingPolicyResponse, error)
+	WebAuthnChallengeCalled            func(userAddress core.AddressHandler, userIp string, request requests.WebAuthnChallenge) (*requests.WebAuthnChallengeResponse, *requests.OTPCodeVerifyData, error)
+	RegisterWebAuthnCalled             func(userAddress core.AddressHandler, userIp string, request requests.RegisterWebAuthn) (*requests.OTPCodeVerifyData, error)
+	RegenerateRecoveryCodesCalled      func(userAddress core.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error)
+	DeregisterUserCalled               func(userAddress core.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error)
+	RegisteredUsersCalled              func() (uint32, error)
+	TcsConfigCalled                    func() *tcsCore.TcsConfig
+	GetUserStateCalled                 func(userAddress core.AddressHandler) (*requests.UserStateResponse, error)
+	ForceUnsetSecurityModeCalled       func(userAddress core.AddressHandler) error
+	ResetRateLimiterCalled             func(userAddress core.AddressHandler, userIp string) error
+	MarkGuardianNotUsableCalled        func(userAddress core.AddressHandler, guardian string) error
+	StartReEncryptionCalled            func() error
+	GetReEncryptionStatusCalled        func() *requests.ReEncryptionStatusResponse
+	ReEncryptUserCalled                func(userAddress core.AddressHandler) error
+	ExportUserDataCalled               func(userAddress core.AddressHandler) (*requests.UserAuditDataResponse, error)
+	GetHistoryCalled                   func(userAddress core.AddressHandler, offset uint32, limit uint32) (*requests.UserHistoryResponse, error)
+	GetGuardianStatusCalled            func(userAddress core.AddressHandler, userIp string) (*requests.GuardianStatusResponse, error)
+	PreviewTransactionsCalled          func(request requests.SignMultipleTransactions) (*requests.PreviewTransactionsResponse, error)
+	ReconcileGuardianActivationsCalled func(ctx context.Context) error
 }
 
 // RegisterUser -
-func (stub *ServiceResolverStub) RegisterUser(userAddress core.AddressHandler, request requests.RegistrationPayload) (*requests.OTP, string, error) {
+func (stub *ServiceResolverStub) RegisterUser(userAddress core.AddressHandler, userIp string, request requests.RegistrationPayload) (*requests.OTP, string, error) {
 	if stub.RegisterUserCalled != nil {
-		return stub.RegisterUserCalled(userAddress, request)
+		return stub.RegisterUserCalled(userAddress, userIp, request)
 	}
 	return &requests.OTP{}, "", nil
 }
 
 // VerifyCode -
-func (stub *ServiceResolverStub) VerifyCode(userAddress core.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error) {
+func (stub *ServiceResolverStub) VerifyCode(userAddress core.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error) {
 	if stub.VerifyCodeCalled != nil {
 		return stub.VerifyCodeCalled(userAddress, userIp, request)
 	}
-	return nil, nil
+	return nil, nil, nil
 }
 
 // SignMessage -
@@ -77,6 +97,54 @@ func (stub *ServiceResolverStub) SignMultipleTransactions(userIp string, request
 	return make([][]byte, 0), nil, nil
 }
 
+// SetSpendingPolicy -
+func (stub *ServiceResolverStub) SetSpendingPolicy(userAddress core.AddressHandler, userIp string, request requests.SetSpendingPolicy) (*requests.OTPCodeVerifyData, error) {
+	if stub.SetSpendingPolicyCalled != nil {
+		return stub.SetSpendingPolicyCalled(userAddress, userIp, request)
+	}
+	return nil, nil
+}
+
+// GetSpendingPolicy -
+func (stub *ServiceResolverStub) GetSpendingPolicy(userAddress core.AddressHandler) (*requests.SpendingPolicyResponse, error) {
+	if stub.GetSpendingPolicyCalled != nil {
+		return stub.GetSpendingPolicyCalled(userAddress)
+	}
+	return &requests.SpendingPolicyResponse{}, nil
+}
+
+// WebAuthnChallenge -
+func (stub *ServiceResolverStub) WebAuthnChallenge(userAddress core.AddressHandler, userIp string, request requests.WebAuthnChallenge) (*requests.WebAuthnChallengeResponse, *requests.OTPCodeVerifyData, error) {
+	if stub.WebAuthnChallengeCalled != nil {
+		return stub.WebAuthnChallengeCalled(userAddress, userIp, request)
+	}
+	return &requests.WebAuthnChallengeResponse{}, nil, nil
+}
+
+// RegisterWebAuthn -
+func (stub *ServiceResolverStub) RegisterWebAuthn(userAddress core.AddressHandler, userIp string, request requests.RegisterWebAuthn) (*requests.OTPCodeVerifyData, error) {
+	if stub.RegisterWebAuthnCalled != nil {
+		return stub.RegisterWebAuthnCalled(userAddress, userIp, request)
+	}
+	return nil, nil
+}
+
+// RegenerateRecoveryCodes -
+func (stub *ServiceResolverStub) RegenerateRecoveryCodes(userAddress core.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.RecoveryCodesResponse, *requests.OTPCodeVerifyData, error) {
+	if stub.RegenerateRecoveryCodesCalled != nil {
+		return stub.RegenerateRecoveryCodesCalled(userAddress, userIp, request)
+	}
+	return &requests.RecoveryCodesResponse{}, nil, nil
+}
+
+// DeregisterUser -
+func (stub *ServiceResolverStub) DeregisterUser(userAddress core.AddressHandler, userIp string, request requests.VerificationPayload) (*requests.OTPCodeVerifyData, error) {
+	if stub.DeregisterUserCalled != nil {
+		return stub.DeregisterUserCalled(userAddress, userIp, request)
+	}
+	return nil, nil
+}
+
 // RegisteredUsers -
 func (stub *ServiceResolverStub) RegisteredUsers() (uint32, error) {
 	if stub.RegisteredUsersCalled != nil {
@@ -85,6 +153,114 @@ func (stub *ServiceResolverStub) RegisteredUsers() (uint32, error) {
 	return 0, nil
 }
 
+// GetUserState -
+func (stub *ServiceResolverStub) GetUserState(userAddress core.AddressHandler) (*requests.UserStateResponse, error) {
+	if stub.GetUserStateCalled != nil {
+		return stub.GetUserStateCalled(userAddress)
+	}
+
+	return &requests.UserStateResponse{}, nil
+}
+
+// ForceUnsetSecurityMode -
+func (stub *ServiceResolverStub) ForceUnsetSecurityMode(userAddress core.AddressHandler) error {
+	if stub.ForceUnsetSecurityModeCalled != nil {
+		return stub.ForceUnsetSecurityModeCalled(userAddress)
+	}
+
+	return nil
+}
+
+// ResetRateLimiter -
+func (stub *ServiceResolverStub) ResetRateLimiter(userAddress core.AddressHandler, userIp string) error {
+	if stub.ResetRateLimiterCalled != nil {
+		return stub.ResetRateLimiterCalled(userAddress, userIp)
+	}
+
+	return nil
+}
+
+// ReEncryptUser -
+func (stub *ServiceResolverStub) ReEncryptUser(userAddress core.AddressHandler) error {
+	if stub.ReEncryptUserCalled != nil {
+		return stub.ReEncryptUserCalled(userAddress)
+	}
+
+	return nil
+}
+
+// StartReEncryption -
+func (stub *ServiceResolverStub) StartReEncryption() error {
+	if stub.StartReEncryptionCalled != nil {
+		return stub.StartReEncryptionCalled()
+	}
+
+	return nil
+}
+
+// GetReEncryptionStatus -
+func (stub *ServiceResolverStub) GetReEncryptionStatus() *requests.ReEncryptionStatusResponse {
+	if stub.GetReEncryptionStatusCalled != nil {
+		return stub.GetReEncryptionStatusCalled()
+	}
+
+	return &requests.ReEncryptionStatusResponse{}
+}
+
+// MarkGuardianNotUsable -
+func (stub *ServiceResolverStub) MarkGuardianNotUsable(userAddress core.AddressHandler, guardian string) error {
+	if stub.MarkGuardianNotUsableCalled != nil {
+		return stub.MarkGuardianNotUsableCalled(userAddress, guardian)
+	}
+
+	return nil
+}
+
+// ExportUserData -
+func (stub *ServiceResolverStub) ExportUserData(userAddress core.AddressHandler) (*requests.UserAuditDataResponse, error) {
+	if stub.ExportUserDataCalled != nil {
+		return stub.ExportUserDataCalled(userAddress)
+	}
+
+	return &requests.UserAuditDataResponse{}, nil
+}
+
+// GetHistory -
+func (stub *ServiceResolverStub) GetHistory(userAddress core.AddressHandler, offset uint32, limit uint32) (*requests.UserHistoryResponse, error) {
+	if stub.GetHistoryCalled != nil {
+		return stub.GetHistoryCalled(userAddress, offset, limit)
+	}
+
+	return &requests.UserHistoryResponse{}, nil
+}
+
+// GetGuardianStatus -
+func (stub *ServiceResolverStub) GetGuardianStatus(userAddress core.AddressHandler, userIp string) (*requests.GuardianStatusResponse, error) {
+	if stub.GetGuardianStatusCalled != nil {
+		return stub.GetGuardianStatusCalled(userAddress, userIp)
+	}
+
+	return &requests.GuardianStatusResponse{}, nil
+}
+
+// PreviewTransactions -
+func (stub *ServiceResolverStub) PreviewTransactions(request requests.SignMultipleTransactions) (*requests.PreviewTransactionsResponse, error) {
+	if stub.PreviewTransactionsCalled != nil {
+		return stub.PreviewTransactionsCalled(request)
+	}
+
+	return &requests.PreviewTransactionsResponse{}, nil
+}
+
+// ReconcileGuardianActivations -
+func (stub *ServiceResolverStub) ReconcileGuardianActivations(ctx context.Context) error {
+	if stub.ReconcileGuardianActivationsCalled != nil {
+		return stub.ReconcileGuardianActivationsCalled(ctx)
+	}
+
+	return nil
+}
+
 // TcsConfig returns the current configuration of the TCS
 func (stub *ServiceResolverStub) TcsConfig() *tcsCore.TcsConfig {
 	if stub.TcsConfigCalled != nil {
@@ -1,6 +1,7 @@
 package testscommon
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
@@ -24,6 +25,21 @@ func (mock *shardedStorageWithIndexMock) AllocateIndex(_ []byte) (uint32, error)
 	return 0, nil
 }
 
+// ReserveIndex -
+func (mock *shardedStorageWithIndexMock) ReserveIndex(_ uint32) error {
+	return nil
+}
+
+// GetLastAllocatedIndex -
+func (mock *shardedStorageWithIndexMock) GetLastAllocatedIndex() (uint32, error) {
+	return 0, nil
+}
+
+// ReserveIndexesUpTo -
+func (mock *shardedStorageWithIndexMock) ReserveIndexesUpTo(_ uint32) error {
+	return nil
+}
+
 // Put -
 func (mock *shardedStorageWithIndexMock) Put(key, data []byte) error {
 	mock.mut.Lock()
@@ -54,6 +70,14 @@ func (mock *shardedStorageWithIndexMock) Has(key []byte) error {
 	return nil
 }
 
+// Remove -
+func (mock *shardedStorageWithIndexMock) Remove(key []byte) error {
+	mock.mut.Lock()
+	delete(mock.cache, string(key))
+	mock.mut.Unlock()
+	return nil
+}
+
 // Close -
 func (mock *shardedStorageWithIndexMock) Close() error {
 	return nil
@@ -66,6 +90,28 @@ func (mock *shardedStorageWithIndexMock) Count() (uint32, error) {
 	return uint32(len(mock.cache)), nil
 }
 
+// RangeKeys -
+func (mock *shardedStorageWithIndexMock) RangeKeys(ctx context.Context, handler func(key []byte, val []byte) bool) error {
+	mock.mut.RLock()
+	entries := make(map[string][]byte, len(mock.cache))
+	for key, val := range mock.cache {
+		entries[key] = val
+	}
+	mock.mut.RUnlock()
+
+	for key, val := range entries {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+		if !handler([]byte(key), val) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
 // IsInterfaceNil -
 func (mock *shardedStorageWithIndexMock) IsInterfaceNil() bool {
 	return mock == nil
@@ -1,14 +1,21 @@
 package testscommon
 
+import "context"
+
 // ShardedStorageWithIndexStub -
 type ShardedStorageWithIndexStub struct {
-	AllocateIndexCalled       func(address []byte) (uint32, error)
-	PutCalled                 func(key, data []byte) error
-	GetCalled                 func(key []byte) ([]byte, error)
-	HasCalled                 func(key []byte) error
-	CloseCalled               func() error
-	AllocateBucketIndexCalled func(address []byte) (uint32, error)
-	CountCalled               func() (uint32, error)
+	AllocateIndexCalled         func(address []byte) (uint32, error)
+	ReserveIndexCalled          func(index uint32) error
+	GetLastAllocatedIndexCalled func() (uint32, error)
+	ReserveIndexesUpToCalled    func(finalIndex uint32) error
+	PutCalled                   func(key, data []byte) error
+	GetCalled                   func(key []byte) ([]byte, error)
+	HasCalled                   func(key []byte) error
+	RemoveCalled                func(key []byte) error
+	CloseCalled                 func() error
+	AllocateBucketIndexCalled   func(address []byte) (uint32, error)
+	CountCalled                 func() (uint32, error)
+	RangeKeysCalled             func(ctx context.Context, handler func(key []byte, val []byte) bool) error
 }
 
 // AllocateIndex -
@@ -19,6 +26,30 @@ func (stub *ShardedStorageWithIndexStub) AllocateIndex(address []byte) (uint32,
 	return 0, nil
 }
 
+// ReserveIndex -
+func (stub *ShardedStorageWithIndexStub) ReserveIndex(index uint32) error {
+	if stub.ReserveIndexCalled != nil {
+		return stub.ReserveIndexCalled(index)
+	}
+	return nil
+}
+
+// GetLastAllocatedIndex -
+func (stub *ShardedStorageWithIndexStub) GetLastAllocatedIndex() (uint32, error) {
+	if stub.GetLastAllocatedIndexCalled != nil {
+		return stub.GetLastAllocatedIndexCalled()
+	}
+	return 0, nil
+}
+
+// ReserveIndexesUpTo -
+func (stub *ShardedStorageWithIndexStub) ReserveIndexesUpTo(finalIndex uint32) error {
+	if stub.ReserveIndexesUpToCalled != nil {
+		return stub.ReserveIndexesUpToCalled(finalIndex)
+	}
+	return nil
+}
+
 // Put -
 func (stub *ShardedStorageWithIndexStub) Put(key, data []byte) error {
 	if stub.PutCalled != nil {
@@ -43,6 +74,14 @@ func (stub *ShardedStorageWithIndexStub) Has(key []byte) error {
 	return nil
 }
 
+// Remove -
+func (stub *ShardedStorageWithIndexStub) Remove(key []byte) error {
+	if stub.RemoveCalled != nil {
+		return stub.RemoveCalled(key)
+	}
+	return nil
+}
+
 // Close -
 func (stub *ShardedStorageWithIndexStub) Close() error {
 	if stub.CloseCalled != nil {
@@ -67,6 +106,14 @@ func (stub *ShardedStorageWithIndexStub) Count() (uint32, error) {
 	return 0, nil
 }
 
+// RangeKeys -
+func (stub *ShardedStorageWithIndexStub) RangeKeys(ctx context.Context, handler func(key []byte, val []byte) bool) error {
+	if stub.RangeKeysCalled != nil {
+		return stub.RangeKeysCalled(ctx, handler)
+	}
+	return nil
+}
+
 // IsInterfaceNil -
 func (stub *ShardedStorageWithIndexStub) IsInterfaceNil() bool {
 	return stub == nil
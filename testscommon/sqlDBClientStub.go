@@ -0,0 +1,146 @@
+package testscommon
+
+import (
+	"context"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/sqldb"
+)
+
+// SQLDBClientStub -
+type SQLDBClientStub struct {
+	PutCalled                 func(table sqldb.TableID, key []byte, data []byte) error
+	GetCalled                 func(table sqldb.TableID, key []byte) ([]byte, error)
+	HasCalled                 func(table sqldb.TableID, key []byte) error
+	RemoveCalled              func(table sqldb.TableID, key []byte) error
+	PutIfNotExistsCalled      func(table sqldb.TableID, key []byte, data []byte) (bool, error)
+	RemoveIfEqualCalled       func(table sqldb.TableID, key []byte, data []byte) error
+	RangeKeysCalled           func(ctx context.Context, table sqldb.TableID, handler func(key []byte, val []byte) bool) error
+	GetIndexCalled            func(table sqldb.TableID, key []byte) (uint32, error)
+	IncrementIndexCalled      func(table sqldb.TableID, key []byte) (uint32, error)
+	PutIndexIfNotExistsCalled func(table sqldb.TableID, key []byte, index uint32) error
+	PutIndexIfGreaterCalled   func(table sqldb.TableID, key []byte, index uint32) error
+	GetAllTablesIDsCalled     func() []sqldb.TableID
+	CloseCalled               func() error
+}
+
+// Put -
+func (stub *SQLDBClientStub) Put(table sqldb.TableID, key []byte, data []byte) error {
+	if stub.PutCalled != nil {
+		return stub.PutCalled(table, key, data)
+	}
+
+	return nil
+}
+
+// Get -
+func (stub *SQLDBClientStub) Get(table sqldb.TableID, key []byte) ([]byte, error) {
+	if stub.GetCalled != nil {
+		return stub.GetCalled(table, key)
+	}
+
+	return nil, nil
+}
+
+// Has -
+func (stub *SQLDBClientStub) Has(table sqldb.TableID, key []byte) error {
+	if stub.HasCalled != nil {
+		return stub.HasCalled(table, key)
+	}
+
+	return nil
+}
+
+// Remove -
+func (stub *SQLDBClientStub) Remove(table sqldb.TableID, key []byte) error {
+	if stub.RemoveCalled != nil {
+		return stub.RemoveCalled(table, key)
+	}
+
+	return nil
+}
+
+// PutIfNotExists -
+func (stub *SQLDBClientStub) PutIfNotExists(table sqldb.TableID, key []byte, data []byte) (bool, error) {
+	if stub.PutIfNotExistsCalled != nil {
+		return stub.PutIfNotExistsCalled(table, key, data)
+	}
+
+	return false, nil
+}
+
+// RemoveIfEqual -
+func (stub *SQLDBClientStub) RemoveIfEqual(table sqldb.TableID, key []byte, data []byte) error {
+	if stub.RemoveIfEqualCalled != nil {
+		return stub.RemoveIfEqualCalled(table, key, data)
+	}
+
+	return nil
+}
+
+// RangeKeys -
+func (stub *SQLDBClientStub) RangeKeys(ctx context.Context, table sqldb.TableID, handler func(key []byte, val []byte) bool) error {
+	if stub.RangeKeysCalled != nil {
+		return stub.RangeKeysCalled(ctx, table, handler)
+	}
+
+	return nil
+}
+
+// GetIndex -
+func (stub *SQLDBClientStub) GetIndex(table sqldb.TableID, key []byte) (uint32, error) {
+	if stub.GetIndexCalled != nil {
+		return stub.GetIndexCalled(table, key)
+	}
+
+	return 0, nil
+}
+
+// IncrementIndex -
+func (stub *SQLDBClientStub) IncrementIndex(table sqldb.TableID, key []byte) (uint32, error) {
+	if stub.IncrementIndexCalled != nil {
+		return stub.IncrementIndexCalled(table, key)
+	}
+
+	return 0, nil
+}
+
+// PutIndexIfNotExists -
+func (stub *SQLDBClientStub) PutIndexIfNotExists(table sqldb.TableID, key []byte, index uint32) error {
+	if stub.PutIndexIfNotExistsCalled != nil {
+		return stub.PutIndexIfNotExistsCalled(table, key, index)
+	}
+
+	return nil
+}
+
+// PutIndexIfGreater -
+func (stub *SQLDBClientStub) PutIndexIfGreater(table sqldb.TableID, key []byte, index uint32) error {
+	if stub.PutIndexIfGreaterCalled != nil {
+		return stub.PutIndexIfGreaterCalled(table, key, index)
+	}
+
+	return nil
+}
+
+// GetAllTablesIDs -
+func (stub *SQLDBClientStub) GetAllTablesIDs() []sqldb.TableID {
+	if stub.GetAllTablesIDsCalled != nil {
+		return stub.GetAllTablesIDsCalled()
+	}
+
+	return make([]sqldb.TableID, 0)
+}
+
+// Close -
+func (stub *SQLDBClientStub) Close() error {
+	if stub.CloseCalled != nil {
+		return stub.CloseCalled()
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *SQLDBClientStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-storage-go/common"
 )
 
 // StorerMock -
@@ -48,7 +49,7 @@ func (sm *StorerMock) Get(key []byte) ([]byte, error) {
 
 	val, ok := sm.data[string(key)]
 	if !ok {
-		return nil, fmt.Errorf("key: %s not found", base64.StdEncoding.EncodeToString(key))
+		return nil, fmt.Errorf("%w, key: %s", common.ErrKeyNotFound, base64.StdEncoding.EncodeToString(key))
 	}
 
 	return val, nil
@@ -76,7 +77,7 @@ func (sm *StorerMock) Has(key []byte) error {
 
 	_, ok := sm.data[string(key)]
 	if !ok {
-		return fmt.Errorf("key: %s not found", base64.StdEncoding.EncodeToString(key))
+		return fmt.Errorf("%w, key: %s", common.ErrKeyNotFound, base64.StdEncoding.EncodeToString(key))
 	}
 	return nil
 }
@@ -96,6 +97,22 @@ func (sm *StorerMock) Remove(key []byte) error {
 	return nil
 }
 
+// RangeKeys -
+func (sm *StorerMock) RangeKeys(handler func(key []byte, val []byte) bool) {
+	sm.mut.RLock()
+	entries := make(map[string][]byte, len(sm.data))
+	for key, val := range sm.data {
+		entries[key] = val
+	}
+	sm.mut.RUnlock()
+
+	for key, val := range entries {
+		if !handler([]byte(key), val) {
+			return
+		}
+	}
+}
+
 // GetOldestEpoch -
 func (sm *StorerMock) GetOldestEpoch() (uint32, error) {
 	return 0, nil
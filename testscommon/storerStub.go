@@ -78,6 +78,13 @@ func (ss *StorerStub) Remove(key []byte) error {
 	return nil
 }
 
+// RangeKeys -
+func (ss *StorerStub) RangeKeys(handler func(key []byte, val []byte) bool) {
+	if ss.RangeKeysCalled != nil {
+		ss.RangeKeysCalled(handler)
+	}
+}
+
 // ClearCache -
 func (ss *StorerStub) ClearCache() {
 	if ss.ClearCacheCalled != nil {
@@ -1,13 +1,14 @@
 package testscommon
 
 import (
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
 	"github.com/multiversx/mx-multi-factor-auth-go-service/handlers"
 )
 
 // TOTPHandlerStub -
 type TOTPHandlerStub struct {
 	CreateTOTPCalled    func(account string) (handlers.OTP, error)
-	TOTPFromBytesCalled func(encryptedMessage []byte) (handlers.OTP, error)
+	TOTPFromBytesCalled func(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error)
 }
 
 // CreateTOTP -
@@ -19,9 +20,9 @@ func (stub *TOTPHandlerStub) CreateTOTP(account string) (handlers.OTP, error) {
 }
 
 // TOTPFromBytes -
-func (stub *TOTPHandlerStub) TOTPFromBytes(encryptedMessage []byte) (handlers.OTP, error) {
+func (stub *TOTPHandlerStub) TOTPFromBytes(encryptedMessage []byte, params core.OTPParams) (handlers.OTP, error) {
 	if stub.TOTPFromBytesCalled != nil {
-		return stub.TOTPFromBytesCalled(encryptedMessage)
+		return stub.TOTPFromBytesCalled(encryptedMessage, params)
 	}
 	return nil, nil
 }
@@ -1,5 +1,7 @@
 package testscommon
 
+import "github.com/multiversx/mx-multi-factor-auth-go-service/core"
+
 // TotpStub -
 type TotpStub struct {
 	ValidateCalled func(userCode string) error
@@ -7,6 +9,7 @@ type TotpStub struct {
 	QRCalled       func() ([]byte, error)
 	ToBytesCalled  func() ([]byte, error)
 	UrlCalled      func() (string, error)
+	ParamsCalled   func() core.OTPParams
 }
 
 // Validate -
@@ -48,3 +51,11 @@ func (stub *TotpStub) Url() (string, error) {
 	}
 	return "", nil
 }
+
+// Params -
+func (stub *TotpStub) Params() core.OTPParams {
+	if stub.ParamsCalled != nil {
+		return stub.ParamsCalled()
+	}
+	return core.OTPParams{}
+}
@@ -0,0 +1,25 @@
+package testscommon
+
+import (
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/txdecoder"
+)
+
+// TxDecoderStub -
+type TxDecoderStub struct {
+	DecodeCalled func(tx transaction.FrontendTransaction) (*txdecoder.DecodedTransaction, error)
+}
+
+// Decode -
+func (stub *TxDecoderStub) Decode(tx transaction.FrontendTransaction) (*txdecoder.DecodedTransaction, error) {
+	if stub.DecodeCalled != nil {
+		return stub.DecodeCalled(tx)
+	}
+	return &txdecoder.DecodedTransaction{}, nil
+}
+
+// IsInterfaceNil -
+func (stub *TxDecoderStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -0,0 +1,30 @@
+package testscommon
+
+import "github.com/multiversx/mx-chain-core-go/data/transaction"
+
+// TxPolicyHandlerStub -
+type TxPolicyHandlerStub struct {
+	CheckTransactionsCalled          func(userAddress string, txs []transaction.FrontendTransaction) (bool, error)
+	CheckAndRecordTransactionsCalled func(userAddress string, txs []transaction.FrontendTransaction, isConfirmed bool) error
+}
+
+// CheckTransactions -
+func (stub *TxPolicyHandlerStub) CheckTransactions(userAddress string, txs []transaction.FrontendTransaction) (bool, error) {
+	if stub.CheckTransactionsCalled != nil {
+		return stub.CheckTransactionsCalled(userAddress, txs)
+	}
+	return false, nil
+}
+
+// CheckAndRecordTransactions -
+func (stub *TxPolicyHandlerStub) CheckAndRecordTransactions(userAddress string, txs []transaction.FrontendTransaction, isConfirmed bool) error {
+	if stub.CheckAndRecordTransactionsCalled != nil {
+		return stub.CheckAndRecordTransactionsCalled(userAddress, txs, isConfirmed)
+	}
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *TxPolicyHandlerStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -4,26 +4,35 @@ import "github.com/multiversx/mx-multi-factor-auth-go-service/core"
 
 // UserEncryptorStub is a stub implementation of UserEncryptor
 type UserEncryptorStub struct {
-	EncryptUserInfoCalled func(userInfo *core.UserInfo) (*core.UserInfo, error)
-	DecryptUserInfoCalled func(userInfo *core.UserInfo) (*core.UserInfo, error)
+	EncryptUserInfoCalled           func(userAddress []byte, userInfo *core.UserInfo) (*core.UserInfo, error)
+	DecryptUserInfoCalled           func(userAddress []byte, userInfo *core.UserInfo) (*core.UserInfo, error)
+	IsEncryptedWithCurrentKeyCalled func(userInfo *core.UserInfo) bool
 }
 
 // EncryptUserInfo encrypts the provided user info
-func (ues *UserEncryptorStub) EncryptUserInfo(userInfo *core.UserInfo) (*core.UserInfo, error) {
+func (ues *UserEncryptorStub) EncryptUserInfo(userAddress []byte, userInfo *core.UserInfo) (*core.UserInfo, error) {
 	if ues.EncryptUserInfoCalled != nil {
-		return ues.EncryptUserInfoCalled(userInfo)
+		return ues.EncryptUserInfoCalled(userAddress, userInfo)
 	}
 	return userInfo, nil
 }
 
 // DecryptUserInfo decrypts the provided user info
-func (ues *UserEncryptorStub) DecryptUserInfo(userInfo *core.UserInfo) (*core.UserInfo, error) {
+func (ues *UserEncryptorStub) DecryptUserInfo(userAddress []byte, userInfo *core.UserInfo) (*core.UserInfo, error) {
 	if ues.DecryptUserInfoCalled != nil {
-		return ues.DecryptUserInfoCalled(userInfo)
+		return ues.DecryptUserInfoCalled(userAddress, userInfo)
 	}
 	return userInfo, nil
 }
 
+// IsEncryptedWithCurrentKey -
+func (ues *UserEncryptorStub) IsEncryptedWithCurrentKey(userInfo *core.UserInfo) bool {
+	if ues.IsEncryptedWithCurrentKeyCalled != nil {
+		return ues.IsEncryptedWithCurrentKeyCalled(userInfo)
+	}
+	return true
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (ues *UserEncryptorStub) IsInterfaceNil() bool {
 	return ues == nil
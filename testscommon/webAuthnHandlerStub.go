@@ -0,0 +1,42 @@
+package testscommon
+
+import (
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core"
+	"github.com/multiversx/mx-multi-factor-auth-go-service/core/requests"
+)
+
+// WebAuthnHandlerStub -
+type WebAuthnHandlerStub struct {
+	CreateChallengeCalled    func(binding []byte) ([]byte, error)
+	VerifyRegistrationCalled func(credential core.WebAuthnInfo, binding []byte, registration requests.WebAuthnRegistration) (*core.WebAuthnInfo, error)
+	VerifyAssertionCalled    func(credential core.WebAuthnInfo, binding []byte, assertion requests.WebAuthnAssertion) (*core.WebAuthnInfo, error)
+}
+
+// CreateChallenge -
+func (stub *WebAuthnHandlerStub) CreateChallenge(binding []byte) ([]byte, error) {
+	if stub.CreateChallengeCalled != nil {
+		return stub.CreateChallengeCalled(binding)
+	}
+	return []byte("challenge"), nil
+}
+
+// VerifyRegistration -
+func (stub *WebAuthnHandlerStub) VerifyRegistration(credential core.WebAuthnInfo, binding []byte, registration requests.WebAuthnRegistration) (*core.WebAuthnInfo, error) {
+	if stub.VerifyRegistrationCalled != nil {
+		return stub.VerifyRegistrationCalled(credential, binding, registration)
+	}
+	return &core.WebAuthnInfo{}, nil
+}
+
+// VerifyAssertion -
+func (stub *WebAuthnHandlerStub) VerifyAssertion(credential core.WebAuthnInfo, binding []byte, assertion requests.WebAuthnAssertion) (*core.WebAuthnInfo, error) {
+	if stub.VerifyAssertionCalled != nil {
+		return stub.VerifyAssertionCalled(credential, binding, assertion)
+	}
+	return &credential, nil
+}
+
+// IsInterfaceNil -
+func (stub *WebAuthnHandlerStub) IsInterfaceNil() bool {
+	return stub == nil
+}